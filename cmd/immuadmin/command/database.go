@@ -45,6 +45,9 @@ func addDbUpdateFlags(c *cobra.Command) {
 		"do not include server-side timestamps in commit checksums, useful when reproducibility is a desired feature")
 	c.Flags().Bool("embedded-values", false, "store values in the tx header")
 	c.Flags().Bool("prealloc-files", false, "enable file preallocation")
+	c.Flags().String("encryption-key-file", "", "name of the file, within the encryption keys folder of the server, holding the keys used to encrypt newly created files")
+	c.Flags().String("compression-format", "no-compression", "compression format of newly created value log files (one of: no-compression, flate, gzip, lzw, zlib, zstd, lz4, snappy)")
+	c.Flags().String("compression-level", "best-speed", "compression level (one of: best-speed, best-compression, default-compression, huffman-only)")
 	c.Flags().Bool("replication-enabled", false, "set database as a replica") // deprecated, use replication-is-replica instead
 	c.Flags().Bool("replication-is-replica", false, "set database as a replica")
 	c.Flags().Bool("replication-sync-enabled", false, "enable synchronous replication")
@@ -493,13 +496,21 @@ func rowToCSV(row client.Row, cols []client.Column, out []string) error {
 
 func renderValue(v interface{}, colType string) (string, error) {
 	switch colType {
-	case sql.VarcharType, sql.JSONType, sql.UUIDType:
+	case sql.VarcharType, sql.JSONType, sql.UUIDType, sql.DecimalType:
 		s, isStr := v.(string)
 		if !isStr {
 			return "", fmt.Errorf("invalid value received")
 		}
 		return s, nil
 	default:
+		if sql.IsArrayType(colType) {
+			s, isStr := v.(string)
+			if !isStr {
+				return "", fmt.Errorf("invalid value received")
+			}
+			return s, nil
+		}
+
 		sqlVal, err := schema.AsSQLValue(v)
 		if err != nil {
 			return "", err
@@ -568,6 +579,11 @@ func (cl *commandline) createImportCmd() *cobra.Command {
 				cols[i] = formatColName(col.Name)
 			}
 
+			colTypes, err := cl.insertColumnTypes(table, res.Columns)
+			if err != nil {
+				return err
+			}
+
 			row, err := reader.Read()
 			for err == nil {
 				if len(row) != len(cols) {
@@ -575,7 +591,7 @@ func (cl *commandline) createImportCmd() *cobra.Command {
 				}
 
 				for i, v := range row {
-					row[i] = formatInsertValue(v, res.Columns[i].Type)
+					row[i] = formatInsertValue(v, colTypes[i])
 				}
 
 				_, err = cl.immuClient.SQLExec(
@@ -602,6 +618,47 @@ func (cl *commandline) createImportCmd() *cobra.Command {
 	return importCmd
 }
 
+// insertColumnTypes returns the types values are cast to when inserted into the columns of the table,
+// the types of query results do not include the precision and scale of DECIMAL columns
+func (cl *commandline) insertColumnTypes(table string, cols []*schema.Column) ([]string, error) {
+	colTypes := make([]string, len(cols))
+
+	var hasDecimals bool
+	for i, col := range cols {
+		colTypes[i] = col.Type
+		hasDecimals = hasDecimals || sql.ArrayElemType(col.Type) == sql.DecimalType
+	}
+
+	if !hasDecimals {
+		return colTypes, nil
+	}
+
+	res, err := cl.immuClient.SQLQuery(
+		cl.context,
+		"SELECT name, type, max_length, scale FROM COLUMNS(@table) WHERE scale IS NOT NULL",
+		map[string]interface{}{"table": table},
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	decimalTypes := make(map[string]string, len(res.Rows))
+	for _, row := range res.Rows {
+		colType := row.Values[1].GetS()
+		elemType := sql.ArrayElemType(colType)
+
+		decimalTypes[row.Values[0].GetS()] = fmt.Sprintf("%s(%d,%d)%s", elemType, row.Values[2].GetN(), row.Values[3].GetN(), strings.TrimPrefix(colType, elemType))
+	}
+
+	for i, col := range cols {
+		if decimalType, ok := decimalTypes[formatColName(col.Name)]; ok {
+			colTypes[i] = decimalType
+		}
+	}
+	return colTypes, nil
+}
+
 func formatColName(col string) string {
 	idx := strings.Index(col, ".")
 	if idx >= 0 {
@@ -618,11 +675,15 @@ func formatInsertValue(v string, colType string) string {
 	switch colType {
 	case sql.VarcharType:
 		return fmt.Sprintf("'%s'", v)
-	case sql.TimestampType, sql.JSONType, sql.UUIDType:
+	case sql.TimestampType, sql.JSONType, sql.UUIDType, sql.DecimalType:
 		return fmt.Sprintf("CAST ('%s' AS %s)", v, colType)
 	case sql.BLOBType:
 		return fmt.Sprintf("x'%s'", v)
 	}
+
+	if sql.IsArrayType(colType) || strings.HasPrefix(colType, sql.DecimalType+"(") {
+		return fmt.Sprintf("CAST ('%s' AS %s)", v, colType)
+	}
 	return v
 }
 
@@ -704,6 +765,21 @@ func prepareDatabaseNullableSettings(flags *pflag.FlagSet) (*schema.DatabaseNull
 		return nil, err
 	}
 
+	ret.EncryptionKeyFile, err = condString("encryption-key-file")
+	if err != nil {
+		return nil, err
+	}
+
+	ret.CompressionFormat, err = condString("compression-format")
+	if err != nil {
+		return nil, err
+	}
+
+	ret.CompressionLevel, err = condString("compression-level")
+	if err != nil {
+		return nil, err
+	}
+
 	ret.ReplicationSettings.Replica, err = condBool("replication-is-replica")
 	if err != nil {
 		return nil, err
@@ -863,6 +939,18 @@ func databaseNullableSettingsStr(settings *schema.DatabaseNullableSettings) stri
 		propertiesStr = append(propertiesStr, fmt.Sprintf("prealloc-files: %v", settings.PreallocFiles.GetValue()))
 	}
 
+	if settings.EncryptionKeyFile != nil {
+		propertiesStr = append(propertiesStr, fmt.Sprintf("encryption-key-file: %s", settings.EncryptionKeyFile.GetValue()))
+	}
+
+	if settings.CompressionFormat != nil {
+		propertiesStr = append(propertiesStr, fmt.Sprintf("compression-format: %s", settings.CompressionFormat.GetValue()))
+	}
+
+	if settings.CompressionLevel != nil {
+		propertiesStr = append(propertiesStr, fmt.Sprintf("compression-level: %s", settings.CompressionLevel.GetValue()))
+	}
+
 	if settings.WriteTxHeaderVersion != nil {
 		propertiesStr = append(propertiesStr, fmt.Sprintf("write-tx-header-version: %d", settings.WriteTxHeaderVersion.GetValue()))
 	}
@@ -94,6 +94,8 @@ func parseOptions() (options *server.Options, err error) {
 
 	maxActiveDatabases := viper.GetInt("max-active-databases")
 
+	encryptionKeysDir := viper.GetString("encryption-keys-dir")
+
 	s3Storage := viper.GetBool("s3-storage")
 	s3RoleEnabled := viper.GetBool("s3-role-enabled")
 	s3Role := viper.GetString("s3-role")
@@ -168,7 +170,8 @@ func parseOptions() (options *server.Options, err error) {
 		WithSwaggerUIEnabled(swaggerUIEnabled).
 		WithGRPCReflectionServerEnabled(grpcReflectionServerEnabled).
 		WithLogRequestMetadata(logRequestMetadata).
-		WithMaxActiveDatabases(maxActiveDatabases)
+		WithMaxActiveDatabases(maxActiveDatabases).
+		WithEncryptionKeysDir(encryptionKeysDir)
 
 	return options, nil
 }
@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/multiapp"
 )
 
@@ -50,6 +51,8 @@ type Options struct {
 
 	fileMode os.FileMode
 
+	keyProvider cryptoapp.KeyProvider
+
 	appFactory AppFactoryFunc
 
 	dataCacheSlots    int
@@ -174,6 +177,11 @@ func (opts *Options) WithCompresionLevel(compressionLevel int) *Options {
 	return opts
 }
 
+func (opts *Options) WithKeyProvider(keyProvider cryptoapp.KeyProvider) *Options {
+	opts.keyProvider = keyProvider
+	return opts
+}
+
 func (opts *Options) WithAppFactory(appFactory AppFactoryFunc) *Options {
 	opts.appFactory = appFactory
 	return opts
@@ -31,6 +31,9 @@ const (
 	GZipCompression
 	LZWCompression
 	ZLibCompression
+	ZstdCompression
+	LZ4Compression
+	SnappyCompression
 )
 
 const (
@@ -0,0 +1,841 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cryptoapp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+)
+
+var ErrIllegalArguments = errors.New("cryptoapp: illegal arguments")
+var ErrInvalidOptions = fmt.Errorf("%w: invalid options", ErrIllegalArguments)
+var ErrKeyProviderRequired = fmt.Errorf("%w: a key provider is required to open encrypted appendables", ErrInvalidOptions)
+var ErrAlreadyClosed = errors.New("cryptoapp: already closed")
+var ErrReadOnly = errors.New("cryptoapp: read-only mode")
+var ErrNegativeOffset = errors.New("cryptoapp: negative offset")
+var ErrCorruptedMetadata = errors.New("cryptoapp: corrupted metadata")
+var ErrCorruptedData = errors.New("cryptoapp: corrupted data")
+var ErrKeyNotFound = errors.New("cryptoapp: key not found")
+var ErrInvalidKey = errors.New("cryptoapp: invalid key")
+
+const (
+	metaKeyID       = "ENCRYPTION_KEY_ID"
+	metaBlockSize   = "ENCRYPTION_BLOCK_SIZE"
+	metaKeyCheck    = "ENCRYPTION_KEY_CHECK"
+	metaKeySalt     = "ENCRYPTION_KEY_SALT"
+	metaWrappedMeta = "WRAPPED_METADATA"
+)
+
+// block header: index of the block + length of its data + nonce
+const (
+	blockIdxSize    = 8
+	blockLenSize    = 4
+	blockNonceSize  = 12
+	blockHeaderSize = blockIdxSize + blockLenSize + blockNonceSize
+)
+
+// size of the random salt the key of each appendable is derived with
+const keySaltSize = 32
+
+var _ appendable.Appendable = (*EncryptedAppendable)(nil)
+
+// EncryptedAppendable encrypts data written into an underlying appendable.
+//
+// Data is split into blocks of a fixed size, each one sealed with AES-GCM using a random nonce,
+// its index and length being authenticated as well. Block i is stored at a fixed-size slot i of
+// the underlying appendable, so data can be read at any offset by decrypting the blocks it spans.
+//
+// Blocks are not sealed with the provided key but with one derived from it and a random salt
+// generated when the appendable is created. Random nonces are safe for about 2^32 seals under the
+// same key, deriving a key per appendable keeps that budget from being shared by every file ever
+// encrypted with the provided key, so it's only spent by the writes into a single appendable.
+//
+// The last block is kept in memory until it is full or a sync is requested, and it is rewritten
+// in place as it grows. When a version of the block was already synced, the new one is first written
+// and synced into the following slot, so an interrupted write can not compromise durable data.
+type EncryptedAppendable struct {
+	app      appendable.Appendable
+	aead     cipher.AEAD
+	readOnly bool
+
+	metadata []byte
+
+	blockSize int
+	slotSize  int64
+
+	block    []byte // content of the last block
+	blockIdx int64  // index of the last block
+	dirty    bool   // the last block holds data not written into the underlying appendable
+	synced   bool   // a version of the last block was synced into its own slot
+	repair   bool   // the last block was recovered from the following slot and must be rewritten into its own
+
+	// slot holding the only durable copy of the preceding block,
+	// it can only be overwritten after the block is synced into its own slot
+	shadowSlot int64
+
+	// number of slots which may hold data in the underlying appendable
+	slots int64
+
+	cachedBlockIdx int64
+	cachedBlock    []byte
+	readBuf        []byte
+
+	closed bool
+
+	mutex sync.Mutex
+}
+
+// NewMetadata returns the metadata to be used when creating an appendable to be encrypted
+// with the current key of the provider, wrapping the provided one
+func NewMetadata(keyProvider KeyProvider, metadata []byte) ([]byte, error) {
+	if keyProvider == nil {
+		return nil, ErrKeyProviderRequired
+	}
+
+	keyID, key, err := keyProvider.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, keySaltSize)
+
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	m := appendable.NewMetadata(nil)
+	m.Put(metaKeyID, []byte(keyID))
+	m.Put(metaKeyCheck, keyCheck(aead))
+	m.Put(metaKeySalt, salt)
+	m.PutInt(metaBlockSize, DefaultBlockSize)
+	m.Put(metaWrappedMeta, metadata)
+
+	return m.Bytes(), nil
+}
+
+// IsEncrypted returns true if the metadata belongs to an encrypted appendable
+func IsEncrypted(metadata []byte) bool {
+	_, ok := appendable.NewMetadata(metadata).Get(metaKeyID)
+	return ok
+}
+
+// Open returns an appendable encrypting data written into app,
+// whose metadata must be created with NewMetadata
+func Open(app appendable.Appendable, opts *Options) (*EncryptedAppendable, error) {
+	if app == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	m := appendable.NewMetadata(app.Metadata())
+
+	keyID, ok := m.Get(metaKeyID)
+	if !ok {
+		return nil, ErrCorruptedMetadata
+	}
+
+	blockSize, ok := m.GetInt(metaBlockSize)
+	if !ok || blockSize <= 0 {
+		return nil, ErrCorruptedMetadata
+	}
+
+	check, ok := m.Get(metaKeyCheck)
+	if !ok {
+		return nil, ErrCorruptedMetadata
+	}
+
+	salt, ok := m.Get(metaKeySalt)
+	if !ok || len(salt) != keySaltSize {
+		return nil, ErrCorruptedMetadata
+	}
+
+	metadata, ok := m.Get(metaWrappedMeta)
+	if !ok {
+		return nil, ErrCorruptedMetadata
+	}
+
+	key, err := opts.keyProvider.Key(string(keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	keyAEAD, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(check, keyCheck(keyAEAD)) {
+		return nil, fmt.Errorf("%w: key '%s' does not match the one used to encrypt the appendable", ErrInvalidKey, keyID)
+	}
+
+	aead, err := newAEAD(deriveKey(key, salt))
+	if err != nil {
+		return nil, err
+	}
+
+	a := &EncryptedAppendable{
+		app:            app,
+		aead:           aead,
+		readOnly:       opts.readOnly,
+		metadata:       metadata,
+		blockSize:      blockSize,
+		slotSize:       int64(blockHeaderSize + blockSize + aead.Overhead()),
+		block:          make([]byte, 0, blockSize),
+		shadowSlot:     -1,
+		cachedBlockIdx: -1,
+		cachedBlock:    make([]byte, 0, blockSize),
+		readBuf:        make([]byte, blockSize+aead.Overhead()),
+	}
+
+	err = a.recover()
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err.Error())
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// deriveKey returns the key blocks of an appendable are sealed with,
+// it has the same length as the provided one so the same AES variant is used
+func deriveKey(key, salt []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(metaKeySalt))
+	mac.Write(salt)
+
+	return mac.Sum(nil)[:len(key)]
+}
+
+// keyCheck returns a value used to detect keys not matching the one used to create an appendable,
+// it's the authentication tag of an empty message which discloses nothing about the key
+func keyCheck(aead cipher.AEAD) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(nil, nonce, nil, []byte(metaKeyCheck))
+}
+
+// recover locates the last block, ignoring trailing slots left by interrupted writes or rollbacks.
+// The last block must be preceded by a complete one, otherwise it's a leftover of discarded data
+func (a *EncryptedAppendable) recover() error {
+	size, err := a.app.Size()
+	if err != nil {
+		return err
+	}
+
+	a.slots = (size + a.slotSize - 1) / a.slotSize
+
+	for slot := a.slots - 1; slot >= 0; slot-- {
+		idx, data, err := a.readSlot(slot, a.block)
+		if errors.Is(err, ErrCorruptedData) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if idx > 0 {
+			pidx, pdata, err := a.readSlot(idx-1, a.cachedBlock)
+			if err != nil && !errors.Is(err, ErrCorruptedData) {
+				return err
+			}
+
+			if err != nil || pidx != idx-1 || len(pdata) != a.blockSize {
+				continue
+			}
+		}
+
+		if idx == slot-1 {
+			// copy of the preceding block, which is used only if its own slot can not be read
+			pidx, pdata, err := a.readSlot(idx, a.cachedBlock)
+			if err != nil && !errors.Is(err, ErrCorruptedData) {
+				return err
+			}
+
+			if err == nil && pidx == idx {
+				data = append(a.block[:0], pdata...)
+			} else {
+				a.shadowSlot = slot
+				a.repair = true
+			}
+		}
+
+		a.blockIdx = idx
+		a.block = data
+		a.synced = !a.repair
+
+		if len(a.block) == a.blockSize && !a.repair {
+			a.blockIdx++
+			a.block = a.block[:0]
+			a.synced = false
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// readSlot reads and decrypts the content of a slot into buf,
+// it may either hold the block with the same index or a copy of the preceding one
+func (a *EncryptedAppendable) readSlot(slot int64, buf []byte) (idx int64, data []byte, err error) {
+	var hdr [blockHeaderSize]byte
+
+	_, err = a.app.ReadAt(hdr[:], slot*a.slotSize)
+	if errors.Is(err, io.EOF) {
+		return 0, nil, ErrCorruptedData
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	idx = int64(binary.BigEndian.Uint64(hdr[:]))
+	dataLen := int(binary.BigEndian.Uint32(hdr[blockIdxSize:]))
+
+	if (idx != slot && idx != slot-1) || dataLen == 0 || dataLen > a.blockSize {
+		return 0, nil, ErrCorruptedData
+	}
+
+	sealed := a.readBuf[:dataLen+a.aead.Overhead()]
+
+	_, err = a.app.ReadAt(sealed, slot*a.slotSize+blockHeaderSize)
+	if errors.Is(err, io.EOF) {
+		return 0, nil, ErrCorruptedData
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	data, err = a.aead.Open(buf[:0], hdr[blockIdxSize+blockLenSize:], sealed, hdr[:blockIdxSize+blockLenSize])
+	if err != nil {
+		return 0, nil, ErrCorruptedData
+	}
+
+	return idx, data, nil
+}
+
+// writeSlot encrypts data of the block idx and writes it into a slot
+func (a *EncryptedAppendable) writeSlot(slot int64, idx int64, data []byte) error {
+	if slot == a.shadowSlot {
+		err := a.app.Sync()
+		if err != nil {
+			return err
+		}
+
+		a.shadowSlot = -1
+	}
+
+	off := slot * a.slotSize
+	currOff := a.app.Offset()
+
+	if off < currOff {
+		err := a.app.SetOffset(off)
+		if err != nil {
+			return err
+		}
+	} else if off > currOff {
+		// the slot of the last block is padded when writing into the following one
+		_, _, err := a.app.Append(make([]byte, off-currOff))
+		if err != nil {
+			return err
+		}
+	}
+
+	bs := make([]byte, blockHeaderSize, blockHeaderSize+len(data)+a.aead.Overhead())
+	binary.BigEndian.PutUint64(bs, uint64(idx))
+	binary.BigEndian.PutUint32(bs[blockIdxSize:], uint32(len(data)))
+
+	nonce := bs[blockIdxSize+blockLenSize:]
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	bs = a.aead.Seal(bs, nonce, data, bs[:blockIdxSize+blockLenSize])
+
+	if idx == a.cachedBlockIdx {
+		a.cachedBlockIdx = -1
+	}
+
+	_, _, err = a.app.Append(bs)
+	if err != nil {
+		return err
+	}
+
+	if slot >= a.slots {
+		a.slots = slot + 1
+	}
+
+	return nil
+}
+
+// discardSlots overwrites with zeroes the slots from the given one onwards,
+// so blocks discarded by a rollback are not recovered when reopening the appendable
+func (a *EncryptedAppendable) discardSlots(slot int64) error {
+	if slot >= a.slots {
+		return nil
+	}
+
+	if a.shadowSlot >= slot {
+		// the preceding block must be durable in its own slot before its copy is discarded
+		err := a.app.Sync()
+		if err != nil {
+			return err
+		}
+
+		a.shadowSlot = -1
+	}
+
+	off := slot * a.slotSize
+	currOff := a.app.Offset()
+
+	if off < currOff {
+		err := a.app.SetOffset(off)
+		if err != nil {
+			return err
+		}
+	} else {
+		// slots beyond the current offset are overwritten as well
+		off = currOff
+	}
+
+	_, _, err := a.app.Append(make([]byte, a.slots*a.slotSize-off))
+	if err != nil {
+		return err
+	}
+
+	a.slots = slot
+
+	return nil
+}
+
+// writeBlock writes the last block into its slot
+func (a *EncryptedAppendable) writeBlock() error {
+	if a.synced {
+		err := a.writeSlot(a.blockIdx+1, a.blockIdx, a.block)
+		if err != nil {
+			return err
+		}
+
+		err = a.app.Sync()
+		if err != nil {
+			return err
+		}
+
+		a.shadowSlot = a.blockIdx + 1
+	}
+
+	err := a.writeSlot(a.blockIdx, a.blockIdx, a.block)
+	if err != nil {
+		return err
+	}
+
+	a.dirty = false
+	a.repair = false
+
+	return nil
+}
+
+func (a *EncryptedAppendable) sealBlock() error {
+	err := a.writeBlock()
+	if err != nil {
+		return err
+	}
+
+	a.blockIdx++
+	a.block = a.block[:0]
+	a.synced = false
+
+	return nil
+}
+
+// persist writes the last block into the underlying appendable if needed
+func (a *EncryptedAppendable) persist() (written bool, err error) {
+	if len(a.block) == a.blockSize {
+		return false, a.sealBlock()
+	}
+
+	if len(a.block) == 0 || (!a.dirty && !a.repair) {
+		return false, nil
+	}
+
+	return true, a.writeBlock()
+}
+
+func (a *EncryptedAppendable) readBlock(idx int64) ([]byte, error) {
+	if idx == a.cachedBlockIdx {
+		return a.cachedBlock, nil
+	}
+
+	a.cachedBlockIdx = -1
+
+	slotIdx, data, err := a.readSlot(idx, a.cachedBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	if slotIdx != idx || len(data) != a.blockSize {
+		return nil, fmt.Errorf("%w: incomplete block %d", ErrCorruptedData, idx)
+	}
+
+	a.cachedBlock = data
+	a.cachedBlockIdx = idx
+
+	return data, nil
+}
+
+func (a *EncryptedAppendable) Copy(dstPath string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if !a.readOnly {
+		_, err := a.persist()
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.app.Copy(dstPath)
+}
+
+func (a *EncryptedAppendable) CompressionFormat() int {
+	return appendable.NoCompression
+}
+
+func (a *EncryptedAppendable) CompressionLevel() int {
+	return a.app.CompressionLevel()
+}
+
+func (a *EncryptedAppendable) Metadata() []byte {
+	return a.metadata
+}
+
+func (a *EncryptedAppendable) Size() (int64, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return 0, ErrAlreadyClosed
+	}
+
+	return a.size(), nil
+}
+
+func (a *EncryptedAppendable) Offset() int64 {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.size()
+}
+
+func (a *EncryptedAppendable) size() int64 {
+	return a.blockIdx*int64(a.blockSize) + int64(len(a.block))
+}
+
+func (a *EncryptedAppendable) SetOffset(newOffset int64) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return ErrReadOnly
+	}
+
+	if newOffset < 0 {
+		return ErrNegativeOffset
+	}
+
+	currOffset := a.size()
+
+	if newOffset > currOffset {
+		return fmt.Errorf("%w: provided offset %d is bigger than current one %d", ErrIllegalArguments, newOffset, currOffset)
+	}
+
+	if newOffset == currOffset {
+		return nil
+	}
+
+	idx := newOffset / int64(a.blockSize)
+	blockOff := int(newOffset % int64(a.blockSize))
+
+	if idx == a.blockIdx {
+		a.block = a.block[:blockOff]
+		return nil
+	}
+
+	var data []byte
+
+	if blockOff > 0 {
+		var err error
+
+		data, err = a.readBlock(idx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// slots of the following blocks are discarded, the slot of the new last block
+	// is kept only if it's partially preserved
+	firstDiscardedSlot := idx + 1
+	if blockOff == 0 {
+		firstDiscardedSlot = idx
+	}
+
+	err := a.discardSlots(firstDiscardedSlot)
+	if err != nil {
+		return err
+	}
+
+	a.block = append(a.block[:0], data[:blockOff]...)
+
+	a.blockIdx = idx
+	a.dirty = false
+	a.synced = blockOff > 0
+	a.repair = false
+
+	return nil
+}
+
+func (a *EncryptedAppendable) DiscardUpto(off int64) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if a.size() < off {
+		return fmt.Errorf("%w: discard beyond existent data boundaries", ErrIllegalArguments)
+	}
+
+	return a.app.DiscardUpto(off / int64(a.blockSize) * a.slotSize)
+}
+
+func (a *EncryptedAppendable) Append(bs []byte) (off int64, n int, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return 0, 0, ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return 0, 0, ErrReadOnly
+	}
+
+	if len(bs) == 0 {
+		return 0, 0, ErrIllegalArguments
+	}
+
+	off = a.size()
+
+	for n < len(bs) {
+		if len(a.block) == a.blockSize {
+			err = a.sealBlock()
+			if err != nil {
+				return off, n, err
+			}
+		}
+
+		c := copy(a.block[len(a.block):a.blockSize], bs[n:])
+
+		a.block = a.block[:len(a.block)+c]
+		a.dirty = true
+
+		n += c
+	}
+
+	if len(a.block) == a.blockSize {
+		err = a.sealBlock()
+	}
+
+	return off, n, err
+}
+
+func (a *EncryptedAppendable) ReadAt(bs []byte, off int64) (n int, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return 0, ErrAlreadyClosed
+	}
+
+	if bs == nil {
+		return 0, ErrIllegalArguments
+	}
+
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+
+	size := a.size()
+
+	for n < len(bs) && off < size {
+		idx := off / int64(a.blockSize)
+
+		var data []byte
+
+		if idx == a.blockIdx {
+			data = a.block
+		} else {
+			data, err = a.readBlock(idx)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		c := copy(bs[n:], data[off%int64(a.blockSize):])
+
+		n += c
+		off += int64(c)
+	}
+
+	if n < len(bs) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+func (a *EncryptedAppendable) SwitchToReadOnlyMode() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err := a.persist()
+	if err != nil {
+		return err
+	}
+
+	err = a.app.SwitchToReadOnlyMode()
+	if err != nil {
+		return err
+	}
+
+	a.shadowSlot = -1
+	a.readOnly = true
+
+	return nil
+}
+
+func (a *EncryptedAppendable) Flush() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return ErrReadOnly
+	}
+
+	return a.app.Flush()
+}
+
+func (a *EncryptedAppendable) Sync() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return ErrReadOnly
+	}
+
+	written, err := a.persist()
+	if err != nil {
+		return err
+	}
+
+	err = a.app.Sync()
+	if err != nil {
+		return err
+	}
+
+	a.shadowSlot = -1
+
+	if written {
+		a.synced = true
+	}
+
+	return nil
+}
+
+func (a *EncryptedAppendable) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return ErrAlreadyClosed
+	}
+
+	if !a.readOnly && a.dirty {
+		_, err := a.persist()
+		if err != nil {
+			return err
+		}
+	}
+
+	a.closed = true
+
+	return a.app.Close()
+}
@@ -0,0 +1,532 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cryptoapp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/singleapp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyProvider(t *testing.T) *FileKeyProvider {
+	kp := NewFileKeyProvider(filepath.Join(t.TempDir(), "keys"))
+
+	_, err := kp.Rotate(32)
+	require.NoError(t, err)
+
+	return kp
+}
+
+func openEncrypted(t *testing.T, path string, kp KeyProvider) *EncryptedAppendable {
+	metadata := []byte("metadata")
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		metadata, err = NewMetadata(kp, metadata)
+		require.NoError(t, err)
+	}
+
+	app, err := singleapp.Open(path, singleapp.DefaultOptions().
+		WithCompressionFormat(appendable.NoCompression).
+		WithMetadata(metadata),
+	)
+	require.NoError(t, err)
+
+	a, err := Open(app, DefaultOptions().WithKeyProvider(kp))
+	require.NoError(t, err)
+
+	return a
+}
+
+func randomBytes(size int) []byte {
+	bs := make([]byte, size)
+	rand.Read(bs)
+	return bs
+}
+
+func TestEncryptedAppendable(t *testing.T) {
+	kp := newKeyProvider(t)
+	path := filepath.Join(t.TempDir(), "testdata.aof")
+
+	a := openEncrypted(t, path, kp)
+
+	require.Equal(t, []byte("metadata"), a.Metadata())
+	require.Equal(t, appendable.NoCompression, a.CompressionFormat())
+
+	sz, err := a.Size()
+	require.NoError(t, err)
+	require.Zero(t, sz)
+
+	_, _, err = a.Append(nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = a.ReadAt(nil, 0)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = a.ReadAt([]byte{0}, -1)
+	require.ErrorIs(t, err, ErrNegativeOffset)
+
+	var data []byte
+
+	for _, size := range []int{1, 100, DefaultBlockSize - 101, DefaultBlockSize, 3*DefaultBlockSize + 17, 5} {
+		bs := randomBytes(size)
+
+		off, n, err := a.Append(bs)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data)), off)
+		require.Equal(t, size, n)
+
+		data = append(data, bs...)
+	}
+
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	readAll := func(a *EncryptedAppendable) {
+		bs := make([]byte, len(data))
+
+		n, err := a.ReadAt(bs, 0)
+		require.NoError(t, err)
+		require.Equal(t, len(data), n)
+		require.Equal(t, data, bs)
+
+		bs = make([]byte, 10)
+
+		n, err = a.ReadAt(bs, int64(DefaultBlockSize-5))
+		require.NoError(t, err)
+		require.Equal(t, 10, n)
+		require.Equal(t, data[DefaultBlockSize-5:DefaultBlockSize+5], bs)
+
+		n, err = a.ReadAt(bs, int64(len(data)-5))
+		require.ErrorIs(t, err, io.EOF)
+		require.Equal(t, 5, n)
+	}
+
+	readAll(a)
+
+	err = a.Flush()
+	require.NoError(t, err)
+
+	err = a.Sync()
+	require.NoError(t, err)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	err = a.Close()
+	require.ErrorIs(t, err, ErrAlreadyClosed)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(raw, data[:100]))
+
+	a = openEncrypted(t, path, kp)
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	readAll(a)
+
+	err = a.SwitchToReadOnlyMode()
+	require.NoError(t, err)
+
+	_, _, err = a.Append([]byte{1})
+	require.ErrorIs(t, err, ErrReadOnly)
+
+	err = a.Close()
+	require.NoError(t, err)
+}
+
+func TestEncryptedAppendableGrowingBlock(t *testing.T) {
+	kp := newKeyProvider(t)
+	path := filepath.Join(t.TempDir(), "testdata.aof")
+
+	a := openEncrypted(t, path, kp)
+
+	var data []byte
+
+	for i := 0; i < 100; i++ {
+		bs := randomBytes(97)
+
+		_, _, err := a.Append(bs)
+		require.NoError(t, err)
+
+		err = a.Sync()
+		require.NoError(t, err)
+
+		data = append(data, bs...)
+	}
+
+	err := a.Close()
+	require.NoError(t, err)
+
+	a = openEncrypted(t, path, kp)
+	defer a.Close()
+
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	bs := make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+}
+
+func TestEncryptedAppendableRecoveryFromShadowCopy(t *testing.T) {
+	kp := newKeyProvider(t)
+	path := filepath.Join(t.TempDir(), "testdata.aof")
+
+	a := openEncrypted(t, path, kp)
+
+	data := randomBytes(DefaultBlockSize + 10)
+
+	_, _, err := a.Append(data[:DefaultBlockSize+5])
+	require.NoError(t, err)
+
+	err = a.Sync()
+	require.NoError(t, err)
+
+	// the last block is rewritten by first copying it into the following slot
+	_, _, err = a.Append(data[DefaultBlockSize+5:])
+	require.NoError(t, err)
+
+	err = a.Sync()
+	require.NoError(t, err)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	// simulate an interrupted write of the last block into its own slot
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+
+	mLenBs := make([]byte, 4)
+	_, err = f.ReadAt(mLenBs, 0)
+	require.NoError(t, err)
+
+	hdrSize := int64(4 + binary.BigEndian.Uint32(mLenBs))
+
+	_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, hdrSize+a.slotSize+blockHeaderSize)
+	require.NoError(t, err)
+
+	err = f.Close()
+	require.NoError(t, err)
+
+	a = openEncrypted(t, path, kp)
+
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	bs := make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+
+	// the recovered block is written back into its own slot
+	err = a.Sync()
+	require.NoError(t, err)
+
+	more := randomBytes(DefaultBlockSize)
+
+	_, _, err = a.Append(more)
+	require.NoError(t, err)
+
+	data = append(data, more...)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	a = openEncrypted(t, path, kp)
+	defer a.Close()
+
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	bs = make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+}
+
+func TestEncryptedAppendableSetOffset(t *testing.T) {
+	kp := newKeyProvider(t)
+	path := filepath.Join(t.TempDir(), "testdata.aof")
+
+	a := openEncrypted(t, path, kp)
+
+	data := randomBytes(3*DefaultBlockSize + 100)
+
+	_, _, err := a.Append(data)
+	require.NoError(t, err)
+
+	err = a.SetOffset(-1)
+	require.ErrorIs(t, err, ErrNegativeOffset)
+
+	err = a.SetOffset(int64(len(data) + 1))
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = a.SetOffset(int64(len(data) - 10))
+	require.NoError(t, err)
+
+	err = a.SetOffset(int64(DefaultBlockSize + 10))
+	require.NoError(t, err)
+	require.Equal(t, int64(DefaultBlockSize+10), a.Offset())
+
+	tail := randomBytes(50)
+
+	_, _, err = a.Append(tail)
+	require.NoError(t, err)
+
+	data = append(data[:DefaultBlockSize+10], tail...)
+
+	bs := make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	a = openEncrypted(t, path, kp)
+	defer a.Close()
+
+	// data beyond the last offset set is not truncated, as it happens with the underlying appendable
+	err = a.SetOffset(int64(len(data)))
+	require.NoError(t, err)
+
+	bs = make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+
+	err = a.DiscardUpto(a.Offset() + 1)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = a.DiscardUpto(a.Offset())
+	require.NoError(t, err)
+}
+
+func TestEncryptedAppendableKeys(t *testing.T) {
+	kp := newKeyProvider(t)
+	path := filepath.Join(t.TempDir(), "testdata.aof")
+
+	a := openEncrypted(t, path, kp)
+
+	_, _, err := a.Append([]byte("data"))
+	require.NoError(t, err)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	t.Run("files should be readable after a key rotation", func(t *testing.T) {
+		keyID, err := kp.Rotate(16)
+		require.NoError(t, err)
+		require.Equal(t, "2", keyID)
+
+		a := openEncrypted(t, path, kp)
+		defer a.Close()
+
+		bs := make([]byte, 4)
+		_, err = a.ReadAt(bs, 0)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), bs)
+
+		md, err := NewMetadata(kp, nil)
+		require.NoError(t, err)
+
+		mdKeyID, ok := appendable.NewMetadata(md).Get(metaKeyID)
+		require.True(t, ok)
+		require.Equal(t, []byte("2"), mdKeyID)
+	})
+
+	t.Run("opening without the right key should fail", func(t *testing.T) {
+		app, err := singleapp.Open(path, singleapp.DefaultOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer app.Close()
+
+		require.True(t, IsEncrypted(app.Metadata()))
+
+		_, err = Open(app, DefaultOptions())
+		require.ErrorIs(t, err, ErrKeyProviderRequired)
+
+		otherKp := newKeyProvider(t)
+
+		_, err = otherKp.Rotate(32)
+		require.NoError(t, err)
+
+		_, err = Open(app, DefaultOptions().WithKeyProvider(otherKp))
+		require.ErrorIs(t, err, ErrInvalidKey)
+
+		emptyKp := NewFileKeyProvider(filepath.Join(t.TempDir(), "keys"))
+
+		_, err = Open(app, DefaultOptions().WithKeyProvider(emptyKp))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		_, err = NewMetadata(emptyKp, nil)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("appendables should seal blocks with keys of their own", func(t *testing.T) {
+		_, key, err := kp.CurrentKey()
+		require.NoError(t, err)
+
+		md1, err := NewMetadata(kp, nil)
+		require.NoError(t, err)
+
+		md2, err := NewMetadata(kp, nil)
+		require.NoError(t, err)
+
+		salt1, ok := appendable.NewMetadata(md1).Get(metaKeySalt)
+		require.True(t, ok)
+		require.Len(t, salt1, keySaltSize)
+
+		salt2, ok := appendable.NewMetadata(md2).Get(metaKeySalt)
+		require.True(t, ok)
+		require.NotEqual(t, salt1, salt2)
+
+		key1 := deriveKey(key, salt1)
+		require.Len(t, key1, len(key))
+		require.NotEqual(t, key, key1)
+		require.NotEqual(t, key1, deriveKey(key, salt2))
+	})
+
+	t.Run("rotated keys should not reuse existing ids", func(t *testing.T) {
+		keysPath := filepath.Join(t.TempDir(), "keys")
+
+		err := os.WriteFile(keysPath, []byte("2:"+strings.Repeat("ab", 32)+"\nmaster:"+strings.Repeat("cd", 32)+"\n"), 0600)
+		require.NoError(t, err)
+
+		kp := NewFileKeyProvider(keysPath)
+
+		keyID, err := kp.Rotate(32)
+		require.NoError(t, err)
+		require.Equal(t, "3", keyID)
+
+		currentID, _, err := kp.CurrentKey()
+		require.NoError(t, err)
+		require.Equal(t, "3", currentID)
+
+		_, err = kp.Key("2")
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid keys should be rejected", func(t *testing.T) {
+		_, err := kp.Rotate(10)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		keysPath := filepath.Join(t.TempDir(), "keys")
+
+		err = os.WriteFile(keysPath, []byte("# comment\n\n1:0011\n"), 0600)
+		require.NoError(t, err)
+
+		_, err = NewMetadata(NewFileKeyProvider(keysPath), nil)
+		require.ErrorIs(t, err, ErrInvalidKey)
+
+		_, err = NewFileKeyProvider(keysPath).Key("2")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+
+		err = os.WriteFile(keysPath, []byte("1:zz\n"), 0600)
+		require.NoError(t, err)
+
+		_, _, err = NewFileKeyProvider(keysPath).CurrentKey()
+		require.ErrorIs(t, err, ErrInvalidKey)
+
+		err = os.WriteFile(keysPath, []byte(""), 0600)
+		require.NoError(t, err)
+
+		_, _, err = NewFileKeyProvider(keysPath).CurrentKey()
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
+
+func TestEncryptedAppendableInvalidOptions(t *testing.T) {
+	_, err := Open(nil, DefaultOptions())
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	require.ErrorIs(t, (*Options)(nil).Validate(), ErrInvalidOptions)
+	require.ErrorIs(t, DefaultOptions().Validate(), ErrKeyProviderRequired)
+
+	_, err = NewMetadata(nil, nil)
+	require.ErrorIs(t, err, ErrKeyProviderRequired)
+
+	require.False(t, IsEncrypted(nil))
+}
+
+func TestEncryptedAppendableRollback(t *testing.T) {
+	kp := newKeyProvider(t)
+	path := filepath.Join(t.TempDir(), "testdata.aof")
+
+	a := openEncrypted(t, path, kp)
+
+	data := randomBytes(8*DefaultBlockSize + 100)
+
+	_, _, err := a.Append(data)
+	require.NoError(t, err)
+
+	err = a.Sync()
+	require.NoError(t, err)
+
+	committed := int64(4*DefaultBlockSize + 30)
+
+	// uncommitted data is discarded and overwritten
+	err = a.SetOffset(committed)
+	require.NoError(t, err)
+
+	more := randomBytes(50)
+
+	_, _, err = a.Append(more)
+	require.NoError(t, err)
+
+	data = append(data[:committed], more...)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	a = openEncrypted(t, path, kp)
+
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	bs := make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+
+	err = a.SetOffset(committed)
+	require.NoError(t, err)
+
+	// rollback into a previous block without writing any further data
+	err = a.SetOffset(2 * DefaultBlockSize)
+	require.NoError(t, err)
+
+	data = data[:2*DefaultBlockSize]
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	a = openEncrypted(t, path, kp)
+	defer a.Close()
+
+	require.Equal(t, int64(len(data)), a.Offset())
+
+	bs = make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cryptoapp
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeyProvider supplies the keys used to encrypt appendables.
+// Keys are identified by an id which is stored in the metadata of each appendable,
+// so files written with a previous key can still be read after a rotation.
+type KeyProvider interface {
+	// CurrentKey returns the key to be used to encrypt newly created appendables
+	CurrentKey() (keyID string, key []byte, err error)
+
+	// Key returns the key with the given id
+	Key(keyID string) ([]byte, error)
+}
+
+// FileKeyProvider reads keys from a text file holding one key per line,
+// in the form <keyID>:<hex encoded key>. The last key in the file is the current one,
+// thus keys are rotated by appending a new line to the file.
+type FileKeyProvider struct {
+	path  string
+	mutex sync.Mutex
+}
+
+var _ KeyProvider = (*FileKeyProvider)(nil)
+
+func NewFileKeyProvider(path string) *FileKeyProvider {
+	return &FileKeyProvider{path: path}
+}
+
+func (p *FileKeyProvider) CurrentKey() (keyID string, key []byte, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ids, keys, err := p.readKeys()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(ids) == 0 {
+		return "", nil, fmt.Errorf("%w: no key found in '%s'", ErrKeyNotFound, p.path)
+	}
+
+	keyID = ids[len(ids)-1]
+
+	return keyID, keys[keyID], nil
+}
+
+func (p *FileKeyProvider) Key(keyID string) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	_, keys, err := p.readKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: key '%s' not found in '%s'", ErrKeyNotFound, keyID, p.path)
+	}
+
+	return key, nil
+}
+
+// Rotate generates a new random key of the given size (16, 24 or 32 bytes)
+// and appends it to the file, making it the current one. The file is created if it does not exist.
+func (p *FileKeyProvider) Rotate(keySize int) (keyID string, err error) {
+	if keySize != 16 && keySize != 24 && keySize != 32 {
+		return "", fmt.Errorf("%w: invalid key size %d", ErrIllegalArguments, keySize)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ids, _, err := p.readKeys()
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	key := make([]byte, keySize)
+
+	_, err = rand.Read(key)
+	if err != nil {
+		return "", err
+	}
+
+	keyID = nextKeyID(ids)
+
+	f, err := os.OpenFile(p.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s:%s\n", keyID, hex.EncodeToString(key))
+	if err != nil {
+		return "", err
+	}
+
+	return keyID, f.Sync()
+}
+
+// nextKeyID returns a numeric id greater than the numeric ids of the existing keys,
+// as files may be hand-written or pruned, ids do not necessarily match the number of keys
+func nextKeyID(ids []string) string {
+	maxID := 0
+
+	for _, id := range ids {
+		n, err := strconv.Atoi(id)
+		if err == nil && n > maxID {
+			maxID = n
+		}
+	}
+
+	return strconv.Itoa(maxID + 1)
+}
+
+func (p *FileKeyProvider) readKeys() (ids []string, keys map[string][]byte, err error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	keys = make(map[string][]byte)
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, nil, fmt.Errorf("%w: malformed line in '%s'", ErrInvalidKey, p.path)
+		}
+
+		key, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: malformed key '%s' in '%s'", ErrInvalidKey, parts[0], p.path)
+		}
+
+		if _, ok := keys[parts[0]]; ok {
+			return nil, nil, fmt.Errorf("%w: duplicated key '%s' in '%s'", ErrInvalidKey, parts[0], p.path)
+		}
+
+		ids = append(ids, parts[0])
+		keys[parts[0]] = key
+	}
+
+	return ids, keys, scanner.Err()
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cryptoapp
+
+import "fmt"
+
+const DefaultBlockSize = 4096
+
+type Options struct {
+	readOnly    bool
+	keyProvider KeyProvider
+}
+
+func DefaultOptions() *Options {
+	return &Options{}
+}
+
+func (opts *Options) Validate() error {
+	if opts == nil {
+		return fmt.Errorf("%w: nil options", ErrInvalidOptions)
+	}
+
+	if opts.keyProvider == nil {
+		return ErrKeyProviderRequired
+	}
+
+	return nil
+}
+
+func (opts *Options) WithReadOnly(readOnly bool) *Options {
+	opts.readOnly = readOnly
+	return opts
+}
+
+func (opts *Options) WithKeyProvider(keyProvider KeyProvider) *Options {
+	opts.keyProvider = keyProvider
+	return opts
+}
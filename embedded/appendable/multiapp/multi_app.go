@@ -28,6 +28,7 @@ import (
 	"sync"
 
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/fileutils"
 	"github.com/codenotary/immudb/embedded/appendable/singleapp"
 	"github.com/codenotary/immudb/embedded/cache"
@@ -108,6 +109,12 @@ type MultiFileAppendable struct {
 	fileExt        string
 	readBufferSize int
 	prealloc       bool
+	keyProvider    cryptoapp.KeyProvider
+
+	// compression settings used when creating new files,
+	// existing ones keep the settings stored in their metadata
+	compressionFormat int
+	compressionLevel  int
 
 	writeBuffer []byte // shared write-buffer only used by active appendable
 
@@ -153,6 +160,15 @@ func OpenWithHooks(path string, hooks MultiFileAppendableHooks, opts *Options) (
 	m.PutInt(metaFileSize, opts.fileSize)
 	m.Put(metaWrappedMeta, opts.metadata)
 
+	metadata := m.Bytes()
+
+	if opts.keyProvider != nil && !opts.readOnly {
+		metadata, err = cryptoapp.NewMetadata(opts.keyProvider, metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var writeBuffer []byte
 
 	if !opts.readOnly {
@@ -169,7 +185,7 @@ func OpenWithHooks(path string, hooks MultiFileAppendableHooks, opts *Options) (
 		WithCompresionLevel(opts.compressionLevel).
 		WithReadBufferSize(opts.readBufferSize).
 		WithWriteBuffer(writeBuffer).
-		WithMetadata(m.Bytes())
+		WithMetadata(metadata)
 
 	if opts.prealloc {
 		appendableOpts.WithPreallocSize(opts.fileSize)
@@ -180,6 +196,14 @@ func OpenWithHooks(path string, hooks MultiFileAppendableHooks, opts *Options) (
 		return nil, err
 	}
 
+	encApp, err := openEncrypted(currApp, opts.readOnly, opts.keyProvider)
+	if err != nil {
+		currApp.Close()
+		return nil, err
+	}
+
+	currApp = encApp
+
 	cache, err := cache.NewCache(opts.maxOpenedFiles)
 	if err != nil {
 		return nil, err
@@ -188,24 +212,45 @@ func OpenWithHooks(path string, hooks MultiFileAppendableHooks, opts *Options) (
 	fileSize, _ := appendable.NewMetadata(currApp.Metadata()).GetInt(metaFileSize)
 
 	return &MultiFileAppendable{
-		appendables:    appendableCache{cache: cache},
-		currAppID:      currAppID,
-		currApp:        currApp,
-		path:           path,
-		readOnly:       opts.readOnly,
-		retryableSync:  opts.retryableSync,
-		autoSync:       opts.autoSync,
-		fileMode:       opts.fileMode,
-		fileSize:       fileSize,
-		fileExt:        opts.fileExt,
-		readBufferSize: opts.readBufferSize,
-		prealloc:       opts.prealloc,
-		writeBuffer:    writeBuffer,
-		closed:         false,
-		hooks:          hooks,
+		appendables:       appendableCache{cache: cache},
+		currAppID:         currAppID,
+		currApp:           currApp,
+		path:              path,
+		readOnly:          opts.readOnly,
+		retryableSync:     opts.retryableSync,
+		autoSync:          opts.autoSync,
+		fileMode:          opts.fileMode,
+		fileSize:          fileSize,
+		fileExt:           opts.fileExt,
+		readBufferSize:    opts.readBufferSize,
+		prealloc:          opts.prealloc,
+		keyProvider:       opts.keyProvider,
+		writeBuffer:       writeBuffer,
+		compressionFormat: opts.compressionFormat,
+		compressionLevel:  opts.compressionLevel,
+		closed:            false,
+		hooks:             hooks,
 	}, nil
 }
 
+// openEncrypted wraps the appendable when its content is encrypted,
+// files written before encryption was enabled are returned as they are
+func openEncrypted(app appendable.Appendable, readOnly bool, keyProvider cryptoapp.KeyProvider) (appendable.Appendable, error) {
+	if !cryptoapp.IsEncrypted(app.Metadata()) {
+		return app, nil
+	}
+
+	encApp, err := cryptoapp.Open(app, cryptoapp.DefaultOptions().
+		WithReadOnly(readOnly).
+		WithKeyProvider(keyProvider),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return encApp, nil
+}
+
 func appendableName(appID int64, ext string) string {
 	return fmt.Sprintf("%08d.%s", appID, ext)
 }
@@ -401,7 +446,36 @@ func (mf *MultiFileAppendable) openAppendable(appname string, createIfNotExists,
 		appendableOpts.WithWriteBuffer(mf.writeBuffer)
 	}
 
-	return mf.hooks.OpenAppendable(appendableOpts, appname, activeChunk)
+	if createIfNotExists {
+		appendableOpts.
+			WithCompressionFormat(mf.compressionFormat).
+			WithCompresionLevel(mf.compressionLevel)
+	}
+
+	if createIfNotExists && mf.keyProvider != nil && !mf.readOnly {
+		// new files are encrypted with the current key, so keys can be rotated over time
+		metadata, err := cryptoapp.NewMetadata(mf.keyProvider, mf.currApp.Metadata())
+		if err != nil {
+			return nil, err
+		}
+
+		appendableOpts.
+			WithCompressionFormat(appendable.NoCompression).
+			WithMetadata(metadata)
+	}
+
+	app, err := mf.hooks.OpenAppendable(appendableOpts, appname, activeChunk)
+	if err != nil {
+		return nil, err
+	}
+
+	encApp, err := openEncrypted(app, mf.readOnly || !activeChunk, mf.keyProvider)
+	if err != nil {
+		app.Close()
+		return nil, err
+	}
+
+	return encApp, nil
 }
 
 func (mf *MultiFileAppendable) Offset() int64 {
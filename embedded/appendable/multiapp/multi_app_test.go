@@ -17,6 +17,7 @@ limitations under the License.
 package multiapp
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
@@ -24,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/singleapp"
 
 	"github.com/stretchr/testify/require"
@@ -303,6 +305,147 @@ func TestMultiAppCompression(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMultiAppCompressionFormatChange(t *testing.T) {
+	path := t.TempDir()
+
+	var offs []int64
+	var entries [][]byte
+
+	formats := []int{
+		appendable.ZLibCompression,
+		appendable.ZstdCompression,
+		appendable.LZ4Compression,
+		appendable.SnappyCompression,
+	}
+
+	for _, format := range formats {
+		a, err := Open(path, DefaultOptions().WithFileSize(100).WithCompressionFormat(format))
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			bs := bytes.Repeat([]byte{byte(format), byte(i)}, 20)
+
+			off, _, err := a.Append(bs)
+			require.NoError(t, err)
+
+			offs = append(offs, off)
+			entries = append(entries, bs)
+		}
+
+		// only newly created files are written with the new format
+		require.Equal(t, format, a.CompressionFormat())
+
+		err = a.Close()
+		require.NoError(t, err)
+	}
+
+	a, err := Open(path, DefaultOptions().WithReadOnly(true))
+	require.NoError(t, err)
+
+	for i, off := range offs {
+		bs := make([]byte, len(entries[i]))
+
+		_, err = a.ReadAt(bs, off)
+		require.NoError(t, err)
+		require.Equal(t, entries[i], bs)
+	}
+
+	err = a.Close()
+	require.NoError(t, err)
+}
+
+func TestMultiAppEncryption(t *testing.T) {
+	path := t.TempDir()
+
+	kp := cryptoapp.NewFileKeyProvider(filepath.Join(t.TempDir(), "keys"))
+
+	_, err := Open(path, DefaultOptions().WithKeyProvider(kp))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = Open(path, DefaultOptions().WithKeyProvider(kp).WithCompressionFormat(appendable.ZLibCompression))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = Open(path, DefaultOptions().WithKeyProvider(kp).WithCompressionFormat(appendable.NoCompression).WithPrealloc(true))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	// files written before enabling encryption remain readable
+	a, err := Open(path, DefaultOptions().WithFileSize(10000).WithCompressionFormat(appendable.NoCompression))
+	require.NoError(t, err)
+
+	data := make([]byte, 25000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	_, _, err = a.Append(data[:5000])
+	require.NoError(t, err)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	_, err = kp.Rotate(32)
+	require.NoError(t, err)
+
+	opts := DefaultOptions().
+		WithFileSize(10000).
+		WithCompressionFormat(appendable.NoCompression).
+		WithKeyProvider(kp)
+
+	a, err = Open(path, opts)
+	require.NoError(t, err)
+
+	_, _, err = a.Append(data[5000:15000])
+	require.NoError(t, err)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	// newly created files are encrypted with the current key
+	_, err = kp.Rotate(32)
+	require.NoError(t, err)
+
+	a, err = Open(path, opts)
+	require.NoError(t, err)
+
+	_, _, err = a.Append(data[15000:])
+	require.NoError(t, err)
+
+	bs := make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	for appID, keyID := range []string{"", "1", "2"} {
+		app, err := singleapp.Open(filepath.Join(path, appendableName(int64(appID), "aof")), singleapp.DefaultOptions().WithReadOnly(true))
+		require.NoError(t, err)
+
+		md := appendable.NewMetadata(app.Metadata())
+
+		id, _ := md.Get("ENCRYPTION_KEY_ID")
+		require.Equal(t, keyID, string(id))
+
+		err = app.Close()
+		require.NoError(t, err)
+	}
+
+	a, err = Open(path, opts.WithReadOnly(true))
+	require.NoError(t, err)
+
+	bs = make([]byte, len(data))
+	_, err = a.ReadAt(bs, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+
+	err = a.Close()
+	require.NoError(t, err)
+
+	_, err = Open(path, DefaultOptions().WithReadOnly(true))
+	require.ErrorIs(t, err, cryptoapp.ErrKeyProviderRequired)
+}
+
 func TestMultiAppAppendableForCurrentChunk(t *testing.T) {
 	path := t.TempDir()
 
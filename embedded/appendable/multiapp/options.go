@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 )
 
 const DefaultFileSize = 1 << 26 // 64Mb
@@ -47,6 +48,8 @@ type Options struct {
 	maxOpenedFiles    int
 	compressionFormat int
 	compressionLevel  int
+
+	keyProvider cryptoapp.KeyProvider // if set, newly created files are encrypted with its current key
 }
 
 func DefaultOptions() *Options {
@@ -90,6 +93,14 @@ func (opts *Options) Validate() error {
 		return fmt.Errorf("%w: invalid writeBufferSize", ErrInvalidOptions)
 	}
 
+	if opts.keyProvider != nil && opts.compressionFormat != appendable.NoCompression {
+		return fmt.Errorf("%w: compression can not be used along with encryption", ErrInvalidOptions)
+	}
+
+	if opts.keyProvider != nil && opts.prealloc {
+		return fmt.Errorf("%w: preallocation can not be used along with encryption", ErrInvalidOptions)
+	}
+
 	return nil
 }
 
@@ -158,6 +169,11 @@ func (opts *Options) WithPrealloc(prealloc bool) *Options {
 	return opts
 }
 
+func (opts *Options) WithKeyProvider(keyProvider cryptoapp.KeyProvider) *Options {
+	opts.keyProvider = keyProvider
+	return opts
+}
+
 func (opt *Options) GetFileExt() string {
 	return opt.fileExt
 }
@@ -177,3 +193,7 @@ func (opts *Options) GetWriteBufferSize() int {
 func (opts *Options) GetPrealloc() bool {
 	return opts.prealloc
 }
+
+func (opts *Options) GetKeyProvider() cryptoapp.KeyProvider {
+	return opts.keyProvider
+}
@@ -11,10 +11,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+const metaWrappedMeta = "WRAPPED_METADATA"
+
 type remoteStorageReader struct {
 	r          remotestorage.Storage
 	name       string
 	baseOffset int64
+	header     []byte
 	dataCache  []byte // Initially we read the whole object into data cache
 }
 
@@ -42,7 +45,7 @@ func openRemoteStorageReader(r remotestorage.Storage, name string) (*remoteStora
 		return nil, ErrCorruptedMetadata
 	}
 
-	// TODO: Read the metadata and validate it
+	// TODO: Validate the metadata
 
 	baseOffset := int64(4 + binary.BigEndian.Uint32(data[:4]))
 	if baseOffset > int64(len(data)) {
@@ -54,16 +57,19 @@ func openRemoteStorageReader(r remotestorage.Storage, name string) (*remoteStora
 		r:          r,
 		name:       name,
 		baseOffset: baseOffset,
+		header:     data[4:baseOffset],
 		dataCache:  data[baseOffset:],
 	}, nil
 }
 
+// Metadata is needed by the multi-file appendable to detect encrypted chunks
 func (r *remoteStorageReader) Metadata() []byte {
-	panic("unimplemented")
+	metadata, _ := appendable.NewMetadata(r.header).Get(metaWrappedMeta)
+	return metadata
 }
 
 func (r *remoteStorageReader) Size() (int64, error) {
-	panic("unimplemented")
+	return int64(len(r.dataCache)), nil
 }
 
 func (r *remoteStorageReader) Offset() int64 {
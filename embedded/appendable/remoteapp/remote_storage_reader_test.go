@@ -2,12 +2,14 @@ package remoteapp
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"io"
 	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/appendable"
 	"github.com/codenotary/immudb/embedded/remotestorage"
 	"github.com/codenotary/immudb/embedded/remotestorage/memory"
 	"github.com/stretchr/testify/require"
@@ -36,8 +38,6 @@ func storeData(t *testing.T, s remotestorage.Storage, name string, data []byte)
 func TestRemoteStorageReaderUnsupportedMethods(t *testing.T) {
 	r := remoteStorageReader{}
 
-	require.Panics(t, func() { r.Metadata() })
-	require.Panics(t, func() { r.Size() })
 	require.Panics(t, func() { r.Offset() })
 	require.Panics(t, func() { r.SetOffset(0) })
 	require.Panics(t, func() { r.Append([]byte{0}) })
@@ -73,6 +73,10 @@ func TestRemoteStorageReadAt(t *testing.T) {
 	r, err := openRemoteStorageReader(m, "fl")
 	require.NoError(t, err)
 
+	sz, err := r.Size()
+	require.NoError(t, err)
+	require.EqualValues(t, 4, sz)
+
 	b := make([]byte, 4)
 	n, err := r.ReadAt(b, 0)
 	require.NoError(t, err)
@@ -100,6 +104,28 @@ func TestRemoteStorageReadAt(t *testing.T) {
 	require.Equal(t, io.EOF, err)
 }
 
+func TestRemoteStorageReaderMetadata(t *testing.T) {
+	md := appendable.NewMetadata(nil)
+	md.Put(metaWrappedMeta, []byte("metadata"))
+
+	header := md.Bytes()
+
+	data := make([]byte, 4+len(header))
+	binary.BigEndian.PutUint32(data, uint32(len(header)))
+	copy(data[4:], header)
+
+	m := memory.Open()
+	storeData(t, m, "fl", append(data, 1, 2, 3))
+
+	r, err := openRemoteStorageReader(m, "fl")
+	require.NoError(t, err)
+	require.Equal(t, []byte("metadata"), r.Metadata())
+
+	sz, err := r.Size()
+	require.NoError(t, err)
+	require.EqualValues(t, 3, sz)
+}
+
 func TestRemoteStorageCorruptedHeader(t *testing.T) {
 	for _, d := range []struct {
 		name  string
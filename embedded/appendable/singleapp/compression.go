@@ -0,0 +1,205 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package singleapp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// zstd encoders and decoders are expensive to create but safe for concurrent use
+// when compressing whole blocks, so they are shared among all the appendables
+var (
+	zstdMutex    sync.Mutex
+	zstdEncoders = make(map[zstd.EncoderLevel]*zstd.Encoder)
+	zstdDecoder  *zstd.Decoder
+)
+
+// lz4MaxExpansion bounds the size of a decompressed lz4 block relative to its compressed size,
+// as every match of the block format encodes at most 255 additional bytes per byte
+const lz4MaxExpansion = 255
+
+var lz4Compressors = sync.Pool{
+	New: func() interface{} { return &lz4.Compressor{} },
+}
+
+// compress returns the compressed representation of bs.
+// Newer formats use block compression, as creating stream writers
+// for every appended entry is too costly for them.
+func (aof *AppendableFile) compress(bs []byte) ([]byte, error) {
+	switch aof.compressionFormat {
+	case appendable.ZstdCompression:
+		enc, err := zstdEncoder(zstdLevel(aof.compressionLevel))
+		if err != nil {
+			return nil, err
+		}
+
+		return enc.EncodeAll(bs, nil), nil
+	case appendable.LZ4Compression:
+		return lz4Compress(bs, aof.compressionLevel)
+	case appendable.SnappyCompression:
+		return snappy.Encode(nil, bs), nil
+	}
+
+	var b bytes.Buffer
+
+	w, err := aof.writer(&b)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	w.(io.Closer).Close()
+
+	return b.Bytes(), nil
+}
+
+func (aof *AppendableFile) decompress(cbs []byte) ([]byte, error) {
+	switch aof.compressionFormat {
+	case appendable.ZstdCompression:
+		dec, err := zstdDecoderInstance()
+		if err != nil {
+			return nil, err
+		}
+
+		return dec.DecodeAll(cbs, nil)
+	case appendable.LZ4Compression:
+		return lz4Decompress(cbs)
+	case appendable.SnappyCompression:
+		return snappy.Decode(nil, cbs)
+	}
+
+	r, err := aof.reader(bytes.NewReader(cbs))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	return buf.Bytes(), nil
+}
+
+func zstdEncoder(level zstd.EncoderLevel) (*zstd.Encoder, error) {
+	zstdMutex.Lock()
+	defer zstdMutex.Unlock()
+
+	enc, ok := zstdEncoders[level]
+	if ok {
+		return enc, nil
+	}
+
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+
+	zstdEncoders[level] = enc
+
+	return enc, nil
+}
+
+func zstdDecoderInstance() (*zstd.Decoder, error) {
+	zstdMutex.Lock()
+	defer zstdMutex.Unlock()
+
+	if zstdDecoder != nil {
+		return zstdDecoder, nil
+	}
+
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	zstdDecoder = dec
+
+	return dec, nil
+}
+
+// zstdLevel maps flate compression levels into the closest zstd ones
+func zstdLevel(level int) zstd.EncoderLevel {
+	switch {
+	case level == appendable.DefaultCompression:
+		return zstd.SpeedDefault
+	case level <= appendable.BestSpeed:
+		return zstd.SpeedFastest
+	case level < 6:
+		return zstd.SpeedDefault
+	case level < appendable.BestCompression:
+		return zstd.SpeedBetterCompression
+	}
+	return zstd.SpeedBestCompression
+}
+
+// lz4Compress compresses bs as a single lz4 block prefixed by its uncompressed size.
+// The high compression mode is used for levels above BestSpeed.
+func lz4Compress(bs []byte, level int) ([]byte, error) {
+	cbs := make([]byte, 4+lz4.CompressBlockBound(len(bs)))
+	binary.BigEndian.PutUint32(cbs, uint32(len(bs)))
+
+	var n int
+	var err error
+
+	if level > appendable.BestSpeed && level <= appendable.BestCompression {
+		c := lz4.CompressorHC{Level: lz4.Level1 << (level - 1)}
+		n, err = c.CompressBlock(bs, cbs[4:])
+	} else {
+		c := lz4Compressors.Get().(*lz4.Compressor)
+		n, err = c.CompressBlock(bs, cbs[4:])
+		lz4Compressors.Put(c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cbs[:4+n], nil
+}
+
+func lz4Decompress(cbs []byte) ([]byte, error) {
+	if len(cbs) < 4 {
+		return nil, fmt.Errorf("%w: invalid lz4 block", ErrCorruptedData)
+	}
+
+	// the size is read from disk, so it's checked before allocating the buffer
+	size := int64(binary.BigEndian.Uint32(cbs))
+	if size > lz4MaxExpansion*int64(len(cbs)-4) {
+		return nil, fmt.Errorf("%w: invalid lz4 block size", ErrCorruptedData)
+	}
+
+	bs := make([]byte, size)
+
+	n, err := lz4.UncompressBlock(cbs[4:], bs)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs[:n], nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package singleapp
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleAppCompressionFormats(t *testing.T) {
+	for _, format := range []int{
+		appendable.ZstdCompression,
+		appendable.LZ4Compression,
+		appendable.SnappyCompression,
+	} {
+		for _, level := range []int{
+			appendable.BestSpeed,
+			appendable.BestCompression,
+			appendable.DefaultCompression,
+			appendable.HuffmanOnly,
+		} {
+			t.Run(fmt.Sprintf("format_%d_level_%d", format, level), func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "testdata.aof")
+
+				opts := DefaultOptions().
+					WithCompressionFormat(format).
+					WithCompresionLevel(level)
+
+				a, err := Open(path, opts)
+				require.NoError(t, err)
+
+				data := bytes.Repeat([]byte{1, 2, 3}, 1000)
+
+				off, _, err := a.Append(data)
+				require.NoError(t, err)
+				require.Equal(t, int64(0), off)
+
+				err = a.Close()
+				require.NoError(t, err)
+
+				// compression format and level are read from the metadata
+				a, err = Open(path, DefaultOptions().WithReadOnly(true))
+				require.NoError(t, err)
+				require.Equal(t, format, a.CompressionFormat())
+				require.Equal(t, level, a.CompressionLevel())
+
+				bs := make([]byte, len(data))
+				_, err = a.ReadAt(bs, 0)
+				require.NoError(t, err)
+				require.Equal(t, data, bs)
+
+				err = a.Close()
+				require.NoError(t, err)
+			})
+		}
+	}
+}
+
+func TestSingleAppCorruptedCompressedData(t *testing.T) {
+	_, err := lz4Decompress([]byte{0, 0})
+	require.ErrorIs(t, err, ErrCorruptedData)
+
+	_, err = lz4Decompress([]byte{0, 0, 0, 10, 1})
+	require.Error(t, err)
+
+	_, err = lz4Decompress([]byte{0xff, 0xff, 0xff, 0xff, 1})
+	require.ErrorIs(t, err, ErrCorruptedData)
+
+	// the most compressible content stays within the size bound
+	data := make([]byte, 1<<20)
+
+	cbs, err := lz4Compress(data, appendable.DefaultCompression)
+	require.NoError(t, err)
+
+	bs, err := lz4Decompress(cbs)
+	require.NoError(t, err)
+	require.Equal(t, data, bs)
+}
+
+func BenchmarkCompressionFormats(b *testing.B) {
+	formats := []struct {
+		name   string
+		format int
+	}{
+		{"no-compression", appendable.NoCompression},
+		{"flate", appendable.FlateCompression},
+		{"gzip", appendable.GZipCompression},
+		{"lzw", appendable.LZWCompression},
+		{"zlib", appendable.ZLibCompression},
+		{"zstd", appendable.ZstdCompression},
+		{"lz4", appendable.LZ4Compression},
+		{"snappy", appendable.SnappyCompression},
+	}
+
+	// half random, half repeated content, similar to what is found in value logs
+	chunk := make([]byte, 512)
+	rand.Read(chunk[:256])
+
+	for _, f := range formats {
+		b.Run(f.name+"/append", func(b *testing.B) {
+			opts := DefaultOptions().
+				WithRetryableSync(false).
+				WithWriteBuffer(make([]byte, DefaultWriteBufferSize*16)).
+				WithCompressionFormat(f.format)
+
+			app, err := Open(filepath.Join(b.TempDir(), "testdata_benchmark_compression.aof"), opts)
+			require.NoError(b, err)
+
+			b.SetBytes(int64(len(chunk)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, _, err = app.Append(chunk)
+				require.NoError(b, err)
+			}
+
+			b.StopTimer()
+
+			sz, err := app.Size()
+			require.NoError(b, err)
+
+			b.ReportMetric(float64(sz)/float64(b.N*len(chunk)), "ratio")
+
+			err = app.Close()
+			require.NoError(b, err)
+		})
+
+		b.Run(f.name+"/read", func(b *testing.B) {
+			opts := DefaultOptions().
+				WithRetryableSync(false).
+				WithCompressionFormat(f.format)
+
+			app, err := Open(filepath.Join(b.TempDir(), "testdata_benchmark_compression.aof"), opts)
+			require.NoError(b, err)
+
+			offs := make([]int64, 1000)
+
+			for i := range offs {
+				offs[i], _, err = app.Append(chunk)
+				require.NoError(b, err)
+			}
+
+			err = app.Flush()
+			require.NoError(b, err)
+
+			bs := make([]byte, len(chunk))
+
+			b.SetBytes(int64(len(chunk)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err = app.ReadAt(bs, offs[i%len(offs)])
+				require.NoError(b, err)
+			}
+
+			b.StopTimer()
+
+			err = app.Close()
+			require.NoError(b, err)
+		})
+	}
+}
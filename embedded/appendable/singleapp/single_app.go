@@ -18,7 +18,6 @@ package singleapp
 
 import (
 	"bufio"
-	"bytes"
 	"compress/flate"
 	"compress/gzip"
 	"compress/lzw"
@@ -43,6 +42,7 @@ var ErrReadOnly = errors.New("singleapp: read-only mode")
 var ErrCorruptedMetadata = errors.New("singleapp: corrupted metadata")
 var ErrBufferFull = errors.New("singleapp: buffer full")
 var ErrNegativeOffset = errors.New("singleapp: negative offset")
+var ErrCorruptedData = errors.New("singleapp: corrupted data")
 
 const (
 	metaPreallocSize      = "PREALLOC_SIZE"
@@ -409,22 +409,11 @@ func (aof *AppendableFile) Append(bs []byte) (off int64, n int, err error) {
 		return off, n, err
 	}
 
-	var b bytes.Buffer
-
-	w, err := aof.writer(&b)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	_, err = w.Write(bs)
+	bb, err := aof.compress(bs)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	w.(io.Closer).Close()
-
-	bb := b.Bytes()
-
 	bbLenBs := make([]byte, 4)
 	binary.BigEndian.PutUint32(bbLenBs, uint32(len(bb)))
 
@@ -542,15 +531,10 @@ func (aof *AppendableFile) ReadAt(bs []byte, off int64) (n int, err error) {
 		return 0, err
 	}
 
-	r, err := aof.reader(bytes.NewReader(cBs))
+	rbs, err := aof.decompress(cBs)
 	if err != nil {
 		return 0, err
 	}
-	defer r.Close()
-
-	var buf bytes.Buffer
-	buf.ReadFrom(r)
-	rbs := buf.Bytes()
 
 	n = minInt(len(rbs), len(bs))
 
@@ -291,6 +291,21 @@ func docIDFieldName(table *sql.Table) string {
 	return table.PrimaryIndex().Cols()[0].Name()
 }
 
+// CollectionPrefix returns the prefix shared by the keys under which the documents of a collection are stored
+func (e *Engine) CollectionPrefix(ctx context.Context, collectionName string) ([]byte, error) {
+	err := validateCollectionName(collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := e.sqlEngine.TableRowsPrefix(ctx, collectionName)
+	if errors.Is(err, sql.ErrTableDoesNotExist) {
+		return nil, fmt.Errorf("%w (%s)", mayTranslateError(err), collectionName)
+	}
+
+	return prefix, mayTranslateError(err)
+}
+
 func getTableForCollection(sqlTx *sql.SQLTx, collectionName string) (*sql.Table, error) {
 	err := validateCollectionName(collectionName)
 	if err != nil {
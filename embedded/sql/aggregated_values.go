@@ -16,7 +16,14 @@ limitations under the License.
 
 package sql
 
-import "strconv"
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 type AggregatedValue interface {
 	TypedValue
@@ -28,6 +35,9 @@ type AggregatedValue interface {
 type CountValue struct {
 	c   int64
 	sel string
+
+	// when set, only non-null values of the selected column are counted i.e. COUNT(col)
+	colBounded bool
 }
 
 func (v *CountValue) Selector() string {
@@ -35,7 +45,7 @@ func (v *CountValue) Selector() string {
 }
 
 func (v *CountValue) ColBounded() bool {
-	return false
+	return v.colBounded
 }
 
 func (v *CountValue) Type() SQLValueType {
@@ -73,17 +83,22 @@ func (v *CountValue) Compare(val TypedValue) (int, error) {
 }
 
 func (v *CountValue) updateWith(val TypedValue) error {
+	if v.colBounded && val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
 	v.c++
 	return nil
 }
 
 // ValueExp
 
-func (v *CountValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+func (v *CountValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	return IntegerType, nil
 }
 
-func (v *CountValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+func (v *CountValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
 	if t != IntegerType {
 		return ErrNotComparableValues
 	}
@@ -114,7 +129,7 @@ func (v *CountValue) isConstant() bool {
 	return false
 }
 
-func (v *CountValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *CountValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
@@ -179,11 +194,11 @@ func (v *SumValue) updateWith(val TypedValue) error {
 
 // ValueExp
 
-func (v *SumValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+func (v *SumValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	return IntegerType, nil
 }
 
-func (v *SumValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+func (v *SumValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
 	if t != IntegerType {
 		return ErrNotComparableValues
 	}
@@ -214,7 +229,7 @@ func (v *SumValue) isConstant() bool {
 	return false
 }
 
-func (v *SumValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *SumValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
@@ -277,7 +292,7 @@ func (v *MinValue) updateWith(val TypedValue) error {
 
 // ValueExp
 
-func (v *MinValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+func (v *MinValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	if v.val.IsNull() {
 		return AnyType, ErrUnexpected
 	}
@@ -285,7 +300,7 @@ func (v *MinValue) inferType(cols map[string]ColDescriptor, params map[string]SQ
 	return v.val.Type(), nil
 }
 
-func (v *MinValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+func (v *MinValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
 	if v.val.IsNull() {
 		return ErrUnexpected
 	}
@@ -321,7 +336,7 @@ func (v *MinValue) isConstant() bool {
 	return false
 }
 
-func (v *MinValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *MinValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
@@ -384,7 +399,7 @@ func (v *MaxValue) updateWith(val TypedValue) error {
 
 // ValueExp
 
-func (v *MaxValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+func (v *MaxValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	if v.val.IsNull() {
 		return AnyType, ErrUnexpected
 	}
@@ -392,7 +407,7 @@ func (v *MaxValue) inferType(cols map[string]ColDescriptor, params map[string]SQ
 	return v.val.Type(), nil
 }
 
-func (v *MaxValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+func (v *MaxValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
 	if v.val.IsNull() {
 		return ErrUnexpected
 	}
@@ -428,7 +443,7 @@ func (v *MaxValue) isConstant() bool {
 	return false
 }
 
-func (v *MaxValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *MaxValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
@@ -508,11 +523,11 @@ func (v *AVGValue) updateWith(val TypedValue) error {
 
 // ValueExp
 
-func (v *AVGValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+func (v *AVGValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	return IntegerType, nil
 }
 
-func (v *AVGValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+func (v *AVGValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
 	if t != IntegerType {
 		return ErrNotComparableValues
 	}
@@ -544,6 +559,464 @@ func (v *AVGValue) isConstant() bool {
 	return false
 }
 
-func (v *AVGValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *AVGValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+// distinctAggValue wraps an aggregation so that it is only updated with distinct values
+type distinctAggValue struct {
+	AggregatedValue
+
+	seen  map[[sha256.Size]byte]struct{}
+	limit int
+}
+
+func newDistinctAggValue(v AggregatedValue, limit int) *distinctAggValue {
+	return &distinctAggValue{
+		AggregatedValue: v,
+		seen:            make(map[[sha256.Size]byte]struct{}),
+		limit:           limit,
+	}
+}
+
+func (v *distinctAggValue) updateWith(val TypedValue) error {
+	if val.IsNull() {
+		return v.AggregatedValue.updateWith(val)
+	}
+
+	encVal, err := EncodeValue(val, val.Type(), 0)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(encVal)
+
+	if _, ok := v.seen[digest]; ok {
+		return nil
+	}
+
+	if len(v.seen) == v.limit {
+		return ErrTooManyRows
+	}
+	v.seen[digest] = struct{}{}
+
+	return v.AggregatedValue.updateWith(val)
+}
+
+type StringAggValue struct {
+	values    []string
+	separator string
+	sel       string
+}
+
+func (v *StringAggValue) Selector() string {
+	return v.sel
+}
+
+func (v *StringAggValue) ColBounded() bool {
+	return true
+}
+
+func (v *StringAggValue) Type() SQLValueType {
+	return VarcharType
+}
+
+func (v *StringAggValue) IsNull() bool {
+	return len(v.values) == 0
+}
+
+func (v *StringAggValue) result() TypedValue {
+	if len(v.values) == 0 {
+		return &NullValue{t: VarcharType}
+	}
+	return &Varchar{val: strings.Join(v.values, v.separator)}
+}
+
+func (v *StringAggValue) String() string {
+	return v.result().String()
+}
+
+func (v *StringAggValue) RawValue() interface{} {
+	return v.result().RawValue()
+}
+
+func (v *StringAggValue) Compare(val TypedValue) (int, error) {
+	return v.result().Compare(val)
+}
+
+func (v *StringAggValue) updateWith(val TypedValue) error {
+	if val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
+	if val.Type() != VarcharType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, val.Type(), VarcharType)
+	}
+
+	v.values = append(v.values, val.RawValue().(string))
+	return nil
+}
+
+// ValueExp
+
+func (v *StringAggValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return VarcharType, nil
+}
+
+func (v *StringAggValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != VarcharType {
+		return ErrNotComparableValues
+	}
+	return nil
+}
+
+func (v *StringAggValue) substitute(params map[string]interface{}) (ValueExp, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *StringAggValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *StringAggValue) selectors() []Selector {
+	return nil
+}
+
+func (v *StringAggValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
+
+func (v *StringAggValue) isConstant() bool {
+	return false
+}
+
+func (v *StringAggValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+// BoolAggValue implements both BOOL_AND and BOOL_OR aggregations
+type BoolAggValue struct {
+	aggFn AggregateFn
+	val   TypedValue
+	sel   string
+}
+
+func (v *BoolAggValue) Selector() string {
+	return v.sel
+}
+
+func (v *BoolAggValue) ColBounded() bool {
+	return true
+}
+
+func (v *BoolAggValue) Type() SQLValueType {
+	return BooleanType
+}
+
+func (v *BoolAggValue) IsNull() bool {
+	return v.val.IsNull()
+}
+
+func (v *BoolAggValue) String() string {
+	return v.val.String()
+}
+
+func (v *BoolAggValue) RawValue() interface{} {
+	return v.val.RawValue()
+}
+
+func (v *BoolAggValue) Compare(val TypedValue) (int, error) {
+	return v.val.Compare(val)
+}
+
+func (v *BoolAggValue) updateWith(val TypedValue) error {
+	if val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
+	if val.Type() != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, val.Type(), BooleanType)
+	}
+
+	if v.val.IsNull() {
+		// First non-null value
+		v.val = val
+		return nil
+	}
+
+	b := val.RawValue().(bool)
+
+	if v.aggFn == BOOL_AND {
+		v.val = &Bool{val: v.val.RawValue().(bool) && b}
+	} else {
+		v.val = &Bool{val: v.val.RawValue().(bool) || b}
+	}
+	return nil
+}
+
+// ValueExp
+
+func (v *BoolAggValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return BooleanType, nil
+}
+
+func (v *BoolAggValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return ErrNotComparableValues
+	}
+	return nil
+}
+
+func (v *BoolAggValue) substitute(params map[string]interface{}) (ValueExp, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *BoolAggValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *BoolAggValue) selectors() []Selector {
+	return nil
+}
+
+func (v *BoolAggValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
+
+func (v *BoolAggValue) isConstant() bool {
+	return false
+}
+
+func (v *BoolAggValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+// StdDevValue calculates the sample standard deviation using Welford's online algorithm
+type StdDevValue struct {
+	n    int64
+	mean float64
+	m2   float64
+	sel  string
+}
+
+func (v *StdDevValue) Selector() string {
+	return v.sel
+}
+
+func (v *StdDevValue) ColBounded() bool {
+	return true
+}
+
+func (v *StdDevValue) Type() SQLValueType {
+	return Float64Type
+}
+
+func (v *StdDevValue) IsNull() bool {
+	return v.n < 2
+}
+
+func (v *StdDevValue) result() TypedValue {
+	if v.n < 2 {
+		return &NullValue{t: Float64Type}
+	}
+	return &Float64{val: math.Sqrt(v.m2 / float64(v.n-1))}
+}
+
+func (v *StdDevValue) String() string {
+	return v.result().String()
+}
+
+func (v *StdDevValue) RawValue() interface{} {
+	return v.result().RawValue()
+}
+
+func (v *StdDevValue) Compare(val TypedValue) (int, error) {
+	return v.result().Compare(val)
+}
+
+func (v *StdDevValue) updateWith(val TypedValue) error {
+	if val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
+	x, err := float64Of(val)
+	if err != nil {
+		return err
+	}
+
+	v.n++
+
+	delta := x - v.mean
+	v.mean += delta / float64(v.n)
+	v.m2 += delta * (x - v.mean)
+
+	return nil
+}
+
+func float64Of(val TypedValue) (float64, error) {
+	switch val.Type() {
+	case IntegerType:
+		return float64(val.RawValue().(int64)), nil
+	case Float64Type:
+		return val.RawValue().(float64), nil
+	case DecimalType:
+		return val.(*Decimal).Float64(), nil
+	}
+	return 0, ErrNumericTypeExpected
+}
+
+// ValueExp
+
+func (v *StdDevValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return Float64Type, nil
+}
+
+func (v *StdDevValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != Float64Type {
+		return ErrNotComparableValues
+	}
+	return nil
+}
+
+func (v *StdDevValue) substitute(params map[string]interface{}) (ValueExp, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *StdDevValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *StdDevValue) selectors() []Selector {
+	return nil
+}
+
+func (v *StdDevValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
+
+func (v *StdDevValue) isConstant() bool {
+	return false
+}
+
+func (v *StdDevValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+// PercentileContValue calculates a continuous percentile, interpolating between adjacent values if needed.
+// Values are kept in memory, up to the specified limit.
+type PercentileContValue struct {
+	fraction float64
+	values   []float64
+	sorted   bool
+	limit    int
+	sel      string
+}
+
+func (v *PercentileContValue) Selector() string {
+	return v.sel
+}
+
+func (v *PercentileContValue) ColBounded() bool {
+	return true
+}
+
+func (v *PercentileContValue) Type() SQLValueType {
+	return Float64Type
+}
+
+func (v *PercentileContValue) IsNull() bool {
+	return len(v.values) == 0
+}
+
+func (v *PercentileContValue) result() TypedValue {
+	if len(v.values) == 0 {
+		return &NullValue{t: Float64Type}
+	}
+
+	if !v.sorted {
+		sort.Float64s(v.values)
+		v.sorted = true
+	}
+
+	pos := v.fraction * float64(len(v.values)-1)
+
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+
+	res := v.values[lower] + (pos-float64(lower))*(v.values[upper]-v.values[lower])
+
+	return &Float64{val: res}
+}
+
+func (v *PercentileContValue) String() string {
+	return v.result().String()
+}
+
+func (v *PercentileContValue) RawValue() interface{} {
+	return v.result().RawValue()
+}
+
+func (v *PercentileContValue) Compare(val TypedValue) (int, error) {
+	return v.result().Compare(val)
+}
+
+func (v *PercentileContValue) updateWith(val TypedValue) error {
+	if val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
+	x, err := float64Of(val)
+	if err != nil {
+		return err
+	}
+
+	if len(v.values) == v.limit {
+		return ErrTooManyRows
+	}
+
+	v.values = append(v.values, x)
+	v.sorted = false
+
+	return nil
+}
+
+// ValueExp
+
+func (v *PercentileContValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return Float64Type, nil
+}
+
+func (v *PercentileContValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != Float64Type {
+		return ErrNotComparableValues
+	}
+	return nil
+}
+
+func (v *PercentileContValue) substitute(params map[string]interface{}) (ValueExp, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *PercentileContValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *PercentileContValue) selectors() []Selector {
+	return nil
+}
+
+func (v *PercentileContValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
+
+func (v *PercentileContValue) isConstant() bool {
+	return false
+}
+
+func (v *PercentileContValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
@@ -53,14 +53,14 @@ func TestCountValue(t *testing.T) {
 
 	// ValueExp
 
-	sqlt, err := cval.inferType(nil, nil, "table1")
+	sqlt, err := cval.inferType(nil, nil, nil, "table1")
 	require.NoError(t, err)
 	require.Equal(t, IntegerType, sqlt)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.NoError(t, err)
 
-	err = cval.requiresType(BooleanType, nil, nil, "table1")
+	err = cval.requiresType(nil, BooleanType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrNotComparableValues)
 
 	_, err = cval.jointColumnTo(nil, "table1")
@@ -76,7 +76,7 @@ func TestCountValue(t *testing.T) {
 
 	require.False(t, cval.isConstant())
 
-	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, cval.selectorRanges(nil, nil, "", nil, nil))
 }
 
 func TestSumValue(t *testing.T) {
@@ -116,14 +116,14 @@ func TestSumValue(t *testing.T) {
 
 	// ValueExp
 
-	sqlt, err := cval.inferType(nil, nil, "table1")
+	sqlt, err := cval.inferType(nil, nil, nil, "table1")
 	require.NoError(t, err)
 	require.Equal(t, IntegerType, sqlt)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.NoError(t, err)
 
-	err = cval.requiresType(BooleanType, nil, nil, "table1")
+	err = cval.requiresType(nil, BooleanType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrNotComparableValues)
 
 	_, err = cval.jointColumnTo(nil, "table1")
@@ -139,7 +139,7 @@ func TestSumValue(t *testing.T) {
 
 	require.False(t, cval.isConstant())
 
-	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, cval.selectorRanges(nil, nil, "", nil, nil))
 }
 
 func TestMinValue(t *testing.T) {
@@ -151,10 +151,10 @@ func TestMinValue(t *testing.T) {
 	require.True(t, cval.ColBounded())
 	require.True(t, cval.IsNull())
 
-	_, err := cval.inferType(nil, nil, "table1")
+	_, err := cval.inferType(nil, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrUnexpected)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrUnexpected)
 
 	err = cval.updateWith(&Integer{val: 10})
@@ -185,14 +185,14 @@ func TestMinValue(t *testing.T) {
 
 	// ValueExp
 
-	sqlt, err := cval.inferType(nil, nil, "table1")
+	sqlt, err := cval.inferType(nil, nil, nil, "table1")
 	require.NoError(t, err)
 	require.Equal(t, IntegerType, sqlt)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.NoError(t, err)
 
-	err = cval.requiresType(BooleanType, nil, nil, "table1")
+	err = cval.requiresType(nil, BooleanType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrNotComparableValues)
 
 	_, err = cval.jointColumnTo(nil, "table1")
@@ -208,7 +208,7 @@ func TestMinValue(t *testing.T) {
 
 	require.False(t, cval.isConstant())
 
-	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, cval.selectorRanges(nil, nil, "", nil, nil))
 }
 
 func TestMaxValue(t *testing.T) {
@@ -220,10 +220,10 @@ func TestMaxValue(t *testing.T) {
 	require.True(t, cval.ColBounded())
 	require.True(t, cval.IsNull())
 
-	_, err := cval.inferType(nil, nil, "table1")
+	_, err := cval.inferType(nil, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrUnexpected)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrUnexpected)
 
 	err = cval.updateWith(&Integer{val: 10})
@@ -254,14 +254,14 @@ func TestMaxValue(t *testing.T) {
 
 	// ValueExp
 
-	sqlt, err := cval.inferType(nil, nil, "table1")
+	sqlt, err := cval.inferType(nil, nil, nil, "table1")
 	require.NoError(t, err)
 	require.Equal(t, IntegerType, sqlt)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.NoError(t, err)
 
-	err = cval.requiresType(BooleanType, nil, nil, "table1")
+	err = cval.requiresType(nil, BooleanType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrNotComparableValues)
 
 	_, err = cval.jointColumnTo(nil, "table1")
@@ -277,7 +277,7 @@ func TestMaxValue(t *testing.T) {
 
 	require.False(t, cval.isConstant())
 
-	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, cval.selectorRanges(nil, nil, "", nil, nil))
 }
 
 func TestAVGValue(t *testing.T) {
@@ -317,14 +317,14 @@ func TestAVGValue(t *testing.T) {
 
 	// ValueExp
 
-	sqlt, err := cval.inferType(nil, nil, "table1")
+	sqlt, err := cval.inferType(nil, nil, nil, "table1")
 	require.NoError(t, err)
 	require.Equal(t, IntegerType, sqlt)
 
-	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	err = cval.requiresType(nil, IntegerType, nil, nil, "table1")
 	require.NoError(t, err)
 
-	err = cval.requiresType(BooleanType, nil, nil, "table1")
+	err = cval.requiresType(nil, BooleanType, nil, nil, "table1")
 	require.ErrorIs(t, err, ErrNotComparableValues)
 
 	_, err = cval.jointColumnTo(nil, "table1")
@@ -340,5 +340,236 @@ func TestAVGValue(t *testing.T) {
 
 	require.False(t, cval.isConstant())
 
-	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, cval.selectorRanges(nil, nil, "", nil, nil))
+}
+
+func TestColBoundedCountValue(t *testing.T) {
+	cval := &CountValue{colBounded: true}
+	require.True(t, cval.ColBounded())
+
+	err := cval.updateWith(&NullValue{t: IntegerType})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), cval.RawValue())
+
+	err = cval.updateWith(&Integer{val: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), cval.RawValue())
+}
+
+func TestDistinctAggValue(t *testing.T) {
+	dval := newDistinctAggValue(&SumValue{val: &NullValue{t: AnyType}}, 2)
+
+	for _, v := range []TypedValue{&Integer{val: 1}, &NullValue{t: IntegerType}, &Integer{val: 1}, &Integer{val: 2}} {
+		err := dval.updateWith(v)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(3), dval.RawValue())
+
+	err := dval.updateWith(&Integer{val: 2})
+	require.NoError(t, err)
+
+	err = dval.updateWith(&Integer{val: 3})
+	require.ErrorIs(t, err, ErrTooManyRows)
+}
+
+func TestStringAggValue(t *testing.T) {
+	sval := &StringAggValue{separator: ", ", sel: "(table1.title)"}
+	require.Equal(t, "(table1.title)", sval.Selector())
+	require.True(t, sval.ColBounded())
+	require.True(t, sval.IsNull())
+	require.Equal(t, VarcharType, sval.Type())
+	require.Nil(t, sval.RawValue())
+
+	err := sval.updateWith(&Integer{val: 1})
+	require.ErrorIs(t, err, ErrInvalidTypes)
+
+	for _, v := range []TypedValue{&Varchar{val: "a"}, &NullValue{t: VarcharType}, &Varchar{val: "b"}} {
+		err = sval.updateWith(v)
+		require.NoError(t, err)
+	}
+	require.False(t, sval.IsNull())
+	require.Equal(t, "a, b", sval.RawValue())
+	require.Equal(t, "'a, b'", sval.String())
+
+	cmp, err := sval.Compare(&Varchar{val: "a, b"})
+	require.NoError(t, err)
+	require.Zero(t, cmp)
+
+	// ValueExp
+
+	sqlt, err := sval.inferType(nil, nil, nil, "table1")
+	require.NoError(t, err)
+	require.Equal(t, VarcharType, sqlt)
+
+	err = sval.requiresType(nil, VarcharType, nil, nil, "table1")
+	require.NoError(t, err)
+
+	err = sval.requiresType(nil, IntegerType, nil, nil, "table1")
+	require.ErrorIs(t, err, ErrNotComparableValues)
+
+	_, err = sval.substitute(nil)
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	_, err = sval.reduce(nil, nil, "table1")
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	require.Nil(t, sval.selectors())
+	require.Equal(t, sval, sval.reduceSelectors(nil, "table1"))
+	require.False(t, sval.isConstant())
+	require.Nil(t, sval.selectorRanges(nil, nil, "", nil, nil))
+}
+
+func TestBoolAggValue(t *testing.T) {
+	andVal := &BoolAggValue{aggFn: BOOL_AND, val: &NullValue{t: BooleanType}, sel: "(table1.active)"}
+	orVal := &BoolAggValue{aggFn: BOOL_OR, val: &NullValue{t: BooleanType}}
+
+	require.Equal(t, "(table1.active)", andVal.Selector())
+	require.True(t, andVal.ColBounded())
+	require.True(t, andVal.IsNull())
+	require.Equal(t, BooleanType, andVal.Type())
+
+	err := andVal.updateWith(&Integer{val: 1})
+	require.ErrorIs(t, err, ErrInvalidTypes)
+
+	for _, v := range []TypedValue{&Bool{val: true}, &NullValue{t: BooleanType}, &Bool{val: false}} {
+		err = andVal.updateWith(v)
+		require.NoError(t, err)
+
+		err = orVal.updateWith(v)
+		require.NoError(t, err)
+	}
+	require.Equal(t, false, andVal.RawValue())
+	require.Equal(t, true, orVal.RawValue())
+	require.Equal(t, "true", orVal.String())
+
+	cmp, err := orVal.Compare(&Bool{val: true})
+	require.NoError(t, err)
+	require.Zero(t, cmp)
+
+	// ValueExp
+
+	sqlt, err := andVal.inferType(nil, nil, nil, "table1")
+	require.NoError(t, err)
+	require.Equal(t, BooleanType, sqlt)
+
+	err = andVal.requiresType(nil, BooleanType, nil, nil, "table1")
+	require.NoError(t, err)
+
+	err = andVal.requiresType(nil, IntegerType, nil, nil, "table1")
+	require.ErrorIs(t, err, ErrNotComparableValues)
+
+	_, err = andVal.substitute(nil)
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	_, err = andVal.reduce(nil, nil, "table1")
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	require.Nil(t, andVal.selectors())
+	require.Equal(t, andVal, andVal.reduceSelectors(nil, "table1"))
+	require.False(t, andVal.isConstant())
+	require.Nil(t, andVal.selectorRanges(nil, nil, "", nil, nil))
+}
+
+func TestStdDevValue(t *testing.T) {
+	sval := &StdDevValue{sel: "(table1.amount)"}
+	require.Equal(t, "(table1.amount)", sval.Selector())
+	require.True(t, sval.ColBounded())
+	require.Equal(t, Float64Type, sval.Type())
+
+	err := sval.updateWith(&Varchar{val: "a"})
+	require.ErrorIs(t, err, ErrNumericTypeExpected)
+
+	err = sval.updateWith(&Integer{val: 2})
+	require.NoError(t, err)
+	require.True(t, sval.IsNull())
+
+	for _, v := range []TypedValue{&Integer{val: 4}, &NullValue{t: IntegerType}, &Float64{val: 4}, &Integer{val: 4}, &Integer{val: 5}, &Integer{val: 5}, &Integer{val: 7}, &Integer{val: 9}} {
+		err = sval.updateWith(v)
+		require.NoError(t, err)
+	}
+	require.False(t, sval.IsNull())
+	require.InDelta(t, 2.138089935, sval.RawValue(), 1e-9)
+
+	cmp, err := sval.Compare(&Float64{val: 2})
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	// ValueExp
+
+	sqlt, err := sval.inferType(nil, nil, nil, "table1")
+	require.NoError(t, err)
+	require.Equal(t, Float64Type, sqlt)
+
+	err = sval.requiresType(nil, Float64Type, nil, nil, "table1")
+	require.NoError(t, err)
+
+	err = sval.requiresType(nil, IntegerType, nil, nil, "table1")
+	require.ErrorIs(t, err, ErrNotComparableValues)
+
+	_, err = sval.substitute(nil)
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	_, err = sval.reduce(nil, nil, "table1")
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	require.Nil(t, sval.selectors())
+	require.Equal(t, sval, sval.reduceSelectors(nil, "table1"))
+	require.False(t, sval.isConstant())
+	require.Nil(t, sval.selectorRanges(nil, nil, "", nil, nil))
+}
+
+func TestPercentileContValue(t *testing.T) {
+	pval := &PercentileContValue{fraction: 0.5, limit: 4, sel: "(table1.amount)"}
+	require.Equal(t, "(table1.amount)", pval.Selector())
+	require.True(t, pval.ColBounded())
+	require.True(t, pval.IsNull())
+	require.Equal(t, Float64Type, pval.Type())
+
+	err := pval.updateWith(&Varchar{val: "a"})
+	require.ErrorIs(t, err, ErrNumericTypeExpected)
+
+	for _, v := range []TypedValue{&Integer{val: 10}, &NullValue{t: IntegerType}, &Integer{val: 1}, &Float64{val: 3}} {
+		err = pval.updateWith(v)
+		require.NoError(t, err)
+	}
+	require.False(t, pval.IsNull())
+	require.Equal(t, float64(3), pval.RawValue())
+
+	err = pval.updateWith(&Integer{val: 4})
+	require.NoError(t, err)
+	require.Equal(t, float64(3.5), pval.RawValue())
+	require.Equal(t, "3.5", pval.String())
+
+	err = pval.updateWith(&Integer{val: 5})
+	require.ErrorIs(t, err, ErrTooManyRows)
+
+	pval.fraction = 0.9
+	require.InDelta(t, 8.2, pval.RawValue(), 1e-9)
+
+	cmp, err := pval.Compare(&Float64{val: 10})
+	require.NoError(t, err)
+	require.Equal(t, -1, cmp)
+
+	// ValueExp
+
+	sqlt, err := pval.inferType(nil, nil, nil, "table1")
+	require.NoError(t, err)
+	require.Equal(t, Float64Type, sqlt)
+
+	err = pval.requiresType(nil, Float64Type, nil, nil, "table1")
+	require.NoError(t, err)
+
+	err = pval.requiresType(nil, IntegerType, nil, nil, "table1")
+	require.ErrorIs(t, err, ErrNotComparableValues)
+
+	_, err = pval.substitute(nil)
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	_, err = pval.reduce(nil, nil, "table1")
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	require.Nil(t, pval.selectors())
+	require.Equal(t, pval, pval.reduceSelectors(nil, "table1"))
+	require.False(t, pval.isConstant())
+	require.Nil(t, pval.selectorRanges(nil, nil, "", nil, nil))
 }
@@ -0,0 +1,943 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const arrayTypeSuffix = "[]"
+
+type ArrayOperator = int
+
+const (
+	CONTAINSOP ArrayOperator = iota
+	OVERLAPSOP
+)
+
+func ArrayOperatorString(op ArrayOperator) string {
+	switch op {
+	case CONTAINSOP:
+		return "@>"
+	case OVERLAPSOP:
+		return "&&"
+	}
+	return ""
+}
+
+// ArrayType returns the type of the arrays holding values of the given type e.g. INTEGER[]
+func ArrayType(elemType SQLValueType) SQLValueType {
+	return elemType + arrayTypeSuffix
+}
+
+func IsArrayType(t SQLValueType) bool {
+	return strings.HasSuffix(t, arrayTypeSuffix)
+}
+
+// ArrayElemType returns the type of the elements of an array type
+func ArrayElemType(t SQLValueType) SQLValueType {
+	return strings.TrimSuffix(t, arrayTypeSuffix)
+}
+
+// validArrayElemType returns true if arrays can hold values of the given type,
+// arrays are one-dimensional and JSON values have arrays of their own
+func validArrayElemType(t SQLValueType) bool {
+	switch t {
+	case IntegerType,
+		Float64Type,
+		DecimalType,
+		BooleanType,
+		VarcharType,
+		UUIDType,
+		TimestampType:
+		return true
+	}
+	return false
+}
+
+type Array struct {
+	elemType SQLValueType
+	vals     []TypedValue
+}
+
+func NewArray(elemType SQLValueType, vals []TypedValue) *Array {
+	return &Array{elemType: elemType, vals: vals}
+}
+
+func (v *Array) Type() SQLValueType {
+	return ArrayType(v.elemType)
+}
+
+func (v *Array) ElemType() SQLValueType {
+	return v.elemType
+}
+
+func (v *Array) Values() []TypedValue {
+	return v.vals
+}
+
+func (v *Array) IsNull() bool {
+	return false
+}
+
+func (v *Array) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return v.Type(), nil
+}
+
+func (v *Array) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	// the type of an empty array is determined by where it's used
+	if t != v.Type() && (v.elemType != AnyType || !IsArrayType(t)) {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, v.Type(), t)
+	}
+	return nil
+}
+
+func (v *Array) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
+
+func (v *Array) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
+}
+
+func (v *Array) selectors() []Selector {
+	return nil
+}
+
+func (v *Array) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
+
+func (v *Array) isConstant() bool {
+	return true
+}
+
+func (v *Array) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (v *Array) RawValue() interface{} {
+	raw := make([]interface{}, len(v.vals))
+	for i, e := range v.vals {
+		raw[i] = e.RawValue()
+	}
+	return raw
+}
+
+// Compare compares arrays element by element, an array being a prefix of another one is the smallest
+func (v *Array) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
+	}
+
+	other, err := v.sameTypeArray(val)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < len(v.vals) && i < len(other.vals); i++ {
+		res, err := v.vals[i].Compare(other.vals[i])
+		if err != nil || res != 0 {
+			return res, err
+		}
+	}
+
+	switch {
+	case len(v.vals) < len(other.vals):
+		return -1, nil
+	case len(v.vals) > len(other.vals):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// sameTypeArray returns the given value as an array holding elements of the same type of v
+func (v *Array) sameTypeArray(val TypedValue) (*Array, error) {
+	if !IsArrayType(val.Type()) && val.Type() != VarcharType {
+		return nil, ErrNotComparableValues
+	}
+
+	if val.Type() == v.Type() || v.elemType == AnyType {
+		if arr, ok := val.(*Array); ok {
+			return arr, nil
+		}
+	}
+
+	conv, err := getConverter(val.Type(), v.Type())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotComparableValues, err.Error())
+	}
+
+	cval, err := conv(val)
+	if err != nil {
+		return nil, err
+	}
+	return cval.(*Array), nil
+}
+
+// contains returns true if the array holds a value equal to the given one
+func (v *Array) contains(val TypedValue) (bool, error) {
+	for _, e := range v.vals {
+		res, err := e.Compare(val)
+		if err != nil {
+			return false, err
+		}
+		if res == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (v *Array) String() string {
+	elems := make([]string, len(v.vals))
+	for i, e := range v.vals {
+		elems[i] = e.String()
+	}
+
+	s := "ARRAY[" + strings.Join(elems, ", ") + "]"
+	if len(v.vals) == 0 && v.elemType != AnyType {
+		return fmt.Sprintf("CAST (%s AS %s)", s, v.Type())
+	}
+	return s
+}
+
+// Text returns the text representation of the array e.g. {1,2,3},
+// which is also the format accepted when casting strings as arrays
+func (v *Array) Text() string {
+	var b strings.Builder
+
+	b.WriteByte('{')
+	for i, e := range v.vals {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		s := e.String()
+
+		switch e.Type() {
+		case VarcharType:
+			s, _ = e.RawValue().(string)
+		case BooleanType:
+			s = s[:1]
+		}
+		b.WriteString(quoteArrayElem(s))
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+func quoteArrayElem(s string) string {
+	if s != "" && !strings.EqualFold(s, "NULL") && !strings.ContainsAny(s, "{},\"\\ \t\n\r") {
+		return s
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// parseArrayText splits the text representation of a one-dimensional array into its elements
+func parseArrayText(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("%w: malformed array literal '%s'", ErrInvalidValue, s)
+	}
+
+	body := s[1 : len(s)-1]
+	if strings.TrimSpace(body) == "" {
+		return []string{}, nil
+	}
+
+	var elems []string
+
+	for i := 0; i <= len(body); {
+		for i < len(body) && isSpace(body[i]) {
+			i++
+		}
+
+		var elem strings.Builder
+		quoted := i < len(body) && body[i] == '"'
+
+		if quoted {
+			i++
+
+			closed := false
+			for ; i < len(body); i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				} else if body[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				elem.WriteByte(body[i])
+			}
+
+			for i < len(body) && isSpace(body[i]) {
+				i++
+			}
+
+			if !closed || (i < len(body) && body[i] != ',') {
+				return nil, fmt.Errorf("%w: malformed array literal '%s'", ErrInvalidValue, s)
+			}
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				if body[i] == '{' || body[i] == '}' || body[i] == '"' {
+					return nil, fmt.Errorf("%w: malformed array literal '%s'", ErrInvalidValue, s)
+				}
+				elem.WriteByte(body[i])
+			}
+
+			e := strings.TrimSpace(elem.String())
+			if e == "" {
+				return nil, fmt.Errorf("%w: malformed array literal '%s'", ErrInvalidValue, s)
+			}
+			if strings.EqualFold(e, "NULL") {
+				return nil, ErrArrayNullElement
+			}
+
+			elem.Reset()
+			elem.WriteString(e)
+		}
+
+		elems = append(elems, elem.String())
+
+		// skip the separator
+		i++
+	}
+
+	return elems, nil
+}
+
+// arrayElemFromText converts the text of an array element into a value of the given type
+func arrayElemFromText(s string, elemType SQLValueType) (TypedValue, error) {
+	switch elemType {
+	case VarcharType:
+		return &Varchar{val: s}, nil
+	case BooleanType:
+		switch strings.ToLower(s) {
+		case "t", "true":
+			return &Bool{val: true}, nil
+		case "f", "false":
+			return &Bool{val: false}, nil
+		}
+		return nil, fmt.Errorf("%w: can not cast string '%s' as a %s", ErrUnsupportedCast, s, BooleanType)
+	}
+
+	conv, err := getConverter(VarcharType, elemType)
+	if err != nil {
+		return nil, err
+	}
+	return conv(&Varchar{val: s})
+}
+
+// newArrayFromText builds an array of the given type from its text representation
+func newArrayFromText(s string, elemType SQLValueType) (*Array, error) {
+	elems, err := parseArrayText(s)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]TypedValue, len(elems))
+	for i, e := range elems {
+		vals[i], err = arrayElemFromText(e, elemType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewArray(elemType, vals), nil
+}
+
+// convertArray converts the elements of the array into values of the given type
+func convertArray(arr *Array, elemType SQLValueType) (*Array, error) {
+	if arr.elemType == elemType {
+		return arr, nil
+	}
+
+	vals := make([]TypedValue, len(arr.vals))
+
+	for i, e := range arr.vals {
+		conv, err := getConverter(e.Type(), elemType)
+		if err != nil {
+			return nil, err
+		}
+
+		vals[i], err = conv(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewArray(elemType, vals), nil
+}
+
+// arrayElems returns the elements of a slice, byte slices are not considered arrays but blobs
+func arrayElems(val interface{}) ([]interface{}, bool) {
+	if elems, ok := val.([]interface{}); ok {
+		return elems, true
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	elems := make([]interface{}, rv.Len())
+	for i := range elems {
+		elems[i] = rv.Index(i).Interface()
+	}
+	return elems, true
+}
+
+// arrayFromSlice builds an array from a slice provided as the value of a parameter,
+// elements are interpreted the same way values of parameters are
+func arrayFromSlice(paramID string, elems []interface{}) (TypedValue, error) {
+	params := make(map[string]interface{}, len(elems))
+	exp := &ArrayExp{elems: make([]ValueExp, len(elems))}
+
+	for i, e := range elems {
+		id := fmt.Sprintf("%s[%d]", paramID, i)
+
+		params[id] = e
+		exp.elems[i] = &Param{id: id}
+	}
+
+	sexp, err := exp.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+	return sexp.reduce(nil, nil, "")
+}
+
+// arrayImplicitConversion converts raw values, either slices or the text representation of an array,
+// into the raw elements of an array of the given type
+func arrayImplicitConversion(val interface{}, elemType SQLValueType) (interface{}, error) {
+	if s, ok := val.(string); ok {
+		arr, err := newArrayFromText(s, elemType)
+		if err != nil {
+			return nil, err
+		}
+		return arr.RawValue(), nil
+	}
+
+	elems, ok := arrayElems(val)
+	if !ok {
+		return val, nil
+	}
+
+	raw := make([]interface{}, len(elems))
+
+	for i, e := range elems {
+		if e == nil {
+			return nil, ErrArrayNullElement
+		}
+
+		if elemType == VarcharType {
+			raw[i] = e
+			continue
+		}
+
+		conv, err := mayApplyImplicitConversion(e, elemType)
+		if err != nil {
+			return nil, err
+		}
+		raw[i] = conv
+	}
+	return raw, nil
+}
+
+// encodeArray encodes the raw elements of an array as len(v) + number of elements + encoded elements
+func encodeArray(val interface{}, elemType SQLValueType, maxLen int) ([]byte, error) {
+	elems, ok := val.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("value is not an array: %w", ErrInvalidValue)
+	}
+
+	encv := make([]byte, EncLenLen+EncLenLen)
+	binary.BigEndian.PutUint32(encv[EncLenLen:], uint32(len(elems)))
+
+	for _, e := range elems {
+		enc, err := EncodeRawValue(e, elemType, maxLen, false)
+		if err != nil {
+			return nil, err
+		}
+		encv = append(encv, enc...)
+	}
+
+	binary.BigEndian.PutUint32(encv, uint32(len(encv)-EncLenLen))
+
+	return encv, nil
+}
+
+func decodeArray(b []byte, elemType SQLValueType) (*Array, error) {
+	if len(b) < EncLenLen {
+		return nil, ErrCorruptedData
+	}
+
+	n := int(binary.BigEndian.Uint32(b))
+	off := EncLenLen
+
+	var vals []TypedValue
+
+	for i := 0; i < n; i++ {
+		v, m, err := DecodeValue(b[off:], elemType)
+		if err != nil {
+			return nil, err
+		}
+
+		vals = append(vals, v)
+		off += m
+	}
+
+	if off != len(b) {
+		return nil, ErrCorruptedData
+	}
+	return NewArray(elemType, vals), nil
+}
+
+// fitArray converts the value into an array of the given type as it would be stored,
+// so the elements are checked against the max length and decimals are fitted to it
+func fitArray(val TypedValue, t SQLValueType, maxLen int) (TypedValue, error) {
+	enc, err := EncodeValue(val, t, maxLen)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, _, err := DecodeValue(enc, t)
+	if err != nil {
+		return nil, err
+	}
+	return arr, nil
+}
+
+// ArrayExp builds an array from the values of its elements e.g. ARRAY[1, 2, 3]
+type ArrayExp struct {
+	elems []ValueExp
+}
+
+func (bexp *ArrayExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	elemType := AnyType
+
+	for _, e := range bexp.elems {
+		t, err := e.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+
+		ct, ok := coerceTypes(elemType, t)
+		if !ok {
+			return AnyType, fmt.Errorf("%w: array elements of types %s and %s", ErrInvalidTypes, elemType, t)
+		}
+		elemType = ct
+	}
+
+	if elemType != AnyType && !validArrayElemType(elemType) {
+		return AnyType, fmt.Errorf("%w: arrays of type %s are not supported", ErrInvalidTypes, elemType)
+	}
+	return ArrayType(elemType), nil
+}
+
+func (bexp *ArrayExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if !IsArrayType(t) {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, ArrayType(AnyType), t)
+	}
+
+	for _, e := range bexp.elems {
+		err := e.requiresType(tx, ArrayElemType(t), cols, params, implicitTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bexp *ArrayExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	elems := make([]ValueExp, len(bexp.elems))
+
+	for i, e := range bexp.elems {
+		se, err := e.substitute(params)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = se
+	}
+	return &ArrayExp{elems: elems}, nil
+}
+
+func (bexp *ArrayExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vals := make([]TypedValue, len(bexp.elems))
+
+	elemType := AnyType
+
+	for i, e := range bexp.elems {
+		v, err := e.reduce(tx, row, implicitTable)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.IsNull() {
+			return nil, ErrArrayNullElement
+		}
+
+		ct, ok := coerceTypes(elemType, v.Type())
+		if !ok {
+			return nil, fmt.Errorf("%w: array elements of types %s and %s", ErrInvalidTypes, elemType, v.Type())
+		}
+		elemType = ct
+
+		vals[i] = v
+	}
+
+	if elemType != AnyType && !validArrayElemType(elemType) {
+		return nil, fmt.Errorf("%w: arrays of type %s are not supported", ErrInvalidTypes, elemType)
+	}
+
+	return convertArray(NewArray(AnyType, vals), elemType)
+}
+
+func (bexp *ArrayExp) selectors() []Selector {
+	var sels []Selector
+	for _, e := range bexp.elems {
+		sels = append(sels, e.selectors()...)
+	}
+	return sels
+}
+
+func (bexp *ArrayExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	elems := make([]ValueExp, len(bexp.elems))
+	for i, e := range bexp.elems {
+		elems[i] = e.reduceSelectors(row, implicitTable)
+	}
+	return &ArrayExp{elems: elems}
+}
+
+func (bexp *ArrayExp) isConstant() bool {
+	for _, e := range bexp.elems {
+		if !e.isConstant() {
+			return false
+		}
+	}
+	return true
+}
+
+func (bexp *ArrayExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *ArrayExp) String() string {
+	elems := make([]string, len(bexp.elems))
+	for i, e := range bexp.elems {
+		elems[i] = e.String()
+	}
+	return "ARRAY[" + strings.Join(elems, ", ") + "]"
+}
+
+// AnyCmpExp is satisfied when the comparison holds for any of the elements of an array e.g. tag = ANY(tags)
+type AnyCmpExp struct {
+	op    CmpOperator
+	val   ValueExp
+	array ValueExp
+}
+
+func (bexp *AnyCmpExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	tval, err := bexp.val.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	tarr, err := bexp.array.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	if tarr == AnyType {
+		if tval != AnyType {
+			err = bexp.array.requiresType(tx, ArrayType(tval), cols, params, implicitTable)
+		}
+		return BooleanType, err
+	}
+
+	if !IsArrayType(tarr) {
+		return AnyType, fmt.Errorf("%w: ANY expects an array but %s was provided", ErrInvalidTypes, tarr)
+	}
+
+	_, ok := coerceTypes(tval, ArrayElemType(tarr))
+	if !ok {
+		return AnyType, fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, tval, ArrayElemType(tarr))
+	}
+
+	if tval == AnyType {
+		err = bexp.val.requiresType(tx, ArrayElemType(tarr), cols, params, implicitTable)
+	}
+	return BooleanType, err
+}
+
+func (bexp *AnyCmpExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	}
+
+	_, err := bexp.inferType(tx, cols, params, implicitTable)
+	return err
+}
+
+func (bexp *AnyCmpExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	val, err := bexp.val.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, err := bexp.array.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnyCmpExp{op: bexp.op, val: val, array: arr}, nil
+}
+
+func (bexp *AnyCmpExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	val, err := bexp.val.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, err := reduceArray(tx, row, implicitTable, bexp.array)
+	if err != nil {
+		return nil, err
+	}
+
+	// as for any other comparison, NULL operands do not satisfy the condition
+	if val.IsNull() || arr == nil {
+		return &Bool{val: false}, nil
+	}
+
+	for _, e := range arr.vals {
+		res, err := val.Compare(e)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmpSatisfiesOp(res, bexp.op) {
+			return &Bool{val: true}, nil
+		}
+	}
+	return &Bool{val: false}, nil
+}
+
+func (bexp *AnyCmpExp) selectors() []Selector {
+	return append(bexp.val.selectors(), bexp.array.selectors()...)
+}
+
+func (bexp *AnyCmpExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &AnyCmpExp{
+		op:    bexp.op,
+		val:   bexp.val.reduceSelectors(row, implicitTable),
+		array: bexp.array.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *AnyCmpExp) isConstant() bool {
+	return bexp.val.isConstant() && bexp.array.isConstant()
+}
+
+func (bexp *AnyCmpExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *AnyCmpExp) String() string {
+	return fmt.Sprintf("(%s %s ANY(%s))", bexp.val.String(), CmpOperatorToString(bexp.op), bexp.array.String())
+}
+
+// ArrayOpExp evaluates the containment (@>) and overlap (&&) of two arrays
+type ArrayOpExp struct {
+	op          ArrayOperator
+	left, right ValueExp
+}
+
+func (bexp *ArrayOpExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	tleft, err := bexp.left.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	tright, err := bexp.right.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	for _, t := range []SQLValueType{tleft, tright} {
+		if t != AnyType && !IsArrayType(t) {
+			return AnyType, fmt.Errorf("%w: %s expects arrays but %s was provided", ErrInvalidTypes, ArrayOperatorString(bexp.op), t)
+		}
+	}
+
+	if tleft == AnyType && tright != AnyType {
+		err = bexp.left.requiresType(tx, tright, cols, params, implicitTable)
+	}
+
+	if tright == AnyType && tleft != AnyType {
+		err = bexp.right.requiresType(tx, tleft, cols, params, implicitTable)
+	}
+	return BooleanType, err
+}
+
+func (bexp *ArrayOpExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	}
+
+	_, err := bexp.inferType(tx, cols, params, implicitTable)
+	return err
+}
+
+func (bexp *ArrayOpExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	left, err := bexp.left.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := bexp.right.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArrayOpExp{op: bexp.op, left: left, right: right}, nil
+}
+
+func (bexp *ArrayOpExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	lval, err := bexp.left.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	rval, err := bexp.right.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if lval.IsNull() || rval.IsNull() {
+		return &Bool{val: false}, nil
+	}
+
+	left, isArray := lval.(*Array)
+	if !isArray {
+		// the text representation of an array takes the type of the other operand e.g. tags @> '{a,b}'
+		right, ok := rval.(*Array)
+		if !ok || lval.Type() != VarcharType || !bexp.left.isConstant() {
+			return nil, fmt.Errorf("%w: expected an array but %s was provided", ErrInvalidTypes, lval.Type())
+		}
+
+		left, err = right.sameTypeArray(lval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !IsArrayType(rval.Type()) && (rval.Type() != VarcharType || !bexp.right.isConstant()) {
+		return nil, fmt.Errorf("%w: expected an array but %s was provided", ErrInvalidTypes, rval.Type())
+	}
+
+	right, err := left.sameTypeArray(rval)
+	if err != nil {
+		return nil, err
+	}
+
+	if left.elemType == AnyType {
+		left, right = right, left
+		if bexp.op == CONTAINSOP {
+			// only the empty array is contained by the empty array
+			return &Bool{val: len(left.vals) == 0}, nil
+		}
+	}
+
+	right, err = left.sameTypeArray(right)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range right.vals {
+		found, err := left.contains(e)
+		if err != nil {
+			return nil, err
+		}
+
+		if bexp.op == OVERLAPSOP && found {
+			return &Bool{val: true}, nil
+		}
+
+		if bexp.op == CONTAINSOP && !found {
+			return &Bool{val: false}, nil
+		}
+	}
+	return &Bool{val: bexp.op == CONTAINSOP}, nil
+}
+
+func (bexp *ArrayOpExp) selectors() []Selector {
+	return append(bexp.left.selectors(), bexp.right.selectors()...)
+}
+
+func (bexp *ArrayOpExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &ArrayOpExp{
+		op:    bexp.op,
+		left:  bexp.left.reduceSelectors(row, implicitTable),
+		right: bexp.right.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *ArrayOpExp) isConstant() bool {
+	return bexp.left.isConstant() && bexp.right.isConstant()
+}
+
+func (bexp *ArrayOpExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *ArrayOpExp) String() string {
+	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), ArrayOperatorString(bexp.op), bexp.right.String())
+}
+
+// reduceArray reduces the expression into an array, nil is returned when its value is NULL
+func reduceArray(tx *SQLTx, row *Row, implicitTable string, exp ValueExp) (*Array, error) {
+	val, err := exp.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if val.IsNull() {
+		return nil, nil
+	}
+
+	arr, ok := val.(*Array)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an array but %s was provided", ErrInvalidTypes, val.Type())
+	}
+	return arr, nil
+}
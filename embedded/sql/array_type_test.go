@@ -0,0 +1,110 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArrayText(t *testing.T) {
+	for _, d := range []struct {
+		s   string
+		exp []string
+	}{
+		{"{}", []string{}},
+		{" { } ", []string{}},
+		{"{1,2,3}", []string{"1", "2", "3"}},
+		{"{ a , b }", []string{"a", "b"}},
+		{`{"a b","c,d",""}`, []string{"a b", "c,d", ""}},
+		{`{"say \"hi\"","back\\slash"}`, []string{`say "hi"`, `back\slash`}},
+		{`{"NULL"}`, []string{"NULL"}},
+	} {
+		t.Run(d.s, func(t *testing.T) {
+			elems, err := parseArrayText(d.s)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, elems)
+		})
+	}
+
+	for _, s := range []string{"", "1,2", "{1,2", "{1,,2}", "{1,2,}", `{"a}`, "{{1},{2}}", "{a}b"} {
+		t.Run(fmt.Sprintf("invalid %q", s), func(t *testing.T) {
+			_, err := parseArrayText(s)
+			require.ErrorIs(t, err, ErrInvalidValue)
+		})
+	}
+
+	_, err := parseArrayText("{1,NULL}")
+	require.ErrorIs(t, err, ErrArrayNullElement)
+}
+
+func TestArrayText(t *testing.T) {
+	for _, d := range []struct {
+		s        string
+		elemType SQLValueType
+		exp      string
+	}{
+		{"{}", IntegerType, "{}"},
+		{"{3, 1, 2}", IntegerType, "{3,1,2}"},
+		{`{a,"b c","",NULL_,"d\"e"}`, VarcharType, `{a,"b c","",NULL_,"d\"e"}`},
+		{`{"null"}`, VarcharType, `{"null"}`},
+		{"{t,FALSE}", BooleanType, "{t,f}"},
+		{"{1.5,2}", DecimalType, "{1.5,2}"},
+	} {
+		t.Run(d.s, func(t *testing.T) {
+			arr, err := newArrayFromText(d.s, d.elemType)
+			require.NoError(t, err)
+			require.Equal(t, ArrayType(d.elemType), arr.Type())
+			require.Equal(t, d.exp, arr.Text())
+
+			parsed, err := newArrayFromText(arr.Text(), d.elemType)
+			require.NoError(t, err)
+			require.Equal(t, arr, parsed)
+		})
+	}
+}
+
+func TestArrayValueEncoding(t *testing.T) {
+	enc, err := EncodeRawValue([]interface{}{"a", "bc"}, ArrayType(VarcharType), 2, false)
+	require.NoError(t, err)
+
+	v, n, err := DecodeValue(enc, ArrayType(VarcharType))
+	require.NoError(t, err)
+	require.Equal(t, len(enc), n)
+	require.Equal(t, []interface{}{"a", "bc"}, v.RawValue())
+
+	enc, err = EncodeRawValue("{1,2}", ArrayType(IntegerType), 0, false)
+	require.NoError(t, err)
+
+	v, _, err = DecodeValue(enc, ArrayType(IntegerType))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int64(1), int64(2)}, v.RawValue())
+
+	_, err = EncodeRawValue([]interface{}{"abc"}, ArrayType(VarcharType), 2, false)
+	require.ErrorIs(t, err, ErrMaxLengthExceeded)
+
+	_, err = EncodeRawValue([]interface{}{int64(1), nil}, ArrayType(IntegerType), 0, false)
+	require.ErrorIs(t, err, ErrArrayNullElement)
+
+	_, err = EncodeRawValue(int64(1), ArrayType(IntegerType), 0, false)
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	_, _, err = DecodeValue(enc[:len(enc)-1], ArrayType(IntegerType))
+	require.Error(t, err)
+}
@@ -40,6 +40,38 @@ type Catalog struct {
 	tablesByName map[string]*Table
 
 	maxTableID uint32 // The maxTableID variable is used to assign unique ids to new tables as they are created.
+
+	views       []*View
+	viewsByName map[string]*View
+
+	maxViewID uint32
+
+	sequences       []*Sequence
+	sequencesByName map[string]*Sequence
+
+	maxSequenceID uint32
+}
+
+// View is a named query stored in the catalog, which can be referenced as a data source
+type View struct {
+	id    uint32
+	name  string
+	cols  []string // optional names given to the columns of the query
+	query string   // text of the query the view was defined with
+	ds    DataSource
+	deps  *viewDependencies
+}
+
+// Sequence is a named generator of integer values stored in the catalog.
+// Its last generated value is persisted separately so it can be advanced within regular transactions.
+type Sequence struct {
+	id        uint32
+	name      string
+	start     int64
+	increment int64
+	minValue  int64
+	maxValue  int64
+	cycle     bool
 }
 
 type Constraint interface{}
@@ -52,6 +84,25 @@ type CheckConstraint struct {
 	exp  ValueExp
 }
 
+type ForeignKeyConstraint struct {
+	name     string
+	cols     []string
+	refTable string
+	refCols  []string // when empty, the primary key of the referenced table is referenced
+}
+
+// ForeignKey requires the values of a set of columns to match those of a row in the referenced table.
+// Rows holding NULL in any of the referencing columns are not checked.
+type ForeignKey struct {
+	id       uint32
+	name     string
+	table    *Table
+	cols     []*Column
+	refTable *Table
+	refCols  []*Column
+	refIndex *Index // unique index of the referenced table on the referenced columns
+}
+
 type Table struct {
 	catalog          *Catalog
 	id               uint32
@@ -63,6 +114,8 @@ type Table struct {
 	indexesByName    map[string]*Index
 	indexesByColID   map[uint32][]*Index
 	checkConstraints map[string]CheckConstraint
+	foreignKeys      []*ForeignKey
+	jsonPathCols     map[string]*Column // virtual columns holding the values at indexed JSON paths
 	primaryIndex     *Index
 	autoIncrementPK  bool
 	maxPK            int64
@@ -87,6 +140,22 @@ type Column struct {
 	maxLen        int
 	autoIncrement bool
 	notNull       bool
+
+	defaultExp   ValueExp // value assigned when none is specified
+	generatedExp ValueExp // expression the value of the column is computed from
+
+	typeChanges []colTypeChange // previous types of the column, sorted by untilTx
+
+	// set when the column is a virtual column holding the values found at a path of a JSON column
+	jsonCol    *Column
+	jsonFields []string
+}
+
+// colTypeChange records the type a column had before being altered, values written
+// before the transaction in which the change was committed are encoded with it
+type colTypeChange struct {
+	untilTx uint64
+	colType SQLValueType
 }
 
 func newCatalog(enginePrefix []byte) *Catalog {
@@ -94,6 +163,9 @@ func newCatalog(enginePrefix []byte) *Catalog {
 		enginePrefix: enginePrefix,
 		tablesByID:   make(map[uint32]*Table),
 		tablesByName: make(map[string]*Table),
+		viewsByName:  make(map[string]*View),
+
+		sequencesByName: make(map[string]*Sequence),
 	}
 
 	pgTypeTable := &Table{
@@ -163,6 +235,130 @@ func (catlg *Catalog) GetTableByName(name string) (*Table, error) {
 	return table, nil
 }
 
+func (catlg *Catalog) ExistView(view string) bool {
+	_, exists := catlg.viewsByName[view]
+	return exists
+}
+
+func (catlg *Catalog) GetViews() []*View {
+	vs := make([]*View, 0, len(catlg.views))
+
+	vs = append(vs, catlg.views...)
+
+	return vs
+}
+
+func (catlg *Catalog) GetViewByName(name string) (*View, error) {
+	view, exists := catlg.viewsByName[name]
+	if !exists {
+		return nil, fmt.Errorf("%w (%s)", ErrViewDoesNotExist, name)
+	}
+	return view, nil
+}
+
+func (v *View) ID() uint32 {
+	return v.id
+}
+
+func (v *View) Name() string {
+	return v.name
+}
+
+func (v *View) Cols() []string {
+	return v.cols
+}
+
+func (v *View) Query() string {
+	return v.query
+}
+
+// dependentView returns the first view whose query references the given table or view
+func (catlg *Catalog) dependentView(name string) *View {
+	for _, v := range catlg.views {
+		if v.deps.references(name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// dependentViewOnColumn returns the first view whose query may reference the given column of the table
+func (catlg *Catalog) dependentViewOnColumn(table, col string) *View {
+	for _, v := range catlg.views {
+		if v.deps.referencesColumn(table, col) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (catlg *Catalog) ExistSequence(sequence string) bool {
+	_, exists := catlg.sequencesByName[sequence]
+	return exists
+}
+
+func (catlg *Catalog) GetSequences() []*Sequence {
+	ss := make([]*Sequence, 0, len(catlg.sequences))
+
+	ss = append(ss, catlg.sequences...)
+
+	return ss
+}
+
+func (catlg *Catalog) GetSequenceByName(name string) (*Sequence, error) {
+	seq, exists := catlg.sequencesByName[name]
+	if !exists {
+		return nil, fmt.Errorf("%w (%s)", ErrSequenceDoesNotExist, name)
+	}
+	return seq, nil
+}
+
+func (s *Sequence) ID() uint32 {
+	return s.id
+}
+
+func (s *Sequence) Name() string {
+	return s.name
+}
+
+func (s *Sequence) Start() int64 {
+	return s.start
+}
+
+func (s *Sequence) Increment() int64 {
+	return s.increment
+}
+
+func (s *Sequence) MinValue() int64 {
+	return s.minValue
+}
+
+func (s *Sequence) MaxValue() int64 {
+	return s.maxValue
+}
+
+func (s *Sequence) Cycle() bool {
+	return s.cycle
+}
+
+// next returns the value following last, wrapping around when the sequence cycles.
+func (s *Sequence) next(last int64) (int64, error) {
+	if s.increment > 0 && last > s.maxValue-s.increment {
+		if !s.cycle {
+			return 0, fmt.Errorf("%w: sequence %s reached its maximum value (%d)", ErrSequenceLimitReached, s.name, s.maxValue)
+		}
+		return s.minValue, nil
+	}
+
+	if s.increment < 0 && last < s.minValue-s.increment {
+		if !s.cycle {
+			return 0, fmt.Errorf("%w: sequence %s reached its minimum value (%d)", ErrSequenceLimitReached, s.name, s.minValue)
+		}
+		return s.maxValue, nil
+	}
+	return last + s.increment, nil
+}
+
 func (catlg *Catalog) GetTableByID(id uint32) (*Table, error) {
 	table, exists := catlg.tablesByID[id]
 	if !exists {
@@ -217,6 +413,105 @@ func (t *Table) GetColumnByName(name string) (*Column, error) {
 	return col, nil
 }
 
+// indexColumn returns the column to be indexed, which is a virtual column
+// holding the values found at the given path when JSON fields are specified
+func (t *Table) indexColumn(colName string, jsonFields []string) (*Column, error) {
+	col, err := t.GetColumnByName(colName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jsonFields) == 0 {
+		return col, nil
+	}
+	return t.jsonPathColumn(col, jsonFields)
+}
+
+func (t *Table) jsonPathColumn(col *Column, fields []string) (*Column, error) {
+	if col.colType != JSONType {
+		return nil, fmt.Errorf("%w: JSON paths can only be indexed on columns of type %s", ErrInvalidTypes, JSONType)
+	}
+
+	if len(fields) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	key := jsonPathColKey(col, fields)
+
+	vcol, exists := t.jsonPathCols[key]
+	if exists {
+		return vcol, nil
+	}
+
+	if t.jsonPathCols == nil {
+		t.jsonPathCols = make(map[string]*Column)
+	}
+
+	vcol = &Column{
+		id:         jsonPathColIDBase + uint32(len(t.jsonPathCols)),
+		table:      t,
+		colName:    (&JSONSelector{ColSelector: &ColSelector{col: col.colName}, fields: fields}).String(),
+		colType:    JSONType,
+		maxLen:     jsonPathColMaxLen,
+		jsonCol:    col,
+		jsonFields: fields,
+	}
+
+	t.jsonPathCols[key] = vcol
+
+	return vcol, nil
+}
+
+// getJSONPathColumn returns the virtual column holding the values found at the given path,
+// it only exists when the path is used by an index
+func (t *Table) getJSONPathColumn(colName string, fields []string) (*Column, bool) {
+	col, exists := t.colsByName[colName]
+	if !exists {
+		return nil, false
+	}
+
+	vcol, exists := t.jsonPathCols[jsonPathColKey(col, fields)]
+	return vcol, exists
+}
+
+func jsonPathColKey(col *Column, fields []string) string {
+	return fmt.Sprintf("%d:%s", col.id, strings.Join(fields, "->"))
+}
+
+// getIndexableColumnByID returns either a column of the table or a virtual column holding the values of a JSON path
+func (t *Table) getIndexableColumnByID(id uint32) (*Column, error) {
+	if id < jsonPathColIDBase {
+		return t.GetColumnByID(id)
+	}
+
+	for _, vcol := range t.jsonPathCols {
+		if vcol.id == id {
+			return vcol, nil
+		}
+	}
+	return nil, ErrColumnDoesNotExist
+}
+
+// withColumn returns a copy of the table in which the column with the same id is replaced by the given one
+func (t *Table) withColumn(col *Column) *Table {
+	tc := *t
+
+	tc.cols = make([]*Column, len(t.cols))
+	tc.colsByID = make(map[uint32]*Column, len(t.colsByID))
+	tc.colsByName = make(map[string]*Column, len(t.colsByName))
+
+	for i, c := range t.cols {
+		if c.id == col.id {
+			c = col
+		}
+
+		tc.cols[i] = c
+		tc.colsByID[c.id] = c
+		tc.colsByName[c.colName] = c
+	}
+	return &tc
+}
+
 func (t *Table) GetColumnByID(id uint32) (*Column, error) {
 	col, exists := t.colsByID[id]
 	if !exists {
@@ -398,6 +693,10 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, name)
 	}
 
+	if catlg.ExistView(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrViewAlreadyExists, name)
+	}
+
 	// Generate a new ID for the table by incrementing the 'maxTableID' variable of the 'catalog' instance.
 	id := (catlg.maxTableID + 1)
 
@@ -442,6 +741,10 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 			return nil, ErrLimitedAutoIncrement
 		}
 
+		if cs.colType == IntervalType {
+			return nil, ErrIntervalColumnNotSupported
+		}
+
 		if !validMaxLenForType(cs.maxLen, cs.colType) {
 			return nil, ErrLimitedMaxLen
 		}
@@ -454,6 +757,8 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 			maxLen:        cs.maxLen,
 			autoIncrement: cs.autoIncrement,
 			notNull:       cs.notNull,
+			defaultExp:    cs.defaultExp,
+			generatedExp:  cs.generatedExp,
 		}
 
 		table.cols = append(table.cols, col)
@@ -473,6 +778,120 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 	return table, nil
 }
 
+func (catlg *Catalog) newView(name string, cols []string, query string) (*View, error) {
+	if len(name) == 0 || len(query) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	if catlg.ExistTable(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, name)
+	}
+
+	if catlg.ExistView(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrViewAlreadyExists, name)
+	}
+
+	ds, err := parseViewQuery(name, query)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &View{
+		id:    catlg.maxViewID + 1,
+		name:  name,
+		cols:  cols,
+		query: query,
+		ds:    ds,
+		deps:  newViewDependencies(ds),
+	}
+
+	catlg.views = append(catlg.views, view)
+	catlg.viewsByName[name] = view
+
+	catlg.maxViewID++
+
+	return view, nil
+}
+
+func (catlg *Catalog) newSequence(name string, start, increment, minValue, maxValue int64, cycle bool) (*Sequence, error) {
+	if len(name) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	if increment == 0 {
+		return nil, fmt.Errorf("%w: sequence increment must not be zero", ErrIllegalArguments)
+	}
+
+	if minValue >= maxValue {
+		return nil, fmt.Errorf("%w: sequence minimum value (%d) must be less than its maximum value (%d)", ErrIllegalArguments, minValue, maxValue)
+	}
+
+	if start < minValue || start > maxValue {
+		return nil, fmt.Errorf("%w: sequence start value (%d) must be between %d and %d", ErrIllegalArguments, start, minValue, maxValue)
+	}
+
+	if catlg.ExistSequence(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrSequenceAlreadyExists, name)
+	}
+
+	seq := &Sequence{
+		id:        catlg.maxSequenceID + 1,
+		name:      name,
+		start:     start,
+		increment: increment,
+		minValue:  minValue,
+		maxValue:  maxValue,
+		cycle:     cycle,
+	}
+
+	catlg.sequences = append(catlg.sequences, seq)
+	catlg.sequencesByName[name] = seq
+
+	catlg.maxSequenceID++
+
+	return seq, nil
+}
+
+func (catlg *Catalog) deleteSequence(seq *Sequence) error {
+	_, exists := catlg.sequencesByName[seq.name]
+	if !exists {
+		return fmt.Errorf("%w (%s)", ErrSequenceDoesNotExist, seq.name)
+	}
+
+	newSequences := make([]*Sequence, 0, len(catlg.sequences)-1)
+
+	for _, s := range catlg.sequences {
+		if s.id != seq.id {
+			newSequences = append(newSequences, s)
+		}
+	}
+
+	catlg.sequences = newSequences
+	delete(catlg.sequencesByName, seq.name)
+
+	return nil
+}
+
+func (catlg *Catalog) deleteView(view *View) error {
+	_, exists := catlg.viewsByName[view.name]
+	if !exists {
+		return fmt.Errorf("%w (%s)", ErrViewDoesNotExist, view.name)
+	}
+
+	newViews := make([]*View, 0, len(catlg.views)-1)
+
+	for _, v := range catlg.views {
+		if v.id != view.id {
+			newViews = append(newViews, v)
+		}
+	}
+
+	catlg.views = newViews
+	delete(catlg.viewsByName, view.name)
+
+	return nil
+}
+
 func (catlg *Catalog) deleteTable(table *Table) error {
 	_, exists := catlg.tablesByID[table.id]
 	if !exists {
@@ -504,7 +923,7 @@ func (t *Table) newIndex(unique bool, colIDs []uint32) (index *Index, err error)
 	colsByID := make(map[uint32]*Column, len(colIDs))
 
 	for i, colID := range colIDs {
-		col, err := t.GetColumnByID(colID)
+		col, err := t.getIndexableColumnByID(colID)
 		if err != nil {
 			return nil, err
 		}
@@ -537,6 +956,10 @@ func (t *Table) newIndex(unique bool, colIDs []uint32) (index *Index, err error)
 	// having a direct way to get the indexes by colID
 	for _, col := range index.cols {
 		t.indexesByColID[col.id] = append(t.indexesByColID[col.id], index)
+
+		if col.jsonCol != nil {
+			t.indexesByColID[col.jsonCol.id] = append(t.indexesByColID[col.jsonCol.id], index)
+		}
 	}
 
 	if index.id == PKIndexID {
@@ -561,10 +984,15 @@ func (t *Table) newColumn(spec *ColSpec) (*Column, error) {
 		return nil, fmt.Errorf("%w (%s)", ErrLimitedAutoIncrement, spec.colName)
 	}
 
-	if spec.notNull {
+	// values of existing rows are computed from the default value or the generation expression
+	if spec.notNull && spec.defaultExp == nil && spec.generatedExp == nil {
 		return nil, fmt.Errorf("%w (%s)", ErrNewColumnMustBeNullable, spec.colName)
 	}
 
+	if spec.colType == IntervalType {
+		return nil, fmt.Errorf("%w (%s)", ErrIntervalColumnNotSupported, spec.colName)
+	}
+
 	if !validMaxLenForType(spec.maxLen, spec.colType) {
 		return nil, fmt.Errorf("%w (%s)", ErrLimitedMaxLen, spec.colName)
 	}
@@ -584,6 +1012,8 @@ func (t *Table) newColumn(spec *ColSpec) (*Column, error) {
 		maxLen:        spec.maxLen,
 		autoIncrement: spec.autoIncrement,
 		notNull:       spec.notNull,
+		defaultExp:    spec.defaultExp,
+		generatedExp:  spec.generatedExp,
 	}
 
 	t.cols = append(t.cols, col)
@@ -608,6 +1038,14 @@ func (ctlg *Catalog) renameTable(oldName, newName string) (*Table, error) {
 		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, newName)
 	}
 
+	if ctlg.ExistView(newName) {
+		return nil, fmt.Errorf("%w (%s)", ErrViewAlreadyExists, newName)
+	}
+
+	if view := ctlg.dependentView(oldName); view != nil {
+		return nil, fmt.Errorf("%w: table %s is required by view %s", ErrIllegalArguments, oldName, view.name)
+	}
+
 	t.name = newName
 
 	delete(ctlg.tablesByName, oldName)
@@ -678,6 +1116,173 @@ func (t *Table) deleteCheck(name string) (uint32, error) {
 	return c.id, nil
 }
 
+func (t *Table) newForeignKey(id uint32, name string, colIDs []uint32, refTable *Table, refColIDs []uint32) (*ForeignKey, error) {
+	if len(colIDs) == 0 || len(colIDs) != len(refColIDs) {
+		return nil, fmt.Errorf("%w: %s must reference as many columns as it includes", ErrInvalidForeignKeyConstraint, name)
+	}
+
+	_, isCheck := t.checkConstraints[name]
+	if isCheck || t.getForeignKey(name) != nil {
+		return nil, fmt.Errorf("%w (%s)", ErrConstraintAlreadyExists, name)
+	}
+
+	fk := &ForeignKey{
+		id:       id,
+		name:     name,
+		table:    t,
+		cols:     make([]*Column, len(colIDs)),
+		refTable: refTable,
+		refCols:  make([]*Column, len(refColIDs)),
+	}
+
+	included := make(map[uint32]struct{}, len(colIDs))
+
+	for i, colID := range colIDs {
+		col, err := t.GetColumnByID(colID)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := included[colID]; ok {
+			return nil, ErrDuplicatedColumn
+		}
+		included[colID] = struct{}{}
+
+		refCol, err := refTable.GetColumnByID(refColIDs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if col.colType != refCol.colType {
+			return nil, fmt.Errorf(
+				"%w: column '%s' of type %s can not reference column '%s' of type %s",
+				ErrInvalidForeignKeyConstraint,
+				col.colName,
+				col.colType,
+				refCol.colName,
+				refCol.colType,
+			)
+		}
+
+		fk.cols[i] = col
+		fk.refCols[i] = refCol
+	}
+
+	fk.refIndex = refTable.uniqueIndexOn(fk.refCols)
+	if fk.refIndex == nil {
+		return nil, fmt.Errorf("%w: referenced columns of table '%s' are not covered by a unique index", ErrInvalidForeignKeyConstraint, refTable.name)
+	}
+
+	t.foreignKeys = append(t.foreignKeys, fk)
+
+	return fk, nil
+}
+
+// uniqueIndexOn returns the unique index including exactly the given columns, if any
+func (t *Table) uniqueIndexOn(cols []*Column) *Index {
+	for _, index := range t.indexes {
+		if !index.unique || len(index.cols) != len(cols) {
+			continue
+		}
+
+		covered := true
+		for _, col := range cols {
+			covered = covered && index.IncludesCol(col.id)
+		}
+
+		if covered {
+			return index
+		}
+	}
+	return nil
+}
+
+func (t *Table) getForeignKey(name string) *ForeignKey {
+	for _, fk := range t.foreignKeys {
+		if fk.name == name {
+			return fk
+		}
+	}
+	return nil
+}
+
+// foreignKeyIncluding returns the first foreign key including the column, along with the column it references
+func (t *Table) foreignKeyIncluding(colID uint32) (*ForeignKey, *Column) {
+	for _, fk := range t.foreignKeys {
+		for i, col := range fk.cols {
+			if col.id == colID {
+				return fk, fk.refCols[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (t *Table) deleteForeignKey(name string) (uint32, error) {
+	fk := t.getForeignKey(name)
+	if fk == nil {
+		return 0, fmt.Errorf("%s.%s: %w", t.name, name, ErrConstraintNotFound)
+	}
+
+	newForeignKeys := make([]*ForeignKey, 0, len(t.foreignKeys)-1)
+
+	for _, k := range t.foreignKeys {
+		if k.id != fk.id {
+			newForeignKeys = append(newForeignKeys, k)
+		}
+	}
+
+	t.foreignKeys = newForeignKeys
+
+	return fk.id, nil
+}
+
+// referencingForeignKeys returns the foreign keys, including self-references, which reference the given table
+func (catlg *Catalog) referencingForeignKeys(table *Table) []*ForeignKey {
+	var fks []*ForeignKey
+
+	for _, t := range catlg.tables {
+		for _, fk := range t.foreignKeys {
+			if fk.refTable == table {
+				fks = append(fks, fk)
+			}
+		}
+	}
+	return fks
+}
+
+func (fk *ForeignKey) Name() string {
+	return fk.name
+}
+
+func (fk *ForeignKey) Table() *Table {
+	return fk.table
+}
+
+func (fk *ForeignKey) Cols() []*Column {
+	return fk.cols
+}
+
+func (fk *ForeignKey) ReferencedTable() *Table {
+	return fk.refTable
+}
+
+func (fk *ForeignKey) ReferencedCols() []*Column {
+	return fk.refCols
+}
+
+func (fk *ForeignKey) String() string {
+	return fmt.Sprintf("%s(%s) REFERENCES %s(%s)", fk.table.name, colNames(fk.cols), fk.refTable.name, colNames(fk.refCols))
+}
+
+func colNames(cols []*Column) string {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.colName
+	}
+	return strings.Join(names, ", ")
+}
+
 func (t *Table) deleteIndex(index *Index) error {
 	if index.IsPrimary() {
 		return fmt.Errorf("%w: primary key index can NOT be deleted", ErrIllegalArguments)
@@ -698,6 +1303,23 @@ func (t *Table) deleteIndex(index *Index) error {
 	return nil
 }
 
+// indexedValue returns the value of the column to be included in index entries
+func (c *Column) indexedValue(valuesByColID map[uint32]TypedValue) TypedValue {
+	if c.jsonCol == nil {
+		val, specified := valuesByColID[c.id]
+		if !specified {
+			return &NullValue{t: c.colType}
+		}
+		return val
+	}
+
+	jsonVal, isJSON := valuesByColID[c.jsonCol.id].(*JSON)
+	if !isJSON {
+		return &NullValue{t: JSONType}
+	}
+	return jsonVal.lookup(c.jsonFields)
+}
+
 func (c *Column) ID() uint32 {
 	return c.id
 }
@@ -735,7 +1357,136 @@ func (c *Column) IsAutoIncremental() bool {
 	return c.autoIncrement
 }
 
+// typeAt returns the type values of the column written at the given transaction are encoded with
+func (c *Column) typeAt(txID uint64) SQLValueType {
+	if txID == 0 {
+		// written within the ongoing transaction
+		return c.colType
+	}
+
+	for _, change := range c.typeChanges {
+		if txID < change.untilTx {
+			return change.colType
+		}
+	}
+	return c.colType
+}
+
+// decodeValueAt decodes a value of the column written at the given transaction. Values written before
+// the type of the column was altered are converted into the current type, when such conversion is
+// not possible or not exact, as it may happen with values only present in the history of a row,
+// they are returned as stored.
+func (c *Column) decodeValueAt(b []byte, txID uint64) (TypedValue, int, error) {
+	colType := c.typeAt(txID)
+
+	val, n, err := DecodeValue(b, colType)
+	if err != nil || colType == c.colType {
+		return val, n, err
+	}
+
+	conv, err := getExactConverter(colType, c.colType)
+	if err != nil {
+		return val, n, nil
+	}
+
+	cval, err := conv(val)
+	if err != nil {
+		return val, n, nil
+	}
+	return cval, n, nil
+}
+
+func (c *Column) DefaultValue() ValueExp {
+	return c.defaultExp
+}
+
+func (c *Column) IsGenerated() bool {
+	return c.generatedExp != nil
+}
+
+// references returns true if the given column is referenced by the generation expression of the column
+func (c *Column) references(col *Column) bool {
+	if c.generatedExp == nil {
+		return false
+	}
+
+	for _, sel := range c.generatedExp.selectors() {
+		_, _, colName := sel.resolve(c.table.name)
+		if colName == col.colName {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeName returns the type of the column along with its arguments e.g. VARCHAR(10), DECIMAL(12,2) or INTEGER[]
+func (c *Column) TypeName() string {
+	elemType := ArrayElemType(c.colType)
+
+	var args string
+
+	switch {
+	case c.maxLen > 0 && (elemType == VarcharType || elemType == BLOBType):
+		args = fmt.Sprintf("(%d)", c.maxLen)
+	case c.maxLen > 0 && elemType == DecimalType:
+		args = fmt.Sprintf("(%d,%d)", decimalPrecision(c.maxLen), decimalScale(c.maxLen))
+	}
+
+	if IsArrayType(c.colType) {
+		return elemType + args + arrayTypeSuffix
+	}
+	return elemType + args
+}
+
+// Precision returns the number of digits of a DECIMAL column, zero when it's unconstrained
+func (c *Column) Precision() int {
+	if c.colType != DecimalType {
+		return 0
+	}
+	return decimalPrecision(c.maxLen)
+}
+
+// Scale returns the number of fractional digits of a DECIMAL column
+func (c *Column) Scale() int {
+	if c.colType != DecimalType {
+		return 0
+	}
+	return decimalScale(c.maxLen)
+}
+
+// fitValue adjusts decimal values to the precision and scale of the column
+// and converts values assigned to array columns into arrays of the type of the column
+func (c *Column) fitValue(val TypedValue) (TypedValue, error) {
+	if val == nil || val.IsNull() {
+		return val, nil
+	}
+
+	if IsArrayType(c.colType) {
+		return fitArray(val, c.colType, c.maxLen)
+	}
+
+	if c.colType != DecimalType {
+		return val, nil
+	}
+
+	d, err := decimalValue(val.RawValue())
+	if err != nil {
+		return nil, err
+	}
+
+	d, err = d.fit(c.maxLen)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
 func validMaxLenForType(maxLen int, sqlType SQLValueType) bool {
+	if IsArrayType(sqlType) {
+		// the max length of an array column applies to its elements
+		return validMaxLenForType(maxLen, ArrayElemType(sqlType))
+	}
+
 	switch sqlType {
 	case BooleanType:
 		return maxLen <= 1
@@ -747,6 +1498,8 @@ func validMaxLenForType(maxLen int, sqlType SQLValueType) bool {
 		return maxLen == 0 || maxLen == 8
 	case UUIDType:
 		return maxLen == 0 || maxLen == 16
+	case DecimalType:
+		return validDecimalMaxLen(maxLen)
 	}
 
 	return maxLen >= 0
@@ -757,6 +1510,19 @@ func (catlg *Catalog) load(ctx context.Context, tx *store.OngoingTx) error {
 }
 
 func (catlg *Catalog) loadCatalog(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
+	err := catlg.loadTables(ctx, tx, copyToTx)
+	if err != nil {
+		return err
+	}
+
+	err = catlg.loadViews(ctx, tx, copyToTx)
+	if err != nil {
+		return err
+	}
+	return catlg.loadSequences(ctx, tx, copyToTx)
+}
+
+func (catlg *Catalog) loadTables(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
 	prefix := MapKey(catlg.enginePrefix, catalogTablePrefix, EncodeID(1))
 
 	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
@@ -798,10 +1564,179 @@ func (catlg *Catalog) loadCatalog(ctx context.Context, tx *store.OngoingTx, copy
 				return err
 			}
 		}
-		return table.loadIndexes(ctx, catlg.enginePrefix, tx, copyToTx)
+
+		err = table.loadColumnTypeChanges(ctx, catlg.enginePrefix, tx, copyToTx)
+		if err != nil {
+			return err
+		}
+
+		err = table.loadIndexes(ctx, catlg.enginePrefix, tx, copyToTx)
+		if err != nil {
+			return err
+		}
+
+		// referenced tables are always created before the referencing ones
+		return table.loadForeignKeys(ctx, catlg.enginePrefix, tx, copyToTx)
+	})
+}
+
+func (catlg *Catalog) loadViews(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(catlg.enginePrefix, catalogViewPrefix, EncodeID(DatabaseID))
+
+	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		viewID, err := unmapViewID(catlg.enginePrefix, key)
+		if err != nil {
+			return err
+		}
+
+		if deleted {
+			catlg.maxViewID++
+			return nil
+		}
+
+		if copyToTx {
+			catlg.maxViewID++
+			return tx.Set(key, nil, value)
+		}
+
+		name, cols, query, err := decodeView(value)
+		if err != nil {
+			return err
+		}
+
+		view, err := catlg.newView(name, cols, query)
+		if err != nil {
+			return err
+		}
+
+		if viewID != view.id {
+			return ErrCorruptedData
+		}
+		return nil
+	})
+}
+
+// decodeView decodes a view definition encoded as {nameLen-1}{name}{colCount}({colNameLen-1}{colName})*{query}
+func decodeView(value []byte) (name string, cols []string, query string, err error) {
+	if len(value) < 2 {
+		return "", nil, "", ErrCorruptedData
+	}
+
+	i := 0
+
+	nameLen := int(value[i]) + 1
+	i++
+
+	if len(value) < i+nameLen+1 {
+		return "", nil, "", ErrCorruptedData
+	}
+
+	name = string(value[i : i+nameLen])
+	i += nameLen
+
+	colCount := int(value[i])
+	i++
+
+	for j := 0; j < colCount; j++ {
+		if len(value) < i+1 {
+			return "", nil, "", ErrCorruptedData
+		}
+
+		colLen := int(value[i]) + 1
+		i++
+
+		if len(value) < i+colLen {
+			return "", nil, "", ErrCorruptedData
+		}
+
+		cols = append(cols, string(value[i:i+colLen]))
+		i += colLen
+	}
+
+	return name, cols, string(value[i:]), nil
+}
+
+func (catlg *Catalog) loadSequences(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(catlg.enginePrefix, catalogSequencePrefix, EncodeID(DatabaseID))
+
+	err := iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		seqID, err := unmapSequenceID(catlg.enginePrefix, key)
+		if err != nil {
+			return err
+		}
+
+		if deleted {
+			catlg.maxSequenceID++
+			return nil
+		}
+
+		if copyToTx {
+			catlg.maxSequenceID++
+			return tx.Set(key, nil, value)
+		}
+
+		name, start, increment, minValue, maxValue, cycle, err := decodeSequence(value)
+		if err != nil {
+			return err
+		}
+
+		seq, err := catlg.newSequence(name, start, increment, minValue, maxValue, cycle)
+		if err != nil {
+			return err
+		}
+
+		if seqID != seq.id {
+			return ErrCorruptedData
+		}
+		return nil
+	})
+	if err != nil || !copyToTx {
+		return err
+	}
+
+	// last generated values are read on demand, they only need to be copied
+	valuePrefix := MapKey(catlg.enginePrefix, catalogSeqValuePrefix, EncodeID(DatabaseID))
+
+	return iteratePrefix(ctx, tx, valuePrefix, func(key, value []byte, deleted bool) error {
+		if deleted {
+			return nil
+		}
+		return tx.Set(key, nil, value)
 	})
 }
 
+// decodeSequence decodes a sequence definition encoded as {nameLen-1}{name}{start}{increment}{minValue}{maxValue}{cycle}
+func decodeSequence(value []byte) (name string, start, increment, minValue, maxValue int64, cycle bool, err error) {
+	if len(value) < 2 {
+		return "", 0, 0, 0, 0, false, ErrCorruptedData
+	}
+
+	nameLen := int(value[0]) + 1
+
+	if len(value) != 1+nameLen+4*8+1 {
+		return "", 0, 0, 0, 0, false, ErrCorruptedData
+	}
+
+	i := 1
+
+	name = string(value[i : i+nameLen])
+	i += nameLen
+
+	start = int64(binary.BigEndian.Uint64(value[i:]))
+	i += 8
+
+	increment = int64(binary.BigEndian.Uint64(value[i:]))
+	i += 8
+
+	minValue = int64(binary.BigEndian.Uint64(value[i:]))
+	i += 8
+
+	maxValue = int64(binary.BigEndian.Uint64(value[i:]))
+	i += 8
+
+	return name, start, increment, minValue, maxValue, value[i] == 1, nil
+}
+
 func loadMaxPK(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, table *Table) ([]byte, error) {
 	pkReaderSpec := store.KeyReaderSpec{
 		Prefix:    MapKey(sqlPrefix, MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id)),
@@ -829,8 +1764,16 @@ func loadColSpecs(ctx context.Context, tableID uint32, tx *store.OngoingTx, sqlP
 	specs := make(map[uint32]*ColSpec)
 
 	err := iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		// the key of a column whose type was altered is replaced by a new one with the same id
 		if deleted {
-			maxColID++
+			_, _, colID, _, err := unmapColSpec(sqlPrefix, key)
+			if err != nil {
+				return err
+			}
+
+			if colID > maxColID {
+				maxColID = colID
+			}
 			return nil
 		}
 
@@ -839,7 +1782,9 @@ func loadColSpecs(ctx context.Context, tableID uint32, tx *store.OngoingTx, sqlP
 			return err
 		}
 
-		maxColID++
+		if colID > maxColID {
+			maxColID = colID
+		}
 
 		specs[colID] = colSpec
 
@@ -856,22 +1801,47 @@ func loadColSpec(sqlPrefix, key, value []byte, tableID uint32) (*ColSpec, uint32
 		return nil, 0, ErrCorruptedData
 	}
 
-	mdbID, mtableID, colID, colType, err := unmapColSpec(sqlPrefix, key)
+	mdbID, mtableID, colID, colType, err := unmapColSpec(sqlPrefix, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if mdbID != 1 || tableID != mtableID {
+		return nil, 0, ErrCorruptedData
+	}
+
+	spec := &ColSpec{
+		colName:       string(value[5:]),
+		colType:       colType,
+		maxLen:        int(binary.BigEndian.Uint32(value[1:])),
+		autoIncrement: value[0]&autoIncrementFlag != 0,
+		notNull:       value[0]&nullableFlag != 0,
+	}
+
+	if value[0]&(defaultFlag|generatedFlag) == 0 {
+		return spec, colID, nil
+	}
+
+	// {flags}{maxLen}{colNameLen-1}{colNAME}{expText}
+	nameLen := int(value[5]) + 1
+	if len(value) < 6+nameLen {
+		return nil, 0, ErrCorruptedData
+	}
+
+	spec.colName = string(value[6 : 6+nameLen])
+
+	exp, err := ParseExpFromString(string(value[6+nameLen:]))
 	if err != nil {
 		return nil, 0, err
 	}
 
-	if mdbID != 1 || tableID != mtableID {
-		return nil, 0, ErrCorruptedData
+	if value[0]&defaultFlag != 0 {
+		spec.defaultExp = exp
+	} else {
+		spec.generatedExp = exp
 	}
 
-	return &ColSpec{
-		colName:       string(value[5:]),
-		colType:       colType,
-		maxLen:        int(binary.BigEndian.Uint32(value[1:])),
-		autoIncrement: value[0]&autoIncrementFlag != 0,
-		notNull:       value[0]&nullableFlag != 0,
-	}, colID, nil
+	return spec, colID, nil
 }
 
 func loadCheckConstraints(ctx context.Context, dbID, tableID uint32, tx *store.OngoingTx, sqlPrefix []byte, copyToTx bool) (map[string]CheckConstraint, error) {
@@ -897,6 +1867,77 @@ func loadCheckConstraints(ctx context.Context, dbID, tableID uint32, tx *store.O
 	return checks, err
 }
 
+// loadColumnTypeChanges loads the previous types of the columns of the table. Changes are persisted within the transaction
+// rewriting the rows of the table, thus such transaction is the first one in which values are encoded with the new type
+func (table *Table) loadColumnTypeChanges(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(sqlPrefix, catalogColumnTypePrefix, EncodeID(DatabaseID), EncodeID(table.id))
+
+	reader, err := tx.NewKeyReader(store.KeyReaderSpec{Prefix: prefix})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for {
+		key, vref, err := reader.Read(ctx)
+		if errors.Is(err, store.ErrNoMoreEntries) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		colID, err := unmapColumnTypeChange(sqlPrefix, key, table.id)
+		if err != nil {
+			return err
+		}
+
+		v, err := vref.Resolve()
+		if err != nil {
+			return err
+		}
+
+		// {untilTx}{colTYPE}, untilTx is only set when the entry is copied into a different transaction
+		untilTx := vref.Tx()
+
+		if len(v) < 8 {
+			return ErrCorruptedData
+		}
+
+		if binary.BigEndian.Uint64(v) > 0 {
+			untilTx = binary.BigEndian.Uint64(v)
+		}
+
+		colType, err := asType(string(v[8:]))
+		if err != nil {
+			return ErrCorruptedData
+		}
+
+		if copyToTx {
+			cv := make([]byte, len(v))
+			copy(cv, v)
+			binary.BigEndian.PutUint64(cv, untilTx)
+
+			err = tx.Set(key, nil, cv)
+			if err != nil {
+				return err
+			}
+		}
+
+		col, err := table.GetColumnByID(colID)
+		if errors.Is(err, ErrColumnDoesNotExist) {
+			// dropped column
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		col.typeChanges = append(col.typeChanges, colTypeChange{untilTx: untilTx, colType: colType})
+	}
+	return nil
+}
+
 func (table *Table) loadIndexes(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, copyToTx bool) error {
 	prefix := MapKey(sqlPrefix, catalogIndexPrefix, EncodeID(1), EncodeID(table.id))
 
@@ -921,20 +1962,19 @@ func (table *Table) loadIndexes(ctx context.Context, sqlPrefix []byte, tx *store
 			}
 		} else {
 			// v={unique {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)}
-			colSpecLen := EncIDLen + 1
-			if len(value) < 1+colSpecLen || len(value)%colSpecLen != 1 {
+			if len(value) < 1+EncIDLen+1 {
 				return ErrCorruptedData
 			}
 
 			var colIDs []uint32
-			for i := 1; i < len(value); i += colSpecLen {
-				colID := binary.BigEndian.Uint32(value[i:])
-
-				// TODO: currently only ASC order is supported
-				if value[i+EncIDLen] != 0 {
-					return ErrCorruptedData
+			for i := 1; i < len(value); {
+				colID, n, err := table.decodeIndexColSpec(value[i:])
+				if err != nil {
+					return err
 				}
+
 				colIDs = append(colIDs, colID)
+				i += n
 			}
 
 			index, err := table.newIndex(value[0] > 0, colIDs)
@@ -950,6 +1990,135 @@ func (table *Table) loadIndexes(ctx context.Context, sqlPrefix []byte, tx *store
 	})
 }
 
+func (table *Table) loadForeignKeys(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(sqlPrefix, catalogForeignKeyPrefix, EncodeID(DatabaseID), EncodeID(table.id))
+
+	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		if deleted {
+			return nil
+		}
+
+		if copyToTx {
+			return tx.Set(key, nil, value)
+		}
+
+		id, err := unmapForeignKeyID(sqlPrefix, key)
+		if err != nil {
+			return err
+		}
+
+		// v={nameLen-1}{name}{refTableID}{colCount}{colID1}...{colIDN}{refColID1}...{refColIDN}
+		if len(value) < 1 {
+			return ErrCorruptedData
+		}
+
+		nameLen := int(value[0]) + 1
+		if len(value) < 1+nameLen+EncIDLen+1 {
+			return ErrCorruptedData
+		}
+
+		name := string(value[1 : 1+nameLen])
+		i := 1 + nameLen
+
+		refTableID := binary.BigEndian.Uint32(value[i:])
+		i += EncIDLen
+
+		n := int(value[i])
+		i++
+
+		if len(value) != i+2*n*EncIDLen {
+			return ErrCorruptedData
+		}
+
+		colIDs := make([]uint32, n)
+		refColIDs := make([]uint32, n)
+
+		for j := 0; j < n; j++ {
+			colIDs[j] = binary.BigEndian.Uint32(value[i+j*EncIDLen:])
+			refColIDs[j] = binary.BigEndian.Uint32(value[i+(n+j)*EncIDLen:])
+		}
+
+		refTable, err := table.catalog.GetTableByID(refTableID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrCorruptedData, err)
+		}
+
+		_, err = table.newForeignKey(id, name, colIDs, refTable, refColIDs)
+		return err
+	})
+}
+
+// encodeIndexColSpec encodes the specification of an indexed column as {colID}(ASC|DESC),
+// columns holding the values of a JSON path are encoded as {colID}{JSONPath}{fieldCount}({fieldLen}{field})+
+func encodeIndexColSpec(col *Column) []byte {
+	if col.jsonCol == nil {
+		// TODO: currently only ASC order is supported
+		return append(EncodeID(col.id), 0)
+	}
+
+	spec := append(EncodeID(col.jsonCol.id), indexColSpecJSONPath)
+	spec = binary.BigEndian.AppendUint32(spec, uint32(len(col.jsonFields)))
+
+	for _, field := range col.jsonFields {
+		spec = binary.BigEndian.AppendUint32(spec, uint32(len(field)))
+		spec = append(spec, field...)
+	}
+	return spec
+}
+
+func (t *Table) decodeIndexColSpec(b []byte) (colID uint32, n int, err error) {
+	if len(b) < EncIDLen+1 {
+		return 0, 0, ErrCorruptedData
+	}
+
+	colID = binary.BigEndian.Uint32(b)
+	n = EncIDLen + 1
+
+	switch b[EncIDLen] {
+	case 0:
+		return colID, n, nil
+	case indexColSpecJSONPath:
+	default:
+		return 0, 0, ErrCorruptedData
+	}
+
+	if len(b) < n+EncLenLen {
+		return 0, 0, ErrCorruptedData
+	}
+
+	fieldCount := int(binary.BigEndian.Uint32(b[n:]))
+	n += EncLenLen
+
+	fields := make([]string, 0, fieldCount)
+
+	for i := 0; i < fieldCount; i++ {
+		if len(b) < n+EncLenLen {
+			return 0, 0, ErrCorruptedData
+		}
+
+		fieldLen := int(binary.BigEndian.Uint32(b[n:]))
+		n += EncLenLen
+
+		if len(b) < n+fieldLen {
+			return 0, 0, ErrCorruptedData
+		}
+
+		fields = append(fields, string(b[n:n+fieldLen]))
+		n += fieldLen
+	}
+
+	col, err := t.GetColumnByID(colID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	vcol, err := t.jsonPathColumn(col, fields)
+	if err != nil {
+		return 0, 0, err
+	}
+	return vcol.id, n, nil
+}
+
 func trimPrefix(prefix, mkey []byte, mappingPrefix []byte) ([]byte, error) {
 	if len(prefix)+len(mappingPrefix) > len(mkey) ||
 		!bytes.Equal(prefix, mkey[:len(prefix)]) ||
@@ -988,6 +2157,58 @@ func unmapCheckID(prefix, mkey []byte) (uint32, error) {
 	return binary.BigEndian.Uint32(encID[2*EncIDLen:]), nil
 }
 
+func unmapColumnTypeChange(prefix, mkey []byte, tableID uint32) (colID uint32, err error) {
+	encID, err := trimPrefix(prefix, mkey, []byte(catalogColumnTypePrefix))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(encID) != 4*EncIDLen {
+		return 0, ErrCorruptedData
+	}
+
+	if binary.BigEndian.Uint32(encID) != DatabaseID || binary.BigEndian.Uint32(encID[EncIDLen:]) != tableID {
+		return 0, ErrCorruptedData
+	}
+	return binary.BigEndian.Uint32(encID[2*EncIDLen:]), nil
+}
+
+func unmapViewID(prefix, mkey []byte) (uint32, error) {
+	encID, err := trimPrefix(prefix, mkey, []byte(catalogViewPrefix))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(encID) != 2*EncIDLen {
+		return 0, ErrCorruptedData
+	}
+	return binary.BigEndian.Uint32(encID[EncIDLen:]), nil
+}
+
+func unmapSequenceID(prefix, mkey []byte) (uint32, error) {
+	encID, err := trimPrefix(prefix, mkey, []byte(catalogSequencePrefix))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(encID) != 2*EncIDLen {
+		return 0, ErrCorruptedData
+	}
+	return binary.BigEndian.Uint32(encID[EncIDLen:]), nil
+}
+
+func unmapForeignKeyID(prefix, mkey []byte) (uint32, error) {
+	encID, err := trimPrefix(prefix, mkey, []byte(catalogForeignKeyPrefix))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(encID) != 3*EncIDLen {
+		return 0, ErrCorruptedData
+	}
+	return binary.BigEndian.Uint32(encID[2*EncIDLen:]), nil
+}
+
 func parseCheckConstraint(prefix, key, value []byte) (*CheckConstraint, error) {
 	id, err := unmapCheckID(prefix, key)
 	if err != nil {
@@ -1035,6 +2256,7 @@ func asType(t string) (SQLValueType, error) {
 	switch t {
 	case IntegerType,
 		Float64Type,
+		DecimalType,
 		BooleanType,
 		VarcharType,
 		UUIDType,
@@ -1043,6 +2265,10 @@ func asType(t string) (SQLValueType, error) {
 		JSONType:
 		return t, nil
 	}
+
+	if IsArrayType(t) && validArrayElemType(ArrayElemType(t)) {
+		return t, nil
+	}
 	return t, ErrCorruptedData
 }
 
@@ -1104,6 +2330,9 @@ func unmapIndexEntry(index *Index, sqlPrefix, mkey []byte) (encPKVals []byte, er
 		if variableSizedType(col.colType) {
 			maxLen += EncLenLen
 		}
+		if col.colType == DecimalType {
+			maxLen = decimalKeyLen
+		}
 		if len(enc)-off < maxLen {
 			return nil, ErrCorruptedData
 		}
@@ -1160,12 +2389,49 @@ const (
 	KeyValPrefixUpperBound byte = 0xFF
 )
 
+// values found at JSON paths are prefixed by their type when encoded as keys
+const (
+	jsonKeyTypeBool   byte = 0x01
+	jsonKeyTypeNumber byte = 0x02
+	jsonKeyTypeString byte = 0x03
+	jsonKeyTypeOther  byte = 0x04
+)
+
+const (
+	// virtual columns holding the values of JSON paths are assigned ids which
+	// don't collide with the ones of table columns
+	jsonPathColIDBase uint32 = 1 << 31
+
+	// max length of the values found at JSON paths which can be indexed
+	jsonPathColMaxLen = 256
+
+	indexColSpecJSONPath byte = 1 << 7
+)
+
+// jsonKeyTypePrefix returns the key prefix shared by all the values found at JSON paths of the same type as val
+func jsonKeyTypePrefix(val TypedValue, maxLen int) ([]byte, error) {
+	encVal, _, err := EncodeValueAsKey(val, JSONType, maxLen)
+	if err != nil {
+		return nil, err
+	}
+
+	if encVal[0] == KeyValPrefixNull {
+		return encVal, nil
+	}
+	return encVal[:2], nil
+}
+
 func EncodeValueAsKey(val TypedValue, colType SQLValueType, maxLen int) ([]byte, int, error) {
 	return EncodeRawValueAsKey(val.RawValue(), colType, maxLen)
 }
 
 // EncodeRawValueAsKey encodes a value in a b-tree meaningful way.
 func EncodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int) ([]byte, int, error) {
+	if colType == DecimalType {
+		// decimal keys have a fixed length regardless of the precision of the column
+		return encodeDecimalAsKey(val)
+	}
+
 	if maxLen <= 0 {
 		return nil, 0, ErrInvalidValue
 	}
@@ -1326,11 +2592,86 @@ func EncodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int) ([]b
 
 			return encv[:], 8, nil
 		}
+	case JSONType:
+		{
+			// notnull + type + value + padding + len(value)
+			encv := make([]byte, 2+maxLen+EncLenLen)
+			encv[0] = KeyValPrefixNotNull
+
+			n := 0
+
+			switch v := convVal.(type) {
+			case bool:
+				encv[1] = jsonKeyTypeBool
+				if v {
+					encv[2] = 1
+				}
+				n = 1
+			case int64, float64:
+				if maxLen < 8 {
+					return nil, 0, ErrMaxLengthExceeded
+				}
+
+				floatVal, ok := v.(float64)
+				if !ok {
+					floatVal = float64(v.(int64))
+				}
+
+				encf, _, err := EncodeRawValueAsKey(floatVal, Float64Type, 8)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				encv[1] = jsonKeyTypeNumber
+				copy(encv[2:], encf[1:])
+				n = 8
+			case string:
+				// longer strings are truncated, as it preserves the order of the keys
+				// and rows are anyway filtered when read through the index
+				if len(v) > maxLen {
+					v = v[:maxLen]
+				}
+
+				encv[1] = jsonKeyTypeString
+				copy(encv[2:], v)
+				n = len(v)
+			default:
+				// arrays and objects are not comparable,
+				// thus their values are not included in the key
+				encv[1] = jsonKeyTypeOther
+			}
+
+			binary.BigEndian.PutUint32(encv[len(encv)-EncLenLen:], uint32(n))
+
+			return encv, n, nil
+		}
 	}
 
 	return nil, 0, ErrInvalidValue
 }
 
+func encodeDecimalAsKey(val interface{}) ([]byte, int, error) {
+	if val == nil {
+		return []byte{KeyValPrefixNull}, 0, nil
+	}
+
+	d, err := decimalValue(val)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d, err = d.fit(0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	encv := make([]byte, 1+decimalKeyLen)
+	encv[0] = KeyValPrefixNotNull
+	copy(encv[1:], d.encodeAsKey())
+
+	return encv, decimalKeyLen, nil
+}
+
 func getEncodeRawValue(val TypedValue, colType SQLValueType) (interface{}, error) {
 	if colType != JSONType || val.Type() == JSONType {
 		return val.RawValue(), nil
@@ -1381,7 +2722,37 @@ func EncodeRawValue(val interface{}, colType SQLValueType, maxLen int, nullable
 		return encv, nil
 	}
 
+	if IsArrayType(colType) {
+		return encodeArray(convVal, ArrayElemType(colType), maxLen)
+	}
+
 	switch colType {
+	case DecimalType:
+		{
+			strVal, ok := convVal.(string)
+			if !ok {
+				return nil, fmt.Errorf("value is not a decimal: %w", ErrInvalidValue)
+			}
+
+			d, err := ParseDecimal(strVal)
+			if err != nil {
+				return nil, err
+			}
+
+			d, err = d.fit(maxLen)
+			if err != nil {
+				return nil, err
+			}
+
+			// len(v) + v
+			strVal = d.String()
+
+			encv := make([]byte, EncLenLen+len(strVal))
+			binary.BigEndian.PutUint32(encv[:], uint32(len(strVal)))
+			copy(encv[EncLenLen:], []byte(strVal))
+
+			return encv, nil
+		}
 	case VarcharType:
 		{
 			strVal, ok := convVal.(string)
@@ -1550,6 +2921,14 @@ func decodeValue(b []byte, colType SQLValueType, nullable bool) (TypedValue, int
 		return &NullValue{t: colType}, voff, nil
 	}
 
+	if IsArrayType(colType) {
+		arr, err := decodeArray(b[voff:voff+vlen], ArrayElemType(colType))
+		if err != nil {
+			return nil, 0, err
+		}
+		return arr, voff + vlen, nil
+	}
+
 	switch colType {
 	case VarcharType:
 		{
@@ -1558,6 +2937,16 @@ func decodeValue(b []byte, colType SQLValueType, nullable bool) (TypedValue, int
 
 			return &Varchar{val: v}, voff, nil
 		}
+	case DecimalType:
+		{
+			d, err := ParseDecimal(string(b[voff : voff+vlen]))
+			if err != nil {
+				return nil, 0, ErrCorruptedData
+			}
+			voff += vlen
+
+			return d, voff, nil
+		}
 	case IntegerType:
 		{
 			if vlen != 8 {
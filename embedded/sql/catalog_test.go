@@ -146,6 +146,69 @@ func TestEncodeRawValueAsKey(t *testing.T) {
 			prevEncKey = encKey
 		}
 	})
+
+	t.Run("encoded json keys should preserve lex order within each type", func(t *testing.T) {
+		var prevEncKey []byte
+
+		for _, v := range []interface{}{false, true, -10.5, int64(-1), float64(0), int64(2), 2.5, "a", "ab", "b", []interface{}{1}} {
+			encKey, _, err := EncodeRawValueAsKey(v, JSONType, 10)
+			require.NoError(t, err)
+			require.Len(t, encKey, 2+10+EncLenLen)
+			require.Greater(t, encKey, prevEncKey)
+
+			prevEncKey = encKey
+		}
+
+		encKey, _, err := EncodeRawValueAsKey(nil, JSONType, 10)
+		require.NoError(t, err)
+		require.Equal(t, []byte{KeyValPrefixNull}, encKey)
+
+		encKey, n, err := EncodeRawValueAsKey("a long string value", JSONType, 10)
+		require.NoError(t, err)
+		require.Equal(t, 10, n)
+
+		truncatedKey, _, err := EncodeRawValueAsKey("a long str", JSONType, 10)
+		require.NoError(t, err)
+		require.Equal(t, truncatedKey, encKey)
+	})
+}
+
+func TestIndexColSpecEncoding(t *testing.T) {
+	catalog := newCatalog(nil)
+
+	table, err := catalog.newTable("events", map[uint32]*ColSpec{
+		1: {colName: "id", colType: IntegerType},
+		2: {colName: "payload", colType: JSONType},
+	}, nil, 2)
+	require.NoError(t, err)
+
+	id, err := table.GetColumnByName("id")
+	require.NoError(t, err)
+
+	spec := encodeIndexColSpec(id)
+
+	colID, n, err := table.decodeIndexColSpec(spec)
+	require.NoError(t, err)
+	require.Equal(t, id.id, colID)
+	require.Len(t, spec, n)
+
+	vcol, err := table.indexColumn("payload", []string{"user", "country"})
+	require.NoError(t, err)
+	require.Equal(t, "payload->'user->country'", vcol.Name())
+	require.GreaterOrEqual(t, vcol.id, jsonPathColIDBase)
+
+	spec = encodeIndexColSpec(vcol)
+
+	colID, n, err = table.decodeIndexColSpec(spec)
+	require.NoError(t, err)
+	require.Equal(t, vcol.id, colID)
+	require.Len(t, spec, n)
+
+	_, _, err = table.decodeIndexColSpec(spec[:len(spec)-1])
+	require.ErrorIs(t, err, ErrCorruptedData)
+
+	_, err = table.indexColumn("id", []string{"field"})
+	require.ErrorIs(t, err, ErrInvalidTypes)
 }
 
 func TestCatalogTableLength(t *testing.T) {
@@ -25,6 +25,10 @@ type conditionalRowReader struct {
 	rowReader RowReader
 
 	condition ValueExp
+
+	// condition with parameters substituted, shared by all rows so that
+	// sub-queries not referencing the rows are evaluated only once
+	substitutedCondition ValueExp
 }
 
 func newConditionalRowReader(rowReader RowReader, condition ValueExp) *conditionalRowReader {
@@ -77,7 +81,7 @@ func (cr *conditionalRowReader) InferParameters(ctx context.Context, params map[
 		return err
 	}
 
-	_, err = cr.condition.inferType(cols, params, cr.TableAlias())
+	_, err = cr.condition.inferType(cr.Tx(), cols, params, cr.TableAlias())
 
 	return err
 }
@@ -89,12 +93,16 @@ func (cr *conditionalRowReader) Read(ctx context.Context) (*Row, error) {
 			return nil, err
 		}
 
-		cond, err := cr.condition.substitute(cr.Parameters())
-		if err != nil {
-			return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
+		if cr.substitutedCondition == nil {
+			cond, err := cr.condition.substitute(cr.Parameters())
+			if err != nil {
+				return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
+			}
+
+			cr.substitutedCondition = cond
 		}
 
-		r, err := cond.reduce(cr.Tx(), row, cr.rowReader.TableAlias())
+		r, err := cr.substitutedCondition.reduce(cr.Tx(), row, cr.rowReader.TableAlias())
 		if err != nil {
 			return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
 		}
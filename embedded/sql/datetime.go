@@ -0,0 +1,436 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fields of timestamps and intervals accepted by DATE_TRUNC, EXTRACT and DATE_PART
+const (
+	microsecondsField = "microseconds"
+	millisecondsField = "milliseconds"
+	secondField       = "second"
+	minuteField       = "minute"
+	hourField         = "hour"
+	dayField          = "day"
+	weekField         = "week"
+	monthField        = "month"
+	quarterField      = "quarter"
+	yearField         = "year"
+	decadeField       = "decade"
+	centuryField      = "century"
+	millenniumField   = "millennium"
+	dowField          = "dow"
+	isodowField       = "isodow"
+	doyField          = "doy"
+	isoyearField      = "isoyear"
+	epochField        = "epoch"
+)
+
+var dateFieldAliases = map[string]string{
+	"microsecond":  microsecondsField,
+	"us":           microsecondsField,
+	"usec":         microsecondsField,
+	"usecs":        microsecondsField,
+	"millisecond":  millisecondsField,
+	"ms":           millisecondsField,
+	"msec":         millisecondsField,
+	"msecs":        millisecondsField,
+	"seconds":      secondField,
+	"sec":          secondField,
+	"secs":         secondField,
+	"s":            secondField,
+	"minutes":      minuteField,
+	"min":          minuteField,
+	"mins":         minuteField,
+	"m":            minuteField,
+	"hours":        hourField,
+	"hr":           hourField,
+	"hrs":          hourField,
+	"h":            hourField,
+	"days":         dayField,
+	"d":            dayField,
+	"weeks":        weekField,
+	"w":            weekField,
+	"months":       monthField,
+	"mon":          monthField,
+	"mons":         monthField,
+	"quarters":     quarterField,
+	"qtr":          quarterField,
+	"years":        yearField,
+	"yr":           yearField,
+	"yrs":          yearField,
+	"y":            yearField,
+	"decades":      decadeField,
+	"centuries":    centuryField,
+	"millennia":    millenniumField,
+	"millenniums":  millenniumField,
+	"microseconds": microsecondsField,
+	"milliseconds": millisecondsField,
+}
+
+func dateField(field string) string {
+	field = strings.ToLower(strings.TrimSpace(field))
+
+	if f, ok := dateFieldAliases[field]; ok {
+		return f
+	}
+	return field
+}
+
+// truncateTimestamp truncates the timestamp to the precision of the given field,
+// weeks start on monday and centuries and millenniums on years 1, 101, 1001 and so on
+func truncateTimestamp(t time.Time, field string) (time.Time, error) {
+	year, month, day := t.Date()
+
+	switch dateField(field) {
+	case microsecondsField:
+		return t.Truncate(time.Microsecond), nil
+	case millisecondsField:
+		return t.Truncate(time.Millisecond), nil
+	case secondField:
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, t.Location()), nil
+	case minuteField:
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, t.Location()), nil
+	case hourField:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location()), nil
+	case dayField:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location()), nil
+	case weekField:
+		return time.Date(year, month, day-(isoWeekday(t)-1), 0, 0, 0, 0, t.Location()), nil
+	case monthField:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location()), nil
+	case quarterField:
+		return time.Date(year, (month-1)/3*3+1, 1, 0, 0, 0, 0, t.Location()), nil
+	case yearField:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	case decadeField:
+		return time.Date(year-year%10, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	case centuryField:
+		return time.Date((year-1)/100*100+1, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	case millenniumField:
+		return time.Date((year-1)/1000*1000+1, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	}
+
+	return t, fmt.Errorf("%w: '%s' function does not support the field '%s'", ErrIllegalArguments, DateTruncFnCall, field)
+}
+
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// extractFromTimestamp returns the value of the given field of the timestamp, seconds being returned with their fraction
+func extractFromTimestamp(fn string, t time.Time, field string) (*Decimal, error) {
+	secondMicros := int64(t.Second())*microsPerSecond + int64(t.Nanosecond()/1000)
+
+	switch dateField(field) {
+	case microsecondsField:
+		return NewDecimal(secondMicros, 0), nil
+	case millisecondsField:
+		return NewDecimal(secondMicros, 3), nil
+	case secondField:
+		return NewDecimal(secondMicros, 6), nil
+	case minuteField:
+		return NewDecimal(int64(t.Minute()), 0), nil
+	case hourField:
+		return NewDecimal(int64(t.Hour()), 0), nil
+	case dayField:
+		return NewDecimal(int64(t.Day()), 0), nil
+	case weekField:
+		_, week := t.ISOWeek()
+		return NewDecimal(int64(week), 0), nil
+	case monthField:
+		return NewDecimal(int64(t.Month()), 0), nil
+	case quarterField:
+		return NewDecimal(int64((t.Month()-1)/3+1), 0), nil
+	case yearField:
+		return NewDecimal(int64(t.Year()), 0), nil
+	case decadeField:
+		return NewDecimal(int64(t.Year()/10), 0), nil
+	case centuryField:
+		return NewDecimal(int64((t.Year()+99)/100), 0), nil
+	case millenniumField:
+		return NewDecimal(int64((t.Year()+999)/1000), 0), nil
+	case dowField:
+		return NewDecimal(int64(t.Weekday()), 0), nil
+	case isodowField:
+		return NewDecimal(int64(isoWeekday(t)), 0), nil
+	case doyField:
+		return NewDecimal(int64(t.YearDay()), 0), nil
+	case isoyearField:
+		year, _ := t.ISOWeek()
+		return NewDecimal(int64(year), 0), nil
+	case epochField:
+		return NewDecimal(t.UnixMicro(), 6), nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s' function does not support the field '%s'", ErrIllegalArguments, fn, field)
+}
+
+// extractFromInterval returns the value of the given field of the interval,
+// the epoch of an interval being its length in seconds taking years of 365.25 days and months of 30 days
+func extractFromInterval(fn string, i *Interval, field string) (*Decimal, error) {
+	minuteMicros := i.micros % microsPerMinute
+	years := i.months / 12
+
+	switch dateField(field) {
+	case microsecondsField:
+		return NewDecimal(minuteMicros, 0), nil
+	case millisecondsField:
+		return NewDecimal(minuteMicros, 3), nil
+	case secondField:
+		return NewDecimal(minuteMicros, 6), nil
+	case minuteField:
+		return NewDecimal(i.micros%microsPerHour/microsPerMinute, 0), nil
+	case hourField:
+		return NewDecimal(i.micros/microsPerHour, 0), nil
+	case dayField:
+		return NewDecimal(i.days, 0), nil
+	case monthField:
+		return NewDecimal(i.months%12, 0), nil
+	case quarterField:
+		return NewDecimal(i.months%12/3+1, 0), nil
+	case yearField:
+		return NewDecimal(years, 0), nil
+	case decadeField:
+		return NewDecimal(years/10, 0), nil
+	case centuryField:
+		return NewDecimal(years/100, 0), nil
+	case millenniumField:
+		return NewDecimal(years/1000, 0), nil
+	case epochField:
+		micros := years*(microsPerDay*365+microsPerDay/4) + (i.months%12*daysPerMonth+i.days)*microsPerDay + i.micros
+		return NewDecimal(micros, 6), nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s' function does not support the field '%s' for intervals", ErrIllegalArguments, fn, field)
+}
+
+// timestampAge returns the difference between two timestamps in years, months, days and time,
+// borrowing from the month of the earliest timestamp when the day of the month of the latest one is smaller
+func timestampAge(t1, t2 time.Time) *Interval {
+	if t1.Before(t2) {
+		return timestampAge(t2, t1).negate()
+	}
+
+	micros := int64(t1.Nanosecond()/1000) - int64(t2.Nanosecond()/1000)
+	seconds := int64(t1.Second() - t2.Second())
+	minutes := int64(t1.Minute() - t2.Minute())
+	hours := int64(t1.Hour() - t2.Hour())
+	days := int64(t1.Day() - t2.Day())
+	months := int64(t1.Month() - t2.Month())
+	years := int64(t1.Year() - t2.Year())
+
+	if micros < 0 {
+		micros += microsPerSecond
+		seconds--
+	}
+	if seconds < 0 {
+		seconds += 60
+		minutes--
+	}
+	if minutes < 0 {
+		minutes += 60
+		hours--
+	}
+	if hours < 0 {
+		hours += 24
+		days--
+	}
+	if days < 0 {
+		days += int64(daysIn(t2.Year(), t2.Month()))
+		months--
+	}
+	if months < 0 {
+		months += 12
+		years--
+	}
+
+	return &Interval{
+		months: years*12 + months,
+		days:   days,
+		micros: hours*microsPerHour + minutes*microsPerMinute + seconds*microsPerSecond + micros,
+	}
+}
+
+// template patterns supported by TO_CHAR, longer patterns must precede their prefixes
+var toCharPatterns = []string{
+	"HH24", "HH12", "HH", "MI", "SS", "MS", "US",
+	"A.M.", "P.M.", "AM", "PM",
+	"YYYY", "YYY", "YY", "Y", "IYYY",
+	"MONTH", "MON", "MM",
+	"DAY", "DY", "DDD", "DD", "D", "ID",
+	"IW", "WW", "W", "Q", "CC", "TZ",
+}
+
+// formatTimestamp formats the timestamp using the template patterns of PostgreSQL e.g. 'YYYY-MM-DD HH24:MI:SS',
+// the FM prefix suppresses padding and text enclosed in double quotes is copied as is
+func formatTimestamp(t time.Time, format string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(format); {
+		if format[i] == '"' {
+			end := strings.IndexByte(format[i+1:], '"')
+			if end < 0 {
+				b.WriteString(format[i+1:])
+				break
+			}
+
+			b.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		fillMode := false
+		if strings.HasPrefix(strings.ToUpper(format[i:]), "FM") {
+			fillMode = true
+			i += 2
+		}
+
+		pattern := ""
+		for _, p := range toCharPatterns {
+			if strings.HasPrefix(strings.ToUpper(format[i:]), p) {
+				pattern = p
+				break
+			}
+		}
+
+		if pattern == "" {
+			if !fillMode {
+				b.WriteByte(format[i])
+				i++
+			}
+			continue
+		}
+
+		b.WriteString(formatTimestampPattern(t, pattern, format[i:i+len(pattern)], fillMode))
+		i += len(pattern)
+	}
+
+	return b.String()
+}
+
+func formatTimestampPattern(t time.Time, pattern, src string, fillMode bool) string {
+	num := func(v, width int) string {
+		if fillMode {
+			return strconv.Itoa(v)
+		}
+		return fmt.Sprintf("%0*d", width, v)
+	}
+
+	// names keep the letter case of the pattern i.e. MONTH, Month or month
+	name := func(s string, width int) string {
+		switch {
+		case src == strings.ToUpper(src):
+			s = strings.ToUpper(s)
+		case src[1:] == strings.ToLower(src[1:]) && src[0] != strings.ToLower(src)[0]:
+		default:
+			s = strings.ToLower(s)
+		}
+
+		if fillMode {
+			return s
+		}
+		return fmt.Sprintf("%-*s", width, s)
+	}
+
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+
+	isoYear, isoWeek := t.ISOWeek()
+
+	switch pattern {
+	case "HH24":
+		return num(t.Hour(), 2)
+	case "HH12", "HH":
+		return num(hour12, 2)
+	case "MI":
+		return num(t.Minute(), 2)
+	case "SS":
+		return num(t.Second(), 2)
+	case "MS":
+		return num(t.Nanosecond()/int(time.Millisecond), 3)
+	case "US":
+		return num(t.Nanosecond()/int(time.Microsecond), 6)
+	case "AM", "PM", "A.M.", "P.M.":
+		meridiem := "AM"
+		if t.Hour() >= 12 {
+			meridiem = "PM"
+		}
+		if len(pattern) == 4 {
+			meridiem = meridiem[:1] + "." + meridiem[1:] + "."
+		}
+		if src == strings.ToLower(src) {
+			return strings.ToLower(meridiem)
+		}
+		return meridiem
+	case "YYYY":
+		return num(t.Year(), 4)
+	case "YYY":
+		return num(t.Year()%1000, 3)
+	case "YY":
+		return num(t.Year()%100, 2)
+	case "Y":
+		return strconv.Itoa(t.Year() % 10)
+	case "IYYY":
+		return num(isoYear, 4)
+	case "MONTH":
+		return name(t.Month().String(), 9)
+	case "MON":
+		return name(t.Month().String()[:3], 3)
+	case "MM":
+		return num(int(t.Month()), 2)
+	case "DAY":
+		return name(t.Weekday().String(), 9)
+	case "DY":
+		return name(t.Weekday().String()[:3], 3)
+	case "DDD":
+		return num(t.YearDay(), 3)
+	case "DD":
+		return num(t.Day(), 2)
+	case "D":
+		return strconv.Itoa(int(t.Weekday()) + 1)
+	case "ID":
+		return strconv.Itoa(isoWeekday(t))
+	case "IW":
+		return num(isoWeek, 2)
+	case "WW":
+		return num((t.YearDay()-1)/7+1, 2)
+	case "W":
+		return strconv.Itoa((t.Day()-1)/7 + 1)
+	case "Q":
+		return strconv.Itoa(int(t.Month()-1)/3 + 1)
+	case "CC":
+		return num((t.Year()+99)/100, 2)
+	case "TZ":
+		name, _ := t.Zone()
+		if src == strings.ToLower(src) {
+			return strings.ToLower(name)
+		}
+		return name
+	}
+	return src
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateTimestamp(t *testing.T) {
+	ts := time.Date(2024, 8, 15, 13, 45, 30, 123456789, time.UTC)
+
+	for _, d := range []struct {
+		field string
+		exp   time.Time
+	}{
+		{"microseconds", time.Date(2024, 8, 15, 13, 45, 30, 123456000, time.UTC)},
+		{"milliseconds", time.Date(2024, 8, 15, 13, 45, 30, 123000000, time.UTC)},
+		{"second", time.Date(2024, 8, 15, 13, 45, 30, 0, time.UTC)},
+		{"minute", time.Date(2024, 8, 15, 13, 45, 0, 0, time.UTC)},
+		{"HOUR", time.Date(2024, 8, 15, 13, 0, 0, 0, time.UTC)},
+		{"day", time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{"quarter", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"year", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"decade", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"century", time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"millennium", time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(d.field, func(t *testing.T) {
+			res, err := truncateTimestamp(ts, d.field)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, res)
+		})
+	}
+
+	_, err := truncateTimestamp(ts, "dow")
+	require.ErrorIs(t, err, ErrIllegalArguments)
+}
+
+func TestExtractFromTimestamp(t *testing.T) {
+	ts := time.Date(2021, 1, 3, 13, 45, 30, 500000000, time.UTC)
+
+	for _, d := range []struct {
+		field string
+		exp   string
+	}{
+		{"microseconds", "30500000"},
+		{"milliseconds", "30500.000"},
+		{"second", "30.500000"},
+		{"minute", "45"},
+		{"hour", "13"},
+		{"day", "3"},
+		{"week", "53"},
+		{"month", "1"},
+		{"quarter", "1"},
+		{"year", "2021"},
+		{"decade", "202"},
+		{"century", "21"},
+		{"millennium", "3"},
+		{"dow", "0"},
+		{"isodow", "7"},
+		{"doy", "3"},
+		{"isoyear", "2020"},
+		{"epoch", "1609681530.500000"},
+	} {
+		t.Run(d.field, func(t *testing.T) {
+			res, err := extractFromTimestamp(ExtractFnCall, ts, d.field)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, res.String())
+		})
+	}
+
+	_, err := extractFromTimestamp(ExtractFnCall, ts, "fortnight")
+	require.ErrorIs(t, err, ErrIllegalArguments)
+}
+
+func TestExtractFromInterval(t *testing.T) {
+	i, err := ParseInterval("1 year 2 months 3 days 04:05:06.5")
+	require.NoError(t, err)
+
+	for _, d := range []struct {
+		field string
+		exp   string
+	}{
+		{"second", "6.500000"},
+		{"minute", "5"},
+		{"hour", "4"},
+		{"day", "3"},
+		{"month", "2"},
+		{"year", "1"},
+		{"epoch", "37015506.500000"},
+	} {
+		t.Run(d.field, func(t *testing.T) {
+			res, err := extractFromInterval(ExtractFnCall, i, d.field)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, res.String())
+		})
+	}
+
+	_, err = extractFromInterval(ExtractFnCall, i, "dow")
+	require.ErrorIs(t, err, ErrIllegalArguments)
+}
+
+func TestTimestampAge(t *testing.T) {
+	for _, d := range []struct {
+		t1  time.Time
+		t2  time.Time
+		exp string
+	}{
+		{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), "00:00:00"},
+		{time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), time.Date(1957, 6, 13, 0, 0, 0, 0, time.UTC), "66 years 9 mons 27 days"},
+		{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), "1 mon 1 day"},
+		{time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC), time.Date(2024, 2, 28, 12, 0, 0, 0, time.UTC), "1 day 18:00:00"},
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "-1 mons -1 days"},
+	} {
+		t.Run(d.exp, func(t *testing.T) {
+			require.Equal(t, d.exp, timestampAge(d.t1, d.t2).String())
+		})
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	ts := time.Date(2024, 3, 5, 7, 8, 9, 123456000, time.UTC)
+
+	for _, d := range []struct {
+		format string
+		exp    string
+	}{
+		{"YYYY-MM-DD HH24:MI:SS", "2024-03-05 07:08:09"},
+		{"DD/MM/YY HH12:MI AM", "05/03/24 07:08 AM"},
+		{"HH24:MI:SS.MS", "07:08:09.123"},
+		{"HH24:MI:SS.US", "07:08:09.123456"},
+		{"Month", "March    "},
+		{"FMMonth FMDD, YYYY", "March 5, 2024"},
+		{"MONTH", "MARCH    "},
+		{"Dy, Mon DD", "Tue, Mar 05"},
+		{"DAY", "TUESDAY  "},
+		{"Q", "1"},
+		{"DDD", "065"},
+		{"\"week\" WW", "week 10"},
+		{"IYYY-IW", "2024-10"},
+		{"D", "3"},
+		{"pm", "am"},
+	} {
+		t.Run(d.format, func(t *testing.T) {
+			require.Equal(t, d.exp, formatTimestamp(ts, d.format))
+		})
+	}
+}
@@ -0,0 +1,456 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxDecimalPrecision is the maximum number of digits a DECIMAL value can hold
+	MaxDecimalPrecision = 38
+
+	// decimalDivScale is the minimum number of fractional digits kept by divisions
+	decimalDivScale = 16
+
+	// maximum absolute exponent accepted when parsing a DECIMAL value
+	maxDecimalExponent = 1000
+
+	// sign + exponent + two digits per byte
+	decimalKeyLen = 1 + 1 + (MaxDecimalPrecision+1)/2
+)
+
+const (
+	decimalKeyNegative byte = iota
+	decimalKeyZero
+	decimalKeyPositive
+)
+
+var bigTen = big.NewInt(10)
+
+// Decimal is an exact numeric value represented by an unscaled integer and a scale,
+// i.e. the value is val * 10^-scale
+type Decimal struct {
+	val   *big.Int
+	scale int
+}
+
+// NewDecimal returns the decimal value unscaled * 10^-scale
+func NewDecimal(unscaled int64, scale int) *Decimal {
+	d := &Decimal{val: big.NewInt(unscaled), scale: scale}
+	if scale < 0 {
+		return d.rescale(0)
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal value from its textual representation,
+// an optional exponent is accepted as in 1.5e3
+func ParseDecimal(s string) (*Decimal, error) {
+	str := strings.TrimSpace(s)
+
+	mantissa, exp := str, 0
+
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil || e > maxDecimalExponent || e < -maxDecimalExponent {
+			return nil, fmt.Errorf("%w: invalid DECIMAL value '%s'", ErrInvalidValue, s)
+		}
+		mantissa, exp = str[:i], e
+	}
+
+	neg := false
+	if len(mantissa) > 0 && (mantissa[0] == '-' || mantissa[0] == '+') {
+		neg = mantissa[0] == '-'
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+
+	digits := intPart + fracPart
+	if len(digits) == 0 {
+		return nil, fmt.Errorf("%w: invalid DECIMAL value '%s'", ErrInvalidValue, s)
+	}
+
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("%w: invalid DECIMAL value '%s'", ErrInvalidValue, s)
+		}
+	}
+
+	val, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		val.Neg(val)
+	}
+
+	d := &Decimal{val: val, scale: len(fracPart) - exp}
+	if d.scale < 0 {
+		return d.rescale(0), nil
+	}
+	return d, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the value with the given number of fractional digits,
+// rounding half away from zero when digits are dropped
+func (d *Decimal) rescale(scale int) *Decimal {
+	if scale == d.scale {
+		return d
+	}
+
+	if scale > d.scale {
+		return &Decimal{val: new(big.Int).Mul(d.val, pow10(scale-d.scale)), scale: scale}
+	}
+
+	return &Decimal{val: divRound(d.val, pow10(d.scale-scale)), scale: scale}
+}
+
+// divRound divides x by y rounding half away from zero
+func divRound(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+
+	r.Abs(r).Lsh(r, 1)
+	if r.Cmp(new(big.Int).Abs(y)) >= 0 {
+		if x.Sign()*y.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// digits returns the number of digits of the unscaled value
+func (d *Decimal) digits() int {
+	if d.val.Sign() == 0 {
+		return 1
+	}
+	return len(new(big.Int).Abs(d.val).String())
+}
+
+// fit adjusts the value to the precision and scale encoded in maxLen,
+// an unconstrained value keeps its own scale as long as it fits MaxDecimalPrecision digits
+func (d *Decimal) fit(maxLen int) (*Decimal, error) {
+	if maxLen <= 0 {
+		r := d
+		if r.scale > MaxDecimalPrecision {
+			r = r.rescale(MaxDecimalPrecision)
+		}
+
+		for r.digits() > MaxDecimalPrecision {
+			if r.scale == 0 {
+				return nil, fmt.Errorf("%w: value exceeds %d digits", ErrNumericFieldOverflow, MaxDecimalPrecision)
+			}
+			r = r.rescale(r.scale - 1)
+		}
+		return r, nil
+	}
+
+	precision, scale := decimalPrecision(maxLen), decimalScale(maxLen)
+
+	r := d.rescale(scale)
+	if r.val.Sign() != 0 && r.digits() > precision {
+		return nil, fmt.Errorf("%w: value must be smaller than 10^%d for DECIMAL(%d,%d)", ErrNumericFieldOverflow, precision-scale, precision, scale)
+	}
+	return r, nil
+}
+
+func maxScale(s1, s2 int) int {
+	if s1 > s2 {
+		return s1
+	}
+	return s2
+}
+
+func (d *Decimal) cmp(o *Decimal) int {
+	scale := maxScale(d.scale, o.scale)
+	return d.rescale(scale).val.Cmp(o.rescale(scale).val)
+}
+
+func (d *Decimal) add(o *Decimal) (*Decimal, error) {
+	scale := maxScale(d.scale, o.scale)
+	return (&Decimal{val: new(big.Int).Add(d.rescale(scale).val, o.rescale(scale).val), scale: scale}).fit(0)
+}
+
+func (d *Decimal) sub(o *Decimal) (*Decimal, error) {
+	scale := maxScale(d.scale, o.scale)
+	return (&Decimal{val: new(big.Int).Sub(d.rescale(scale).val, o.rescale(scale).val), scale: scale}).fit(0)
+}
+
+func (d *Decimal) mul(o *Decimal) (*Decimal, error) {
+	return (&Decimal{val: new(big.Int).Mul(d.val, o.val), scale: d.scale + o.scale}).fit(0)
+}
+
+func (d *Decimal) div(o *Decimal) (*Decimal, error) {
+	if o.val.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	scale := maxScale(maxScale(d.scale, o.scale), decimalDivScale)
+
+	// d.val * 10^(scale + o.scale - d.scale) / o.val has the wanted scale
+	num := new(big.Int).Mul(d.val, pow10(scale+o.scale-d.scale))
+
+	return (&Decimal{val: divRound(num, o.val), scale: scale}).fit(0)
+}
+
+func (d *Decimal) mod(o *Decimal) (*Decimal, error) {
+	if o.val.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	scale := maxScale(d.scale, o.scale)
+	return &Decimal{val: new(big.Int).Rem(d.rescale(scale).val, o.rescale(scale).val), scale: scale}, nil
+}
+
+func (d *Decimal) abs() *Decimal {
+	if d.val.Sign() >= 0 {
+		return d
+	}
+	return &Decimal{val: new(big.Int).Neg(d.val), scale: d.scale}
+}
+
+// round rounds the value half away from zero to the given number of fractional digits,
+// a negative scale rounds to the left of the decimal point, i.e. round(1250, -2) = 1300
+func (d *Decimal) round(scale int) *Decimal {
+	if scale >= 0 {
+		return d.rescale(scale)
+	}
+
+	q := divRound(d.val, pow10(d.scale-scale))
+	return &Decimal{val: q.Mul(q, pow10(-scale)), scale: 0}
+}
+
+// floor returns the greatest integral value not greater than the value
+func (d *Decimal) floor() *Decimal {
+	if d.scale <= 0 {
+		return d
+	}
+
+	q, _ := new(big.Int).DivMod(d.val, pow10(d.scale), new(big.Int))
+	return &Decimal{val: q, scale: 0}
+}
+
+// ceil returns the smallest integral value not less than the value
+func (d *Decimal) ceil() *Decimal {
+	if d.scale <= 0 {
+		return d
+	}
+
+	q, m := new(big.Int).DivMod(d.val, pow10(d.scale), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return &Decimal{val: q, scale: 0}
+}
+
+// Float64 returns the nearest float64 value
+func (d *Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Int64 returns the value rounded to an integer,
+// it returns false if the result doesn't fit in an int64
+func (d *Decimal) Int64() (int64, bool) {
+	i := d.rescale(0).val
+	if !i.IsInt64() {
+		return 0, false
+	}
+	return i.Int64(), true
+}
+
+func (d *Decimal) Type() SQLValueType {
+	return DecimalType
+}
+
+func (d *Decimal) IsNull() bool {
+	return false
+}
+
+func (d *Decimal) String() string {
+	s := new(big.Int).Abs(d.val).String()
+
+	if d.scale > 0 {
+		if len(s) <= d.scale {
+			s = strings.Repeat("0", d.scale-len(s)+1) + s
+		}
+		s = s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+	}
+
+	if d.val.Sign() < 0 {
+		return "-" + s
+	}
+	return s
+}
+
+func (d *Decimal) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return DecimalType, nil
+}
+
+func (d *Decimal) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != DecimalType && t != JSONType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, DecimalType, t)
+	}
+	return nil
+}
+
+func (d *Decimal) selectors() []Selector {
+	return nil
+}
+
+func (d *Decimal) substitute(params map[string]interface{}) (ValueExp, error) {
+	return d, nil
+}
+
+func (d *Decimal) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return d, nil
+}
+
+func (d *Decimal) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return d
+}
+
+func (d *Decimal) isConstant() bool {
+	return true
+}
+
+func (d *Decimal) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+// RawValue returns the textual representation of the value, which preserves its exactness
+func (d *Decimal) RawValue() interface{} {
+	return d.String()
+}
+
+func (d *Decimal) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
+	}
+
+	if val.Type() == JSONType || val.Type() == Float64Type {
+		res, err := val.Compare(d)
+		return -res, err
+	}
+
+	convVal, err := mayApplyImplicitConversion(val.RawValue(), DecimalType)
+	if err != nil {
+		return 0, err
+	}
+
+	s, ok := convVal.(string)
+	if !ok {
+		return 0, ErrNotComparableValues
+	}
+
+	rval, err := ParseDecimal(s)
+	if err != nil {
+		return 0, ErrNotComparableValues
+	}
+
+	return d.cmp(rval), nil
+}
+
+// encodeAsKey encodes the value in a way that preserves the numeric order regardless of the scale,
+// as {sign}{exponent}{digits} where the value is 0.{digits} * 10^exponent
+func (d *Decimal) encodeAsKey() []byte {
+	var encv [decimalKeyLen]byte
+
+	if d.val.Sign() == 0 {
+		encv[0] = decimalKeyZero
+		return encv[:]
+	}
+
+	digits := new(big.Int).Abs(d.val).String()
+	exp := len(digits) - d.scale
+	digits = strings.TrimRight(digits, "0")
+
+	encv[1] = byte(exp + math.MaxInt8 + 1)
+
+	for i := 0; i < len(digits); i++ {
+		n := digits[i] - '0'
+		if i%2 == 0 {
+			n *= 10
+		}
+		encv[2+i/2] += n
+	}
+
+	if d.val.Sign() > 0 {
+		encv[0] = decimalKeyPositive
+		return encv[:]
+	}
+
+	// the order of negative values is reversed
+	encv[0] = decimalKeyNegative
+	for i := 1; i < len(encv); i++ {
+		encv[i] = ^encv[i]
+	}
+	return encv[:]
+}
+
+func decimalMaxLen(precision, scale int) int {
+	return precision<<8 | scale
+}
+
+func decimalPrecision(maxLen int) int {
+	return maxLen >> 8
+}
+
+func decimalScale(maxLen int) int {
+	return maxLen & 0xff
+}
+
+func validDecimalMaxLen(maxLen int) bool {
+	if maxLen == 0 {
+		return true
+	}
+
+	precision, scale := decimalPrecision(maxLen), decimalScale(maxLen)
+	return precision >= 1 && precision <= MaxDecimalPrecision && scale <= precision
+}
+
+func decimalTypeString(maxLen int) string {
+	if maxLen == 0 {
+		return string(DecimalType)
+	}
+	return fmt.Sprintf("%s(%d,%d)", DecimalType, decimalPrecision(maxLen), decimalScale(maxLen))
+}
+
+// decimalValue converts a raw value into a decimal
+func decimalValue(val interface{}) (*Decimal, error) {
+	convVal, err := mayApplyImplicitConversion(val, DecimalType)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := convVal.(string)
+	if !ok {
+		return nil, fmt.Errorf("value is not a decimal: %w", ErrInvalidValue)
+	}
+	return ParseDecimal(s)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, d := range []struct {
+		s   string
+		exp string
+	}{
+		{"0", "0"},
+		{"-0.00", "0.00"},
+		{"12.50", "12.50"},
+		{"+12.5", "12.5"},
+		{"-0.05", "-0.05"},
+		{".5", "0.5"},
+		{"7.", "7"},
+		{"1.5e3", "1500"},
+		{"15E-3", "0.015"},
+		{" 42 ", "42"},
+	} {
+		t.Run(d.s, func(t *testing.T) {
+			v, err := ParseDecimal(d.s)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, v.String())
+		})
+	}
+
+	for _, s := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1e1001", "0x10"} {
+		t.Run(fmt.Sprintf("invalid %q", s), func(t *testing.T) {
+			_, err := ParseDecimal(s)
+			require.ErrorIs(t, err, ErrInvalidValue)
+		})
+	}
+}
+
+func TestDecimalFit(t *testing.T) {
+	for _, d := range []struct {
+		s         string
+		precision int
+		scale     int
+		exp       string
+	}{
+		{"12.5", 10, 2, "12.50"},
+		{"1.005", 10, 2, "1.01"},
+		{"-1.005", 10, 2, "-1.01"},
+		{"1.004", 10, 2, "1.00"},
+		{"99.994", 4, 2, "99.99"},
+		{"2.5", 1, 0, "3"},
+		{"0.001", 3, 3, "0.001"},
+	} {
+		t.Run(fmt.Sprintf("%s as DECIMAL(%d,%d)", d.s, d.precision, d.scale), func(t *testing.T) {
+			v, err := ParseDecimal(d.s)
+			require.NoError(t, err)
+
+			fitted, err := v.fit(decimalMaxLen(d.precision, d.scale))
+			require.NoError(t, err)
+			require.Equal(t, d.exp, fitted.String())
+		})
+	}
+
+	for _, d := range []struct {
+		s         string
+		precision int
+		scale     int
+	}{
+		{"100", 4, 2},
+		{"99.995", 4, 2},
+		{"-1000", 3, 0},
+	} {
+		t.Run(fmt.Sprintf("%s overflows DECIMAL(%d,%d)", d.s, d.precision, d.scale), func(t *testing.T) {
+			v, err := ParseDecimal(d.s)
+			require.NoError(t, err)
+
+			_, err = v.fit(decimalMaxLen(d.precision, d.scale))
+			require.ErrorIs(t, err, ErrNumericFieldOverflow)
+		})
+	}
+
+	t.Run("unconstrained values are limited to the max precision", func(t *testing.T) {
+		v, err := ParseDecimal("1." + strings.Repeat("3", 50))
+		require.NoError(t, err)
+
+		fitted, err := v.fit(0)
+		require.NoError(t, err)
+		require.Equal(t, "1."+strings.Repeat("3", MaxDecimalPrecision-1), fitted.String())
+
+		v, err = ParseDecimal("1e40")
+		require.NoError(t, err)
+
+		_, err = v.fit(0)
+		require.ErrorIs(t, err, ErrNumericFieldOverflow)
+	})
+}
+
+func TestDecimalCompare(t *testing.T) {
+	for _, d := range []struct {
+		l   TypedValue
+		r   TypedValue
+		exp int
+	}{
+		{NewDecimal(1250, 2), NewDecimal(125, 1), 0},
+		{NewDecimal(1250, 2), NewDecimal(1251, 2), -1},
+		{NewDecimal(1250, 2), &Integer{val: 12}, 1},
+		{&Integer{val: 13}, NewDecimal(1250, 2), 1},
+		{NewDecimal(1250, 2), &Float64{val: 12.5}, 0},
+		{&Float64{val: 12.25}, NewDecimal(1250, 2), -1},
+		{NewDecimal(1250, 2), &Varchar{val: "12.5"}, 0},
+		{NewDecimal(1250, 2), &NullValue{t: DecimalType}, 1},
+	} {
+		t.Run(fmt.Sprintf("%s vs %s", d.l, d.r), func(t *testing.T) {
+			res, err := d.l.Compare(d.r)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, res)
+		})
+	}
+
+	_, err := NewDecimal(1, 0).Compare(&Bool{val: true})
+	require.ErrorIs(t, err, ErrNotComparableValues)
+}
+
+func TestDecimalKeyEncodingOrder(t *testing.T) {
+	values := []string{
+		"-1e30",
+		"-1000.5",
+		"-1000",
+		"-999.99",
+		"-1",
+		"-0.5",
+		"-0.05",
+		"-0.0001",
+		"0",
+		"0.0001",
+		"0.05",
+		"0.1",
+		"0.12",
+		"0.123",
+		"1",
+		"1.0000000001",
+		"9.99",
+		"10",
+		"99999999999999999999999999999999999999",
+	}
+
+	var prev []byte
+
+	for _, s := range values {
+		enc, n, err := EncodeRawValueAsKey(s, DecimalType, 0)
+		require.NoError(t, err)
+		require.Equal(t, decimalKeyLen, n)
+		require.Len(t, enc, 1+decimalKeyLen)
+
+		if prev != nil {
+			require.Equal(t, 1, bytes.Compare(enc, prev), "%s is not encoded after the previous value", s)
+		}
+		prev = enc
+	}
+
+	t.Run("keys do not depend on the scale", func(t *testing.T) {
+		enc1, _, err := EncodeRawValueAsKey("12.5", DecimalType, decimalMaxLen(10, 2))
+		require.NoError(t, err)
+
+		enc2, _, err := EncodeRawValueAsKey("12.500", DecimalType, decimalMaxLen(10, 2))
+		require.NoError(t, err)
+
+		require.Equal(t, enc1, enc2)
+	})
+}
+
+func TestDecimalValueEncoding(t *testing.T) {
+	enc, err := EncodeRawValue("12.5", DecimalType, decimalMaxLen(10, 2), false)
+	require.NoError(t, err)
+
+	v, n, err := DecodeValue(enc, DecimalType)
+	require.NoError(t, err)
+	require.Equal(t, len(enc), n)
+	require.Equal(t, "12.50", v.RawValue())
+
+	// values are encoded without a max length when sorted
+	enc, err = EncodeNullableValue(NewDecimal(-1250, 3), DecimalType, -1)
+	require.NoError(t, err)
+
+	v, _, err = DecodeNullableValue(enc, DecimalType)
+	require.NoError(t, err)
+	require.Equal(t, "-1.250", v.RawValue())
+
+	_, err = EncodeRawValue(int64(1000), DecimalType, decimalMaxLen(4, 2), false)
+	require.ErrorIs(t, err, ErrNumericFieldOverflow)
+
+	_, err = EncodeRawValue("abc", DecimalType, 0, false)
+	require.ErrorIs(t, err, ErrUnsupportedCast)
+}
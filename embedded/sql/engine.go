@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/codenotary/immudb/embedded/store"
 )
@@ -31,18 +32,28 @@ var (
 	ErrIllegalArguments                       = store.ErrIllegalArguments
 	ErrMultiIndexingNotEnabled                = fmt.Errorf("%w: multi-indexing must be enabled", store.ErrIllegalState)
 	ErrParsingError                           = errors.New("parsing error")
-	ErrDDLorDMLTxOnly                         = errors.New("transactions can NOT combine DDL and DML statements")
 	ErrUnspecifiedMultiDBHandler              = fmt.Errorf("%w: unspecified multidbHanlder", store.ErrIllegalState)
 	ErrDatabaseDoesNotExist                   = errors.New("database does not exist")
 	ErrDatabaseAlreadyExists                  = errors.New("database already exists")
 	ErrTableAlreadyExists                     = errors.New("table already exists")
 	ErrTableDoesNotExist                      = errors.New("table does not exist")
+	ErrViewAlreadyExists                      = errors.New("view already exists")
+	ErrViewDoesNotExist                       = errors.New("view does not exist")
+	ErrSequenceAlreadyExists                  = errors.New("sequence already exists")
+	ErrSequenceDoesNotExist                   = errors.New("sequence does not exist")
+	ErrSequenceLimitReached                   = errors.New("sequence limit reached")
+	ErrSequenceNotUsed                        = errors.New("sequence has not been used yet")
 	ErrColumnDoesNotExist                     = errors.New("column does not exist")
 	ErrColumnAlreadyExists                    = errors.New("column already exists")
 	ErrCannotDropColumn                       = errors.New("cannot drop column")
+	ErrCannotAlterColumn                      = errors.New("cannot alter column")
+	ErrCannotDropTable                        = errors.New("cannot drop table")
+	ErrCannotDropIndex                        = errors.New("cannot drop index")
+	ErrCannotDropView                         = errors.New("cannot drop view")
 	ErrSameOldAndNewNames                     = errors.New("same old and new names")
 	ErrColumnNotIndexed                       = errors.New("column is not indexed")
 	ErrFunctionDoesNotExist                   = errors.New("function does not exist")
+	ErrFunctionAlreadyExists                  = errors.New("function already exists")
 	ErrLimitedKeyType                         = errors.New("indexed key of unsupported type or exceeded length")
 	ErrLimitedAutoIncrement                   = errors.New("only INTEGER single-column primary keys can be set as auto incremental")
 	ErrLimitedMaxLen                          = errors.New("only VARCHAR and BLOB types support max length")
@@ -50,6 +61,8 @@ var (
 	ErrInvalidColumn                          = errors.New("invalid column")
 	ErrInvalidCheckConstraint                 = errors.New("invalid check constraint")
 	ErrCheckConstraintViolation               = errors.New("check constraint violation")
+	ErrInvalidForeignKeyConstraint            = errors.New("invalid foreign key constraint")
+	ErrForeignKeyConstraintViolation          = errors.New("foreign key constraint violation")
 	ErrReservedWord                           = errors.New("reserved word")
 	ErrNoPrimaryKey                           = errors.New("no primary key specified")
 	ErrPKCanNotBeNull                         = errors.New("primary key can not be null")
@@ -57,10 +70,14 @@ var (
 	ErrMultiplePrimaryKeys                    = errors.New("multiple primary keys are not allowed")
 	ErrNotNullableColumnCannotBeNull          = errors.New("not nullable column can not be null")
 	ErrNewColumnMustBeNullable                = errors.New("new column must be nullable")
+	ErrInvalidDefaultValue                    = errors.New("invalid default value")
+	ErrInvalidGeneratedColumn                 = errors.New("invalid generated column")
+	ErrGeneratedColumnCannotBeSet             = errors.New("generated column can not be set")
 	ErrIndexAlreadyExists                     = errors.New("index already exists")
 	ErrMaxNumberOfColumnsInIndexExceeded      = errors.New("number of columns in multi-column index exceeded")
 	ErrIndexNotFound                          = errors.New("index not found")
 	ErrConstraintNotFound                     = errors.New("constraint not found")
+	ErrConstraintAlreadyExists                = errors.New("constraint already exists")
 	ErrInvalidNumberOfValues                  = errors.New("invalid number of values provided")
 	ErrInvalidValue                           = errors.New("invalid value provided")
 	ErrInferredMultipleTypes                  = errors.New("inferred multiple types")
@@ -87,6 +104,7 @@ var (
 	ErrNoOngoingTx                            = errors.New("no ongoing transaction")
 	ErrNonTransactionalStmt                   = errors.New("non transactional statement")
 	ErrDivisionByZero                         = errors.New("division by zero")
+	ErrNumericFieldOverflow                   = fmt.Errorf("%w: numeric field overflow", ErrInvalidValue)
 	ErrMissingParameter                       = errors.New("missing parameter")
 	ErrUnsupportedParameter                   = errors.New("unsupported parameter")
 	ErrDuplicatedParameters                   = errors.New("duplicated parameters")
@@ -97,10 +115,19 @@ var (
 	ErrUnsupportedCast                        = fmt.Errorf("%w: unsupported cast", ErrInvalidValue)
 	ErrColumnMismatchInUnionStmt              = errors.New("column mismatch in union statement")
 	ErrCannotIndexJson                        = errors.New("cannot index column of type JSON")
+	ErrCannotIndexArray                       = errors.New("cannot index column of array type")
+	ErrIntervalColumnNotSupported             = errors.New("columns of type INTERVAL are not supported")
+	ErrArrayNullElement                       = fmt.Errorf("%w: array elements can not be NULL", ErrInvalidValue)
 	ErrInvalidTxMetadata                      = errors.New("invalid transaction metadata")
 	ErrAccessDenied                           = errors.New("access denied")
+	ErrInvalidWindowFnUsage                   = errors.New("window functions are only allowed in the select list and ORDER BY clause")
 )
 
+// ErrDDLorDMLTxOnly is no longer returned, DDL and DML statements can be combined in the same transaction.
+//
+// Deprecated: kept for backward compatibility only.
+var ErrDDLorDMLTxOnly = errors.New("transactions can NOT combine DDL and DML statements")
+
 var MaxKeyLen = 512
 
 const (
@@ -119,8 +146,24 @@ type Engine struct {
 	autocommit                    bool
 	lazyIndexConstraintValidation bool
 	parseTxMetadata               func([]byte) (map[string]interface{}, error)
+	indexBackfillProgressInterval int
+	indexBackfillProgressFunc     IndexBackfillProgressFunc
 	multidbHandler                MultiDBHandler
 	tableResolvers                map[string]TableResolver
+
+	userFunctions    map[string]*userFunction
+	userFunctionsMtx sync.RWMutex
+
+	pendingIndexes    map[string]*pendingIndex // store indexes initialized by transactions not yet committed
+	pendingIndexesMtx sync.Mutex
+}
+
+// pendingIndex is a store index initialized for a table created or indexed within a transaction,
+// it's shared with concurrent transactions defining an identical index with the same identifier
+type pendingIndex struct {
+	signature string
+	holders   map[*SQLTx]struct{}
+	committed bool
 }
 
 type MultiDBHandler interface {
@@ -170,7 +213,10 @@ func NewEngine(st *store.ImmuStore, opts *Options) (*Engine, error) {
 		autocommit:                    opts.autocommit,
 		lazyIndexConstraintValidation: opts.lazyIndexConstraintValidation,
 		parseTxMetadata:               opts.parseTxMetadata,
+		indexBackfillProgressInterval: opts.indexBackfillProgressInterval,
+		indexBackfillProgressFunc:     opts.indexBackfillProgressFunc,
 		multidbHandler:                opts.multidbHandler,
+		pendingIndexes:                make(map[string]*pendingIndex),
 	}
 
 	copy(e.prefix, opts.prefix)
@@ -188,6 +234,13 @@ func NewEngine(st *store.ImmuStore, opts *Options) (*Engine, error) {
 		e.registerTableResolver(r.Table(), r)
 	}
 
+	for _, f := range opts.userFunctions {
+		err := e.RegisterFunction(f.name, f.fn, f.deterministic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// TODO: find a better way to handle parsing errors
 	yyErrorVerbose = true
 
@@ -237,61 +290,15 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 	}
 
 	for _, table := range catalog.GetTables() {
-		primaryIndex := table.primaryIndex
-
-		rowEntryPrefix := MapKey(
-			e.prefix,
-			RowPrefix,
-			EncodeID(DatabaseID),
-			EncodeID(table.id),
-			EncodeID(primaryIndex.id),
-		)
-
-		mappedPKEntryPrefix := MapKey(
-			e.prefix,
-			MappedPrefix,
-			EncodeID(table.id),
-			EncodeID(primaryIndex.id),
-		)
-
-		err = e.store.InitIndexing(&store.IndexSpec{
-			SourcePrefix: rowEntryPrefix,
-
-			TargetEntryMapper: indexEntryMapperFor(primaryIndex, primaryIndex),
-			TargetPrefix:      mappedPKEntryPrefix,
-
-			InjectiveMapping: true,
-		})
-		if err != nil && !errors.Is(err, store.ErrIndexAlreadyInitialized) {
+		err = e.checkFunctionsOf(table)
+		if err != nil {
+			tx.Cancel()
 			return nil, err
 		}
 
-		for _, index := range table.indexes {
-			if index.IsPrimary() {
-				continue
-			}
-
-			mappedEntryPrefix := MapKey(
-				e.prefix,
-				MappedPrefix,
-				EncodeID(table.id),
-				EncodeID(index.id),
-			)
-
-			err = e.store.InitIndexing(&store.IndexSpec{
-				SourcePrefix:      rowEntryPrefix,
-				SourceEntryMapper: indexEntryMapperFor(primaryIndex, primaryIndex),
-				TargetEntryMapper: indexEntryMapperFor(index, primaryIndex),
-				TargetPrefix:      mappedEntryPrefix,
-
-				InjectiveMapping: true,
-			})
-			if errors.Is(err, store.ErrIndexAlreadyInitialized) {
-				continue
-			}
-			if err != nil {
-				return nil, err
-			}
+		_, err = e.initTableIndexing(table)
+		if err != nil {
+			return nil, err
 		}
 
 		if table.autoIncrementPK {
@@ -328,10 +335,192 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 	}, nil
 }
 
+// initPendingTableIndexing initializes the store indexes of a table created or indexed within a transaction,
+// returning the prefixes of the indexes held by it until it's either committed or cancelled.
+// Concurrent transactions may assign the same identifier to a table or an index, the store index is
+// shared when both definitions are identical and discarded only if none of them gets committed.
+func (e *Engine) initPendingTableIndexing(sqlTx *SQLTx, table *Table) (held [][]byte, err error) {
+	e.pendingIndexesMtx.Lock()
+	defer e.pendingIndexesMtx.Unlock()
+
+	signatures := make(map[string]string, len(table.indexes))
+
+	for _, index := range table.indexes {
+		prefix := string(e.mappedIndexPrefix(table, index))
+		signature := indexSignature(index, table.primaryIndex)
+
+		pending, ok := e.pendingIndexes[prefix]
+		if !ok {
+			signatures[prefix] = signature
+			continue
+		}
+
+		if _, holder := pending.holders[sqlTx]; holder {
+			continue
+		}
+
+		if pending.signature != signature {
+			return nil, fmt.Errorf("%w: table '%s' is being concurrently created or altered", store.ErrTxReadConflict, table.name)
+		}
+
+		pending.holders[sqlTx] = struct{}{}
+		held = append(held, []byte(prefix))
+	}
+
+	initialized, err := e.initTableIndexing(table)
+
+	for _, prefix := range initialized {
+		e.pendingIndexes[string(prefix)] = &pendingIndex{
+			signature: signatures[string(prefix)],
+			holders:   map[*SQLTx]struct{}{sqlTx: {}},
+		}
+	}
+
+	return append(held, initialized...), err
+}
+
+// releasePendingIndexes releases the indexes held by a transaction,
+// those not held by any other transaction are deleted unless one of the holders was committed
+func (e *Engine) releasePendingIndexes(sqlTx *SQLTx, prefixes [][]byte, committed bool) {
+	e.pendingIndexesMtx.Lock()
+	defer e.pendingIndexesMtx.Unlock()
+
+	for _, prefix := range prefixes {
+		pending, ok := e.pendingIndexes[string(prefix)]
+		if !ok {
+			continue
+		}
+
+		if _, holder := pending.holders[sqlTx]; !holder {
+			continue
+		}
+
+		delete(pending.holders, sqlTx)
+
+		pending.committed = pending.committed || committed
+
+		if len(pending.holders) > 0 {
+			continue
+		}
+
+		delete(e.pendingIndexes, string(prefix))
+
+		if !pending.committed {
+			e.store.DeleteIndex(prefix)
+		}
+	}
+}
+
+// indexSignature describes the definitions the entries of an index are mapped with
+func indexSignature(index, primaryIndex *Index) string {
+	var sb strings.Builder
+
+	for _, col := range index.table.cols {
+		fmt.Fprintf(&sb, "%d:%s:%d", col.id, col.colType, col.maxLen)
+
+		if col.jsonCol != nil {
+			fmt.Fprintf(&sb, ":%d:%s", col.jsonCol.id, strings.Join(col.jsonFields, "."))
+		}
+
+		sb.WriteByte(';')
+	}
+
+	for _, idx := range []*Index{index, primaryIndex} {
+		sb.WriteByte('|')
+
+		for _, col := range idx.cols {
+			fmt.Fprintf(&sb, "%d;", col.id)
+		}
+	}
+
+	return sb.String()
+}
+
+func (e *Engine) mappedIndexPrefix(table *Table, index *Index) []byte {
+	return MapKey(
+		e.prefix,
+		MappedPrefix,
+		EncodeID(table.id),
+		EncodeID(index.id),
+	)
+}
+
+// initTableIndexing initializes the store indexes of the table which were not yet initialized,
+// returning the prefixes of the newly initialized ones
+func (e *Engine) initTableIndexing(table *Table) (initialized [][]byte, err error) {
+	primaryIndex := table.primaryIndex
+
+	rowEntryPrefix := MapKey(
+		e.prefix,
+		RowPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(table.id),
+		EncodeID(primaryIndex.id),
+	)
+
+	mappedPKEntryPrefix := e.mappedIndexPrefix(table, primaryIndex)
+
+	err = e.store.InitIndexing(&store.IndexSpec{
+		SourcePrefix: rowEntryPrefix,
+
+		TargetEntryMapper: indexEntryMapperFor(primaryIndex, primaryIndex),
+		TargetPrefix:      mappedPKEntryPrefix,
+
+		InjectiveMapping: true,
+	})
+	if err == nil {
+		initialized = append(initialized, mappedPKEntryPrefix)
+	} else if !errors.Is(err, store.ErrIndexAlreadyInitialized) {
+		return initialized, err
+	}
+
+	for _, index := range table.indexes {
+		if index.IsPrimary() {
+			continue
+		}
+
+		mappedEntryPrefix := e.mappedIndexPrefix(table, index)
+
+		err = e.store.InitIndexing(&store.IndexSpec{
+			SourcePrefix:      rowEntryPrefix,
+			SourceEntryMapper: indexEntryMapperFor(primaryIndex, primaryIndex),
+			TargetEntryMapper: indexEntryMapperFor(index, primaryIndex),
+			TargetPrefix:      mappedEntryPrefix,
+
+			InjectiveMapping: true,
+		})
+		if errors.Is(err, store.ErrIndexAlreadyInitialized) {
+			continue
+		}
+		if err != nil {
+			return initialized, err
+		}
+
+		initialized = append(initialized, mappedEntryPrefix)
+	}
+	return initialized, nil
+}
+
 func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 	// value={count (colID valLen val)+})
 	// key=M.{tableID}{indexID}({null}({val}{padding}{valLen})?)+({pkVal}{padding}{pkValLen})+
 
+	// only the values of the indexed columns are decoded, the remaining ones may have been
+	// encoded with a type other than the one of the column when its type was altered
+	requiredCols := make(map[uint32]struct{}, len(index.cols)+len(primaryIndex.cols))
+
+	for _, col := range index.cols {
+		requiredCols[col.id] = struct{}{}
+
+		if col.jsonCol != nil {
+			requiredCols[col.jsonCol.id] = struct{}{}
+		}
+	}
+
+	for _, col := range primaryIndex.cols {
+		requiredCols[col.id] = struct{}{}
+	}
+
 	valueExtractor := func(value []byte, valuesByColID map[uint32]TypedValue) error {
 		voff := 0
 
@@ -346,8 +535,10 @@ func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 			colID := binary.BigEndian.Uint32(value[voff:])
 			voff += EncIDLen
 
+			_, required := requiredCols[colID]
+
 			col, err := index.table.GetColumnByID(colID)
-			if errors.Is(err, ErrColumnDoesNotExist) {
+			if errors.Is(err, ErrColumnDoesNotExist) || (err == nil && !required) {
 				vlen := int(binary.BigEndian.Uint32(value[voff:]))
 				voff += EncLenLen + vlen
 				continue
@@ -385,7 +576,7 @@ func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 		}
 
 		for i, col := range index.cols {
-			encKey, _, err := EncodeValueAsKey(valuesByColID[col.id], col.Type(), col.MaxLen())
+			encKey, _, err := EncodeValueAsKey(col.indexedValue(valuesByColID), col.Type(), col.MaxLen())
 			if err != nil {
 				return nil, err
 			}
@@ -404,6 +595,12 @@ func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 	}
 }
 
+func (e *Engine) notifyIndexBackfillProgress(index *Index, validatedRows int) {
+	if e.indexBackfillProgressFunc != nil {
+		e.indexBackfillProgressFunc(index.Name(), validatedRows)
+	}
+}
+
 func (e *Engine) Exec(ctx context.Context, tx *SQLTx, sql string, params map[string]interface{}) (ntx *SQLTx, committedTxs []*SQLTx, err error) {
 	stmts, err := ParseSQL(strings.NewReader(sql))
 	if err != nil {
@@ -741,6 +938,33 @@ func (e *Engine) GetPrefix() []byte {
 	return e.prefix
 }
 
+// TableRowsPrefix returns the prefix shared by the keys under which the rows of a table are stored
+func (e *Engine) TableRowsPrefix(ctx context.Context, tableName string) ([]byte, error) {
+	catalog, err := e.Catalog(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := catalog.GetTableByName(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapKey(
+		e.prefix,
+		RowPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(table.id),
+		EncodeID(PKIndexID),
+	), nil
+}
+
+// TablesCatalogPrefix returns the prefix shared by the keys under which tables are registered in the catalog,
+// tables being created, dropped or renamed within a transaction can be detected by checking its entries
+func (e *Engine) TablesCatalogPrefix() []byte {
+	return MapKey(e.prefix, catalogTablePrefix)
+}
+
 func (e *Engine) tableResolveFor(tableName string) TableResolver {
 	if e.tableResolvers == nil {
 		return nil
@@ -754,3 +978,97 @@ func (e *Engine) registerTableResolver(tableName string, r TableResolver) {
 	}
 	e.tableResolvers[tableName] = r
 }
+
+// RegisterFunction makes fn callable by name from the statements executed by the engine.
+// Names are case insensitive and can neither collide with builtin functions nor with reserved words.
+// Only deterministic functions, whose result solely depends on their arguments,
+// can be used in check constraints and generated columns.
+//
+// Functions are not persisted: the catalog only stores the expressions calling them.
+// Every function referenced by a check constraint, a default value or a generated column must therefore be
+// registered again, with the same behaviour, whenever an engine is opened over the same data.
+// Until then, transactions can not be started and fail with ErrFunctionDoesNotExist.
+func (e *Engine) RegisterFunction(name string, fn Function, deterministic bool) error {
+	if fn == nil {
+		return fmt.Errorf("%w: nil function", ErrIllegalArguments)
+	}
+
+	if !isFunctionName(name) {
+		return fmt.Errorf("%w: invalid function name '%s'", ErrIllegalArguments, name)
+	}
+
+	fnName := strings.ToUpper(name)
+
+	if _, isBuiltin := builtinFunctions[fnName]; isBuiltin {
+		return fmt.Errorf("%w: '%s' is a builtin function", ErrFunctionAlreadyExists, name)
+	}
+
+	e.userFunctionsMtx.Lock()
+	defer e.userFunctionsMtx.Unlock()
+
+	if _, exists := e.userFunctions[fnName]; exists {
+		return fmt.Errorf("%w (%s)", ErrFunctionAlreadyExists, name)
+	}
+
+	if e.userFunctions == nil {
+		e.userFunctions = make(map[string]*userFunction)
+	}
+
+	e.userFunctions[fnName] = &userFunction{
+		name:          name,
+		fn:            fn,
+		deterministic: deterministic,
+	}
+	return nil
+}
+
+// checkFunctionsOf returns an error naming the first function called by the table which is neither
+// builtin nor registered into the engine, as the table could not be written otherwise
+func (e *Engine) checkFunctionsOf(table *Table) error {
+	exps := make([]ValueExp, 0, len(table.checkConstraints))
+
+	for _, check := range table.checkConstraints {
+		exps = append(exps, check.exp)
+	}
+
+	for _, col := range table.cols {
+		exps = append(exps, col.defaultExp, col.generatedExp)
+	}
+
+	var missingFn string
+
+	for _, exp := range exps {
+		visitExp(exp, func(v ValueExp) bool {
+			fn, ok := v.(*FnCall)
+			if !ok {
+				return true
+			}
+
+			fnName := strings.ToUpper(fn.fn)
+
+			if _, isBuiltin := builtinFunctions[fnName]; isBuiltin {
+				return true
+			}
+
+			if _, exists := e.userFunctionFor(fnName); exists {
+				return true
+			}
+
+			missingFn = fn.fn
+			return false
+		})
+
+		if missingFn != "" {
+			return fmt.Errorf("%w: function '%s' used by table '%s' is not registered", ErrFunctionDoesNotExist, missingFn, table.name)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) userFunctionFor(fnName string) (*userFunction, bool) {
+	e.userFunctionsMtx.RLock()
+	defer e.userFunctionsMtx.RUnlock()
+
+	fn, exists := e.userFunctions[fnName]
+	return fn, exists
+}
@@ -25,6 +25,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -110,7 +111,10 @@ func TestUseDatabaseWithoutMultiDBHandler(t *testing.T) {
 		require.ErrorIs(t, err, ErrUnspecifiedMultiDBHandler)
 	})
 
-	r, err := engine.Query(context.Background(), nil, "SELECT ts FROM pg_type WHERE ts < 1 + NOW()", nil)
+	_, err = engine.Query(context.Background(), nil, "SELECT ts FROM pg_type WHERE ts < 1 + NOW()", nil)
+	require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+	r, err := engine.Query(context.Background(), nil, "SELECT typname FROM pg_type WHERE typname < TO_CHAR(NOW(), 'YYYY')", nil)
 	require.NoError(t, err)
 	defer r.Close()
 
@@ -1606,10 +1610,141 @@ func TestCreateIndex(t *testing.T) {
 	require.ErrorIs(t, err, ErrPKCanNotBeNull)
 
 	_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON table1(active)", nil)
-	require.ErrorIs(t, err, ErrLimitedIndexCreation)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1(id, name, age) VALUES (2, 'name2', 50)", nil)
+	require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1(id, name, age, active) VALUES (2, 'name1', 50, true)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON table1(name, age)", nil)
+	require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
 
 	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON table1(active)", nil)
+	require.ErrorIs(t, err, ErrIndexAlreadyExists)
+}
+
+func TestCreateUniqueIndexOnNonEmptyTable(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	progress := make(map[string][]int)
+
+	opts := DefaultOptions().
+		WithPrefix(sqlPrefix).
+		WithIndexBackfillProgressInterval(3).
+		WithIndexBackfillProgressFunc(func(indexName string, validatedRows int) {
+			progress[indexName] = append(progress[indexName], validatedRows)
+		})
+
+	engine, err := NewEngine(st, opts)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE customers (id INTEGER AUTO_INCREMENT, email VARCHAR[64], country VARCHAR[16], PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			"INSERT INTO customers(email, country) VALUES (@email, @country)",
+			map[string]interface{}{"email": fmt.Sprintf("user%d@immudb.io", i), "country": fmt.Sprintf("country%d", i%3)},
+		)
+		require.NoError(t, err)
+	}
+
+	// values of previous versions of a row must not be taken into account
+	_, _, err = engine.Exec(context.Background(), nil, "UPDATE customers SET email = 'admin@immudb.io' WHERE id = 1", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO customers(email, country) VALUES ('user1@immudb.io', 'country1')", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM customers WHERE id = 2", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO customers(email, country) VALUES ('user2@immudb.io', 'country2')", nil)
+	require.NoError(t, err)
+
+	t.Run("index creation should fail when existing rows hold duplicated values", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON customers(country)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON customers(email, country)", nil)
+		require.NoError(t, err)
+
+		// no partial index is left behind
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON customers(country)", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("index creation should validate existing rows in batches", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON customers(email)", nil)
+		require.NoError(t, err)
+		require.Equal(t, []int{3, 6, 9, 11}, progress["customers(email)"])
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO customers(email, country) VALUES ('user3@immudb.io', 'country3')", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO customers(email, country) VALUES ('user11@immudb.io', 'country3')", nil)
+		require.NoError(t, err)
+
+		r, err := engine.Query(context.Background(), nil, "SELECT id FROM customers USE INDEX ON (email) WHERE email = 'user1@immudb.io'", nil)
+		require.NoError(t, err)
+
+		row, err := r.Read(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(11), row.ValuesByPosition[0].RawValue())
+
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+
+		err = r.Close()
+		require.NoError(t, err)
+	})
+
+	t.Run("index creation should fail when a duplicated value is concurrently inserted", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "CREATE UNIQUE INDEX ON customers(id, country)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO customers(email, country) VALUES ('user12@immudb.io', 'country3')", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT;", nil)
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON customers(id, country)", nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestCreateUniqueIndexExceedingReadSetLimit(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true).WithMVCCReadSetLimit(50))
 	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER AUTO_INCREMENT, code VARCHAR[16], PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 200; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1(code) VALUES (@code)", map[string]interface{}{"code": fmt.Sprintf("code%d", i)})
+		require.NoError(t, err)
+	}
+
+	// existing rows are validated without being added to the read-set of the transaction
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON table1(code)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1(code) VALUES ('code10')", nil)
+	require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
 }
 
 func TestUpsertInto(t *testing.T) {
@@ -3528,6 +3663,328 @@ func TestJSON(t *testing.T) {
 	})
 }
 
+func TestJSONPathIndexes(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(), nil,
+		`
+		CREATE TABLE events (
+			id INTEGER AUTO_INCREMENT,
+			kind VARCHAR[16],
+			payload JSON,
+
+			PRIMARY KEY(id)
+		)`, nil)
+	require.NoError(t, err)
+
+	longType := strings.Repeat("x", jsonPathColMaxLen+10)
+
+	payloads := []string{
+		`{"type": "click", "amount": 10, "user": {"country": "IT"}}`,
+		`{"type": "view", "amount": 2.5, "user": {"country": "US"}, "tag": true}`,
+		`{"type": "click", "amount": 100, "user": {"country": "US"}, "tag": "b"}`,
+		`{"type": "signup", "amount": 50, "tag": {"nested": true}}`,
+		`{"amount": 75.5, "tag": "a"}`,
+		`{"type": "purchase", "amount": 10, "tag": 10}`,
+		fmt.Sprintf(`{"type": "%s", "amount": -1}`, longType),
+		`{"type": "view", "amount": 1}`,
+	}
+
+	for _, payload := range payloads {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events(kind, payload) VALUES ('event', @payload)", map[string]interface{}{"payload": payload})
+		require.NoError(t, err)
+	}
+
+	readIDs := func(t *testing.T, query string, expectedIndex string) []int64 {
+		reader, err := engine.Query(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		require.Equal(t, expectedIndex, reader.ScanSpecs().Index.Name())
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	// queryIDs reads the rows through the given index and checks the same rows are read when scanning the primary index
+	queryIDs := func(t *testing.T, indexOn string, cond string, expectedIndex string) []int64 {
+		ids := readIDs(t, fmt.Sprintf("SELECT id FROM events USE INDEX ON (%s) WHERE %s", indexOn, cond), expectedIndex)
+
+		pkIDs := readIDs(t, fmt.Sprintf("SELECT id FROM events WHERE %s ORDER BY id DESC", cond), "events(id)")
+		require.ElementsMatch(t, pkIDs, ids)
+
+		return ids
+	}
+
+	t.Run("invalid indexes", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events(payload)", nil)
+		require.ErrorIs(t, err, ErrCannotIndexJson)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events((kind->'type'))", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events((content->'type'))", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX ON events((payload->'type'))", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("queries without json path indexes should scan the primary index", func(t *testing.T) {
+		ids := readIDs(t, "SELECT id FROM events WHERE payload->'type' = 'click'", "events(id)")
+		require.Equal(t, []int64{1, 3}, ids)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM events USE INDEX ON (payload->'type') WHERE payload->'type' = 'click'", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+	})
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events((payload->'type'))", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX IF NOT EXISTS ON events(payload->'type')", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events((payload->'amount'))", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events((payload->'tag'))", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON events((payload->'user'->'country'), kind)", nil)
+	require.NoError(t, err)
+
+	t.Run("json path indexes should only be used when requested", func(t *testing.T) {
+		ids := readIDs(t, "SELECT id FROM events WHERE payload->'type' = 'click'", "events(id)")
+		require.Equal(t, []int64{1, 3}, ids)
+
+		ids = readIDs(t, "SELECT id FROM events USE INDEX ON ((payload->'type')) WHERE payload->'type' = 'click'", "events(payload->'type')")
+		require.Equal(t, []int64{1, 3}, ids)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM events USE INDEX ON (payload->'unknown') WHERE payload->'type' = 'click'", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+	})
+
+	t.Run("existing rows should be indexed", func(t *testing.T) {
+		ids := queryIDs(t, "payload->'type'", "payload->'type' = 'click'", "events(payload->'type')")
+		require.Equal(t, []int64{1, 3}, ids)
+
+		ids = queryIDs(t, "payload->'type'", fmt.Sprintf("payload->'type' = '%s'", longType), "events(payload->'type')")
+		require.Equal(t, []int64{7}, ids)
+
+		ids = queryIDs(t, "payload->'tag'", "payload->'tag' = true", "events(payload->'tag')")
+		require.Equal(t, []int64{2}, ids)
+
+		ids = queryIDs(t, "payload->'user'->'country', kind", "payload->'user'->'country' = 'US' AND kind = 'event'", "events(payload->'user->country',kind)")
+		require.Equal(t, []int64{2, 3}, ids)
+	})
+
+	t.Run("values of other kinds should not satisfy comparisons", func(t *testing.T) {
+		ids := queryIDs(t, "payload->'tag'", "payload->'tag' = 'a'", "events(payload->'tag')")
+		require.Equal(t, []int64{5}, ids)
+
+		ids = queryIDs(t, "payload->'type'", "payload->'type' = 10", "events(payload->'type')")
+		require.Empty(t, ids)
+
+		ids = queryIDs(t, "payload->'amount'", "payload->'amount' >= 10 AND payload->'amount' < 100", "events(payload->'amount')")
+		require.Equal(t, []int64{1, 6, 4, 5}, ids)
+
+		ids = queryIDs(t, "payload->'amount'", "payload->'amount' < 10.0", "events(payload->'amount')")
+		require.Equal(t, []int64{7, 8, 2}, ids)
+
+		ids = queryIDs(t, "payload->'type'", "payload->'type' > 'purchase'", "events(payload->'type')")
+		require.Equal(t, []int64{4, 2, 8, 7}, ids)
+
+		ids = queryIDs(t, "payload->'tag'", "payload->'tag' >= 'a'", "events(payload->'tag')")
+		require.Equal(t, []int64{5, 3}, ids)
+
+		// as for any other column, missing values are NULL and NULL sorts before any other value
+		ids = queryIDs(t, "payload->'tag'", "payload->'tag' < 100", "events(payload->'tag')")
+		require.Equal(t, []int64{1, 7, 8, 6}, ids)
+	})
+
+	t.Run("index entries should be updated", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, `UPDATE events SET payload = '{"type": "view"}' WHERE id = 1`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM events WHERE id = 2", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `INSERT INTO events(kind, payload) VALUES ('event', '{"type": "click"}')`, nil)
+		require.NoError(t, err)
+
+		ids := queryIDs(t, "payload->'type'", "payload->'type' = 'click'", "events(payload->'type')")
+		require.Equal(t, []int64{3, 9}, ids)
+
+		ids = queryIDs(t, "payload->'type'", "payload->'type' = 'view'", "events(payload->'type')")
+		require.Equal(t, []int64{1, 8}, ids)
+	})
+
+	t.Run("json column can not be dropped while indexed", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE events DROP COLUMN payload", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+	})
+
+	t.Run("json path indexes can be dropped", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, "DROP INDEX ON events((payload->'type'))", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP INDEX ON events((payload->'type'))", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+
+		ids := readIDs(t, "SELECT id FROM events WHERE payload->'type' = 'click'", "events(id)")
+		require.Equal(t, []int64{3, 9}, ids)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM events USE INDEX ON (payload->'type') WHERE payload->'type' = 'click'", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+	})
+}
+
+func TestExplain(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(), nil,
+		`
+		CREATE TABLE orders (id INTEGER AUTO_INCREMENT, customer_id INTEGER, status VARCHAR[16], amount INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON orders(status);
+		CREATE TABLE customers (id INTEGER, name VARCHAR, PRIMARY KEY id);
+
+		INSERT INTO customers(id, name) VALUES (1, 'alice'), (2, 'bob');
+		INSERT INTO orders(customer_id, status, amount) VALUES (1, 'paid', 10), (2, 'paid', 20), (1, 'pending', 30), (2, 'shipped', 40);
+		`, nil)
+	require.NoError(t, err)
+
+	explain := func(t *testing.T, query string, params map[string]interface{}) []string {
+		rows, err := engine.queryAll(context.Background(), nil, query, params)
+		require.NoError(t, err)
+
+		plan := make([]string, len(rows))
+		for i, row := range rows {
+			plan[i] = row.ValuesByPosition[0].RawValue().(string)
+		}
+		return plan
+	}
+
+	analyzeStats := regexp.MustCompile(`time=[^)]+\)`)
+
+	t.Run("columns", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "EXPLAIN SELECT id FROM orders", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		cols, err := reader.Columns(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []ColDescriptor{{Table: "explain", Column: "plan", Type: VarcharType}}, cols)
+	})
+
+	t.Run("selected index and key ranges", func(t *testing.T) {
+		plan := explain(t, "EXPLAIN SELECT id, amount FROM orders WHERE id >= 2 AND id < @maxID ORDER BY id DESC LIMIT 2", map[string]interface{}{"maxID": 4})
+		require.Equal(t, []string{
+			"limit 2",
+			"-> project id, amount",
+			"  -> filter ((id >= 2) AND (id < @maxid))",
+			"    -> scan orders using index orders(id) desc range id >= 2 AND id < 4",
+		}, plan)
+
+		plan = explain(t, "EXPLAIN SELECT status, SUM(amount) FROM orders WHERE status = 'paid' GROUP BY status", nil)
+		require.Equal(t, []string{
+			"project status, SUM(amount)",
+			"-> group by status: SUM(amount)",
+			"  -> filter (status = 'paid')",
+			"    -> scan orders using index orders(status) range status = 'paid'",
+		}, plan)
+
+		plan = explain(t, "EXPLAIN SELECT id FROM orders USE INDEX ON (status) ORDER BY amount", nil)
+		require.Equal(t, []string{
+			"project id",
+			"-> sort by amount",
+			"  -> scan orders using index orders(status)",
+		}, plan)
+	})
+
+	t.Run("joins and set operations", func(t *testing.T) {
+		plan := explain(t, "EXPLAIN SELECT o.id, c.name FROM orders AS o LEFT JOIN customers AS c ON o.customer_id = c.id AND c.id > 0", nil)
+		require.Equal(t, []string{
+			"project id, name",
+			"-> nested loop join",
+			"  -> scan orders as o using index orders(id)",
+			"  -> left join on ((customer_id = id) AND (id > 0))",
+			"    -> scan customers as c using index customers(id) range id > 0",
+		}, plan)
+
+		plan = explain(t, "EXPLAIN SELECT customer_id FROM orders UNION SELECT id FROM customers", nil)
+		require.Equal(t, []string{
+			"distinct",
+			"-> union all",
+			"  -> project customer_id",
+			"    -> scan orders using index orders(id)",
+			"  -> project id",
+			"    -> scan customers using index customers(id)",
+		}, plan)
+
+		plan = explain(t, "EXPLAIN SELECT customer_id FROM orders EXCEPT ALL SELECT id FROM customers", nil)
+		require.Equal(t, []string{
+			"except",
+			"-> project customer_id",
+			"  -> scan orders using index orders(id)",
+			"-> project id",
+			"  -> scan customers using index customers(id)",
+		}, plan)
+	})
+
+	t.Run("analyze", func(t *testing.T) {
+		plan := explain(t, "EXPLAIN ANALYZE SELECT DISTINCT customer_id FROM orders WHERE amount > 10 OFFSET 1", nil)
+		require.Len(t, plan, 5)
+
+		for i := range plan {
+			plan[i] = analyzeStats.ReplaceAllString(plan[i], "time)")
+		}
+
+		require.Equal(t, []string{
+			"offset 1 (rows=1 time)",
+			"-> distinct (rows=2 time)",
+			"  -> project customer_id (rows=3 time)",
+			"    -> filter (amount > 10) (rows=3 time)",
+			"      -> scan orders using index orders(id) (rows=4 time)",
+		}, plan)
+
+		plan = explain(t, "EXPLAIN ANALYZE SELECT o.id, c.name FROM orders AS o INNER JOIN customers AS c ON o.customer_id = c.id WHERE o.status = 'paid'", nil)
+
+		for i := range plan {
+			plan[i] = analyzeStats.ReplaceAllString(plan[i], "time)")
+		}
+
+		require.Equal(t, []string{
+			"project id, name (rows=2 time)",
+			"-> filter (status = 'paid') (rows=2 time)",
+			"  -> nested loop join (rows=4 time)",
+			"    -> scan orders as o using index orders(id) (rows=4 time)",
+			"    -> inner join on (customer_id = id)",
+			"      -> scan customers as c using index customers(id)",
+		}, plan)
+	})
+
+	t.Run("explain statements do not return rows when executed", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "EXPLAIN ANALYZE SELECT id FROM orders", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("errors are reported", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "EXPLAIN SELECT id FROM invoices", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, err = engine.queryAll(context.Background(), nil, "EXPLAIN ANALYZE SELECT id FROM orders WHERE amount > @minAmount", nil)
+		require.ErrorIs(t, err, ErrMissingParameter)
+	})
+}
+
 func TestQueryCornerCases(t *testing.T) {
 	opts := store.DefaultOptions().WithMultiIndexing(true)
 	opts.WithIndexOptions(opts.IndexOpts.WithMaxActiveSnapshots(1))
@@ -5653,6 +6110,138 @@ func TestGroupBy(t *testing.T) {
 	})
 }
 
+func TestExtendedAggregations(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE events (id INTEGER AUTO_INCREMENT, user_id INTEGER, kind VARCHAR, amount FLOAT, ok BOOLEAN, PRIMARY KEY id);
+
+		INSERT INTO events(user_id, kind, amount, ok) VALUES
+			(1, 'login', 2, true),
+			(1, 'login', 4, true),
+			(2, 'purchase', 4, false),
+			(2, 'login', 4, NULL),
+			(3, 'purchase', 5, true),
+			(NULL, 'purchase', 5, true),
+			(3, 'logout', 7, true),
+			(1, 'logout', 9, false);
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("count ignoring nulls and distinct values", func(t *testing.T) {
+		rows := queryRawRows(t, engine, "SELECT COUNT(*), COUNT(user_id), COUNT(DISTINCT user_id), COUNT(DISTINCT kind) FROM events", nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(8), int64(7), int64(3), int64(3)},
+		}, rows)
+	})
+
+	t.Run("distinct counts per group", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT kind, COUNT(DISTINCT user_id), COUNT(user_id)
+			FROM events
+			GROUP BY kind
+			HAVING COUNT(DISTINCT user_id) > 1
+			ORDER BY kind`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"login", int64(2), int64(3)},
+			{"logout", int64(2), int64(2)},
+			{"purchase", int64(2), int64(2)},
+		}, rows)
+	})
+
+	t.Run("sum and avg of distinct values", func(t *testing.T) {
+		rows := queryRawRows(t, engine, "SELECT SUM(amount), SUM(DISTINCT amount), AVG(DISTINCT amount) FROM events", nil)
+
+		require.Equal(t, [][]interface{}{
+			{float64(40), float64(27), float64(5.4)},
+		}, rows)
+	})
+
+	t.Run("string aggregation", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT user_id, STRING_AGG(kind, ','), STRING_AGG(DISTINCT kind, ' | ')
+			FROM events
+			WHERE user_id IS NOT NULL
+			GROUP BY user_id
+			ORDER BY user_id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), "login,login,logout", "login | logout"},
+			{int64(2), "purchase,login", "purchase | login"},
+			{int64(3), "purchase,logout", "purchase | logout"},
+		}, rows)
+	})
+
+	t.Run("boolean aggregations", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT kind, BOOL_AND(ok), BOOL_OR(ok)
+			FROM events
+			GROUP BY kind
+			ORDER BY kind`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"login", true, true},
+			{"logout", false, true},
+			{"purchase", false, true},
+		}, rows)
+	})
+
+	t.Run("standard deviation and percentiles", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT
+				STDDEV(amount),
+				PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY amount),
+				PERCENTILE_CONT(0.25) WITHIN GROUP (ORDER BY amount DESC),
+				PERCENTILE_CONT(1) WITHIN GROUP (ORDER BY amount)
+			FROM events`, nil)
+
+		require.Len(t, rows, 1)
+		require.InDelta(t, 2.138089935, rows[0][0], 1e-9)
+		require.Equal(t, float64(4.5), rows[0][1])
+		require.Equal(t, float64(5.5), rows[0][2])
+		require.Equal(t, float64(9), rows[0][3])
+
+		rows = queryRawRows(t, engine, "SELECT STDDEV(amount) FROM events WHERE id = 1", nil)
+		require.Equal(t, [][]interface{}{{nil}}, rows)
+	})
+
+	t.Run("aggregations over no rows", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT
+				COUNT(*),
+				STRING_AGG(kind, ','),
+				BOOL_AND(ok),
+				BOOL_OR(ok),
+				STDDEV(amount),
+				PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY amount)
+			FROM events
+			WHERE id < 0`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(0), nil, nil, nil, nil, nil},
+		}, rows)
+	})
+
+	t.Run("invalid aggregations", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT STRING_AGG(kind) FROM events", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT SUM(amount, ',') FROM events", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT PERCENTILE_CONT(1.5) WITHIN GROUP (ORDER BY amount) FROM events", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT STDDEV(kind) FROM events", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT BOOL_AND(amount) FROM events", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+}
+
 func TestGroupByHaving(t *testing.T) {
 	engine := setupCommonTest(t)
 
@@ -5701,8 +6290,12 @@ func TestGroupByHaving(t *testing.T) {
 	r, err = engine.Query(context.Background(), nil, "SELECT active, COUNT(id) FROM table1 GROUP BY active ORDER BY active", nil)
 	require.NoError(t, err)
 
-	_, err = r.Read(context.Background())
-	require.ErrorIs(t, err, ErrLimitedCount)
+	for _, active := range []bool{false, true} {
+		row, err := r.Read(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, active, row.ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(rowCount/2), row.ValuesByPosition[1].RawValue())
+	}
 
 	err = r.Close()
 	require.NoError(t, err)
@@ -6136,6 +6729,549 @@ func TestLeftJoins(t *testing.T) {
 	)
 }
 
+func TestRightAndFullOuterJoins(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE employees (id INTEGER, name VARCHAR, dept_id INTEGER, PRIMARY KEY id);
+		CREATE TABLE departments (id INTEGER, title VARCHAR, PRIMARY KEY id);
+		CREATE TABLE locations (dept_id INTEGER, city VARCHAR, PRIMARY KEY dept_id);
+
+		INSERT INTO employees(id, name, dept_id) VALUES (1, 'alice', 10), (2, 'bob', 20), (3, 'charlie', NULL), (4, 'dave', 10);
+		INSERT INTO departments(id, title) VALUES (10, 'engineering'), (20, 'sales'), (30, 'marketing');
+		INSERT INTO locations(dept_id, city) VALUES (10, 'rome'), (40, 'paris');
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("right join", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT e.name, d.title
+			FROM employees AS e
+			RIGHT JOIN departments AS d ON e.dept_id = d.id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"alice", "engineering"},
+			{"bob", "sales"},
+			{"dave", "engineering"},
+			{nil, "marketing"},
+		}, rows)
+	})
+
+	t.Run("right outer join with ordering", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT d.title, e.name
+			FROM employees AS e
+			RIGHT OUTER JOIN departments AS d ON e.dept_id = d.id
+			ORDER BY d.title, e.name`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"engineering", "alice"},
+			{"engineering", "dave"},
+			{"marketing", nil},
+			{"sales", "bob"},
+		}, rows)
+	})
+
+	t.Run("full outer join", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT e.id, d.id
+			FROM employees AS e
+			FULL OUTER JOIN departments AS d ON e.dept_id = d.id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), int64(10)},
+			{int64(2), int64(20)},
+			{int64(3), nil},
+			{int64(4), int64(10)},
+			{nil, int64(30)},
+		}, rows)
+	})
+
+	t.Run("full join with filtering", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT e.id, d.id
+			FROM employees AS e
+			FULL JOIN departments AS d ON e.dept_id = d.id
+			WHERE e.id IS NULL OR d.id IS NULL`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(3), nil},
+			{nil, int64(30)},
+		}, rows)
+	})
+
+	t.Run("chained joins", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT e.name, d.title, l.city
+			FROM employees AS e
+			INNER JOIN departments AS d ON e.dept_id = d.id
+			FULL OUTER JOIN locations AS l ON d.id = l.dept_id
+			LEFT JOIN departments AS d2 ON l.dept_id = d2.id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"alice", "engineering", "rome"},
+			{"bob", "sales", nil},
+			{"dave", "engineering", "rome"},
+			{nil, nil, "paris"},
+		}, rows)
+	})
+
+	t.Run("right join with sub-query", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT e.name, d.title
+			FROM employees AS e
+			RIGHT JOIN (SELECT id, title FROM departments WHERE id > 10) AS d ON e.dept_id = d.id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"bob", "sales"},
+			{nil, "marketing"},
+		}, rows)
+	})
+}
+
+func TestWindowFunctions(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE movements (id INTEGER AUTO_INCREMENT, account VARCHAR, amount INTEGER, PRIMARY KEY id);
+
+		INSERT INTO movements(account, amount) VALUES
+			('acc1', 100), ('acc2', 50), ('acc1', -30), ('acc2', 50), ('acc1', 10), ('acc3', NULL);
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("running balance", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT id, account, SUM(amount) OVER (PARTITION BY account ORDER BY id) AS balance
+			FROM movements
+			ORDER BY id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), "acc1", int64(100)},
+			{int64(2), "acc2", int64(50)},
+			{int64(3), "acc1", int64(70)},
+			{int64(4), "acc2", int64(100)},
+			{int64(5), "acc1", int64(80)},
+			{int64(6), "acc3", nil},
+		}, rows)
+	})
+
+	t.Run("aggregations over the whole partition", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT id, COUNT(*) OVER (PARTITION BY account), COUNT(amount) OVER (PARTITION BY account), MAX(amount) OVER ()
+			FROM movements
+			ORDER BY id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), int64(3), int64(3), int64(100)},
+			{int64(2), int64(2), int64(2), int64(100)},
+			{int64(3), int64(3), int64(3), int64(100)},
+			{int64(4), int64(2), int64(2), int64(100)},
+			{int64(5), int64(3), int64(3), int64(100)},
+			{int64(6), int64(1), int64(0), int64(100)},
+		}, rows)
+	})
+
+	t.Run("ranking", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT
+				id,
+				ROW_NUMBER() OVER (ORDER BY amount DESC, id),
+				RANK() OVER (ORDER BY amount DESC),
+				DENSE_RANK() OVER (ORDER BY amount DESC)
+			FROM movements
+			WHERE amount IS NOT NULL
+			ORDER BY id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), int64(1), int64(1), int64(1)},
+			{int64(2), int64(2), int64(2), int64(2)},
+			{int64(3), int64(5), int64(5), int64(4)},
+			{int64(4), int64(3), int64(2), int64(2)},
+			{int64(5), int64(4), int64(4), int64(3)},
+		}, rows)
+	})
+
+	t.Run("row number per partition", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT account, id, ROW_NUMBER() OVER (PARTITION BY account ORDER BY id DESC) AS rn
+			FROM movements
+			ORDER BY account, id DESC`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"acc1", int64(5), int64(1)},
+			{"acc1", int64(3), int64(2)},
+			{"acc1", int64(1), int64(3)},
+			{"acc2", int64(4), int64(1)},
+			{"acc2", int64(2), int64(2)},
+			{"acc3", int64(6), int64(1)},
+		}, rows)
+	})
+
+	t.Run("lag and lead", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT
+				id,
+				LAG(amount) OVER (PARTITION BY account ORDER BY id),
+				LEAD(amount, 1, 0) OVER (PARTITION BY account ORDER BY id),
+				LAG(id, 2, -1) OVER (ORDER BY id)
+			FROM movements
+			WHERE account = 'acc1' OR account = 'acc2'
+			ORDER BY id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), nil, int64(-30), int64(-1)},
+			{int64(2), nil, int64(50), int64(-1)},
+			{int64(3), int64(100), int64(10), int64(1)},
+			{int64(4), int64(50), int64(0), int64(2)},
+			{int64(5), int64(-30), int64(0), int64(3)},
+		}, rows)
+	})
+
+	t.Run("ordering by window function", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT id
+			FROM movements
+			WHERE amount IS NOT NULL
+			ORDER BY SUM(amount) OVER (PARTITION BY account ORDER BY id) DESC, id`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1)},
+			{int64(4)},
+			{int64(5)},
+			{int64(3)},
+			{int64(2)},
+		}, rows)
+	})
+
+	t.Run("window functions are not allowed in the where clause", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM movements WHERE ROW_NUMBER() OVER () > 1", nil)
+		require.ErrorIs(t, err, ErrInvalidWindowFnUsage)
+	})
+
+	t.Run("invalid window functions", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT NTILE() OVER () FROM movements", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT RANK(id) OVER () FROM movements", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT LAG(id, -1) OVER () FROM movements", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+}
+
+func TestCommonTableExpressions(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE employees (id INTEGER, name VARCHAR, manager_id INTEGER, PRIMARY KEY id);
+
+		INSERT INTO employees(id, name, manager_id) VALUES
+			(1, 'alice', NULL), (2, 'bob', 1), (3, 'charlie', 1), (4, 'dave', 2), (5, 'eve', 4), (6, 'frank', NULL);
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("non-recursive", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			WITH
+				managers AS (SELECT DISTINCT manager_id FROM employees WHERE manager_id IS NOT NULL),
+				named_managers (id, manager) AS (
+					SELECT e.id, e.name FROM employees AS e WHERE e.id IN (SELECT manager_id FROM managers)
+				)
+			SELECT e.name, m.manager
+			FROM employees AS e
+			INNER JOIN named_managers AS m ON e.manager_id = m.id
+			ORDER BY e.name`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"bob", "alice"},
+			{"charlie", "alice"},
+			{"dave", "bob"},
+			{"eve", "dave"},
+		}, rows)
+	})
+
+	t.Run("combined with union", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			WITH roots AS (SELECT id, name FROM employees WHERE manager_id IS NULL)
+			SELECT name FROM roots
+			UNION
+			SELECT name FROM employees WHERE id = 2`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{"alice"},
+			{"frank"},
+			{"bob"},
+		}, rows)
+	})
+
+	t.Run("used as a sub-query", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT t.n
+			FROM (WITH numbers (n) AS (SELECT id FROM employees) SELECT n FROM numbers WHERE n > 4) AS t`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(5)},
+			{int64(6)},
+		}, rows)
+	})
+
+	t.Run("recursive tree walk", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			WITH RECURSIVE reports (id, name, depth) AS (
+				SELECT id, name, 0 FROM employees WHERE id = @root
+				UNION ALL
+				SELECT e.id, e.name, r.depth + 1
+				FROM employees AS e
+				INNER JOIN reports AS r ON e.manager_id = r.id
+			)
+			SELECT name, depth FROM reports ORDER BY depth, name`, map[string]interface{}{"root": 1})
+
+		require.Equal(t, [][]interface{}{
+			{"alice", int64(0)},
+			{"bob", int64(1)},
+			{"charlie", int64(1)},
+			{"dave", int64(2)},
+			{"eve", int64(3)},
+		}, rows)
+	})
+
+	t.Run("recursive statement executed concurrently", func(t *testing.T) {
+		stmts, err := ParseSQLString(`
+			WITH RECURSIVE reports (id, name) AS (
+				SELECT id, name FROM employees WHERE id = @root
+				UNION ALL
+				SELECT e.id, e.name
+				FROM employees AS e
+				INNER JOIN reports AS r ON e.manager_id = r.id
+			)
+			SELECT name FROM reports ORDER BY name`)
+		require.NoError(t, err)
+
+		stmt := stmts[0].(DataSource)
+
+		reader1, err := engine.QueryPreparedStmt(context.Background(), nil, stmt, map[string]interface{}{"root": 2})
+		require.NoError(t, err)
+		defer reader1.Close()
+
+		reader2, err := engine.QueryPreparedStmt(context.Background(), nil, stmt, map[string]interface{}{"root": 4})
+		require.NoError(t, err)
+		defer reader2.Close()
+
+		rows1, err := ReadAllRows(context.Background(), reader1)
+		require.NoError(t, err)
+		require.Len(t, rows1, 3)
+
+		rows2, err := ReadAllRows(context.Background(), reader2)
+		require.NoError(t, err)
+		require.Len(t, rows2, 2)
+	})
+
+	t.Run("recursive with union", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			WITH RECURSIVE numbers (n) AS (
+				SELECT 1 FROM employees
+				UNION
+				SELECT n + 1 FROM numbers WHERE n < 5
+			)
+			SELECT n FROM numbers`, nil)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1)},
+			{int64(2)},
+			{int64(3)},
+			{int64(4)},
+			{int64(5)},
+		}, rows)
+	})
+
+	t.Run("recursive cte must be a union", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "WITH RECURSIVE r AS (SELECT id FROM r) SELECT * FROM r", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("recursive term must match the columns of the non-recursive term", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, `
+			WITH RECURSIVE r (n) AS (
+				SELECT id FROM employees
+				UNION ALL
+				SELECT name FROM employees INNER JOIN r ON employees.id = r.n
+			)
+			SELECT * FROM r`, nil)
+		require.ErrorIs(t, err, ErrColumnMismatchInUnionStmt)
+	})
+
+	t.Run("unbounded recursion", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, `
+			WITH RECURSIVE r (n) AS (
+				SELECT 1 FROM employees WHERE id = 1
+				UNION ALL
+				SELECT n + 1 FROM r
+			)
+			SELECT * FROM r`, nil)
+		require.ErrorIs(t, err, ErrTooManyRows)
+	})
+
+	t.Run("recursion bounded by the limit of the query", func(t *testing.T) {
+		rows := queryRawRows(t, engine, "WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r) SELECT n FROM r LIMIT 3", nil)
+		require.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}}, rows)
+
+		rows = queryRawRows(t, engine, "WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r) SELECT n * 10 FROM r LIMIT @limit OFFSET 2", map[string]interface{}{"limit": 2})
+		require.Equal(t, [][]interface{}{{int64(30)}, {int64(40)}}, rows)
+
+		// every row must be produced to filter or sort them
+		_, err := engine.queryAll(context.Background(), nil, "WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r) SELECT n FROM r WHERE n > 1 LIMIT 3", nil)
+		require.ErrorIs(t, err, ErrTooManyRows)
+
+		_, err = engine.queryAll(context.Background(), nil, "WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r) SELECT n FROM r ORDER BY n DESC LIMIT 3", nil)
+		require.ErrorIs(t, err, ErrTooManyRows)
+	})
+
+	t.Run("invalid number of columns", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "WITH r (a, b) AS (SELECT id FROM employees) SELECT * FROM r", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("infer parameters", func(t *testing.T) {
+		params, err := engine.InferParameters(context.Background(), nil, `
+			WITH RECURSIVE reports (id, depth) AS (
+				SELECT id, 0 FROM employees WHERE id = @root
+				UNION ALL
+				SELECT e.id, r.depth + 1
+				FROM employees AS e
+				INNER JOIN reports AS r ON e.manager_id = r.id
+				WHERE r.depth < @maxDepth
+			)
+			SELECT id FROM reports WHERE id > @minID`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"root": IntegerType, "maxdepth": IntegerType, "minid": IntegerType}, params)
+	})
+}
+
+func TestIntersectAndExcept(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE accounts (id INTEGER, owner VARCHAR, balance INTEGER, PRIMARY KEY id);
+		INSERT INTO accounts(id, owner, balance) VALUES (1, 'alice', 100), (2, 'bob', 50), (3, 'charlie', 50);
+	`, nil)
+	require.NoError(t, err)
+
+	_, ctxs, err := engine.Exec(context.Background(), nil, `
+		UPDATE accounts SET balance = 70 WHERE id = 2;
+		DELETE FROM accounts WHERE id = 3;
+		INSERT INTO accounts(id, owner, balance) VALUES (4, 'dave', 100);
+	`, nil)
+	require.NoError(t, err)
+
+	snapshotTxID := ctxs[0].txHeader.ID
+
+	params := map[string]interface{}{"tx": snapshotTxID}
+
+	t.Run("rows changed since a previous snapshot", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT id, owner, balance FROM accounts BEFORE TX @tx
+			EXCEPT
+			SELECT id, owner, balance FROM accounts`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(2), "bob", int64(50)},
+			{int64(3), "charlie", int64(50)},
+		}, rows)
+	})
+
+	t.Run("rows unchanged since a previous snapshot", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT id, owner, balance FROM accounts BEFORE TX @tx
+			INTERSECT
+			SELECT id, owner, balance FROM accounts`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), "alice", int64(100)},
+		}, rows)
+	})
+
+	t.Run("duplicates are removed unless ALL is specified", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT balance FROM accounts BEFORE TX @tx
+			INTERSECT
+			SELECT col0 FROM (VALUES (50), (50), (100))`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(100)},
+			{int64(50)},
+		}, rows)
+
+		rows = queryRawRows(t, engine, `
+			SELECT col0 FROM (VALUES (50), (50), (50), (100))
+			INTERSECT ALL
+			SELECT balance FROM accounts BEFORE TX @tx`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(50)},
+			{int64(50)},
+			{int64(100)},
+		}, rows)
+
+		rows = queryRawRows(t, engine, `
+			SELECT col0 FROM (VALUES (50), (50), (50), (100), (100))
+			EXCEPT
+			SELECT balance FROM accounts BEFORE TX @tx`, params)
+
+		require.Empty(t, rows)
+
+		rows = queryRawRows(t, engine, `
+			SELECT col0 FROM (VALUES (50), (50), (50), (100), (100))
+			EXCEPT ALL
+			SELECT balance FROM accounts BEFORE TX @tx`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(50)},
+			{int64(100)},
+		}, rows)
+	})
+
+	t.Run("intersect takes precedence over union and except", func(t *testing.T) {
+		rows := queryRawRows(t, engine, `
+			SELECT id FROM accounts WHERE id = 1
+			UNION
+			SELECT id FROM accounts WHERE id > 1
+			INTERSECT
+			SELECT id FROM accounts WHERE id < 3`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1)},
+			{int64(2)},
+		}, rows)
+
+		rows = queryRawRows(t, engine, `
+			SELECT id FROM accounts
+			EXCEPT
+			SELECT id FROM accounts WHERE id = 1
+			EXCEPT
+			SELECT id FROM accounts WHERE id = 2`, params)
+
+		require.Equal(t, [][]interface{}{
+			{int64(4)},
+		}, rows)
+	})
+
+	t.Run("column mismatch", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id, owner FROM accounts INTERSECT SELECT id FROM accounts", nil)
+		require.ErrorIs(t, err, ErrColumnMismatchInUnionStmt)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM accounts EXCEPT SELECT owner FROM accounts", nil)
+		require.ErrorIs(t, err, ErrColumnMismatchInUnionStmt)
+	})
+
+	t.Run("infer parameters", func(t *testing.T) {
+		params, err := engine.InferParameters(context.Background(), nil, "SELECT id FROM accounts WHERE owner = @owner EXCEPT SELECT id FROM accounts WHERE balance > @balance")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"owner": VarcharType, "balance": IntegerType}, params)
+	})
+}
+
 func TestReOpening(t *testing.T) {
 	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
 	require.NoError(t, err)
@@ -6300,6 +7436,181 @@ func TestJoinsWithSubquery(t *testing.T) {
 	err = r.Close()
 	require.NoError(t, err)
 }
+func TestSubQueries(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE customers (id INTEGER, name VARCHAR, PRIMARY KEY id);
+		CREATE TABLE orders (id INTEGER AUTO_INCREMENT, customer_id INTEGER, amount INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON orders(customer_id);
+
+		INSERT INTO customers(id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'charlie'), (4, 'dave');
+		INSERT INTO orders(customer_id, amount) VALUES (1, 10), (1, 20), (3, 100), (NULL, 5);
+	`, nil)
+	require.NoError(t, err)
+
+	queryIDs := func(t *testing.T, query string, params map[string]interface{}) []int64 {
+		reader, err := engine.Query(context.Background(), nil, query, params)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("uncorrelated IN sub-query", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM customers WHERE id IN (SELECT customer_id FROM orders WHERE amount >= @amount)", map[string]interface{}{"amount": 20})
+		require.Equal(t, []int64{1, 3}, ids)
+	})
+
+	t.Run("uncorrelated NOT IN sub-query", func(t *testing.T) {
+		// the sub-query returns a NULL value, hence the condition can not be satisfied
+		ids := queryIDs(t, "SELECT id FROM customers WHERE id NOT IN (SELECT customer_id FROM orders)", nil)
+		require.Empty(t, ids)
+
+		ids = queryIDs(t, "SELECT id FROM customers WHERE id NOT IN (SELECT customer_id FROM orders WHERE customer_id > 0)", nil)
+		require.Equal(t, []int64{2, 4}, ids)
+	})
+
+	t.Run("correlated IN sub-query", func(t *testing.T) {
+		ids := queryIDs(t, `
+			SELECT id FROM customers AS c
+			WHERE 20 IN (SELECT amount FROM orders WHERE orders.customer_id = c.id)`, nil)
+		require.Equal(t, []int64{1}, ids)
+	})
+
+	t.Run("correlated EXISTS sub-query", func(t *testing.T) {
+		ids := queryIDs(t, `
+			SELECT id FROM customers
+			WHERE EXISTS (SELECT id FROM orders WHERE customer_id = customers.id AND amount > 15)`, nil)
+		require.Equal(t, []int64{1, 3}, ids)
+
+		ids = queryIDs(t, `
+			SELECT id FROM customers
+			WHERE NOT EXISTS (SELECT id FROM orders WHERE customer_id = customers.id)`, nil)
+		require.Equal(t, []int64{2, 4}, ids)
+	})
+
+	t.Run("inner columns shadow the outer ones", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM customers WHERE EXISTS (SELECT id FROM customers WHERE id > 10)", nil)
+		require.Empty(t, ids)
+
+		ids = queryIDs(t, "SELECT id FROM customers WHERE EXISTS (SELECT id FROM customers AS c2 WHERE c2.id > customers.id)", nil)
+		require.Equal(t, []int64{1, 2, 3}, ids)
+	})
+
+	t.Run("nested sub-queries", func(t *testing.T) {
+		ids := queryIDs(t, `
+			SELECT id FROM customers
+			WHERE id IN (
+				SELECT customer_id FROM orders
+				WHERE EXISTS (SELECT id FROM customers AS c WHERE c.id = orders.customer_id AND c.name = 'charlie')
+			)`, nil)
+		require.Equal(t, []int64{3}, ids)
+	})
+
+	t.Run("sub-query with union", func(t *testing.T) {
+		ids := queryIDs(t, `
+			SELECT id FROM customers
+			WHERE id IN (SELECT customer_id FROM orders WHERE amount = 100 UNION SELECT 2)`, nil)
+		require.Equal(t, []int64{2, 3}, ids)
+	})
+
+	t.Run("sub-query in the select list", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT id, EXISTS (SELECT id FROM orders WHERE customer_id = customers.id) FROM customers", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		for i, hasOrders := range []bool{true, false, true, false} {
+			require.Equal(t, hasOrders, rows[i].ValuesByPosition[1].RawValue())
+		}
+	})
+
+	t.Run("sub-query returning multiple columns", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT id FROM customers WHERE id IN (SELECT id, customer_id FROM orders)", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		_, err = reader.Read(context.Background())
+		require.ErrorIs(t, err, ErrInvalidNumberOfValues)
+	})
+
+	t.Run("sub-query over a non-existent table", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT id FROM customers WHERE EXISTS (SELECT id FROM unknown_table)", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		_, err = reader.Read(context.Background())
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+
+	t.Run("uncorrelated sub-queries are evaluated once", func(t *testing.T) {
+		calls := 0
+
+		err := engine.RegisterFunction("probe", &mockFunction{
+			t: BooleanType,
+			apply: func(params []TypedValue) (TypedValue, error) {
+				calls++
+				return NewBool(true), nil
+			},
+		}, false)
+		require.NoError(t, err)
+
+		ids := queryIDs(t, "SELECT id FROM customers WHERE id NOT IN (SELECT customer_id FROM orders WHERE PROBE() AND customer_id > 0)", nil)
+		require.Equal(t, []int64{2, 4}, ids)
+		require.Equal(t, 4, calls)
+
+		calls = 0
+
+		ids = queryIDs(t, "SELECT id FROM customers AS c WHERE id IN (SELECT customer_id FROM orders WHERE PROBE() AND customer_id = c.id)", nil)
+		require.Equal(t, []int64{1, 3}, ids)
+		require.Greater(t, calls, 4)
+	})
+
+	t.Run("outer columns in the select list, HAVING and ORDER BY of sub-queries", func(t *testing.T) {
+		ids := queryIDs(t, `
+			SELECT id FROM customers AS c
+			WHERE 30 IN (SELECT SUM(amount) + c.id - 1 FROM orders WHERE customer_id = c.id)`, nil)
+		require.Equal(t, []int64{1}, ids)
+
+		ids = queryIDs(t, `
+			SELECT id FROM customers AS c
+			WHERE EXISTS (SELECT SUM(amount) FROM orders GROUP BY customer_id HAVING SUM(amount) > c.id * 25)`, nil)
+		require.Equal(t, []int64{1, 2, 3}, ids)
+
+		ids = queryIDs(t, `
+			SELECT id FROM customers AS c
+			WHERE id IN (SELECT customer_id FROM orders ORDER BY (amount - c.id * 30) * (amount - c.id * 30) LIMIT 1)`, nil)
+		require.Equal(t, []int64{1, 3}, ids)
+	})
+
+	t.Run("type of the sub-query column", func(t *testing.T) {
+		_, err := engine.InferParameters(context.Background(), nil, "SELECT id FROM customers WHERE name IN (SELECT customer_id FROM orders)")
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		params, err := engine.InferParameters(context.Background(), nil, "SELECT id FROM customers WHERE @id IN (SELECT customer_id FROM orders WHERE customer_id = customers.id)")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"id": IntegerType}, params)
+	})
+
+	t.Run("sub-query in DELETE statement", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DELETE FROM customers WHERE NOT EXISTS (SELECT id FROM orders WHERE customer_id = customers.id)", nil)
+		require.NoError(t, err)
+
+		ids := queryIDs(t, "SELECT id FROM customers", nil)
+		require.Equal(t, []int64{1, 3}, ids)
+	})
+}
 
 func TestInferParameters(t *testing.T) {
 	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
@@ -9247,51 +10558,1963 @@ func TestCheckConstraints(t *testing.T) {
 	})
 }
 
-func TestQueryTxMetadata(t *testing.T) {
-	opts := store.DefaultOptions().WithMultiIndexing(true)
-	opts.WithIndexOptions(opts.IndexOpts.WithMaxActiveSnapshots(1))
+func TestForeignKeys(t *testing.T) {
+	engine := setupCommonTest(t)
 
-	st, err := store.Open(t.TempDir(), opts)
-	require.NoError(t, err)
-	defer closeStore(t, st)
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE customers (
+			id INTEGER AUTO_INCREMENT,
+			email VARCHAR[64],
+			name VARCHAR,
 
-	engine, err := NewEngine(st,
-		DefaultOptions().WithPrefix(sqlPrefix).WithParseTxMetadataFunc(func(b []byte) (map[string]interface{}, error) {
-			var md map[string]interface{}
-			err := json.Unmarshal(b, &md)
-			return md, err
-		}),
-	)
-	require.NoError(t, err)
+			PRIMARY KEY id
+		);
 
-	_, _, err = engine.Exec(
-		context.Background(), nil,
-		`
-		CREATE TABLE mytbl (
+		CREATE UNIQUE INDEX ON customers(email);
+
+		CREATE TABLE orders (
 			id INTEGER AUTO_INCREMENT,
+			customer_id INTEGER REFERENCES customers,
+			customer_email VARCHAR[64],
 
-			PRIMARY KEY(id)
-		)`, nil)
+			CONSTRAINT fk_email FOREIGN KEY (customer_email) REFERENCES customers(email) ON DELETE RESTRICT ON UPDATE RESTRICT,
+
+			PRIMARY KEY id
+		);
+
+		CREATE TABLE employees (
+			id INTEGER,
+			manager_id INTEGER REFERENCES employees(id),
+
+			PRIMARY KEY id
+		);`, nil,
+	)
 	require.NoError(t, err)
 
-	for i := 0; i < 10; i++ {
-		extra, err := json.Marshal(map[string]interface{}{
-			"n": i + 1,
-		})
-		require.NoError(t, err)
+	t.Run("invalid foreign keys", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE invalid_fk (id INTEGER, ref INTEGER REFERENCES missing, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
 
-		txOpts := DefaultTxOptions().WithExtra(extra)
-		tx, err := engine.NewTx(context.Background(), txOpts)
-		require.NoError(t, err)
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid_fk (id INTEGER, ref INTEGER REFERENCES customers(email), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidForeignKeyConstraint)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid_fk (id INTEGER, ref VARCHAR REFERENCES customers(name), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidForeignKeyConstraint)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid_fk (id INTEGER, ref INTEGER, FOREIGN KEY (ref) REFERENCES customers(id, email), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidForeignKeyConstraint)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid_fk (id INTEGER, ref INTEGER, FOREIGN KEY (ref) REFERENCES customers(missing), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
 
 		_, _, err = engine.Exec(
 			context.Background(),
-			tx,
-			fmt.Sprintf("INSERT INTO mytbl(id) VALUES (%d)", i+1),
 			nil,
+			`CREATE TABLE invalid_fk (
+				id INTEGER,
+				ref INTEGER,
+
+				CONSTRAINT ref_fk CHECK ref > 0,
+				CONSTRAINT ref_fk FOREIGN KEY (ref) REFERENCES customers,
+
+				PRIMARY KEY id
+			)`, nil,
 		)
-		require.NoError(t, err)
-	}
+		require.ErrorIs(t, err, ErrConstraintAlreadyExists)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM invalid_fk", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+
+	t.Run("referencing rows must match a referenced row", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO customers(email, name) VALUES ('jane@example.com', 'jane'), ('john@example.com', 'john')", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(customer_id, customer_email) VALUES (1, 'jane@example.com'), (NULL, NULL)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(customer_id) VALUES (3)", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(customer_id, customer_email) VALUES (1, 'jack@example.com')", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO orders(id, customer_id) VALUES (1, 3)", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET customer_id = 3 WHERE id = 1", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET customer_id = 2 WHERE id = 2", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("referenced rows can not be deleted nor updated", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DELETE FROM customers WHERE id = 2", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE customers SET email = 'jane@example.org' WHERE id = 1", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO customers(id, email) VALUES (1, 'jane@example.org')", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE customers SET name = 'Jane' WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE customers SET email = 'john@example.org' WHERE id = 2", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO customers(email) VALUES ('jack@example.com')", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM customers WHERE id = 3", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("self-referencing rows", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO employees(id, manager_id) VALUES (1, 1), (2, 1), (3, 4), (4, NULL)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO employees(id, manager_id) VALUES (5, 6)", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM employees WHERE id = 1", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM employees WHERE id = 3", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM employees", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("referenced rows can be deleted once no longer referenced", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "DELETE FROM customers WHERE id = 1", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		tx, _, err = engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "DELETE FROM orders WHERE id = 1; DELETE FROM customers WHERE id = 1; COMMIT;", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM customers", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name, foreign_key, referenced_table, referenced_column FROM COLUMNS('orders')", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		require.Equal(t, "id", rows[0].ValuesByPosition[0].RawValue())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+		require.True(t, rows[0].ValuesByPosition[2].IsNull())
+		require.True(t, rows[0].ValuesByPosition[3].IsNull())
+
+		require.Equal(t, "customer_id", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, "orders_fkey1", rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, "customers", rows[1].ValuesByPosition[2].RawValue())
+		require.Equal(t, "id", rows[1].ValuesByPosition[3].RawValue())
+
+		require.Equal(t, "customer_email", rows[2].ValuesByPosition[0].RawValue())
+		require.Equal(t, "fk_email", rows[2].ValuesByPosition[1].RawValue())
+		require.Equal(t, "customers", rows[2].ValuesByPosition[2].RawValue())
+		require.Equal(t, "email", rows[2].ValuesByPosition[3].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name, referenced_by FROM INDEXES('customers')", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		require.Equal(t, "customers(id)", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "orders.orders_fkey1", rows[0].ValuesByPosition[1].RawValue())
+
+		require.Equal(t, "customers(email)", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, "orders.fk_email", rows[1].ValuesByPosition[1].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name, referenced_by FROM INDEXES('orders')", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+	})
+
+	t.Run("drop referenced objects", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DROP TABLE customers", nil)
+		require.ErrorIs(t, err, ErrCannotDropTable)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP INDEX ON customers(email)", nil)
+		require.ErrorIs(t, err, ErrCannotDropIndex)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders DROP COLUMN customer_email", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders DROP CONSTRAINT fk_email", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders DROP CONSTRAINT fk_email", nil)
+		require.ErrorIs(t, err, ErrConstraintNotFound)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders DROP COLUMN customer_email", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP INDEX ON customers(email)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(customer_id) VALUES (1)", nil)
+		require.ErrorIs(t, err, ErrForeignKeyConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TABLE employees", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TABLE orders; DROP TABLE customers;", nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestViews(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE products (
+			id INTEGER AUTO_INCREMENT,
+			name VARCHAR,
+			price INTEGER,
+			active BOOLEAN,
+
+			PRIMARY KEY id
+		);
+
+		INSERT INTO products(name, price, active) VALUES ('p1', 10, true), ('p2', 20, false), ('p3', 30, true);`, nil,
+	)
+	require.NoError(t, err)
+
+	_, txs, err := engine.Exec(context.Background(), nil, "UPDATE products SET price = price * 2", nil)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+
+	updateTxID := txs[0].TxHeader().ID
+
+	_, _, err = engine.Exec(
+		context.Background(),
+		nil,
+		fmt.Sprintf(`
+			CREATE VIEW active_products AS SELECT id, name, price FROM products WHERE active;
+
+			CREATE VIEW prices (product, amount) AS SELECT name, price FROM products;
+
+			CREATE VIEW initial_prices AS SELECT name, price FROM products BEFORE TX %d;
+
+			CREATE VIEW expensive_products AS SELECT name FROM active_products WHERE price > 20;
+		`, updateTxID), nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("invalid views", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE VIEW active_products AS SELECT * FROM products", nil)
+		require.ErrorIs(t, err, ErrViewAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW IF NOT EXISTS active_products AS SELECT * FROM products", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW products AS SELECT * FROM active_products", nil)
+		require.ErrorIs(t, err, ErrTableAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE active_products (id INTEGER, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrViewAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products RENAME TO prices", nil)
+		require.ErrorIs(t, err, ErrViewAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW missing_products AS SELECT * FROM missing", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW missing_cols AS SELECT missing FROM products", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW too_many_cols (a, b, c) AS SELECT id, name FROM products", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW with_params AS SELECT id FROM products WHERE price > @price", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM active_products BEFORE TX 1", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP VIEW missing", nil)
+		require.ErrorIs(t, err, ErrViewDoesNotExist)
+	})
+
+	t.Run("query views", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT * FROM active_products ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "p1", rows[0].ValuesBySelector[EncodeSelector("", "active_products", "name")].RawValue())
+		require.Equal(t, int64(20), rows[0].ValuesBySelector[EncodeSelector("", "active_products", "price")].RawValue())
+		require.Equal(t, "p3", rows[1].ValuesBySelector[EncodeSelector("", "active_products", "name")].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT p.name FROM active_products AS p WHERE p.price > @price", map[string]interface{}{"price": 40})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "p3", rows[0].ValuesBySelector[EncodeSelector("", "p", "name")].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT product, amount FROM prices WHERE amount = 40", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "p2", rows[0].ValuesBySelector[EncodeSelector("", "prices", "product")].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name, price FROM initial_prices ORDER BY name", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, int64(10), rows[0].ValuesBySelector[EncodeSelector("", "initial_prices", "price")].RawValue())
+		require.Equal(t, int64(30), rows[2].ValuesBySelector[EncodeSelector("", "initial_prices", "price")].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name FROM expensive_products", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "p3", rows[0].ValuesBySelector[EncodeSelector("", "expensive_products", "name")].RawValue())
+
+		rows, err = engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT p.name, i.price FROM products AS p INNER JOIN initial_prices AS i ON p.name = i.name WHERE p.active ORDER BY p.id DESC",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "p3", rows[0].ValuesBySelector[EncodeSelector("", "p", "name")].RawValue())
+		require.Equal(t, int64(30), rows[0].ValuesBySelector[EncodeSelector("", "i", "price")].RawValue())
+	})
+
+	t.Run("list views", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name, query FROM VIEWS() WHERE name = 'prices'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "SELECT name, price FROM products", rows[0].ValuesBySelector[EncodeSelector("", "views", "query")].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SHOW VIEWS", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+	})
+
+	t.Run("tables and columns required by views", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DROP TABLE products", nil)
+		require.ErrorIs(t, err, ErrCannotDropTable)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products RENAME TO goods", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products DROP COLUMN active", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products RENAME COLUMN price TO cost", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ADD COLUMN notes VARCHAR", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW all_products AS SELECT * FROM products", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products DROP COLUMN notes", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP VIEW all_products; ALTER TABLE products DROP COLUMN notes", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("drop views", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DROP VIEW active_products", nil)
+		require.ErrorIs(t, err, ErrCannotDropView)
+		require.ErrorContains(t, err, "because view expensive_products requires it")
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM expensive_products", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE VIEW cheap_products AS SELECT id, name FROM active_products WHERE price < 100;
+			DROP VIEW cheap_products;
+		`, nil)
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM cheap_products", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SHOW VIEWS", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			DROP VIEW expensive_products;
+			DROP VIEW active_products;
+			DROP VIEW prices;
+			DROP VIEW initial_prices;
+
+			ALTER TABLE products RENAME TO goods;
+			DROP TABLE goods;
+		`, nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestDefaultValuesAndGeneratedColumns(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE orders (
+			id INTEGER AUTO_INCREMENT,
+			ref UUID NOT NULL DEFAULT RANDOM_UUID(),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			status VARCHAR[16] DEFAULT 'pending',
+			price INTEGER NOT NULL,
+			qty INTEGER DEFAULT 1,
+			total INTEGER GENERATED ALWAYS AS (price * qty) STORED,
+
+			PRIMARY KEY id
+		);
+
+		CREATE INDEX ON orders(total);`, nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("invalid column expressions", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, v INTEGER DEFAULT id + 1, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidDefaultValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, v INTEGER DEFAULT 'one', PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidDefaultValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER DEFAULT 1 AUTO_INCREMENT, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidDefaultValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, v INTEGER GENERATED ALWAYS AS (missing + 1), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, v1 INTEGER GENERATED ALWAYS AS (id + 1), v2 INTEGER GENERATED ALWAYS AS (v1 + 1), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, v INTEGER GENERATED ALWAYS AS (id + 1), PRIMARY KEY v)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, ts TIMESTAMP GENERATED ALWAYS AS (NOW()), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, ref UUID GENERATED ALWAYS AS (RANDOM_UUID()), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE invalid (id INTEGER, ts TIMESTAMP, age INTERVAL GENERATED ALWAYS AS (AGE(ts)), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(price, total) VALUES (10, 100)", nil)
+		require.ErrorIs(t, err, ErrGeneratedColumnCannotBeSet)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET total = 100", nil)
+		require.ErrorIs(t, err, ErrGeneratedColumnCannotBeSet)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(qty) VALUES (10)", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders DROP COLUMN qty", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders RENAME COLUMN price TO unit_price", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders ADD COLUMN notes VARCHAR NOT NULL", nil)
+		require.ErrorIs(t, err, ErrNewColumnMustBeNullable)
+	})
+
+	_, _, err = engine.Exec(
+		context.Background(),
+		nil,
+		`INSERT INTO orders(price) VALUES (10);
+		INSERT INTO orders(price, qty, status) VALUES (20, 3, 'paid');
+		INSERT INTO orders(price, qty, status) VALUES (5, 2, NULL);`, nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("default and generated values", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id, ref, created_at, status, qty, total FROM orders ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		require.Equal(t, "pending", rows[0].ValuesByPosition[3].RawValue())
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[4].RawValue())
+		require.Equal(t, int64(10), rows[0].ValuesByPosition[5].RawValue())
+
+		require.Equal(t, "paid", rows[1].ValuesByPosition[3].RawValue())
+		require.Equal(t, int64(60), rows[1].ValuesByPosition[5].RawValue())
+
+		require.True(t, rows[2].ValuesByPosition[3].IsNull())
+		require.Equal(t, int64(10), rows[2].ValuesByPosition[5].RawValue())
+
+		require.NotEqual(t, rows[0].ValuesByPosition[1].RawValue(), rows[1].ValuesByPosition[1].RawValue())
+		require.False(t, rows[0].ValuesByPosition[2].IsNull())
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET qty = 4 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM orders USE INDEX ON (total) WHERE total = 40", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT default_value, generated_as FROM COLUMNS('orders') WHERE name = 'total' OR name = 'status'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "'pending'", rows[0].ValuesByPosition[0].RawValue())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+		require.True(t, rows[1].ValuesByPosition[0].IsNull())
+		require.Equal(t, "(price * qty)", rows[1].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("add columns to non-empty table", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE orders ADD COLUMN currency VARCHAR[3] NOT NULL DEFAULT 'EUR'", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders ADD COLUMN discounted INTEGER NOT NULL GENERATED ALWAYS AS (price - 1)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders ADD COLUMN label VARCHAR NOT NULL GENERATED ALWAYS AS (status)", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT currency, discounted FROM orders ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		for i, price := range []int64{10, 20, 5} {
+			require.Equal(t, "EUR", rows[i].ValuesByPosition[0].RawValue())
+			require.Equal(t, price-1, rows[i].ValuesByPosition[1].RawValue())
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders(price, currency) VALUES (100, 'USD')", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT currency, discounted, total FROM orders WHERE id = 4", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "USD", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(99), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(100), rows[0].ValuesByPosition[2].RawValue())
+	})
+
+	t.Run("generated values of null operands", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO orders(price, qty) VALUES (7, NULL)", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT discounted, total FROM orders WHERE id = 5", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(6), rows[0].ValuesByPosition[0].RawValue())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET qty = 2 WHERE id = 5", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT total FROM orders WHERE id = 5", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(14), rows[0].ValuesByPosition[0].RawValue())
+	})
+}
+
+func TestAlterColumn(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE products (
+			id INTEGER AUTO_INCREMENT,
+			code VARCHAR[8] NOT NULL,
+			name VARCHAR[64] NOT NULL,
+			price INTEGER,
+			qty INTEGER,
+			total INTEGER GENERATED ALWAYS AS (price * qty),
+			discount INTEGER,
+
+			PRIMARY KEY id
+		);
+
+		CREATE UNIQUE INDEX ON products(code);`, nil,
+	)
+	require.NoError(t, err)
+
+	_, txs, err := engine.Exec(
+		context.Background(),
+		nil,
+		`INSERT INTO products(code, name, price, qty) VALUES ('p1', 'first product', 10, 1), ('p2', 'second product', 20, 2);
+		INSERT INTO products(code, name, price, qty, discount) VALUES ('p3', 'third product', 30, 3, 5);`, nil,
+	)
+	require.NoError(t, err)
+
+	insertTxID := txs[0].TxHeader().ID
+
+	t.Run("invalid alterations", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN missing TYPE INTEGER", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN id DROP NOT NULL", nil)
+		require.ErrorIs(t, err, ErrPKCanNotBeNull)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN id TYPE FLOAT", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN code TYPE VARCHAR", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN name TYPE VARCHAR[8]", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN name TYPE BOOLEAN", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN name TYPE INTEGER[4]", nil)
+		require.ErrorIs(t, err, ErrLimitedMaxLen)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN price TYPE VARCHAR", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN price TYPE TIMESTAMP", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN discount SET NOT NULL", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+	})
+
+	t.Run("widen varchar and relax nullability", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN name TYPE VARCHAR[256]", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN name DROP NOT NULL", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO products(code, name, price, qty) VALUES ('p4', @name, 40, 1)", map[string]interface{}{"name": strings.Repeat("a", 100)})
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO products(code, price, qty) VALUES ('p5', 50, 1)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN name SET NOT NULL", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT type, max_length, nullable FROM COLUMNS('products') WHERE name = 'name'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, VarcharType, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(256), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, true, rows[0].ValuesByPosition[2].RawValue())
+	})
+
+	t.Run("alter indexed columns", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN code TYPE VARCHAR[16]", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM products USE INDEX ON (code) WHERE code = 'p2'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "second product", rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO products(code, name, price, qty) VALUES ('p1', 'duplicated', 10, 1)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			`CREATE TABLE tags (
+				name VARCHAR[8],
+				score INTEGER,
+				ratio FLOAT,
+				amount DECIMAL(5,2),
+
+				PRIMARY KEY name
+			);
+
+			CREATE INDEX ON tags(score);
+			CREATE UNIQUE INDEX ON tags(ratio);
+			CREATE INDEX ON tags(amount);
+
+			INSERT INTO tags(name, score, ratio, amount) VALUES ('a', 1, 1.2, 1.25), ('b', 2, 1.4, 2.5), ('c', 2, 3, 3);`, nil,
+		)
+		require.NoError(t, err)
+
+		// rows are keyed by their primary key, thus its key encoding must be preserved
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE tags ALTER COLUMN name TYPE VARCHAR[16]", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		// index entries are built out of every version of the rows
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE tags ALTER COLUMN ratio TYPE INTEGER", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE tags ALTER COLUMN score TYPE FLOAT", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE tags ALTER COLUMN amount TYPE DECIMAL(5,1)", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE tags ALTER COLUMN amount TYPE DECIMAL(10,2)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO tags(name, score, ratio, amount) VALUES ('d', 4, 4, 12345678.25)", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name FROM tags USE INDEX ON (amount) WHERE amount >= 2.5", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, "b", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "c", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, "d", rows[2].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			`CREATE TABLE labels (
+				id INTEGER,
+				label VARCHAR[4],
+				category VARCHAR[4],
+
+				PRIMARY KEY id
+			);
+
+			CREATE UNIQUE INDEX ON labels(category, label);
+
+			INSERT INTO labels(id, label, category) VALUES (1, 'ab', 'x'), (2, 'abcd', 'x');`, nil,
+		)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE labels SET label = 'abc' WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE labels ALTER COLUMN label TYPE VARCHAR[8]", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO labels(id, label, category) VALUES (3, 'abcdefgh', 'x')", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO labels(id, label, category) VALUES (4, 'abcd', 'x')", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO labels(id, label, category) VALUES (4, 'ab', 'x')", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM labels USE INDEX ON (category, label) WHERE category = 'x'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		require.Equal(t, int64(4), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(1), rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(2), rows[2].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[3].ValuesByPosition[0].RawValue())
+
+		// the type of an indexed column can not be changed
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE labels ALTER COLUMN label TYPE BLOB[8]", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+	})
+
+	t.Run("convert integer into float", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE products ALTER COLUMN qty TYPE FLOAT", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE products SET qty = 2.5 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT qty, total FROM products ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+		require.Equal(t, 2.5, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(25), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, 2.0, rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(40), rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, 3.0, rows[2].ValuesByPosition[0].RawValue())
+
+		// values written before the change are read in the current type
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT qty FROM products BEFORE TX @tx ORDER BY id", map[string]interface{}{"tx": insertTxID + 1})
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, 1.0, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, 2.0, rows[1].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT qty FROM (HISTORY OF products) WHERE id = 1", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, 1.0, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, 1.0, rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, 2.5, rows[2].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name FROM products USE INDEX ON (code) WHERE code = 'p2'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "second product", rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("lossy conversions are rejected", func(t *testing.T) {
+		_, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`CREATE TABLE measures (id INTEGER, v FLOAT, PRIMARY KEY id);
+			INSERT INTO measures(id, v) VALUES (1, 1.5), (2, 3.0);`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE measures ALTER COLUMN v TYPE INTEGER", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+		require.ErrorContains(t, err, "1.5 can not be converted exactly into INTEGER")
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE measures SET v = 2.0 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE measures ALTER COLUMN v TYPE INTEGER", nil)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]interface{}{int64(2), int64(3)},
+			queryRawValues(t, engine, "SELECT v FROM measures", nil),
+		)
+
+		// values only present in the history of the row are returned as stored
+		require.Equal(t,
+			[]interface{}{1.5, int64(2), int64(2)},
+			queryRawValues(t, engine, "SELECT v FROM (HISTORY OF measures) WHERE id = 1", nil),
+		)
+	})
+
+	t.Run("alter columns within the same transaction", func(t *testing.T) {
+		_, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				ALTER TABLE products ALTER COLUMN price TYPE FLOAT;
+				ALTER TABLE products ALTER COLUMN code SET NOT NULL;
+				ALTER TABLE products ALTER COLUMN discount SET NOT NULL;
+			COMMIT;`, nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				INSERT INTO products(code, name, price, qty) VALUES ('p6', 'sixth product', 60, 1);
+				ALTER TABLE products ALTER COLUMN price TYPE FLOAT;
+			COMMIT;`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT price, total FROM products WHERE code = 'p6'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, 60.0, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(60), rows[0].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("type changes are preserved when loading the catalog", func(t *testing.T) {
+		engine, err := NewEngine(engine.store, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT qty FROM (HISTORY OF products) WHERE id = 2", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		for _, row := range rows {
+			require.Equal(t, 2.0, row.ValuesByPosition[0].RawValue())
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE products ADD COLUMN notes VARCHAR", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM products", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 6)
+	})
+}
+
+func TestDDLAndDMLWithinTheSameTx(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	t.Run("create and seed a table", func(t *testing.T) {
+		tx, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE TABLE accounts (id INTEGER AUTO_INCREMENT, owner VARCHAR[32], balance INTEGER, PRIMARY KEY id);
+				INSERT INTO accounts(owner, balance) VALUES ('alice', 10), ('bob', 20);
+				CREATE INDEX ON accounts(owner);
+				INSERT INTO accounts(owner, balance) VALUES ('carol', 30);
+			`, nil)
+		require.NoError(t, err)
+		require.NotNil(t, tx)
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT id FROM accounts USE INDEX ON (owner) WHERE owner = 'bob' OR owner = 'carol'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[1].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM accounts USE INDEX ON (owner) WHERE owner = 'alice'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("add a column and backfill it", func(t *testing.T) {
+		tx, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				ALTER TABLE accounts ADD COLUMN currency VARCHAR[3];
+				UPDATE accounts SET currency = 'EUR';
+				CREATE INDEX ON accounts(currency);
+			`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT id FROM accounts WHERE currency = 'EUR'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		err = tx.Cancel()
+		require.NoError(t, err)
+
+		tx, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				ALTER TABLE accounts ADD COLUMN currency VARCHAR[3];
+				CREATE INDEX ON accounts(currency);
+				UPDATE accounts SET currency = 'EUR';
+			`, nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), tx, "SELECT id FROM accounts WHERE currency = 'EUR'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM accounts USE INDEX ON (currency) WHERE currency = 'EUR'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+	})
+
+	t.Run("failed migrations are rolled back", func(t *testing.T) {
+		_, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE TABLE movements (id INTEGER, amount INTEGER, PRIMARY KEY id);
+				CREATE INDEX ON movements(amount);
+				INSERT INTO movements(id, amount) VALUES (1, 100);
+				INSERT INTO movements(id, amount) VALUES (1, 200);
+			COMMIT;`, nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM movements", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		// the same table id is assigned to the table, with a different definition
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE TABLE movements (id INTEGER, account VARCHAR[32], amount INTEGER, PRIMARY KEY id);
+				CREATE INDEX ON movements(account);
+				INSERT INTO movements(id, account, amount) VALUES (1, 'alice', 100), (2, 'bob', 200);
+			COMMIT;`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT amount FROM movements USE INDEX ON (account) WHERE account = 'bob'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(200), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("index committed rows within the same transaction", func(t *testing.T) {
+		tx, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE INDEX ON accounts(balance);
+			`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT owner FROM accounts USE INDEX ON (balance) WHERE balance > 15", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "bob", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "carol", rows[1].ValuesByPosition[0].RawValue())
+
+		err = tx.Cancel()
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT owner FROM accounts USE INDEX ON (balance)", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+	})
+
+	t.Run("index rows modified within the same transaction", func(t *testing.T) {
+		_, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				INSERT INTO accounts(owner, balance, currency) VALUES ('alice', 10, 'EUR');
+				CREATE UNIQUE INDEX ON accounts(owner, currency);
+			COMMIT;`, nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		tx, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				INSERT INTO accounts(owner, balance, currency) VALUES ('dave', 40, 'EUR');
+				UPDATE accounts SET balance = 5 WHERE owner = 'carol';
+				DELETE FROM accounts WHERE owner = 'bob';
+				CREATE INDEX ON accounts(balance);
+			`, nil)
+		require.NoError(t, err)
+
+		owners := func(tx *SQLTx, query string) []interface{} {
+			rows, err := engine.queryAll(context.Background(), tx, query, nil)
+			require.NoError(t, err)
+
+			values := make([]interface{}, len(rows))
+			for i, row := range rows {
+				values[i] = row.ValuesByPosition[0].RawValue()
+			}
+			return values
+		}
+
+		require.Equal(t, []interface{}{"carol", "alice", "dave"}, owners(tx, "SELECT owner FROM accounts USE INDEX ON (balance)"))
+		require.Equal(t, []interface{}{"dave"}, owners(tx, "SELECT owner FROM accounts USE INDEX ON (balance) WHERE balance > 15"))
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []interface{}{"carol", "alice", "dave"}, owners(nil, "SELECT owner FROM accounts USE INDEX ON (balance)"))
+		require.Equal(t, []interface{}{"dave"}, owners(nil, "SELECT owner FROM accounts USE INDEX ON (balance) WHERE balance > 15"))
+	})
+
+	t.Run("concurrent transactions can not share indexes of tables defined differently", func(t *testing.T) {
+		tx1, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE TABLE tx1_table (id INTEGER, PRIMARY KEY id);
+				INSERT INTO tx1_table(id) VALUES (1);
+			`, nil)
+		require.NoError(t, err)
+
+		// the same table id is assigned to both tables
+		tx2, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx2, "CREATE TABLE tx2_table (id VARCHAR[16], PRIMARY KEY id);", nil)
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+		require.True(t, tx2.Closed())
+
+		rows, err := engine.queryAll(context.Background(), tx1, "SELECT id FROM tx1_table", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		_, _, err = engine.Exec(context.Background(), tx1, "COMMIT", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM tx1_table", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("indexes shared with a committed transaction are not discarded", func(t *testing.T) {
+		tx1, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE TABLE shared_table (id INTEGER, PRIMARY KEY id);
+				INSERT INTO shared_table(id) VALUES (1);
+			`, nil)
+		require.NoError(t, err)
+
+		tx2, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`BEGIN TRANSACTION;
+				CREATE TABLE shared_table (id INTEGER, PRIMARY KEY id);
+				INSERT INTO shared_table(id) VALUES (2), (3);
+			`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx2, "COMMIT", nil)
+		require.NoError(t, err)
+
+		// the transaction which initialized the indexes is not committed
+		_, _, err = engine.Exec(context.Background(), tx1, "COMMIT", nil)
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM shared_table", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+	})
+}
+
+func TestSequences(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	nextVal := func(t *testing.T, tx *SQLTx, seq string) int64 {
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT NEXTVAL(@seq)", map[string]interface{}{"seq": seq})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		return rows[0].ValuesByPosition[0].RawValue().(int64)
+	}
+
+	t.Run("create sequences", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE SEQUENCE seq1 INCREMENT 0", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE SEQUENCE seq1 START 0", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE SEQUENCE seq1 MINVALUE 10 MAXVALUE 10", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE SEQUENCE invoice_numbers;
+			CREATE SEQUENCE countdown START WITH 3 INCREMENT BY -1 MINVALUE 1 MAXVALUE 3 CYCLE;
+			CREATE SEQUENCE tens INCREMENT BY 10 MAXVALUE 20;
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE SEQUENCE tens", nil)
+		require.ErrorIs(t, err, ErrSequenceAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE SEQUENCE IF NOT EXISTS tens", nil)
+		require.NoError(t, err)
+
+		catalog, err := engine.Catalog(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, catalog.GetSequences(), 3)
+	})
+
+	t.Run("generate values", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT CURRVAL('invoice_numbers')", nil)
+		require.ErrorIs(t, err, ErrSequenceNotUsed)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT NEXTVAL('unknown')", nil)
+		require.ErrorIs(t, err, ErrSequenceDoesNotExist)
+
+		// advancing a sequence requires a read-write transaction
+		_, err = engine.queryAll(context.Background(), nil, "SELECT NEXTVAL('invoice_numbers')", nil)
+		require.ErrorIs(t, err, store.ErrReadOnlyTx)
+		require.ErrorContains(t, err, "'NEXTVAL' function must be called within a read-write transaction")
+
+		for _, c := range []struct {
+			query    string
+			readOnly bool
+		}{
+			{"SELECT CURRVAL('invoice_numbers')", true},
+			{"SELECT NEXTVAL('invoice_numbers')", false},
+			{"SELECT id FROM invoices WHERE id > nextval('invoice_numbers')", false},
+			{"SELECT id FROM invoices WHERE id IN (SELECT NEXTVAL('invoice_numbers'))", false},
+			{"SELECT 1 UNION SELECT NEXTVAL('invoice_numbers')", false},
+			{"WITH s AS (SELECT NEXTVAL('invoice_numbers')) SELECT * FROM s", false},
+		} {
+			stmts, err := ParseSQLString(c.query)
+			require.NoError(t, err)
+			require.Equal(t, c.readOnly, stmts[0].readOnly(), c.query)
+		}
+
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, int64(1), nextVal(t, tx, "invoice_numbers"))
+		require.Equal(t, int64(2), nextVal(t, tx, "invoice_numbers"))
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT CURRVAL('invoice_numbers')", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+
+		for _, expected := range []int64{3, 2, 1, 3} {
+			require.Equal(t, expected, nextVal(t, tx, "countdown"))
+		}
+
+		require.Equal(t, int64(1), nextVal(t, tx, "tens"))
+		require.Equal(t, int64(11), nextVal(t, tx, "tens"))
+
+		_, err = engine.queryAll(context.Background(), tx, "SELECT NEXTVAL('tens')", nil)
+		require.ErrorIs(t, err, ErrSequenceLimitReached)
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT CURRVAL('invoice_numbers'), CURRVAL('countdown')", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("share a sequence between tables", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE invoices (id INTEGER AUTO_INCREMENT, number INTEGER NOT NULL DEFAULT NEXTVAL('invoice_numbers'), amount INTEGER, PRIMARY KEY id);
+			CREATE TABLE credit_notes (id INTEGER AUTO_INCREMENT, number INTEGER NOT NULL DEFAULT NEXTVAL('invoice_numbers'), amount INTEGER, PRIMARY KEY id);
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1 (id INTEGER AUTO_INCREMENT, n INTEGER GENERATED ALWAYS AS (NEXTVAL('invoice_numbers')), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1 (id INTEGER AUTO_INCREMENT, n INTEGER, CHECK (n < NEXTVAL('invoice_numbers')), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrNoSupported)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			INSERT INTO invoices(amount) VALUES (10);
+			INSERT INTO credit_notes(amount) VALUES (10);
+			INSERT INTO invoices(amount) VALUES (10);
+		`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT number FROM invoices", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(5), rows[1].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT number FROM credit_notes", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(4), rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP SEQUENCE invoice_numbers", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("values are not consumed by cancelled or conflicting transactions", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION; INSERT INTO invoices(amount) VALUES (10);", nil)
+		require.NoError(t, err)
+
+		err = tx.Cancel()
+		require.NoError(t, err)
+
+		tx1, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		tx2, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION;", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, int64(6), nextVal(t, tx1, "invoice_numbers"))
+		require.Equal(t, int64(6), nextVal(t, tx2, "invoice_numbers"))
+
+		_, _, err = engine.Exec(context.Background(), tx1, "COMMIT", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx2, "COMMIT", nil)
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT CURRVAL('invoice_numbers')", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(6), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("sequences survive reloading the catalog", func(t *testing.T) {
+		engine, err := NewEngine(engine.store, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT CURRVAL('invoice_numbers'), CURRVAL('countdown')", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(6), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[1].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO credit_notes(amount) VALUES (10)", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT number FROM credit_notes ORDER BY id DESC LIMIT 1", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("drop a sequence", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DROP SEQUENCE unknown", nil)
+		require.ErrorIs(t, err, ErrSequenceDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP SEQUENCE countdown; CREATE SEQUENCE countdown;", nil)
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT CURRVAL('countdown')", nil)
+		require.ErrorIs(t, err, ErrSequenceNotUsed)
+
+		catalog, err := engine.Catalog(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, catalog.GetSequences(), 3)
+	})
+}
+
+func TestDecimalType(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	t.Run("create tables with decimal columns", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE t1(id DECIMAL(39,2), PRIMARY KEY id)", nil)
+		require.ErrorContains(t, err, "DECIMAL precision 39 must be between 1 and 38")
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1(id DECIMAL(2,3), PRIMARY KEY id)", nil)
+		require.ErrorContains(t, err, "DECIMAL scale 3 must be between 0 and precision 2")
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1(id VARCHAR(10,2), PRIMARY KEY id)", nil)
+		require.ErrorContains(t, err, "invalid type modifier for VARCHAR")
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE accounts(
+				code DECIMAL(6,0),
+				balance NUMERIC(10,2) NOT NULL,
+				rate DECIMAL,
+				PRIMARY KEY code
+			);
+
+			CREATE INDEX ON accounts(balance);
+
+			CREATE TABLE movements(
+				id INTEGER AUTO_INCREMENT,
+				amount DECIMAL(12,2),
+				fee DECIMAL(12,2) GENERATED ALWAYS AS (amount * 0.015),
+				PRIMARY KEY id
+			);
+		`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT column_name, type_name FROM TABLE(accounts)", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, "DECIMAL(6,0)", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "DECIMAL(10,2)", rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, "DECIMAL", rows[2].ValuesByPosition[1].RawValue())
+
+		catalog, err := engine.Catalog(context.Background(), nil)
+		require.NoError(t, err)
+
+		table, err := catalog.GetTableByName("accounts")
+		require.NoError(t, err)
+
+		col, err := table.GetColumnByName("balance")
+		require.NoError(t, err)
+		require.Equal(t, DecimalType, col.Type())
+		require.Equal(t, 10, col.Precision())
+		require.Equal(t, 2, col.Scale())
+
+		require.Equal(t,
+			[][]interface{}{
+				{DecimalType, int64(6), int64(0)},
+				{DecimalType, int64(10), int64(2)},
+				{DecimalType, int64(0), nil},
+			},
+			queryRawRows(t, engine, "SELECT type, max_length, scale FROM COLUMNS('accounts')", nil),
+		)
+	})
+
+	t.Run("values are rounded to the scale of the column", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			INSERT INTO accounts(code, balance, rate) VALUES
+				(1, 100, 0.0125),
+				(2, 20.005, NULL),
+				(3, '-0.5', 1.50),
+				(4, @balance, 2)
+		`, map[string]interface{}{"balance": "12345678.904"})
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]interface{}{"1", "2", "3", "4"},
+			queryRawValues(t, engine, "SELECT code FROM accounts", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"100.00", "20.01", "-0.50", "12345678.90"},
+			queryRawValues(t, engine, "SELECT balance FROM accounts", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"0.0125", nil, "1.5", "2"},
+			queryRawValues(t, engine, "SELECT rate FROM accounts", nil),
+		)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts(code, balance) VALUES (5, 123456789)", nil)
+		require.ErrorIs(t, err, ErrNumericFieldOverflow)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts(code, balance) VALUES (1.4, 0)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts(code, balance) VALUES (5, 'abc')", nil)
+		require.ErrorIs(t, err, ErrUnsupportedCast)
+	})
+
+	t.Run("decimal values are ordered in indexes", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{"-0.50", "20.01", "100.00", "12345678.90"},
+			queryRawValues(t, engine, "SELECT balance FROM accounts USE INDEX ON (balance)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"12345678.90", "100.00", "20.01", "-0.50"},
+			queryRawValues(t, engine, "SELECT balance FROM accounts ORDER BY balance DESC", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"20.01", "100.00"},
+			queryRawValues(t, engine, "SELECT balance FROM accounts WHERE balance > 20.005 AND balance <= @max ORDER BY balance", map[string]interface{}{"max": "100"}),
+		)
+
+		require.Equal(t,
+			[]interface{}{"3"},
+			queryRawValues(t, engine, "SELECT code FROM accounts WHERE code = 3.0", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"2"},
+			queryRawValues(t, engine, "SELECT code FROM accounts WHERE balance = 20.01", nil),
+		)
+	})
+
+	t.Run("arithmetic is exact", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			INSERT INTO movements(amount) VALUES (0.10), (0.20), (0.30), (100.33)
+		`, nil)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]interface{}{"0.00", "0.00", "0.00", "1.50"},
+			queryRawValues(t, engine, "SELECT fee FROM movements", nil),
+		)
+
+		require.Equal(t, []interface{}{"100.93"}, queryRawValues(t, engine, "SELECT SUM(amount) FROM movements", nil))
+		require.Equal(t, []interface{}{"25.2325000000000000"}, queryRawValues(t, engine, "SELECT AVG(amount) FROM movements", nil))
+		require.Equal(t, []interface{}{"0.10"}, queryRawValues(t, engine, "SELECT MIN(amount) FROM movements", nil))
+
+		require.Equal(t,
+			[]interface{}{"0.30", "0.60", "0.90", "300.99"},
+			queryRawValues(t, engine, "SELECT amount * 3 FROM movements", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{true},
+			queryRawValues(t, engine, "SELECT 0.1::DECIMAL + 0.2::DECIMAL = 0.3::DECIMAL", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"0.3333333333333333"},
+			queryRawValues(t, engine, "SELECT CAST(1 AS DECIMAL) / 3", nil),
+		)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT amount / 0 FROM movements", nil)
+		require.ErrorIs(t, err, ErrDivisionByZero)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE movements SET amount = amount + 0.005 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]interface{}{"0.11"},
+			queryRawValues(t, engine, "SELECT amount FROM movements WHERE id = 1", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"0.115", "0.205"},
+			queryRawValues(t, engine, "SELECT amount + 0.005 FROM movements WHERE id <= 2", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"0.705"},
+			queryRawValues(t, engine, "SELECT 1.005 - amount FROM movements WHERE id = 3", nil),
+		)
+	})
+
+	t.Run("decimal casts", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, `
+			SELECT
+				CAST('12.345' AS DECIMAL(5,2)),
+				CAST(12.5 AS DECIMAL),
+				CAST(CAST('12.5' AS DECIMAL) AS INTEGER),
+				CAST(CAST('12.25' AS DECIMAL) AS FLOAT),
+				CAST(CAST('-12.50' AS DECIMAL) AS VARCHAR),
+				'7.125'::DECIMAL(4,1)
+		`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		require.Equal(t, "12.35", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "12.5", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(13), rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, 12.25, rows[0].ValuesByPosition[3].RawValue())
+		require.Equal(t, "-12.50", rows[0].ValuesByPosition[4].RawValue())
+		require.Equal(t, "7.1", rows[0].ValuesByPosition[5].RawValue())
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT CAST(1000 AS DECIMAL(3,1))", nil)
+		require.ErrorIs(t, err, ErrNumericFieldOverflow)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT CAST(true AS DECIMAL)", nil)
+		require.ErrorIs(t, err, ErrUnsupportedCast)
+	})
+
+	t.Run("alter the scale of a decimal column", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE accounts ALTER COLUMN rate TYPE DECIMAL(3,1)", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+		require.ErrorContains(t, err, "0.0125 can not be converted exactly")
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET rate = 0 WHERE code = 1", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE accounts ALTER COLUMN rate TYPE DECIMAL(3,1)", nil)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]interface{}{"0.0", nil, "1.5", "2.0"},
+			queryRawValues(t, engine, "SELECT rate FROM accounts", nil),
+		)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE movements ALTER COLUMN amount TYPE DECIMAL(2,1)", nil)
+		require.ErrorIs(t, err, ErrCannotAlterColumn)
+	})
+}
+
+func TestArrayType(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	t.Run("create tables with array columns", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE t1(id INTEGER, data JSON[], PRIMARY KEY id)", nil)
+		require.ErrorContains(t, err, "arrays of type JSON are not supported")
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1(id INTEGER[], PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrCannotIndexArray)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE posts(
+				id INTEGER AUTO_INCREMENT,
+				title VARCHAR,
+				tags VARCHAR(10)[],
+				scores INTEGER[] NOT NULL DEFAULT ARRAY[]::INTEGER[],
+				prices DECIMAL(6,2)[],
+				PRIMARY KEY id
+			)
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON posts(tags)", nil)
+		require.ErrorIs(t, err, ErrCannotIndexArray)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT column_name, type_name FROM TABLE(posts)", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+		require.Equal(t, "VARCHAR(10)[]", rows[2].ValuesByPosition[1].RawValue())
+		require.Equal(t, "INTEGER[]", rows[3].ValuesByPosition[1].RawValue())
+		require.Equal(t, "DECIMAL(6,2)[]", rows[4].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("insert arrays", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			INSERT INTO posts(title, tags, scores, prices) VALUES
+				('first', ARRAY['go', 'sql'], ARRAY[3, 1, 2], ARRAY[1.5, 2]),
+				('second', '{sql,"immu db"}', '{10}', NULL),
+				('third', ARRAY[]::VARCHAR[], ARRAY[], NULL)
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title, tags) VALUES ('fourth', @tags)", map[string]interface{}{"tags": []string{"go", "kv"}})
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title) VALUES ('fifth')", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title, tags) VALUES ('sixth', ARRAY['too long for the column'])", nil)
+		require.ErrorIs(t, err, ErrMaxLengthExceeded)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title, tags) VALUES ('sixth', ARRAY['go', NULL])", nil)
+		require.ErrorIs(t, err, ErrArrayNullElement)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title, scores) VALUES ('sixth', '{1,x}')", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title, scores) VALUES ('sixth', '{1,{2}}')", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO posts(title, scores) VALUES ('sixth', ARRAY[1, 'a'])", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT tags, scores, prices FROM posts", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+
+		require.Equal(t, []interface{}{"go", "sql"}, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, []interface{}{int64(3), int64(1), int64(2)}, rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, []interface{}{"1.50", "2.00"}, rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, ArrayType(DecimalType), rows[0].ValuesByPosition[2].Type())
+
+		require.Equal(t, []interface{}{"sql", "immu db"}, rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, `{sql,"immu db"}`, rows[1].ValuesByPosition[0].(*Array).Text())
+		require.True(t, rows[1].ValuesByPosition[2].IsNull())
+
+		require.Equal(t, []interface{}{}, rows[2].ValuesByPosition[0].RawValue())
+		require.Equal(t, []interface{}{}, rows[2].ValuesByPosition[1].RawValue())
+
+		require.Equal(t, []interface{}{"go", "kv"}, rows[3].ValuesByPosition[0].RawValue())
+
+		require.True(t, rows[4].ValuesByPosition[0].IsNull())
+		require.Equal(t, []interface{}{}, rows[4].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("query with array operators", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{"first", "second"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE 'sql' = ANY(tags)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first", "second"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE 5 < ANY(scores) OR 2 = ANY(scores)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first", "fourth"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags @> ARRAY['go']", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags @> '{sql,go}'", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first", "second", "third", "fourth"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags @> ARRAY[]", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"second", "fourth"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags && ARRAY['kv', 'immu db']", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags = ARRAY['go', 'sql']", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"fourth"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags = @tags", map[string]interface{}{"tags": []interface{}{"go", "kv"}}),
+		)
+
+		require.Equal(t,
+			[]interface{}{"third", "fourth", "first", "second"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE tags IS NOT NULL ORDER BY tags", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first", "second", "fourth"},
+			queryRawValues(t, engine, "SELECT title FROM posts WHERE @tag = ANY(tags) OR tags && @tags", map[string]interface{}{"tag": "go", "tags": "{sql}"}),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT title FROM posts WHERE 1 = ANY(tags)", nil)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT title FROM posts WHERE title @> ARRAY['a']", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+
+	t.Run("array functions", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{int64(3), int64(1), nil, nil, nil},
+			queryRawValues(t, engine, "SELECT ARRAY_LENGTH(scores, 1) FROM posts", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{nil},
+			queryRawValues(t, engine, "SELECT ARRAY_LENGTH(ARRAY[1, 2], 2)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"go", "sql"},
+			queryRawValues(t, engine, "SELECT * FROM UNNEST(ARRAY['go', 'sql'])", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{int64(2), int64(3)},
+			queryRawValues(t, engine, "SELECT u.unnest FROM UNNEST(@scores) AS u WHERE u.unnest > 1 ORDER BY u.unnest", map[string]interface{}{"scores": []int64{3, 1, 2}}),
+		)
+
+		require.Equal(t,
+			[]interface{}{"first", "first"},
+			queryRawValues(t, engine, "SELECT title FROM posts INNER JOIN UNNEST('{1,2}'::INTEGER[]) AS u ON u.unnest = ANY(posts.scores)", nil),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT * FROM UNNEST(ARRAY[])", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM UNNEST(1)", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+
+	t.Run("cast arrays", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{"{go,sql}"},
+			queryRawValues(t, engine, "SELECT CAST(tags AS VARCHAR) FROM posts WHERE title = 'first'", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{[]interface{}{1.0, 2.5}},
+			queryRawValues(t, engine, "SELECT ARRAY[1, 2.5]", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{[]interface{}{"1.00", "2.50"}},
+			queryRawValues(t, engine, "SELECT '{1, 2.499}'::DECIMAL(3,2)[]", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{[]interface{}{true, false}},
+			queryRawValues(t, engine, "SELECT CAST('{t,false}' AS BOOLEAN[])", nil),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT CAST(1 AS INTEGER[])", nil)
+		require.ErrorIs(t, err, ErrUnsupportedCast)
+	})
+
+	t.Run("update arrays", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE posts SET scores = ARRAY[4, 5] WHERE title = 'third'", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE posts ALTER COLUMN title TYPE VARCHAR[]", nil)
+		require.ErrorContains(t, err, "malformed array literal")
+
+		require.Equal(t,
+			[]interface{}{[]interface{}{int64(4), int64(5)}},
+			queryRawValues(t, engine, "SELECT scores FROM posts WHERE title = 'third'", nil),
+		)
+	})
+}
+
+func TestIntervalAndDateTimeFunctions(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	ts := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04:05", s)
+		require.NoError(t, err)
+		return v
+	}
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE events(
+			id INTEGER AUTO_INCREMENT,
+			ts TIMESTAMP,
+			PRIMARY KEY id
+		);
+
+		CREATE INDEX ON events(ts);
+
+		INSERT INTO events(ts) VALUES
+			(CAST('2024-01-31 10:30:15' AS TIMESTAMP)),
+			(CAST('2024-02-29 23:59:59' AS TIMESTAMP)),
+			(CAST('2024-12-30 00:00:00' AS TIMESTAMP)),
+			(NULL);
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("interval literals", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{"7 days"},
+			queryRawValues(t, engine, "SELECT INTERVAL '1 week'", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"1 year 2 mons 3 days 04:05:06.5"},
+			queryRawValues(t, engine, "SELECT '1 year 2 months 3 days 4 hours 5 minutes 6.5 seconds'::INTERVAL", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"-1 days -02:00:00"},
+			queryRawValues(t, engine, "SELECT CAST('1 day 2 hours ago' AS INTERVAL)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"1 day"},
+			queryRawValues(t, engine, "SELECT CAST(INTERVAL '1 day' AS VARCHAR)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"00:01:30"},
+			queryRawValues(t, engine, "SELECT @d", map[string]interface{}{"d": 90 * time.Second}),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT INTERVAL '1 fortnight'", nil)
+		require.ErrorIs(t, err, ErrUnsupportedCast)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1(id INTEGER, d INTERVAL, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrIntervalColumnNotSupported)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE events ADD COLUMN d INTERVAL", nil)
+		require.ErrorIs(t, err, ErrIntervalColumnNotSupported)
+	})
+
+	t.Run("interval arithmetic", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{ts("2024-02-29 10:30:15"), ts("2024-03-29 23:59:59"), ts("2025-01-30 00:00:00"), nil},
+			queryRawValues(t, engine, "SELECT ts + INTERVAL '1 month' FROM events", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{ts("2024-01-30 09:30:15"), ts("2024-02-28 22:59:59"), ts("2024-12-28 23:00:00"), nil},
+			queryRawValues(t, engine, "SELECT ts - INTERVAL '1 day 1 hour' FROM events", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{ts("2023-01-31 10:30:15")},
+			queryRawValues(t, engine, "SELECT INTERVAL '-1 year' + ts FROM events WHERE id = 1 AND ts + INTERVAL '1 year' > ts", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"29 days 13:29:44", "304 days 00:00:01"},
+			queryRawValues(t, engine, "SELECT e2.ts - e1.ts FROM events AS e1 INNER JOIN events AS e2 ON e2.id = e1.id + 1 WHERE e2.ts IS NOT NULL", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"2 days -01:00:00"},
+			queryRawValues(t, engine, "SELECT INTERVAL '2 days' - INTERVAL '1 hour'", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{true},
+			queryRawValues(t, engine, "SELECT INTERVAL '1 month' > INTERVAL '29 days' AND INTERVAL '24 hours' = '1 day'", nil),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT ts + ts FROM events", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT ts * INTERVAL '1 day' FROM events", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+
+	t.Run("date/time functions", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{ts("2024-01-01 00:00:00"), ts("2024-02-01 00:00:00"), ts("2024-12-01 00:00:00"), nil},
+			queryRawValues(t, engine, "SELECT DATE_TRUNC('month', ts) FROM events", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{ts("2024-01-29 00:00:00"), ts("2024-02-26 00:00:00"), ts("2024-12-30 00:00:00"), nil},
+			queryRawValues(t, engine, "SELECT DATE_TRUNC('week', ts) FROM events", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"2024", "2024", "2024", nil},
+			queryRawValues(t, engine, "SELECT EXTRACT(YEAR FROM ts) FROM events", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"15.000000", "59.000000"},
+			queryRawValues(t, engine, "SELECT EXTRACT(second FROM ts) FROM events WHERE ts IS NOT NULL AND EXTRACT(month FROM ts) < 3", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{int64(1), int64(2)},
+			queryRawValues(t, engine, "SELECT id FROM events WHERE EXTRACT(quarter FROM ts) = 1", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{float64(1)},
+			queryRawValues(t, engine, "SELECT DATE_PART('week', ts) FROM events WHERE id = 3", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"93600.000000"},
+			queryRawValues(t, engine, "SELECT EXTRACT(EPOCH FROM INTERVAL '1 day 2 hours')", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"2024-01-31 10:30:15", "Thursday  29 Feb 2024 11:59 PM"},
+			queryRawValues(t, engine, "SELECT TO_CHAR(ts, CASE WHEN id = 1 THEN 'YYYY-MM-DD HH24:MI:SS' ELSE 'Day DD Mon YYYY HH12:MI AM' END) FROM events WHERE id < 3", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"1 mon 30 days 13:29:45"},
+			queryRawValues(t, engine, "SELECT AGE(CAST('2024-03-31' AS TIMESTAMP), ts) FROM events WHERE id = 1", nil),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT DATE_TRUNC('fortnight', ts) FROM events", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT EXTRACT(dow FROM INTERVAL '1 day')", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT LENGTH(year FROM ts) FROM events", nil)
+		require.ErrorContains(t, err, "unexpected FROM in call to function length")
+	})
+
+	t.Run("ranges are computed from expressions evaluated within the transaction", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT id FROM events WHERE ts > NOW() - INTERVAL '1 day' ORDER BY ts", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		scanSpecs := r.ScanSpecs()
+		require.NotNil(t, scanSpecs.Index)
+		require.False(t, scanSpecs.Index.IsPrimary())
+		require.Len(t, scanSpecs.rangesByColID, 1)
+
+		tsRange := scanSpecs.rangesByColID[2]
+		require.NotNil(t, tsRange.lRange)
+		require.False(t, tsRange.lRange.inclusive)
+		require.Equal(t, TimestampType, tsRange.lRange.val.Type())
+		require.Nil(t, tsRange.hRange)
+
+		require.Equal(t,
+			[]interface{}{int64(2)},
+			queryRawValues(t, engine, "SELECT id FROM events WHERE ts >= @since - INTERVAL '1 hour' AND ts < DATE_TRUNC('month', @since) + INTERVAL '1 month' ORDER BY ts", map[string]interface{}{"since": ts("2024-02-29 23:00:00")}),
+		)
+	})
+}
+
+func TestQueryTxMetadata(t *testing.T) {
+	opts := store.DefaultOptions().WithMultiIndexing(true)
+	opts.WithIndexOptions(opts.IndexOpts.WithMaxActiveSnapshots(1))
+
+	st, err := store.Open(t.TempDir(), opts)
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st,
+		DefaultOptions().WithPrefix(sqlPrefix).WithParseTxMetadataFunc(func(b []byte) (map[string]interface{}, error) {
+			var md map[string]interface{}
+			err := json.Unmarshal(b, &md)
+			return md, err
+		}),
+	)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(
+		context.Background(), nil,
+		`
+		CREATE TABLE mytbl (
+			id INTEGER AUTO_INCREMENT,
+
+			PRIMARY KEY(id)
+		)`, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		extra, err := json.Marshal(map[string]interface{}{
+			"n": i + 1,
+		})
+		require.NoError(t, err)
+
+		txOpts := DefaultTxOptions().WithExtra(extra)
+		tx, err := engine.NewTx(context.Background(), txOpts)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(
+			context.Background(),
+			tx,
+			fmt.Sprintf("INSERT INTO mytbl(id) VALUES (%d)", i+1),
+			nil,
+		)
+		require.NoError(t, err)
+	}
 
 	rows, err := engine.queryAll(
 		context.Background(),
@@ -9619,6 +12842,453 @@ func TestTableResolver(t *testing.T) {
 	)
 }
 
+func TestConditionalAndMathFunctions(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	value := func(t *testing.T, q string, params map[string]interface{}) interface{} {
+		vals := queryRawValues(t, engine, q, params)
+		require.Len(t, vals, 1)
+		return vals[0]
+	}
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE people(
+			id INTEGER AUTO_INCREMENT,
+			name VARCHAR,
+			nickname VARCHAR,
+			score INTEGER,
+			balance DECIMAL(10,2),
+			ratio FLOAT,
+			PRIMARY KEY id
+		);
+
+		INSERT INTO people(name, nickname, score, balance, ratio) VALUES
+			('john', 'johnny', 10, 12.45, 0.5),
+			('mary', NULL, -7, -3.5, NULL),
+			('anna', NULL, NULL, NULL, 2.25);
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("conditional functions", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{"johnny", "mary", "anna"},
+			queryRawValues(t, engine, "SELECT COALESCE(nickname, name) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"johnny", "guest", "guest"},
+			queryRawValues(t, engine, "SELECT COALESCE(nickname, @default) FROM people", map[string]interface{}{"default": "guest"}),
+		)
+
+		require.Equal(t,
+			[]interface{}{0.5, -7.0, 2.25},
+			queryRawValues(t, engine, "SELECT COALESCE(ratio, score, 0) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{nil},
+			queryRawValues(t, engine, "SELECT COALESCE(NULL, NULL)", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{nil, int64(-7), nil},
+			queryRawValues(t, engine, "SELECT NULLIF(score, 10) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{int64(10), int64(5), int64(5)},
+			queryRawValues(t, engine, "SELECT GREATEST(score, 0, NULL, 5) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"anna", "anna", "anna"},
+			queryRawValues(t, engine, "SELECT LEAST(name, 'anna', nickname) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"12.45", "-3.50", "-4"},
+			queryRawValues(t, engine, "SELECT GREATEST(balance, -4, NULL) FROM people", nil),
+		)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT COALESCE(name, score) FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT COALESCE() FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT NULLIF(name) FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("math functions", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{int64(10), int64(7), nil},
+			queryRawValues(t, engine, "SELECT ABS(score) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"12.45", "3.50", nil},
+			queryRawValues(t, engine, "SELECT ABS(balance) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"12.5", "-3.5", nil},
+			queryRawValues(t, engine, "SELECT ROUND(balance, 1) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"12", "-4", nil},
+			queryRawValues(t, engine, "SELECT ROUND(balance) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"12", "-4", nil},
+			queryRawValues(t, engine, "SELECT FLOOR(balance) FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{"13", "-3", nil},
+			queryRawValues(t, engine, "SELECT CEIL(balance) FROM people", nil),
+		)
+
+		require.Equal(t, int64(1300), value(t, "SELECT ROUND(1250, -2)", nil))
+		require.Equal(t, int64(-7), value(t, "SELECT ROUND(-7, 1)", nil))
+		require.Equal(t, 3.14, value(t, "SELECT ROUND(3.14159, 2)", nil))
+		require.Equal(t, -3.0, value(t, "SELECT ROUND(-2.5)", nil))
+		require.Equal(t, "1300", value(t, "SELECT ROUND(1250.5::DECIMAL, -2)", nil))
+		require.Equal(t, 2.0, value(t, "SELECT CEILING(1.2)", nil))
+		require.Equal(t, -2.0, value(t, "SELECT FLOOR(-1.5)", nil))
+		require.Equal(t, int64(-7), value(t, "SELECT FLOOR(-7)", nil))
+
+		require.Equal(t,
+			[]interface{}{int64(1), int64(-1), nil},
+			queryRawValues(t, engine, "SELECT MOD(score, 3) FROM people", nil),
+		)
+
+		require.Equal(t, 1024.0, value(t, "SELECT POWER(2, 10)", nil))
+		require.Equal(t, 0.5, value(t, "SELECT POWER(@base, -1)", map[string]interface{}{"base": 2.0}))
+		require.Equal(t, 1.5, value(t, "SELECT SQRT(2.25)", nil))
+		require.Equal(t, 3.0, value(t, "SELECT SQRT(9::DECIMAL)", nil))
+		require.Nil(t, value(t, "SELECT SQRT(NULL)", nil))
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT SQRT(-1)", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT MOD(score, 0) FROM people", nil)
+		require.ErrorIs(t, err, ErrDivisionByZero)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT ABS(name) FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT ROUND(ratio, 'a') FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("string functions", func(t *testing.T) {
+		require.Equal(t,
+			[]interface{}{"j_hn", "mary", "anna"},
+			queryRawValues(t, engine, "SELECT REPLACE(name, 'o', '_') FROM people", nil),
+		)
+
+		require.Equal(t,
+			[]interface{}{int64(0), int64(2), int64(1)},
+			queryRawValues(t, engine, "SELECT POSITION('a' IN name) FROM people", nil),
+		)
+		require.Equal(t, int64(3), value(t, "SELECT POSITION('c', 'abc')", nil))
+
+		require.Equal(t,
+			[]interface{}{"000john", "000mary", "000anna"},
+			queryRawValues(t, engine, "SELECT LPAD(name, 7, '0') FROM people", nil),
+		)
+		require.Equal(t, "jo", value(t, "SELECT LPAD('john', 2)", nil))
+		require.Equal(t, "ab   ", value(t, "SELECT RPAD('ab', 5)", nil))
+		require.Equal(t, "abxyx", value(t, "SELECT RPAD('ab', 5, 'xy')", nil))
+		require.Equal(t, "", value(t, "SELECT RPAD('ab', -1)", nil))
+
+		require.Equal(t, "Xbc-abc-abc", value(t, "SELECT REGEXP_REPLACE('abc-abc-abc', 'a', 'X')", nil))
+		require.Equal(t, "XbcXBC", value(t, "SELECT REGEXP_REPLACE('abcABC', 'a', 'X', 'gi')", nil))
+		require.Equal(t, "doe, john $1", value(t, "SELECT REGEXP_REPLACE('john doe', '(\\w+) (\\w+)', '\\2, \\1 $1')", nil))
+		require.Equal(t, "[abc]", value(t, "SELECT REGEXP_REPLACE('abc', '.+', '[\\&]')", nil))
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT REGEXP_REPLACE('abc', '(', 'x')", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT REGEXP_REPLACE('abc', 'a', 'x', 'z')", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		require.Equal(t, "def", value(t, "SELECT SPLIT_PART('abc~@~def~@~ghi', '~@~', 2)", nil))
+		require.Equal(t, "ghi", value(t, "SELECT SPLIT_PART('abc,def,ghi', ',', -1)", nil))
+		require.Equal(t, "", value(t, "SELECT SPLIT_PART('abc,def,ghi', ',', 4)", nil))
+		require.Equal(t, "abc", value(t, "SELECT SPLIT_PART('abc', '', 1)", nil))
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT SPLIT_PART('abc', ',', 0)", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT REPLACE(name, 1, 'x') FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT LPAD(name) FROM people", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("parameters are typed from the arguments", func(t *testing.T) {
+		params, err := engine.InferParameters(context.Background(), nil, "SELECT COALESCE(@nickname, name) FROM people")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"nickname": VarcharType}, params)
+
+		params, err = engine.InferParameters(context.Background(), nil, "SELECT * FROM people WHERE COALESCE(@a, @b) = score")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"a": IntegerType, "b": IntegerType}, params)
+
+		params, err = engine.InferParameters(context.Background(), nil, "SELECT LPAD(@s, @n, @fill), SPLIT_PART(name, @delim, @pos) FROM people")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{
+			"s":     VarcharType,
+			"n":     IntegerType,
+			"fill":  VarcharType,
+			"delim": VarcharType,
+			"pos":   IntegerType,
+		}, params)
+
+		params, err = engine.InferParameters(context.Background(), nil, "SELECT ROUND(balance, @digits), SQRT(@x), MOD(score, @m) FROM people WHERE ABS(@y) > ratio")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{
+			"digits": IntegerType,
+			"x":      Float64Type,
+			"m":      IntegerType,
+			"y":      Float64Type,
+		}, params)
+
+		params, err = engine.InferParameters(context.Background(), nil, "SELECT * FROM people WHERE POSITION(@sub IN name) > 0 AND NULLIF(name, @name) IS NOT NULL")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"sub": VarcharType, "name": VarcharType}, params)
+
+		_, err = engine.InferParameters(context.Background(), nil, "SELECT * FROM people WHERE COALESCE(name, score) = @x")
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, err = engine.InferParameters(context.Background(), nil, "SELECT * FROM people WHERE REPLACE(name, 1, @s) = name")
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		rows, err := engine.queryAll(context.Background(), nil,
+			"SELECT name FROM people WHERE COALESCE(@nickname, nickname, name) = name",
+			map[string]interface{}{"nickname": nil},
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+	})
+}
+
+func TestUserDefinedFunctions(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	tenantFn := &mockFunction{
+		t: VarcharType,
+		apply: func(params []TypedValue) (TypedValue, error) {
+			if len(params) != 1 || params[0].Type() != VarcharType {
+				return nil, ErrIllegalArguments
+			}
+			tenant, _, _ := strings.Cut(params[0].RawValue().(string), ":")
+			return NewVarchar(tenant), nil
+		},
+	}
+
+	checksumFn := &mockFunction{
+		t: BooleanType,
+		apply: func(params []TypedValue) (TypedValue, error) {
+			if len(params) != 1 || params[0].Type() != VarcharType {
+				return nil, ErrIllegalArguments
+			}
+
+			sum := 0
+			for _, ch := range params[0].RawValue().(string) {
+				sum += int(ch - '0')
+			}
+			return NewBool(sum%10 == 0), nil
+		},
+	}
+
+	calls := 0
+	counterFn := &mockFunction{
+		t: IntegerType,
+		apply: func(params []TypedValue) (TypedValue, error) {
+			calls++
+			return NewInteger(int64(calls)), nil
+		},
+	}
+
+	engine, err := NewEngine(
+		st,
+		DefaultOptions().
+			WithPrefix(sqlPrefix).
+			WithFunction("tenant_id", tenantFn, true).
+			WithFunction("VALID_CHECKSUM", checksumFn, true),
+	)
+	require.NoError(t, err)
+
+	err = engine.RegisterFunction("counter", counterFn, false)
+	require.NoError(t, err)
+
+	t.Run("functions can not be registered twice", func(t *testing.T) {
+		err := engine.RegisterFunction("TENANT_ID", tenantFn, true)
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+		err = engine.RegisterFunction("length", tenantFn, true)
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+		_, err = NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithFunction("now", counterFn, false))
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+	})
+
+	t.Run("functions must be callable by name", func(t *testing.T) {
+		for _, name := range []string{"", "1fn", "tenant-id", "select", "count", "varchar", "true", "inner"} {
+			err := engine.RegisterFunction(name, tenantFn, true)
+			require.ErrorIs(t, err, ErrIllegalArguments)
+		}
+
+		err := engine.RegisterFunction("nil_fn", nil, true)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE accounts(
+			id VARCHAR[64],
+			code VARCHAR,
+			tenant VARCHAR GENERATED ALWAYS AS (tenant_id(id)) STORED,
+			CHECK valid_checksum(code),
+			PRIMARY KEY id
+		);
+
+		INSERT INTO accounts(id, code) VALUES ('acme:1', '127'), ('acme:2', '55'), ('globex:1', '1234');
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("functions are evaluated within statements", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT id, UPPER(tenant_id(id)) FROM accounts WHERE tenant = @tenant AND valid_checksum(code) ORDER BY id",
+			map[string]interface{}{"tenant": "acme"},
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "acme:1", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "ACME", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "acme:2", rows[1].ValuesByPosition[0].RawValue())
+
+		params, err := engine.InferParameters(context.Background(), nil, "SELECT id FROM accounts WHERE tenant_id(id) = 'acme' AND counter() > @n")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"n": IntegerType}, params)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts(id, code) VALUES ('acme:3', '128')", nil)
+		require.ErrorIs(t, err, ErrCheckConstraintViolation)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT tenant_id(code, id) FROM accounts", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("non deterministic functions can not be used in constraints", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE t1(id INTEGER, CHECK counter() > 0, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrNoSupported)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1(id INTEGER, n INTEGER GENERATED ALWAYS AS (counter() + id), PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidGeneratedColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t1(id INTEGER, n INTEGER DEFAULT counter(), PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("functions are registered per engine", func(t *testing.T) {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		defer closeStore(t, st)
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT tenant_id('acme:1')", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("functions used by the catalog must be registered when reopening the engine", func(t *testing.T) {
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithFunction("counter", counterFn, false))
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM accounts", nil)
+		require.ErrorIs(t, err, ErrFunctionDoesNotExist)
+		require.ErrorContains(t, err, "function 'valid_checksum' used by table 'accounts' is not registered")
+
+		err = engine.RegisterFunction("valid_checksum", checksumFn, true)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts(id, code) VALUES ('acme:4', '19')", nil)
+		require.ErrorIs(t, err, ErrFunctionDoesNotExist)
+		require.ErrorContains(t, err, "function 'tenant_id' used by table 'accounts' is not registered")
+
+		err = engine.RegisterFunction("tenant_id", tenantFn, true)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM accounts", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts(id, code) VALUES ('acme:4', '19')", nil)
+		require.NoError(t, err)
+	})
+}
+
+type mockFunction struct {
+	t     SQLValueType
+	apply func(params []TypedValue) (TypedValue, error)
+}
+
+func (f *mockFunction) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return f.t, nil
+}
+
+func (f *mockFunction) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != f.t {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, f.t, t)
+	}
+	return nil
+}
+
+func (f *mockFunction) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	return f.apply(params)
+}
+
+// queryRawRows returns the raw values of the rows produced by the query
+func queryRawRows(t *testing.T, engine *Engine, query string, params map[string]interface{}) [][]interface{} {
+	reader, err := engine.Query(context.Background(), nil, query, params)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	rows, err := ReadAllRows(context.Background(), reader)
+	require.NoError(t, err)
+
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(row.ValuesByPosition))
+		for j, v := range row.ValuesByPosition {
+			values[i][j] = v.RawValue()
+		}
+	}
+	return values
+}
+
+// queryRawValues returns the raw values of the first column of the rows produced by the query
+func queryRawValues(t *testing.T, engine *Engine, query string, params map[string]interface{}) []interface{} {
+	rows, err := engine.queryAll(context.Background(), nil, query, params)
+	require.NoError(t, err)
+
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = row.ValuesByPosition[0].RawValue()
+	}
+	return values
+}
+
 func assertQueryShouldProduceResults(t *testing.T, e *Engine, query, resultQuery string) {
 	queryReader, err := e.Query(context.Background(), nil, query, nil)
 	require.NoError(t, err)
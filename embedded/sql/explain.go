@@ -0,0 +1,458 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const explainPlanCol = "plan"
+
+// ExplainStmt describes how a query is resolved, i.e. the tree of row readers, the selected index and
+// the key ranges used to scan it. When analyze is set, the query is also executed and the number of rows
+// produced by each reader, together with the time spent on it, is reported.
+type ExplainStmt struct {
+	analyze bool
+	q       DataSource
+}
+
+func NewExplainStmt(q DataSource, analyze bool) *ExplainStmt {
+	return &ExplainStmt{
+		analyze: analyze,
+		q:       q,
+	}
+}
+
+// Query returns the query being explained
+func (stmt *ExplainStmt) Query() DataSource {
+	return stmt.q
+}
+
+// Analyze returns true when the query is executed while resolving the statement
+func (stmt *ExplainStmt) Analyze() bool {
+	return stmt.analyze
+}
+
+func (stmt *ExplainStmt) readOnly() bool {
+	return true
+}
+
+func (stmt *ExplainStmt) requiredPrivileges() []SQLPrivilege {
+	return stmt.q.requiredPrivileges()
+}
+
+func (stmt *ExplainStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return stmt.q.inferParameters(ctx, tx, params)
+}
+
+func (stmt *ExplainStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	return stmt.q.execAt(ctx, tx, params)
+}
+
+func (stmt *ExplainStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
+	if tx == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	rowReader, err := stmt.q.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &planBuilder{
+		ctx:     ctx,
+		tx:      tx,
+		params:  params,
+		analyze: stmt.analyze,
+	}
+
+	plan, err := b.node(&rowReader)
+	if err == nil && stmt.analyze {
+		err = drainRows(ctx, rowReader)
+	}
+
+	closeErr := rowReader.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	var lines []string
+	plan.render(0, &lines)
+
+	values := make([][]ValueExp, len(lines))
+	for i, line := range lines {
+		values[i] = []ValueExp{&Varchar{val: line}}
+	}
+
+	cols := []ColDescriptor{
+		{
+			Column: explainPlanCol,
+			Type:   VarcharType,
+		},
+	}
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *ExplainStmt) Alias() string {
+	return "explain"
+}
+
+func drainRows(ctx context.Context, rowReader RowReader) error {
+	for {
+		_, err := rowReader.Read(ctx)
+		if err == ErrNoMoreRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type planNode struct {
+	desc     string
+	stats    *analyzedRowReader
+	children []*planNode
+}
+
+func (n *planNode) render(depth int, lines *[]string) {
+	var sb strings.Builder
+
+	if depth > 0 {
+		sb.WriteString(strings.Repeat("  ", depth-1))
+		sb.WriteString("-> ")
+	}
+	sb.WriteString(n.desc)
+
+	if n.stats != nil {
+		sb.WriteString(fmt.Sprintf(" (rows=%d time=%s)", n.stats.rows, n.stats.elapsed))
+	}
+	*lines = append(*lines, sb.String())
+
+	for _, child := range n.children {
+		child.render(depth+1, lines)
+	}
+}
+
+// planBuilder walks a tree of row readers producing its description.
+// When analyzing a query, every reader is replaced by an analyzedRowReader
+// so to collect the number of rows it produces and the time spent reading them.
+type planBuilder struct {
+	ctx     context.Context
+	tx      *SQLTx
+	params  map[string]interface{}
+	analyze bool
+}
+
+func (b *planBuilder) node(rowReader *RowReader) (*planNode, error) {
+	n, err := b.describe(*rowReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.analyze {
+		analyzedReader := &analyzedRowReader{RowReader: *rowReader}
+		*rowReader = analyzedReader
+		n.stats = analyzedReader
+	}
+	return n, nil
+}
+
+func (b *planBuilder) describe(rowReader RowReader) (*planNode, error) {
+	switch r := rowReader.(type) {
+	case *rawRowReader:
+		{
+			return &planNode{desc: describeScan(r.table, r.tableAlias, r.scanSpecs)}, nil
+		}
+	case *valuesRowReader:
+		{
+			return &planNode{desc: fmt.Sprintf("values (%d rows)", len(r.values))}, nil
+		}
+	case *conditionalRowReader:
+		{
+			return b.withChildren(fmt.Sprintf("filter %s", r.condition.String()), &r.rowReader)
+		}
+	case *projectedRowReader:
+		{
+			return b.withChildren(fmt.Sprintf("project %s", describeTargets(r.targets)), &r.rowReader)
+		}
+	case *sortRowReader:
+		{
+			return b.withChildren(fmt.Sprintf("sort by %s", describeOrdExps(r.ordExps)), &r.rowReader)
+		}
+	case *groupedRowReader:
+		{
+			desc := "aggregate"
+			if len(r.groupByCols) > 0 {
+				cols := make([]string, len(r.groupByCols))
+				for i, col := range r.groupByCols {
+					cols[i] = col.String()
+				}
+				desc = fmt.Sprintf("group by %s", strings.Join(cols, ", "))
+			}
+
+			if len(r.selectors) > 0 {
+				aggs := make([]string, len(r.selectors))
+				for i, sel := range r.selectors {
+					aggs[i] = sel.String()
+				}
+				desc = fmt.Sprintf("%s: %s", desc, strings.Join(aggs, ", "))
+			}
+			return b.withChildren(desc, &r.rowReader)
+		}
+	case *windowRowReader:
+		{
+			fns := make([]string, len(r.fns))
+			for i, fn := range r.fns {
+				fns[i] = fn.String()
+			}
+			return b.withChildren(fmt.Sprintf("window %s", strings.Join(fns, ", ")), &r.rowReader)
+		}
+	case *distinctRowReader:
+		{
+			return b.withChildren("distinct", &r.rowReader)
+		}
+	case *offsetRowReader:
+		{
+			return b.withChildren(fmt.Sprintf("offset %d", r.offset), &r.rowReader)
+		}
+	case *limitRowReader:
+		{
+			return b.withChildren(fmt.Sprintf("limit %d", r.limit), &r.rowReader)
+		}
+	case *jointRowReader:
+		{
+			// rows of the left side are read from the first of the joint readers
+			n, err := b.describeJoins(&r.rowReaders[0], r.joins)
+			r.rowReader = r.rowReaders[0]
+			return n, err
+		}
+	case *outerJoinRowReader:
+		{
+			return b.describeJoins(&r.rowReader, []*JoinSpec{r.jspec})
+		}
+	case *unionRowReader:
+		{
+			readers := make([]*RowReader, len(r.rowReaders))
+			for i := range r.rowReaders {
+				readers[i] = &r.rowReaders[i]
+			}
+			return b.withChildren("union all", readers...)
+		}
+	case *setOpRowReader:
+		{
+			desc := "intersect"
+			if r.op == Except {
+				desc = "except"
+			}
+			return b.withChildren(desc, &r.left, &r.right)
+		}
+	}
+	return &planNode{desc: strings.TrimPrefix(fmt.Sprintf("%T", rowReader), "*sql.")}, nil
+}
+
+func (b *planBuilder) withChildren(desc string, rowReaders ...*RowReader) (*planNode, error) {
+	n := &planNode{
+		desc:     desc,
+		children: make([]*planNode, len(rowReaders)),
+	}
+
+	for i, rowReader := range rowReaders {
+		child, err := b.node(rowReader)
+		if err != nil {
+			return nil, err
+		}
+		n.children[i] = child
+	}
+	return n, nil
+}
+
+// describeJoins describes a nested loop join. The right side of each join is resolved
+// once per row of the left side, hence it's described by resolving it without
+// the values of the left row i.e. only key ranges over constant values are shown.
+func (b *planBuilder) describeJoins(rowReader *RowReader, joins []*JoinSpec) (*planNode, error) {
+	n, err := b.withChildren("nested loop join", rowReader)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, jspec := range joins {
+		jointq := &SelectStmt{
+			ds:      jspec.ds,
+			where:   jspec.cond,
+			indexOn: jspec.indexOn,
+		}
+
+		scanSpecs, err := jointq.genScanSpecs(b.tx, b.params)
+		if err != nil {
+			return nil, err
+		}
+
+		rightReader, err := jspec.ds.Resolve(b.ctx, b.tx, b.params, scanSpecs)
+		if err != nil {
+			return nil, err
+		}
+
+		// the right side is not read from this reader, thus no stats are collected for it
+		rightNode, err := (&planBuilder{ctx: b.ctx, tx: b.tx, params: b.params}).node(&rightReader)
+
+		closeErr := rightReader.Close()
+		if err != nil {
+			return nil, err
+		}
+		if closeErr != nil {
+			return nil, closeErr
+		}
+
+		n.children = append(n.children, &planNode{
+			desc:     fmt.Sprintf("%s on %s", joinTypeString(jspec.joinType), jspec.cond.String()),
+			children: []*planNode{rightNode},
+		})
+	}
+	return n, nil
+}
+
+func joinTypeString(joinType JoinType) string {
+	switch joinType {
+	case LeftJoin:
+		return "left join"
+	case RightJoin:
+		return "right join"
+	case FullOuterJoin:
+		return "full outer join"
+	}
+	return "inner join"
+}
+
+func describeScan(table *Table, tableAlias string, scanSpecs *ScanSpecs) string {
+	var sb strings.Builder
+
+	sb.WriteString("scan ")
+	sb.WriteString(table.name)
+
+	if tableAlias != table.name {
+		sb.WriteString(" as ")
+		sb.WriteString(tableAlias)
+	}
+
+	sb.WriteString(" using index ")
+	sb.WriteString(scanSpecs.Index.Name())
+
+	if scanSpecs.DescOrder {
+		sb.WriteString(" desc")
+	}
+
+	if scanSpecs.IncludeHistory {
+		sb.WriteString(" with history")
+	}
+
+	ranges := describeRanges(scanSpecs.Index, scanSpecs.rangesByColID)
+	if ranges != "" {
+		sb.WriteString(" range ")
+		sb.WriteString(ranges)
+	}
+	return sb.String()
+}
+
+// describeRanges describes the key ranges used to scan the index, that is, the ranges
+// over the leading columns of the index as considered when building the key reader spec
+func describeRanges(index *Index, rangesByColID map[uint32]*typedValueRange) string {
+	var conds []string
+
+	for _, col := range index.cols {
+		rng, ok := rangesByColID[col.id]
+		if !ok {
+			break
+		}
+
+		if rng.unitary() {
+			conds = append(conds, fmt.Sprintf("%s = %s", col.colName, rng.lRange.val.String()))
+			continue
+		}
+
+		if rng.lRange != nil {
+			op := ">"
+			if rng.lRange.inclusive {
+				op = ">="
+			}
+			conds = append(conds, fmt.Sprintf("%s %s %s", col.colName, op, rng.lRange.val.String()))
+		}
+
+		if rng.hRange != nil {
+			op := "<"
+			if rng.hRange.inclusive {
+				op = "<="
+			}
+			conds = append(conds, fmt.Sprintf("%s %s %s", col.colName, op, rng.hRange.val.String()))
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func describeTargets(targets []TargetEntry) string {
+	if len(targets) == 0 {
+		return "*"
+	}
+
+	exps := make([]string, len(targets))
+	for i, t := range targets {
+		exps[i] = t.Exp.String()
+		if t.As != "" {
+			exps[i] += " AS " + t.As
+		}
+	}
+	return strings.Join(exps, ", ")
+}
+
+func describeOrdExps(ordExps []*OrdExp) string {
+	exps := make([]string, len(ordExps))
+	for i, e := range ordExps {
+		exps[i] = e.exp.String()
+		if e.descOrder {
+			exps[i] += " DESC"
+		}
+	}
+	return strings.Join(exps, ", ")
+}
+
+// analyzedRowReader keeps track of the number of rows read from the underlying reader
+// and the time spent on it, including the time spent on the readers it depends on.
+type analyzedRowReader struct {
+	RowReader
+
+	rows    int
+	elapsed time.Duration
+}
+
+func (r *analyzedRowReader) Read(ctx context.Context) (*Row, error) {
+	start := time.Now()
+
+	row, err := r.RowReader.Read(ctx)
+
+	r.elapsed += time.Since(start)
+
+	if err == nil {
+		r.rows++
+	}
+	return row, err
+}
@@ -18,9 +18,12 @@ package sql
 
 import (
 	"fmt"
+	"math"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/codenotary/immudb/embedded/store"
 	"github.com/google/uuid"
 )
 
@@ -31,16 +34,44 @@ const (
 	LowerFnCall              string = "LOWER"
 	UpperFnCall              string = "UPPER"
 	TrimFnCall               string = "TRIM"
+	ReplaceFnCall            string = "REPLACE"
+	PositionFnCall           string = "POSITION"
+	LPadFnCall               string = "LPAD"
+	RPadFnCall               string = "RPAD"
+	RegexpReplaceFnCall      string = "REGEXP_REPLACE"
+	SplitPartFnCall          string = "SPLIT_PART"
+	CoalesceFnCall           string = "COALESCE"
+	NullIfFnCall             string = "NULLIF"
+	GreatestFnCall           string = "GREATEST"
+	LeastFnCall              string = "LEAST"
+	AbsFnCall                string = "ABS"
+	RoundFnCall              string = "ROUND"
+	FloorFnCall              string = "FLOOR"
+	CeilFnCall               string = "CEIL"
+	CeilingFnCall            string = "CEILING"
+	ModFnCall                string = "MOD"
+	PowerFnCall              string = "POWER"
+	SqrtFnCall               string = "SQRT"
 	NowFnCall                string = "NOW"
+	DateTruncFnCall          string = "DATE_TRUNC"
+	ExtractFnCall            string = "EXTRACT"
+	DatePartFnCall           string = "DATE_PART"
+	ToCharFnCall             string = "TO_CHAR"
+	AgeFnCall                string = "AGE"
 	UUIDFnCall               string = "RANDOM_UUID"
+	NextValFnCall            string = "NEXTVAL"
+	CurrValFnCall            string = "CURRVAL"
 	DatabasesFnCall          string = "DATABASES"
 	TablesFnCall             string = "TABLES"
+	ViewsFnCall              string = "VIEWS"
 	TableFnCall              string = "TABLE"
 	UsersFnCall              string = "USERS"
 	ColumnsFnCall            string = "COLUMNS"
 	IndexesFnCall            string = "INDEXES"
 	GrantsFnCall             string = "GRANTS"
 	JSONTypeOfFnCall         string = "JSON_TYPEOF"
+	ArrayLengthFnCall        string = "ARRAY_LENGTH"
+	UnnestFnCall             string = "UNNEST"
 	PGGetUserByIDFnCall      string = "PG_GET_USERBYID"
 	PgTableIsVisibleFnCall   string = "PG_TABLE_IS_VISIBLE"
 	PgShobjDescriptionFnCall string = "SHOBJ_DESCRIPTION"
@@ -53,20 +84,64 @@ var builtinFunctions = map[string]Function{
 	LowerFnCall:              &LowerUpperFnc{},
 	UpperFnCall:              &LowerUpperFnc{isUpper: true},
 	TrimFnCall:               &TrimFnc{},
+	ReplaceFnCall:            &ReplaceFn{newFnSignature(ReplaceFnCall, VarcharType, 3, VarcharType, VarcharType, VarcharType)},
+	PositionFnCall:           &PositionFn{newFnSignature(PositionFnCall, IntegerType, 2, VarcharType, VarcharType)},
+	LPadFnCall:               &PadFn{fnSignature: newFnSignature(LPadFnCall, VarcharType, 2, VarcharType, IntegerType, VarcharType)},
+	RPadFnCall:               &PadFn{fnSignature: newFnSignature(RPadFnCall, VarcharType, 2, VarcharType, IntegerType, VarcharType), right: true},
+	RegexpReplaceFnCall:      &RegexpReplaceFn{newFnSignature(RegexpReplaceFnCall, VarcharType, 3, VarcharType, VarcharType, VarcharType, VarcharType)},
+	SplitPartFnCall:          &SplitPartFn{newFnSignature(SplitPartFnCall, VarcharType, 3, VarcharType, VarcharType, IntegerType)},
+	CoalesceFnCall:           &CoalesceFn{commonTypeFn{name: CoalesceFnCall, minArgs: 1, maxArgs: -1}},
+	NullIfFnCall:             &NullIfFn{commonTypeFn{name: NullIfFnCall, minArgs: 2, maxArgs: 2}},
+	GreatestFnCall:           &GreatestLeastFn{commonTypeFn: commonTypeFn{name: GreatestFnCall, minArgs: 1, maxArgs: -1}},
+	LeastFnCall:              &GreatestLeastFn{commonTypeFn: commonTypeFn{name: LeastFnCall, minArgs: 1, maxArgs: -1}, least: true},
+	AbsFnCall:                &AbsFn{numericFn{name: AbsFnCall, maxArgs: 1}},
+	RoundFnCall:              &RoundFn{numericFn: numericFn{name: RoundFnCall, maxArgs: 2}},
+	FloorFnCall:              &RoundFn{numericFn: numericFn{name: FloorFnCall, maxArgs: 1}, mode: roundFloor},
+	CeilFnCall:               &RoundFn{numericFn: numericFn{name: CeilFnCall, maxArgs: 1}, mode: roundCeil},
+	CeilingFnCall:            &RoundFn{numericFn: numericFn{name: CeilingFnCall, maxArgs: 1}, mode: roundCeil},
+	ModFnCall:                &ModFn{},
+	PowerFnCall:              &FloatFn{fnSignature: newFnSignature(PowerFnCall, Float64Type, 2, Float64Type, Float64Type), apply: power},
+	SqrtFnCall:               &FloatFn{fnSignature: newFnSignature(SqrtFnCall, Float64Type, 1, Float64Type), apply: sqrt},
 	NowFnCall:                &NowFn{},
+	DateTruncFnCall:          &DateTruncFn{},
+	ExtractFnCall:            &ExtractFn{},
+	DatePartFnCall:           &ExtractFn{asFloat: true},
+	ToCharFnCall:             &ToCharFn{},
+	AgeFnCall:                &AgeFn{},
 	UUIDFnCall:               &UUIDFn{},
+	NextValFnCall:            &SequenceFn{next: true},
+	CurrValFnCall:            &SequenceFn{},
 	JSONTypeOfFnCall:         &JsonTypeOfFn{},
+	ArrayLengthFnCall:        &ArrayLengthFn{},
 	PGGetUserByIDFnCall:      &pgGetUserByIDFunc{},
 	PgTableIsVisibleFnCall:   &pgTableIsVisible{},
 	PgShobjDescriptionFnCall: &pgShobjDescription{},
 }
 
+// nonDeterministicFunctions are the builtin functions whose result does not solely depend on their arguments
+var nonDeterministicFunctions = map[string]struct{}{
+	NowFnCall:                {},
+	UUIDFnCall:               {},
+	NextValFnCall:            {},
+	CurrValFnCall:            {},
+	PGGetUserByIDFnCall:      {},
+	PgTableIsVisibleFnCall:   {},
+	PgShobjDescriptionFnCall: {},
+}
+
 type Function interface {
 	RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error
 	InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error)
 	Apply(tx *SQLTx, params []TypedValue) (TypedValue, error)
 }
 
+// userFunction is a function registered into an engine in addition to the builtin ones
+type userFunction struct {
+	name          string
+	fn            Function
+	deterministic bool
+}
+
 // -------------------------------------
 // String Functions
 // -------------------------------------
@@ -261,86 +336,1215 @@ func (f *TrimFnc) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
 	return &Varchar{val: strings.Trim(s, " \t\n\r\v\f")}, nil
 }
 
-// -------------------------------------
-// Time Functions
-// -------------------------------------
+type ReplaceFn struct {
+	fnSignature
+}
 
-type NowFn struct{}
+func (f *ReplaceFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkArgs(params)
+	if err != nil || isNull {
+		return &NullValue{t: VarcharType}, err
+	}
 
-func (f *NowFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return TimestampType, nil
+	s, _ := params[0].RawValue().(string)
+	from, _ := params[1].RawValue().(string)
+	to, _ := params[2].RawValue().(string)
+
+	if from == "" {
+		return &Varchar{val: s}, nil
+	}
+	return &Varchar{val: strings.ReplaceAll(s, from, to)}, nil
 }
 
-func (f *NowFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != TimestampType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, TimestampType, t)
+// PositionFn returns the position of the first occurrence of a substring, i.e. POSITION(substring IN string),
+// positions start from one as in SUBSTRING, zero is returned if the substring is not found
+type PositionFn struct {
+	fnSignature
+}
+
+func (f *PositionFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkArgs(params)
+	if err != nil || isNull {
+		return &NullValue{t: IntegerType}, err
 	}
-	return nil
+
+	sub, _ := params[0].RawValue().(string)
+	s, _ := params[1].RawValue().(string)
+
+	return &Integer{val: int64(strings.Index(s, sub) + 1)}, nil
 }
 
-func (f *NowFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
-	if len(params) > 0 {
-		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, NowFnCall, len(params))
+// maxPaddedLen is the maximum length of the strings built by LPAD and RPAD
+const maxPaddedLen = 1 << 20
+
+// PadFn fills a string up to the given number of characters by prepending (LPAD) or appending (RPAD)
+// the fill string, a space by default. A longer string is truncated to the given length.
+type PadFn struct {
+	fnSignature
+	right bool
+}
+
+func (f *PadFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkArgs(params)
+	if err != nil || isNull {
+		return &NullValue{t: VarcharType}, err
 	}
-	return &Timestamp{val: tx.Timestamp().Truncate(time.Microsecond).UTC()}, nil
+
+	s, _ := params[0].RawValue().(string)
+	length, _ := params[1].RawValue().(int64)
+
+	fill := " "
+	if len(params) > 2 {
+		fill, _ = params[2].RawValue().(string)
+	}
+
+	if length > int64(maxPaddedLen) {
+		return nil, fmt.Errorf("%w: '%s' function can not produce strings longer than %d characters", ErrIllegalArguments, f.name, maxPaddedLen)
+	}
+
+	if length <= 0 {
+		return &Varchar{val: ""}, nil
+	}
+
+	runes := []rune(s)
+	if int64(len(runes)) >= length {
+		return &Varchar{val: string(runes[:length])}, nil
+	}
+
+	fillRunes := []rune(fill)
+	if len(fillRunes) == 0 {
+		return &Varchar{val: s}, nil
+	}
+
+	padding := make([]rune, int(length)-len(runes))
+	for i := range padding {
+		padding[i] = fillRunes[i%len(fillRunes)]
+	}
+
+	if f.right {
+		return &Varchar{val: s + string(padding)}, nil
+	}
+	return &Varchar{val: string(padding) + s}, nil
+}
+
+// RegexpReplaceFn replaces the first match of a regular expression, or every match when the 'g' flag is given.
+// As in PostgreSQL, \1 to \9 in the replacement refer to the submatches and \& to the whole match.
+type RegexpReplaceFn struct {
+	fnSignature
+}
+
+func (f *RegexpReplaceFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkArgs(params)
+	if err != nil || isNull {
+		return &NullValue{t: VarcharType}, err
+	}
+
+	s, _ := params[0].RawValue().(string)
+	pattern, _ := params[1].RawValue().(string)
+	repl, _ := params[2].RawValue().(string)
+
+	flags := ""
+	if len(params) > 3 {
+		flags, _ = params[3].RawValue().(string)
+	}
+
+	global := false
+	for _, flag := range flags {
+		switch flag {
+		case 'g':
+			global = true
+		case 'i':
+			pattern = "(?i)" + pattern
+		case 'c':
+		default:
+			return nil, fmt.Errorf("%w: '%s' function does not support the flag '%c'", ErrIllegalArguments, f.name, flag)
+		}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid regular expression: %s", ErrIllegalArguments, err)
+	}
+
+	template := regexpTemplate(repl)
+
+	if global {
+		return &Varchar{val: re.ReplaceAllString(s, template)}, nil
+	}
+
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return &Varchar{val: s}, nil
+	}
+
+	replaced := re.ExpandString(nil, template, s, loc)
+	return &Varchar{val: s[:loc[0]] + string(replaced) + s[loc[1]:]}, nil
+}
+
+// regexpTemplate converts a replacement string in the PostgreSQL syntax into a template for regexp.Expand
+func regexpTemplate(repl string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(repl); i++ {
+		c := repl[i]
+
+		switch {
+		case c == '$':
+			sb.WriteString("$$")
+		case c == '\\' && i+1 < len(repl) && repl[i+1] >= '0' && repl[i+1] <= '9':
+			sb.WriteString("${" + string(repl[i+1]) + "}")
+			i++
+		case c == '\\' && i+1 < len(repl) && repl[i+1] == '&':
+			sb.WriteString("${0}")
+			i++
+		case c == '\\' && i+1 < len(repl) && repl[i+1] == '\\':
+			sb.WriteByte('\\')
+			i++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// SplitPartFn returns the n-th field of a string split on a delimiter,
+// a negative n counts fields from the end of the string
+type SplitPartFn struct {
+	fnSignature
+}
+
+func (f *SplitPartFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkArgs(params)
+	if err != nil || isNull {
+		return &NullValue{t: VarcharType}, err
+	}
+
+	s, _ := params[0].RawValue().(string)
+	delim, _ := params[1].RawValue().(string)
+	n, _ := params[2].RawValue().(int64)
+
+	if n == 0 {
+		return nil, fmt.Errorf("%w: field position must not be zero", ErrIllegalArguments)
+	}
+
+	if s == "" {
+		return &Varchar{val: ""}, nil
+	}
+
+	fields := []string{s}
+	if delim != "" {
+		fields = strings.Split(s, delim)
+	}
+
+	if n < 0 {
+		n += int64(len(fields)) + 1
+	}
+
+	if n <= 0 || n > int64(len(fields)) {
+		return &Varchar{val: ""}, nil
+	}
+	return &Varchar{val: fields[n-1]}, nil
 }
 
 // -------------------------------------
-// JSON Functions
+// Conditional Functions
 // -------------------------------------
 
-type JsonTypeOfFn struct{}
+// commonTypeFn implements the type inference of the functions returning one of their arguments,
+// arguments must be of the same type except for numeric ones, which are converted to a common type
+type commonTypeFn struct {
+	name    string
+	minArgs int
+	maxArgs int
+}
 
-func (f *JsonTypeOfFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return VarcharType, nil
+func (f *commonTypeFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return AnyType, nil
 }
 
-func (f *JsonTypeOfFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != VarcharType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+func (f *commonTypeFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	return nil
+}
+
+func (f *commonTypeFn) inferTypeFromArgs(tx *SQLTx, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := checkArgsCount(f.name, len(args), f.minArgs, f.maxArgs)
+	if err != nil {
+		return AnyType, err
+	}
+
+	t := AnyType
+
+	for _, arg := range args {
+		argType, err := arg.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+
+		ct, ok := coerceTypes(t, argType)
+		if !ok {
+			return AnyType, fmt.Errorf("%w: '%s' function can not mix arguments of type %v and %v", ErrInvalidTypes, f.name, t, argType)
+		}
+		t = ct
+	}
+
+	if t == AnyType {
+		return AnyType, nil
+	}
+	return t, f.requiresTypeOfArgs(tx, t, args, cols, params, implicitTable)
+}
+
+func (f *commonTypeFn) requiresTypeOfArgs(tx *SQLTx, t SQLValueType, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	err := checkArgsCount(f.name, len(args), f.minArgs, f.maxArgs)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		argType, err := arg.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return err
+		}
+
+		if argType == AnyType {
+			err = arg.requiresType(tx, t, cols, params, implicitTable)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ct, ok := coerceTypes(argType, t); !ok || ct != t {
+			return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, argType, t)
+		}
 	}
 	return nil
 }
 
-func (f *JsonTypeOfFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
-	if len(params) != 1 {
-		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, JSONTypeOfFnCall, 1, len(params))
+// commonValues converts the arguments to their common type, which is returned as well
+func (f *commonTypeFn) commonValues(params []TypedValue) ([]TypedValue, SQLValueType, error) {
+	err := checkArgsCount(f.name, len(params), f.minArgs, f.maxArgs)
+	if err != nil {
+		return nil, AnyType, err
 	}
 
-	v := params[0]
-	if v.IsNull() {
-		return NewNull(AnyType), nil
+	t := AnyType
+
+	for _, p := range params {
+		ct, ok := coerceTypes(t, p.Type())
+		if !ok {
+			return nil, AnyType, fmt.Errorf("%w: '%s' function can not mix arguments of type %v and %v", ErrIllegalArguments, f.name, t, p.Type())
+		}
+		t = ct
 	}
 
-	jsonVal, ok := v.(*JSON)
-	if !ok {
-		return nil, fmt.Errorf("%w: '%s' function expects an argument of type JSON", ErrIllegalArguments, JSONTypeOfFnCall)
+	values := make([]TypedValue, len(params))
+
+	for i, p := range params {
+		if p.IsNull() {
+			values[i] = &NullValue{t: t}
+			continue
+		}
+
+		conv, err := getConverter(p.Type(), t)
+		if err != nil {
+			return nil, AnyType, err
+		}
+
+		values[i], err = conv(p)
+		if err != nil {
+			return nil, AnyType, err
+		}
 	}
-	return NewVarchar(jsonVal.primitiveType()), nil
+	return values, t, nil
+}
+
+// CoalesceFn returns its first argument which is not NULL
+type CoalesceFn struct {
+	commonTypeFn
+}
+
+func (f *CoalesceFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	values, t, err := f.commonValues(params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if !v.IsNull() {
+			return v, nil
+		}
+	}
+	return &NullValue{t: t}, nil
+}
+
+// NullIfFn returns NULL if both arguments are equal, the first one otherwise
+type NullIfFn struct {
+	commonTypeFn
+}
+
+func (f *NullIfFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	values, t, err := f.commonValues(params)
+	if err != nil {
+		return nil, err
+	}
+
+	v1, v2 := values[0], values[1]
+	if v1.IsNull() || v2.IsNull() {
+		return v1, nil
+	}
+
+	cmp, err := v1.Compare(v2)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmp == 0 {
+		return &NullValue{t: t}, nil
+	}
+	return v1, nil
+}
+
+// GreatestLeastFn returns the greatest (or least) of its arguments, NULL values are ignored
+type GreatestLeastFn struct {
+	commonTypeFn
+	least bool
+}
+
+func (f *GreatestLeastFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	values, t, err := f.commonValues(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res TypedValue
+
+	for _, v := range values {
+		if v.IsNull() {
+			continue
+		}
+
+		if res == nil {
+			res = v
+			continue
+		}
+
+		cmp, err := v.Compare(res)
+		if err != nil {
+			return nil, err
+		}
+
+		if (f.least && cmp < 0) || (!f.least && cmp > 0) {
+			res = v
+		}
+	}
+
+	if res == nil {
+		return &NullValue{t: t}, nil
+	}
+	return res, nil
 }
 
 // -------------------------------------
-// UUID Functions
+// Math Functions
 // -------------------------------------
 
-type UUIDFn struct{}
+// numericFn implements the type inference of the functions returning a value of the same numeric type as their first argument,
+// any further argument is an INTEGER, e.g. the number of fractional digits kept by ROUND
+type numericFn struct {
+	name    string
+	maxArgs int
+}
 
-func (f *UUIDFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return UUIDType, nil
+func (f *numericFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return AnyType, nil
 }
 
-func (f *UUIDFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != UUIDType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, UUIDType, t)
+func (f *numericFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if !IsNumericType(t) {
+		return fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, t)
 	}
 	return nil
 }
 
-func (f *UUIDFn) Apply(_ *SQLTx, params []TypedValue) (TypedValue, error) {
-	if len(params) > 0 {
-		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, UUIDFnCall, len(params))
+func (f *numericFn) inferTypeFromArgs(tx *SQLTx, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := f.checkArgs(tx, args, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
 	}
-	return &UUID{val: uuid.New()}, nil
+
+	t, err := args[0].inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	if t != AnyType && !IsNumericType(t) {
+		return AnyType, fmt.Errorf("%w: '%s' function expects an argument of type %v, %v or %v", ErrInvalidTypes, f.name, IntegerType, Float64Type, DecimalType)
+	}
+	return t, nil
+}
+
+func (f *numericFn) requiresTypeOfArgs(tx *SQLTx, t SQLValueType, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	err := f.RequiresType(t, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	err = f.checkArgs(tx, args, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+	return args[0].requiresType(tx, t, cols, params, implicitTable)
+}
+
+func (f *numericFn) checkArgs(tx *SQLTx, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	err := checkArgsCount(f.name, len(args), 1, f.maxArgs)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args[1:] {
+		err := requireArgType(tx, f.name, arg, IntegerType, cols, params, implicitTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *numericFn) checkValues(params []TypedValue) (isNull bool, err error) {
+	err = checkArgsCount(f.name, len(params), 1, f.maxArgs)
+	if err != nil {
+		return false, err
+	}
+
+	if !params[0].IsNull() && !IsNumericType(params[0].Type()) {
+		return false, fmt.Errorf("%w: '%s' function expects an argument of type %v, %v or %v", ErrIllegalArguments, f.name, IntegerType, Float64Type, DecimalType)
+	}
+
+	for _, p := range params {
+		if p.IsNull() {
+			isNull = true
+		}
+	}
+
+	for _, p := range params[1:] {
+		if !p.IsNull() && p.Type() != IntegerType {
+			return false, fmt.Errorf("%w: '%s' function expects an argument of type %s", ErrIllegalArguments, f.name, IntegerType)
+		}
+	}
+	return isNull, nil
+}
+
+type AbsFn struct {
+	numericFn
+}
+
+func (f *AbsFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkValues(params)
+	if err != nil {
+		return nil, err
+	}
+
+	v := params[0]
+	if isNull {
+		return &NullValue{t: v.Type()}, nil
+	}
+
+	switch n := v.RawValue().(type) {
+	case int64:
+		if n == math.MinInt64 {
+			return nil, fmt.Errorf("%w: integer out of range", ErrNumericFieldOverflow)
+		}
+		if n < 0 {
+			return &Integer{val: -n}, nil
+		}
+		return v, nil
+	case float64:
+		return &Float64{val: math.Abs(n)}, nil
+	}
+	return v.(*Decimal).abs(), nil
+}
+
+// RoundFn implements ROUND, FLOOR and CEIL, which return a value of the same type as their argument.
+// ROUND rounds half away from zero and accepts the number of fractional digits to keep.
+type RoundFn struct {
+	numericFn
+	mode roundingMode
+}
+
+type roundingMode int
+
+const (
+	roundHalfAwayFromZero roundingMode = iota
+	roundFloor
+	roundCeil
+)
+
+func (f *RoundFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	isNull, err := f.checkValues(params)
+	if err != nil {
+		return nil, err
+	}
+
+	v := params[0]
+	if isNull {
+		return &NullValue{t: v.Type()}, nil
+	}
+
+	var scale int64
+	if len(params) > 1 {
+		scale, _ = params[1].RawValue().(int64)
+	}
+
+	if scale > MaxDecimalPrecision || scale < -MaxDecimalPrecision {
+		return nil, fmt.Errorf("%w: '%s' function expects a number of digits between %d and %d", ErrIllegalArguments, f.name, -MaxDecimalPrecision, MaxDecimalPrecision)
+	}
+
+	switch n := v.RawValue().(type) {
+	case int64:
+		if scale >= 0 {
+			return v, nil
+		}
+
+		rounded := NewDecimal(n, 0).round(int(scale))
+		i, ok := rounded.Int64()
+		if !ok {
+			return nil, fmt.Errorf("%w: integer out of range", ErrNumericFieldOverflow)
+		}
+		return &Integer{val: i}, nil
+	case float64:
+		switch f.mode {
+		case roundFloor:
+			return &Float64{val: math.Floor(n)}, nil
+		case roundCeil:
+			return &Float64{val: math.Ceil(n)}, nil
+		}
+
+		p := math.Pow10(int(scale))
+
+		rounded := math.Round(n*p) / p
+		if math.IsNaN(rounded) || math.IsInf(rounded, 0) {
+			// the value has no digits beyond the ones to be kept
+			return v, nil
+		}
+		return &Float64{val: rounded}, nil
+	}
+
+	d := v.(*Decimal)
+
+	switch f.mode {
+	case roundFloor:
+		return d.floor(), nil
+	case roundCeil:
+		return d.ceil(), nil
+	}
+	return d.round(int(scale)), nil
+}
+
+// ModFn returns the remainder of the division of its arguments, as the % operator
+type ModFn struct{}
+
+func (f *ModFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return AnyType, nil
+}
+
+func (f *ModFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if !IsNumericType(t) {
+		return fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, t)
+	}
+	return nil
+}
+
+func (f *ModFn) inferTypeFromArgs(tx *SQLTx, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := checkArgsCount(ModFnCall, len(args), 2, 2)
+	if err != nil {
+		return AnyType, err
+	}
+
+	t, err := (&NumExp{op: MODOP, left: args[0], right: args[1]}).inferType(tx, cols, params, implicitTable)
+	if err != nil || t != AnyType {
+		return t, err
+	}
+
+	// unlike the % operator, an untyped argument takes the type of the other one, i.e. MOD(id, @n) is an INTEGER
+	for i, arg := range args {
+		argType, err := arg.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+
+		if argType != AnyType {
+			return argType, args[1-i].requiresType(tx, argType, cols, params, implicitTable)
+		}
+	}
+	return AnyType, nil
+}
+
+func (f *ModFn) requiresTypeOfArgs(tx *SQLTx, t SQLValueType, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	err := f.RequiresType(t, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	err = checkArgsCount(ModFnCall, len(args), 2, 2)
+	if err != nil {
+		return err
+	}
+	return (&NumExp{op: MODOP, left: args[0], right: args[1]}).requiresType(tx, t, cols, params, implicitTable)
+}
+
+func (f *ModFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	err := checkArgsCount(ModFnCall, len(params), 2, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	v1, v2 := params[0], params[1]
+
+	for _, v := range params {
+		if !v.IsNull() && !IsNumericType(v.Type()) {
+			return nil, fmt.Errorf("%w: '%s' function expects an argument of type %v, %v or %v", ErrIllegalArguments, ModFnCall, IntegerType, Float64Type, DecimalType)
+		}
+	}
+
+	if v1.IsNull() || v2.IsNull() {
+		t, _ := coerceTypes(v1.Type(), v2.Type())
+		return &NullValue{t: t}, nil
+	}
+	return applyNumOperator(MODOP, v1, v2)
+}
+
+// FloatFn implements POWER and SQRT, which take numeric arguments of any type and return a FLOAT
+type FloatFn struct {
+	fnSignature
+	apply func(args []float64) (float64, error)
+}
+
+func (f *FloatFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	err := checkArgsCount(f.name, len(params), f.minArgs, len(f.args))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]float64, len(params))
+
+	for i, p := range params {
+		if p.IsNull() {
+			return &NullValue{t: Float64Type}, nil
+		}
+
+		switch n := p.RawValue().(type) {
+		case int64:
+			args[i] = float64(n)
+		case float64:
+			args[i] = n
+		default:
+			d, ok := p.(*Decimal)
+			if !ok {
+				return nil, fmt.Errorf("%w: '%s' function expects an argument of type %v, %v or %v", ErrIllegalArguments, f.name, IntegerType, Float64Type, DecimalType)
+			}
+			args[i] = d.Float64()
+		}
+	}
+
+	res, err := f.apply(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if math.IsNaN(res) || math.IsInf(res, 0) {
+		return nil, fmt.Errorf("%w: '%s' function result is out of range", ErrIllegalArguments, f.name)
+	}
+	return &Float64{val: res}, nil
+}
+
+func power(args []float64) (float64, error) {
+	if args[0] == 0 && args[1] < 0 {
+		return 0, fmt.Errorf("%w: zero raised to a negative power is undefined", ErrIllegalArguments)
+	}
+
+	if args[0] < 0 && args[1] != math.Trunc(args[1]) {
+		return 0, fmt.Errorf("%w: a negative number raised to a non-integer power yields a complex result", ErrIllegalArguments)
+	}
+	return math.Pow(args[0], args[1]), nil
+}
+
+func sqrt(args []float64) (float64, error) {
+	if args[0] < 0 {
+		return 0, fmt.Errorf("%w: can not take the square root of a negative number", ErrIllegalArguments)
+	}
+	return math.Sqrt(args[0]), nil
+}
+
+// -------------------------------------
+// Argument Types
+// -------------------------------------
+
+// argTypedFunction is implemented by the functions whose type depends on the types of their arguments,
+// or whose arguments must be typed e.g. to infer the type of the parameters passed to them
+type argTypedFunction interface {
+	inferTypeFromArgs(tx *SQLTx, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error)
+	requiresTypeOfArgs(tx *SQLTx, t SQLValueType, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error
+}
+
+// fnSignature implements the type inference of the functions returning a value of type t
+// from arguments of fixed types, the ones following the first minArgs are optional.
+// Numeric arguments typed as FLOAT accept values of any numeric type.
+type fnSignature struct {
+	name    string
+	t       SQLValueType
+	minArgs int
+	args    []SQLValueType
+}
+
+func newFnSignature(name string, t SQLValueType, minArgs int, args ...SQLValueType) fnSignature {
+	return fnSignature{name: name, t: t, minArgs: minArgs, args: args}
+}
+
+func (f *fnSignature) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return f.t, nil
+}
+
+func (f *fnSignature) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != f.t {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, f.t, t)
+	}
+	return nil
+}
+
+func (f *fnSignature) inferTypeFromArgs(tx *SQLTx, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := checkArgsCount(f.name, len(args), f.minArgs, len(f.args))
+	if err != nil {
+		return AnyType, err
+	}
+
+	for i, arg := range args {
+		err := requireArgType(tx, f.name, arg, f.args[i], cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+	}
+	return f.t, nil
+}
+
+func (f *fnSignature) requiresTypeOfArgs(tx *SQLTx, t SQLValueType, args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	err := f.RequiresType(t, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.inferTypeFromArgs(tx, args, cols, params, implicitTable)
+	return err
+}
+
+// checkArgs validates the arguments a function is applied to and reports whether any of them is NULL
+func (f *fnSignature) checkArgs(params []TypedValue) (isNull bool, err error) {
+	err = checkArgsCount(f.name, len(params), f.minArgs, len(f.args))
+	if err != nil {
+		return false, err
+	}
+
+	for i, p := range params {
+		if p.IsNull() {
+			isNull = true
+			continue
+		}
+
+		if p.Type() != f.args[i] {
+			return false, fmt.Errorf("%w: '%s' function expects an argument of type %s", ErrIllegalArguments, f.name, f.args[i])
+		}
+	}
+	return isNull, nil
+}
+
+// requireArgType checks the type of a function argument, a parameter or a NULL value is given the expected type
+func requireArgType(tx *SQLTx, fn string, arg ValueExp, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	argType, err := arg.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	if argType == AnyType {
+		return arg.requiresType(tx, t, cols, params, implicitTable)
+	}
+
+	if argType == t || (t == Float64Type && IsNumericType(argType)) {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' function expects an argument of type %s", ErrInvalidTypes, fn, t)
+}
+
+// checkArgsCount checks the number of arguments of a function, maxArgs is negative if it's unbounded
+func checkArgsCount(fn string, n, minArgs, maxArgs int) error {
+	switch {
+	case n >= minArgs && (maxArgs < 0 || n <= maxArgs):
+		return nil
+	case minArgs == maxArgs:
+		return fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, fn, minArgs, n)
+	case maxArgs < 0:
+		return fmt.Errorf("%w: '%s' function expects at least %d arguments but %d were provided", ErrIllegalArguments, fn, minArgs, n)
+	}
+	return fmt.Errorf("%w: '%s' function expects from %d to %d arguments but %d were provided", ErrIllegalArguments, fn, minArgs, maxArgs, n)
+}
+
+// -------------------------------------
+// Time Functions
+// -------------------------------------
+
+type NowFn struct{}
+
+func (f *NowFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return TimestampType, nil
+}
+
+func (f *NowFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != TimestampType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, TimestampType, t)
+	}
+	return nil
+}
+
+func (f *NowFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) > 0 {
+		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, NowFnCall, len(params))
+	}
+	return &Timestamp{val: tx.Timestamp().Truncate(time.Microsecond).UTC()}, nil
+}
+
+type DateTruncFn struct{}
+
+func (f *DateTruncFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return TimestampType, nil
+}
+
+func (f *DateTruncFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != TimestampType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, TimestampType, t)
+	}
+	return nil
+}
+
+func (f *DateTruncFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, DateTruncFnCall, 2, len(params))
+	}
+
+	field, ts := params[0], params[1]
+	if field.IsNull() || ts.IsNull() {
+		return &NullValue{t: TimestampType}, nil
+	}
+
+	if field.Type() != VarcharType || ts.Type() != TimestampType {
+		return nil, fmt.Errorf("%w: '%s' function expects arguments of type %s and %s", ErrIllegalArguments, DateTruncFnCall, VarcharType, TimestampType)
+	}
+
+	t, err := truncateTimestamp(ts.RawValue().(time.Time), field.RawValue().(string))
+	if err != nil {
+		return nil, err
+	}
+	return &Timestamp{val: t}, nil
+}
+
+// ExtractFn returns a field of a timestamp or an interval, i.e. EXTRACT(field FROM source) or DATE_PART('field', source).
+// As in PostgreSQL, EXTRACT returns exact values while DATE_PART returns floats.
+type ExtractFn struct {
+	asFloat bool
+}
+
+func (f *ExtractFn) name() string {
+	if f.asFloat {
+		return DatePartFnCall
+	}
+	return ExtractFnCall
+}
+
+func (f *ExtractFn) resultType() SQLValueType {
+	if f.asFloat {
+		return Float64Type
+	}
+	return DecimalType
+}
+
+func (f *ExtractFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return f.resultType(), nil
+}
+
+func (f *ExtractFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != f.resultType() {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, f.resultType(), t)
+	}
+	return nil
+}
+
+func (f *ExtractFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, f.name(), 2, len(params))
+	}
+
+	field, src := params[0], params[1]
+	if field.IsNull() || src.IsNull() {
+		return &NullValue{t: f.resultType()}, nil
+	}
+
+	if field.Type() != VarcharType {
+		return nil, fmt.Errorf("%w: '%s' function expects a field of type %s", ErrIllegalArguments, f.name(), VarcharType)
+	}
+
+	var d *Decimal
+	var err error
+
+	switch v := src.(type) {
+	case *Timestamp:
+		d, err = extractFromTimestamp(f.name(), v.val, field.RawValue().(string))
+	case *Interval:
+		d, err = extractFromInterval(f.name(), v, field.RawValue().(string))
+	default:
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %s or %s", ErrIllegalArguments, f.name(), TimestampType, IntervalType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.asFloat {
+		return &Float64{val: d.Float64()}, nil
+	}
+	return d, nil
+}
+
+type ToCharFn struct{}
+
+func (f *ToCharFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return VarcharType, nil
+}
+
+func (f *ToCharFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != VarcharType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+	}
+	return nil
+}
+
+func (f *ToCharFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, ToCharFnCall, 2, len(params))
+	}
+
+	ts, format := params[0], params[1]
+	if ts.IsNull() || format.IsNull() {
+		return &NullValue{t: VarcharType}, nil
+	}
+
+	if ts.Type() != TimestampType || format.Type() != VarcharType {
+		return nil, fmt.Errorf("%w: '%s' function expects arguments of type %s and %s", ErrIllegalArguments, ToCharFnCall, TimestampType, VarcharType)
+	}
+	return &Varchar{val: formatTimestamp(ts.RawValue().(time.Time), format.RawValue().(string))}, nil
+}
+
+// AgeFn returns the difference between two timestamps in years, months and days,
+// a single timestamp being subtracted from the current date
+type AgeFn struct{}
+
+func (f *AgeFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return IntervalType, nil
+}
+
+func (f *AgeFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != IntervalType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntervalType, t)
+	}
+	return nil
+}
+
+func (f *AgeFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 1 && len(params) != 2 {
+		return nil, fmt.Errorf("%w: '%s' function expects one or two arguments but %d were provided", ErrIllegalArguments, AgeFnCall, len(params))
+	}
+
+	for _, p := range params {
+		if p.IsNull() {
+			return &NullValue{t: IntervalType}, nil
+		}
+
+		if p.Type() != TimestampType {
+			return nil, fmt.Errorf("%w: '%s' function expects arguments of type %s", ErrIllegalArguments, AgeFnCall, TimestampType)
+		}
+	}
+
+	if len(params) == 1 {
+		today := tx.Timestamp().UTC().Truncate(24 * time.Hour)
+		return timestampAge(today, params[0].RawValue().(time.Time)), nil
+	}
+	return timestampAge(params[0].RawValue().(time.Time), params[1].RawValue().(time.Time)), nil
+}
+
+// -------------------------------------
+// JSON Functions
+// -------------------------------------
+
+type JsonTypeOfFn struct{}
+
+func (f *JsonTypeOfFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return VarcharType, nil
+}
+
+func (f *JsonTypeOfFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != VarcharType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+	}
+	return nil
+}
+
+func (f *JsonTypeOfFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, JSONTypeOfFnCall, 1, len(params))
+	}
+
+	v := params[0]
+	if v.IsNull() {
+		return NewNull(AnyType), nil
+	}
+
+	jsonVal, ok := v.(*JSON)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type JSON", ErrIllegalArguments, JSONTypeOfFnCall)
+	}
+	return NewVarchar(jsonVal.primitiveType()), nil
+}
+
+// -------------------------------------
+// Array Functions
+// -------------------------------------
+
+type ArrayLengthFn struct{}
+
+func (f *ArrayLengthFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return IntegerType, nil
+}
+
+func (f *ArrayLengthFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != IntegerType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	}
+	return nil
+}
+
+func (f *ArrayLengthFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, ArrayLengthFnCall, 2, len(params))
+	}
+
+	v, dim := params[0], params[1]
+	if v.IsNull() || dim.IsNull() {
+		return &NullValue{t: IntegerType}, nil
+	}
+
+	arr, ok := v.(*Array)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' function expects an array as first argument", ErrIllegalArguments, ArrayLengthFnCall)
+	}
+
+	d, ok := dim.RawValue().(int64)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' function expects the dimension as an argument of type %s", ErrIllegalArguments, ArrayLengthFnCall, IntegerType)
+	}
+
+	// arrays are one-dimensional, and an empty array has no dimensions at all
+	if d != 1 || len(arr.vals) == 0 {
+		return &NullValue{t: IntegerType}, nil
+	}
+	return &Integer{val: int64(len(arr.vals))}, nil
+}
+
+// -------------------------------------
+// UUID Functions
+// -------------------------------------
+
+type UUIDFn struct{}
+
+func (f *UUIDFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return UUIDType, nil
+}
+
+func (f *UUIDFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != UUIDType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, UUIDType, t)
+	}
+	return nil
+}
+
+func (f *UUIDFn) Apply(_ *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) > 0 {
+		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, UUIDFnCall, len(params))
+	}
+	return &UUID{val: uuid.New()}, nil
+}
+
+// -------------------------------------
+// Sequence Functions
+// -------------------------------------
+
+// SequenceFn implements NEXTVAL, which advances a sequence and returns the new value,
+// and CURRVAL, which returns the last value generated by a sequence.
+//
+// As NEXTVAL writes the new value of the sequence, queries calling it are not read-only
+// and need to be executed within a read-write transaction.
+// Unlike PostgreSQL, CURRVAL is not bound to the session: it returns the value generated
+// within the current transaction if any, otherwise the last committed value of the sequence,
+// which may have been generated by any other transaction.
+type SequenceFn struct {
+	next bool
+}
+
+func (f *SequenceFn) name() string {
+	if f.next {
+		return NextValFnCall
+	}
+	return CurrValFnCall
+}
+
+func (f *SequenceFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return IntegerType, nil
+}
+
+func (f *SequenceFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != IntegerType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	}
+	return nil
+}
+
+func (f *SequenceFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, f.name(), 1, len(params))
+	}
+
+	if params[0].Type() != VarcharType || params[0].IsNull() {
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %s", ErrIllegalArguments, f.name(), VarcharType)
+	}
+
+	if tx == nil {
+		return nil, fmt.Errorf("%w: '%s' function requires a transaction", ErrIllegalArguments, f.name())
+	}
+
+	seq, err := tx.catalog.GetSequenceByName(params[0].RawValue().(string))
+	if err != nil {
+		return nil, err
+	}
+
+	if f.next && tx.tx.IsReadOnly() {
+		return nil, fmt.Errorf("%w: '%s' function must be called within a read-write transaction", store.ErrReadOnlyTx, f.name())
+	}
+
+	var v int64
+	if f.next {
+		v, err = tx.nextSequenceValue(tx.tx.Context(), seq)
+	} else {
+		v, err = tx.lastSequenceValue(tx.tx.Context(), seq)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Integer{val: v}, nil
 }
 
 // pg functions
@@ -112,10 +112,15 @@ func (gr *groupedRowReader) colsBySelector(ctx context.Context) (map[string]ColD
 	for _, sel := range gr.selectors {
 		aggFn, table, col := sel.resolve(gr.rowReader.TableAlias())
 
-		if aggFn == "" {
+		if sel.aggFn == "" {
 			continue
 		}
 
+		err := sel.validate()
+		if err != nil {
+			return nil, err
+		}
+
 		des := ColDescriptor{
 			AggFn:  aggFn,
 			Table:  table,
@@ -125,7 +130,7 @@ func (gr *groupedRowReader) colsBySelector(ctx context.Context) (map[string]ColD
 
 		encSel := des.Selector()
 
-		if aggFn == COUNT {
+		if sel.aggFn == COUNT {
 			colDescriptors[encSel] = des
 			continue
 		}
@@ -135,7 +140,19 @@ func (gr *groupedRowReader) colsBySelector(ctx context.Context) (map[string]ColD
 			return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
 		}
 
-		des.Type = colDesc.Type
+		switch sel.aggFn {
+		case STRING_AGG, BOOL_AND, BOOL_OR, STDDEV, PERCENTILE_CONT:
+			{
+				des.Type, err = aggregationType(sel.aggFn, colDesc.Type)
+				if err != nil {
+					return nil, err
+				}
+			}
+		default:
+			{
+				des.Type = colDesc.Type
+			}
+		}
 		colDescriptors[encSel] = des
 	}
 	return colDescriptors, nil
@@ -157,6 +174,8 @@ func zeroForType(t SQLValueType) TypedValue {
 		return &Integer{}
 	case Float64Type:
 		return &Float64{}
+	case DecimalType:
+		return NewDecimal(0, 0)
 	case BooleanType:
 		return &Bool{}
 	case VarcharType:
@@ -293,9 +312,14 @@ func (gr *groupedRowReader) zeroRow(ctx context.Context) (*Row, error) {
 		encSel := EncodeSelector(aggFn, table, col)
 
 		var zero TypedValue
-		if aggFn == COUNT {
+
+		switch sel.aggFn {
+		case COUNT:
 			zero = zeroForType(IntegerType)
-		} else {
+		case STRING_AGG, BOOL_AND, BOOL_OR, STDDEV, PERCENTILE_CONT:
+			// as over rows where the aggregated column is NULL
+			zero = &NullValue{t: colsBySelector[encSel].Type}
+		default:
 			zero = zeroForType(colsBySelector[encSel].Type)
 		}
 
@@ -308,8 +332,7 @@ func (gr *groupedRowReader) zeroRow(ctx context.Context) (*Row, error) {
 func (gr *groupedRowReader) initAggregations(row *Row) error {
 	// augment row with aggregated values
 	for _, sel := range gr.selectors {
-		aggFn, table, col := sel.resolve(gr.rowReader.TableAlias())
-		v, err := initAggValue(aggFn, table, col)
+		v, err := initAggValue(sel, gr.rowReader.TableAlias(), gr.Tx().distinctLimit())
 		if err != nil {
 			return err
 		}
@@ -318,7 +341,7 @@ func (gr *groupedRowReader) initAggregations(row *Row) error {
 			continue
 		}
 
-		encSel := EncodeSelector(aggFn, table, col)
+		encSel := EncodeSelector(sel.resolve(gr.rowReader.TableAlias()))
 		row.ValuesBySelector[encSel] = v
 	}
 
@@ -335,45 +358,95 @@ func (gr *groupedRowReader) initAggregations(row *Row) error {
 	return updateRow(row, row)
 }
 
-func initAggValue(aggFn, table, col string) (TypedValue, error) {
-	var v TypedValue
-	switch aggFn {
+func initAggValue(sel *AggColSelector, implicitTable string, distinctLimit int) (AggregatedValue, error) {
+	if sel.aggFn == "" {
+		return nil, nil
+	}
+
+	err := sel.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, table, col := sel.resolve(implicitTable)
+	encSel := EncodeSelector("", table, col)
+
+	var v AggregatedValue
+
+	switch sel.aggFn {
 	case COUNT:
 		{
-			if col != "*" {
-				return nil, ErrLimitedCount
+			v = &CountValue{
+				sel:        encSel,
+				colBounded: col != "*",
 			}
-
-			v = &CountValue{sel: EncodeSelector("", table, col)}
 		}
 	case SUM:
 		{
 			v = &SumValue{
 				val: &NullValue{t: AnyType},
-				sel: EncodeSelector("", table, col),
+				sel: encSel,
 			}
 		}
 	case MIN:
 		{
 			v = &MinValue{
 				val: &NullValue{t: AnyType},
-				sel: EncodeSelector("", table, col),
+				sel: encSel,
 			}
 		}
 	case MAX:
 		{
 			v = &MaxValue{
 				val: &NullValue{t: AnyType},
-				sel: EncodeSelector("", table, col),
+				sel: encSel,
 			}
 		}
 	case AVG:
 		{
 			v = &AVGValue{
 				s:   &NullValue{t: AnyType},
-				sel: EncodeSelector("", table, col),
+				sel: encSel,
+			}
+		}
+	case STRING_AGG:
+		{
+			v = &StringAggValue{
+				separator: sel.arg.RawValue().(string),
+				sel:       encSel,
 			}
 		}
+	case BOOL_AND, BOOL_OR:
+		{
+			v = &BoolAggValue{
+				aggFn: sel.aggFn,
+				val:   &NullValue{t: BooleanType},
+				sel:   encSel,
+			}
+		}
+	case STDDEV:
+		{
+			v = &StdDevValue{
+				sel: encSel,
+			}
+		}
+	case PERCENTILE_CONT:
+		{
+			fraction := sel.arg.RawValue().(float64)
+			if sel.descOrder {
+				fraction = 1 - fraction
+			}
+
+			v = &PercentileContValue{
+				fraction: fraction,
+				limit:    distinctLimit,
+				sel:      encSel,
+			}
+		}
+	}
+
+	if sel.distinct {
+		return newDistinctAggValue(v, distinctLimit), nil
 	}
 	return v, nil
 }
@@ -25,6 +25,10 @@ func mayApplyImplicitConversion(val interface{}, requiredColumnType SQLValueType
 		return nil, nil
 	}
 
+	if IsArrayType(requiredColumnType) {
+		return arrayImplicitConversion(val, ArrayElemType(requiredColumnType))
+	}
+
 	var converter converterFunc
 	var typedVal TypedValue
 	var err error
@@ -73,6 +77,37 @@ func mayApplyImplicitConversion(val interface{}, requiredColumnType SQLValueType
 				return nil, err
 			}
 
+			typedVal = &Varchar{val: value}
+		}
+	case DecimalType:
+		switch value := val.(type) {
+		case int:
+			converter, err = getConverter(IntegerType, DecimalType)
+			if err != nil {
+				return nil, err
+			}
+
+			typedVal = &Integer{val: int64(value)}
+		case int64:
+			converter, err = getConverter(IntegerType, DecimalType)
+			if err != nil {
+				return nil, err
+			}
+
+			typedVal = &Integer{val: value}
+		case float64:
+			converter, err = getConverter(Float64Type, DecimalType)
+			if err != nil {
+				return nil, err
+			}
+
+			typedVal = &Float64{val: value}
+		case string:
+			converter, err = getConverter(VarcharType, DecimalType)
+			if err != nil {
+				return nil, err
+			}
+
 			typedVal = &Varchar{val: value}
 		}
 	case UUIDType:
@@ -0,0 +1,476 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	microsPerMilli  = int64(time.Millisecond / time.Microsecond)
+	microsPerSecond = int64(time.Second / time.Microsecond)
+	microsPerMinute = 60 * microsPerSecond
+	microsPerHour   = 60 * microsPerMinute
+	microsPerDay    = 24 * microsPerHour
+
+	// months are considered to be 30 days long when intervals are compared
+	daysPerMonth = 30
+)
+
+type intervalUnit struct {
+	months int64
+	days   int64
+	micros int64
+}
+
+var intervalUnits = map[string]intervalUnit{
+	"microsecond": {micros: 1},
+	"millisecond": {micros: microsPerMilli},
+	"second":      {micros: microsPerSecond},
+	"minute":      {micros: microsPerMinute},
+	"hour":        {micros: microsPerHour},
+	"day":         {days: 1},
+	"week":        {days: 7},
+	"month":       {months: 1},
+	"year":        {months: 12},
+	"decade":      {months: 120},
+	"century":     {months: 1200},
+	"millennium":  {months: 12000},
+}
+
+var intervalUnitAliases = map[string]string{
+	"us":           "microsecond",
+	"usec":         "microsecond",
+	"usecs":        "microsecond",
+	"microseconds": "microsecond",
+	"ms":           "millisecond",
+	"msec":         "millisecond",
+	"msecs":        "millisecond",
+	"milliseconds": "millisecond",
+	"s":            "second",
+	"sec":          "second",
+	"secs":         "second",
+	"seconds":      "second",
+	"m":            "minute",
+	"min":          "minute",
+	"mins":         "minute",
+	"minutes":      "minute",
+	"h":            "hour",
+	"hr":           "hour",
+	"hrs":          "hour",
+	"hours":        "hour",
+	"d":            "day",
+	"days":         "day",
+	"w":            "week",
+	"weeks":        "week",
+	"mon":          "month",
+	"mons":         "month",
+	"months":       "month",
+	"y":            "year",
+	"yr":           "year",
+	"yrs":          "year",
+	"years":        "year",
+	"decades":      "decade",
+	"centuries":    "century",
+	"millennia":    "millennium",
+	"millenniums":  "millennium",
+}
+
+// Interval is a span of time made of months, days and microseconds,
+// which are kept apart as the length of months and days depends on the timestamp they are added to
+type Interval struct {
+	months int64
+	days   int64
+	micros int64
+}
+
+func NewInterval(months, days, micros int64) *Interval {
+	return &Interval{months: months, days: days, micros: micros}
+}
+
+// Fields returns the months, days and microseconds the interval is made of
+func (i *Interval) Fields() (months, days, micros int64) {
+	return i.months, i.days, i.micros
+}
+
+// ParseInterval parses intervals such as '7 days', '1 year 2 months', '-1 day 02:30:00' or '3 hours ago'
+func ParseInterval(s string) (*Interval, error) {
+	fields := strings.Fields(strings.ToLower(s))
+
+	if len(fields) > 0 && fields[0] == "@" {
+		fields = fields[1:]
+	}
+
+	ago := len(fields) > 0 && fields[len(fields)-1] == "ago"
+	if ago {
+		fields = fields[:len(fields)-1]
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: invalid interval '%s'", ErrInvalidValue, s)
+	}
+
+	var i Interval
+
+	for n := 0; n < len(fields); n++ {
+		f := fields[n]
+
+		if strings.Contains(f, ":") {
+			micros, err := parseIntervalTime(f)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid interval '%s'", ErrInvalidValue, s)
+			}
+			i.micros += micros
+			continue
+		}
+
+		// the unit may follow the amount with no space in between e.g. 7d
+		num, unit := f, ""
+		if p := strings.IndexFunc(f, func(r rune) bool { return r >= 'a' && r <= 'z' }); p > 0 {
+			num, unit = f[:p], f[p:]
+		} else if n+1 < len(fields) {
+			n++
+			unit = fields[n]
+		}
+
+		amount, err := strconv.ParseFloat(num, 64)
+		if err != nil || math.IsInf(amount, 0) || math.IsNaN(amount) {
+			return nil, fmt.Errorf("%w: invalid interval '%s'", ErrInvalidValue, s)
+		}
+
+		if alias, ok := intervalUnitAliases[unit]; ok {
+			unit = alias
+		}
+
+		u, ok := intervalUnits[unit]
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid interval '%s'", ErrInvalidValue, s)
+		}
+
+		i.add(amount, u)
+	}
+
+	if ago {
+		return i.negate(), nil
+	}
+	return &i, nil
+}
+
+// add adds the given amount of units, fractions of months and days are carried over to the smaller fields
+func (i *Interval) add(amount float64, u intervalUnit) {
+	months := amount * float64(u.months)
+	wholeMonths := math.Trunc(months)
+
+	days := amount*float64(u.days) + (months-wholeMonths)*daysPerMonth
+	wholeDays := math.Trunc(days)
+
+	i.months += int64(wholeMonths)
+	i.days += int64(wholeDays)
+	i.micros += int64(math.Round(amount*float64(u.micros) + (days-wholeDays)*float64(microsPerDay)))
+}
+
+// parseIntervalTime parses a time of the form [-]hh:mm[:ss[.ffffff]] into microseconds
+func parseIntervalTime(s string) (int64, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, ErrInvalidValue
+	}
+
+	var micros int64
+
+	for n, p := range parts {
+		if n < 2 {
+			v, err := strconv.ParseUint(p, 10, 32)
+			if err != nil {
+				return 0, err
+			}
+
+			if n == 0 {
+				micros += int64(v) * microsPerHour
+			} else {
+				micros += int64(v) * microsPerMinute
+			}
+			continue
+		}
+
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 || math.IsInf(v, 0) {
+			return 0, ErrInvalidValue
+		}
+		micros += int64(math.Round(v * float64(microsPerSecond)))
+	}
+
+	if neg {
+		return -micros, nil
+	}
+	return micros, nil
+}
+
+func (i *Interval) negate() *Interval {
+	return &Interval{months: -i.months, days: -i.days, micros: -i.micros}
+}
+
+func (i *Interval) addInterval(o *Interval) *Interval {
+	return &Interval{months: i.months + o.months, days: i.days + o.days, micros: i.micros + o.micros}
+}
+
+// addTo adds the interval to the timestamp, the day of the month being capped to the last day
+// of the resulting month e.g. 2024-01-31 + 1 month = 2024-02-29
+func (i *Interval) addTo(t time.Time) time.Time {
+	if i.months != 0 {
+		year, month, day := t.Date()
+
+		m := int64(year)*12 + int64(month-1) + i.months
+		year, month = int(m/12), time.Month(m%12+1)
+		if m < 0 && m%12 != 0 {
+			year, month = int(m/12)-1, time.Month(m%12+13)
+		}
+
+		if last := daysIn(year, month); day > last {
+			day = last
+		}
+
+		t = time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	}
+
+	return t.AddDate(0, 0, int(i.days)).Add(time.Duration(i.micros) * time.Microsecond)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// totalMicros returns the length of the interval taking months of 30 days
+func (i *Interval) totalMicros() int64 {
+	return (i.months*daysPerMonth+i.days)*microsPerDay + i.micros
+}
+
+func (i *Interval) Type() SQLValueType {
+	return IntervalType
+}
+
+func (i *Interval) IsNull() bool {
+	return false
+}
+
+// String returns the interval in the format used by PostgreSQL e.g. 1 year 2 mons 3 days 04:05:06
+func (i *Interval) String() string {
+	var parts []string
+
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	if years := i.months / 12; years != 0 {
+		parts = append(parts, plural(years, "year"))
+	}
+
+	if months := i.months % 12; months != 0 {
+		parts = append(parts, plural(months, "mon"))
+	}
+
+	if i.days != 0 {
+		parts = append(parts, plural(i.days, "day"))
+	}
+
+	if i.micros != 0 || len(parts) == 0 {
+		sign := ""
+		if i.micros < 0 {
+			sign = "-"
+		} else if i.months < 0 || i.days < 0 {
+			sign = "+"
+		}
+
+		micros := i.micros
+		if micros < 0 {
+			micros = -micros
+		}
+
+		t := fmt.Sprintf(
+			"%s%02d:%02d:%02d",
+			sign,
+			micros/microsPerHour,
+			micros%microsPerHour/microsPerMinute,
+			micros%microsPerMinute/microsPerSecond,
+		)
+
+		if frac := micros % microsPerSecond; frac > 0 {
+			t += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+		}
+
+		parts = append(parts, t)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (i *Interval) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return IntervalType, nil
+}
+
+func (i *Interval) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != IntervalType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntervalType, t)
+	}
+	return nil
+}
+
+func (i *Interval) selectors() []Selector {
+	return nil
+}
+
+func (i *Interval) substitute(params map[string]interface{}) (ValueExp, error) {
+	return i, nil
+}
+
+func (i *Interval) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return i, nil
+}
+
+func (i *Interval) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return i
+}
+
+func (i *Interval) isConstant() bool {
+	return true
+}
+
+func (i *Interval) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+// RawValue returns the textual representation of the interval
+func (i *Interval) RawValue() interface{} {
+	return i.String()
+}
+
+func (i *Interval) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
+	}
+
+	var other *Interval
+
+	switch v := val.(type) {
+	case *Interval:
+		other = v
+	case *Varchar:
+		parsed, err := ParseInterval(v.val)
+		if err != nil {
+			return 0, ErrNotComparableValues
+		}
+		other = parsed
+	default:
+		return 0, ErrNotComparableValues
+	}
+
+	l, r := i.totalMicros(), other.totalMicros()
+
+	switch {
+	case l < r:
+		return -1, nil
+	case l > r:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func isTemporalType(t SQLValueType) bool {
+	return t == TimestampType || t == IntervalType
+}
+
+// temporalOperatorType returns the type resulting from adding or subtracting timestamps and intervals
+func temporalOperatorType(op NumOperator, tleft, tright SQLValueType) (SQLValueType, error) {
+	if op == ADDOP || op == SUBSOP {
+		switch {
+		case tleft == TimestampType && tright == IntervalType:
+			return TimestampType, nil
+		case tleft == IntervalType && tright == TimestampType && op == ADDOP:
+			return TimestampType, nil
+		case tleft == TimestampType && tright == TimestampType && op == SUBSOP:
+			return IntervalType, nil
+		case tleft == IntervalType && tright == IntervalType:
+			return IntervalType, nil
+		case tleft == AnyType || tright == AnyType:
+			return AnyType, nil
+		}
+	}
+
+	return AnyType, fmt.Errorf(
+		"%w: operator %s can not be applied to %v and %v",
+		ErrInvalidTypes,
+		NumOperatorString(op),
+		tleft,
+		tright,
+	)
+}
+
+func applyTemporalOperator(op NumOperator, vl, vr TypedValue) (TypedValue, error) {
+	t, err := temporalOperatorType(op, vl.Type(), vr.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	if vl.IsNull() || vr.IsNull() {
+		return &NullValue{t: t}, nil
+	}
+
+	if t == AnyType {
+		return nil, fmt.Errorf(
+			"%w: operator %s can not be applied to %v and %v",
+			ErrInvalidTypes,
+			NumOperatorString(op),
+			vl.Type(),
+			vr.Type(),
+		)
+	}
+
+	switch l := vl.(type) {
+	case *Timestamp:
+		if r, ok := vr.(*Timestamp); ok {
+			diff := l.val.UnixMicro() - r.val.UnixMicro()
+			return &Interval{days: diff / microsPerDay, micros: diff % microsPerDay}, nil
+		}
+
+		i := vr.(*Interval)
+		if op == SUBSOP {
+			i = i.negate()
+		}
+		return &Timestamp{val: i.addTo(l.val)}, nil
+	case *Interval:
+		if r, ok := vr.(*Timestamp); ok {
+			return &Timestamp{val: l.addTo(r.val)}, nil
+		}
+
+		i := vr.(*Interval)
+		if op == SUBSOP {
+			i = i.negate()
+		}
+		return l.addInterval(i), nil
+	}
+	return nil, ErrUnexpected
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterval(t *testing.T) {
+	for _, d := range []struct {
+		s   string
+		exp string
+	}{
+		{"1 day", "1 day"},
+		{"7 days", "7 days"},
+		{"1 week", "7 days"},
+		{"2 weeks 1 day", "15 days"},
+		{"1 year 2 months 3 days", "1 year 2 mons 3 days"},
+		{"1 yr 2 mons", "1 year 2 mons"},
+		{"36 hours", "36:00:00"},
+		{"90 minutes", "01:30:00"},
+		{"1.5 seconds", "00:00:01.5"},
+		{"250 ms", "00:00:00.25"},
+		{"1.5 days", "1 day 12:00:00"},
+		{"1.5 months", "1 mon 15 days"},
+		{"1 decade", "10 years"},
+		{"-1 day", "-1 days"},
+		{"-1 day +2 hours", "-1 days +02:00:00"},
+		{"1 day 2 hours ago", "-1 days -02:00:00"},
+		{"@ 3 days", "3 days"},
+		{"7d", "7 days"},
+		{"3 days 04:05:06", "3 days 04:05:06"},
+		{"-04:05", "-04:05:00"},
+		{"1 DAY", "1 day"},
+		{"0 seconds", "00:00:00"},
+	} {
+		t.Run(d.s, func(t *testing.T) {
+			v, err := ParseInterval(d.s)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, v.String())
+		})
+	}
+
+	for _, s := range []string{"", "day", "1 fortnight", "1 day 2", "1:2:3:4", "1:xx", "ago", "1 day ago ago"} {
+		t.Run(fmt.Sprintf("invalid %q", s), func(t *testing.T) {
+			_, err := ParseInterval(s)
+			require.ErrorIs(t, err, ErrInvalidValue)
+		})
+	}
+}
+
+func TestIntervalAddTo(t *testing.T) {
+	ts := time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)
+
+	for _, d := range []struct {
+		i   *Interval
+		exp time.Time
+	}{
+		{NewInterval(1, 0, 0), time.Date(2024, 2, 29, 10, 30, 0, 0, time.UTC)},
+		{NewInterval(13, 0, 0), time.Date(2025, 2, 28, 10, 30, 0, 0, time.UTC)},
+		{NewInterval(-2, 0, 0), time.Date(2023, 11, 30, 10, 30, 0, 0, time.UTC)},
+		{NewInterval(-13, 0, 0), time.Date(2022, 12, 31, 10, 30, 0, 0, time.UTC)},
+		{NewInterval(0, 1, 0), time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC)},
+		{NewInterval(0, 0, -11*microsPerHour), time.Date(2024, 1, 30, 23, 30, 0, 0, time.UTC)},
+		{NewInterval(1, 1, 0), time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+	} {
+		t.Run(d.i.String(), func(t *testing.T) {
+			require.Equal(t, d.exp, d.i.addTo(ts))
+		})
+	}
+}
+
+func TestIntervalCompare(t *testing.T) {
+	for _, d := range []struct {
+		l   TypedValue
+		r   TypedValue
+		exp int
+	}{
+		{NewInterval(0, 1, 0), NewInterval(0, 0, 24*microsPerHour), 0},
+		{NewInterval(1, 0, 0), NewInterval(0, 30, 0), 0},
+		{NewInterval(1, 0, 0), NewInterval(0, 29, 0), 1},
+		{NewInterval(0, 0, -1), NewInterval(0, 0, 0), -1},
+		{NewInterval(0, 7, 0), &Varchar{val: "1 week"}, 0},
+		{NewInterval(0, 7, 0), &NullValue{t: IntervalType}, 1},
+	} {
+		t.Run(fmt.Sprintf("%s vs %s", d.l, d.r), func(t *testing.T) {
+			res, err := d.l.Compare(d.r)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, res)
+		})
+	}
+
+	_, err := NewInterval(0, 1, 0).Compare(&Integer{val: 1})
+	require.ErrorIs(t, err, ErrNotComparableValues)
+
+	_, err = NewInterval(0, 1, 0).Compare(&Varchar{val: "one day"})
+	require.ErrorIs(t, err, ErrNotComparableValues)
+}
+
+func TestTemporalOperators(t *testing.T) {
+	ts := &Timestamp{val: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}
+	day := NewInterval(0, 1, 0)
+
+	for _, d := range []struct {
+		op  NumOperator
+		l   TypedValue
+		r   TypedValue
+		exp TypedValue
+	}{
+		{ADDOP, ts, day, &Timestamp{val: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		{ADDOP, day, ts, &Timestamp{val: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		{SUBSOP, ts, day, &Timestamp{val: time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)}},
+		{SUBSOP, ts, &Timestamp{val: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}, NewInterval(0, 29, 12*microsPerHour)},
+		{ADDOP, day, NewInterval(1, 0, 1), NewInterval(1, 1, 1)},
+		{SUBSOP, day, NewInterval(1, 0, 1), NewInterval(-1, 1, -1)},
+		{ADDOP, ts, &NullValue{t: IntervalType}, &NullValue{t: TimestampType}},
+		{SUBSOP, &NullValue{t: TimestampType}, ts, &NullValue{t: IntervalType}},
+	} {
+		t.Run(fmt.Sprintf("%s %d %s", d.l, d.op, d.r), func(t *testing.T) {
+			res, err := applyTemporalOperator(d.op, d.l, d.r)
+			require.NoError(t, err)
+			require.Equal(t, d.exp, res)
+		})
+	}
+
+	for _, d := range []struct {
+		op NumOperator
+		l  SQLValueType
+		r  SQLValueType
+	}{
+		{ADDOP, TimestampType, TimestampType},
+		{SUBSOP, IntervalType, TimestampType},
+		{MULTOP, TimestampType, IntervalType},
+		{ADDOP, TimestampType, IntegerType},
+	} {
+		t.Run(fmt.Sprintf("%s %d %s", d.l, d.op, d.r), func(t *testing.T) {
+			_, err := temporalOperatorType(d.op, d.l, d.r)
+			require.ErrorIs(t, err, ErrInvalidTypes)
+		})
+	}
+}
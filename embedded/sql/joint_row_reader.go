@@ -55,6 +55,43 @@ func newJointRowReader(rowReader RowReader, joins []*JoinSpec) (*jointRowReader,
 	}, nil
 }
 
+// newJoinsRowReader chains the readers required to resolve the specified joins. Consecutive INNER and LEFT joins
+// are resolved by a single jointRowReader while RIGHT and FULL OUTER joins are resolved by an outerJoinRowReader
+// over the rows joined so far.
+func newJoinsRowReader(rowReader RowReader, joins []*JoinSpec) (RowReader, error) {
+	if rowReader == nil || len(joins) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	for len(joins) > 0 {
+		i := 0
+		for i < len(joins) && (joins[i].joinType == InnerJoin || joins[i].joinType == LeftJoin) {
+			i++
+		}
+
+		if i > 0 {
+			jointRowReader, err := newJointRowReader(rowReader, joins[:i])
+			if err != nil {
+				return nil, err
+			}
+			rowReader = jointRowReader
+		}
+
+		if i == len(joins) {
+			break
+		}
+
+		outerJoinRowReader, err := newOuterJoinRowReader(rowReader, joins[i])
+		if err != nil {
+			return nil, err
+		}
+		rowReader = outerJoinRowReader
+
+		joins = joins[i+1:]
+	}
+	return rowReader, nil
+}
+
 func (jointr *jointRowReader) onClose(callback func()) {
 	jointr.rowReader.onClose(callback)
 }
@@ -168,7 +205,7 @@ func (jointr *jointRowReader) InferParameters(ctx context.Context, params map[st
 			return err
 		}
 
-		_, err = join.cond.inferType(cols, params, jointr.TableAlias())
+		_, err = join.cond.inferType(jointr.Tx(), cols, params, jointr.TableAlias())
 		if err != nil {
 			return err
 		}
@@ -260,17 +297,7 @@ func (jointr *jointRowReader) Read(ctx context.Context) (row *Row, err error) {
 						return nil, err
 					}
 
-					r = &Row{
-						ValuesByPosition: make([]TypedValue, len(cols)),
-						ValuesBySelector: make(map[string]TypedValue, len(cols)),
-					}
-
-					for i, col := range cols {
-						nullValue := NewNull(col.Type)
-
-						r.ValuesByPosition[i] = nullValue
-						r.ValuesBySelector[col.Selector()] = nullValue
-					}
+					r = nullRow(cols)
 				}
 			} else if err != nil {
 				reader.Close()
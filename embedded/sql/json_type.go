@@ -56,17 +56,21 @@ func (v *JSON) IsNull() bool {
 	return false
 }
 
-func (v *JSON) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+func (v *JSON) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	return JSONType, nil
 }
 
-func (v *JSON) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+func (v *JSON) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
 	ok := t == JSONType
 	switch t {
 	case IntegerType, Float64Type:
 		_, isInt := v.val.(int64)
 		_, isFloat := v.val.(float64)
 		ok = isInt || (isFloat && t == Float64Type)
+	case DecimalType:
+		_, isInt := v.val.(int64)
+		_, isFloat := v.val.(float64)
+		ok = isInt || isFloat
 	case VarcharType:
 		_, ok = v.val.(string)
 	case BooleanType:
@@ -101,7 +105,7 @@ func (v *JSON) isConstant() bool {
 	return true
 }
 
-func (v *JSON) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *JSON) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
@@ -127,6 +131,31 @@ func (v *JSON) Compare(val TypedValue) (int, error) {
 	return -res, err
 }
 
+// jsonValuesOfSameKind returns false when a JSON value is compared against a value of a kind it does not hold,
+// e.g. a JSON number against a VARCHAR, in which case the comparison is not satisfied
+func jsonValuesOfSameKind(v1, v2 TypedValue) bool {
+	jv, isJSON := v1.(*JSON)
+	other := v2
+	if !isJSON {
+		jv, isJSON = v2.(*JSON)
+		other = v1
+	}
+
+	if !isJSON || other.IsNull() {
+		return true
+	}
+
+	switch other.Type() {
+	case IntegerType, Float64Type:
+		return jv.primitiveType() == JSONTypeNumber
+	case VarcharType:
+		return jv.primitiveType() == JSONTypeString
+	case BooleanType:
+		return jv.primitiveType() == JSONTypeBool
+	}
+	return true
+}
+
 func (v *JSON) primitiveType() string {
 	switch v.val.(type) {
 	case int64, float64:
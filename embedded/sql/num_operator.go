@@ -22,9 +22,15 @@ import (
 )
 
 func applyNumOperator(op NumOperator, vl, vr TypedValue) (TypedValue, error) {
+	if isTemporalType(vl.Type()) || isTemporalType(vr.Type()) {
+		return applyTemporalOperator(op, vl, vr)
+	}
 	if vl.Type() == Float64Type || vr.Type() == Float64Type {
 		return applyNumOperatorFloat64(op, vl, vr)
 	}
+	if vl.Type() == DecimalType || vr.Type() == DecimalType {
+		return applyNumOperatorDecimal(op, vl, vr)
+	}
 	return applyNumOperatorInteger(op, vl, vr)
 }
 
@@ -137,3 +143,37 @@ func applyNumOperatorFloat64(op NumOperator, vl, vr TypedValue) (TypedValue, err
 
 	return nil, ErrUnexpected
 }
+
+func applyNumOperatorDecimal(op NumOperator, vl, vr TypedValue) (TypedValue, error) {
+	nl, err := decimalValue(vl.RawValue())
+	if err != nil {
+		return nil, fmt.Errorf("%w (expecting numeric value)", err)
+	}
+
+	nr, err := decimalValue(vr.RawValue())
+	if err != nil {
+		return nil, fmt.Errorf("%w (expecting numeric value)", err)
+	}
+
+	var res *Decimal
+
+	switch op {
+	case ADDOP:
+		res, err = nl.add(nr)
+	case SUBSOP:
+		res, err = nl.sub(nr)
+	case DIVOP:
+		res, err = nl.div(nr)
+	case MODOP:
+		res, err = nl.mod(nr)
+	case MULTOP:
+		res, err = nl.mul(nr)
+	default:
+		return nil, ErrUnexpected
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -57,6 +57,17 @@ func TestNumOperator(t *testing.T) {
 			{MULTOP, &Float64{val: 10}, &Integer{val: 3}, float64(30)},
 			{MULTOP, &Integer{val: 10}, &Float64{val: 3}, float64(30)},
 			{MULTOP, &Float64{val: 10}, &Float64{val: 3}, float64(30)},
+
+			{ADDOP, NewDecimal(1050, 2), &Integer{val: 2}, "12.50"},
+			{ADDOP, NewDecimal(1, 1), NewDecimal(2, 1), "0.3"},
+			{ADDOP, NewDecimal(105, 1), &Float64{val: 1}, float64(11.5)},
+			{SUBSOP, NewDecimal(1050, 2), NewDecimal(25, 2), "10.25"},
+			{SUBSOP, &Integer{val: 1}, NewDecimal(125, 2), "-0.25"},
+			{DIVOP, NewDecimal(10, 0), &Integer{val: 3}, "3.3333333333333333"},
+			{DIVOP, NewDecimal(200, 2), NewDecimal(-3, 0), "-0.6666666666666667"},
+			{MODOP, NewDecimal(1050, 2), &Integer{val: 3}, "1.50"},
+			{MULTOP, NewDecimal(1050, 2), &Integer{val: 3}, "31.50"},
+			{MULTOP, NewDecimal(15, 1), NewDecimal(-15, 1), "-2.25"},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
 				result, err := applyNumOperator(d.op, d.lv, d.rv)
@@ -75,6 +86,8 @@ func TestNumOperator(t *testing.T) {
 			{&Float64{val: 100}, &Integer{val: 0}},
 			{&Integer{val: 100}, &Float64{val: 0}},
 			{&Float64{val: 100}, &Float64{val: 0}},
+			{NewDecimal(100, 0), &Integer{val: 0}},
+			{&Integer{val: 100}, NewDecimal(0, 2)},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
 				result, err := applyNumOperator(DIVOP, d.lv, d.rv)
@@ -93,6 +106,7 @@ func TestNumOperator(t *testing.T) {
 			{&Float64{val: 100}, &Bool{}},
 			{&Bool{}, &Integer{val: 100}},
 			{&Bool{}, &Float64{val: 100}},
+			{NewDecimal(100, 0), &Bool{}},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
 				result, err := applyNumOperator(ADDOP, d.lv, d.rv)
@@ -109,6 +123,7 @@ func TestNumOperator(t *testing.T) {
 		}{
 			{&Integer{val: 100}, &Integer{val: 1}},
 			{&Float64{val: 100}, &Float64{val: 1}},
+			{NewDecimal(100, 0), NewDecimal(1, 0)},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
 				result, err := applyNumOperator(NumOperator(-1), d.lv, d.rv)
@@ -25,8 +25,15 @@ import (
 const (
 	defaultDistinctLimit  = 1 << 20 // ~ 1mi rows
 	defaultSortBufferSize = 1024
+
+	defaultIndexBackfillProgressInterval = 1000
 )
 
+// IndexBackfillProgressFunc is invoked while existing rows are validated during the creation
+// of a unique index on a non-empty table, receiving the name of the index and the number of
+// rows validated so far.
+type IndexBackfillProgressFunc func(indexName string, validatedRows int)
+
 type Options struct {
 	prefix                        []byte
 	sortBufferSize                int
@@ -35,14 +42,20 @@ type Options struct {
 	lazyIndexConstraintValidation bool
 	parseTxMetadata               func([]byte) (map[string]interface{}, error)
 
+	indexBackfillProgressInterval int
+	indexBackfillProgressFunc     IndexBackfillProgressFunc
+
 	multidbHandler MultiDBHandler
 	tableResolvers []TableResolver
+	userFunctions  []*userFunction
 }
 
 func DefaultOptions() *Options {
 	return &Options{
 		sortBufferSize: defaultSortBufferSize,
 		distinctLimit:  defaultDistinctLimit,
+
+		indexBackfillProgressInterval: defaultIndexBackfillProgressInterval,
 	}
 }
 
@@ -59,6 +72,10 @@ func (opts *Options) Validate() error {
 		return fmt.Errorf("%w: invalid SortBufferSize value", store.ErrInvalidOptions)
 	}
 
+	if opts.indexBackfillProgressInterval <= 0 {
+		return fmt.Errorf("%w: invalid IndexBackfillProgressInterval value", store.ErrInvalidOptions)
+	}
+
 	return nil
 }
 
@@ -95,6 +112,21 @@ func (opts *Options) WithSortBufferSize(size int) *Options {
 	return opts
 }
 
+// WithIndexBackfillProgressInterval specifies the number of existing rows validated between consecutive
+// progress notifications, and cancellation checks, when a unique index is created on a non-empty table.
+// The default value is 1000.
+func (opts *Options) WithIndexBackfillProgressInterval(size int) *Options {
+	opts.indexBackfillProgressInterval = size
+	return opts
+}
+
+// WithIndexBackfillProgressFunc specifies the function used to report the progress of the
+// validation of existing rows when a unique index is created on a non-empty table.
+func (opts *Options) WithIndexBackfillProgressFunc(progressFunc IndexBackfillProgressFunc) *Options {
+	opts.indexBackfillProgressFunc = progressFunc
+	return opts
+}
+
 func (opts *Options) WithParseTxMetadataFunc(parseFunc func([]byte) (map[string]interface{}, error)) *Options {
 	opts.parseTxMetadata = parseFunc
 	return opts
@@ -104,3 +136,15 @@ func (opts *Options) WithTableResolvers(resolvers ...TableResolver) *Options {
 	opts.tableResolvers = append(opts.tableResolvers, resolvers...)
 	return opts
 }
+
+// WithFunction specifies a user defined function to be registered into the engine,
+// see Engine.RegisterFunction for the conditions it must satisfy.
+// Functions used by check constraints, default values or generated columns must be specified each time the engine is opened.
+func (opts *Options) WithFunction(name string, fn Function, deterministic bool) *Options {
+	opts.userFunctions = append(opts.userFunctions, &userFunction{
+		name:          name,
+		fn:            fn,
+		deterministic: deterministic,
+	})
+	return opts
+}
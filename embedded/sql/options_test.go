@@ -48,5 +48,18 @@ func TestOptions(t *testing.T) {
 	opts.WithSortBufferSize(defaultSortBufferSize)
 	require.Equal(t, opts.sortBufferSize, defaultSortBufferSize)
 
+	opts.WithIndexBackfillProgressInterval(0)
+	require.Error(t, opts.Validate())
+
+	opts.WithIndexBackfillProgressInterval(defaultIndexBackfillProgressInterval)
+	require.Equal(t, defaultIndexBackfillProgressInterval, opts.indexBackfillProgressInterval)
+
+	opts.WithIndexBackfillProgressFunc(func(indexName string, validatedRows int) {})
+	require.NotNil(t, opts.indexBackfillProgressFunc)
+
+	opts.WithFunction("fn", &mockFunction{t: IntegerType}, true)
+	require.Len(t, opts.userFunctions, 1)
+	require.True(t, opts.userFunctions[0].deterministic)
+
 	require.NoError(t, opts.Validate())
 }
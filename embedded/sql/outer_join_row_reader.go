@@ -0,0 +1,326 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/codenotary/immudb/embedded/multierr"
+)
+
+// outerJoinRowReader implements RIGHT and FULL OUTER joins of the rows produced by rowReader
+// with the rows of a single data source.
+//
+// Rows are produced in two phases: first, every row of the left side is joined with the matching rows
+// of the right side (NULL padded for FULL OUTER joins when no match is found). Then, the right side is
+// scanned once more and the rows which were not matched are returned with NULL values for the left side.
+type outerJoinRowReader struct {
+	rowReader RowReader
+
+	jspec *JoinSpec
+
+	leftCols  []ColDescriptor
+	rightCols []ColDescriptor
+
+	leftRow     *Row
+	rightReader RowReader
+
+	matchedRows    map[[sha256.Size]byte]struct{}
+	scanUnmatched  bool
+	leftRowMatched bool
+
+	onCloseCallback func()
+	closed          bool
+}
+
+func newOuterJoinRowReader(rowReader RowReader, jspec *JoinSpec) (*outerJoinRowReader, error) {
+	if rowReader == nil || jspec == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	switch jspec.joinType {
+	case RightJoin, FullOuterJoin:
+	default:
+		return nil, ErrUnsupportedJoinType
+	}
+
+	ctx := context.Background()
+
+	leftCols, err := rowReader.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: We're using a dummy ScanSpec object that is only used during read, we're only interested
+	//       in column list though
+	rr, err := jspec.ds.Resolve(ctx, rowReader.Tx(), nil, &ScanSpecs{Index: &Index{}})
+	if err != nil {
+		return nil, err
+	}
+	defer rr.Close()
+
+	rightCols, err := rr.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outerJoinRowReader{
+		rowReader:   rowReader,
+		jspec:       jspec,
+		leftCols:    leftCols,
+		rightCols:   rightCols,
+		matchedRows: make(map[[sha256.Size]byte]struct{}),
+	}, nil
+}
+
+// onClose callback is not propagated to the left reader, as it may be closed
+// as soon as all its rows are read, while the right side still needs to be scanned
+func (r *outerJoinRowReader) onClose(callback func()) {
+	r.onCloseCallback = callback
+}
+
+func (r *outerJoinRowReader) Tx() *SQLTx {
+	return r.rowReader.Tx()
+}
+
+func (r *outerJoinRowReader) TableAlias() string {
+	return r.rowReader.TableAlias()
+}
+
+func (r *outerJoinRowReader) Parameters() map[string]interface{} {
+	return r.rowReader.Parameters()
+}
+
+func (r *outerJoinRowReader) OrderBy() []ColDescriptor {
+	return nil
+}
+
+func (r *outerJoinRowReader) ScanSpecs() *ScanSpecs {
+	return r.rowReader.ScanSpecs()
+}
+
+func (r *outerJoinRowReader) Columns(ctx context.Context) ([]ColDescriptor, error) {
+	cols := make([]ColDescriptor, 0, len(r.leftCols)+len(r.rightCols))
+	cols = append(cols, r.leftCols...)
+	return append(cols, r.rightCols...), nil
+}
+
+func (r *outerJoinRowReader) colsBySelector(ctx context.Context) (map[string]ColDescriptor, error) {
+	colDescriptors, err := r.rowReader.colsBySelector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jointDescriptors := make(map[string]ColDescriptor, len(colDescriptors)+len(r.rightCols))
+	for sel, desc := range colDescriptors {
+		jointDescriptors[sel] = desc
+	}
+
+	for _, des := range r.rightCols {
+		sel := des.Selector()
+
+		if _, exists := jointDescriptors[sel]; exists {
+			return nil, fmt.Errorf(
+				"error resolving '%s' in a join: %w, "+
+					"use aliasing to assign unique names "+
+					"for all tables, sub-queries and columns",
+				sel,
+				ErrAmbiguousSelector,
+			)
+		}
+		jointDescriptors[sel] = des
+	}
+	return jointDescriptors, nil
+}
+
+func (r *outerJoinRowReader) InferParameters(ctx context.Context, params map[string]SQLValueType) error {
+	err := r.rowReader.InferParameters(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	cols, err := r.colsBySelector(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.jspec.ds.inferParameters(ctx, r.Tx(), params)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.jspec.cond.inferType(r.Tx(), cols, params, r.TableAlias())
+	return err
+}
+
+func (r *outerJoinRowReader) Read(ctx context.Context) (*Row, error) {
+	if r.scanUnmatched {
+		return r.readUnmatched(ctx)
+	}
+
+	for {
+		if r.rightReader == nil {
+			leftRow, err := r.rowReader.Read(ctx)
+			if err == ErrNoMoreRows {
+				return r.startUnmatchedScan(ctx)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			jointq := &SelectStmt{
+				ds:      r.jspec.ds,
+				where:   r.jspec.cond.reduceSelectors(leftRow, r.TableAlias()),
+				indexOn: r.jspec.indexOn,
+			}
+
+			reader, err := jointq.Resolve(ctx, r.Tx(), r.Parameters(), nil)
+			if err != nil {
+				return nil, err
+			}
+
+			r.leftRow = leftRow
+			r.rightReader = reader
+			r.leftRowMatched = false
+		}
+
+		rightRow, err := r.rightReader.Read(ctx)
+		if err == ErrNoMoreRows {
+			err = r.rightReader.Close()
+			r.rightReader = nil
+			if err != nil {
+				return nil, err
+			}
+
+			if !r.leftRowMatched && r.jspec.joinType == FullOuterJoin {
+				return r.jointRow(r.leftRow, nullRow(r.rightCols)), nil
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		digest, err := rightRow.digest(r.rightCols)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, matched := r.matchedRows[digest]; !matched {
+			if len(r.matchedRows) == r.Tx().distinctLimit() {
+				return nil, ErrTooManyRows
+			}
+			r.matchedRows[digest] = struct{}{}
+		}
+
+		r.leftRowMatched = true
+
+		return r.jointRow(r.leftRow, rightRow), nil
+	}
+}
+
+func (r *outerJoinRowReader) startUnmatchedScan(ctx context.Context) (*Row, error) {
+	jointq := &SelectStmt{
+		ds:      r.jspec.ds,
+		indexOn: r.jspec.indexOn,
+	}
+
+	reader, err := jointq.Resolve(ctx, r.Tx(), r.Parameters(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.rightReader = reader
+	r.scanUnmatched = true
+
+	return r.readUnmatched(ctx)
+}
+
+func (r *outerJoinRowReader) readUnmatched(ctx context.Context) (*Row, error) {
+	for {
+		rightRow, err := r.rightReader.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		digest, err := rightRow.digest(r.rightCols)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, matched := r.matchedRows[digest]; !matched {
+			return r.jointRow(nullRow(r.leftCols), rightRow), nil
+		}
+	}
+}
+
+func (r *outerJoinRowReader) jointRow(left, right *Row) *Row {
+	row := &Row{
+		ValuesByPosition: make([]TypedValue, 0, len(left.ValuesByPosition)+len(right.ValuesByPosition)),
+		ValuesBySelector: make(map[string]TypedValue, len(left.ValuesBySelector)+len(right.ValuesBySelector)),
+	}
+
+	row.ValuesByPosition = append(row.ValuesByPosition, left.ValuesByPosition...)
+	row.ValuesByPosition = append(row.ValuesByPosition, right.ValuesByPosition...)
+
+	for sel, v := range left.ValuesBySelector {
+		row.ValuesBySelector[sel] = v
+	}
+
+	for sel, v := range right.ValuesBySelector {
+		row.ValuesBySelector[sel] = v
+	}
+	return row
+}
+
+func nullRow(cols []ColDescriptor) *Row {
+	row := &Row{
+		ValuesByPosition: make([]TypedValue, len(cols)),
+		ValuesBySelector: make(map[string]TypedValue, len(cols)),
+	}
+
+	for i, col := range cols {
+		nullValue := NewNull(col.Type)
+
+		row.ValuesByPosition[i] = nullValue
+		row.ValuesBySelector[col.Selector()] = nullValue
+	}
+	return row
+}
+
+func (r *outerJoinRowReader) Close() error {
+	if r.closed {
+		return ErrAlreadyClosed
+	}
+
+	r.closed = true
+
+	merr := multierr.NewMultiErr()
+
+	if r.rightReader != nil {
+		merr.Append(r.rightReader.Close())
+	}
+
+	merr.Append(r.rowReader.Close())
+
+	if r.onCloseCallback != nil {
+		r.onCloseCallback()
+	}
+	return merr.Reduce()
+}
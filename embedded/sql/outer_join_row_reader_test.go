@@ -0,0 +1,95 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOuterJoinRowReader(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, err = newOuterJoinRowReader(nil, nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE table1(id INTEGER, number INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions())
+	require.NoError(t, err)
+
+	defer tx.Cancel()
+
+	table := tx.catalog.tables[0]
+
+	r, err := newRawRowReader(tx, nil, table, period{}, "", &ScanSpecs{Index: table.primaryIndex})
+	require.NoError(t, err)
+
+	_, err = newOuterJoinRowReader(r, &JoinSpec{joinType: LeftJoin})
+	require.ErrorIs(t, err, ErrUnsupportedJoinType)
+
+	_, err = newOuterJoinRowReader(r, &JoinSpec{joinType: RightJoin, ds: &dummyDataSource{
+		ResolveFunc: func(ctx context.Context, tx *SQLTx, params map[string]interface{}, ScanSpecs *ScanSpecs) (RowReader, error) {
+			return &dummyRowReader{failReturningColumns: true}, nil
+		},
+	}})
+	require.ErrorIs(t, err, errDummy)
+
+	jr, err := newOuterJoinRowReader(r, &JoinSpec{joinType: FullOuterJoin, ds: &tableRef{table: "table1", as: "table2"}})
+	require.NoError(t, err)
+	require.Nil(t, jr.OrderBy())
+	require.Equal(t, "table1", jr.TableAlias())
+
+	cols, err := jr.Columns(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cols, 4)
+	require.Equal(t, "table1", cols[0].Table)
+	require.Equal(t, "table2", cols[3].Table)
+	require.Equal(t, "number", cols[3].Column)
+
+	colsBySel, err := jr.colsBySelector(context.Background())
+	require.NoError(t, err)
+	require.Len(t, colsBySel, 4)
+
+	t.Run("detect ambiguous selectors", func(t *testing.T) {
+		jr, err := newOuterJoinRowReader(r, &JoinSpec{joinType: RightJoin, ds: &tableRef{table: "table1"}})
+		require.NoError(t, err)
+
+		_, err = jr.colsBySelector(context.Background())
+		require.ErrorIs(t, err, ErrAmbiguousSelector)
+	})
+
+	t.Run("onClose callback is invoked once all readers are closed", func(t *testing.T) {
+		var callbackInvoked bool
+		jr.onClose(func() { callbackInvoked = true })
+
+		err := jr.Close()
+		require.NoError(t, err)
+		require.True(t, callbackInvoked)
+
+		err = jr.Close()
+		require.ErrorIs(t, err, ErrAlreadyClosed)
+	})
+}
@@ -70,9 +70,18 @@ var reservedWords = map[string]int{
 	"DISTINCT":       DISTINCT,
 	"FROM":           FROM,
 	"UNION":          UNION,
+	"INTERSECT":      INTERSECT,
+	"EXCEPT":         EXCEPT,
 	"ALL":            ALL,
 	"TX":             TX,
 	"JOIN":           JOIN,
+	"OUTER":          OUTER,
+	"OVER":           OVER,
+	"PARTITION":      PARTITION,
+	"RECURSIVE":      RECURSIVE,
+	"EXPLAIN":        EXPLAIN,
+	"ANALYZE":        ANALYZE,
+	"WITHIN":         WITHIN,
 	"HAVING":         HAVING,
 	"WHERE":          WHERE,
 	"GROUP":          GROUP,
@@ -112,17 +121,30 @@ var reservedWords = map[string]int{
 	"PRIVILEGES":     PRIVILEGES,
 	"CHECK":          CHECK,
 	"CONSTRAINT":     CONSTRAINT,
+	"FOREIGN":        FOREIGN,
+	"REFERENCES":     REFERENCES,
+	"RESTRICT":       RESTRICT,
+	"VIEW":           VIEW,
+	"SEQUENCE":       SEQUENCE,
+	"VIEWS":          VIEWS,
+	"DEFAULT":        DEFAULT,
+	"GENERATED":      GENERATED,
+	"ALWAYS":         ALWAYS,
+	"STORED":         STORED,
 	"CASE":           CASE,
 	"WHEN":           WHEN,
 	"THEN":           THEN,
 	"ELSE":           ELSE,
 	"END":            END,
+	"ARRAY":          ARRAY,
+	"ANY":            ANY,
 }
 
 var joinTypes = map[string]JoinType{
 	"INNER": InnerJoin,
 	"LEFT":  LeftJoin,
 	"RIGHT": RightJoin,
+	"FULL":  FullOuterJoin,
 }
 
 var types = map[string]SQLValueType{
@@ -132,16 +154,24 @@ var types = map[string]SQLValueType{
 	"UUID":      UUIDType,
 	"BLOB":      BLOBType,
 	"TIMESTAMP": TimestampType,
+	"INTERVAL":  IntervalType,
 	"FLOAT":     Float64Type,
+	"DECIMAL":   DecimalType,
+	"NUMERIC":   DecimalType,
 	"JSON":      JSONType,
 }
 
 var aggregateFns = map[string]AggregateFn{
-	"COUNT": COUNT,
-	"SUM":   SUM,
-	"MAX":   MAX,
-	"MIN":   MIN,
-	"AVG":   AVG,
+	"COUNT":           COUNT,
+	"SUM":             SUM,
+	"MAX":             MAX,
+	"MIN":             MIN,
+	"AVG":             AVG,
+	"STRING_AGG":      STRING_AGG,
+	"BOOL_AND":        BOOL_AND,
+	"BOOL_OR":         BOOL_OR,
+	"STDDEV":          STDDEV,
+	"PERCENTILE_CONT": PERCENTILE_CONT,
 }
 
 var boolValues = map[string]bool{
@@ -184,6 +214,7 @@ type aheadByteReader struct {
 	nextErr   error
 	r         io.ByteReader
 	readCount int
+	read      []byte // consumed input, required to keep the text of view definitions
 }
 
 func newAheadByteReader(r io.ByteReader) *aheadByteReader {
@@ -201,6 +232,10 @@ func (ar *aheadByteReader) ReadByte() (byte, error) {
 
 	ar.readCount++
 
+	if ar.nextErr == nil {
+		ar.read = append(ar.read, ar.nextChar)
+	}
+
 	return ar.nextChar, ar.nextErr
 }
 
@@ -212,6 +247,13 @@ func (ar *aheadByteReader) NextByte() (byte, error) {
 	return ar.nextChar, ar.nextErr
 }
 
+// textFrom returns the input consumed since the given read count,
+// a trailing statement separator, read ahead by the parser, is excluded
+func (ar *aheadByteReader) textFrom(readCount int) string {
+	text := strings.TrimSpace(string(ar.read[readCount:]))
+	return strings.TrimSpace(strings.TrimSuffix(text, ";"))
+}
+
 func ParseSQLString(sql string) ([]SQLStmt, error) {
 	return ParseSQL(strings.NewReader(sql))
 }
@@ -236,6 +278,59 @@ func ParseExpFromString(exp string) (ValueExp, error) {
 	return s.where, nil
 }
 
+// typeMaxLen returns the max length of a column of the given type from the arguments of the type,
+// DECIMAL(precision, scale) is the only type accepting two arguments
+func typeMaxLen(t SQLValueType, args []uint64) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	if t != DecimalType {
+		if len(args) > 1 {
+			return 0, fmt.Errorf("invalid type modifier for %s", t)
+		}
+		return int(args[0]), nil
+	}
+
+	precision, scale := args[0], uint64(0)
+	if len(args) > 1 {
+		scale = args[1]
+	}
+
+	if precision < 1 || precision > MaxDecimalPrecision {
+		return 0, fmt.Errorf("DECIMAL precision %d must be between 1 and %d", precision, MaxDecimalPrecision)
+	}
+
+	if scale > precision {
+		return 0, fmt.Errorf("DECIMAL scale %d must be between 0 and precision %d", scale, precision)
+	}
+
+	return decimalMaxLen(int(precision), int(scale)), nil
+}
+
+// typeSpec is a type as specified in column definitions and casts
+type typeSpec struct {
+	t      SQLValueType
+	maxLen int
+}
+
+// newTypeSpec validates the arguments of the type, the max length of an array type applies to its elements
+func newTypeSpec(t SQLValueType, args []uint64, array bool) (typeSpec, error) {
+	maxLen, err := typeMaxLen(t, args)
+	if err != nil {
+		return typeSpec{}, err
+	}
+
+	if !array {
+		return typeSpec{t: t, maxLen: maxLen}, nil
+	}
+
+	if !validArrayElemType(t) {
+		return typeSpec{}, fmt.Errorf("arrays of type %s are not supported", t)
+	}
+	return typeSpec{t: ArrayType(t), maxLen: maxLen}, nil
+}
+
 func newLexer(r io.ByteReader) *lexer {
 	return &lexer{
 		r:   newAheadByteReader(r),
@@ -469,6 +564,18 @@ func (l *lexer) Lex(lval *yySymType) int {
 		return SCAST
 	}
 
+	if ch == '@' && l.r.nextChar == '>' {
+		l.r.ReadByte()
+		lval.arrayOp = CONTAINSOP
+		return ARRAY_OP
+	}
+
+	if ch == '&' && l.r.nextChar == '&' {
+		l.r.ReadByte()
+		lval.arrayOp = OVERLAPSOP
+		return ARRAY_OP
+	}
+
 	if ch == '@' {
 		if l.namedParamsType == UnnamedParamType {
 			lval.err = ErrEitherNamedOrUnnamedParams
@@ -657,6 +764,29 @@ func isSpace(ch byte) bool {
 	return ch == 32 || ch == 9 //SPACE or TAB
 }
 
+// isFunctionName returns true if name is read as an identifier, thus it can be used to call a function
+func isFunctionName(name string) bool {
+	if name == "" || !isLetter(name[0]) {
+		return false
+	}
+
+	for i := 1; i < len(name); i++ {
+		if !isLetter(name[i]) && !isNumber(name[i]) {
+			return false
+		}
+	}
+
+	tid := strings.ToUpper(name)
+
+	_, isType := types[tid]
+	_, isBool := boolValues[tid]
+	_, isAggFn := aggregateFns[tid]
+	_, isJoinType := joinTypes[tid]
+	_, isReserved := reservedWords[tid]
+
+	return !isType && !isBool && !isAggFn && !isJoinType && !isReserved
+}
+
 func isNumber(ch byte) bool {
 	return '0' <= ch && ch <= '9'
 }
@@ -20,6 +20,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -227,6 +228,21 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE TABLE table1 (id INTEGER, created_at TIMESTAMP NOT NULL DEFAULT NOW(), qty INTEGER DEFAULT 1, total INTEGER GENERATED ALWAYS AS (qty * 10) STORED, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table: "table1",
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{colName: "created_at", colType: TimestampType, notNull: true, defaultExp: &FnCall{fn: "now"}},
+						{colName: "qty", colType: IntegerType, defaultExp: &Integer{val: 1}},
+						{colName: "total", colType: IntegerType, generatedExp: &NumExp{op: MULTOP, left: &ColSelector{col: "qty"}, right: &Integer{val: 10}}},
+					},
+					pkColNames: []string{"id"},
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "CREATE TABLE \"table\" (\"primary\" INTEGER AUTO_INCREMENT, PRIMARY KEY \"primary\")",
 			expectedOutput: []SQLStmt{
@@ -278,6 +294,51 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE TABLE ledger (id INTEGER, amount DECIMAL(12,2), rate NUMERIC(5), total DECIMAL, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table: "ledger",
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{colName: "amount", colType: DecimalType, maxLen: decimalMaxLen(12, 2)},
+						{colName: "rate", colType: DecimalType, maxLen: decimalMaxLen(5, 0)},
+						{colName: "total", colType: DecimalType},
+					},
+					pkColNames: []string{"id"},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "CREATE TABLE ledger (id INTEGER, amount DECIMAL(39,2), PRIMARY KEY id)",
+			expectedOutput: nil,
+			expectedError:  errors.New("DECIMAL precision 39 must be between 1 and 38 at position 54"),
+		},
+		{
+			input: "CREATE TABLE posts (id INTEGER, tags VARCHAR(10)[], scores INTEGER[], prices DECIMAL(6,2)[], PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table: "posts",
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{colName: "tags", colType: ArrayType(VarcharType), maxLen: 10},
+						{colName: "scores", colType: ArrayType(IntegerType)},
+						{colName: "prices", colType: ArrayType(DecimalType), maxLen: decimalMaxLen(6, 2)},
+					},
+					pkColNames: []string{"id"},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "CREATE TABLE posts (id INTEGER, data JSON[], PRIMARY KEY id)",
+			expectedOutput: nil,
+			expectedError:  errors.New("arrays of type JSON are not supported at position 43"),
+		},
+		{
+			input:          "CREATE TABLE ledger (id INTEGER, name VARCHAR(10,2), PRIMARY KEY id)",
+			expectedOutput: nil,
+			expectedError:  errors.New("invalid type modifier for VARCHAR at position 52"),
+		},
 		{
 			input:          "CREATE table1",
 			expectedOutput: nil,
@@ -291,7 +352,7 @@ func TestCreateTableStmt(t *testing.T) {
 		{
 			input:          "CREATE TABLE table1()",
 			expectedOutput: []SQLStmt{&CreateTableStmt{table: "table1"}},
-			expectedError:  errors.New("syntax error: unexpected ')', expecting CONSTRAINT or PRIMARY or CHECK or IDENTIFIER at position 21"),
+			expectedError:  errors.New("syntax error: unexpected ')' at position 21"),
 		},
 		{
 			input: "CREATE TABLE table1(id INTEGER, balance FLOAT, CONSTRAINT non_negative_balance CHECK (balance >= 0), PRIMARY KEY id)",
@@ -332,6 +393,51 @@ func TestCreateTableStmt(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "CREATE TABLE orders(id INTEGER, customer_id INTEGER REFERENCES customers, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table: "orders",
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{
+							colName:    "customer_id",
+							colType:    IntegerType,
+							references: &ForeignKeyConstraint{cols: []string{"customer_id"}, refTable: "customers"},
+						},
+					},
+					foreignKeys: []ForeignKeyConstraint{
+						{cols: []string{"customer_id"}, refTable: "customers"},
+					},
+					pkColNames: PrimaryKeyConstraint{"id"},
+				}},
+		},
+		{
+			input: "CREATE TABLE orders(id INTEGER, code VARCHAR, region VARCHAR, CONSTRAINT fk_product FOREIGN KEY (code, region) REFERENCES products(code, region) ON DELETE RESTRICT ON UPDATE RESTRICT, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table: "orders",
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{colName: "code", colType: VarcharType},
+						{colName: "region", colType: VarcharType},
+					},
+					foreignKeys: []ForeignKeyConstraint{
+						{
+							name:     "fk_product",
+							cols:     []string{"code", "region"},
+							refTable: "products",
+							refCols:  []string{"code", "region"},
+						},
+					},
+					pkColNames: PrimaryKeyConstraint{"id"},
+				}},
+		},
+		{
+			input:          "CREATE TABLE orders(id INTEGER, customer_id INTEGER, FOREIGN KEY (customer_id) REFERENCES customers ON DELETE CASCADE, PRIMARY KEY id)",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected IDENTIFIER, expecting RESTRICT at position 117"),
+		},
 		{
 			input: "DROP TABLE table1",
 			expectedOutput: []SQLStmt{
@@ -397,6 +503,26 @@ func TestCreateIndexStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE INDEX ON events((payload->'type'), ts, payload->'user'->'country')",
+			expectedOutput: []SQLStmt{
+				&CreateIndexStmt{
+					table:      "events",
+					cols:       []string{"payload", "ts", "payload"},
+					jsonFields: [][]string{{"type"}, nil, {"user", "country"}},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "DROP INDEX ON events((payload->'type'))",
+			expectedOutput: []SQLStmt{
+				&DropIndexStmt{
+					table:      "events",
+					cols:       []string{"payload"},
+					jsonFields: [][]string{{"type"}},
+				}},
+			expectedError: nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -436,7 +562,7 @@ func TestAlterTable(t *testing.T) {
 		{
 			input:          "ALTER TABLE table1 COLUMN title VARCHAR",
 			expectedOutput: nil,
-			expectedError:  errors.New("syntax error: unexpected COLUMN, expecting DROP or ADD or RENAME at position 25"),
+			expectedError:  errors.New("syntax error: unexpected COLUMN, expecting DROP or ALTER or ADD or RENAME at position 25"),
 		},
 		{
 			input: "ALTER TABLE table1 RENAME COLUMN title TO newtitle",
@@ -453,6 +579,41 @@ func TestAlterTable(t *testing.T) {
 			expectedOutput: nil,
 			expectedError:  errors.New("syntax error: unexpected TO, expecting IDENTIFIER at position 35"),
 		},
+		{
+			input: "ALTER TABLE table1 ALTER COLUMN title TYPE VARCHAR[256]",
+			expectedOutput: []SQLStmt{
+				&AlterColumnStmt{
+					table:      "table1",
+					colName:    "title",
+					changeType: true,
+					colType:    VarcharType,
+					maxLen:     256,
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "ALTER TABLE table1 ALTER COLUMN title KIND VARCHAR",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected kind, expecting TYPE at position 51"),
+		},
+		{
+			input: "ALTER TABLE table1 ALTER COLUMN title SET NOT NULL; ALTER TABLE table1 ALTER COLUMN title DROP NOT NULL",
+			expectedOutput: []SQLStmt{
+				&AlterColumnStmt{
+					table:             "table1",
+					colName:           "title",
+					changeNullability: true,
+					notNull:           true,
+				},
+				&AlterColumnStmt{
+					table:             "table1",
+					colName:           "title",
+					changeNullability: true,
+					notNull:           false,
+				},
+			},
+			expectedError: nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -1202,6 +1363,72 @@ func TestSelectStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT id FROM table1 RIGHT OUTER JOIN table2 ON table1.id = table2.id FULL JOIN table3 ON table2.id = table3.id",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "id"}},
+					},
+					ds: &tableRef{table: "table1"},
+					joins: []*JoinSpec{
+						{
+							joinType: RightJoin,
+							ds:       &tableRef{table: "table2"},
+							cond: &CmpBoolExp{
+								op:    EQ,
+								left:  &ColSelector{table: "table1", col: "id"},
+								right: &ColSelector{table: "table2", col: "id"},
+							},
+						},
+						{
+							joinType: FullOuterJoin,
+							ds:       &tableRef{table: "table3"},
+							cond: &CmpBoolExp{
+								op:    EQ,
+								left:  &ColSelector{table: "table2", col: "id"},
+								right: &ColSelector{table: "table3", col: "id"},
+							},
+						},
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "SELECT id FROM table1 INNER OUTER JOIN table2 ON table1.id = table2.id",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected OUTER, expecting JOIN at position 33"),
+		},
+		{
+			input:          "SELECT id FROM table1 OUTER JOIN table2 ON table1.id = table2.id",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected OUTER at position 27"),
+		},
+		{
+			input: "SELECT ROW_NUMBER() OVER (PARTITION BY account ORDER BY id DESC), SUM(amount) OVER (ORDER BY id) AS balance FROM table1",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{
+							Exp: &WindowFnExp{
+								fn:          "row_number",
+								partitionBy: []ValueExp{&ColSelector{col: "account"}},
+								orderBy:     []*OrdExp{{exp: &ColSelector{col: "id"}, descOrder: true}},
+							},
+						},
+						{
+							Exp: &WindowFnExp{
+								fn:      "SUM",
+								params:  []ValueExp{&ColSelector{col: "amount"}},
+								orderBy: []*OrdExp{{exp: &ColSelector{col: "id"}}},
+							},
+							As: "balance",
+						},
+					},
+					ds: &tableRef{table: "table1"},
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "SELECT id, title FROM (SELECT col1 AS id, col2 AS title FROM table2 LIMIT 100 OFFSET 1) LIMIT 10",
 			expectedOutput: []SQLStmt{
@@ -1352,6 +1579,39 @@ func TestSelectUnionStmt(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT id FROM table1 EXCEPT ALL SELECT id FROM table2 INTERSECT SELECT id FROM table3 UNION SELECT id FROM table4",
+			expectedOutput: []SQLStmt{
+				&UnionStmt{
+					distinct: true,
+					left: &SetOpStmt{
+						op:       Except,
+						distinct: false,
+						left: &SelectStmt{
+							targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+							ds:      &tableRef{table: "table1"},
+						},
+						right: &SetOpStmt{
+							op:       Intersect,
+							distinct: true,
+							left: &SelectStmt{
+								targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+								ds:      &tableRef{table: "table2"},
+							},
+							right: &SelectStmt{
+								targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+								ds:      &tableRef{table: "table3"},
+							},
+						},
+					},
+					right: &SelectStmt{
+						targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+						ds:      &tableRef{table: "table4"},
+					},
+				},
+			},
+			expectedError: nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -1364,6 +1624,182 @@ func TestSelectUnionStmt(t *testing.T) {
 	}
 }
 
+func TestWithStmt(t *testing.T) {
+	t.Run("non-recursive", func(t *testing.T) {
+		res, err := ParseSQLString("WITH t1 AS (SELECT id FROM table1), t2 (n) AS (SELECT id FROM t1) SELECT n FROM t2 AS t JOIN table2 ON t.n = table2.id")
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		stmt, ok := res[0].(*WithStmt)
+		require.True(t, ok)
+		require.False(t, stmt.recursive)
+		require.Len(t, stmt.ctes, 2)
+
+		t1, t2 := stmt.ctes[0], stmt.ctes[1]
+		require.Equal(t, "t1", t1.name)
+		require.Equal(t, []string{"n"}, t2.cols)
+		require.False(t, t1.recursive)
+		require.False(t, t2.recursive)
+
+		require.Equal(t, &tableRef{table: "table1"}, t1.q.(*SelectStmt).ds)
+		require.Equal(t, &cteRef{cte: t1}, t2.q.(*SelectStmt).ds)
+
+		q := stmt.q.(*SelectStmt)
+		require.Equal(t, &cteRef{cte: t2, as: "t"}, q.ds)
+		require.Equal(t, &tableRef{table: "table2"}, q.joins[0].ds)
+		require.Equal(t, "t", stmt.Alias())
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		res, err := ParseSQLString("WITH RECURSIVE r (n) AS (SELECT 1 FROM table1 UNION ALL SELECT n + 1 FROM r WHERE n < 10) SELECT n FROM r")
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		stmt, ok := res[0].(*WithStmt)
+		require.True(t, ok)
+		require.True(t, stmt.recursive)
+		require.Len(t, stmt.ctes, 1)
+
+		r := stmt.ctes[0]
+		require.True(t, r.recursive)
+
+		anchor, recursiveTerm, distinct, err := r.recursiveTerms()
+		require.NoError(t, err)
+		require.False(t, distinct)
+		require.Equal(t, &tableRef{table: "table1"}, anchor.(*SelectStmt).ds)
+		require.Equal(t, &cteRef{cte: r}, recursiveTerm.(*SelectStmt).ds)
+	})
+
+	t.Run("common table expressions are not visible before their declaration", func(t *testing.T) {
+		res, err := ParseSQLString("WITH t1 AS (SELECT id FROM t2), t2 AS (SELECT id FROM table1) SELECT id FROM t1")
+		require.NoError(t, err)
+
+		stmt := res[0].(*WithStmt)
+		require.Equal(t, &tableRef{table: "t2"}, stmt.ctes[0].q.(*SelectStmt).ds)
+	})
+
+	t.Run("duplicated query names", func(t *testing.T) {
+		_, err := ParseSQLString("WITH t1 AS (SELECT id FROM table1), t1 AS (SELECT id FROM table2) SELECT id FROM t1")
+		require.ErrorContains(t, err, "query name 't1' specified more than once")
+
+		_, err = ParseSQLString("WITH t1 AS (WITH t2 AS (SELECT id FROM table1), t2 AS (SELECT id FROM table2) SELECT id FROM t2) SELECT id FROM t1")
+		require.ErrorContains(t, err, "query name 't2' specified more than once")
+
+		// an inner WITH clause may shadow a name of the outer one
+		_, err = ParseSQLString("WITH t1 AS (SELECT id FROM table1) SELECT id FROM (WITH t1 AS (SELECT id FROM table2) SELECT id FROM t1)")
+		require.NoError(t, err)
+	})
+}
+
+func TestExplainStmt(t *testing.T) {
+	res, err := ParseSQLString("EXPLAIN SELECT id FROM table1 WHERE id > 1")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	stmt, ok := res[0].(*ExplainStmt)
+	require.True(t, ok)
+	require.False(t, stmt.Analyze())
+	require.Equal(t, &tableRef{table: "table1"}, stmt.Query().(*SelectStmt).ds)
+	require.True(t, stmt.readOnly())
+
+	res, err = ParseSQLString("EXPLAIN ANALYZE SELECT id FROM table1 UNION SELECT id FROM table2")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	stmt, ok = res[0].(*ExplainStmt)
+	require.True(t, ok)
+	require.True(t, stmt.Analyze())
+	require.IsType(t, &UnionStmt{}, stmt.Query())
+
+	_, err = ParseSQLString("EXPLAIN DELETE FROM table1")
+	require.ErrorContains(t, err, "syntax error")
+
+	_, err = ParseSQLString("EXPLAIN EXPLAIN SELECT id FROM table1")
+	require.ErrorContains(t, err, "syntax error")
+}
+
+func TestViewStmts(t *testing.T) {
+	res, err := ParseSQLString("CREATE VIEW view1 AS SELECT id, title FROM table1 WHERE active")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	stmt, ok := res[0].(*CreateViewStmt)
+	require.True(t, ok)
+	require.Equal(t, "view1", stmt.view)
+	require.False(t, stmt.ifNotExists)
+	require.Empty(t, stmt.cols)
+	require.Equal(t, "SELECT id, title FROM table1 WHERE active", stmt.query)
+
+	res, err = ParseSQLString(`
+		CREATE VIEW IF NOT EXISTS view1 (a, b) AS
+			SELECT id, title FROM table1 BEFORE TX 10
+			UNION
+			SELECT id, title FROM table2;
+
+		DROP VIEW view1;`)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	stmt, ok = res[0].(*CreateViewStmt)
+	require.True(t, ok)
+	require.Equal(t, "view1", stmt.view)
+	require.True(t, stmt.ifNotExists)
+	require.Equal(t, []string{"a", "b"}, stmt.cols)
+	require.Equal(t, "SELECT id, title FROM table1 BEFORE TX 10\n\t\t\tUNION\n\t\t\tSELECT id, title FROM table2", stmt.query)
+	require.Equal(t, &DropViewStmt{view: "view1"}, res[1])
+
+	res, err = ParseSQLString("SHOW VIEWS")
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{&SelectStmt{ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "views"}}}}, res)
+
+	_, err = ParseSQLString("CREATE VIEW view1 AS DELETE FROM table1")
+	require.ErrorContains(t, err, "syntax error")
+}
+
+func TestSequenceStmts(t *testing.T) {
+	res, err := ParseSQLString("CREATE SEQUENCE seq1")
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{NewCreateSequenceStmt("seq1", false, 1, 1, 1, math.MaxInt64, false)}, res)
+
+	res, err = ParseSQLString(`
+		CREATE SEQUENCE IF NOT EXISTS seq1 START WITH 100 INCREMENT BY 10 MAXVALUE 1000 CYCLE;
+		CREATE SEQUENCE seq2 INCREMENT -1 MINVALUE -10;
+		DROP SEQUENCE seq1;`)
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{
+		NewCreateSequenceStmt("seq1", true, 100, 10, 1, 1000, true),
+		NewCreateSequenceStmt("seq2", false, -1, -1, -10, -1, false),
+		&DropSequenceStmt{sequence: "seq1"},
+	}, res)
+
+	res, err = ParseSQLString("INSERT INTO table1(id) VALUES (NEXTVAL('seq1'))")
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{
+		&UpsertIntoStmt{
+			isInsert: true,
+			tableRef: &tableRef{table: "table1"},
+			cols:     []string{"id"},
+			ds: &valuesDataSource{
+				rows: []*RowSpec{
+					{Values: []ValueExp{&FnCall{fn: "nextval", params: []ValueExp{&Varchar{val: "seq1"}}}}},
+				},
+			},
+		},
+	}, res)
+
+	_, err = ParseSQLString("CREATE SEQUENCE seq1 STEP 2")
+	require.ErrorContains(t, err, "syntax error: unexpected step, expecting START or INCREMENT or MINVALUE or MAXVALUE or CYCLE")
+
+	_, err = ParseSQLString("CREATE SEQUENCE seq1 START")
+	require.ErrorContains(t, err, "syntax error: missing value for sequence option START")
+
+	_, err = ParseSQLString("CREATE SEQUENCE seq1 CYCLE 1")
+	require.ErrorContains(t, err, "syntax error: unexpected value for sequence option CYCLE")
+
+	_, err = ParseSQLString("CREATE SEQUENCE seq1 INCREMENT 1 INCREMENT 2")
+	require.ErrorContains(t, err, "syntax error: conflicting or redundant sequence option INCREMENT")
+}
+
 func TestAggFnStmt(t *testing.T) {
 	testCases := []struct {
 		input          string
@@ -1403,6 +1839,30 @@ func TestAggFnStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT COUNT(DISTINCT country), STRING_AGG(DISTINCT name, ', ') FROM table1",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &AggColSelector{aggFn: COUNT, col: "country", distinct: true}},
+						{Exp: &AggColSelector{aggFn: STRING_AGG, col: "name", distinct: true, arg: &Varchar{val: ", "}}},
+					},
+					ds: &tableRef{table: "table1"},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY amount DESC), PERCENTILE_CONT(1) WITHIN GROUP (ORDER BY amount) FROM table1",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &AggColSelector{aggFn: PERCENTILE_CONT, col: "amount", arg: &Float64{val: 0.5}, descOrder: true}},
+						{Exp: &AggColSelector{aggFn: PERCENTILE_CONT, col: "amount", arg: &Float64{val: 1}}},
+					},
+					ds: &tableRef{table: "table1"},
+				}},
+			expectedError: nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -1859,6 +2319,51 @@ func TestParseExp(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "SELECT EXTRACT(year FROM ts) FROM events WHERE ts > NOW() - INTERVAL '1 day'",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &tableRef{table: "events"},
+					targets: []TargetEntry{
+						{
+							Exp: &FnCall{fn: "extract", params: []ValueExp{&Varchar{"year"}, &ColSelector{col: "ts"}}},
+						},
+					},
+					where: &CmpBoolExp{
+						op:   GT,
+						left: &ColSelector{col: "ts"},
+						right: &NumExp{
+							op:    SUBSOP,
+							left:  &FnCall{fn: "now"},
+							right: &Cast{val: &Varchar{"1 day"}, t: IntervalType},
+						},
+					},
+				},
+			},
+		},
+		{
+			input:          "SELECT LENGTH(year FROM ts) FROM events",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected FROM in call to function length at position 27"),
+		},
+		{
+			input: "SELECT POSITION('@' IN email) FROM accounts",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &tableRef{table: "accounts"},
+					targets: []TargetEntry{
+						{
+							Exp: &FnCall{fn: "position", params: []ValueExp{&Varchar{"@"}, &ColSelector{col: "email"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			input:          "SELECT LENGTH('@' IN email) FROM accounts",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected IN in call to function length at position 27"),
+		},
 	}
 
 	for i, tc := range testCases {
@@ -2087,6 +2592,16 @@ func TestExpString(t *testing.T) {
 		"CASE WHEN is_active THEN 'active' WHEN is_expired THEN 'expired' ELSE 'active' END",
 		"'text' LIKE 'pattern'",
 		"'text' NOT LIKE 'pattern'",
+		"ROW_NUMBER() OVER (PARTITION BY account ORDER BY id DESC)",
+		"SUM(amount) OVER (ORDER BY ts, id)",
+		"COUNT(*) OVER ()",
+		"ARRAY[1, 2, 3]",
+		"tag = ANY(tags)",
+		"tags @> ARRAY['a', 'b'] AND tags && @tags",
+		"CAST ('{1,2}' AS INTEGER[])",
+		"ts > NOW() - INTERVAL '1 day'",
+		"EXTRACT(YEAR FROM ts) = 2024 AND DATE_TRUNC('month', ts) < ts",
+		"POSITION('@' IN email) > 0 AND COALESCE(nickname, name) = LPAD(@s, 5, '0')",
 	}
 
 	for i, e := range exps {
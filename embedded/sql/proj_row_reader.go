@@ -26,6 +26,10 @@ type projectedRowReader struct {
 	tableAlias string
 
 	targets []TargetEntry
+
+	// targets with parameters substituted, shared by all rows so that
+	// sub-queries not referencing the rows are evaluated only once
+	substitutedTargets []ValueExp
 }
 
 func newProjectedRowReader(ctx context.Context, rowReader RowReader, tableAlias string, targets []TargetEntry) (*projectedRowReader, error) {
@@ -132,7 +136,7 @@ func (pr *projectedRowReader) colsBySelector(ctx context.Context) (map[string]Co
 			aggFn, table, col = s.resolve(pr.rowReader.TableAlias())
 		}
 
-		sqlType, err := t.Exp.inferType(dsColDescriptors, emptyParams, pr.rowReader.TableAlias())
+		sqlType, err := t.Exp.inferType(pr.Tx(), dsColDescriptors, emptyParams, pr.rowReader.TableAlias())
 		if err != nil {
 			return nil, err
 		}
@@ -170,7 +174,7 @@ func (pr *projectedRowReader) InferParameters(ctx context.Context, params map[st
 	}
 
 	for _, ex := range pr.targets {
-		_, err = ex.Exp.inferType(cols, params, pr.TableAlias())
+		_, err = ex.Exp.inferType(pr.Tx(), cols, params, pr.TableAlias())
 		if err != nil {
 			return err
 		}
@@ -193,13 +197,23 @@ func (pr *projectedRowReader) Read(ctx context.Context) (*Row, error) {
 		ValuesBySelector: make(map[string]TypedValue, len(pr.targets)),
 	}
 
-	for i, t := range pr.targets {
-		e, err := t.Exp.substitute(pr.Parameters())
-		if err != nil {
-			return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
+	if pr.substitutedTargets == nil {
+		exps := make([]ValueExp, len(pr.targets))
+
+		for i, t := range pr.targets {
+			e, err := t.Exp.substitute(pr.Parameters())
+			if err != nil {
+				return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
+			}
+
+			exps[i] = e
 		}
 
-		v, err := e.reduce(pr.Tx(), row, pr.rowReader.TableAlias())
+		pr.substitutedTargets = exps
+	}
+
+	for i, t := range pr.targets {
+		v, err := pr.substitutedTargets[i].reduce(pr.Tx(), row, pr.rowReader.TableAlias())
 		if err != nil {
 			return nil, err
 		}
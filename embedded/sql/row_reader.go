@@ -286,6 +286,15 @@ func keyReaderSpecFrom(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) (sp
 		if !hiKeyReady {
 			if colRange.hRange == nil {
 				hiKeyReady = true
+
+				if col.colType == JSONType && colRange.lRange != nil {
+					// values of a different kind never satisfy the comparison
+					typePrefix, err := jsonKeyTypePrefix(colRange.lRange.val, col.MaxLen())
+					if err != nil {
+						return nil, err
+					}
+					hiKey = append(hiKey, typePrefix...)
+				}
 			} else {
 				encVal, _, err := EncodeValueAsKey(colRange.hRange.val, col.colType, col.MaxLen())
 				if err != nil {
@@ -381,14 +390,14 @@ func (r *rawRowReader) InferParameters(ctx context.Context, params map[string]SQ
 	}
 
 	if r.period.start != nil {
-		_, err = r.period.start.instant.exp.inferType(cols, params, r.TableAlias())
+		_, err = r.period.start.instant.exp.inferType(r.Tx(), cols, params, r.TableAlias())
 		if err != nil {
 			return err
 		}
 	}
 
 	if r.period.end != nil {
-		_, err = r.period.end.instant.exp.inferType(cols, params, r.TableAlias())
+		_, err = r.period.end.instant.exp.inferType(r.Tx(), cols, params, r.TableAlias())
 		if err != nil {
 			return err
 		}
@@ -517,7 +526,7 @@ func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
 			return nil, ErrCorruptedData
 		}
 
-		val, n, err := DecodeValue(v[voff:], col.colType)
+		val, n, err := col.decodeValueAt(v[voff:], vref.Tx())
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/codenotary/immudb/embedded/multierr"
+)
+
+// setOpRowReader implements INTERSECT ALL and EXCEPT ALL operations.
+// Rows of the right side are counted first, then each row of the left side
+// is returned or discarded based on the number of its occurrences in the right side
+type setOpRowReader struct {
+	op SetOperator
+
+	left, right RowReader
+
+	cols      []ColDescriptor
+	rightCols []ColDescriptor
+
+	rightRows map[[sha256.Size]byte]int
+}
+
+func newSetOpRowReader(ctx context.Context, op SetOperator, left, right RowReader) (*setOpRowReader, error) {
+	if left == nil || right == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if op != Intersect && op != Except {
+		return nil, ErrIllegalArguments
+	}
+
+	cols, err := left.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rightCols, err := right.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireCompatibleColumns(cols, rightCols)
+	if err != nil {
+		return nil, err
+	}
+
+	return &setOpRowReader{
+		op:        op,
+		left:      left,
+		right:     right,
+		cols:      cols,
+		rightCols: rightCols,
+	}, nil
+}
+
+func (sr *setOpRowReader) onClose(callback func()) {
+	sr.left.onClose(callback)
+}
+
+func (sr *setOpRowReader) Tx() *SQLTx {
+	return sr.left.Tx()
+}
+
+func (sr *setOpRowReader) TableAlias() string {
+	return ""
+}
+
+func (sr *setOpRowReader) Parameters() map[string]interface{} {
+	return sr.left.Parameters()
+}
+
+func (sr *setOpRowReader) OrderBy() []ColDescriptor {
+	return nil
+}
+
+func (sr *setOpRowReader) ScanSpecs() *ScanSpecs {
+	return nil
+}
+
+func (sr *setOpRowReader) Columns(ctx context.Context) ([]ColDescriptor, error) {
+	return sr.left.Columns(ctx)
+}
+
+func (sr *setOpRowReader) colsBySelector(ctx context.Context) (map[string]ColDescriptor, error) {
+	return sr.left.colsBySelector(ctx)
+}
+
+func (sr *setOpRowReader) InferParameters(ctx context.Context, params map[string]SQLValueType) error {
+	err := sr.left.InferParameters(ctx, params)
+	if err != nil {
+		return err
+	}
+	return sr.right.InferParameters(ctx, params)
+}
+
+func (sr *setOpRowReader) loadRightRows(ctx context.Context) error {
+	rightRows := make(map[[sha256.Size]byte]int)
+
+	for {
+		row, err := sr.right.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		digest, err := row.digest(sr.rightCols)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := rightRows[digest]; !ok && len(rightRows) == sr.Tx().distinctLimit() {
+			return ErrTooManyRows
+		}
+		rightRows[digest]++
+	}
+
+	sr.rightRows = rightRows
+
+	return nil
+}
+
+func (sr *setOpRowReader) Read(ctx context.Context) (*Row, error) {
+	if sr.rightRows == nil {
+		err := sr.loadRightRows(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		row, err := sr.left.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		digest, err := row.digest(sr.cols)
+		if err != nil {
+			return nil, err
+		}
+
+		matched := sr.rightRows[digest] > 0
+		if matched {
+			sr.rightRows[digest]--
+		}
+
+		if (sr.op == Intersect) == matched {
+			return row, nil
+		}
+	}
+}
+
+func (sr *setOpRowReader) Close() error {
+	merr := multierr.NewMultiErr()
+
+	// the right reader is closed first to ensure the onClose callback
+	// is called after the last reader is closed
+	merr.Append(sr.right.Close())
+	merr.Append(sr.left.Close())
+
+	return merr.Reduce()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetOpRowReader(t *testing.T) {
+	_, err := newSetOpRowReader(context.Background(), Intersect, nil, nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	params := map[string]interface{}{
+		"param1": 1,
+	}
+
+	left := &dummyRowReader{
+		database:             "db1",
+		failReturningColumns: true,
+		params:               params,
+	}
+
+	right := &dummyRowReader{
+		database: "db1",
+		params:   params,
+	}
+
+	_, err = newSetOpRowReader(context.Background(), Except+1, left, right)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = newSetOpRowReader(context.Background(), Intersect, left, right)
+	require.ErrorIs(t, err, errDummy)
+
+	left.failReturningColumns = false
+	right.failReturningColumns = true
+
+	_, err = newSetOpRowReader(context.Background(), Except, left, right)
+	require.ErrorIs(t, err, errDummy)
+
+	right.failReturningColumns = false
+
+	rowReader, err := newSetOpRowReader(context.Background(), Except, left, right)
+	require.NoError(t, err)
+	require.NotNil(t, rowReader)
+
+	require.Equal(t, "", rowReader.TableAlias())
+	require.Nil(t, rowReader.OrderBy())
+	require.Nil(t, rowReader.ScanSpecs())
+	require.Equal(t, params, rowReader.Parameters())
+
+	paramTypes := make(map[string]string)
+	err = rowReader.InferParameters(context.Background(), paramTypes)
+	require.NoError(t, err)
+
+	right.failInferringParams = true
+	err = rowReader.InferParameters(context.Background(), paramTypes)
+	require.ErrorIs(t, err, errDummy)
+
+	_, err = rowReader.Read(context.Background())
+	require.ErrorIs(t, err, errDummy)
+
+	left.recordClose = true
+	right.recordClose = true
+
+	err = rowReader.Close()
+	require.NoError(t, err)
+	require.True(t, left.closed)
+	require.True(t, right.closed)
+}
@@ -144,7 +144,7 @@ func getOrderByDescriptors(ordExps []*OrdExp, rowReader RowReader) ([]ColDescrip
 	params := make(map[string]string)
 	orderByDescriptors := make([]ColDescriptor, len(ordExps))
 	for i, col := range ordExps {
-		sqlType, err := col.exp.inferType(colsBySel, params, rowReader.TableAlias())
+		sqlType, err := col.exp.inferType(rowReader.Tx(), colsBySel, params, rowReader.TableAlias())
 		if err != nil {
 			return nil, err
 		}
@@ -57,6 +57,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
     join *JoinSpec
     joinType JoinType
     check CheckConstraint
+    foreignKey ForeignKeyConstraint
     exp ValueExp
     binExp ValueExp
     err error
@@ -74,21 +75,35 @@ func setResult(l yyLexer, stmts []SQLStmt) {
     whenThenClauses []whenThenClause
     tableElem TableElem
     tableElems []TableElem
+    cte *CTE
+    ctes []*CTE
+    indexCol *indexColSpec
+    indexCols []*indexColSpec
+    seqOpt sequenceOption
+    seqOpts []sequenceOption
+    typeArgs []uint64
+    typeSpec typeSpec
+    arrayOp ArrayOperator
 }
 
 %token CREATE DROP USE DATABASE USER WITH PASSWORD READ READWRITE ADMIN SNAPSHOT HISTORY SINCE AFTER BEFORE UNTIL TX OF TIMESTAMP
 %token TABLE UNIQUE INDEX ON ALTER ADD RENAME TO COLUMN CONSTRAINT PRIMARY KEY CHECK GRANT REVOKE GRANTS FOR PRIVILEGES
+%token FOREIGN REFERENCES RESTRICT VIEW SEQUENCE
+%token DEFAULT GENERATED ALWAYS STORED
 %token BEGIN TRANSACTION COMMIT ROLLBACK
 %token INSERT UPSERT INTO VALUES DELETE UPDATE SET CONFLICT DO NOTHING RETURNING
-%token SELECT DISTINCT FROM JOIN HAVING WHERE GROUP BY LIMIT OFFSET ORDER ASC DESC AS UNION ALL CASE WHEN THEN ELSE END
+%token SELECT DISTINCT FROM JOIN OUTER HAVING WHERE GROUP BY LIMIT OFFSET ORDER ASC DESC AS UNION INTERSECT EXCEPT ALL CASE WHEN THEN ELSE END
 %token NOT LIKE IF EXISTS IN IS
-%token AUTO_INCREMENT NULL CAST SCAST
-%token SHOW DATABASES TABLES USERS
+%token OVER PARTITION RECURSIVE WITHIN
+%token EXPLAIN ANALYZE
+%token AUTO_INCREMENT NULL CAST SCAST ARRAY ANY
+%token SHOW DATABASES TABLES USERS VIEWS
 %token <id> NPARAM
 %token <pparam> PPARAM
 %token <joinType> JOINTYPE
 %token <logicOp> AND OR
 %token <cmpOp> CMPOP
+%token <arrayOp> ARRAY_OP
 %token NOT_MATCHES_OP
 %token <id> IDENTIFIER
 %token <sqlType> TYPE
@@ -112,7 +127,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %right LIKE
 %right NOT
 
-%left CMPOP
+%left CMPOP ARRAY_OP
 %left '+' '-'
 %left '*' '/' '%'
 %left  '.'
@@ -120,18 +135,22 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %left IS
 
 %type <stmts> sql sqlstmts
-%type <stmt> sqlstmt ddlstmt dmlstmt dqlstmt select_stmt
+%type <stmt> sqlstmt ddlstmt dmlstmt dqlstmt explainstmt select_stmt compound_select intersect_select
 %type <colSpec> colSpec
 %type <ids> ids one_or_more_ids opt_ids
 %type <cols> cols
 %type <rows> rows
 %type <row> row
-%type <values> values opt_values
+%type <values> values opt_values opt_partitionby
 %type <value> val fnCall
 %type <sel> selector
 %type <jsonFields> jsonFields
+%type <indexCol> index_col
+%type <indexCols> index_cols opt_indexon
+%type <seqOpt> sequence_option
+%type <seqOpts> opt_sequence_options
 %type <col> col
-%type <distinct> opt_distinct opt_all
+%type <distinct> opt_distinct opt_all opt_analyze
 %type <ds> ds values_or_query
 %type <tableRef> tableRef
 %type <period> opt_period
@@ -142,19 +161,24 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %type <join> join
 %type <joinType> opt_join_type
 %type <check> check
+%type <foreignKey> foreign_key
+%type <ids> opt_ref_cols
 %type <tableElem> tableElem
 %type <tableElems> tableElems
-%type <exp> exp opt_exp opt_where opt_having boundexp opt_else
+%type <exp> exp opt_exp opt_where opt_having boundexp opt_else window_fn
 %type <binExp> binExp
 %type <cols> opt_groupby
-%type <exp> opt_limit opt_offset case_when_exp
+%type <exp> opt_limit opt_offset case_when_exp opt_default opt_generated position_operand
 %type <targets> opt_targets targets
-%type <integer> opt_max_len
+%type <typeArgs> opt_type_args
+%type <typeSpec> sql_type
 %type <id> opt_as
 %type <ordexps> ordexps opt_orderby
 %type <opt_ord> opt_ord
-%type <ids> opt_indexon
-%type <boolean> opt_if_not_exists opt_auto_increment opt_not_null opt_not opt_primary_key
+%type <boolean> opt_if_not_exists opt_auto_increment opt_not_null opt_not opt_primary_key opt_recursive opt_outer
+%type <cte> cte
+%type <ctes> ctes
+%type <ids> opt_cte_cols
 %type <update> update
 %type <updates> updates
 %type <onConflict> opt_on_conflict
@@ -186,7 +210,23 @@ sqlstmts:
 
 opt_separator: {} | STMT_SEPARATOR
 
-sqlstmt: ddlstmt | dmlstmt | dqlstmt
+sqlstmt: ddlstmt | dmlstmt | dqlstmt | explainstmt
+
+explainstmt:
+    EXPLAIN opt_analyze dqlstmt
+    {
+        $$ = NewExplainStmt($3.(DataSource), $2)
+    }
+
+opt_analyze:
+    {
+        $$ = false
+    }
+|
+    ANALYZE
+    {
+        $$ = true
+    }
 
 ddlstmt:
     BEGIN TRANSACTION
@@ -233,6 +273,7 @@ ddlstmt:
     {
         colsSpecs := make([]*ColSpec, 0, 5)
         var checks []CheckConstraint
+        var foreignKeys []ForeignKeyConstraint
 
         var pk PrimaryKeyConstraint
 
@@ -240,6 +281,9 @@ ddlstmt:
             switch c := e.(type) {
                 case *ColSpec:
                     colsSpecs = append(colsSpecs, c)
+                    if c.references != nil {
+                        foreignKeys = append(foreignKeys, *c.references)
+                    }
                 case PrimaryKeyConstraint:
                     pk = c
                 case CheckConstraint:
@@ -247,6 +291,8 @@ ddlstmt:
                         checks = make([]CheckConstraint, 0, 5)
                     }
                     checks = append(checks, c)
+                case ForeignKeyConstraint:
+                    foreignKeys = append(foreignKeys, c)
             }
         }
 
@@ -256,6 +302,7 @@ ddlstmt:
             colsSpec: colsSpecs,
             pkColNames: pk,
             checks: checks,
+            foreignKeys: foreignKeys,
         }
     }
 |
@@ -264,19 +311,50 @@ ddlstmt:
         $$ = &DropTableStmt{table: $3}
     }
 |
-    CREATE INDEX opt_if_not_exists ON IDENTIFIER '(' ids ')'
+    CREATE VIEW opt_if_not_exists IDENTIFIER opt_cte_cols AS
+    {
+        $<integer>$ = uint64(yylex.(*lexer).r.ReadCount())
+    }
+    dqlstmt
+    {
+        $$ = NewCreateViewStmt($4, $3, $5, yylex.(*lexer).r.textFrom(int($<integer>7)))
+    }
+|
+    DROP VIEW IDENTIFIER
     {
-        $$ = &CreateIndexStmt{ifNotExists: $3, table: $5, cols: $7}
+        $$ = &DropViewStmt{view: $3}
     }
 |
-    CREATE UNIQUE INDEX opt_if_not_exists ON IDENTIFIER '(' ids ')'
+    CREATE SEQUENCE opt_if_not_exists IDENTIFIER opt_sequence_options
     {
-        $$ = &CreateIndexStmt{unique: true, ifNotExists: $4, table: $6, cols: $8}
+        stmt, err := newCreateSequenceStmt($4, $3, $5)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+        $$ = stmt
     }
 |
-    DROP INDEX ON IDENTIFIER '(' ids ')'
+    DROP SEQUENCE IDENTIFIER
     {
-        $$ = &DropIndexStmt{table: $4, cols: $6}
+        $$ = &DropSequenceStmt{sequence: $3}
+    }
+|
+    CREATE INDEX opt_if_not_exists ON IDENTIFIER '(' index_cols ')'
+    {
+        cols, jsonFields := splitIndexColSpecs($7)
+        $$ = &CreateIndexStmt{ifNotExists: $3, table: $5, cols: cols, jsonFields: jsonFields}
+    }
+|
+    CREATE UNIQUE INDEX opt_if_not_exists ON IDENTIFIER '(' index_cols ')'
+    {
+        cols, jsonFields := splitIndexColSpecs($8)
+        $$ = &CreateIndexStmt{unique: true, ifNotExists: $4, table: $6, cols: cols, jsonFields: jsonFields}
+    }
+|
+    DROP INDEX ON IDENTIFIER '(' index_cols ')'
+    {
+        cols, jsonFields := splitIndexColSpecs($6)
+        $$ = &DropIndexStmt{table: $4, cols: cols, jsonFields: jsonFields}
     }
 |
     DROP INDEX IDENTIFIER DOT IDENTIFIER
@@ -308,6 +386,26 @@ ddlstmt:
     {
         $$ = &DropConstraintStmt{table: $3, constraintName: $6}
     }
+|
+    ALTER TABLE IDENTIFIER ALTER COLUMN IDENTIFIER IDENTIFIER sql_type
+    {
+        // TYPE is not a reserved word so to keep it available as a column name
+        if $7 != "type" {
+            yylex.Error(fmt.Sprintf("syntax error: unexpected %s, expecting TYPE", $7))
+        }
+
+        $$ = &AlterColumnStmt{table: $3, colName: $6, changeType: true, colType: $8.t, maxLen: $8.maxLen}
+    }
+|
+    ALTER TABLE IDENTIFIER ALTER COLUMN IDENTIFIER SET NOT NULL
+    {
+        $$ = &AlterColumnStmt{table: $3, colName: $6, changeNullability: true, notNull: true}
+    }
+|
+    ALTER TABLE IDENTIFIER ALTER COLUMN IDENTIFIER DROP NOT NULL
+    {
+        $$ = &AlterColumnStmt{table: $3, colName: $6, changeNullability: true, notNull: false}
+    }
 |
     CREATE USER IDENTIFIER WITH PASSWORD VARCHAR permission
     {
@@ -416,6 +514,42 @@ opt_if_not_exists:
         $$ = true
     }
 
+opt_sequence_options:
+    {
+        $$ = nil
+    }
+|
+    opt_sequence_options sequence_option
+    {
+        $$ = append($1, $2)
+    }
+
+sequence_option:
+    IDENTIFIER
+    {
+        $$ = sequenceOption{name: $1}
+    }
+|
+    IDENTIFIER opt_with_or_by INTEGER
+    {
+        $$ = sequenceOption{name: $1, value: int64($3), hasValue: true}
+    }
+|
+    IDENTIFIER opt_with_or_by '-' INTEGER
+    {
+        $$ = sequenceOption{name: $1, value: -int64($4), hasValue: true}
+    }
+
+opt_with_or_by:
+    {}
+|
+    WITH
+    {}
+|
+    BY
+    {}
+;
+
 one_or_more_ids:
     IDENTIFIER
     {
@@ -514,6 +648,33 @@ row:
         $$ = &RowSpec{Values: $2}
     }
 
+index_cols:
+    index_col
+    {
+        $$ = []*indexColSpec{$1}
+    }
+|
+    index_cols ',' index_col
+    {
+        $$ = append($1, $3)
+    }
+
+index_col:
+    IDENTIFIER
+    {
+        $$ = &indexColSpec{col: $1}
+    }
+|
+    IDENTIFIER jsonFields
+    {
+        $$ = &indexColSpec{col: $1, jsonFields: $2}
+    }
+|
+    '(' IDENTIFIER jsonFields ')'
+    {
+        $$ = &indexColSpec{col: $2, jsonFields: $3}
+    }
+
 ids:
     IDENTIFIER
     {
@@ -583,9 +744,19 @@ val:
         $$ = &Blob{val: $1}
     }
 |
-    CAST '(' exp AS TYPE ')'
+    CAST '(' exp AS sql_type ')'
     {
-        $$ = &Cast{val: $3, t: $5}
+        $$ = &Cast{val: $3, t: $5.t, maxLen: $5.maxLen}
+    }
+|
+    TYPE VARCHAR
+    {
+        $$ = &Cast{val: &Varchar{val: $2}, t: $1}
+    }
+|
+    ARRAY '[' opt_values ']'
+    {
+        $$ = &ArrayExp{elems: $3}
     }
 |
     fnCall
@@ -613,6 +784,37 @@ fnCall:
     {
         $$ = &FnCall{fn: $1, params: $3}
     }
+|
+    IDENTIFIER '(' IDENTIFIER FROM exp ')'
+    {
+        // EXTRACT is not a reserved word, the field is passed as the first argument of the function
+        if $1 != "extract" {
+            yylex.Error(fmt.Sprintf("syntax error: unexpected FROM in call to function %s", $1))
+        }
+
+        $$ = &FnCall{fn: $1, params: []ValueExp{&Varchar{val: $3}, $5}}
+    }
+|
+    IDENTIFIER '(' boundexp opt_not IN position_operand ')'
+    {
+        // POSITION is not a reserved word, the string is passed as the second argument of the function
+        if $1 != "position" || $4 {
+            yylex.Error(fmt.Sprintf("syntax error: unexpected IN in call to function %s", $1))
+        }
+
+        $$ = &FnCall{fn: $1, params: []ValueExp{$3, $6}}
+    }
+
+position_operand:
+    selector
+    {
+        $$ = $1
+    }
+|
+    val
+    {
+        $$ = $1
+    }
 
 tableElems:
     tableElem
@@ -635,6 +837,17 @@ tableElem:
     {
         $$ = $1
     }
+|
+    colSpec REFERENCES IDENTIFIER opt_ref_cols opt_ref_actions
+    {
+        $1.references = &ForeignKeyConstraint{cols: []string{$1.colName}, refTable: $3, refCols: $4}
+        $$ = $1
+    }
+|
+    foreign_key
+    {
+        $$ = $1
+    }
 |
     PRIMARY KEY one_or_more_ids
     {
@@ -642,11 +855,83 @@ tableElem:
     }
 ;
 
+foreign_key:
+    FOREIGN KEY '(' ids ')' REFERENCES IDENTIFIER opt_ref_cols opt_ref_actions
+    {
+        $$ = ForeignKeyConstraint{cols: $4, refTable: $7, refCols: $8}
+    }
+|
+    CONSTRAINT IDENTIFIER FOREIGN KEY '(' ids ')' REFERENCES IDENTIFIER opt_ref_cols opt_ref_actions
+    {
+        $$ = ForeignKeyConstraint{name: $2, cols: $6, refTable: $9, refCols: $10}
+    }
+;
+
+opt_ref_cols:
+    {
+        $$ = nil
+    }
+|
+    '(' ids ')'
+    {
+        $$ = $2
+    }
+;
+
+/* only RESTRICT semantics are currently supported */
+opt_ref_actions:
+    {}
+|
+    opt_ref_actions ON DELETE RESTRICT
+    {}
+|
+    opt_ref_actions ON UPDATE RESTRICT
+    {}
+;
+
 colSpec:
-    IDENTIFIER TYPE opt_max_len opt_not_null opt_auto_increment opt_primary_key
+    IDENTIFIER sql_type opt_not_null opt_default opt_generated opt_auto_increment opt_primary_key
+    {
+        $$ = &ColSpec{
+            colName: $1,
+            colType: $2.t,
+            maxLen: $2.maxLen,
+            notNull: $3 || $7,
+            defaultExp: $4,
+            generatedExp: $5,
+            autoIncrement: $6,
+            primaryKey: $7,
+        }
+    }
+
+opt_default:
     {
-        $$ = &ColSpec{colName: $1, colType: $2, maxLen: int($3), notNull: $4 || $6, autoIncrement: $5, primaryKey: $6}
+        $$ = nil
+    }
+|
+    DEFAULT exp
+    {
+        $$ = $2
     }
+;
+
+opt_generated:
+    {
+        $$ = nil
+    }
+|
+    GENERATED ALWAYS AS '(' exp ')' opt_stored
+    {
+        $$ = $5
+    }
+;
+
+opt_stored:
+    {}
+|
+    STORED
+    {}
+;
 
 opt_primary_key:
     {
@@ -659,19 +944,66 @@ opt_primary_key:
     }
 ;
 
-opt_max_len:
+sql_type:
+    TYPE opt_type_args
+    {
+        spec, err := newTypeSpec($1, $2, false)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        $$ = spec
+    }
+|
+    TYPE '[' ']'
+    {
+        spec, err := newTypeSpec($1, nil, true)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        $$ = spec
+    }
+|
+    TYPE '(' INTEGER ')' '[' ']'
+    {
+        spec, err := newTypeSpec($1, []uint64{$3}, true)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        $$ = spec
+    }
+|
+    TYPE '(' INTEGER ',' INTEGER ')' '[' ']'
+    {
+        spec, err := newTypeSpec($1, []uint64{$3, $5}, true)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        $$ = spec
+    }
+;
+
+opt_type_args:
     {
-        $$ = 0
+        $$ = nil
     }
 |
     '[' INTEGER ']'
     {
-        $$ = $2
+        $$ = []uint64{$2}
     }
 |
     '(' INTEGER ')'
     {
-        $$ = $2
+        $$ = []uint64{$2}
+    }
+|
+    '(' INTEGER ',' INTEGER ')'
+    {
+        $$ = []uint64{$2, $4}
     }
 
 opt_auto_increment:
@@ -700,17 +1032,19 @@ opt_not_null:
     }
 
 dqlstmt:
-    select_stmt
+    compound_select
     {
         $$ = $1
     }
 |
-    select_stmt UNION opt_all dqlstmt
+    WITH opt_recursive ctes compound_select
     {
-        $$ = &UnionStmt{
-            distinct: $3,
-            left: $1.(DataSource),
-            right: $4.(DataSource),
+        stmt, err := NewWithStmt($3, $2, $4.(DataSource))
+        if err != nil {
+            yylex.Error(err.Error())
+            $$ = $4
+        } else {
+            $$ = stmt
         }
     }
 |
@@ -727,6 +1061,13 @@ dqlstmt:
             ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "tables"}},
         }
     }
+|
+    SHOW VIEWS
+    {
+        $$ = &SelectStmt{
+            ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "views"}},
+        }
+    }
 |
     SHOW TABLE IDENTIFIER
     {
@@ -756,6 +1097,84 @@ dqlstmt:
         }
     }
 
+compound_select:
+    intersect_select
+    {
+        $$ = $1
+    }
+|
+    compound_select UNION opt_all intersect_select
+    {
+        $$ = &UnionStmt{
+            distinct: $3,
+            left: $1.(DataSource),
+            right: $4.(DataSource),
+        }
+    }
+|
+    compound_select EXCEPT opt_all intersect_select
+    {
+        $$ = &SetOpStmt{
+            op: Except,
+            distinct: $3,
+            left: $1.(DataSource),
+            right: $4.(DataSource),
+        }
+    }
+
+intersect_select:
+    select_stmt
+    {
+        $$ = $1
+    }
+|
+    intersect_select INTERSECT opt_all select_stmt
+    {
+        $$ = &SetOpStmt{
+            op: Intersect,
+            distinct: $3,
+            left: $1.(DataSource),
+            right: $4.(DataSource),
+        }
+    }
+
+opt_recursive:
+    {
+        $$ = false
+    }
+|
+    RECURSIVE
+    {
+        $$ = true
+    }
+
+ctes:
+    cte
+    {
+        $$ = []*CTE{$1}
+    }
+|
+    ctes ',' cte
+    {
+        $$ = append($1, $3)
+    }
+
+cte:
+    IDENTIFIER opt_cte_cols AS '(' dqlstmt ')'
+    {
+        $$ = NewCTE($1, $2, $5.(DataSource))
+    }
+
+opt_cte_cols:
+    {
+        $$ = nil
+    }
+|
+    '(' ids ')'
+    {
+        $$ = $2
+    }
+
 select_stmt: SELECT opt_distinct opt_targets FROM ds opt_indexon opt_joins opt_where opt_groupby opt_having opt_orderby opt_limit opt_offset
     {
         $$ = &SelectStmt{
@@ -845,6 +1264,31 @@ selector:
     {
         $$ = &AggColSelector{aggFn: $1, table: $3.table, col: $3.col}
     }
+|
+    AGGREGATE_FUNC '(' DISTINCT col ')'
+    {
+        $$ = &AggColSelector{aggFn: $1, table: $4.table, col: $4.col, distinct: true}
+    }
+|
+    AGGREGATE_FUNC '(' col ',' VARCHAR ')'
+    {
+        $$ = &AggColSelector{aggFn: $1, table: $3.table, col: $3.col, arg: &Varchar{val: $5}}
+    }
+|
+    AGGREGATE_FUNC '(' DISTINCT col ',' VARCHAR ')'
+    {
+        $$ = &AggColSelector{aggFn: $1, table: $4.table, col: $4.col, distinct: true, arg: &Varchar{val: $6}}
+    }
+|
+    AGGREGATE_FUNC '(' FLOAT ')' WITHIN GROUP '(' ORDER BY col opt_ord ')'
+    {
+        $$ = &AggColSelector{aggFn: $1, table: $10.table, col: $10.col, arg: &Float64{val: $3}, descOrder: $11}
+    }
+|
+    AGGREGATE_FUNC '(' INTEGER ')' WITHIN GROUP '(' ORDER BY col opt_ord ')'
+    {
+        $$ = &AggColSelector{aggFn: $1, table: $10.table, col: $10.col, arg: &Float64{val: float64($3)}, descOrder: $11}
+    }
 
 jsonFields:
     ARROW VARCHAR
@@ -883,7 +1327,11 @@ ds:
 |
     '(' dqlstmt ')' opt_as
     {
-        $2.(*SelectStmt).as = $4
+        q := $2
+        if w, ok := q.(*WithStmt); ok {
+            q = w.q
+        }
+        q.(*SelectStmt).as = $4
         $$ = $2.(DataSource)
     }
 |
@@ -896,6 +1344,11 @@ ds:
     {
         $$ = &FnDataSourceStmt{fnCall:  &FnCall{fn: "tables"}, as: $4}
     }
+|
+    VIEWS '(' ')' opt_as
+    {
+        $$ = &FnDataSourceStmt{fnCall:  &FnCall{fn: "views"}, as: $4}
+    }
 |
     TABLE '(' IDENTIFIER ')'
     {
@@ -1002,11 +1455,25 @@ opt_join_type:
         $$ = InnerJoin
     }
 |
-    JOINTYPE
+    JOINTYPE opt_outer
     {
+        if $2 && $1 == InnerJoin {
+            yylex.Error("syntax error: unexpected OUTER, expecting JOIN")
+        }
+
         $$ = $1
     }
 
+opt_outer:
+    {
+        $$ = false
+    }
+|
+    OUTER
+    {
+        $$ = true
+    }
+
 opt_where:
     {
         $$ = nil
@@ -1072,9 +1539,14 @@ opt_indexon:
         $$ = nil
     }
 |
-    USE INDEX ON one_or_more_ids
+    USE INDEX ON IDENTIFIER
     {
-        $$ = $4
+        $$ = []*indexColSpec{{col: $4}}
+    }
+|
+    USE INDEX ON '(' index_cols ')'
+    {
+        $$ = $5
     }
 
 ordexps:
@@ -1184,7 +1656,7 @@ exp:
 |
     boundexp opt_not IN '(' dqlstmt ')'
     {
-        $$ = &InSubQueryExp{val: $1, notIn: $2, q: $5.(*SelectStmt)}
+        $$ = &InSubQueryExp{val: $1, notIn: $2, q: $5.(DataSource)}
     }
 |
     boundexp opt_not IN '(' values ')'
@@ -1247,9 +1719,41 @@ boundexp:
         $$ = $2
     }
 |
-    boundexp SCAST TYPE
+    boundexp SCAST sql_type
+    {
+        $$ = &Cast{val: $1, t: $3.t, maxLen: $3.maxLen}
+    }
+|
+    window_fn
+    {
+        $$ = $1
+    }
+
+window_fn:
+    fnCall OVER '(' opt_partitionby opt_orderby ')'
+    {
+        fn := $1.(*FnCall)
+        $$ = &WindowFnExp{fn: fn.fn, params: fn.params, partitionBy: $4, orderBy: $5}
+    }
+|
+    AGGREGATE_FUNC '(' '*' ')' OVER '(' opt_partitionby opt_orderby ')'
+    {
+        $$ = &WindowFnExp{fn: $1, partitionBy: $7, orderBy: $8}
+    }
+|
+    AGGREGATE_FUNC '(' col ')' OVER '(' opt_partitionby opt_orderby ')'
     {
-        $$ = &Cast{val: $1, t: $3}
+        $$ = &WindowFnExp{fn: $1, params: []ValueExp{$3}, partitionBy: $7, orderBy: $8}
+    }
+
+opt_partitionby:
+    {
+        $$ = nil
+    }
+|
+    PARTITION BY values
+    {
+        $$ = $3
     }
 
 opt_not:
@@ -1302,6 +1806,16 @@ binExp:
     {
         $$ = &CmpBoolExp{left: $1, op: $2, right: $3}
     }
+|
+    exp CMPOP ANY '(' exp ')'
+    {
+        $$ = &AnyCmpExp{op: $2, val: $1, array: $5}
+    }
+|
+    exp ARRAY_OP exp
+    {
+        $$ = &ArrayOpExp{op: $2, left: $1, right: $3}
+    }
 |
     exp IS NULL
     {
@@ -43,6 +43,7 @@ type yySymType struct {
 	join            *JoinSpec
 	joinType        JoinType
 	check           CheckConstraint
+	foreignKey      ForeignKeyConstraint
 	exp             ValueExp
 	binExp          ValueExp
 	err             error
@@ -60,6 +61,15 @@ type yySymType struct {
 	whenThenClauses []whenThenClause
 	tableElem       TableElem
 	tableElems      []TableElem
+	cte             *CTE
+	ctes            []*CTE
+	indexCol        *indexColSpec
+	indexCols       []*indexColSpec
+	seqOpt          sequenceOption
+	seqOpts         []sequenceOption
+	typeArgs        []uint64
+	typeSpec        typeSpec
+	arrayOp         ArrayOperator
 }
 
 const CREATE = 57346
@@ -99,75 +109,97 @@ const REVOKE = 57379
 const GRANTS = 57380
 const FOR = 57381
 const PRIVILEGES = 57382
-const BEGIN = 57383
-const TRANSACTION = 57384
-const COMMIT = 57385
-const ROLLBACK = 57386
-const INSERT = 57387
-const UPSERT = 57388
-const INTO = 57389
-const VALUES = 57390
-const DELETE = 57391
-const UPDATE = 57392
-const SET = 57393
-const CONFLICT = 57394
-const DO = 57395
-const NOTHING = 57396
-const RETURNING = 57397
-const SELECT = 57398
-const DISTINCT = 57399
-const FROM = 57400
-const JOIN = 57401
-const HAVING = 57402
-const WHERE = 57403
-const GROUP = 57404
-const BY = 57405
-const LIMIT = 57406
-const OFFSET = 57407
-const ORDER = 57408
-const ASC = 57409
-const DESC = 57410
-const AS = 57411
-const UNION = 57412
-const ALL = 57413
-const CASE = 57414
-const WHEN = 57415
-const THEN = 57416
-const ELSE = 57417
-const END = 57418
-const NOT = 57419
-const LIKE = 57420
-const IF = 57421
-const EXISTS = 57422
-const IN = 57423
-const IS = 57424
-const AUTO_INCREMENT = 57425
-const NULL = 57426
-const CAST = 57427
-const SCAST = 57428
-const SHOW = 57429
-const DATABASES = 57430
-const TABLES = 57431
-const USERS = 57432
-const NPARAM = 57433
-const PPARAM = 57434
-const JOINTYPE = 57435
-const AND = 57436
-const OR = 57437
-const CMPOP = 57438
-const NOT_MATCHES_OP = 57439
-const IDENTIFIER = 57440
-const TYPE = 57441
-const INTEGER = 57442
-const FLOAT = 57443
-const VARCHAR = 57444
-const BOOLEAN = 57445
-const BLOB = 57446
-const AGGREGATE_FUNC = 57447
-const ERROR = 57448
-const DOT = 57449
-const ARROW = 57450
-const STMT_SEPARATOR = 57451
+const FOREIGN = 57383
+const REFERENCES = 57384
+const RESTRICT = 57385
+const VIEW = 57386
+const SEQUENCE = 57387
+const DEFAULT = 57388
+const GENERATED = 57389
+const ALWAYS = 57390
+const STORED = 57391
+const BEGIN = 57392
+const TRANSACTION = 57393
+const COMMIT = 57394
+const ROLLBACK = 57395
+const INSERT = 57396
+const UPSERT = 57397
+const INTO = 57398
+const VALUES = 57399
+const DELETE = 57400
+const UPDATE = 57401
+const SET = 57402
+const CONFLICT = 57403
+const DO = 57404
+const NOTHING = 57405
+const RETURNING = 57406
+const SELECT = 57407
+const DISTINCT = 57408
+const FROM = 57409
+const JOIN = 57410
+const OUTER = 57411
+const HAVING = 57412
+const WHERE = 57413
+const GROUP = 57414
+const BY = 57415
+const LIMIT = 57416
+const OFFSET = 57417
+const ORDER = 57418
+const ASC = 57419
+const DESC = 57420
+const AS = 57421
+const UNION = 57422
+const INTERSECT = 57423
+const EXCEPT = 57424
+const ALL = 57425
+const CASE = 57426
+const WHEN = 57427
+const THEN = 57428
+const ELSE = 57429
+const END = 57430
+const NOT = 57431
+const LIKE = 57432
+const IF = 57433
+const EXISTS = 57434
+const IN = 57435
+const IS = 57436
+const OVER = 57437
+const PARTITION = 57438
+const RECURSIVE = 57439
+const WITHIN = 57440
+const EXPLAIN = 57441
+const ANALYZE = 57442
+const AUTO_INCREMENT = 57443
+const NULL = 57444
+const CAST = 57445
+const SCAST = 57446
+const ARRAY = 57447
+const ANY = 57448
+const SHOW = 57449
+const DATABASES = 57450
+const TABLES = 57451
+const USERS = 57452
+const VIEWS = 57453
+const NPARAM = 57454
+const PPARAM = 57455
+const JOINTYPE = 57456
+const AND = 57457
+const OR = 57458
+const CMPOP = 57459
+const ARRAY_OP = 57460
+const NOT_MATCHES_OP = 57461
+const IDENTIFIER = 57462
+const TYPE = 57463
+const INTEGER = 57464
+const FLOAT = 57465
+const VARCHAR = 57466
+const BOOLEAN = 57467
+const BLOB = 57468
+const AGGREGATE_FUNC = 57469
+const ERROR = 57470
+const DOT = 57471
+const ARROW = 57472
+const STMT_SEPARATOR = 57473
 
 var yyToknames = [...]string{
 	"$end",
@@ -210,6 +242,15 @@ var yyToknames = [...]string{
 	"GRANTS",
 	"FOR",
 	"PRIVILEGES",
+	"FOREIGN",
+	"REFERENCES",
+	"RESTRICT",
+	"VIEW",
+	"SEQUENCE",
+	"DEFAULT",
+	"GENERATED",
+	"ALWAYS",
+	"STORED",
 	"BEGIN",
 	"TRANSACTION",
 	"COMMIT",
@@ -229,6 +270,7 @@ var yyToknames = [...]string{
 	"DISTINCT",
 	"FROM",
 	"JOIN",
+	"OUTER",
 	"HAVING",
 	"WHERE",
 	"GROUP",
@@ -240,6 +282,8 @@ var yyToknames = [...]string{
 	"DESC",
 	"AS",
 	"UNION",
+	"INTERSECT",
+	"EXCEPT",
 	"ALL",
 	"CASE",
 	"WHEN",
@@ -252,20 +296,30 @@ var yyToknames = [...]string{
 	"EXISTS",
 	"IN",
 	"IS",
+	"OVER",
+	"PARTITION",
+	"RECURSIVE",
+	"WITHIN",
+	"EXPLAIN",
+	"ANALYZE",
 	"AUTO_INCREMENT",
 	"NULL",
 	"CAST",
 	"SCAST",
+	"ARRAY",
+	"ANY",
 	"SHOW",
 	"DATABASES",
 	"TABLES",
 	"USERS",
+	"VIEWS",
 	"NPARAM",
 	"PPARAM",
 	"JOINTYPE",
 	"AND",
 	"OR",
 	"CMPOP",
+	"ARRAY_OP",
 	"NOT_MATCHES_OP",
 	"IDENTIFIER",
 	"TYPE",
@@ -302,300 +356,425 @@ var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 97,
-	78, 199,
-	81, 199,
-	-2, 180,
-	-1, 277,
-	59, 152,
-	-2, 147,
-	-1, 331,
-	59, 152,
-	-2, 149,
+	-1, 123,
+	90, 278,
+	93, 278,
+	-2, 253,
+	-1, 305,
+	90, 278,
+	93, 278,
+	-2, 253,
+	-1, 319,
+	122, 66,
+	133, 66,
+	-2, 63,
+	-1, 347,
+	68, 222,
+	-2, 217,
+	-1, 428,
+	68, 222,
+	-2, 219,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 593
+const yyLast = 920
 
 var yyAct = [...]int16{
-	131, 435, 324, 107, 271, 153, 203, 336, 200, 209,
-	244, 115, 353, 330, 335, 249, 245, 319, 6, 310,
-	147, 144, 71, 250, 22, 408, 358, 269, 357, 269,
-	301, 130, 374, 409, 269, 269, 404, 303, 395, 384,
-	106, 375, 129, 360, 314, 99, 302, 269, 101, 402,
-	401, 387, 118, 114, 214, 21, 270, 383, 381, 116,
-	117, 354, 96, 343, 341, 340, 119, 338, 109, 110,
-	111, 112, 113, 108, 300, 298, 297, 291, 268, 100,
-	355, 337, 180, 106, 309, 105, 290, 179, 99, 285,
-	284, 101, 179, 283, 162, 118, 114, 282, 256, 189,
-	169, 170, 116, 117, 162, 182, 172, 174, 149, 119,
-	162, 109, 110, 111, 112, 113, 108, 132, 161, 212,
-	213, 215, 100, 178, 157, 156, 158, 177, 105, 217,
-	171, 188, 154, 155, 157, 156, 158, 143, 154, 155,
-	157, 156, 158, 142, 434, 24, 205, 428, 211, 145,
-	374, 242, 202, 218, 301, 219, 220, 221, 222, 223,
-	224, 225, 226, 216, 162, 240, 269, 232, 206, 152,
-	186, 187, 83, 238, 436, 437, 159, 160, 161, 243,
-	246, 241, 176, 180, 134, 296, 265, 258, 239, 162,
-	234, 393, 154, 155, 157, 156, 158, 207, 259, 392,
-	235, 159, 160, 161, 350, 164, 76, 305, 233, 276,
-	242, 255, 252, 274, 254, 260, 277, 154, 155, 157,
-	156, 158, 286, 201, 287, 32, 168, 164, 275, 162,
-	280, 289, 33, 278, 163, 167, 378, 364, 295, 363,
-	162, 159, 160, 161, 362, 407, 166, 333, 342, 322,
-	307, 148, 159, 160, 161, 306, 163, 154, 155, 157,
-	156, 158, 264, 263, 262, 308, 261, 253, 154, 155,
-	157, 156, 158, 326, 257, 247, 229, 253, 77, 328,
-	198, 197, 190, 183, 334, 316, 150, 321, 323, 321,
-	133, 246, 162, 122, 347, 348, 120, 91, 54, 80,
-	79, 78, 351, 75, 159, 70, 161, 345, 22, 344,
-	69, 208, 288, 406, 39, 162, 31, 320, 361, 352,
-	154, 155, 157, 156, 158, 22, 368, 391, 181, 230,
-	49, 370, 231, 385, 390, 367, 65, 58, 246, 21,
-	369, 162, 121, 281, 377, 372, 379, 380, 376, 382,
-	386, 371, 60, 159, 160, 161, 21, 349, 90, 228,
-	394, 346, 237, 55, 388, 162, 227, 420, 325, 154,
-	155, 157, 156, 158, 272, 427, 279, 159, 160, 161,
-	413, 398, 400, 399, 22, 216, 403, 145, 293, 412,
-	294, 64, 373, 154, 155, 157, 156, 158, 140, 151,
-	52, 62, 56, 57, 59, 418, 414, 410, 415, 396,
-	88, 51, 50, 421, 25, 21, 82, 423, 106, 66,
-	67, 359, 92, 99, 426, 429, 101, 425, 432, 430,
-	118, 114, 433, 304, 417, 438, 191, 116, 117, 315,
-	439, 194, 195, 267, 119, 137, 109, 110, 111, 112,
-	113, 108, 192, 193, 106, 266, 424, 100, 94, 99,
-	124, 210, 101, 105, 299, 366, 118, 114, 135, 136,
-	327, 184, 123, 116, 117, 84, 81, 162, 43, 47,
-	119, 53, 109, 110, 111, 112, 113, 108, 273, 159,
-	160, 161, 68, 100, 10, 12, 11, 2, 339, 105,
-	38, 48, 138, 36, 318, 154, 155, 157, 156, 158,
-	73, 74, 85, 86, 87, 37, 196, 13, 34, 44,
-	35, 185, 63, 46, 45, 125, 14, 15, 128, 127,
-	42, 7, 317, 8, 9, 16, 17, 26, 30, 18,
-	19, 141, 311, 312, 313, 40, 22, 139, 204, 23,
-	236, 41, 365, 27, 29, 28, 146, 416, 165, 389,
-	405, 419, 431, 356, 95, 93, 102, 397, 98, 292,
-	97, 411, 173, 248, 251, 332, 331, 21, 329, 126,
-	72, 89, 61, 175, 103, 104, 422, 199, 20, 5,
-	4, 3, 1,
+	301, 571, 537, 593, 420, 339, 461, 191, 134, 248,
+	381, 433, 144, 257, 323, 129, 245, 130, 299, 427,
+	300, 432, 284, 214, 324, 6, 415, 308, 176, 400,
+	89, 204, 309, 179, 123, 113, 185, 446, 629, 586,
+	516, 379, 365, 337, 364, 160, 133, 611, 559, 374,
+	337, 125, 624, 22, 127, 219, 263, 445, 618, 599,
+	405, 337, 337, 405, 147, 141, 203, 143, 276, 580,
+	578, 573, 547, 201, 145, 146, 647, 646, 122, 617,
+	290, 201, 148, 142, 136, 137, 138, 139, 140, 135,
+	548, 613, 162, 162, 197, 126, 199, 200, 612, 518,
+	117, 131, 197, 198, 199, 200, 587, 202, 517, 27,
+	569, 192, 193, 195, 194, 196, 380, 564, 384, 192,
+	193, 195, 194, 196, 558, 515, 208, 209, 133, 557,
+	503, 405, 211, 125, 213, 528, 127, 527, 163, 504,
+	480, 260, 261, 264, 262, 161, 147, 141, 523, 143,
+	514, 23, 456, 266, 405, 325, 145, 146, 511, 162,
+	162, 455, 232, 404, 148, 142, 136, 137, 138, 139,
+	140, 135, 259, 387, 326, 538, 374, 126, 250, 337,
+	222, 497, 386, 131, 627, 373, 441, 439, 346, 225,
+	221, 267, 247, 268, 269, 270, 271, 272, 273, 274,
+	275, 277, 265, 256, 230, 231, 283, 251, 438, 133,
+	337, 437, 494, 469, 125, 435, 383, 127, 298, 338,
+	254, 377, 376, 372, 366, 293, 336, 147, 141, 222,
+	143, 495, 470, 636, 577, 570, 286, 145, 146, 221,
+	303, 566, 378, 565, 434, 148, 142, 136, 137, 138,
+	139, 140, 135, 522, 344, 521, 305, 201, 126, 476,
+	399, 342, 362, 359, 131, 221, 201, 347, 327, 356,
+	355, 354, 353, 357, 352, 358, 345, 320, 197, 198,
+	199, 200, 361, 133, 302, 350, 343, 233, 125, 348,
+	370, 127, 186, 224, 217, 192, 193, 195, 194, 196,
+	216, 147, 141, 375, 143, 201, 195, 194, 196, 210,
+	175, 145, 146, 201, 174, 391, 29, 177, 27, 148,
+	142, 136, 137, 138, 139, 140, 135, 592, 199, 200,
+	215, 478, 126, 120, 380, 398, 390, 385, 131, 503,
+	290, 422, 479, 192, 193, 195, 194, 196, 424, 406,
+	222, 192, 193, 195, 194, 196, 431, 337, 190, 103,
+	442, 165, 417, 412, 417, 419, 524, 454, 133, 449,
+	450, 425, 371, 125, 333, 322, 127, 252, 291, 218,
+	560, 460, 546, 444, 184, 464, 147, 141, 443, 143,
+	447, 315, 312, 203, 314, 39, 145, 146, 285, 644,
+	316, 459, 40, 96, 148, 142, 136, 137, 138, 139,
+	140, 135, 294, 297, 481, 466, 625, 126, 201, 246,
+	477, 207, 493, 131, 507, 489, 488, 484, 467, 496,
+	482, 483, 485, 440, 202, 418, 206, 407, 492, 197,
+	198, 199, 200, 506, 411, 508, 509, 510, 499, 512,
+	201, 205, 520, 505, 392, 498, 192, 193, 195, 194,
+	196, 180, 332, 331, 648, 330, 297, 329, 296, 295,
+	328, 197, 198, 199, 200, 313, 544, 321, 536, 313,
+	596, 533, 319, 534, 530, 306, 280, 539, 192, 193,
+	195, 194, 196, 545, 114, 243, 531, 97, 133, 410,
+	242, 234, 227, 125, 187, 164, 127, 153, 38, 152,
+	552, 151, 149, 265, 555, 556, 147, 141, 115, 143,
+	561, 63, 201, 100, 99, 98, 145, 146, 95, 94,
+	568, 93, 562, 563, 304, 142, 136, 137, 138, 139,
+	140, 135, 576, 197, 198, 199, 200, 126, 430, 88,
+	294, 87, 253, 131, 550, 572, 21, 549, 26, 409,
+	192, 193, 195, 194, 196, 584, 585, 71, 513, 588,
+	589, 542, 473, 360, 279, 201, 601, 75, 458, 597,
+	457, 453, 73, 607, 25, 472, 67, 278, 606, 382,
+	452, 48, 609, 616, 603, 220, 197, 198, 199, 200,
+	281, 201, 281, 465, 297, 282, 296, 295, 58, 223,
+	81, 628, 626, 192, 193, 195, 194, 196, 292, 487,
+	632, 287, 22, 630, 631, 448, 594, 595, 638, 486,
+	150, 639, 289, 110, 641, 642, 640, 643, 147, 141,
+	645, 143, 519, 201, 76, 623, 22, 649, 145, 146,
+	201, 652, 68, 69, 72, 70, 148, 142, 136, 137,
+	138, 139, 140, 535, 197, 198, 199, 200, 451, 183,
+	416, 197, 198, 199, 200, 362, 201, 188, 27, 594,
+	595, 192, 193, 195, 194, 196, 201, 22, 192, 193,
+	195, 194, 196, 351, 181, 172, 182, 197, 198, 199,
+	200, 368, 27, 369, 80, 317, 255, 197, 198, 199,
+	200, 64, 462, 65, 192, 193, 195, 194, 196, 591,
+	23, 590, 109, 421, 192, 193, 195, 194, 196, 11,
+	13, 12, 52, 56, 22, 349, 340, 82, 83, 84,
+	85, 396, 615, 27, 23, 614, 583, 529, 463, 554,
+	582, 526, 14, 525, 177, 57, 502, 500, 189, 61,
+	78, 15, 16, 27, 605, 579, 551, 108, 258, 619,
+	620, 60, 59, 30, 602, 8, 651, 9, 10, 17,
+	18, 575, 53, 19, 20, 23, 55, 54, 111, 62,
+	27, 155, 541, 51, 634, 633, 31, 37, 637, 118,
+	45, 604, 475, 388, 102, 397, 635, 116, 474, 543,
+	393, 49, 32, 36, 35, 41, 389, 44, 622, 238,
+	239, 236, 237, 168, 24, 240, 235, 408, 105, 106,
+	107, 335, 23, 33, 34, 334, 42, 43, 2, 598,
+	610, 491, 423, 228, 341, 169, 166, 167, 154, 104,
+	101, 86, 47, 436, 159, 158, 91, 92, 401, 402,
+	403, 241, 229, 170, 156, 414, 413, 46, 79, 173,
+	171, 249, 650, 395, 394, 28, 288, 50, 490, 178,
+	112, 501, 66, 621, 471, 600, 567, 363, 121, 119,
+	532, 574, 540, 128, 553, 124, 132, 367, 581, 212,
+	307, 311, 310, 429, 428, 426, 157, 90, 74, 77,
+	226, 318, 608, 244, 468, 7, 5, 4, 3, 1,
 }
 
 var yyPact = [...]int16{
-	490, -1000, -1000, 29, -1000, -1000, -1000, 372, -1000, -1000,
-	530, 218, 495, 492, 474, 474, 365, 364, 342, 200,
-	293, 314, 344, -1000, 490, -1000, 257, 257, 257, 467,
-	212, -1000, 207, 494, 205, 180, 203, 202, 201, 450,
-	376, 63, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 449,
-	200, 200, 200, 359, -1000, 287, -1000, -1000, 199, -1000,
-	383, 346, -1000, -1000, 198, 265, 195, 446, 257, 516,
-	-1000, -1000, 510, 11, 11, -1000, 192, 77, -1000, 440,
-	493, 540, -1000, 474, 534, 26, 20, 326, 153, 252,
-	-1000, -1000, 188, 341, -1000, 60, 158, 149, -1000, 382,
-	382, 13, -1000, -1000, -1000, 382, 382, 74, 10, -1000,
-	-1000, -1000, -1000, -1000, 6, -1000, -1000, -1000, -1000, -25,
-	-1000, 248, -12, 185, 445, 511, -1000, 11, 11, -1000,
-	382, 147, -1000, -18, 184, 405, 422, 410, 506, 183,
-	-1000, 182, 125, 125, 542, 382, 88, -1000, 215, -1000,
-	-1000, 31, 382, -1000, 382, 382, 382, 382, 382, 382,
-	382, 382, 282, -1000, 178, 251, 382, 109, -1000, 22,
-	12, 252, 82, 289, 147, 65, 86, 53, 382, 382,
-	177, -1000, 179, -19, 176, 85, -1000, -1000, 147, 125,
-	-1000, 169, 168, 166, 165, 164, 84, 425, 413, -40,
-	57, -1000, -62, 310, 463, 147, 542, 153, 382, 542,
-	494, 328, -20, -24, -27, -28, 136, -30, 158, 12,
-	12, 233, 233, 233, 22, 210, 28, -1000, 228, -1000,
-	382, -31, 22, -1000, -41, -1000, 315, 382, 83, -1000,
-	-42, -43, 76, 395, -44, 45, 147, -1000, -72, -1000,
-	-1000, -1000, 399, 108, 382, 152, 125, -33, 531, -74,
-	-1000, -1000, 409, -1000, -1000, 531, 524, 496, 269, 151,
-	269, 303, 382, 444, 310, -1000, 147, 154, 136, -36,
-	-51, 477, -53, -54, 150, -55, -1000, -1000, -1000, 22,
-	-32, -1000, 285, 382, 382, 283, -1000, -1000, -1000, 105,
-	-1000, 382, -1000, 179, -37, -91, 147, 386, -75, 125,
-	-1000, -1000, -1000, -1000, -1000, 146, -1000, 141, 139, 439,
-	-36, -1000, -1000, -1000, -1000, 382, 147, -37, 303, 326,
-	-1000, 154, 333, -1000, -1000, -77, -1000, 382, 136, 138,
-	136, 136, -60, 136, -61, -79, -1000, 259, 147, 382,
-	-67, 147, -1000, -1000, -1000, 125, 250, 99, 91, 382,
-	-1000, -80, -1000, -1000, -1000, -1000, 357, 41, 147, -1000,
-	-1000, 319, -1000, 31, -36, -1000, -68, -1000, -69, -1000,
-	-1000, -1000, -1000, -1000, -1000, 382, 147, -1000, -82, 230,
-	-1000, 161, -95, -85, 147, -1000, 354, 329, 317, 542,
-	-1000, -1000, 136, 147, -1000, 401, -1000, -1000, -1000, -1000,
-	351, 301, 382, 112, 430, -1000, -1000, 393, -1000, 310,
-	312, 147, 38, -1000, 382, -1000, 303, 382, 112, 147,
-	-1000, 35, 107, -1000, 382, -1000, -1000, -1000, 107, -1000,
+	725, -1000, -1000, 178, -1000, -1000, -1000, -1000, 722, -1000,
+	-1000, 789, 388, 792, 844, 728, 728, 716, 715, 692,
+	401, 631, 489, 544, 477, 563, -1000, 694, -1000, 725,
+	-1000, 519, 519, 519, 519, 519, 826, 431, -1000, 429,
+	840, 411, 409, 408, 377, 405, 404, 403, 824, 764,
+	228, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 823, 401,
+	401, 401, 707, -1000, 550, 550, 374, -1000, -1000, -1000,
+	-1000, 398, -1000, 768, 637, -1000, 550, 199, -1000, -1000,
+	392, 541, 391, 389, 387, 822, 519, 855, -1000, -1000,
+	836, 125, 125, -1000, -1000, -1000, 385, 232, -1000, 818,
+	854, 863, -1000, 728, 862, 175, 171, 683, 341, 698,
+	-1000, 698, 253, -1000, 153, -1000, 384, -1000, 698, 691,
+	-1000, 227, -13, 332, -1000, 284, 284, 170, -1000, -1000,
+	-1000, 284, -1000, 284, 200, 161, -1000, -1000, -1000, -1000,
+	-1000, 155, 255, -86, 500, -1000, -1000, -1000, 100, -1000,
+	517, 154, 153, -1000, 382, 817, 852, -1000, 125, 125,
+	-1000, 284, 592, -1000, 148, 381, 795, 791, 788, 794,
+	851, 380, -1000, 375, 299, 299, 865, 284, 246, -1000,
+	435, 563, 563, 631, 374, 627, 299, -1000, -1000, 33,
+	284, -1000, 284, 284, 284, 284, 284, 284, 284, -38,
+	284, 485, -1000, 366, 512, 284, 277, -1000, 211, 172,
+	637, 481, 547, 592, 210, 254, 484, 284, -1000, 284,
+	145, 414, 365, -1000, 359, 626, 362, 138, 357, 251,
+	-1000, -1000, 592, 35, -1000, 355, 350, 347, 345, 343,
+	342, 250, 805, 801, 86, 226, -1000, 79, 662, 819,
+	592, 865, 341, 284, -1000, 137, 48, 865, 840, 678,
+	135, 133, 132, 131, 130, 314, 126, -13, 172, 172,
+	507, 507, 507, 211, -21, 219, 124, 219, -1000, 471,
+	-1000, 284, 123, 211, -1000, -97, 84, -1000, 616, 284,
+	248, -1000, 83, 45, 293, 82, 81, 221, 163, -101,
+	203, 592, 493, 76, 51, 332, -1000, 42, -1000, 761,
+	-1000, -1000, 782, 277, 284, 334, 776, -1000, -1000, 732,
+	35, 121, 847, 23, -1000, 200, 317, -1000, -1000, 797,
+	-1000, -1000, 439, 847, 858, 857, 613, 315, 613, 648,
+	284, 816, 662, -1000, 592, 637, -1000, 434, 314, 105,
+	75, 832, 71, 68, 47, 313, 46, -1000, -1000, 284,
+	-1000, 211, 44, -1000, -85, 268, -1000, 537, 284, 284,
+	582, -1000, 495, 486, 243, 21, 482, 480, 277, -1000,
+	284, 636, 675, -1000, 284, 510, -1000, 359, 308, 93,
+	483, 592, 767, 120, 637, 209, -1000, -1000, 0, 35,
+	-1000, -1000, -1000, -1000, -1000, 35, 210, 200, 307, 277,
+	540, 530, -1000, 306, 305, 815, 105, -1000, -1000, -1000,
+	-1000, 284, 592, 92, 648, 41, 683, -1000, 434, 689,
+	687, -1000, -1, -1000, 284, 314, 304, 314, 314, 314,
+	18, 314, 428, 10, -15, -1000, -102, -32, -1000, 556,
+	592, 284, 116, 114, 8, -1000, 242, 681, 679, -3,
+	592, -5, 674, 284, 356, 536, -1000, 36, -1000, -1000,
+	299, 746, -1000, 469, 775, 284, 299, -1000, -1000, 260,
+	-1000, -68, -1000, -50, -1000, -1000, 455, 452, -1000, -1000,
+	-1000, 705, 208, 592, -1000, 35, -1000, -1000, 677, -1000,
+	33, -1000, -1000, 105, -1000, -11, -1000, -16, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -93, 258, 284,
+	592, 493, 493, -1000, -23, 104, 102, -1000, -1000, 284,
+	203, -1000, -30, -1000, -1000, 96, -1000, -1000, 299, -69,
+	734, 284, -1000, 95, 592, -70, -1000, -1000, -1000, -1000,
+	-1000, 703, -71, 680, 673, 865, -1000, -1000, 314, -103,
+	-34, 592, 636, 636, -1000, 645, 643, 196, 549, -1000,
+	346, 813, -81, -1000, 475, 726, 592, 299, 759, 701,
+	-1000, 636, 284, 293, 814, -1000, -1000, -94, -42, -49,
+	672, 669, 284, -1000, -1000, -1000, -61, -82, 711, -1000,
+	785, -1000, 566, -88, 296, -1000, 662, 592, 53, -1000,
+	284, -104, -1000, -1000, 293, 293, 549, -1000, -1000, 752,
+	751, -1000, 772, 94, 756, 36, 648, 293, 592, -1000,
+	602, 602, -1000, -1000, -1000, -1000, 284, 279, -1000, -1000,
+	-1000, -63, -64, 324, 36, 813, -1000, -1000, 727, -1000,
+	-1000, -1000, 813,
 }
 
 var yyPgo = [...]int16{
-	0, 592, 497, 591, 590, 589, 18, 588, 23, 8,
-	12, 587, 586, 14, 7, 16, 10, 585, 11, 584,
-	583, 3, 582, 581, 9, 17, 461, 22, 580, 579,
-	42, 578, 13, 576, 575, 574, 15, 573, 0, 572,
-	21, 571, 570, 569, 568, 567, 4, 2, 566, 565,
-	564, 563, 5, 562, 561, 1, 6, 391, 560, 559,
-	558, 557, 20, 556, 552, 19, 551, 314, 550, 549,
+	0, 919, 838, 918, 917, 916, 25, 915, 558, 556,
+	584, 32, 16, 914, 913, 912, 21, 11, 20, 18,
+	10, 17, 12, 15, 23, 24, 14, 9, 911, 910,
+	8, 909, 722, 908, 13, 26, 768, 30, 907, 906,
+	45, 905, 19, 904, 903, 902, 901, 2, 27, 900,
+	0, 899, 28, 898, 34, 897, 896, 895, 894, 5,
+	4, 893, 892, 891, 890, 889, 888, 887, 22, 7,
+	886, 6, 3, 704, 885, 884, 31, 883, 882, 881,
+	35, 880, 36, 33, 879, 878, 29, 877, 591, 876,
+	875, 874, 873, 1, 872,
 }
 
 var yyR1 = [...]int8{
-	0, 1, 2, 2, 69, 69, 3, 3, 3, 4,
+	0, 1, 2, 2, 90, 90, 3, 3, 3, 3,
+	7, 33, 33, 4, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 91, 4, 4, 4, 4, 4, 4,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 67, 67, 67, 66, 66, 66, 66,
-	66, 66, 66, 65, 65, 65, 65, 57, 57, 10,
-	10, 5, 5, 5, 5, 25, 25, 64, 64, 63,
-	63, 62, 11, 11, 13, 13, 14, 9, 9, 12,
-	12, 16, 16, 15, 15, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 18, 37, 37, 36, 36,
-	36, 8, 61, 61, 51, 51, 51, 58, 58, 59,
-	59, 59, 6, 6, 6, 6, 6, 6, 6, 6,
-	7, 7, 23, 23, 22, 22, 49, 49, 50, 50,
-	19, 19, 19, 19, 20, 20, 21, 21, 24, 24,
-	24, 24, 24, 24, 24, 24, 24, 26, 27, 28,
-	28, 28, 29, 29, 29, 30, 30, 31, 31, 32,
-	32, 33, 34, 34, 40, 40, 45, 45, 41, 41,
-	46, 46, 47, 47, 54, 54, 56, 56, 53, 53,
-	55, 55, 55, 52, 52, 52, 35, 35, 39, 39,
-	38, 38, 38, 38, 38, 38, 38, 38, 38, 38,
-	48, 68, 68, 43, 43, 42, 42, 42, 42, 60,
-	60, 44, 44, 44, 44, 44, 44, 44, 44, 44,
-	44,
+	4, 4, 4, 4, 4, 88, 88, 88, 87, 87,
+	87, 87, 87, 87, 87, 86, 86, 86, 86, 73,
+	73, 29, 29, 28, 28, 28, 92, 92, 92, 13,
+	13, 5, 5, 5, 5, 35, 35, 85, 85, 84,
+	84, 83, 14, 14, 16, 16, 17, 26, 26, 25,
+	25, 25, 12, 12, 15, 15, 19, 19, 18, 18,
+	21, 21, 21, 21, 21, 21, 21, 21, 21, 21,
+	21, 21, 22, 22, 22, 64, 64, 49, 49, 48,
+	48, 48, 48, 48, 46, 46, 47, 47, 93, 93,
+	93, 11, 62, 62, 63, 63, 94, 94, 77, 77,
+	68, 68, 68, 68, 67, 67, 67, 67, 74, 74,
+	75, 75, 75, 6, 6, 6, 6, 6, 6, 6,
+	6, 6, 9, 9, 9, 10, 10, 78, 78, 81,
+	81, 80, 82, 82, 8, 8, 32, 32, 31, 31,
+	65, 65, 66, 66, 23, 23, 23, 23, 23, 23,
+	23, 23, 23, 24, 24, 30, 30, 34, 34, 34,
+	34, 34, 34, 34, 34, 34, 34, 36, 37, 38,
+	38, 38, 39, 39, 39, 40, 40, 41, 41, 42,
+	42, 43, 44, 44, 79, 79, 52, 52, 58, 58,
+	53, 53, 59, 59, 60, 60, 71, 71, 27, 27,
+	27, 70, 70, 72, 72, 72, 69, 69, 69, 45,
+	45, 51, 51, 50, 50, 50, 50, 50, 50, 50,
+	50, 50, 50, 61, 89, 89, 55, 55, 54, 54,
+	54, 54, 54, 56, 56, 56, 20, 20, 76, 76,
+	57, 57, 57, 57, 57, 57, 57, 57, 57, 57,
+	57, 57,
 }
 
 var yyR2 = [...]int8{
-	0, 1, 2, 3, 0, 1, 1, 1, 1, 2,
-	1, 1, 1, 4, 2, 3, 3, 7, 3, 8,
-	9, 7, 5, 6, 6, 8, 6, 6, 7, 7,
-	3, 8, 8, 2, 1, 3, 1, 1, 1, 1,
-	1, 1, 1, 0, 1, 1, 1, 0, 3, 1,
+	0, 1, 2, 3, 0, 1, 1, 1, 1, 1,
+	3, 0, 1, 2, 1, 1, 1, 4, 2, 3,
+	3, 7, 3, 0, 8, 3, 5, 3, 8, 9,
+	7, 5, 6, 6, 8, 6, 6, 8, 9, 9,
+	7, 7, 3, 8, 8, 2, 1, 3, 1, 1,
+	1, 1, 1, 1, 1, 0, 1, 1, 1, 0,
+	3, 0, 2, 1, 3, 4, 0, 1, 1, 1,
 	3, 8, 7, 7, 8, 2, 1, 0, 4, 1,
 	3, 3, 0, 1, 1, 3, 3, 1, 3, 1,
-	3, 0, 1, 1, 3, 1, 1, 1, 1, 1,
-	6, 1, 1, 1, 1, 4, 1, 3, 1, 1,
-	3, 6, 0, 2, 0, 3, 3, 0, 1, 0,
-	1, 2, 1, 4, 2, 2, 3, 2, 2, 4,
-	13, 3, 0, 1, 0, 1, 1, 1, 2, 4,
-	1, 2, 4, 4, 2, 3, 1, 3, 3, 4,
+	2, 4, 1, 3, 1, 3, 0, 1, 1, 3,
+	1, 1, 1, 1, 1, 6, 2, 4, 1, 1,
+	1, 1, 4, 6, 7, 1, 1, 1, 3, 1,
+	1, 5, 1, 3, 9, 11, 0, 3, 0, 4,
+	4, 7, 0, 2, 0, 7, 0, 1, 0, 2,
+	2, 3, 6, 8, 0, 3, 3, 5, 0, 1,
+	0, 1, 2, 1, 4, 2, 2, 2, 3, 2,
+	2, 4, 1, 4, 4, 1, 4, 0, 1, 1,
+	3, 6, 0, 3, 13, 3, 0, 1, 0, 1,
+	1, 1, 2, 4, 1, 2, 4, 4, 5, 6,
+	7, 12, 12, 2, 3, 1, 3, 3, 4, 4,
 	4, 4, 4, 4, 4, 2, 6, 1, 2, 0,
 	2, 2, 0, 2, 2, 2, 1, 0, 1, 1,
-	2, 6, 0, 1, 0, 2, 0, 3, 0, 2,
-	0, 2, 0, 2, 0, 3, 0, 4, 2, 4,
-	0, 1, 1, 0, 1, 2, 2, 4, 0, 1,
-	1, 1, 2, 2, 4, 3, 4, 6, 6, 1,
-	5, 4, 5, 0, 2, 1, 1, 3, 3, 0,
-	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	4,
+	2, 6, 0, 2, 0, 1, 0, 2, 0, 3,
+	0, 2, 0, 2, 0, 2, 0, 3, 0, 4,
+	6, 2, 4, 0, 1, 1, 0, 1, 2, 2,
+	4, 0, 1, 1, 1, 2, 2, 4, 3, 4,
+	6, 6, 1, 5, 4, 5, 0, 2, 1, 1,
+	3, 3, 1, 6, 9, 9, 0, 3, 0, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 6, 3,
+	3, 4,
 }
 
 var yyChk = [...]int16{
-	-1000, -1, -2, -3, -4, -5, -6, 41, 43, 44,
-	4, 6, 5, 27, 36, 37, 45, 46, 49, 50,
-	-7, 87, 56, -69, 116, 42, 7, 23, 25, 24,
-	8, 98, 7, 14, 23, 25, 8, 23, 8, -67,
-	71, -66, 56, 4, 45, 50, 49, 5, 27, -67,
-	47, 47, 58, -26, 98, 70, 88, 89, 23, 90,
-	38, -22, 57, -2, -57, 79, -57, -57, 25, 98,
-	98, -27, -28, 16, 17, 98, 26, 98, 98, 98,
-	98, 26, 40, 109, 26, -26, -26, -26, 51, -23,
-	71, 98, 39, -49, 112, -50, -38, -42, -44, 77,
-	111, 80, -48, -19, -17, 117, 72, -21, 105, 100,
-	101, 102, 103, 104, 85, -18, 91, 92, 84, 98,
-	98, 77, 98, 26, -57, 9, -29, 19, 18, -30,
-	20, -38, -30, 98, 107, 28, 29, 5, 9, 7,
-	-67, 7, 117, 117, -40, 61, -63, -62, 98, -6,
-	98, 58, 109, -52, 110, 111, 113, 112, 114, 94,
-	95, 96, 82, 98, 69, -60, 97, 86, 77, -38,
-	-38, 117, -38, -39, -38, -20, 108, 117, 117, 117,
-	107, 80, 117, 98, 26, 10, -30, -30, -38, 117,
-	98, 31, 30, 31, 31, 32, 10, 98, 98, -11,
-	-9, 98, -9, -56, 6, -38, -40, 109, 96, -24,
-	-26, 117, 88, 89, 23, 90, -18, 98, -38, -38,
-	-38, -38, -38, -38, -38, -38, -38, 84, 77, 98,
-	78, 81, -38, 99, -6, 118, -68, 73, 108, 102,
-	112, -21, 98, -38, -16, -15, -38, 98, -37, -36,
-	-8, -35, 33, 98, 35, 32, 117, 98, 102, -9,
-	-8, 98, 98, 98, 98, 102, 30, 30, 118, 109,
-	118, -46, 64, 25, -56, -62, -38, -56, -27, 48,
-	-6, 15, 117, 117, 117, 117, -52, -52, 84, -38,
-	117, 118, -43, 73, 75, -38, 102, 118, 118, 69,
-	118, 109, 118, 109, 34, 99, -38, 98, -9, 117,
-	-65, 11, 12, 13, 118, 30, -65, 8, 8, -25,
-	48, -6, 98, -25, -47, 65, -38, 26, -46, -31,
-	-32, -33, -34, 93, -52, -13, -14, 117, 118, 21,
-	118, 118, 98, 118, -6, -15, 76, -38, -38, 74,
-	99, -38, -36, -10, 98, 117, -51, 119, 117, 35,
-	118, -9, 98, 98, 98, -64, 26, -13, -38, -10,
-	-47, -40, -32, 59, 109, 118, -16, -52, 98, -52,
-	-52, 118, -52, 118, 118, 74, -38, 118, -9, -59,
-	84, 77, 100, 100, -38, 118, 52, -45, 62, -24,
-	-14, 118, 118, -38, 118, -58, 83, 84, 120, 118,
-	53, -41, 60, 63, -56, -52, -61, 33, 54, -54,
-	66, -38, -12, -21, 26, 34, -46, 63, 109, -38,
-	-47, -53, -38, -21, 109, -55, 67, 68, -38, -55,
+	-1000, -1, -2, -3, -4, -5, -6, -7, 50, 52,
+	53, 4, 6, 5, 27, 36, 37, 54, 55, 58,
+	59, -9, 9, 107, 99, -10, -8, 65, -90, 138,
+	51, 7, 23, 44, 45, 25, 24, 8, 120, 7,
+	14, 23, 44, 45, 25, 8, 23, 8, -88, 83,
+	-87, 65, 4, 54, 59, 58, 5, 27, -88, 56,
+	56, 67, -36, 120, 80, 82, -78, 97, 108, 109,
+	111, 23, 110, 38, -33, 100, 81, -31, 66, -2,
+	-73, 91, -73, -73, -73, -73, 25, 120, 120, -37,
+	-38, 16, 17, 120, 120, 120, 26, 120, 120, 120,
+	120, 26, 40, 131, 26, -36, -36, -36, 60, -32,
+	83, -32, -81, -80, 120, 120, 39, -6, -32, -65,
+	134, -66, -50, -54, -57, 89, 133, 92, -61, -23,
+	-21, 139, -56, 84, -30, 127, 122, 123, 124, 125,
+	126, 103, 121, 105, -22, 112, 113, 102, 120, 120,
+	89, 120, 120, 120, 26, -73, 9, -39, 19, 18,
+	-40, 20, -50, -40, 120, 129, 28, 29, 5, 27,
+	9, 7, -88, 7, 139, 139, -52, 71, -84, -83,
+	120, -10, -10, -9, 131, -82, 139, 120, -8, 67,
+	131, -69, 132, 133, 135, 134, 136, 115, 116, 117,
+	118, 94, 120, 79, -76, 119, 104, 89, -50, -50,
+	139, -50, -51, -50, -24, 130, 139, 139, 124, 141,
+	95, 139, 129, 92, 139, -82, -29, 120, 26, 10,
+	-40, -40, -50, 139, 120, 31, 30, 31, 31, 32,
+	31, 10, 120, 120, -14, -12, 120, -12, -27, 6,
+	-50, -52, 131, 117, -80, 79, -12, -34, -36, 139,
+	108, 109, 111, 23, 110, -22, 120, -50, -50, -50,
+	-50, -50, -50, -50, -50, -50, 106, -50, 102, 89,
+	120, 90, 93, -50, -68, 121, -6, 140, -89, 85,
+	130, 124, 134, -30, 66, 123, 122, 120, -50, -19,
+	-18, -50, 139, -19, 120, -54, 120, -49, -48, -11,
+	-45, -46, 33, 120, 35, 32, 41, 79, -28, 120,
+	139, 120, 124, -26, -25, 120, 139, -11, 120, 120,
+	120, 120, 120, 124, 30, 30, 140, 131, 140, -59,
+	74, 25, -27, -83, -50, 139, 140, -27, -37, 57,
+	-6, 15, 139, 139, 139, 139, 139, -69, -69, 139,
+	102, -50, 139, -67, 141, 139, 140, -55, 85, 87,
+	-50, 124, 140, 140, 131, -30, 140, 140, 79, 142,
+	131, -20, 96, 140, 67, -76, 140, 131, 42, 34,
+	-68, -50, 120, 34, -91, -92, 9, 73, -26, 139,
+	-86, 11, 12, 13, 140, 131, -24, 120, 30, 120,
+	60, 5, -86, 8, 8, -35, 57, -6, 120, -35,
+	-60, 75, -50, 26, -59, -6, -41, -42, -43, -44,
+	114, -69, -16, -17, 139, 140, 21, 140, 140, 140,
+	120, 140, -50, -6, -18, 142, 122, 122, 88, -50,
+	-50, 86, 95, 95, 124, 140, 131, 98, 98, -68,
+	-50, -71, 76, 73, -50, 93, -48, 120, -13, 120,
+	139, -75, 102, 89, 41, 35, 139, -6, 122, 133,
+	140, -26, -25, -24, 120, -68, 89, 89, 120, 120,
+	-85, 26, -16, -50, 120, 139, -60, 140, -52, -42,
+	68, -79, 69, 131, 140, -19, -69, 120, -69, -69,
+	-69, 140, -69, 140, 140, 140, 142, 140, 131, 86,
+	-50, 139, 139, 140, 124, 72, 72, 140, 140, 73,
+	-18, 140, -64, -23, -21, 127, -22, -47, 139, -12,
+	-62, 46, 102, 34, -50, -12, 122, 140, 140, 102,
+	102, 61, -26, -58, 72, -34, -17, 140, 140, 141,
+	122, -50, -20, -20, 140, 139, 139, -70, -50, 140,
+	139, -93, -12, 140, -63, 47, -50, 139, 140, 62,
+	140, -53, 70, 73, -27, -69, 142, 140, -71, -71,
+	76, 76, 131, -72, 77, 78, 134, -30, 26, 140,
+	-74, 101, 48, -12, 42, 63, -71, -50, -15, -30,
+	26, 141, 140, 140, 73, 73, -50, 140, 140, 58,
+	59, -77, 33, 79, 140, 120, -59, 131, -50, 142,
+	-30, -30, -72, 43, 43, 34, 139, 42, -47, -60,
+	-30, -72, -72, -50, 120, -93, 140, 140, 140, -47,
+	-94, 49, -93,
 }
 
 var yyDef = [...]int16{
-	0, -2, 1, 4, 6, 7, 8, 10, 11, 12,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	102, 0, 114, 2, 5, 9, 47, 47, 47, 0,
-	0, 14, 0, 139, 0, 0, 0, 0, 0, 0,
-	0, 34, 36, 37, 38, 39, 40, 41, 42, 0,
-	0, 0, 0, 0, 137, 112, 104, 105, 0, 107,
-	108, 0, 115, 3, 0, 0, 0, 0, 47, 0,
-	15, 16, 142, 0, 0, 18, 0, 0, 30, 0,
-	0, 0, 33, 0, 0, 0, 0, 154, 0, 0,
-	113, 106, 0, 111, 116, 117, 173, -2, 181, 0,
-	0, 0, 189, 195, 196, 0, 178, 120, 0, 75,
-	76, 77, 78, 79, 0, 81, 82, 83, 84, 126,
-	13, 0, 0, 0, 0, 0, 138, 0, 0, 140,
-	0, 146, 141, 0, 0, 0, 0, 0, 0, 0,
-	35, 0, 62, 0, 166, 0, 154, 59, 0, 103,
-	109, 0, 0, 118, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 174, 0, 0, 0, 0, 200, 182,
-	183, 0, 0, 0, 179, 121, 0, 0, 0, 71,
-	0, 48, 0, 0, 0, 0, 143, 144, 145, 0,
-	22, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	63, 67, 0, 160, 0, 155, 166, 0, 0, 166,
-	139, 0, 0, 0, 0, 0, 173, 137, 173, 201,
-	202, 203, 204, 205, 206, 207, 208, 209, 0, 175,
-	0, 0, 185, 198, 0, 197, 193, 0, 0, 124,
-	0, 0, 126, 0, 0, 72, 73, 127, 0, 86,
-	88, 89, 0, 0, 0, 0, 0, 0, 43, 0,
-	23, 24, 0, 26, 27, 43, 0, 0, 0, 0,
-	0, 162, 0, 0, 160, 60, 61, -2, 173, 0,
-	0, 0, 0, 0, 0, 0, 135, 119, 210, 184,
-	0, 186, 0, 0, 0, 0, 125, 122, 123, 0,
-	85, 0, 17, 0, 0, 94, 176, 0, 0, 0,
-	28, 44, 45, 46, 21, 0, 29, 0, 0, 57,
-	0, 56, 68, 52, 53, 0, 161, 0, 162, 154,
-	148, -2, 0, 153, 128, 0, 64, 71, 173, 0,
-	173, 173, 0, 173, 0, 0, 190, 0, 194, 0,
-	0, 74, 87, 90, 49, 0, 99, 0, 0, 0,
-	19, 0, 25, 31, 32, 51, 0, 55, 163, 167,
-	54, 156, 150, 0, 0, 129, 0, 130, 0, 131,
-	132, 133, 134, 187, 188, 0, 191, 80, 0, 97,
-	100, 0, 0, 0, 177, 20, 0, 158, 0, 166,
-	65, 66, 173, 192, 50, 92, 98, 101, 95, 96,
-	0, 164, 0, 0, 0, 136, 91, 0, 58, 160,
-	0, 159, 157, 69, 0, 93, 162, 0, 0, 151,
-	110, 165, 170, 70, 0, 168, 171, 172, 170, 169,
+	0, -2, 1, 4, 6, 7, 8, 9, 14, 15,
+	16, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 153, 167, 0, 11, 162, 165, 178, 2, 5,
+	13, 59, 59, 59, 59, 59, 0, 0, 18, 0,
+	209, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	46, 48, 49, 50, 51, 52, 53, 54, 0, 0,
+	0, 0, 0, 207, 176, 176, 0, 168, 155, 156,
+	157, 0, 159, 160, 0, 12, 176, 0, 179, 3,
+	0, 0, 0, 0, 0, 0, 59, 0, 19, 20,
+	212, 0, 0, 22, 25, 27, 0, 0, 42, 0,
+	0, 0, 45, 0, 0, 0, 0, 226, 0, 0,
+	177, 0, 0, 169, 172, 158, 0, 10, 0, 175,
+	180, 181, 246, -2, 254, 0, 0, 0, 262, 268,
+	269, 0, 272, 251, 184, 0, 100, 101, 102, 103,
+	104, 0, 0, 0, 108, 109, 110, 111, 195, 17,
+	0, 0, 172, 61, 0, 0, 0, 208, 0, 0,
+	210, 0, 216, 211, 0, 0, 0, 0, 0, 0,
+	0, 0, 47, 0, 82, 0, 238, 0, 226, 79,
+	0, 163, 164, 154, 0, 0, 0, 161, 166, 0,
+	0, 182, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 247, 0, 0, 0, 0, 279, 255, 256,
+	0, 0, 0, 252, 185, 0, 0, 0, 106, 96,
+	0, 96, 0, 60, 0, 0, 26, 0, 0, 0,
+	213, 214, 215, 0, 31, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 83, 92, 0, 232, 0,
+	227, 238, 0, 0, 170, 0, 0, 238, 209, 0,
+	0, 0, 0, 0, 0, 246, 207, 246, 280, 281,
+	282, 283, 284, 285, 286, 287, 0, 289, 290, 0,
+	248, 0, 0, 258, 271, 144, 0, 270, 266, 0,
+	0, 193, 0, 0, 0, 0, 0, 195, 0, 0,
+	97, 98, 276, 0, 195, -2, 196, 0, 117, 119,
+	120, 122, 0, 0, 0, 0, 0, 23, 62, -2,
+	0, 0, 55, 0, 87, 89, 0, 32, 33, 0,
+	35, 36, 0, 55, 0, 0, 0, 0, 0, 234,
+	0, 0, 232, 80, 81, 0, 173, -2, 246, 0,
+	0, 0, 0, 0, 0, 0, 0, 205, 183, 0,
+	291, 257, 0, 140, 0, 0, 259, 0, 0, 0,
+	0, 194, 186, 187, 0, 0, 0, 0, 0, 107,
+	0, 236, 0, 112, 0, 0, 21, 0, 0, 0,
+	150, 249, 0, 0, 0, 0, 67, 68, 0, 0,
+	40, 56, 57, 58, 30, 0, 90, 0, 0, 0,
+	0, 0, 41, 0, 0, 77, 0, 76, 93, 72,
+	73, 0, 233, 0, 234, 0, 226, 218, -2, 0,
+	224, 197, 0, 84, 96, 246, 0, 246, 246, 246,
+	0, 246, 0, 0, 0, 141, 0, 0, 263, 0,
+	267, 0, 0, 0, 0, 188, 0, 0, 0, 0,
+	99, 0, 0, 0, 0, 0, 118, 126, 123, 69,
+	0, 132, 151, 0, 0, 0, 0, 24, 64, 0,
+	28, 0, 88, 0, 34, 37, 0, 0, 43, 44,
+	71, 0, 75, 235, 239, 0, 74, 171, 228, 220,
+	0, 223, 225, 0, 198, 0, 199, 0, 200, 201,
+	202, 203, 204, 288, 260, 261, 145, 146, 0, 0,
+	264, 276, 276, 189, 0, 0, 0, 105, 273, 0,
+	277, 113, 0, 115, 116, 0, 108, 128, 0, 0,
+	134, 0, 152, 0, 250, 0, 65, 29, 91, 38,
+	39, 0, 0, 230, 0, 238, 85, 86, 246, 0,
+	0, 265, 236, 236, 190, 0, 0, 237, 243, 114,
+	0, 121, 0, 70, 148, 0, 133, 0, 0, 0,
+	240, 236, 0, 0, 0, 206, 142, 147, 0, 0,
+	0, 0, 0, 241, 244, 245, 0, 0, 0, 127,
+	138, 149, 0, 0, 0, 78, 232, 231, 229, 94,
+	0, 0, 274, 275, 0, 0, 243, 186, 187, 0,
+	0, 131, 0, 0, 0, 126, 234, 0, 221, 143,
+	243, 243, 242, 129, 130, 139, 0, 0, 128, 174,
+	95, 0, 0, 0, 126, 124, 191, 192, 136, 128,
+	135, 137, 125,
 }
 
-var yyTok1 = [...]int8{
+var yyTok1 = [...]uint8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 114, 3, 3,
-	117, 118, 112, 110, 109, 111, 115, 113, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 136, 3, 3,
+	139, 140, 134, 132, 131, 133, 137, 135, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 119, 3, 120,
+	3, 141, 3, 142,
 }
 
-var yyTok2 = [...]int8{
+var yyTok2 = [...]uint8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -606,7 +785,9 @@ var yyTok2 = [...]int8{
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
-	102, 103, 104, 105, 106, 107, 108, 116,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
+	122, 123, 124, 125, 126, 127, 128, 129, 130, 138,
 }
 
 var yyTok3 = [...]int8{
@@ -968,51 +1149,67 @@ yydefault:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 		}
-	case 9:
+	case 10:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = NewExplainStmt(yyDollar[3].stmt.(DataSource), yyDollar[2].distinct)
+		}
+	case 11:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.distinct = false
+		}
+	case 12:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.distinct = true
+		}
+	case 13:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &BeginTransactionStmt{}
 		}
-	case 10:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = &BeginTransactionStmt{}
 		}
-	case 11:
+	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = &CommitStmt{}
 		}
-	case 12:
+	case 16:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = &RollbackStmt{}
 		}
-	case 13:
+	case 17:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &CreateDatabaseStmt{ifNotExists: yyDollar[3].boolean, DB: yyDollar[4].id}
 		}
-	case 14:
+	case 18:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &UseDatabaseStmt{DB: yyDollar[2].id}
 		}
-	case 15:
+	case 19:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &UseDatabaseStmt{DB: yyDollar[3].id}
 		}
-	case 16:
+	case 20:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &UseSnapshotStmt{period: yyDollar[3].period}
 		}
-	case 17:
+	case 21:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			colsSpecs := make([]*ColSpec, 0, 5)
 			var checks []CheckConstraint
+			var foreignKeys []ForeignKeyConstraint
 
 			var pk PrimaryKeyConstraint
 
@@ -1020,6 +1217,9 @@ yydefault:
 				switch c := e.(type) {
 				case *ColSpec:
 					colsSpecs = append(colsSpecs, c)
+					if c.references != nil {
+						foreignKeys = append(foreignKeys, *c.references)
+					}
 				case PrimaryKeyConstraint:
 					pk = c
 				case CheckConstraint:
@@ -1027,6 +1227,8 @@ yydefault:
 						checks = make([]CheckConstraint, 0, 5)
 					}
 					checks = append(checks, c)
+				case ForeignKeyConstraint:
+					foreignKeys = append(foreignKeys, c)
 				}
 			}
 
@@ -1036,492 +1238,855 @@ yydefault:
 				colsSpec:    colsSpecs,
 				pkColNames:  pk,
 				checks:      checks,
+				foreignKeys: foreignKeys,
 			}
 		}
-	case 18:
+	case 22:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &DropTableStmt{table: yyDollar[3].id}
 		}
-	case 19:
+	case 23:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.integer = uint64(yylex.(*lexer).r.ReadCount())
+		}
+	case 24:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
-			yyVAL.stmt = &CreateIndexStmt{ifNotExists: yyDollar[3].boolean, table: yyDollar[5].id, cols: yyDollar[7].ids}
+			yyVAL.stmt = NewCreateViewStmt(yyDollar[4].id, yyDollar[3].boolean, yyDollar[5].ids, yylex.(*lexer).r.textFrom(int(yyDollar[7].integer)))
 		}
-	case 20:
+	case 25:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = &DropViewStmt{view: yyDollar[3].id}
+		}
+	case 26:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			stmt, err := newCreateSequenceStmt(yyDollar[4].id, yyDollar[3].boolean, yyDollar[5].seqOpts)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+			yyVAL.stmt = stmt
+		}
+	case 27:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = &DropSequenceStmt{sequence: yyDollar[3].id}
+		}
+	case 28:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			cols, jsonFields := splitIndexColSpecs(yyDollar[7].indexCols)
+			yyVAL.stmt = &CreateIndexStmt{ifNotExists: yyDollar[3].boolean, table: yyDollar[5].id, cols: cols, jsonFields: jsonFields}
+		}
+	case 29:
 		yyDollar = yyS[yypt-9 : yypt+1]
 		{
-			yyVAL.stmt = &CreateIndexStmt{unique: true, ifNotExists: yyDollar[4].boolean, table: yyDollar[6].id, cols: yyDollar[8].ids}
+			cols, jsonFields := splitIndexColSpecs(yyDollar[8].indexCols)
+			yyVAL.stmt = &CreateIndexStmt{unique: true, ifNotExists: yyDollar[4].boolean, table: yyDollar[6].id, cols: cols, jsonFields: jsonFields}
 		}
-	case 21:
+	case 30:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
-			yyVAL.stmt = &DropIndexStmt{table: yyDollar[4].id, cols: yyDollar[6].ids}
+			cols, jsonFields := splitIndexColSpecs(yyDollar[6].indexCols)
+			yyVAL.stmt = &DropIndexStmt{table: yyDollar[4].id, cols: cols, jsonFields: jsonFields}
 		}
-	case 22:
+	case 31:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.stmt = &DropIndexStmt{table: yyDollar[3].id, cols: []string{yyDollar[5].id}}
 		}
-	case 23:
+	case 32:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &AddColumnStmt{table: yyDollar[3].id, colSpec: yyDollar[6].colSpec}
 		}
-	case 24:
+	case 33:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &RenameTableStmt{oldName: yyDollar[3].id, newName: yyDollar[6].id}
 		}
-	case 25:
+	case 34:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &RenameColumnStmt{table: yyDollar[3].id, oldName: yyDollar[6].id, newName: yyDollar[8].id}
 		}
-	case 26:
+	case 35:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &DropColumnStmt{table: yyDollar[3].id, colName: yyDollar[6].id}
 		}
-	case 27:
+	case 36:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &DropConstraintStmt{table: yyDollar[3].id, constraintName: yyDollar[6].id}
 		}
-	case 28:
+	case 37:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			// TYPE is not a reserved word so to keep it available as a column name
+			if yyDollar[7].id != "type" {
+				yylex.Error(fmt.Sprintf("syntax error: unexpected %s, expecting TYPE", yyDollar[7].id))
+			}
+
+			yyVAL.stmt = &AlterColumnStmt{table: yyDollar[3].id, colName: yyDollar[6].id, changeType: true, colType: yyDollar[8].typeSpec.t, maxLen: yyDollar[8].typeSpec.maxLen}
+		}
+	case 38:
+		yyDollar = yyS[yypt-9 : yypt+1]
+		{
+			yyVAL.stmt = &AlterColumnStmt{table: yyDollar[3].id, colName: yyDollar[6].id, changeNullability: true, notNull: true}
+		}
+	case 39:
+		yyDollar = yyS[yypt-9 : yypt+1]
+		{
+			yyVAL.stmt = &AlterColumnStmt{table: yyDollar[3].id, colName: yyDollar[6].id, changeNullability: true, notNull: false}
+		}
+	case 40:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &CreateUserStmt{username: yyDollar[3].id, password: yyDollar[6].str, permission: yyDollar[7].permission}
 		}
-	case 29:
+	case 41:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &AlterUserStmt{username: yyDollar[3].id, password: yyDollar[6].str, permission: yyDollar[7].permission}
 		}
-	case 30:
+	case 42:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &DropUserStmt{username: yyDollar[3].id}
 		}
-	case 31:
+	case 43:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &AlterPrivilegesStmt{database: yyDollar[5].id, user: yyDollar[8].id, privileges: yyDollar[2].sqlPrivileges, isGrant: true}
 		}
-	case 32:
+	case 44:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &AlterPrivilegesStmt{database: yyDollar[5].id, user: yyDollar[8].id, privileges: yyDollar[2].sqlPrivileges}
 		}
-	case 33:
+	case 45:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.sqlPrivileges = allPrivileges
 		}
-	case 34:
+	case 46:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivileges = []SQLPrivilege{yyDollar[1].sqlPrivilege}
 		}
-	case 35:
+	case 47:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.sqlPrivileges = append(yyDollar[3].sqlPrivileges, yyDollar[1].sqlPrivilege)
 		}
-	case 36:
+	case 48:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeSelect
 		}
-	case 37:
+	case 49:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeCreate
 		}
-	case 38:
+	case 50:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeInsert
 		}
-	case 39:
+	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeUpdate
 		}
-	case 40:
+	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeDelete
 		}
-	case 41:
+	case 53:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeDrop
 		}
-	case 42:
+	case 54:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeAlter
 		}
-	case 43:
+	case 55:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.permission = PermissionReadWrite
 		}
-	case 44:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.permission = PermissionReadOnly
 		}
-	case 45:
+	case 57:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.permission = PermissionReadWrite
 		}
-	case 46:
+	case 58:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.permission = PermissionAdmin
 		}
-	case 47:
+	case 59:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 48:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 49:
+	case 61:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.seqOpts = nil
+		}
+	case 62:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.seqOpts = append(yyDollar[1].seqOpts, yyDollar[2].seqOpt)
+		}
+	case 63:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.seqOpt = sequenceOption{name: yyDollar[1].id}
+		}
+	case 64:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.seqOpt = sequenceOption{name: yyDollar[1].id, value: int64(yyDollar[3].integer), hasValue: true}
+		}
+	case 65:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.seqOpt = sequenceOption{name: yyDollar[1].id, value: -int64(yyDollar[4].integer), hasValue: true}
+		}
+	case 66:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+		}
+	case 67:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+		}
+	case 68:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+		}
+	case 69:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ids = []string{yyDollar[1].id}
 		}
-	case 50:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ids = yyDollar[2].ids
 		}
-	case 51:
+	case 71:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &UpsertIntoStmt{isInsert: true, tableRef: yyDollar[3].tableRef, cols: yyDollar[5].ids, ds: yyDollar[7].ds, onConflict: yyDollar[8].onConflict}
 		}
-	case 52:
+	case 72:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &UpsertIntoStmt{tableRef: yyDollar[3].tableRef, cols: yyDollar[5].ids, ds: yyDollar[7].ds}
 		}
-	case 53:
+	case 73:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
-			yyVAL.stmt = &DeleteFromStmt{tableRef: yyDollar[3].tableRef, where: yyDollar[4].exp, indexOn: yyDollar[5].ids, limit: yyDollar[6].exp, offset: yyDollar[7].exp}
+			yyVAL.stmt = &DeleteFromStmt{tableRef: yyDollar[3].tableRef, where: yyDollar[4].exp, indexOn: yyDollar[5].indexCols, limit: yyDollar[6].exp, offset: yyDollar[7].exp}
 		}
-	case 54:
+	case 74:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
-			yyVAL.stmt = &UpdateStmt{tableRef: yyDollar[2].tableRef, updates: yyDollar[4].updates, where: yyDollar[5].exp, indexOn: yyDollar[6].ids, limit: yyDollar[7].exp, offset: yyDollar[8].exp}
+			yyVAL.stmt = &UpdateStmt{tableRef: yyDollar[2].tableRef, updates: yyDollar[4].updates, where: yyDollar[5].exp, indexOn: yyDollar[6].indexCols, limit: yyDollar[7].exp, offset: yyDollar[8].exp}
 		}
-	case 55:
+	case 75:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.ds = &valuesDataSource{rows: yyDollar[2].rows}
 		}
-	case 56:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ds = yyDollar[1].stmt.(DataSource)
 		}
-	case 57:
+	case 77:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.onConflict = nil
 		}
-	case 58:
+	case 78:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.onConflict = &OnConflictDo{}
 		}
-	case 59:
+	case 79:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.updates = []*colUpdate{yyDollar[1].update}
 		}
-	case 60:
+	case 80:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.updates = append(yyDollar[1].updates, yyDollar[3].update)
 		}
-	case 61:
+	case 81:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.update = &colUpdate{col: yyDollar[1].id, op: yyDollar[2].cmpOp, val: yyDollar[3].exp}
 		}
-	case 62:
+	case 82:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.ids = nil
 		}
-	case 63:
+	case 83:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ids = yyDollar[1].ids
 		}
-	case 64:
+	case 84:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.rows = []*RowSpec{yyDollar[1].row}
 		}
-	case 65:
+	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.rows = append(yyDollar[1].rows, yyDollar[3].row)
 		}
-	case 66:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.row = &RowSpec{Values: yyDollar[2].values}
 		}
-	case 67:
+	case 87:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.indexCols = []*indexColSpec{yyDollar[1].indexCol}
+		}
+	case 88:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.indexCols = append(yyDollar[1].indexCols, yyDollar[3].indexCol)
+		}
+	case 89:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.indexCol = &indexColSpec{col: yyDollar[1].id}
+		}
+	case 90:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.indexCol = &indexColSpec{col: yyDollar[1].id, jsonFields: yyDollar[2].jsonFields}
+		}
+	case 91:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.indexCol = &indexColSpec{col: yyDollar[2].id, jsonFields: yyDollar[3].jsonFields}
+		}
+	case 92:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ids = []string{yyDollar[1].id}
 		}
-	case 68:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ids = append(yyDollar[1].ids, yyDollar[3].id)
 		}
-	case 69:
+	case 94:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.cols = []*ColSelector{yyDollar[1].col}
 		}
-	case 70:
+	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.cols = append(yyDollar[1].cols, yyDollar[3].col)
 		}
-	case 71:
+	case 96:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.values = nil
 		}
-	case 72:
+	case 97:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.values = yyDollar[1].values
 		}
-	case 73:
+	case 98:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.values = []ValueExp{yyDollar[1].exp}
 		}
-	case 74:
+	case 99:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.values = append(yyDollar[1].values, yyDollar[3].exp)
 		}
-	case 75:
+	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Integer{val: int64(yyDollar[1].integer)}
 		}
-	case 76:
+	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Float64{val: float64(yyDollar[1].float)}
 		}
-	case 77:
+	case 102:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Varchar{val: yyDollar[1].str}
 		}
-	case 78:
+	case 103:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Bool{val: yyDollar[1].boolean}
 		}
-	case 79:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Blob{val: yyDollar[1].blob}
 		}
-	case 80:
+	case 105:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
-			yyVAL.value = &Cast{val: yyDollar[3].exp, t: yyDollar[5].sqlType}
+			yyVAL.value = &Cast{val: yyDollar[3].exp, t: yyDollar[5].typeSpec.t, maxLen: yyDollar[5].typeSpec.maxLen}
 		}
-	case 81:
-		yyDollar = yyS[yypt-1 : yypt+1]
+	case 106:
+		yyDollar = yyS[yypt-2 : yypt+1]
 		{
-			yyVAL.value = yyDollar[1].value
+			yyVAL.value = &Cast{val: &Varchar{val: yyDollar[2].str}, t: yyDollar[1].sqlType}
 		}
-	case 82:
-		yyDollar = yyS[yypt-1 : yypt+1]
+	case 107:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.value = &ArrayExp{elems: yyDollar[3].values}
+		}
+	case 108:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.value = yyDollar[1].value
+		}
+	case 109:
+		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Param{id: yyDollar[1].id}
 		}
-	case 83:
+	case 110:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Param{id: fmt.Sprintf("param%d", yyDollar[1].pparam), pos: yyDollar[1].pparam}
 		}
-	case 84:
+	case 111:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &NullValue{t: AnyType}
 		}
-	case 85:
+	case 112:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.value = &FnCall{fn: yyDollar[1].id, params: yyDollar[3].values}
 		}
-	case 86:
+	case 113:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			// EXTRACT is not a reserved word, the field is passed as the first argument of the function
+			if yyDollar[1].id != "extract" {
+				yylex.Error(fmt.Sprintf("syntax error: unexpected FROM in call to function %s", yyDollar[1].id))
+			}
+
+			yyVAL.value = &FnCall{fn: yyDollar[1].id, params: []ValueExp{&Varchar{val: yyDollar[3].id}, yyDollar[5].exp}}
+		}
+	case 114:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		{
+			// POSITION is not a reserved word, the string is passed as the second argument of the function
+			if yyDollar[1].id != "position" || yyDollar[4].boolean {
+				yylex.Error(fmt.Sprintf("syntax error: unexpected IN in call to function %s", yyDollar[1].id))
+			}
+
+			yyVAL.value = &FnCall{fn: yyDollar[1].id, params: []ValueExp{yyDollar[3].exp, yyDollar[6].exp}}
+		}
+	case 115:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.exp = yyDollar[1].sel
+		}
+	case 116:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.exp = yyDollar[1].value
+		}
+	case 117:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableElems = []TableElem{yyDollar[1].tableElem}
 		}
-	case 87:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableElems = append(yyDollar[1].tableElems, yyDollar[3].tableElem)
 		}
-	case 88:
+	case 119:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableElem = yyDollar[1].colSpec
 		}
-	case 89:
+	case 120:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableElem = yyDollar[1].check
 		}
-	case 90:
+	case 121:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyDollar[1].colSpec.references = &ForeignKeyConstraint{cols: []string{yyDollar[1].colSpec.colName}, refTable: yyDollar[3].id, refCols: yyDollar[4].ids}
+			yyVAL.tableElem = yyDollar[1].colSpec
+		}
+	case 122:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.tableElem = yyDollar[1].foreignKey
+		}
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableElem = PrimaryKeyConstraint(yyDollar[3].ids)
 		}
-	case 91:
-		yyDollar = yyS[yypt-6 : yypt+1]
+	case 124:
+		yyDollar = yyS[yypt-9 : yypt+1]
 		{
-			yyVAL.colSpec = &ColSpec{colName: yyDollar[1].id, colType: yyDollar[2].sqlType, maxLen: int(yyDollar[3].integer), notNull: yyDollar[4].boolean || yyDollar[6].boolean, autoIncrement: yyDollar[5].boolean, primaryKey: yyDollar[6].boolean}
+			yyVAL.foreignKey = ForeignKeyConstraint{cols: yyDollar[4].ids, refTable: yyDollar[7].id, refCols: yyDollar[8].ids}
 		}
-	case 92:
+	case 125:
+		yyDollar = yyS[yypt-11 : yypt+1]
+		{
+			yyVAL.foreignKey = ForeignKeyConstraint{name: yyDollar[2].id, cols: yyDollar[6].ids, refTable: yyDollar[9].id, refCols: yyDollar[10].ids}
+		}
+	case 126:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.ids = nil
+		}
+	case 127:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.ids = yyDollar[2].ids
+		}
+	case 128:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+		}
+	case 129:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+		}
+	case 130:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+		}
+	case 131:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		{
+			yyVAL.colSpec = &ColSpec{
+				colName:       yyDollar[1].id,
+				colType:       yyDollar[2].typeSpec.t,
+				maxLen:        yyDollar[2].typeSpec.maxLen,
+				notNull:       yyDollar[3].boolean || yyDollar[7].boolean,
+				defaultExp:    yyDollar[4].exp,
+				generatedExp:  yyDollar[5].exp,
+				autoIncrement: yyDollar[6].boolean,
+				primaryKey:    yyDollar[7].boolean,
+			}
+		}
+	case 132:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.exp = nil
+		}
+	case 133:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.exp = yyDollar[2].exp
+		}
+	case 134:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.exp = nil
+		}
+	case 135:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		{
+			yyVAL.exp = yyDollar[5].exp
+		}
+	case 136:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+		}
+	case 137:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+		}
+	case 138:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 93:
+	case 139:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 94:
+	case 140:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			spec, err := newTypeSpec(yyDollar[1].sqlType, yyDollar[2].typeArgs, false)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+
+			yyVAL.typeSpec = spec
+		}
+	case 141:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			spec, err := newTypeSpec(yyDollar[1].sqlType, nil, true)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+
+			yyVAL.typeSpec = spec
+		}
+	case 142:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			spec, err := newTypeSpec(yyDollar[1].sqlType, []uint64{yyDollar[3].integer}, true)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+
+			yyVAL.typeSpec = spec
+		}
+	case 143:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			spec, err := newTypeSpec(yyDollar[1].sqlType, []uint64{yyDollar[3].integer, yyDollar[5].integer}, true)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+
+			yyVAL.typeSpec = spec
+		}
+	case 144:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
-			yyVAL.integer = 0
+			yyVAL.typeArgs = nil
 		}
-	case 95:
+	case 145:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.integer = yyDollar[2].integer
+			yyVAL.typeArgs = []uint64{yyDollar[2].integer}
 		}
-	case 96:
+	case 146:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.integer = yyDollar[2].integer
+			yyVAL.typeArgs = []uint64{yyDollar[2].integer}
 		}
-	case 97:
+	case 147:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.typeArgs = []uint64{yyDollar[2].integer, yyDollar[4].integer}
+		}
+	case 148:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 98:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 99:
+	case 150:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 100:
+	case 151:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 101:
+	case 152:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 102:
+	case 153:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
-	case 103:
+	case 154:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.stmt = &UnionStmt{
-				distinct: yyDollar[3].distinct,
-				left:     yyDollar[1].stmt.(DataSource),
-				right:    yyDollar[4].stmt.(DataSource),
+			stmt, err := NewWithStmt(yyDollar[3].ctes, yyDollar[2].boolean, yyDollar[4].stmt.(DataSource))
+			if err != nil {
+				yylex.Error(err.Error())
+				yyVAL.stmt = yyDollar[4].stmt
+			} else {
+				yyVAL.stmt = stmt
 			}
 		}
-	case 104:
+	case 155:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "databases"}},
 			}
 		}
-	case 105:
+	case 156:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "tables"}},
 			}
 		}
-	case 106:
+	case 157:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.stmt = &SelectStmt{
+				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "views"}},
+			}
+		}
+	case 158:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "table", params: []ValueExp{&Varchar{val: yyDollar[3].id}}}},
 			}
 		}
-	case 107:
+	case 159:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "users"}},
 			}
 		}
-	case 108:
+	case 160:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "grants"}},
 			}
 		}
-	case 109:
+	case 161:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "grants", params: []ValueExp{&Varchar{val: yyDollar[4].id}}}},
 			}
 		}
-	case 110:
+	case 162:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 163:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = &UnionStmt{
+				distinct: yyDollar[3].distinct,
+				left:     yyDollar[1].stmt.(DataSource),
+				right:    yyDollar[4].stmt.(DataSource),
+			}
+		}
+	case 164:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = &SetOpStmt{
+				op:       Except,
+				distinct: yyDollar[3].distinct,
+				left:     yyDollar[1].stmt.(DataSource),
+				right:    yyDollar[4].stmt.(DataSource),
+			}
+		}
+	case 165:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 166:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = &SetOpStmt{
+				op:       Intersect,
+				distinct: yyDollar[3].distinct,
+				left:     yyDollar[1].stmt.(DataSource),
+				right:    yyDollar[4].stmt.(DataSource),
+			}
+		}
+	case 167:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 168:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 169:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.ctes = []*CTE{yyDollar[1].cte}
+		}
+	case 170:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.ctes = append(yyDollar[1].ctes, yyDollar[3].cte)
+		}
+	case 171:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.cte = NewCTE(yyDollar[1].id, yyDollar[2].ids, yyDollar[5].stmt.(DataSource))
+		}
+	case 172:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.ids = nil
+		}
+	case 173:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.ids = yyDollar[2].ids
+		}
+	case 174:
 		yyDollar = yyS[yypt-13 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				distinct: yyDollar[2].distinct,
 				targets:  yyDollar[3].targets,
 				ds:       yyDollar[5].ds,
-				indexOn:  yyDollar[6].ids,
+				indexOn:  yyDollar[6].indexCols,
 				joins:    yyDollar[7].joins,
 				where:    yyDollar[8].exp,
 				groupBy:  yyDollar[9].cols,
@@ -1531,7 +2096,7 @@ yydefault:
 				offset:   yyDollar[13].exp,
 			}
 		}
-	case 111:
+	case 175:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
@@ -1540,365 +2105,418 @@ yydefault:
 				ds:       &valuesDataSource{rows: []*RowSpec{{}}},
 			}
 		}
-	case 112:
+	case 176:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.distinct = true
 		}
-	case 113:
+	case 177:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.distinct = false
 		}
-	case 114:
+	case 178:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.distinct = false
 		}
-	case 115:
+	case 179:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.distinct = true
 		}
-	case 116:
+	case 180:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.targets = nil
 		}
-	case 117:
+	case 181:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.targets = yyDollar[1].targets
 		}
-	case 118:
+	case 182:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.targets = []TargetEntry{{Exp: yyDollar[1].exp, As: yyDollar[2].id}}
 		}
-	case 119:
+	case 183:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.targets = append(yyDollar[1].targets, TargetEntry{Exp: yyDollar[3].exp, As: yyDollar[4].id})
 		}
-	case 120:
+	case 184:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sel = yyDollar[1].col
 		}
-	case 121:
+	case 185:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.sel = &JSONSelector{ColSelector: yyDollar[1].col, fields: yyDollar[2].jsonFields}
 		}
-	case 122:
+	case 186:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, col: "*"}
 		}
-	case 123:
+	case 187:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[3].col.table, col: yyDollar[3].col.col}
 		}
-	case 124:
+	case 188:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[4].col.table, col: yyDollar[4].col.col, distinct: true}
+		}
+	case 189:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[3].col.table, col: yyDollar[3].col.col, arg: &Varchar{val: yyDollar[5].str}}
+		}
+	case 190:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		{
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[4].col.table, col: yyDollar[4].col.col, distinct: true, arg: &Varchar{val: yyDollar[6].str}}
+		}
+	case 191:
+		yyDollar = yyS[yypt-12 : yypt+1]
+		{
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[10].col.table, col: yyDollar[10].col.col, arg: &Float64{val: yyDollar[3].float}, descOrder: yyDollar[11].opt_ord}
+		}
+	case 192:
+		yyDollar = yyS[yypt-12 : yypt+1]
+		{
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[10].col.table, col: yyDollar[10].col.col, arg: &Float64{val: float64(yyDollar[3].integer)}, descOrder: yyDollar[11].opt_ord}
+		}
+	case 193:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.jsonFields = []string{yyDollar[2].str}
 		}
-	case 125:
+	case 194:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.jsonFields = append(yyVAL.jsonFields, yyDollar[3].str)
 		}
-	case 126:
+	case 195:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{col: yyDollar[1].id}
 		}
-	case 127:
+	case 196:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{table: yyDollar[1].id, col: yyDollar[3].id}
 		}
-	case 128:
+	case 197:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyDollar[1].tableRef.period = yyDollar[2].period
 			yyDollar[1].tableRef.as = yyDollar[3].id
 			yyVAL.ds = yyDollar[1].tableRef
 		}
-	case 129:
+	case 198:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &valuesDataSource{inferTypes: true, rows: yyDollar[3].rows}
 		}
-	case 130:
+	case 199:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyDollar[2].stmt.(*SelectStmt).as = yyDollar[4].id
+			q := yyDollar[2].stmt
+			if w, ok := q.(*WithStmt); ok {
+				q = w.q
+			}
+			q.(*SelectStmt).as = yyDollar[4].id
 			yyVAL.ds = yyDollar[2].stmt.(DataSource)
 		}
-	case 131:
+	case 200:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "databases"}, as: yyDollar[4].id}
 		}
-	case 132:
+	case 201:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "tables"}, as: yyDollar[4].id}
 		}
-	case 133:
+	case 202:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "views"}, as: yyDollar[4].id}
+		}
+	case 203:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "table", params: []ValueExp{&Varchar{val: yyDollar[3].id}}}}
 		}
-	case 134:
+	case 204:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "users"}, as: yyDollar[4].id}
 		}
-	case 135:
+	case 205:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: yyDollar[1].value.(*FnCall), as: yyDollar[2].id}
 		}
-	case 136:
+	case 206:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.ds = &tableRef{table: yyDollar[4].id, history: true, as: yyDollar[6].id}
 		}
-	case 137:
+	case 207:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableRef = &tableRef{table: yyDollar[1].id}
 		}
-	case 138:
+	case 208:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.period = period{start: yyDollar[1].openPeriod, end: yyDollar[2].openPeriod}
 		}
-	case 139:
+	case 209:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.openPeriod = nil
 		}
-	case 140:
+	case 210:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{inclusive: true, instant: yyDollar[2].periodInstant}
 		}
-	case 141:
+	case 211:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{instant: yyDollar[2].periodInstant}
 		}
-	case 142:
+	case 212:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.openPeriod = nil
 		}
-	case 143:
+	case 213:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{inclusive: true, instant: yyDollar[2].periodInstant}
 		}
-	case 144:
+	case 214:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{instant: yyDollar[2].periodInstant}
 		}
-	case 145:
+	case 215:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.periodInstant = periodInstant{instantType: txInstant, exp: yyDollar[2].exp}
 		}
-	case 146:
+	case 216:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.periodInstant = periodInstant{instantType: timeInstant, exp: yyDollar[1].exp}
 		}
-	case 147:
+	case 217:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.joins = nil
 		}
-	case 148:
+	case 218:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joins = yyDollar[1].joins
 		}
-	case 149:
+	case 219:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joins = []*JoinSpec{yyDollar[1].join}
 		}
-	case 150:
+	case 220:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.joins = append([]*JoinSpec{yyDollar[1].join}, yyDollar[2].joins...)
 		}
-	case 151:
+	case 221:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
-			yyVAL.join = &JoinSpec{joinType: yyDollar[1].joinType, ds: yyDollar[3].ds, indexOn: yyDollar[4].ids, cond: yyDollar[6].exp}
+			yyVAL.join = &JoinSpec{joinType: yyDollar[1].joinType, ds: yyDollar[3].ds, indexOn: yyDollar[4].indexCols, cond: yyDollar[6].exp}
 		}
-	case 152:
+	case 222:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.joinType = InnerJoin
 		}
-	case 153:
-		yyDollar = yyS[yypt-1 : yypt+1]
+	case 223:
+		yyDollar = yyS[yypt-2 : yypt+1]
 		{
+			if yyDollar[2].boolean && yyDollar[1].joinType == InnerJoin {
+				yylex.Error("syntax error: unexpected OUTER, expecting JOIN")
+			}
+
 			yyVAL.joinType = yyDollar[1].joinType
 		}
-	case 154:
+	case 224:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 225:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 226:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 155:
+	case 227:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 156:
+	case 228:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.cols = nil
 		}
-	case 157:
+	case 229:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.cols = yyDollar[3].cols
 		}
-	case 158:
+	case 230:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 159:
+	case 231:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 160:
+	case 232:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 161:
+	case 233:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 162:
+	case 234:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 163:
+	case 235:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 164:
+	case 236:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.ordexps = nil
 		}
-	case 165:
+	case 237:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ordexps = yyDollar[3].ordexps
 		}
-	case 166:
+	case 238:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
-			yyVAL.ids = nil
+			yyVAL.indexCols = nil
 		}
-	case 167:
+	case 239:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.ids = yyDollar[4].ids
+			yyVAL.indexCols = []*indexColSpec{{col: yyDollar[4].id}}
 		}
-	case 168:
+	case 240:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.indexCols = yyDollar[5].indexCols
+		}
+	case 241:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.ordexps = []*OrdExp{{exp: yyDollar[1].exp, descOrder: yyDollar[2].opt_ord}}
 		}
-	case 169:
+	case 242:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ordexps = append(yyDollar[1].ordexps, &OrdExp{exp: yyDollar[3].exp, descOrder: yyDollar[4].opt_ord})
 		}
-	case 170:
+	case 243:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.opt_ord = false
 		}
-	case 171:
+	case 244:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.opt_ord = false
 		}
-	case 172:
+	case 245:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.opt_ord = true
 		}
-	case 173:
+	case 246:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.id = ""
 		}
-	case 174:
+	case 247:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.id = yyDollar[1].id
 		}
-	case 175:
+	case 248:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.id = yyDollar[2].id
 		}
-	case 176:
+	case 249:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.check = CheckConstraint{exp: yyDollar[2].exp}
 		}
-	case 177:
+	case 250:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.check = CheckConstraint{name: yyDollar[2].id, exp: yyDollar[4].exp}
 		}
-	case 178:
+	case 251:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 179:
+	case 252:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].exp
 		}
-	case 180:
+	case 253:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].exp
 		}
-	case 181:
+	case 254:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].binExp
 		}
-	case 182:
+	case 255:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = &NotBoolExp{exp: yyDollar[2].exp}
 		}
-	case 183:
+	case 256:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			i, isInt := yyDollar[2].exp.(*Integer)
@@ -1909,37 +2527,37 @@ yydefault:
 				yyVAL.exp = &NumExp{left: &Integer{val: 0}, op: SUBSOP, right: yyDollar[2].exp}
 			}
 		}
-	case 184:
+	case 257:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.exp = &LikeBoolExp{val: yyDollar[1].exp, notLike: yyDollar[2].boolean, pattern: yyDollar[4].exp}
 		}
-	case 185:
+	case 258:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &LikeBoolExp{val: yyDollar[1].exp, notLike: true, pattern: yyDollar[3].exp}
 		}
-	case 186:
+	case 259:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.exp = &ExistsBoolExp{q: (yyDollar[3].stmt).(DataSource)}
 		}
-	case 187:
+	case 260:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
-			yyVAL.exp = &InSubQueryExp{val: yyDollar[1].exp, notIn: yyDollar[2].boolean, q: yyDollar[5].stmt.(*SelectStmt)}
+			yyVAL.exp = &InSubQueryExp{val: yyDollar[1].exp, notIn: yyDollar[2].boolean, q: yyDollar[5].stmt.(DataSource)}
 		}
-	case 188:
+	case 261:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.exp = &InListExp{val: yyDollar[1].exp, notIn: yyDollar[2].boolean, values: yyDollar[5].values}
 		}
-	case 189:
+	case 262:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].exp
 		}
-	case 190:
+	case 263:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.exp = &CaseWhenExp{
@@ -1948,102 +2566,143 @@ yydefault:
 				elseExp:  yyDollar[4].exp,
 			}
 		}
-	case 191:
+	case 264:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.whenThenClauses = []whenThenClause{{when: yyDollar[2].exp, then: yyDollar[4].exp}}
 		}
-	case 192:
+	case 265:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.whenThenClauses = append(yyDollar[1].whenThenClauses, whenThenClause{when: yyDollar[3].exp, then: yyDollar[5].exp})
 		}
-	case 193:
+	case 266:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 194:
+	case 267:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 195:
+	case 268:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].sel
 		}
-	case 196:
+	case 269:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].value
 		}
-	case 197:
+	case 270:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 198:
+	case 271:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.exp = &Cast{val: yyDollar[1].exp, t: yyDollar[3].sqlType}
+			yyVAL.exp = &Cast{val: yyDollar[1].exp, t: yyDollar[3].typeSpec.t, maxLen: yyDollar[3].typeSpec.maxLen}
 		}
-	case 199:
+	case 272:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.exp = yyDollar[1].exp
+		}
+	case 273:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			fn := yyDollar[1].value.(*FnCall)
+			yyVAL.exp = &WindowFnExp{fn: fn.fn, params: fn.params, partitionBy: yyDollar[4].values, orderBy: yyDollar[5].ordexps}
+		}
+	case 274:
+		yyDollar = yyS[yypt-9 : yypt+1]
+		{
+			yyVAL.exp = &WindowFnExp{fn: yyDollar[1].aggFn, partitionBy: yyDollar[7].values, orderBy: yyDollar[8].ordexps}
+		}
+	case 275:
+		yyDollar = yyS[yypt-9 : yypt+1]
+		{
+			yyVAL.exp = &WindowFnExp{fn: yyDollar[1].aggFn, params: []ValueExp{yyDollar[3].col}, partitionBy: yyDollar[7].values, orderBy: yyDollar[8].ordexps}
+		}
+	case 276:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.values = nil
+		}
+	case 277:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.values = yyDollar[3].values
+		}
+	case 278:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 200:
+	case 279:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 201:
+	case 280:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].exp, op: ADDOP, right: yyDollar[3].exp}
 		}
-	case 202:
+	case 281:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].exp, op: SUBSOP, right: yyDollar[3].exp}
 		}
-	case 203:
+	case 282:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].exp, op: DIVOP, right: yyDollar[3].exp}
 		}
-	case 204:
+	case 283:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].exp, op: MULTOP, right: yyDollar[3].exp}
 		}
-	case 205:
+	case 284:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &NumExp{left: yyDollar[1].exp, op: MODOP, right: yyDollar[3].exp}
 		}
-	case 206:
+	case 285:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &BinBoolExp{left: yyDollar[1].exp, op: And, right: yyDollar[3].exp}
 		}
-	case 207:
+	case 286:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &BinBoolExp{left: yyDollar[1].exp, op: Or, right: yyDollar[3].exp}
 		}
-	case 208:
+	case 287:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &CmpBoolExp{left: yyDollar[1].exp, op: yyDollar[2].cmpOp, right: yyDollar[3].exp}
 		}
-	case 209:
+	case 288:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.binExp = &AnyCmpExp{op: yyDollar[2].cmpOp, val: yyDollar[1].exp, array: yyDollar[5].exp}
+		}
+	case 289:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.binExp = &ArrayOpExp{op: yyDollar[2].arrayOp, left: yyDollar[1].exp, right: yyDollar[3].exp}
+		}
+	case 290:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.binExp = &CmpBoolExp{left: yyDollar[1].exp, op: EQ, right: &NullValue{t: AnyType}}
 		}
-	case 210:
+	case 291:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.binExp = &CmpBoolExp{left: yyDollar[1].exp, op: NE, right: &NullValue{t: AnyType}}
@@ -17,6 +17,7 @@ limitations under the License.
 package sql
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"os"
@@ -46,6 +47,12 @@ type SQLTx struct {
 	txHeader *store.TxHeader // header is set once tx is committed
 
 	onCommittedCallbacks []onCommittedCallback
+
+	initializedIndexes [][]byte            // store indexes initialized for tables created or indexed within the current tx
+	createdTables      map[uint32]struct{} // tables created within the current tx
+	writtenTables      map[uint32]struct{} // tables whose rows were written within the current tx
+
+	cteWorkingTables map[*CTE]*cteWorkingTable // working tables of the recursive common table expressions evaluated within the current tx
 }
 
 type onCommittedCallback = func(sqlTx *SQLTx) error
@@ -118,6 +125,7 @@ func (sqlTx *SQLTx) getWithPrefix(ctx context.Context, prefix, neq []byte) (key
 
 func (sqlTx *SQLTx) Cancel() error {
 	defer sqlTx.removeTempFiles()
+	defer sqlTx.releaseIndexing(false)
 
 	return sqlTx.tx.Cancel()
 }
@@ -133,9 +141,12 @@ func (sqlTx *SQLTx) Commit(ctx context.Context) error {
 	// no need to wait for indexing to be up to date during commit phase
 	sqlTx.txHeader, err = sqlTx.tx.AsyncCommit(ctx)
 	if err != nil && !errors.Is(err, store.ErrNoEntriesProvided) {
+		sqlTx.releaseIndexing(false)
 		return err
 	}
 
+	sqlTx.releaseIndexing(true)
+
 	merr := multierr.NewMultiErr()
 
 	for _, onCommitCallback := range sqlTx.onCommittedCallbacks {
@@ -154,6 +165,298 @@ func (sqlTx *SQLTx) delete(ctx context.Context, key []byte) error {
 	return sqlTx.tx.Delete(ctx, key)
 }
 
+// initIndexing initializes the indexes of a table created or indexed within the transaction,
+// so to make them available to the following statements. Entries of indexes initialized in the
+// middle of a transaction are otherwise only built once committed, rows written within the
+// transaction are thus indexed as well.
+func (sqlTx *SQLTx) initIndexing(ctx context.Context, table *Table) error {
+	_, created := sqlTx.createdTables[table.id]
+	_, written := sqlTx.writtenTables[table.id]
+
+	initialized, err := sqlTx.engine.initPendingTableIndexing(sqlTx, table)
+	sqlTx.initializedIndexes = append(sqlTx.initializedIndexes, initialized...)
+	if err != nil {
+		return err
+	}
+
+	if len(initialized) == 0 {
+		return nil
+	}
+
+	if created {
+		if !written {
+			return nil
+		}
+
+		return sqlTx.indexWrittenRows(ctx, table, sqlTx.indexesWithPrefix(table, initialized))
+	}
+
+	committedTxID := sqlTx.engine.store.LastCommittedTxID()
+
+	// committed rows must be indexed before the index is read
+	err = sqlTx.engine.store.WaitForIndexingUpto(ctx, committedTxID)
+	if err != nil || !written {
+		return err
+	}
+
+	return sqlTx.indexModifiedRows(ctx, table, sqlTx.indexesWithPrefix(table, initialized), committedTxID)
+}
+
+// indexesWithPrefix returns the secondary indexes of the table stored under any of the given prefixes
+func (sqlTx *SQLTx) indexesWithPrefix(table *Table, prefixes [][]byte) []*Index {
+	var indexes []*Index
+
+	for _, index := range table.indexes {
+		if index.IsPrimary() {
+			continue
+		}
+
+		prefix := MapKey(sqlTx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(index.id))
+
+		for _, p := range prefixes {
+			if bytes.Equal(p, prefix) {
+				indexes = append(indexes, index)
+			}
+		}
+	}
+
+	return indexes
+}
+
+// indexWrittenRows adds the rows of a table created within the transaction to the given indexes
+func (sqlTx *SQLTx) indexWrittenRows(ctx context.Context, table *Table, indexes []*Index) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	rows, err := newPKRowScanner(ctx, sqlTx, table)
+	if err != nil {
+		return err
+	}
+	defer rows.close()
+
+	for rows.pk != nil {
+		err = sqlTx.setIndexEntries(table, indexes, rows.valuesByColID)
+		if err != nil {
+			return err
+		}
+
+		err = rows.next(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexModifiedRows adds the rows of an existing table inserted or updated within the transaction to the given
+// indexes, which already hold the committed rows, and deletes the entries of the rows updated or deleted in it.
+// Modified rows are found by comparing the rows of the transaction with the committed ones, both sorted by primary key.
+func (sqlTx *SQLTx) indexModifiedRows(ctx context.Context, table *Table, indexes []*Index, committedTxID uint64) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	snapTx, err := sqlTx.engine.NewTx(ctx, DefaultTxOptions().
+		WithReadOnly(true).
+		WithSnapshotMustIncludeTxID(func(_ uint64) uint64 {
+			return committedTxID
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer snapTx.Cancel()
+
+	committedRows, err := newPKRowScanner(ctx, snapTx, table)
+	if err != nil {
+		return err
+	}
+	defer committedRows.close()
+
+	rows, err := newPKRowScanner(ctx, sqlTx, table)
+	if err != nil {
+		return err
+	}
+	defer rows.close()
+
+	for committedRows.pk != nil || rows.pk != nil {
+		cmp := 0
+
+		if committedRows.pk == nil {
+			cmp = 1
+		} else if rows.pk == nil {
+			cmp = -1
+		} else {
+			cmp = bytes.Compare(committedRows.pk, rows.pk)
+		}
+
+		if cmp == 0 && bytes.Equal(committedRows.encodedRow, rows.encodedRow) {
+			err = committedRows.next(ctx)
+			if err == nil {
+				err = rows.next(ctx)
+			}
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if cmp <= 0 {
+			// the committed row was either updated or deleted
+			err = sqlTx.deleteCommittedIndexEntries(table, indexes, committedRows.pk, committedRows.valuesByColID)
+			if err == nil {
+				err = committedRows.next(ctx)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if cmp >= 0 {
+			err = sqlTx.setIndexEntries(table, indexes, rows.valuesByColID)
+			if err == nil {
+				err = rows.next(ctx)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setIndexEntries sets the transient entries of the row into the given indexes
+func (sqlTx *SQLTx) setIndexEntries(table *Table, indexes []*Index, valuesByColID map[uint32]TypedValue) error {
+	encodedRowValue, err := sqlTx.encodeRowValue(valuesByColID, table)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		smkey, err := sqlTx.indexEntryKey(index, valuesByColID)
+		if err != nil {
+			return err
+		}
+
+		err = sqlTx.setTransient(smkey, nil, encodedRowValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteCommittedIndexEntries marks as deleted the entries of a committed row in the given indexes,
+// as done with the entries of existing indexes when the row is updated or deleted
+func (sqlTx *SQLTx) deleteCommittedIndexEntries(table *Table, indexes []*Index, pkEncVals []byte, valuesByColID map[uint32]TypedValue) error {
+	encodedRowValue, err := sqlTx.encodeRowValue(valuesByColID, table)
+	if err != nil {
+		return err
+	}
+
+	md := store.NewKVMetadata()
+	md.AsDeleted(true)
+
+	for _, index := range indexes {
+		smkey, err := sqlTx.indexEntryKey(index, valuesByColID)
+		if err != nil {
+			return err
+		}
+
+		err = sqlTx.set(append(smkey, pkEncVals...), md, encodedRowValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pkRowScanner reads the rows of a table sorted by primary key, pk is nil once all rows were read
+type pkRowScanner struct {
+	table  *Table
+	reader *rawRowReader
+
+	pk            []byte
+	encodedRow    []byte
+	valuesByColID map[uint32]TypedValue
+}
+
+func newPKRowScanner(ctx context.Context, tx *SQLTx, table *Table) (*pkRowScanner, error) {
+	reader, err := newRawRowReader(tx, nil, table, period{}, table.name, &ScanSpecs{Index: table.primaryIndex})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &pkRowScanner{table: table, reader: reader}
+
+	err = s.next(ctx)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *pkRowScanner) next(ctx context.Context) error {
+	row, err := s.reader.Read(ctx)
+	if errors.Is(err, ErrNoMoreRows) {
+		s.pk = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.valuesByColID = make(map[uint32]TypedValue, len(s.table.cols))
+
+	for _, col := range s.table.cols {
+		v := row.ValuesBySelector[EncodeSelector("", s.table.name, col.colName)]
+		if v != nil && !v.IsNull() {
+			s.valuesByColID[col.id] = v
+		}
+	}
+
+	s.pk, err = encodedKey(s.table.primaryIndex, s.valuesByColID)
+	if err != nil {
+		return err
+	}
+
+	s.encodedRow, err = s.reader.tx.encodeRowValue(s.valuesByColID, s.table)
+	return err
+}
+
+func (s *pkRowScanner) close() error {
+	return s.reader.Close()
+}
+
+// releaseIndexing releases the indexes initialized within the transaction,
+// which are deleted if it's not committed
+func (sqlTx *SQLTx) releaseIndexing(committed bool) {
+	sqlTx.engine.releasePendingIndexes(sqlTx, sqlTx.initializedIndexes, committed)
+	sqlTx.initializedIndexes = nil
+}
+
+func (sqlTx *SQLTx) tableCreated(table *Table) {
+	if sqlTx.createdTables == nil {
+		sqlTx.createdTables = make(map[uint32]struct{})
+	}
+	sqlTx.createdTables[table.id] = struct{}{}
+}
+
+func (sqlTx *SQLTx) tableWritten(table *Table) {
+	if sqlTx.writtenTables == nil {
+		sqlTx.writtenTables = make(map[uint32]struct{})
+	}
+	sqlTx.writtenTables[table.id] = struct{}{}
+}
+
 func (sqlTx *SQLTx) addOnCommittedCallback(callback onCommittedCallback) error {
 	if callback == nil {
 		return ErrIllegalArguments
@@ -19,6 +19,7 @@ package sql
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
@@ -34,12 +35,17 @@ import (
 )
 
 const (
-	catalogPrefix          = "CTL."
-	catalogTablePrefix     = "CTL.TABLE."     // (key=CTL.TABLE.{1}{tableID}, value={tableNAME})
-	catalogColumnPrefix    = "CTL.COLUMN."    // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable){maxLen}{colNAME}})
-	catalogIndexPrefix     = "CTL.INDEX."     // (key=CTL.INDEX.{1}{tableID}{indexID}, value={unique {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)})
-	catalogCheckPrefix     = "CTL.CHECK."     // (key=CTL.CHECK.{1}{tableID}{checkID}, value={nameLen}{name}{expText})
-	catalogPrivilegePrefix = "CTL.PRIVILEGE." // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable){maxLen}{colNAME}})
+	catalogPrefix           = "CTL."
+	catalogTablePrefix      = "CTL.TABLE."     // (key=CTL.TABLE.{1}{tableID}, value={tableNAME})
+	catalogColumnPrefix     = "CTL.COLUMN."    // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable | default | generated){maxLen}({colNameLen}{colNAME}{expText} | {colNAME})})
+	catalogIndexPrefix      = "CTL.INDEX."     // (key=CTL.INDEX.{1}{tableID}{indexID}, value={unique {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)})
+	catalogCheckPrefix      = "CTL.CHECK."     // (key=CTL.CHECK.{1}{tableID}{checkID}, value={nameLen}{name}{expText})
+	catalogForeignKeyPrefix = "CTL.FKEY."      // (key=CTL.FKEY.{1}{tableID}{fkID}, value={nameLen}{name}{refTableID}{colCount}{colID}+{refColID}+)
+	catalogViewPrefix       = "CTL.VIEW."      // (key=CTL.VIEW.{1}{viewID}, value={nameLen}{name}{colCount}({colNameLen}{colName})*{query})
+	catalogColumnTypePrefix = "CTL.COLTYPE."   // (key=CTL.COLTYPE.{1}{tableID}{colID}{seq}, value={untilTx}{colTYPE})
+	catalogSequencePrefix   = "CTL.SEQUENCE."  // (key=CTL.SEQUENCE.{1}{seqID}, value={nameLen}{name}{start}{increment}{minValue}{maxValue}{cycle})
+	catalogSeqValuePrefix   = "CTL.SEQVAL."    // (key=CTL.SEQVAL.{1}{seqID}, value={lastValue})
+	catalogPrivilegePrefix  = "CTL.PRIVILEGE." // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable){maxLen}{colNAME}})
 
 	RowPrefix    = "R." // (key=R.{1}{tableID}{0}({null}({pkVal}{padding}{pkValLen})?)+, value={count (colID valLen val)+})
 	MappedPrefix = "M." // (key=M.{tableID}{indexID}({null}({val}{padding}{valLen})?)*({pkVal}{padding}{pkValLen})+, value={count (colID valLen val)+})
@@ -53,6 +59,8 @@ const (
 const (
 	nullableFlag      byte = 1 << iota
 	autoIncrementFlag byte = 1 << iota
+	defaultFlag       byte = 1 << iota
+	generatedFlag     byte = 1 << iota
 )
 
 const (
@@ -79,13 +87,15 @@ const (
 	UUIDType      SQLValueType = "UUID"
 	BLOBType      SQLValueType = "BLOB"
 	Float64Type   SQLValueType = "FLOAT"
+	DecimalType   SQLValueType = "DECIMAL"
 	TimestampType SQLValueType = "TIMESTAMP"
+	IntervalType  SQLValueType = "INTERVAL"
 	AnyType       SQLValueType = "ANY"
 	JSONType      SQLValueType = "JSON"
 )
 
 func IsNumericType(t SQLValueType) bool {
-	return t == IntegerType || t == Float64Type
+	return t == IntegerType || t == Float64Type || t == DecimalType
 }
 
 type Permission = string
@@ -118,11 +128,16 @@ func PermissionFromCode(code uint32) Permission {
 type AggregateFn = string
 
 const (
-	COUNT AggregateFn = "COUNT"
-	SUM   AggregateFn = "SUM"
-	MAX   AggregateFn = "MAX"
-	MIN   AggregateFn = "MIN"
-	AVG   AggregateFn = "AVG"
+	COUNT           AggregateFn = "COUNT"
+	SUM             AggregateFn = "SUM"
+	MAX             AggregateFn = "MAX"
+	MIN             AggregateFn = "MIN"
+	AVG             AggregateFn = "AVG"
+	STRING_AGG      AggregateFn = "STRING_AGG"
+	BOOL_AND        AggregateFn = "BOOL_AND"
+	BOOL_OR         AggregateFn = "BOOL_OR"
+	STDDEV          AggregateFn = "STDDEV"
+	PERCENTILE_CONT AggregateFn = "PERCENTILE_CONT"
 )
 
 type CmpOperator = int
@@ -168,6 +183,13 @@ func LogicOperatorToString(op LogicOperator) string {
 	return "OR"
 }
 
+type SetOperator = int
+
+const (
+	Intersect SetOperator = iota
+	Except
+)
+
 type NumOperator = int
 
 const (
@@ -200,6 +222,7 @@ const (
 	InnerJoin JoinType = iota
 	LeftJoin
 	RightJoin
+	FullOuterJoin
 )
 
 type SQLStmt interface {
@@ -463,6 +486,7 @@ type CreateTableStmt struct {
 	ifNotExists bool
 	colsSpec    []*ColSpec
 	checks      []CheckConstraint
+	foreignKeys []ForeignKeyConstraint
 	pkColNames  PrimaryKeyConstraint
 }
 
@@ -482,6 +506,157 @@ func (stmt *CreateTableStmt) inferParameters(ctx context.Context, tx *SQLTx, par
 	return nil
 }
 
+// validateColSpecExps checks the default values and generation expressions of the given columns.
+// Default values must not depend on other columns, while generation expressions may only reference
+// non-generated columns. Both are evaluated over a row holding the zero value of each column in cols.
+func validateColSpecExps(tx *SQLTx, table string, specs []*ColSpec, cols []*ColSpec) error {
+	row := zeroRow(table, cols)
+
+	generated := make(map[string]struct{})
+	for _, col := range cols {
+		if col.generatedExp != nil {
+			generated[col.colName] = struct{}{}
+		}
+	}
+
+	for _, spec := range specs {
+		exp := spec.defaultExp
+		expErr := ErrInvalidDefaultValue
+
+		if spec.generatedExp != nil {
+			exp = spec.generatedExp
+			expErr = ErrInvalidGeneratedColumn
+		}
+
+		if exp == nil {
+			continue
+		}
+
+		if spec.defaultExp != nil && spec.generatedExp != nil {
+			return fmt.Errorf("%w: column '%s' can not have a default value", ErrInvalidGeneratedColumn, spec.colName)
+		}
+
+		if spec.autoIncrement {
+			return fmt.Errorf("%w: column '%s' is auto incremental", expErr, spec.colName)
+		}
+
+		if isSubQueryExp(exp) {
+			return fmt.Errorf("%w: sub-queries in column expressions", ErrNoSupported)
+		}
+
+		if spec.generatedExp != nil && usesSequence(exp, "") {
+			return fmt.Errorf("%w: column '%s' can not use sequences", expErr, spec.colName)
+		}
+
+		if spec.generatedExp != nil && usesNonDeterministicFunction(tx, exp) {
+			return fmt.Errorf("%w: column '%s' can not use non deterministic functions", expErr, spec.colName)
+		}
+
+		for _, sel := range exp.selectors() {
+			if spec.defaultExp != nil {
+				return fmt.Errorf("%w: column '%s' can not reference other columns", expErr, spec.colName)
+			}
+
+			_, _, colName := sel.resolve(table)
+			if _, isGenerated := generated[colName]; isGenerated {
+				return fmt.Errorf("%w: column '%s' can not reference generated column '%s'", expErr, spec.colName, colName)
+			}
+		}
+
+		if usesSequence(exp, "") {
+			// evaluating the default value would advance the sequence
+			continue
+		}
+
+		val, err := exp.reduce(tx, row, table)
+		if err != nil {
+			return fmt.Errorf("%w: column '%s': %v", expErr, spec.colName, err)
+		}
+
+		if val.IsNull() {
+			continue
+		}
+
+		_, err = EncodeValue(val, spec.colType, spec.maxLen)
+		if err != nil {
+			return fmt.Errorf("%w: column '%s': %v", expErr, spec.colName, err)
+		}
+	}
+	return nil
+}
+
+// defaultValue evaluates the default value of the column, NULL is returned when no default value is set
+func (c *Column) defaultValue(tx *SQLTx) (TypedValue, error) {
+	if c.defaultExp == nil {
+		return &NullValue{t: c.colType}, nil
+	}
+	return c.defaultExp.reduce(tx, nil, c.table.name)
+}
+
+// setGeneratedValues computes the value of generated columns out of the values of the row,
+// decimal values are first adjusted to the precision and scale of their columns
+func setGeneratedValues(tx *SQLTx, table *Table, valuesByColID map[uint32]TypedValue) error {
+	for colID, val := range valuesByColID {
+		col, err := table.GetColumnByID(colID)
+		if err != nil {
+			return err
+		}
+
+		fitted, err := col.fitValue(val)
+		if err != nil {
+			return fmt.Errorf("%w (%s)", err, col.colName)
+		}
+		valuesByColID[colID] = fitted
+	}
+
+	var row *Row
+
+	for _, col := range table.cols {
+		if col.generatedExp == nil {
+			continue
+		}
+
+		if row == nil {
+			row = &Row{
+				ValuesByPosition: make([]TypedValue, len(table.cols)),
+				ValuesBySelector: make(map[string]TypedValue, len(table.cols)),
+			}
+
+			for i, c := range table.cols {
+				v, ok := valuesByColID[c.id]
+				if !ok || v == nil {
+					v = &NullValue{t: c.colType}
+				}
+
+				row.ValuesByPosition[i] = v
+				row.ValuesBySelector[EncodeSelector("", table.name, c.colName)] = v
+			}
+		}
+
+		val, err := col.generatedExp.reduce(tx, row, table.name)
+		if err != nil {
+			return err
+		}
+
+		if val.IsNull() {
+			if col.notNull {
+				return fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+			}
+
+			delete(valuesByColID, col.id)
+			continue
+		}
+
+		val, err = col.fitValue(val)
+		if err != nil {
+			return fmt.Errorf("%w (%s)", err, col.colName)
+		}
+
+		valuesByColID[col.id] = val
+	}
+	return nil
+}
+
 func zeroRow(tableName string, cols []*ColSpec) *Row {
 	r := Row{
 		ValuesByPosition: make([]TypedValue, len(cols)),
@@ -513,6 +688,18 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 
 	row := zeroRow(stmt.table, stmt.colsSpec)
 	for _, check := range stmt.checks {
+		if isSubQueryExp(check.exp) {
+			return nil, fmt.Errorf("%w: sub-queries in check constraints", ErrNoSupported)
+		}
+
+		if usesSequence(check.exp, "") {
+			return nil, fmt.Errorf("%w: sequences in check constraints", ErrNoSupported)
+		}
+
+		if usesNonDeterministicFunction(tx, check.exp) {
+			return nil, fmt.Errorf("%w: non deterministic functions in check constraints", ErrNoSupported)
+		}
+
 		value, err := check.exp.reduce(tx, row, stmt.table)
 		if err != nil {
 			return nil, err
@@ -523,6 +710,11 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		}
 	}
 
+	err := validateColSpecExps(tx, stmt.table, stmt.colsSpec, stmt.colsSpec)
+	if err != nil {
+		return nil, err
+	}
+
 	nextUnnamedCheck := 0
 	checks := make(map[string]CheckConstraint)
 	for id, check := range stmt.checks {
@@ -542,6 +734,8 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		return nil, err
 	}
 
+	tx.tableCreated(table)
+
 	createIndexStmt := &CreateIndexStmt{unique: true, table: table.name, cols: stmt.primaryKeyCols()}
 	_, err = createIndexStmt.execAt(ctx, tx, params)
 	if err != nil {
@@ -555,6 +749,10 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 			}
 		}
 
+		if col.generatedExp != nil && table.primaryIndex.IncludesCol(col.id) {
+			return nil, fmt.Errorf("%w: column '%s' is part of the primary key", ErrInvalidGeneratedColumn, col.colName)
+		}
+
 		err := persistColumn(tx, col)
 		if err != nil {
 			return nil, err
@@ -567,6 +765,25 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		}
 	}
 
+	nextUnnamedForeignKey := 0
+	for id, spec := range stmt.foreignKeys {
+		name := fmt.Sprintf("%s_fkey%d", stmt.table, nextUnnamedForeignKey+1)
+		if spec.name != "" {
+			name = spec.name
+		} else {
+			nextUnnamedForeignKey++
+		}
+
+		fk, err := newForeignKeyFromSpec(tx.catalog, table, uint32(id), name, &spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := persistForeignKey(tx, fk); err != nil {
+			return nil, err
+		}
+	}
+
 	mappedKey := MapKey(tx.sqlPrefix(), catalogTablePrefix, EncodeID(DatabaseID), EncodeID(table.id))
 
 	err = tx.set(mappedKey, nil, []byte(table.name))
@@ -615,7 +832,7 @@ func (stmt *CreateTableStmt) primaryKeyCols() []string {
 
 func persistColumn(tx *SQLTx, col *Column) error {
 	//{auto_incremental | nullable}{maxLen}{colNAME})
-	v := make([]byte, 1+4+len(col.colName))
+	v := make([]byte, 1+4, 1+4+len(col.colName))
 
 	if col.autoIncrement {
 		v[0] = v[0] | autoIncrementFlag
@@ -627,7 +844,28 @@ func persistColumn(tx *SQLTx, col *Column) error {
 
 	binary.BigEndian.PutUint32(v[1:], uint32(col.MaxLen()))
 
-	copy(v[5:], []byte(col.Name()))
+	exp := col.defaultExp
+	if exp != nil {
+		v[0] = v[0] | defaultFlag
+	}
+
+	if col.generatedExp != nil {
+		exp = col.generatedExp
+		v[0] = v[0] | generatedFlag
+	}
+
+	if exp == nil {
+		v = append(v, []byte(col.Name())...)
+	} else {
+		// {default | generated}{maxLen}{colNameLen-1}{colNAME}{expText}
+		if len(col.colName) > 256 {
+			return fmt.Errorf("column name len: %w", ErrMaxLengthExceeded)
+		}
+
+		v = append(v, byte(len(col.colName)-1))
+		v = append(v, []byte(col.colName)...)
+		v = append(v, []byte(exp.String())...)
+	}
 
 	mappedKey := MapKey(
 		tx.sqlPrefix(),
@@ -667,6 +905,97 @@ func persistCheck(tx *SQLTx, table *Table, check *CheckConstraint) error {
 	return tx.set(mappedKey, nil, val)
 }
 
+// newForeignKeyFromSpec resolves the columns and the table referenced by a foreign key
+func newForeignKeyFromSpec(catalog *Catalog, table *Table, id uint32, name string, spec *ForeignKeyConstraint) (*ForeignKey, error) {
+	refTable := table
+	if spec.refTable != table.name {
+		t, err := catalog.GetTableByName(spec.refTable)
+		if err != nil {
+			return nil, err
+		}
+		refTable = t
+	}
+
+	colIDs := make([]uint32, len(spec.cols))
+	for i, colName := range spec.cols {
+		col, err := table.GetColumnByName(colName)
+		if err != nil {
+			return nil, err
+		}
+		colIDs[i] = col.id
+	}
+
+	var refColIDs []uint32
+
+	if len(spec.refCols) == 0 {
+		refColIDs = make([]uint32, len(refTable.primaryIndex.cols))
+		for i, col := range refTable.primaryIndex.cols {
+			refColIDs[i] = col.id
+		}
+	} else {
+		refColIDs = make([]uint32, len(spec.refCols))
+		for i, colName := range spec.refCols {
+			col, err := refTable.GetColumnByName(colName)
+			if err != nil {
+				return nil, err
+			}
+			refColIDs[i] = col.id
+		}
+	}
+
+	return table.newForeignKey(id, name, colIDs, refTable, refColIDs)
+}
+
+func persistForeignKey(tx *SQLTx, fk *ForeignKey) error {
+	mappedKey := MapKey(
+		tx.sqlPrefix(),
+		catalogForeignKeyPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(fk.table.id),
+		EncodeID(fk.id),
+	)
+
+	if len(fk.name) > 256 {
+		return fmt.Errorf("constraint name len: %w", ErrMaxLengthExceeded)
+	}
+
+	if len(fk.cols) > math.MaxUint8 {
+		return fmt.Errorf("%w: too many columns in foreign key %s", ErrInvalidForeignKeyConstraint, fk.name)
+	}
+
+	//{nameLen-1}{name}{refTableID}{colCount}{colID}+{refColID}+
+	val := make([]byte, 1+len(fk.name)+EncIDLen+1+2*len(fk.cols)*EncIDLen)
+
+	val[0] = byte(len(fk.name)) - 1
+	copy(val[1:], []byte(fk.name))
+
+	i := 1 + len(fk.name)
+
+	binary.BigEndian.PutUint32(val[i:], fk.refTable.id)
+	i += EncIDLen
+
+	val[i] = byte(len(fk.cols))
+	i++
+
+	for j, col := range fk.cols {
+		binary.BigEndian.PutUint32(val[i+j*EncIDLen:], col.id)
+		binary.BigEndian.PutUint32(val[i+(len(fk.cols)+j)*EncIDLen:], fk.refCols[j].id)
+	}
+
+	return tx.set(mappedKey, nil, val)
+}
+
+func persistForeignKeyDeletion(ctx context.Context, tx *SQLTx, tableID uint32, fkID uint32) error {
+	mappedKey := MapKey(
+		tx.sqlPrefix(),
+		catalogForeignKeyPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(tableID),
+		EncodeID(fkID),
+	)
+	return tx.delete(ctx, mappedKey)
+}
+
 type ColSpec struct {
 	colName       string
 	colType       SQLValueType
@@ -674,6 +1003,12 @@ type ColSpec struct {
 	autoIncrement bool
 	notNull       bool
 	primaryKey    bool
+
+	defaultExp   ValueExp
+	generatedExp ValueExp
+
+	// set when a column-level REFERENCES clause is specified
+	references *ForeignKeyConstraint
 }
 
 func NewColSpec(name string, colType SQLValueType, maxLen int, autoIncrement bool, notNull bool) *ColSpec {
@@ -691,6 +1026,37 @@ type CreateIndexStmt struct {
 	ifNotExists bool
 	table       string
 	cols        []string
+	jsonFields  [][]string // paths of JSON columns, if any, by position of the indexed column
+}
+
+// indexColSpec specifies an indexed column, when JSON fields are set,
+// the values found at such path of a JSON column are indexed instead
+type indexColSpec struct {
+	col        string
+	jsonFields []string
+}
+
+func splitIndexColSpecs(specs []*indexColSpec) (cols []string, jsonFields [][]string) {
+	cols = make([]string, len(specs))
+
+	for i, spec := range specs {
+		cols[i] = spec.col
+
+		if len(spec.jsonFields) > 0 {
+			if jsonFields == nil {
+				jsonFields = make([][]string, len(specs))
+			}
+			jsonFields[i] = spec.jsonFields
+		}
+	}
+	return cols, jsonFields
+}
+
+func jsonFieldsAt(jsonFields [][]string, i int) []string {
+	if jsonFields == nil {
+		return nil
+	}
+	return jsonFields[i]
 }
 
 func NewCreateIndexStmt(table string, cols []string, isUnique bool) *CreateIndexStmt {
@@ -728,20 +1094,32 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 	indexKeyLen := 0
 
 	for i, colName := range stmt.cols {
-		col, err := table.GetColumnByName(colName)
+		col, err := table.indexColumn(colName, jsonFieldsAt(stmt.jsonFields, i))
 		if err != nil {
 			return nil, err
 		}
 
-		if col.Type() == JSONType {
+		if col.jsonCol == nil && col.Type() == JSONType {
 			return nil, ErrCannotIndexJson
 		}
 
+		if IsArrayType(col.Type()) {
+			return nil, ErrCannotIndexArray
+		}
+
+		if col.jsonCol != nil && stmt.unique {
+			return nil, fmt.Errorf("%w: unique indexes on JSON paths are not supported", ErrIllegalArguments)
+		}
+
 		if variableSizedType(col.colType) && !tx.engine.lazyIndexConstraintValidation && (col.MaxLen() == 0 || col.MaxLen() > MaxKeyLen) {
 			return nil, fmt.Errorf("%w: can not create index using column '%s'. Max key length for variable columns is %d", ErrLimitedKeyType, col.colName, MaxKeyLen)
 		}
 
-		indexKeyLen += col.MaxLen()
+		if col.colType == DecimalType {
+			indexKeyLen += decimalKeyLen
+		} else {
+			indexKeyLen += col.MaxLen()
+		}
 
 		colIDs[i] = col.id
 	}
@@ -750,7 +1128,15 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		return nil, fmt.Errorf("%w: can not create index using columns '%v'. Max key length is %d", ErrLimitedKeyType, stmt.cols, MaxKeyLen)
 	}
 
-	if stmt.unique && table.primaryIndex != nil {
+	index, err := table.newIndex(stmt.unique, colIDs)
+	if errors.Is(err, ErrIndexAlreadyExists) && stmt.ifNotExists {
+		return tx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if index.IsUnique() {
 		// check table is empty
 		pkPrefix := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id))
 		_, _, err := tx.getWithPrefix(ctx, pkPrefix, nil)
@@ -758,32 +1144,26 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 			return nil, ErrTableDoesNotExist
 		}
 		if err == nil {
-			return nil, ErrLimitedIndexCreation
+			// existing rows must not violate the uniqueness constraint,
+			// index entries are then built by the store indexer
+			err = validateUniqueIndex(ctx, tx, index)
+			if err != nil {
+				return nil, err
+			}
 		} else if !errors.Is(err, store.ErrKeyNotFound) {
 			return nil, err
 		}
 	}
 
-	index, err := table.newIndex(stmt.unique, colIDs)
-	if errors.Is(err, ErrIndexAlreadyExists) && stmt.ifNotExists {
-		return tx, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
 	// v={unique {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)}
-	// TODO: currently only ASC order is supported
-	colSpecLen := EncIDLen + 1
-
-	encodedValues := make([]byte, 1+len(index.cols)*colSpecLen)
+	encodedValues := make([]byte, 1)
 
 	if index.IsUnique() {
 		encodedValues[0] = 1
 	}
 
-	for i, col := range index.cols {
-		copy(encodedValues[1+i*colSpecLen:], EncodeID(col.id))
+	for _, col := range index.cols {
+		encodedValues = append(encodedValues, encodeIndexColSpec(col)...)
 	}
 
 	mappedKey := MapKey(tx.sqlPrefix(), catalogIndexPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(index.id))
@@ -793,51 +1173,261 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		return nil, err
 	}
 
+	err = tx.initIndexing(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
 	tx.mutatedCatalog = true
 
 	return tx, nil
 }
 
-type AddColumnStmt struct {
-	table   string
-	colSpec *ColSpec
-}
+// validateUniqueIndex checks that the rows of the table hold no duplicated values for the columns of the index.
+// Committed rows are read from a snapshot, so they are not added to the read-set of the transaction and writers
+// are not blocked, but the transaction conflicts if rows are modified after the snapshot as they were not validated.
+func validateUniqueIndex(ctx context.Context, tx *SQLTx, index *Index) error {
+	_, created := tx.createdTables[index.table.id]
+	_, written := tx.writtenTables[index.table.id]
 
-func NewAddColumnStmt(table string, colSpec *ColSpec) *AddColumnStmt {
-	return &AddColumnStmt{table: table, colSpec: colSpec}
-}
+	if created || written {
+		// rows were written within the transaction
+		return validateUniqueRows(ctx, tx, index)
+	}
 
-func (stmt *AddColumnStmt) readOnly() bool {
-	return false
-}
+	snapshotTxID := tx.engine.store.LastPrecommittedTxID()
 
-func (stmt *AddColumnStmt) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeAlter}
-}
+	snapTx, err := tx.engine.NewTx(ctx, DefaultTxOptions().
+		WithReadOnly(true).
+		WithSnapshotMustIncludeTxID(func(_ uint64) uint64 {
+			return snapshotTxID
+		}),
+	)
+	if err != nil {
+		return err
+	}
 
-func (stmt *AddColumnStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	return nil
-}
+	err = validateUniqueRows(ctx, snapTx, index)
 
-func (stmt *AddColumnStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	table, err := tx.catalog.GetTableByName(stmt.table)
+	cerr := snapTx.Cancel()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if cerr != nil {
+		return cerr
 	}
 
-	col, err := table.newColumn(stmt.colSpec)
+	return tx.requireUnmodifiedRows(ctx, index.table, snapshotTxID)
+}
+
+// validateUniqueRows sorts the rows of the table by the indexed columns, spilling into temporary files when they
+// don't fit into the sort buffer, thus duplicated values are found by comparing adjacent rows.
+func validateUniqueRows(ctx context.Context, tx *SQLTx, index *Index) error {
+	table := index.table
+
+	rowReader, err := newRawRowReader(tx, nil, table, period{}, table.name, &ScanSpecs{Index: table.primaryIndex})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	err = persistColumn(tx, col)
+	ordExps := make([]*OrdExp, len(index.cols))
+	for i, col := range index.cols {
+		ordExps[i] = &OrdExp{exp: &ColSelector{table: table.name, col: col.colName}}
+	}
+
+	sortReader, err := newSortRowReader(rowReader, ordExps)
 	if err != nil {
-		return nil, err
+		rowReader.Close()
+		return err
 	}
+	defer sortReader.Close()
 
-	tx.mutatedCatalog = true
+	var prevKey []byte
 
-	return tx, nil
+	validatedRows := 0
+
+	for {
+		row, err := sortReader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var key []byte
+
+		for _, col := range index.cols {
+			encVal, _, err := EncodeValueAsKey(row.ValuesBySelector[EncodeSelector("", table.name, col.colName)], col.colType, col.MaxLen())
+			if err != nil {
+				return fmt.Errorf("%w: index on '%s' and column '%s'", err, index.Name(), col.colName)
+			}
+
+			key = append(key, encVal...)
+		}
+
+		if bytes.Equal(key, prevKey) {
+			return fmt.Errorf("%w: can not create unique index '%s' due to duplicated values in existing rows", store.ErrKeyAlreadyExists, index.Name())
+		}
+
+		prevKey = key
+		validatedRows++
+
+		if validatedRows%tx.engine.indexBackfillProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			tx.engine.notifyIndexBackfillProgress(index, validatedRows)
+		}
+	}
+
+	if validatedRows%tx.engine.indexBackfillProgressInterval != 0 {
+		tx.engine.notifyIndexBackfillProgress(index, validatedRows)
+	}
+
+	return nil
+}
+
+// requireUnmodifiedRows adds a single read to the read-set of the transaction, making it conflict
+// if rows of the table are modified after the given transaction
+func (tx *SQLTx) requireUnmodifiedRows(ctx context.Context, table *Table, txID uint64) error {
+	r, err := tx.newKeyReader(store.KeyReaderSpec{
+		Prefix: tx.engine.mappedIndexPrefix(table, table.primaryIndex),
+	})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, _, err = r.ReadBetween(ctx, txID+1, math.MaxUint64)
+	if errors.Is(err, store.ErrNoMoreEntries) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: rows of table '%s' were modified while validating existing ones", store.ErrTxReadConflict, table.name)
+}
+
+type AddColumnStmt struct {
+	table   string
+	colSpec *ColSpec
+}
+
+func NewAddColumnStmt(table string, colSpec *ColSpec) *AddColumnStmt {
+	return &AddColumnStmt{table: table, colSpec: colSpec}
+}
+
+func (stmt *AddColumnStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *AddColumnStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeAlter}
+}
+
+func (stmt *AddColumnStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *AddColumnStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]*ColSpec, 0, len(table.cols)+1)
+	for _, c := range table.cols {
+		cols = append(cols, &ColSpec{colName: c.colName, colType: c.colType, generatedExp: c.generatedExp})
+	}
+	cols = append(cols, stmt.colSpec)
+
+	err = validateColSpecExps(tx, table.name, []*ColSpec{stmt.colSpec}, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	col, err := table.newColumn(stmt.colSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	err = persistColumn(tx, col)
+	if err != nil {
+		return nil, err
+	}
+
+	if col.defaultExp != nil || col.generatedExp != nil {
+		err = fillColumn(ctx, tx, col)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+// fillColumn assigns the default or generated value of a new column to the existing rows of the table
+func fillColumn(ctx context.Context, tx *SQLTx, col *Column) error {
+	table := col.table
+
+	rowReader, err := newRawRowReader(tx, nil, table, period{}, table.name, &ScanSpecs{Index: table.primaryIndex})
+	if err != nil {
+		return err
+	}
+	defer rowReader.Close()
+
+	for {
+		row, err := rowReader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		valuesByColID := make(map[uint32]TypedValue, len(table.cols))
+
+		for _, c := range table.cols {
+			v := row.ValuesBySelector[EncodeSelector("", table.name, c.colName)]
+			if c.id != col.id && v != nil && !v.IsNull() {
+				valuesByColID[c.id] = v
+			}
+		}
+
+		val, err := col.defaultValue(tx)
+		if err != nil {
+			return err
+		}
+
+		if !val.IsNull() {
+			valuesByColID[col.id] = val
+		}
+
+		err = setGeneratedValues(tx, table, valuesByColID)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := valuesByColID[col.id]; !ok && col.notNull {
+			return fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+		}
+
+		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.doUpsert(ctx, pkEncVals, valuesByColID, table, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type RenameTableStmt struct {
@@ -908,6 +1498,18 @@ func (stmt *RenameColumnStmt) execAt(ctx context.Context, tx *SQLTx, params map[
 		return nil, err
 	}
 
+	if col, err := table.GetColumnByName(stmt.oldName); err == nil {
+		for _, c := range table.cols {
+			if c.references(col) {
+				return nil, fmt.Errorf("%w: column %s is required by generated column %s", ErrIllegalArguments, col.colName, c.colName)
+			}
+		}
+
+		if view := tx.catalog.dependentViewOnColumn(table.name, col.colName); view != nil {
+			return nil, fmt.Errorf("%w: column %s is required by view %s", ErrIllegalArguments, col.colName, view.name)
+		}
+	}
+
 	col, err := table.renameColumn(stmt.oldName, stmt.newName)
 	if err != nil {
 		return nil, err
@@ -976,6 +1578,20 @@ func (stmt *DropColumnStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 }
 
 func canDropColumn(tx *SQLTx, table *Table, col *Column) error {
+	if fk, _ := table.foreignKeyIncluding(col.id); fk != nil {
+		return fmt.Errorf("%w %s because %s constraint requires it", ErrCannotDropColumn, col.Name(), fk.name)
+	}
+
+	for _, c := range table.cols {
+		if c.references(col) {
+			return fmt.Errorf("%w %s because generated column %s requires it", ErrCannotDropColumn, col.Name(), c.colName)
+		}
+	}
+
+	if view := tx.catalog.dependentViewOnColumn(table.name, col.colName); view != nil {
+		return fmt.Errorf("%w %s because view %s requires it", ErrCannotDropColumn, col.Name(), view.name)
+	}
+
 	colSpecs := make([]*ColSpec, 0, len(table.Cols())-1)
 	for _, c := range table.cols {
 		if c.id != col.id {
@@ -1010,4818 +1626,8192 @@ func persistColumnDeletion(ctx context.Context, tx *SQLTx, col *Column) error {
 	return tx.delete(ctx, mappedKey)
 }
 
-type DropConstraintStmt struct {
-	table          string
-	constraintName string
+// AlterColumnStmt changes the type, max length or nullability of an existing column.
+// Existing rows are validated against the new definition and, when the type of the column
+// changes, rewritten with their values converted into the new type. Values already present
+// in the history of the rows are converted when read.
+//
+// Index keys are encoded according to the type and max length of the indexed columns,
+// as index entries are built out of every version of the rows, the type of an indexed column
+// can not be changed. Secondary indexes are created again when a VARCHAR or BLOB column is widened,
+// while rows are keyed by their primary key, so only changes preserving its encoding are allowed,
+// i.e. the precision of a DECIMAL column. Columns referenced by foreign keys or included in indexes
+// on JSON paths can not change their key encoding either.
+type AlterColumnStmt struct {
+	table   string
+	colName string
+
+	changeType bool
+	colType    SQLValueType
+	maxLen     int
+
+	changeNullability bool
+	notNull           bool
 }
 
-func (stmt *DropConstraintStmt) readOnly() bool {
+func NewAlterColumnTypeStmt(table, colName string, colType SQLValueType, maxLen int) *AlterColumnStmt {
+	return &AlterColumnStmt{table: table, colName: colName, changeType: true, colType: colType, maxLen: maxLen}
+}
+
+func NewAlterColumnNullabilityStmt(table, colName string, notNull bool) *AlterColumnStmt {
+	return &AlterColumnStmt{table: table, colName: colName, changeNullability: true, notNull: notNull}
+}
+
+func (stmt *AlterColumnStmt) readOnly() bool {
 	return false
 }
 
-func (stmt *DropConstraintStmt) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeDrop}
+func (stmt *AlterColumnStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeAlter}
 }
 
-func (stmt *DropConstraintStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+func (stmt *AlterColumnStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *AlterColumnStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
 	table, err := tx.catalog.GetTableByName(stmt.table)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := table.deleteCheck(stmt.constraintName)
+	col, err := table.GetColumnByName(stmt.colName)
 	if err != nil {
 		return nil, err
 	}
 
-	err = persistCheckDeletion(ctx, tx, table.id, id)
+	// the column as defined before being altered, used to read existing rows
+	prevCol := *col
 
-	tx.mutatedCatalog = true
+	if stmt.changeNullability {
+		if !stmt.notNull && table.primaryIndex.IncludesCol(col.id) {
+			return nil, fmt.Errorf("%w: column %s", ErrPKCanNotBeNull, col.colName)
+		}
 
-	return tx, err
-}
+		if !stmt.notNull || col.notNull {
+			col.notNull = stmt.notNull
 
-func persistCheckDeletion(ctx context.Context, tx *SQLTx, tableID uint32, checkId uint32) error {
-	mappedKey := MapKey(
-		tx.sqlPrefix(),
-		catalogCheckPrefix,
-		EncodeID(DatabaseID),
-		EncodeID(tableID),
-		EncodeID(checkId),
-	)
-	return tx.delete(ctx, mappedKey)
-}
+			tx.mutatedCatalog = true
 
-func (stmt *DropConstraintStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	return nil
-}
+			return tx, persistColumn(tx, col)
+		}
 
-type UpsertIntoStmt struct {
-	isInsert   bool
-	tableRef   *tableRef
-	cols       []string
-	ds         DataSource
-	onConflict *OnConflictDo
-}
+		col.notNull = true
+	}
 
-func (stmt *UpsertIntoStmt) readOnly() bool {
-	return false
-}
+	if stmt.changeType {
+		err = canAlterColumnType(tx, table, col, stmt.colType, stmt.maxLen)
+		if err != nil {
+			return nil, err
+		}
 
-func (stmt *UpsertIntoStmt) requiredPrivileges() []SQLPrivilege {
-	privileges := stmt.privileges()
-	if stmt.ds != nil {
-		privileges = append(privileges, stmt.ds.requiredPrivileges()...)
+		col.colType = stmt.colType
+		col.maxLen = stmt.maxLen
 	}
-	return privileges
-}
 
-func (stmt *UpsertIntoStmt) privileges() []SQLPrivilege {
-	if stmt.isInsert {
-		return []SQLPrivilege{SQLPrivilegeInsert}
+	err = alterColumn(ctx, tx, table, &prevCol, col)
+	if err != nil {
+		return nil, err
 	}
-	return []SQLPrivilege{SQLPrivilegeInsert, SQLPrivilegeUpdate}
-}
 
-func NewUpsertIntoStmt(table string, cols []string, ds DataSource, isInsert bool, onConflict *OnConflictDo) *UpsertIntoStmt {
-	return &UpsertIntoStmt{
-		isInsert:   isInsert,
-		tableRef:   NewTableRef(table, ""),
-		cols:       cols,
-		ds:         ds,
-		onConflict: onConflict,
-	}
-}
+	if col.colType != prevCol.colType {
+		err = persistColumnDeletion(ctx, tx, &prevCol)
+		if err != nil {
+			return nil, err
+		}
 
-type RowSpec struct {
-	Values []ValueExp
-}
+		err = persistColumnTypeChange(tx, col, prevCol.colType)
+		if err != nil {
+			return nil, err
+		}
 
-func NewRowSpec(values []ValueExp) *RowSpec {
-	return &RowSpec{
-		Values: values,
+		col.typeChanges = append(col.typeChanges, colTypeChange{untilTx: math.MaxUint64, colType: prevCol.colType})
 	}
-}
 
-type OnConflictDo struct{}
+	err = persistColumn(tx, col)
+	if err != nil {
+		return nil, err
+	}
 
-func (stmt *UpsertIntoStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	ds, ok := stmt.ds.(*valuesDataSource)
-	if !ok {
-		return stmt.ds.inferParameters(ctx, tx, params)
+	if !keyEncodingPreserved(&prevCol, col) {
+		err = recreateIndexes(ctx, tx, table, col)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	emptyDescriptors := make(map[string]ColDescriptor)
-	for _, row := range ds.rows {
-		if len(stmt.cols) != len(row.Values) {
-			return ErrInvalidNumberOfValues
-		}
+	tx.mutatedCatalog = true
 
-		for i, val := range row.Values {
-			table, err := stmt.tableRef.referencedTable(tx)
-			if err != nil {
-				return err
-			}
+	return tx, nil
+}
 
-			col, err := table.GetColumnByName(stmt.cols[i])
-			if err != nil {
-				return err
-			}
+// keyEncodingPreserved returns true if values of the column are encoded into the same index keys
+// after the column is altered, decimal keys have a fixed length regardless of the precision of the column
+func keyEncodingPreserved(prevCol, col *Column) bool {
+	if prevCol.colType != col.colType {
+		return false
+	}
 
-			err = val.requiresType(col.colType, emptyDescriptors, params, table.name)
-			if err != nil {
-				return err
-			}
-		}
+	if col.colType == DecimalType {
+		return prevCol.Scale() == col.Scale()
 	}
-	return nil
+
+	return prevCol.MaxLen() == col.MaxLen()
 }
 
-func (stmt *UpsertIntoStmt) validate(table *Table) (map[uint32]int, error) {
-	selPosByColID := make(map[uint32]int, len(stmt.cols))
+// reindexable returns true if the indexes of the column can be created again with keys encoded according
+// to its new definition. As index entries are built out of every version of the rows, values must be
+// decoded in the same way and fit into the new keys, which only holds when a VARCHAR or BLOB column is widened
+func reindexable(prevCol, col *Column) bool {
+	return prevCol.colType == col.colType &&
+		variableSizedType(col.colType) &&
+		col.MaxLen() > prevCol.MaxLen() &&
+		col.MaxLen() <= MaxKeyLen
+}
 
-	for i, c := range stmt.cols {
-		col, err := table.GetColumnByName(c)
-		if err != nil {
-			return nil, err
+// recreateIndexes drops and creates again the secondary indexes of the column,
+// so that their entries get built with keys encoded according to the new definition of the column
+func recreateIndexes(ctx context.Context, tx *SQLTx, table *Table, col *Column) error {
+	indexes := make([]*Index, len(table.indexesByColID[col.id]))
+	copy(indexes, table.indexesByColID[col.id])
+
+	for _, index := range indexes {
+		cols := make([]string, len(index.cols))
+		for i, c := range index.cols {
+			cols[i] = c.colName
 		}
 
-		_, duplicated := selPosByColID[col.id]
-		if duplicated {
-			return nil, fmt.Errorf("%w (%s)", ErrDuplicatedColumn, col.colName)
+		_, err := (&DropIndexStmt{table: table.name, cols: cols}).execAt(ctx, tx, nil)
+		if err != nil {
+			return err
 		}
 
-		selPosByColID[col.id] = i
+		_, err = (&CreateIndexStmt{unique: index.unique, table: table.name, cols: cols}).execAt(ctx, tx, nil)
+		if err != nil {
+			return err
+		}
 	}
-
-	return selPosByColID, nil
+	return nil
 }
 
-func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	table, err := stmt.tableRef.referencedTable(tx)
-	if err != nil {
-		return nil, err
+func canAlterColumnType(tx *SQLTx, table *Table, col *Column, colType SQLValueType, maxLen int) error {
+	if colType == IntervalType {
+		return ErrIntervalColumnNotSupported
 	}
 
-	selPosByColID, err := stmt.validate(table)
-	if err != nil {
-		return nil, err
+	if !validMaxLenForType(maxLen, colType) {
+		return ErrLimitedMaxLen
 	}
 
-	r := &Row{
-		ValuesByPosition: make([]TypedValue, len(table.cols)),
-		ValuesBySelector: make(map[string]TypedValue),
+	if col.colType == colType && col.MaxLen() == maxLen {
+		return nil
 	}
 
-	reader, err := stmt.ds.Resolve(ctx, tx, params, nil)
-	if err != nil {
-		return nil, err
+	if col.autoIncrement {
+		return fmt.Errorf("%w %s because it is auto incremental", ErrCannotAlterColumn, col.colName)
 	}
-	defer reader.Close()
 
-	for {
-		row, err := reader.Read(ctx)
-		if errors.Is(err, ErrNoMoreRows) {
-			break
-		}
+	altered := *col
+	altered.colType = colType
+	altered.maxLen = maxLen
+
+	if !keyEncodingPreserved(col, &altered) {
+		err := canReindexColumn(tx, table, col, &altered)
 		if err != nil {
-			return nil, err
+			return err
 		}
+	}
 
-		if len(row.ValuesByPosition) != len(stmt.cols) {
-			return nil, ErrInvalidNumberOfValues
+	if fk, _ := table.foreignKeyIncluding(col.id); fk != nil {
+		return fmt.Errorf("%w %s because %s constraint requires it", ErrCannotAlterColumn, col.colName, fk.name)
+	}
+
+	_, err := getConverter(col.colType, colType)
+	if err != nil {
+		return fmt.Errorf("%w %s: %v", ErrCannotAlterColumn, col.colName, err)
+	}
+
+	// default values and generation expressions must be valid for the new type
+	specs := make([]*ColSpec, 0, len(table.cols))
+	cols := make([]*ColSpec, 0, len(table.cols))
+
+	for _, c := range table.cols {
+		spec := &ColSpec{
+			colName:      c.colName,
+			colType:      c.colType,
+			maxLen:       c.maxLen,
+			defaultExp:   c.defaultExp,
+			generatedExp: c.generatedExp,
 		}
 
-		valuesByColID := make(map[uint32]TypedValue)
+		if c.id == col.id {
+			spec.colType = colType
+			spec.maxLen = maxLen
+		}
 
-		var pkMustExist bool
+		cols = append(cols, spec)
 
-		for colID, col := range table.colsByID {
-			colPos, specified := selPosByColID[colID]
-			if !specified {
-				// TODO: Default values
-				if col.notNull && !col.autoIncrement {
-					return nil, fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
-				}
+		if c.defaultExp != nil || c.generatedExp != nil {
+			specs = append(specs, spec)
+		}
+	}
 
-				// inject auto-incremental pk value
-				if stmt.isInsert && col.autoIncrement {
-					// current implementation assumes only PK can be set as autoincremental
-					table.maxPK++
+	err = validateColSpecExps(tx, table.name, specs, cols)
+	if err != nil {
+		return err
+	}
 
-					pkCol := table.primaryIndex.cols[0]
-					valuesByColID[pkCol.id] = &Integer{val: table.maxPK}
+	row := zeroRow(table.name, cols)
+	for name, check := range table.checkConstraints {
+		_, err := check.exp.reduce(tx, row, table.name)
+		if err != nil {
+			return fmt.Errorf("%w %s because %s constraint requires it", ErrCannotAlterColumn, col.colName, name)
+		}
+	}
+	return nil
+}
 
-					if _, ok := tx.firstInsertedPKs[table.name]; !ok {
-						tx.firstInsertedPKs[table.name] = table.maxPK
-					}
-					tx.lastInsertedPKs[table.name] = table.maxPK
-				}
+// canReindexColumn checks that the indexes of the column can be created again according to its new definition
+func canReindexColumn(tx *SQLTx, table *Table, prevCol, col *Column) error {
+	for _, index := range table.indexesByColID[col.id] {
+		if index.IsPrimary() {
+			return fmt.Errorf("%w %s because the primary key requires it", ErrCannotAlterColumn, col.colName)
+		}
 
-				continue
-			}
+		if !reindexable(prevCol, col) {
+			return fmt.Errorf("%w %s because index %s requires it", ErrCannotAlterColumn, col.colName, index.Name())
+		}
 
-			// value was specified
-			cVal := row.ValuesByPosition[colPos]
+		indexKeyLen := 0
 
-			val, err := cVal.substitute(params)
-			if err != nil {
-				return nil, err
+		for _, c := range index.cols {
+			if c.jsonCol != nil {
+				return fmt.Errorf("%w %s because index %s requires it", ErrCannotAlterColumn, col.colName, index.Name())
 			}
 
-			rval, err := val.reduce(tx, nil, table.name)
-			if err != nil {
-				return nil, err
+			switch {
+			case c.id == col.id:
+				indexKeyLen += col.MaxLen()
+			case c.colType == DecimalType:
+				indexKeyLen += decimalKeyLen
+			default:
+				indexKeyLen += c.MaxLen()
 			}
+		}
 
-			if rval.IsNull() {
-				if col.notNull || col.autoIncrement {
-					return nil, fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
-				}
+		if indexKeyLen > MaxKeyLen {
+			return fmt.Errorf("%w %s because index %s requires it: %v", ErrCannotAlterColumn, col.colName, index.Name(), ErrLimitedKeyType)
+		}
+	}
 
-				continue
-			}
+	for _, fk := range tx.catalog.referencingForeignKeys(table) {
+		if fk.refIndex.IncludesCol(col.id) {
+			return fmt.Errorf("%w %s because %s constraint of table %s requires it", ErrCannotAlterColumn, col.colName, fk.name, fk.table.name)
+		}
+	}
+	return nil
+}
 
-			if col.autoIncrement {
-				// validate specified value
-				nl, isNumber := rval.RawValue().(int64)
-				if !isNumber {
-					return nil, fmt.Errorf("%w (expecting numeric value)", ErrInvalidValue)
-				}
+// alterColumn validates the existing rows of the table against the new definition of the column,
+// rewriting them when the type of the column changes
+func alterColumn(ctx context.Context, tx *SQLTx, table *Table, prevCol, col *Column) error {
+	// values are rounded when the scale of a decimal column changes
+	typeChanged := col.colType != prevCol.colType || (ArrayElemType(col.colType) == DecimalType && col.maxLen != prevCol.maxLen)
+	narrowed := col.MaxLen() > 0 && (prevCol.MaxLen() == 0 || col.MaxLen() < prevCol.MaxLen())
 
-				pkMustExist = nl <= table.maxPK
+	if !typeChanged && !narrowed && (!col.notNull || prevCol.notNull) {
+		return nil
+	}
 
-				if _, ok := tx.firstInsertedPKs[table.name]; !ok {
-					tx.firstInsertedPKs[table.name] = nl
-				}
-				tx.lastInsertedPKs[table.name] = nl
-			}
+	// existing values must be preserved, as they are read from the history of rows as well
+	conv, err := getExactConverter(prevCol.colType, col.colType)
+	if err != nil {
+		return err
+	}
 
-			valuesByColID[colID] = rval
+	prevTable := table.withColumn(prevCol)
+
+	rowReader, err := newRawRowReader(tx, nil, prevTable, period{}, table.name, &ScanSpecs{Index: prevTable.primaryIndex})
+	if err != nil {
+		return err
+	}
+	defer rowReader.Close()
+
+	for {
+		row, err := rowReader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return err
 		}
 
-		for i, col := range table.cols {
-			v := valuesByColID[col.id]
+		valuesByColID := make(map[uint32]TypedValue, len(table.cols))
 
-			if v == nil {
-				v = NewNull(AnyType)
-			} else if len(table.checkConstraints) > 0 && col.Type() == JSONType {
-				s, _ := v.RawValue().(string)
-				jsonVal, err := NewJsonFromString(s)
-				if err != nil {
-					return nil, err
-				}
-				v = jsonVal
+		for _, c := range table.cols {
+			v := row.ValuesBySelector[EncodeSelector("", table.name, c.colName)]
+			if v != nil && !v.IsNull() {
+				valuesByColID[c.id] = v
 			}
+		}
 
-			r.ValuesByPosition[i] = v
-			r.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = v
+		val, ok := valuesByColID[col.id]
+		if !ok {
+			if col.notNull {
+				return fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+			}
+			continue
 		}
 
-		if err := checkConstraints(tx, table.checkConstraints, r, table.name); err != nil {
-			return nil, err
+		cval, err := conv(val)
+		if err != nil {
+			return fmt.Errorf("%w %s: %v", ErrCannotAlterColumn, col.colName, err)
 		}
 
-		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		_, err = EncodeValue(cval, col.colType, col.MaxLen())
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("%w %s: %v", ErrCannotAlterColumn, col.colName, err)
 		}
 
-		// pk entry
-		mappedPKey := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id), pkEncVals, pkEncVals)
-		if len(mappedPKey) > MaxKeyLen {
-			return nil, ErrMaxKeyLengthExceeded
+		fitted, err := col.fitValue(cval)
+		if err != nil {
+			return fmt.Errorf("%w %s: %v", ErrCannotAlterColumn, col.colName, err)
 		}
 
-		_, err = tx.get(ctx, mappedPKey)
-		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
-			return nil, err
+		if r, err := fitted.Compare(cval); err != nil || r != 0 {
+			return fmt.Errorf("%w %s: %s can not be converted exactly into %s", ErrCannotAlterColumn, col.colName, val.String(), col.Type())
 		}
 
-		if errors.Is(err, store.ErrKeyNotFound) && pkMustExist {
-			return nil, fmt.Errorf("%w: specified value must be greater than current one", ErrInvalidValue)
+		if !typeChanged {
+			continue
 		}
 
-		if stmt.isInsert {
-			if err == nil && stmt.onConflict == nil {
-				return nil, store.ErrKeyAlreadyExists
-			}
+		valuesByColID[col.id] = cval
 
-			if err == nil && stmt.onConflict != nil {
-				// TODO: conflict resolution may be extended. Currently only supports "ON CONFLICT DO NOTHING"
-				continue
-			}
+		err = setGeneratedValues(tx, table, valuesByColID)
+		if err != nil {
+			return err
 		}
 
-		err = tx.doUpsert(ctx, pkEncVals, valuesByColID, table, !stmt.isInsert)
-		if err != nil {
-			return nil, err
+		r := &Row{
+			ValuesByPosition: make([]TypedValue, len(table.cols)),
+			ValuesBySelector: make(map[string]TypedValue, len(table.cols)),
 		}
-	}
-	return tx, nil
-}
 
-func checkConstraints(tx *SQLTx, checks map[string]CheckConstraint, row *Row, table string) error {
-	for _, check := range checks {
-		val, err := check.exp.reduce(tx, row, table)
-		if err != nil {
-			return fmt.Errorf("%w: %s", ErrCheckConstraintViolation, err)
+		for i, c := range table.cols {
+			v, ok := valuesByColID[c.id]
+			if !ok {
+				v = &NullValue{t: c.colType}
+			}
+
+			r.ValuesByPosition[i] = v
+			r.ValuesBySelector[EncodeSelector("", table.name, c.colName)] = v
 		}
 
-		if val.Type() != BooleanType {
-			return ErrInvalidCheckConstraint
+		if err := checkConstraints(tx, table.checkConstraints, r, table.name); err != nil {
+			return err
 		}
 
-		if !val.RawValue().(bool) {
-			return fmt.Errorf("%w: %s", ErrCheckConstraintViolation, check.exp.String())
+		err = tx.rewriteRow(valuesByColID, table)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (tx *SQLTx) encodeRowValue(valuesByColID map[uint32]TypedValue, table *Table) ([]byte, error) {
-	valbuf := bytes.Buffer{}
+// rewriteRow encodes the row and the entries of the secondary indexes again, as done when the type of
+// a non-indexed column is altered, index keys are thus unchanged while indexed values are the new ones
+func (tx *SQLTx) rewriteRow(valuesByColID map[uint32]TypedValue, table *Table) error {
+	tx.tableWritten(table)
 
-	// null values are not serialized
-	encodedVals := 0
-	for _, v := range valuesByColID {
-		if !v.IsNull() {
-			encodedVals++
-		}
+	pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+	if err != nil {
+		return err
 	}
 
-	b := make([]byte, EncLenLen)
-	binary.BigEndian.PutUint32(b, uint32(encodedVals))
-
-	_, err := valbuf.Write(b)
+	encodedRowValue, err := tx.encodeRowValue(valuesByColID, table)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	for _, col := range table.cols {
-		rval, specified := valuesByColID[col.id]
-		if !specified || rval.IsNull() {
-			continue
-		}
+	rowKey := MapKey(tx.sqlPrefix(), RowPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(PKIndexID), pkEncVals)
 
-		b := make([]byte, EncIDLen)
-		binary.BigEndian.PutUint32(b, uint32(col.id))
+	err = tx.set(rowKey, nil, encodedRowValue)
+	if err != nil {
+		return err
+	}
 
-		_, err = valbuf.Write(b)
-		if err != nil {
-			return nil, fmt.Errorf("%w: table: %s, column: %s", err, table.name, col.colName)
+	for _, index := range table.indexes {
+		if index.IsPrimary() {
+			continue
 		}
 
-		encVal, err := EncodeValue(rval, col.colType, col.MaxLen())
+		smkey, err := tx.indexEntryKey(index, valuesByColID)
 		if err != nil {
-			return nil, fmt.Errorf("%w: table: %s, column: %s", err, table.name, col.colName)
+			return err
 		}
 
-		_, err = valbuf.Write(encVal)
+		err = tx.setTransient(smkey, nil, encodedRowValue)
 		if err != nil {
-			return nil, fmt.Errorf("%w: table: %s, column: %s", err, table.name, col.colName)
+			return err
 		}
 	}
+	return nil
+}
 
-	return valbuf.Bytes(), nil
+func persistColumnTypeChange(tx *SQLTx, col *Column, prevType SQLValueType) error {
+	mappedKey := MapKey(
+		tx.sqlPrefix(),
+		catalogColumnTypePrefix,
+		EncodeID(DatabaseID),
+		EncodeID(col.table.id),
+		EncodeID(col.id),
+		EncodeID(uint32(len(col.typeChanges))),
+	)
+
+	// untilTx is set to the transaction in which the change gets committed when loading the catalog
+	v := make([]byte, 8+len(prevType))
+	copy(v[8:], prevType)
+
+	return tx.set(mappedKey, nil, v)
 }
 
-func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID map[uint32]TypedValue, table *Table, reuseIndex bool) error {
-	var reusableIndexEntries map[uint32]struct{}
+type DropConstraintStmt struct {
+	table          string
+	constraintName string
+}
 
-	if reuseIndex && len(table.indexes) > 1 {
-		currPKRow, err := tx.fetchPKRow(ctx, table, valuesByColID)
-		if err == nil {
-			currValuesByColID := make(map[uint32]TypedValue, len(currPKRow.ValuesBySelector))
+func (stmt *DropConstraintStmt) readOnly() bool {
+	return false
+}
 
-			for _, col := range table.cols {
-				encSel := EncodeSelector("", table.name, col.colName)
-				currValuesByColID[col.id] = currPKRow.ValuesBySelector[encSel]
-			}
+func (stmt *DropConstraintStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeDrop}
+}
 
-			reusableIndexEntries, err = tx.deprecateIndexEntries(pkEncVals, currValuesByColID, valuesByColID, table)
-			if err != nil {
-				return err
-			}
-		} else if !errors.Is(err, ErrNoMoreRows) {
-			return err
-		}
+func (stmt *DropConstraintStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
 	}
 
-	rowKey := MapKey(tx.sqlPrefix(), RowPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(PKIndexID), pkEncVals)
+	if table.getForeignKey(stmt.constraintName) != nil {
+		id, err := table.deleteForeignKey(stmt.constraintName)
+		if err != nil {
+			return nil, err
+		}
 
-	encodedRowValue, err := tx.encodeRowValue(valuesByColID, table)
-	if err != nil {
-		return err
+		err = persistForeignKeyDeletion(ctx, tx, table.id, id)
+
+		tx.mutatedCatalog = true
+
+		return tx, err
 	}
 
-	err = tx.set(rowKey, nil, encodedRowValue)
+	id, err := table.deleteCheck(stmt.constraintName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// create in-memory and validate entries for secondary indexes
-	for _, index := range table.indexes {
-		if index.IsPrimary() {
-			continue
-		}
+	err = persistCheckDeletion(ctx, tx, table.id, id)
 
-		if reusableIndexEntries != nil {
-			_, reusable := reusableIndexEntries[index.id]
-			if reusable {
-				continue
-			}
-		}
+	tx.mutatedCatalog = true
 
-		encodedValues := make([][]byte, 2+len(index.cols))
-		encodedValues[0] = EncodeID(table.id)
-		encodedValues[1] = EncodeID(index.id)
+	return tx, err
+}
 
-		indexKeyLen := 0
+func persistCheckDeletion(ctx context.Context, tx *SQLTx, tableID uint32, checkId uint32) error {
+	mappedKey := MapKey(
+		tx.sqlPrefix(),
+		catalogCheckPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(tableID),
+		EncodeID(checkId),
+	)
+	return tx.delete(ctx, mappedKey)
+}
 
-		for i, col := range index.cols {
-			rval, specified := valuesByColID[col.id]
-			if !specified {
-				rval = &NullValue{t: col.colType}
-			}
+func (stmt *DropConstraintStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
 
-			encVal, n, err := EncodeValueAsKey(rval, col.colType, col.MaxLen())
-			if err != nil {
-				return fmt.Errorf("%w: index on '%s' and column '%s'", err, index.Name(), col.colName)
-			}
+type UpsertIntoStmt struct {
+	isInsert   bool
+	tableRef   *tableRef
+	cols       []string
+	ds         DataSource
+	onConflict *OnConflictDo
+}
 
-			if n > MaxKeyLen {
-				return fmt.Errorf("%w: can not index entry for column '%s'. Max key length for variable columns is %d", ErrLimitedKeyType, col.colName, MaxKeyLen)
-			}
+func (stmt *UpsertIntoStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *UpsertIntoStmt) requiredPrivileges() []SQLPrivilege {
+	privileges := stmt.privileges()
+	if stmt.ds != nil {
+		privileges = append(privileges, stmt.ds.requiredPrivileges()...)
+	}
+	return privileges
+}
 
-			indexKeyLen += n
+func (stmt *UpsertIntoStmt) privileges() []SQLPrivilege {
+	if stmt.isInsert {
+		return []SQLPrivilege{SQLPrivilegeInsert}
+	}
+	return []SQLPrivilege{SQLPrivilegeInsert, SQLPrivilegeUpdate}
+}
 
-			encodedValues[i+2] = encVal
-		}
+func NewUpsertIntoStmt(table string, cols []string, ds DataSource, isInsert bool, onConflict *OnConflictDo) *UpsertIntoStmt {
+	return &UpsertIntoStmt{
+		isInsert:   isInsert,
+		tableRef:   NewTableRef(table, ""),
+		cols:       cols,
+		ds:         ds,
+		onConflict: onConflict,
+	}
+}
 
-		if indexKeyLen > MaxKeyLen {
-			return fmt.Errorf("%w: can not index entry using columns '%v'. Max key length is %d", ErrLimitedKeyType, index.cols, MaxKeyLen)
+type RowSpec struct {
+	Values []ValueExp
+}
+
+func NewRowSpec(values []ValueExp) *RowSpec {
+	return &RowSpec{
+		Values: values,
+	}
+}
+
+type OnConflictDo struct{}
+
+func (stmt *UpsertIntoStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	ds, ok := stmt.ds.(*valuesDataSource)
+	if !ok {
+		return stmt.ds.inferParameters(ctx, tx, params)
+	}
+
+	emptyDescriptors := make(map[string]ColDescriptor)
+	for _, row := range ds.rows {
+		if len(stmt.cols) != len(row.Values) {
+			return ErrInvalidNumberOfValues
 		}
 
-		smkey := MapKey(tx.sqlPrefix(), MappedPrefix, encodedValues...)
+		for i, val := range row.Values {
+			table, err := stmt.tableRef.referencedTable(tx)
+			if err != nil {
+				return err
+			}
 
-		// no other equivalent entry should be already indexed
-		if index.IsUnique() {
-			_, valRef, err := tx.getWithPrefix(ctx, smkey, nil)
-			if err == nil && (valRef.KVMetadata() == nil || !valRef.KVMetadata().Deleted()) {
-				return store.ErrKeyAlreadyExists
-			} else if !errors.Is(err, store.ErrKeyNotFound) {
+			col, err := table.GetColumnByName(stmt.cols[i])
+			if err != nil {
 				return err
 			}
-		}
 
-		err = tx.setTransient(smkey, nil, encodedRowValue) // only-indexable
-		if err != nil {
-			return err
+			err = val.requiresType(tx, col.colType, emptyDescriptors, params, table.name)
+			if err != nil {
+				return err
+			}
 		}
 	}
-
-	tx.updatedRows++
-
 	return nil
 }
 
-func encodedKey(index *Index, valuesByColID map[uint32]TypedValue) ([]byte, error) {
-	valbuf := bytes.Buffer{}
-
-	indexKeyLen := 0
-
-	for _, col := range index.cols {
-		rval, specified := valuesByColID[col.id]
-		if !specified || rval.IsNull() {
-			return nil, ErrPKCanNotBeNull
-		}
+func (stmt *UpsertIntoStmt) validate(table *Table) (map[uint32]int, error) {
+	selPosByColID := make(map[uint32]int, len(stmt.cols))
 
-		encVal, n, err := EncodeValueAsKey(rval, col.colType, col.MaxLen())
+	for i, c := range stmt.cols {
+		col, err := table.GetColumnByName(c)
 		if err != nil {
-			return nil, fmt.Errorf("%w: index of table '%s' and column '%s'", err, index.table.name, col.colName)
+			return nil, err
 		}
 
-		if n > MaxKeyLen {
-			return nil, fmt.Errorf("%w: invalid key entry for column '%s'. Max key length for variable columns is %d", ErrLimitedKeyType, col.colName, MaxKeyLen)
+		_, duplicated := selPosByColID[col.id]
+		if duplicated {
+			return nil, fmt.Errorf("%w (%s)", ErrDuplicatedColumn, col.colName)
 		}
 
-		indexKeyLen += n
-
-		_, err = valbuf.Write(encVal)
-		if err != nil {
-			return nil, err
+		if col.generatedExp != nil {
+			return nil, fmt.Errorf("%w (%s)", ErrGeneratedColumnCannotBeSet, col.colName)
 		}
-	}
 
-	if indexKeyLen > MaxKeyLen {
-		return nil, fmt.Errorf("%w: invalid key entry using columns '%v'. Max key length is %d", ErrLimitedKeyType, index.cols, MaxKeyLen)
+		selPosByColID[col.id] = i
 	}
 
-	return valbuf.Bytes(), nil
+	return selPosByColID, nil
 }
 
-func (tx *SQLTx) fetchPKRow(ctx context.Context, table *Table, valuesByColID map[uint32]TypedValue) (*Row, error) {
-	pkRanges := make(map[uint32]*typedValueRange, len(table.primaryIndex.cols))
-
-	for _, pkCol := range table.primaryIndex.cols {
-		pkVal := valuesByColID[pkCol.id]
+func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := stmt.tableRef.referencedTable(tx)
+	if err != nil {
+		return nil, err
+	}
 
-		pkRanges[pkCol.id] = &typedValueRange{
-			lRange: &typedValueSemiRange{val: pkVal, inclusive: true},
-			hRange: &typedValueSemiRange{val: pkVal, inclusive: true},
-		}
+	selPosByColID, err := stmt.validate(table)
+	if err != nil {
+		return nil, err
 	}
 
-	scanSpecs := &ScanSpecs{
-		Index:         table.primaryIndex,
-		rangesByColID: pkRanges,
+	r := &Row{
+		ValuesByPosition: make([]TypedValue, len(table.cols)),
+		ValuesBySelector: make(map[string]TypedValue),
 	}
 
-	r, err := newRawRowReader(tx, nil, table, period{}, table.name, scanSpecs)
+	reader, err := stmt.ds.Resolve(ctx, tx, params, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer reader.Close()
 
-	defer func() {
-		r.Close()
-	}()
+	referencing := tx.catalog.referencingForeignKeys(table)
 
-	return r.Read(ctx)
-}
-
-// deprecateIndexEntries mark previous index entries as deleted
-func (tx *SQLTx) deprecateIndexEntries(
-	pkEncVals []byte,
-	currValuesByColID, newValuesByColID map[uint32]TypedValue,
-	table *Table) (reusableIndexEntries map[uint32]struct{}, err error) {
-
-	encodedRowValue, err := tx.encodeRowValue(currValuesByColID, table)
-	if err != nil {
-		return nil, err
-	}
+	// rows to be checked against foreign keys once all of them are written
+	var written, removed []map[uint32]TypedValue
 
-	reusableIndexEntries = make(map[uint32]struct{})
+	for {
+		row, err := reader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	for _, index := range table.indexes {
-		if index.IsPrimary() {
-			continue
+		if len(row.ValuesByPosition) != len(stmt.cols) {
+			return nil, ErrInvalidNumberOfValues
 		}
 
-		encodedValues := make([][]byte, 2+len(index.cols)+1)
-		encodedValues[0] = EncodeID(table.id)
-		encodedValues[1] = EncodeID(index.id)
-		encodedValues[len(encodedValues)-1] = pkEncVals
+		valuesByColID := make(map[uint32]TypedValue)
 
-		// existent index entry is deleted only if it differs from existent one
-		sameIndexKey := true
+		var pkMustExist bool
 
-		for i, col := range index.cols {
-			currVal, specified := currValuesByColID[col.id]
+		for colID, col := range table.colsByID {
+			colPos, specified := selPosByColID[colID]
 			if !specified {
-				currVal = &NullValue{t: col.colType}
-			}
+				if col.defaultExp != nil {
+					val, err := col.defaultValue(tx)
+					if err != nil {
+						return nil, err
+					}
 
-			newVal, specified := newValuesByColID[col.id]
-			if !specified {
-				newVal = &NullValue{t: col.colType}
-			}
+					if val.IsNull() && col.notNull {
+						return nil, fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+					}
 
-			r, err := currVal.Compare(newVal)
-			if err != nil {
-				return nil, err
-			}
+					if !val.IsNull() {
+						valuesByColID[colID] = val
+					}
+					continue
+				}
 
-			sameIndexKey = sameIndexKey && r == 0
+				// generated columns are computed once all the other values are known
+				if col.generatedExp != nil {
+					continue
+				}
 
-			encVal, _, _ := EncodeValueAsKey(currVal, col.colType, col.MaxLen())
+				if col.notNull && !col.autoIncrement {
+					return nil, fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+				}
 
-			encodedValues[i+3] = encVal
-		}
+				// inject auto-incremental pk value
+				if stmt.isInsert && col.autoIncrement {
+					// current implementation assumes only PK can be set as autoincremental
+					table.maxPK++
 
-		// mark existent index entry as deleted
-		if sameIndexKey {
-			reusableIndexEntries[index.id] = struct{}{}
-		} else {
-			md := store.NewKVMetadata()
+					pkCol := table.primaryIndex.cols[0]
+					valuesByColID[pkCol.id] = &Integer{val: table.maxPK}
 
-			md.AsDeleted(true)
+					if _, ok := tx.firstInsertedPKs[table.name]; !ok {
+						tx.firstInsertedPKs[table.name] = table.maxPK
+					}
+					tx.lastInsertedPKs[table.name] = table.maxPK
+				}
 
-			err = tx.set(MapKey(tx.sqlPrefix(), MappedPrefix, encodedValues...), md, encodedRowValue)
-			if err != nil {
-				return nil, err
+				continue
 			}
-		}
-	}
 
-	return reusableIndexEntries, nil
-}
+			// value was specified
+			cVal := row.ValuesByPosition[colPos]
 
-type UpdateStmt struct {
-	tableRef *tableRef
-	where    ValueExp
-	updates  []*colUpdate
-	indexOn  []string
-	limit    ValueExp
-	offset   ValueExp
-}
+			val, err := cVal.substitute(params)
+			if err != nil {
+				return nil, err
+			}
 
-type colUpdate struct {
-	col string
-	op  CmpOperator
-	val ValueExp
-}
+			rval, err := val.reduce(tx, nil, table.name)
+			if err != nil {
+				return nil, err
+			}
 
-func (stmt *UpdateStmt) readOnly() bool {
-	return false
-}
+			if rval.IsNull() {
+				if col.notNull || col.autoIncrement {
+					return nil, fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+				}
 
-func (stmt *UpdateStmt) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeUpdate}
-}
+				continue
+			}
 
-func (stmt *UpdateStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	selectStmt := &SelectStmt{
-		ds:    stmt.tableRef,
-		where: stmt.where,
-	}
+			if col.autoIncrement {
+				// validate specified value
+				nl, isNumber := rval.RawValue().(int64)
+				if !isNumber {
+					return nil, fmt.Errorf("%w (expecting numeric value)", ErrInvalidValue)
+				}
 
-	err := selectStmt.inferParameters(ctx, tx, params)
-	if err != nil {
-		return err
-	}
+				pkMustExist = nl <= table.maxPK
 
-	table, err := stmt.tableRef.referencedTable(tx)
-	if err != nil {
-		return err
-	}
+				if _, ok := tx.firstInsertedPKs[table.name]; !ok {
+					tx.firstInsertedPKs[table.name] = nl
+				}
+				tx.lastInsertedPKs[table.name] = nl
+			}
 
-	for _, update := range stmt.updates {
-		col, err := table.GetColumnByName(update.col)
-		if err != nil {
-			return err
+			valuesByColID[colID] = rval
 		}
 
-		err = update.val.requiresType(col.colType, make(map[string]ColDescriptor), params, table.name)
+		err = setGeneratedValues(tx, table, valuesByColID)
 		if err != nil {
-			return err
+			return nil, err
 		}
-	}
 
-	return nil
-}
+		for i, col := range table.cols {
+			v := valuesByColID[col.id]
 
-func (stmt *UpdateStmt) validate(table *Table) error {
-	colIDs := make(map[uint32]struct{}, len(stmt.updates))
+			if v == nil {
+				v = NewNull(AnyType)
+			} else if len(table.checkConstraints) > 0 && col.Type() == JSONType {
+				s, _ := v.RawValue().(string)
+				jsonVal, err := NewJsonFromString(s)
+				if err != nil {
+					return nil, err
+				}
+				v = jsonVal
+			}
 
-	for _, update := range stmt.updates {
-		if update.op != EQ {
-			return ErrIllegalArguments
+			r.ValuesByPosition[i] = v
+			r.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = v
 		}
 
-		col, err := table.GetColumnByName(update.col)
-		if err != nil {
-			return err
+		if err := checkConstraints(tx, table.checkConstraints, r, table.name); err != nil {
+			return nil, err
 		}
 
-		if table.PrimaryIndex().IncludesCol(col.id) {
-			return ErrPKCanNotBeUpdated
+		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		if err != nil {
+			return nil, err
 		}
 
-		_, duplicated := colIDs[col.id]
-		if duplicated {
-			return ErrDuplicatedColumn
+		// pk entry
+		mappedPKey := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id), pkEncVals, pkEncVals)
+		if len(mappedPKey) > MaxKeyLen {
+			return nil, ErrMaxKeyLengthExceeded
 		}
 
-		colIDs[col.id] = struct{}{}
-	}
-
-	return nil
-}
-
-func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	selectStmt := &SelectStmt{
-		ds:      stmt.tableRef,
-		where:   stmt.where,
-		indexOn: stmt.indexOn,
-		limit:   stmt.limit,
-		offset:  stmt.offset,
-	}
-
-	rowReader, err := selectStmt.Resolve(ctx, tx, params, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer rowReader.Close()
-
-	table := rowReader.ScanSpecs().Index.table
-
-	err = stmt.validate(table)
-	if err != nil {
-		return nil, err
-	}
-
-	cols, err := rowReader.colsBySelector(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		row, err := rowReader.Read(ctx)
-		if errors.Is(err, ErrNoMoreRows) {
-			break
-		} else if err != nil {
+		_, err = tx.get(ctx, mappedPKey)
+		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
 			return nil, err
 		}
 
-		valuesByColID := make(map[uint32]TypedValue, len(row.ValuesBySelector))
-
-		for _, col := range table.cols {
-			encSel := EncodeSelector("", table.name, col.colName)
-			valuesByColID[col.id] = row.ValuesBySelector[encSel]
+		if errors.Is(err, store.ErrKeyNotFound) && pkMustExist {
+			return nil, fmt.Errorf("%w: specified value must be greater than current one", ErrInvalidValue)
 		}
 
-		for _, update := range stmt.updates {
-			col, err := table.GetColumnByName(update.col)
-			if err != nil {
-				return nil, err
+		if stmt.isInsert {
+			if err == nil && stmt.onConflict == nil {
+				return nil, store.ErrKeyAlreadyExists
 			}
 
-			sval, err := update.val.substitute(params)
-			if err != nil {
-				return nil, err
+			if err == nil && stmt.onConflict != nil {
+				// TODO: conflict resolution may be extended. Currently only supports "ON CONFLICT DO NOTHING"
+				continue
 			}
+		}
 
-			rval, err := sval.reduce(tx, row, table.name)
+		if err == nil && len(referencing) > 0 {
+			currPKRow, err := tx.fetchPKRow(ctx, table, valuesByColID)
 			if err != nil {
 				return nil, err
 			}
 
-			err = rval.requiresType(col.colType, cols, nil, table.name)
+			currValuesByColID := make(map[uint32]TypedValue, len(table.cols))
+
+			for _, col := range table.cols {
+				encSel := EncodeSelector("", table.name, col.colName)
+				currValuesByColID[col.id] = currPKRow.ValuesBySelector[encSel]
+			}
+
+			changed, err := referencedValuesChanged(referencing, currValuesByColID, valuesByColID)
 			if err != nil {
 				return nil, err
 			}
 
-			valuesByColID[col.id] = rval
+			if changed {
+				removed = append(removed, currValuesByColID)
+			}
 		}
 
-		for i, col := range table.cols {
-			v := valuesByColID[col.id]
-
-			row.ValuesByPosition[i] = v
-			row.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = v
+		err = tx.doUpsert(ctx, pkEncVals, valuesByColID, table, !stmt.isInsert)
+		if err != nil {
+			return nil, err
 		}
 
-		if err := checkConstraints(tx, table.checkConstraints, row, table.name); err != nil {
-			return nil, err
+		if len(table.foreignKeys) > 0 {
+			written = append(written, valuesByColID)
 		}
+	}
 
-		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+	err = tx.checkForeignKeys(ctx, table, written, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func checkConstraints(tx *SQLTx, checks map[string]CheckConstraint, row *Row, table string) error {
+	for _, check := range checks {
+		val, err := check.exp.reduce(tx, row, table)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("%w: %s", ErrCheckConstraintViolation, err)
 		}
 
-		// primary index entry
-		mkey := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id), pkEncVals, pkEncVals)
+		if val.Type() != BooleanType {
+			return ErrInvalidCheckConstraint
+		}
 
-		// mkey must exist
-		_, err = tx.get(ctx, mkey)
-		if err != nil {
-			return nil, err
+		if !val.RawValue().(bool) {
+			return fmt.Errorf("%w: %s", ErrCheckConstraintViolation, check.exp.String())
 		}
+	}
+	return nil
+}
 
-		err = tx.doUpsert(ctx, pkEncVals, valuesByColID, table, true)
-		if err != nil {
-			return nil, err
+// checkForeignKeys verifies, once a statement has been applied, that the written rows reference existing rows
+// and that no row still references the removed ones (or those whose referenced values were updated)
+func (tx *SQLTx) checkForeignKeys(ctx context.Context, table *Table, written, removed []map[uint32]TypedValue) error {
+	for _, valuesByColID := range written {
+		for _, fk := range table.foreignKeys {
+			refValuesByColID, ok := mapValues(fk.cols, fk.refCols, valuesByColID)
+			if !ok {
+				continue
+			}
+
+			exists, err := tx.rowExists(ctx, fk.refTable, fk.refIndex, refValuesByColID)
+			if err != nil {
+				return err
+			}
+
+			if !exists {
+				return fmt.Errorf("%w: %s (no matching row in table %s)", ErrForeignKeyConstraintViolation, fk.name, fk.refTable.name)
+			}
 		}
 	}
 
-	return tx, nil
-}
+	if len(removed) == 0 {
+		return nil
+	}
 
-type DeleteFromStmt struct {
-	tableRef *tableRef
-	where    ValueExp
-	indexOn  []string
-	orderBy  []*OrdExp
-	limit    ValueExp
-	offset   ValueExp
-}
+	referencing := tx.catalog.referencingForeignKeys(table)
 
-func NewDeleteFromStmt(table string, where ValueExp, orderBy []*OrdExp, limit ValueExp) *DeleteFromStmt {
-	return &DeleteFromStmt{
-		tableRef: NewTableRef(table, ""),
-		where:    where,
-		orderBy:  orderBy,
-		limit:    limit,
+	for _, valuesByColID := range removed {
+		for _, fk := range referencing {
+			refValuesByColID, ok := mapValues(fk.refCols, fk.refCols, valuesByColID)
+			if !ok {
+				continue
+			}
+
+			// referenced values may have been taken over by another row within the same statement
+			exists, err := tx.rowExists(ctx, table, fk.refIndex, refValuesByColID)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				continue
+			}
+
+			fkValuesByColID, _ := mapValues(fk.refCols, fk.cols, valuesByColID)
+
+			referenced, err := tx.rowExists(ctx, fk.table, fk.referencingIndex(), fkValuesByColID)
+			if err != nil {
+				return err
+			}
+
+			if referenced {
+				return fmt.Errorf("%w: %s (row is still referenced from table %s)", ErrForeignKeyConstraintViolation, fk.name, fk.table.name)
+			}
+		}
 	}
+	return nil
 }
 
-func (stmt *DeleteFromStmt) readOnly() bool {
-	return false
-}
+// rowExists returns true if a row of the table holds all the given values.
+// The scan is narrowed down using the leading columns of the index for which a value is given.
+func (tx *SQLTx) rowExists(ctx context.Context, table *Table, index *Index, valuesByColID map[uint32]TypedValue) (bool, error) {
+	rangesByColID := make(map[uint32]*typedValueRange, len(index.cols))
 
-func (stmt *DeleteFromStmt) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeDelete}
-}
+	for _, col := range index.cols {
+		val, specified := valuesByColID[col.id]
+		if !specified {
+			break
+		}
 
-func (stmt *DeleteFromStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	selectStmt := &SelectStmt{
-		ds:      stmt.tableRef,
-		where:   stmt.where,
-		orderBy: stmt.orderBy,
+		rangesByColID[col.id] = &typedValueRange{
+			lRange: &typedValueSemiRange{val: val, inclusive: true},
+			hRange: &typedValueSemiRange{val: val, inclusive: true},
+		}
 	}
-	return selectStmt.inferParameters(ctx, tx, params)
-}
 
-func (stmt *DeleteFromStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	selectStmt := &SelectStmt{
-		ds:      stmt.tableRef,
-		where:   stmt.where,
-		indexOn: stmt.indexOn,
-		orderBy: stmt.orderBy,
-		limit:   stmt.limit,
-		offset:  stmt.offset,
+	scanSpecs := &ScanSpecs{
+		Index:         index,
+		rangesByColID: rangesByColID,
 	}
 
-	rowReader, err := selectStmt.Resolve(ctx, tx, params, nil)
+	r, err := newRawRowReader(tx, nil, table, period{}, table.name, scanSpecs)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	defer rowReader.Close()
-
-	table := rowReader.ScanSpecs().Index.table
+	defer r.Close()
 
 	for {
-		row, err := rowReader.Read(ctx)
+		row, err := r.Read(ctx)
 		if errors.Is(err, ErrNoMoreRows) {
-			break
+			return false, nil
 		}
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		valuesByColID := make(map[uint32]TypedValue, len(row.ValuesBySelector))
+		matches, err := rowMatches(table, row, valuesByColID)
+		if err != nil {
+			return false, err
+		}
 
-		for _, col := range table.cols {
-			encSel := EncodeSelector("", table.name, col.colName)
-			valuesByColID[col.id] = row.ValuesBySelector[encSel]
+		if matches && !index.IsPrimary() {
+			// entries of secondary indexes deprecated within the ongoing transaction may still be read,
+			// thus the match is confirmed against the current row
+			currValuesByColID := make(map[uint32]TypedValue, len(table.cols))
+
+			for _, col := range table.cols {
+				currValuesByColID[col.id] = row.ValuesBySelector[EncodeSelector("", table.name, col.colName)]
+			}
+
+			currRow, err := tx.fetchPKRow(ctx, table, currValuesByColID)
+			if errors.Is(err, ErrNoMoreRows) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+
+			matches, err = rowMatches(table, currRow, valuesByColID)
+			if err != nil {
+				return false, err
+			}
 		}
 
-		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		if matches {
+			return true, nil
+		}
+	}
+}
+
+// rowMatches returns true if the row holds all the given values
+func rowMatches(table *Table, row *Row, valuesByColID map[uint32]TypedValue) (bool, error) {
+	for colID, val := range valuesByColID {
+		col, err := table.GetColumnByID(colID)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		err = tx.deleteIndexEntries(pkEncVals, valuesByColID, table)
+		rowVal := row.ValuesBySelector[EncodeSelector("", table.name, col.colName)]
+		if rowVal == nil || rowVal.IsNull() {
+			return false, nil
+		}
+
+		cmp, err := rowVal.Compare(val)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		tx.updatedRows++
+		if cmp != 0 {
+			return false, nil
+		}
 	}
-	return tx, nil
+	return true, nil
 }
 
-func (tx *SQLTx) deleteIndexEntries(pkEncVals []byte, valuesByColID map[uint32]TypedValue, table *Table) error {
-	encodedRowValue, err := tx.encodeRowValue(valuesByColID, table)
-	if err != nil {
-		return err
-	}
-
-	for _, index := range table.indexes {
-		if !index.IsPrimary() {
+// referencingIndex returns an index of the referencing table whose leading columns are
+// those of the foreign key, the primary index is returned when there is none
+func (fk *ForeignKey) referencingIndex() *Index {
+	for _, index := range fk.table.indexes {
+		if len(index.cols) < len(fk.cols) {
 			continue
 		}
 
-		encodedValues := make([][]byte, 3+len(index.cols))
-		encodedValues[0] = EncodeID(DatabaseID)
-		encodedValues[1] = EncodeID(table.id)
-		encodedValues[2] = EncodeID(index.id)
-
-		for i, col := range index.cols {
-			val, specified := valuesByColID[col.id]
-			if !specified {
-				val = &NullValue{t: col.colType}
-			}
-
-			encVal, _, _ := EncodeValueAsKey(val, col.colType, col.MaxLen())
-
-			encodedValues[i+3] = encVal
+		covered := true
+		for _, col := range index.cols[:len(fk.cols)] {
+			covered = covered && fk.includesCol(col.id)
 		}
 
-		md := store.NewKVMetadata()
-
-		md.AsDeleted(true)
-
-		err := tx.set(MapKey(tx.sqlPrefix(), RowPrefix, encodedValues...), md, encodedRowValue)
-		if err != nil {
-			return err
+		if covered {
+			return index
 		}
 	}
-
-	return nil
+	return fk.table.primaryIndex
 }
 
-type ValueExp interface {
-	inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error)
-	requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error
-	substitute(params map[string]interface{}) (ValueExp, error)
-	selectors() []Selector
-	reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error)
-	reduceSelectors(row *Row, implicitTable string) ValueExp
-	isConstant() bool
-	selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error
-	String() string
+func (fk *ForeignKey) includesCol(colID uint32) bool {
+	for _, col := range fk.cols {
+		if col.id == colID {
+			return true
+		}
+	}
+	return false
 }
 
-type typedValueRange struct {
-	lRange *typedValueSemiRange
-	hRange *typedValueSemiRange
-}
+// mapValues returns the values of the columns in from keyed by the id of the columns at the same position in to.
+// It returns false if any of the values is NULL.
+func mapValues(from, to []*Column, valuesByColID map[uint32]TypedValue) (map[uint32]TypedValue, bool) {
+	mapped := make(map[uint32]TypedValue, len(from))
 
-type typedValueSemiRange struct {
-	val       TypedValue
-	inclusive bool
+	for i, col := range from {
+		val := valuesByColID[col.id]
+		if val == nil || val.IsNull() {
+			return nil, false
+		}
+		mapped[to[i].id] = val
+	}
+	return mapped, true
 }
 
-func (r *typedValueRange) unitary() bool {
-	// TODO: this simplified implementation doesn't cover all unitary cases e.g. 3<=v<4
-	if r.lRange == nil || r.hRange == nil {
-		return false
-	}
+// valuesChanged returns true if the values of any of the columns differ
+func valuesChanged(cols []*Column, currValuesByColID, newValuesByColID map[uint32]TypedValue) (bool, error) {
+	for _, col := range cols {
+		currVal := currValuesByColID[col.id]
+		newVal := newValuesByColID[col.id]
 
-	res, _ := r.lRange.val.Compare(r.hRange.val)
-	return res == 0 && r.lRange.inclusive && r.hRange.inclusive
-}
+		currIsNull := currVal == nil || currVal.IsNull()
+		newIsNull := newVal == nil || newVal.IsNull()
 
-func (r *typedValueRange) refineWith(refiningRange *typedValueRange) error {
-	if r.lRange == nil {
-		r.lRange = refiningRange.lRange
-	} else if r.lRange != nil && refiningRange.lRange != nil {
-		maxRange, err := maxSemiRange(r.lRange, refiningRange.lRange)
-		if err != nil {
-			return err
+		if currIsNull || newIsNull {
+			if currIsNull != newIsNull {
+				return true, nil
+			}
+			continue
 		}
-		r.lRange = maxRange
-	}
 
-	if r.hRange == nil {
-		r.hRange = refiningRange.hRange
-	} else if r.hRange != nil && refiningRange.hRange != nil {
-		minRange, err := minSemiRange(r.hRange, refiningRange.hRange)
+		cmp, err := currVal.Compare(newVal)
 		if err != nil {
-			return err
+			return false, err
 		}
-		r.hRange = minRange
-	}
 
-	return nil
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-func (r *typedValueRange) extendWith(extendingRange *typedValueRange) error {
-	if r.lRange == nil || extendingRange.lRange == nil {
-		r.lRange = nil
-	} else {
-		minRange, err := minSemiRange(r.lRange, extendingRange.lRange)
-		if err != nil {
-			return err
+// referencedValuesChanged returns true if the values referenced by any of the foreign keys differ
+func referencedValuesChanged(fks []*ForeignKey, currValuesByColID, newValuesByColID map[uint32]TypedValue) (bool, error) {
+	for _, fk := range fks {
+		changed, err := valuesChanged(fk.refCols, currValuesByColID, newValuesByColID)
+		if changed || err != nil {
+			return changed, err
 		}
-		r.lRange = minRange
 	}
+	return false, nil
+}
 
-	if r.hRange == nil || extendingRange.hRange == nil {
-		r.hRange = nil
-	} else {
-		maxRange, err := maxSemiRange(r.hRange, extendingRange.hRange)
-		if err != nil {
-			return err
+// foreignKeyValuesChanged returns true if the values of the columns of any of the foreign keys differ
+func foreignKeyValuesChanged(fks []*ForeignKey, currValuesByColID, newValuesByColID map[uint32]TypedValue) (bool, error) {
+	for _, fk := range fks {
+		changed, err := valuesChanged(fk.cols, currValuesByColID, newValuesByColID)
+		if changed || err != nil {
+			return changed, err
 		}
-		r.hRange = maxRange
 	}
-
-	return nil
+	return false, nil
 }
 
-func maxSemiRange(or1, or2 *typedValueSemiRange) (*typedValueSemiRange, error) {
-	r, err := or1.val.Compare(or2.val)
-	if err != nil {
-		return nil, err
-	}
+func (tx *SQLTx) encodeRowValue(valuesByColID map[uint32]TypedValue, table *Table) ([]byte, error) {
+	valbuf := bytes.Buffer{}
 
-	maxVal := or1.val
-	if r < 0 {
-		maxVal = or2.val
+	// null values are not serialized
+	encodedVals := 0
+	for _, v := range valuesByColID {
+		if !v.IsNull() {
+			encodedVals++
+		}
 	}
 
-	return &typedValueSemiRange{
-		val:       maxVal,
-		inclusive: or1.inclusive && or2.inclusive,
-	}, nil
-}
+	b := make([]byte, EncLenLen)
+	binary.BigEndian.PutUint32(b, uint32(encodedVals))
 
-func minSemiRange(or1, or2 *typedValueSemiRange) (*typedValueSemiRange, error) {
-	r, err := or1.val.Compare(or2.val)
+	_, err := valbuf.Write(b)
 	if err != nil {
 		return nil, err
 	}
 
-	minVal := or1.val
-	if r > 0 {
-		minVal = or2.val
-	}
-
-	return &typedValueSemiRange{
-		val:       minVal,
-		inclusive: or1.inclusive || or2.inclusive,
-	}, nil
-}
+	for _, col := range table.cols {
+		rval, specified := valuesByColID[col.id]
+		if !specified || rval.IsNull() {
+			continue
+		}
 
-type TypedValue interface {
-	ValueExp
-	Type() SQLValueType
-	RawValue() interface{}
-	Compare(val TypedValue) (int, error)
-	IsNull() bool
-}
+		b := make([]byte, EncIDLen)
+		binary.BigEndian.PutUint32(b, uint32(col.id))
 
-type Tuple []TypedValue
+		_, err = valbuf.Write(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: table: %s, column: %s", err, table.name, col.colName)
+		}
 
-func (t Tuple) Compare(other Tuple) (int, int, error) {
-	if len(t) != len(other) {
-		return -1, -1, ErrNotComparableValues
-	}
+		encVal, err := EncodeValue(rval, col.colType, col.MaxLen())
+		if err != nil {
+			return nil, fmt.Errorf("%w: table: %s, column: %s", err, table.name, col.colName)
+		}
 
-	for i := range t {
-		res, err := t[i].Compare(other[i])
-		if err != nil || res != 0 {
-			return res, i, err
+		_, err = valbuf.Write(encVal)
+		if err != nil {
+			return nil, fmt.Errorf("%w: table: %s, column: %s", err, table.name, col.colName)
 		}
 	}
-	return 0, -1, nil
-}
 
-func NewNull(t SQLValueType) *NullValue {
-	return &NullValue{t: t}
+	return valbuf.Bytes(), nil
 }
 
-type NullValue struct {
-	t SQLValueType
-}
+func (tx *SQLTx) indexEntryKey(index *Index, valuesByColID map[uint32]TypedValue) ([]byte, error) {
+	encodedValues := make([][]byte, 2+len(index.cols))
+	encodedValues[0] = EncodeID(index.table.id)
+	encodedValues[1] = EncodeID(index.id)
 
-func (n *NullValue) Type() SQLValueType {
-	return n.t
-}
+	indexKeyLen := 0
 
-func (n *NullValue) RawValue() interface{} {
-	return nil
-}
+	for i, col := range index.cols {
+		rval := col.indexedValue(valuesByColID)
 
-func (n *NullValue) IsNull() bool {
-	return true
-}
+		encVal, n, err := EncodeValueAsKey(rval, col.colType, col.MaxLen())
+		if err != nil {
+			return nil, fmt.Errorf("%w: index on '%s' and column '%s'", err, index.Name(), col.colName)
+		}
 
-func (n *NullValue) String() string {
-	return "NULL"
-}
+		if n > MaxKeyLen {
+			return nil, fmt.Errorf("%w: can not index entry for column '%s'. Max key length for variable columns is %d", ErrLimitedKeyType, col.colName, MaxKeyLen)
+		}
 
-func (n *NullValue) Compare(val TypedValue) (int, error) {
-	if n.t != AnyType && val.Type() != AnyType && n.t != val.Type() {
-		return 0, ErrNotComparableValues
+		indexKeyLen += n
+
+		encodedValues[i+2] = encVal
 	}
 
-	if val.RawValue() == nil {
-		return 0, nil
+	if indexKeyLen > MaxKeyLen {
+		return nil, fmt.Errorf("%w: can not index entry using columns '%v'. Max key length is %d", ErrLimitedKeyType, index.cols, MaxKeyLen)
 	}
-	return -1, nil
-}
 
-func (v *NullValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return v.t, nil
+	return MapKey(tx.sqlPrefix(), MappedPrefix, encodedValues...), nil
 }
 
-func (v *NullValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if v.t == t {
-		return nil
+func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID map[uint32]TypedValue, table *Table, reuseIndex bool) error {
+	var reusableIndexEntries map[uint32]struct{}
+
+	if reuseIndex && len(table.indexes) > 1 {
+		currPKRow, err := tx.fetchPKRow(ctx, table, valuesByColID)
+		if err == nil {
+			currValuesByColID := make(map[uint32]TypedValue, len(currPKRow.ValuesBySelector))
+
+			for _, col := range table.cols {
+				encSel := EncodeSelector("", table.name, col.colName)
+				currValuesByColID[col.id] = currPKRow.ValuesBySelector[encSel]
+			}
+
+			reusableIndexEntries, err = tx.deprecateIndexEntries(pkEncVals, currValuesByColID, valuesByColID, table)
+			if err != nil {
+				return err
+			}
+		} else if !errors.Is(err, ErrNoMoreRows) {
+			return err
+		}
 	}
 
-	if v.t != AnyType {
-		return ErrInvalidTypes
+	rowKey := MapKey(tx.sqlPrefix(), RowPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(PKIndexID), pkEncVals)
+
+	encodedRowValue, err := tx.encodeRowValue(valuesByColID, table)
+	if err != nil {
+		return err
 	}
 
-	v.t = t
+	err = tx.set(rowKey, nil, encodedRowValue)
+	if err != nil {
+		return err
+	}
 
-	return nil
-}
+	// create in-memory and validate entries for secondary indexes
+	for _, index := range table.indexes {
+		if index.IsPrimary() {
+			continue
+		}
 
-func (v *NullValue) selectors() []Selector {
-	return nil
-}
+		if reusableIndexEntries != nil {
+			_, reusable := reusableIndexEntries[index.id]
+			if reusable {
+				continue
+			}
+		}
 
-func (v *NullValue) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
-}
+		smkey, err := tx.indexEntryKey(index, valuesByColID)
+		if err != nil {
+			return err
+		}
 
-func (v *NullValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
-}
+		// no other equivalent entry should be already indexed
+		if index.IsUnique() {
+			_, valRef, err := tx.getWithPrefix(ctx, smkey, nil)
+			if err == nil && (valRef.KVMetadata() == nil || !valRef.KVMetadata().Deleted()) {
+				return store.ErrKeyAlreadyExists
+			} else if !errors.Is(err, store.ErrKeyNotFound) {
+				return err
+			}
+		}
 
-func (v *NullValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
-}
+		err = tx.setTransient(smkey, nil, encodedRowValue) // only-indexable
+		if err != nil {
+			return err
+		}
+	}
 
-func (v *NullValue) isConstant() bool {
-	return true
-}
+	tx.updatedRows++
+
+	tx.tableWritten(table)
 
-func (v *NullValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
-type Integer struct {
-	val int64
-}
+func encodedKey(index *Index, valuesByColID map[uint32]TypedValue) ([]byte, error) {
+	valbuf := bytes.Buffer{}
 
-func NewInteger(val int64) *Integer {
-	return &Integer{val: val}
-}
+	indexKeyLen := 0
 
-func (v *Integer) Type() SQLValueType {
-	return IntegerType
-}
+	for _, col := range index.cols {
+		rval, specified := valuesByColID[col.id]
+		if !specified || rval.IsNull() {
+			return nil, ErrPKCanNotBeNull
+		}
 
-func (v *Integer) IsNull() bool {
-	return false
-}
+		encVal, n, err := EncodeValueAsKey(rval, col.colType, col.MaxLen())
+		if err != nil {
+			return nil, fmt.Errorf("%w: index of table '%s' and column '%s'", err, index.table.name, col.colName)
+		}
 
-func (v *Integer) String() string {
-	return strconv.FormatInt(v.val, 10)
-}
+		if n > MaxKeyLen {
+			return nil, fmt.Errorf("%w: invalid key entry for column '%s'. Max key length for variable columns is %d", ErrLimitedKeyType, col.colName, MaxKeyLen)
+		}
 
-func (v *Integer) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return IntegerType, nil
-}
+		indexKeyLen += n
 
-func (v *Integer) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != IntegerType && t != JSONType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+		_, err = valbuf.Write(encVal)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
-}
+	if indexKeyLen > MaxKeyLen {
+		return nil, fmt.Errorf("%w: invalid key entry using columns '%v'. Max key length is %d", ErrLimitedKeyType, index.cols, MaxKeyLen)
+	}
 
-func (v *Integer) selectors() []Selector {
-	return nil
+	return valbuf.Bytes(), nil
 }
 
-func (v *Integer) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
-}
+func (tx *SQLTx) fetchPKRow(ctx context.Context, table *Table, valuesByColID map[uint32]TypedValue) (*Row, error) {
+	pkRanges := make(map[uint32]*typedValueRange, len(table.primaryIndex.cols))
 
-func (v *Integer) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
-}
+	for _, pkCol := range table.primaryIndex.cols {
+		pkVal := valuesByColID[pkCol.id]
 
-func (v *Integer) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
-}
+		pkRanges[pkCol.id] = &typedValueRange{
+			lRange: &typedValueSemiRange{val: pkVal, inclusive: true},
+			hRange: &typedValueSemiRange{val: pkVal, inclusive: true},
+		}
+	}
 
-func (v *Integer) isConstant() bool {
-	return true
-}
+	scanSpecs := &ScanSpecs{
+		Index:         table.primaryIndex,
+		rangesByColID: pkRanges,
+	}
 
-func (v *Integer) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+	r, err := newRawRowReader(tx, nil, table, period{}, table.name, scanSpecs)
+	if err != nil {
+		return nil, err
+	}
 
-func (v *Integer) RawValue() interface{} {
-	return v.val
-}
-
-func (v *Integer) Compare(val TypedValue) (int, error) {
-	if val.IsNull() {
-		return 1, nil
-	}
+	defer func() {
+		r.Close()
+	}()
 
-	if val.Type() == JSONType {
-		res, err := val.Compare(v)
-		return -res, err
-	}
+	return r.Read(ctx)
+}
 
-	if val.Type() == Float64Type {
-		r, err := val.Compare(v)
-		return r * -1, err
-	}
+// deprecateIndexEntries mark previous index entries as deleted
+func (tx *SQLTx) deprecateIndexEntries(
+	pkEncVals []byte,
+	currValuesByColID, newValuesByColID map[uint32]TypedValue,
+	table *Table) (reusableIndexEntries map[uint32]struct{}, err error) {
 
-	if val.Type() != IntegerType {
-		return 0, ErrNotComparableValues
+	encodedRowValue, err := tx.encodeRowValue(currValuesByColID, table)
+	if err != nil {
+		return nil, err
 	}
 
-	rval := val.RawValue().(int64)
+	reusableIndexEntries = make(map[uint32]struct{})
 
-	if v.val == rval {
-		return 0, nil
-	}
+	for _, index := range table.indexes {
+		if index.IsPrimary() {
+			continue
+		}
 
-	if v.val > rval {
-		return 1, nil
-	}
+		encodedValues := make([][]byte, 2+len(index.cols)+1)
+		encodedValues[0] = EncodeID(table.id)
+		encodedValues[1] = EncodeID(index.id)
+		encodedValues[len(encodedValues)-1] = pkEncVals
 
-	return -1, nil
-}
+		// existent index entry is deleted only if it differs from existent one
+		sameIndexKey := true
 
-type Timestamp struct {
-	val time.Time
-}
+		for i, col := range index.cols {
+			currVal := col.indexedValue(currValuesByColID)
+			newVal := col.indexedValue(newValuesByColID)
 
-func (v *Timestamp) Type() SQLValueType {
-	return TimestampType
-}
+			encVal, _, _ := EncodeValueAsKey(currVal, col.colType, col.MaxLen())
 
-func (v *Timestamp) IsNull() bool {
-	return false
-}
+			if col.jsonCol != nil {
+				// values found at JSON paths may not be comparable
+				newEncVal, _, _ := EncodeValueAsKey(newVal, col.colType, col.MaxLen())
 
-func (v *Timestamp) String() string {
-	return v.val.Format("2006-01-02 15:04:05.999999")
-}
+				sameIndexKey = sameIndexKey && bytes.Equal(encVal, newEncVal)
+			} else {
+				r, err := currVal.Compare(newVal)
+				if err != nil {
+					return nil, err
+				}
 
-func (v *Timestamp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return TimestampType, nil
-}
+				sameIndexKey = sameIndexKey && r == 0
+			}
 
-func (v *Timestamp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != TimestampType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, TimestampType, t)
-	}
+			encodedValues[i+3] = encVal
+		}
 
-	return nil
-}
+		// mark existent index entry as deleted
+		if sameIndexKey {
+			reusableIndexEntries[index.id] = struct{}{}
+		} else {
+			md := store.NewKVMetadata()
 
-func (v *Timestamp) selectors() []Selector {
-	return nil
-}
+			md.AsDeleted(true)
 
-func (v *Timestamp) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
-}
+			err = tx.set(MapKey(tx.sqlPrefix(), MappedPrefix, encodedValues...), md, encodedRowValue)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
-func (v *Timestamp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
+	return reusableIndexEntries, nil
 }
 
-func (v *Timestamp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
+type UpdateStmt struct {
+	tableRef *tableRef
+	where    ValueExp
+	updates  []*colUpdate
+	indexOn  []*indexColSpec
+	limit    ValueExp
+	offset   ValueExp
 }
 
-func (v *Timestamp) isConstant() bool {
-	return true
+type colUpdate struct {
+	col string
+	op  CmpOperator
+	val ValueExp
 }
 
-func (v *Timestamp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
+func (stmt *UpdateStmt) readOnly() bool {
+	return false
 }
 
-func (v *Timestamp) RawValue() interface{} {
-	return v.val
+func (stmt *UpdateStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeUpdate}
 }
 
-func (v *Timestamp) Compare(val TypedValue) (int, error) {
-	if val.IsNull() {
-		return 1, nil
+func (stmt *UpdateStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	selectStmt := &SelectStmt{
+		ds:    stmt.tableRef,
+		where: stmt.where,
 	}
 
-	if val.Type() != TimestampType {
-		return 0, ErrNotComparableValues
+	err := selectStmt.inferParameters(ctx, tx, params)
+	if err != nil {
+		return err
 	}
 
-	rval := val.RawValue().(time.Time)
-
-	if v.val.Before(rval) {
-		return -1, nil
+	table, err := stmt.tableRef.referencedTable(tx)
+	if err != nil {
+		return err
 	}
 
-	if v.val.After(rval) {
-		return 1, nil
+	for _, update := range stmt.updates {
+		col, err := table.GetColumnByName(update.col)
+		if err != nil {
+			return err
+		}
+
+		err = update.val.requiresType(tx, col.colType, make(map[string]ColDescriptor), params, table.name)
+		if err != nil {
+			return err
+		}
 	}
 
-	return 0, nil
+	return nil
 }
 
-type Varchar struct {
-	val string
-}
+func (stmt *UpdateStmt) validate(table *Table) error {
+	colIDs := make(map[uint32]struct{}, len(stmt.updates))
 
-func NewVarchar(val string) *Varchar {
-	return &Varchar{val: val}
-}
+	for _, update := range stmt.updates {
+		if update.op != EQ {
+			return ErrIllegalArguments
+		}
 
-func (v *Varchar) Type() SQLValueType {
-	return VarcharType
-}
+		col, err := table.GetColumnByName(update.col)
+		if err != nil {
+			return err
+		}
 
-func (v *Varchar) IsNull() bool {
-	return false
-}
+		if table.PrimaryIndex().IncludesCol(col.id) {
+			return ErrPKCanNotBeUpdated
+		}
 
-func (v *Varchar) String() string {
-	return fmt.Sprintf("'%s'", v.val)
-}
+		if col.generatedExp != nil {
+			return fmt.Errorf("%w (%s)", ErrGeneratedColumnCannotBeSet, col.colName)
+		}
 
-func (v *Varchar) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return VarcharType, nil
-}
+		_, duplicated := colIDs[col.id]
+		if duplicated {
+			return ErrDuplicatedColumn
+		}
 
-func (v *Varchar) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != VarcharType && t != JSONType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+		colIDs[col.id] = struct{}{}
 	}
-	return nil
-}
 
-func (v *Varchar) selectors() []Selector {
 	return nil
 }
 
-func (v *Varchar) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
-}
-
-func (v *Varchar) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
-}
-
-func (v *Varchar) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
-}
-
-func (v *Varchar) isConstant() bool {
-	return true
-}
+func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	selectStmt := &SelectStmt{
+		ds:      stmt.tableRef,
+		where:   stmt.where,
+		indexOn: stmt.indexOn,
+		limit:   stmt.limit,
+		offset:  stmt.offset,
+	}
 
-func (v *Varchar) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+	rowReader, err := selectStmt.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rowReader.Close()
 
-func (v *Varchar) RawValue() interface{} {
-	return v.val
-}
+	table := rowReader.ScanSpecs().Index.table
 
-func (v *Varchar) Compare(val TypedValue) (int, error) {
-	if val.IsNull() {
-		return 1, nil
+	err = stmt.validate(table)
+	if err != nil {
+		return nil, err
 	}
 
-	if val.Type() == JSONType {
-		res, err := val.Compare(v)
-		return -res, err
+	cols, err := rowReader.colsBySelector(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if val.Type() != VarcharType {
-		return 0, ErrNotComparableValues
-	}
+	referencing := tx.catalog.referencingForeignKeys(table)
 
-	rval := val.RawValue().(string)
+	// rows to be checked against foreign keys once all of them are updated
+	var written, removed []map[uint32]TypedValue
 
-	return bytes.Compare([]byte(v.val), []byte(rval)), nil
-}
+	for {
+		row, err := rowReader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
 
-type UUID struct {
-	val uuid.UUID
-}
+		valuesByColID := make(map[uint32]TypedValue, len(row.ValuesBySelector))
+		currValuesByColID := make(map[uint32]TypedValue, len(row.ValuesBySelector))
 
-func NewUUID(val uuid.UUID) *UUID {
-	return &UUID{val: val}
-}
+		for _, col := range table.cols {
+			encSel := EncodeSelector("", table.name, col.colName)
+			valuesByColID[col.id] = row.ValuesBySelector[encSel]
+			currValuesByColID[col.id] = row.ValuesBySelector[encSel]
+		}
 
-func (v *UUID) Type() SQLValueType {
-	return UUIDType
-}
+		for _, update := range stmt.updates {
+			col, err := table.GetColumnByName(update.col)
+			if err != nil {
+				return nil, err
+			}
 
-func (v *UUID) IsNull() bool {
-	return false
-}
+			sval, err := update.val.substitute(params)
+			if err != nil {
+				return nil, err
+			}
 
-func (v *UUID) String() string {
-	return v.val.String()
-}
+			rval, err := sval.reduce(tx, row, table.name)
+			if err != nil {
+				return nil, err
+			}
 
-func (v *UUID) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return UUIDType, nil
-}
+			err = rval.requiresType(tx, col.colType, cols, nil, table.name)
+			if err != nil {
+				return nil, err
+			}
 
-func (v *UUID) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != UUIDType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, UUIDType, t)
-	}
+			valuesByColID[col.id] = rval
+		}
 
-	return nil
-}
+		err = setGeneratedValues(tx, table, valuesByColID)
+		if err != nil {
+			return nil, err
+		}
 
-func (v *UUID) selectors() []Selector {
-	return nil
-}
+		for i, col := range table.cols {
+			v, ok := valuesByColID[col.id]
+			if !ok {
+				v = &NullValue{t: col.colType}
+				valuesByColID[col.id] = v
+			}
 
-func (v *UUID) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
-}
+			row.ValuesByPosition[i] = v
+			row.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = v
+		}
 
-func (v *UUID) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
-}
+		if err := checkConstraints(tx, table.checkConstraints, row, table.name); err != nil {
+			return nil, err
+		}
 
-func (v *UUID) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
-}
+		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		if err != nil {
+			return nil, err
+		}
 
-func (v *UUID) isConstant() bool {
-	return true
-}
+		// primary index entry
+		mkey := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id), pkEncVals, pkEncVals)
 
-func (v *UUID) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+		// mkey must exist
+		_, err = tx.get(ctx, mkey)
+		if err != nil {
+			return nil, err
+		}
 
-func (v *UUID) RawValue() interface{} {
-	return v.val
-}
+		err = tx.doUpsert(ctx, pkEncVals, valuesByColID, table, true)
+		if err != nil {
+			return nil, err
+		}
 
-func (v *UUID) Compare(val TypedValue) (int, error) {
-	if val.IsNull() {
-		return 1, nil
-	}
+		changed, err := foreignKeyValuesChanged(table.foreignKeys, currValuesByColID, valuesByColID)
+		if err != nil {
+			return nil, err
+		}
 
-	if val.Type() != UUIDType {
-		return 0, ErrNotComparableValues
-	}
+		if changed {
+			written = append(written, valuesByColID)
+		}
 
-	rval := val.RawValue().(uuid.UUID)
+		changed, err = referencedValuesChanged(referencing, currValuesByColID, valuesByColID)
+		if err != nil {
+			return nil, err
+		}
 
-	return bytes.Compare(v.val[:], rval[:]), nil
-}
+		if changed {
+			removed = append(removed, currValuesByColID)
+		}
+	}
 
-type Bool struct {
-	val bool
-}
+	err = tx.checkForeignKeys(ctx, table, written, removed)
+	if err != nil {
+		return nil, err
+	}
 
-func NewBool(val bool) *Bool {
-	return &Bool{val: val}
+	return tx, nil
 }
 
-func (v *Bool) Type() SQLValueType {
-	return BooleanType
+type DeleteFromStmt struct {
+	tableRef *tableRef
+	where    ValueExp
+	indexOn  []*indexColSpec
+	orderBy  []*OrdExp
+	limit    ValueExp
+	offset   ValueExp
 }
 
-func (v *Bool) IsNull() bool {
-	return false
+func NewDeleteFromStmt(table string, where ValueExp, orderBy []*OrdExp, limit ValueExp) *DeleteFromStmt {
+	return &DeleteFromStmt{
+		tableRef: NewTableRef(table, ""),
+		where:    where,
+		orderBy:  orderBy,
+		limit:    limit,
+	}
 }
 
-func (v *Bool) String() string {
-	return strconv.FormatBool(v.val)
+func (stmt *DeleteFromStmt) readOnly() bool {
+	return false
 }
 
-func (v *Bool) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return BooleanType, nil
+func (stmt *DeleteFromStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeDelete}
 }
 
-func (v *Bool) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != BooleanType && t != JSONType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+func (stmt *DeleteFromStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	selectStmt := &SelectStmt{
+		ds:      stmt.tableRef,
+		where:   stmt.where,
+		orderBy: stmt.orderBy,
 	}
-	return nil
+	return selectStmt.inferParameters(ctx, tx, params)
 }
 
-func (v *Bool) selectors() []Selector {
-	return nil
-}
+func (stmt *DeleteFromStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	selectStmt := &SelectStmt{
+		ds:      stmt.tableRef,
+		where:   stmt.where,
+		indexOn: stmt.indexOn,
+		orderBy: stmt.orderBy,
+		limit:   stmt.limit,
+		offset:  stmt.offset,
+	}
 
-func (v *Bool) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
-}
+	rowReader, err := selectStmt.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rowReader.Close()
 
-func (v *Bool) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
-}
+	table := rowReader.ScanSpecs().Index.table
 
-func (v *Bool) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
-}
+	referencing := tx.catalog.referencingForeignKeys(table)
 
-func (v *Bool) isConstant() bool {
-	return true
-}
+	// rows to be checked against foreign keys once all of them are deleted
+	var removed []map[uint32]TypedValue
 
-func (v *Bool) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+	for {
+		row, err := rowReader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
 
-func (v *Bool) RawValue() interface{} {
-	return v.val
-}
+		valuesByColID := make(map[uint32]TypedValue, len(row.ValuesBySelector))
 
-func (v *Bool) Compare(val TypedValue) (int, error) {
-	if val.IsNull() {
-		return 1, nil
-	}
+		for _, col := range table.cols {
+			encSel := EncodeSelector("", table.name, col.colName)
+			valuesByColID[col.id] = row.ValuesBySelector[encSel]
+		}
 
-	if val.Type() == JSONType {
-		res, err := val.Compare(v)
-		return -res, err
-	}
+		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		if err != nil {
+			return nil, err
+		}
 
-	if val.Type() != BooleanType {
-		return 0, ErrNotComparableValues
-	}
+		err = tx.deleteIndexEntries(pkEncVals, valuesByColID, table)
+		if err != nil {
+			return nil, err
+		}
 
-	rval := val.RawValue().(bool)
+		if len(referencing) > 0 {
+			removed = append(removed, valuesByColID)
+		}
 
-	if v.val == rval {
-		return 0, nil
+		tx.updatedRows++
 	}
 
-	if v.val {
-		return 1, nil
+	err = tx.checkForeignKeys(ctx, table, nil, removed)
+	if err != nil {
+		return nil, err
 	}
 
-	return -1, nil
+	return tx, nil
 }
 
-type Blob struct {
-	val []byte
-}
+func (tx *SQLTx) deleteIndexEntries(pkEncVals []byte, valuesByColID map[uint32]TypedValue, table *Table) error {
+	tx.tableWritten(table)
 
-func NewBlob(val []byte) *Blob {
-	return &Blob{val: val}
-}
+	encodedRowValue, err := tx.encodeRowValue(valuesByColID, table)
+	if err != nil {
+		return err
+	}
 
-func (v *Blob) Type() SQLValueType {
-	return BLOBType
-}
+	for _, index := range table.indexes {
+		if !index.IsPrimary() {
+			continue
+		}
 
-func (v *Blob) IsNull() bool {
-	return false
-}
+		encodedValues := make([][]byte, 3+len(index.cols))
+		encodedValues[0] = EncodeID(DatabaseID)
+		encodedValues[1] = EncodeID(table.id)
+		encodedValues[2] = EncodeID(index.id)
 
-func (v *Blob) String() string {
-	return hex.EncodeToString(v.val)
-}
+		for i, col := range index.cols {
+			val, specified := valuesByColID[col.id]
+			if !specified {
+				val = &NullValue{t: col.colType}
+			}
 
-func (v *Blob) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return BLOBType, nil
-}
+			encVal, _, _ := EncodeValueAsKey(val, col.colType, col.MaxLen())
 
-func (v *Blob) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != BLOBType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BLOBType, t)
+			encodedValues[i+3] = encVal
+		}
+
+		md := store.NewKVMetadata()
+
+		md.AsDeleted(true)
+
+		err := tx.set(MapKey(tx.sqlPrefix(), RowPrefix, encodedValues...), md, encodedRowValue)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (v *Blob) selectors() []Selector {
-	return nil
+type ValueExp interface {
+	inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error)
+	requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error
+	substitute(params map[string]interface{}) (ValueExp, error)
+	selectors() []Selector
+	reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error)
+	reduceSelectors(row *Row, implicitTable string) ValueExp
+	isConstant() bool
+	selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error
+	String() string
 }
 
-func (v *Blob) substitute(params map[string]interface{}) (ValueExp, error) {
-	return v, nil
+type typedValueRange struct {
+	lRange *typedValueSemiRange
+	hRange *typedValueSemiRange
 }
 
-func (v *Blob) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return v, nil
+type typedValueSemiRange struct {
+	val       TypedValue
+	inclusive bool
 }
 
-func (v *Blob) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
+func (r *typedValueRange) unitary() bool {
+	// TODO: this simplified implementation doesn't cover all unitary cases e.g. 3<=v<4
+	if r.lRange == nil || r.hRange == nil {
+		return false
+	}
+
+	res, _ := r.lRange.val.Compare(r.hRange.val)
+	return res == 0 && r.lRange.inclusive && r.hRange.inclusive
 }
 
-func (v *Blob) isConstant() bool {
-	return true
+func (r *typedValueRange) refineWith(refiningRange *typedValueRange) error {
+	if r.lRange == nil {
+		r.lRange = refiningRange.lRange
+	} else if r.lRange != nil && refiningRange.lRange != nil {
+		maxRange, err := maxSemiRange(r.lRange, refiningRange.lRange)
+		if err != nil {
+			return err
+		}
+		r.lRange = maxRange
+	}
+
+	if r.hRange == nil {
+		r.hRange = refiningRange.hRange
+	} else if r.hRange != nil && refiningRange.hRange != nil {
+		minRange, err := minSemiRange(r.hRange, refiningRange.hRange)
+		if err != nil {
+			return err
+		}
+		r.hRange = minRange
+	}
+
+	return nil
 }
 
-func (v *Blob) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (r *typedValueRange) extendWith(extendingRange *typedValueRange) error {
+	if r.lRange == nil || extendingRange.lRange == nil {
+		r.lRange = nil
+	} else {
+		minRange, err := minSemiRange(r.lRange, extendingRange.lRange)
+		if err != nil {
+			return err
+		}
+		r.lRange = minRange
+	}
+
+	if r.hRange == nil || extendingRange.hRange == nil {
+		r.hRange = nil
+	} else {
+		maxRange, err := maxSemiRange(r.hRange, extendingRange.hRange)
+		if err != nil {
+			return err
+		}
+		r.hRange = maxRange
+	}
+
 	return nil
 }
 
-func (v *Blob) RawValue() interface{} {
-	return v.val
+func maxSemiRange(or1, or2 *typedValueSemiRange) (*typedValueSemiRange, error) {
+	r, err := or1.val.Compare(or2.val)
+	if err != nil {
+		return nil, err
+	}
+
+	maxVal := or1.val
+	if r < 0 {
+		maxVal = or2.val
+	}
+
+	return &typedValueSemiRange{
+		val:       maxVal,
+		inclusive: or1.inclusive && or2.inclusive,
+	}, nil
 }
 
-func (v *Blob) Compare(val TypedValue) (int, error) {
-	if val.IsNull() {
-		return 1, nil
+func minSemiRange(or1, or2 *typedValueSemiRange) (*typedValueSemiRange, error) {
+	r, err := or1.val.Compare(or2.val)
+	if err != nil {
+		return nil, err
 	}
 
-	if val.Type() != BLOBType {
-		return 0, ErrNotComparableValues
+	minVal := or1.val
+	if r > 0 {
+		minVal = or2.val
 	}
 
-	rval := val.RawValue().([]byte)
+	return &typedValueSemiRange{
+		val:       minVal,
+		inclusive: or1.inclusive || or2.inclusive,
+	}, nil
+}
 
-	return bytes.Compare(v.val, rval), nil
+type TypedValue interface {
+	ValueExp
+	Type() SQLValueType
+	RawValue() interface{}
+	Compare(val TypedValue) (int, error)
+	IsNull() bool
 }
 
-type Float64 struct {
-	val float64
+type Tuple []TypedValue
+
+func (t Tuple) Compare(other Tuple) (int, int, error) {
+	if len(t) != len(other) {
+		return -1, -1, ErrNotComparableValues
+	}
+
+	for i := range t {
+		res, err := t[i].Compare(other[i])
+		if err != nil || res != 0 {
+			return res, i, err
+		}
+	}
+	return 0, -1, nil
 }
 
-func NewFloat64(val float64) *Float64 {
-	return &Float64{val: val}
+func NewNull(t SQLValueType) *NullValue {
+	return &NullValue{t: t}
 }
 
-func (v *Float64) Type() SQLValueType {
-	return Float64Type
+type NullValue struct {
+	t SQLValueType
 }
 
-func (v *Float64) IsNull() bool {
-	return false
+func (n *NullValue) Type() SQLValueType {
+	return n.t
 }
 
-func (v *Float64) String() string {
-	return strconv.FormatFloat(float64(v.val), 'f', -1, 64)
+func (n *NullValue) RawValue() interface{} {
+	return nil
 }
 
-func (v *Float64) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return Float64Type, nil
+func (n *NullValue) IsNull() bool {
+	return true
 }
 
-func (v *Float64) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != Float64Type && t != JSONType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, Float64Type, t)
+func (n *NullValue) String() string {
+	return "NULL"
+}
+
+func (n *NullValue) Compare(val TypedValue) (int, error) {
+	if n.t != AnyType && val.Type() != AnyType && n.t != val.Type() &&
+		!(IsNumericType(n.t) && IsNumericType(val.Type())) {
+		return 0, ErrNotComparableValues
+	}
+
+	if val.RawValue() == nil {
+		return 0, nil
+	}
+	return -1, nil
+}
+
+func (v *NullValue) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return v.t, nil
+}
+
+func (v *NullValue) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if v.t == t {
+		return nil
+	}
+
+	if v.t != AnyType {
+		return ErrInvalidTypes
 	}
+
+	v.t = t
+
 	return nil
 }
 
-func (v *Float64) selectors() []Selector {
+func (v *NullValue) selectors() []Selector {
 	return nil
 }
 
-func (v *Float64) substitute(params map[string]interface{}) (ValueExp, error) {
+func (v *NullValue) substitute(params map[string]interface{}) (ValueExp, error) {
 	return v, nil
 }
 
-func (v *Float64) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+func (v *NullValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
-func (v *Float64) reduceSelectors(row *Row, implicitTable string) ValueExp {
+func (v *NullValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
 	return v
 }
 
-func (v *Float64) isConstant() bool {
+func (v *NullValue) isConstant() bool {
 	return true
 }
 
-func (v *Float64) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *NullValue) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
-func (v *Float64) RawValue() interface{} {
-	return v.val
+type Integer struct {
+	val int64
 }
 
-func (v *Float64) Compare(val TypedValue) (int, error) {
-	if val.Type() == JSONType {
-		res, err := val.Compare(v)
-		return -res, err
-	}
+func NewInteger(val int64) *Integer {
+	return &Integer{val: val}
+}
 
-	convVal, err := mayApplyImplicitConversion(val.RawValue(), Float64Type)
-	if err != nil {
-		return 0, err
-	}
+func (v *Integer) Type() SQLValueType {
+	return IntegerType
+}
 
-	if convVal == nil {
-		return 1, nil
-	}
+func (v *Integer) IsNull() bool {
+	return false
+}
 
-	rval, ok := convVal.(float64)
-	if !ok {
-		return 0, ErrNotComparableValues
-	}
+func (v *Integer) String() string {
+	return strconv.FormatInt(v.val, 10)
+}
 
-	if v.val == rval {
-		return 0, nil
-	}
+func (v *Integer) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return IntegerType, nil
+}
 
-	if v.val > rval {
-		return 1, nil
+func (v *Integer) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != IntegerType && t != DecimalType && t != JSONType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
 	}
 
-	return -1, nil
+	return nil
 }
 
-type FnCall struct {
-	fn     string
-	params []ValueExp
+func (v *Integer) selectors() []Selector {
+	return nil
 }
 
-func (v *FnCall) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	fn, err := v.resolveFunc()
-	if err != nil {
-		return AnyType, nil
-	}
-	return fn.InferType(cols, params, implicitTable)
-}
+func (v *Integer) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
 
-func (v *FnCall) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	fn, err := v.resolveFunc()
-	if err != nil {
-		return err
-	}
-	return fn.RequiresType(t, cols, params, implicitTable)
+func (v *Integer) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
 }
 
-func (v *FnCall) selectors() []Selector {
-	selectors := make([]Selector, 0)
-	for _, param := range v.params {
-		selectors = append(selectors, param.selectors()...)
-	}
-	return selectors
+func (v *Integer) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
 }
 
-func (v *FnCall) substitute(params map[string]interface{}) (val ValueExp, err error) {
-	ps := make([]ValueExp, len(v.params))
-	for i, p := range v.params {
-		ps[i], err = p.substitute(params)
-		if err != nil {
-			return nil, err
-		}
-	}
+func (v *Integer) isConstant() bool {
+	return true
+}
 
-	return &FnCall{
-		fn:     v.fn,
-		params: ps,
-	}, nil
+func (v *Integer) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
 }
 
-func (v *FnCall) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	fn, err := v.resolveFunc()
-	if err != nil {
-		return nil, err
+func (v *Integer) RawValue() interface{} {
+	return v.val
+}
+
+func (v *Integer) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
 	}
 
-	fnInputs, err := v.reduceParams(tx, row, implicitTable)
-	if err != nil {
-		return nil, err
+	if val.Type() == JSONType {
+		res, err := val.Compare(v)
+		return -res, err
 	}
-	return fn.Apply(tx, fnInputs)
-}
 
-func (v *FnCall) reduceParams(tx *SQLTx, row *Row, implicitTable string) ([]TypedValue, error) {
-	var values []TypedValue
-	if len(v.params) > 0 {
-		values = make([]TypedValue, len(v.params))
-		for i, p := range v.params {
-			v, err := p.reduce(tx, row, implicitTable)
-			if err != nil {
-				return nil, err
-			}
-			values[i] = v
-		}
+	if val.Type() == Float64Type || val.Type() == DecimalType {
+		r, err := val.Compare(v)
+		return r * -1, err
 	}
-	return values, nil
-}
 
-func (v *FnCall) resolveFunc() (Function, error) {
-	fn, exists := builtinFunctions[strings.ToUpper(v.fn)]
-	if !exists {
-		return nil, fmt.Errorf("%w: unknown function %s", ErrIllegalArguments, v.fn)
+	if val.Type() != IntegerType {
+		return 0, ErrNotComparableValues
 	}
-	return fn, nil
-}
 
-func (v *FnCall) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return v
-}
+	rval := val.RawValue().(int64)
 
-func (v *FnCall) isConstant() bool {
-	return false
-}
+	if v.val == rval {
+		return 0, nil
+	}
 
-func (v *FnCall) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
+	if v.val > rval {
+		return 1, nil
+	}
+
+	return -1, nil
 }
 
-func (v *FnCall) String() string {
-	params := make([]string, len(v.params))
-	for i, p := range v.params {
-		params[i] = p.String()
-	}
-	return v.fn + "(" + strings.Join(params, ",") + ")"
+type Timestamp struct {
+	val time.Time
 }
 
-type Cast struct {
-	val ValueExp
-	t   SQLValueType
+func (v *Timestamp) Type() SQLValueType {
+	return TimestampType
 }
 
-func (c *Cast) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	_, err := c.val.inferType(cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
-	}
+func (v *Timestamp) IsNull() bool {
+	return false
+}
 
-	// val type may be restricted by compatible conversions, but multiple types may be compatible...
+func (v *Timestamp) String() string {
+	return v.val.Format("2006-01-02 15:04:05.999999")
+}
 
-	return c.t, nil
+func (v *Timestamp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return TimestampType, nil
 }
 
-func (c *Cast) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if c.t != t {
-		return fmt.Errorf("%w: can not use value cast to %s as %s", ErrInvalidTypes, c.t, t)
+func (v *Timestamp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != TimestampType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, TimestampType, t)
 	}
 
 	return nil
 }
 
-func (c *Cast) substitute(params map[string]interface{}) (ValueExp, error) {
-	val, err := c.val.substitute(params)
-	if err != nil {
-		return nil, err
-	}
-	c.val = val
-	return c, nil
+func (v *Timestamp) selectors() []Selector {
+	return nil
 }
 
-func (c *Cast) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	val, err := c.val.reduce(tx, row, implicitTable)
-	if err != nil {
-		return nil, err
-	}
-
-	conv, err := getConverter(val.Type(), c.t)
-	if conv == nil {
-		return nil, err
-	}
-
-	return conv(val)
+func (v *Timestamp) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
 }
 
-func (v *Cast) selectors() []Selector {
-	return v.val.selectors()
+func (v *Timestamp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
 }
 
-func (c *Cast) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return &Cast{
-		val: c.val.reduceSelectors(row, implicitTable),
-		t:   c.t,
-	}
+func (v *Timestamp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
 }
 
-func (c *Cast) isConstant() bool {
-	return c.val.isConstant()
+func (v *Timestamp) isConstant() bool {
+	return true
 }
 
-func (c *Cast) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *Timestamp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
-func (c *Cast) String() string {
-	return fmt.Sprintf("CAST (%s AS %s)", c.val.String(), c.t)
+func (v *Timestamp) RawValue() interface{} {
+	return v.val
 }
 
-type Param struct {
-	id  string
-	pos int
-}
+func (v *Timestamp) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
+	}
 
-func (v *Param) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	t, ok := params[v.id]
-	if !ok {
-		params[v.id] = AnyType
-		return AnyType, nil
+	if val.Type() != TimestampType {
+		return 0, ErrNotComparableValues
 	}
 
-	return t, nil
-}
+	rval := val.RawValue().(time.Time)
 
-func (v *Param) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	currT, ok := params[v.id]
-	if ok && currT != t && currT != AnyType {
-		return ErrInferredMultipleTypes
+	if v.val.Before(rval) {
+		return -1, nil
 	}
 
-	params[v.id] = t
+	if v.val.After(rval) {
+		return 1, nil
+	}
 
-	return nil
+	return 0, nil
 }
 
-func (p *Param) substitute(params map[string]interface{}) (ValueExp, error) {
-	val, ok := params[p.id]
-	if !ok {
-		return nil, fmt.Errorf("%w(%s)", ErrMissingParameter, p.id)
-	}
-
-	if val == nil {
-		return &NullValue{t: AnyType}, nil
-	}
+type Varchar struct {
+	val string
+}
 
-	switch v := val.(type) {
-	case bool:
-		{
-			return &Bool{val: v}, nil
-		}
-	case string:
-		{
-			return &Varchar{val: v}, nil
-		}
-	case int:
-		{
-			return &Integer{val: int64(v)}, nil
-		}
-	case uint:
-		{
-			return &Integer{val: int64(v)}, nil
-		}
-	case uint64:
-		{
-			return &Integer{val: int64(v)}, nil
-		}
-	case int64:
-		{
-			return &Integer{val: v}, nil
-		}
-	case []byte:
-		{
-			return &Blob{val: v}, nil
-		}
-	case time.Time:
-		{
-			return &Timestamp{val: v.Truncate(time.Microsecond).UTC()}, nil
-		}
-	case float64:
-		{
-			return &Float64{val: v}, nil
-		}
-	}
-	return nil, ErrUnsupportedParameter
+func NewVarchar(val string) *Varchar {
+	return &Varchar{val: val}
 }
 
-func (p *Param) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return nil, ErrUnexpected
+func (v *Varchar) Type() SQLValueType {
+	return VarcharType
 }
 
-func (p *Param) selectors() []Selector {
-	return nil
+func (v *Varchar) IsNull() bool {
+	return false
 }
 
-func (p *Param) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return p
+func (v *Varchar) String() string {
+	return fmt.Sprintf("'%s'", v.val)
 }
 
-func (p *Param) isConstant() bool {
-	return true
+func (v *Varchar) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return VarcharType, nil
 }
 
-func (v *Param) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (v *Varchar) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	// strings are accepted as text representation of arrays e.g. '{1,2,3}'
+	if t != VarcharType && t != DecimalType && t != JSONType && !IsArrayType(t) {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+	}
 	return nil
 }
 
-func (v *Param) String() string {
-	return "@" + v.id
+func (v *Varchar) selectors() []Selector {
+	return nil
 }
 
-type whenThenClause struct {
-	when, then ValueExp
+func (v *Varchar) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
 }
 
-type CaseWhenExp struct {
-	exp      ValueExp
-	whenThen []whenThenClause
-	elseExp  ValueExp
+func (v *Varchar) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
 }
 
-func (ce *CaseWhenExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	checkType := func(e ValueExp, expectedType SQLValueType) (string, error) {
-		t, err := e.inferType(cols, params, implicitTable)
-		if err != nil {
-			return "", err
-		}
+func (v *Varchar) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
 
-		if expectedType == AnyType {
-			return t, nil
-		}
+func (v *Varchar) isConstant() bool {
+	return true
+}
 
-		if t != expectedType {
-			if (t == Float64Type && expectedType == IntegerType) ||
-				(t == IntegerType && expectedType == Float64Type) {
-				return Float64Type, nil
-			}
-			return "", fmt.Errorf("%w: CASE types %s and %s cannot be matched", ErrInferredMultipleTypes, expectedType, t)
-		}
-		return t, nil
+func (v *Varchar) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (v *Varchar) RawValue() interface{} {
+	return v.val
+}
+
+func (v *Varchar) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
 	}
 
-	searchType := BooleanType
-	inferredResType := AnyType
-	if ce.exp != nil {
-		t, err := ce.exp.inferType(cols, params, implicitTable)
-		if err != nil {
-			return "", err
-		}
-		searchType = t
+	if val.Type() == JSONType || val.Type() == DecimalType || IsArrayType(val.Type()) {
+		res, err := val.Compare(v)
+		return -res, err
 	}
 
-	for _, e := range ce.whenThen {
-		whenType, err := e.when.inferType(cols, params, implicitTable)
-		if err != nil {
-			return "", err
-		}
+	if val.Type() != VarcharType {
+		return 0, ErrNotComparableValues
+	}
 
-		if whenType != searchType {
-			return "", fmt.Errorf("%w: argument of CASE/WHEN must be of type %s, not type %s", ErrInvalidTypes, searchType, whenType)
-		}
+	rval := val.RawValue().(string)
 
-		t, err := checkType(e.then, inferredResType)
-		if err != nil {
-			return "", err
-		}
-		inferredResType = t
-	}
+	return bytes.Compare([]byte(v.val), []byte(rval)), nil
+}
 
-	if ce.elseExp != nil {
-		return checkType(ce.elseExp, inferredResType)
-	}
-	return inferredResType, nil
+type UUID struct {
+	val uuid.UUID
 }
 
-func (ce *CaseWhenExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	inferredType, err := ce.inferType(cols, params, implicitTable)
-	if err != nil {
-		return err
-	}
+func NewUUID(val uuid.UUID) *UUID {
+	return &UUID{val: val}
+}
 
-	if inferredType != t {
-		return fmt.Errorf("%w: expected type %s but %s found instead", ErrInvalidTypes, t, inferredType)
-	}
-	return nil
+func (v *UUID) Type() SQLValueType {
+	return UUIDType
 }
 
-func (ce *CaseWhenExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	var exp ValueExp
-	if ce.exp != nil {
-		e, err := ce.exp.substitute(params)
-		if err != nil {
-			return nil, err
-		}
-		exp = e
-	}
+func (v *UUID) IsNull() bool {
+	return false
+}
 
-	whenThen := make([]whenThenClause, len(ce.whenThen))
-	for i, wt := range ce.whenThen {
-		whenValue, err := wt.when.substitute(params)
-		if err != nil {
-			return nil, err
-		}
-		whenThen[i].when = whenValue
+func (v *UUID) String() string {
+	return v.val.String()
+}
 
-		thenValue, err := wt.then.substitute(params)
-		if err != nil {
-			return nil, err
-		}
-		whenThen[i].then = thenValue
-	}
+func (v *UUID) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return UUIDType, nil
+}
 
-	if ce.elseExp == nil {
-		return &CaseWhenExp{
-			exp:      exp,
-			whenThen: whenThen,
-		}, nil
+func (v *UUID) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != UUIDType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, UUIDType, t)
 	}
 
-	elseValue, err := ce.elseExp.substitute(params)
-	if err != nil {
-		return nil, err
-	}
-	return &CaseWhenExp{
-		exp:      exp,
-		whenThen: whenThen,
-		elseExp:  elseValue,
-	}, nil
+	return nil
 }
 
-func (ce *CaseWhenExp) selectors() []Selector {
-	selectors := make([]Selector, 0)
-	if ce.exp != nil {
-		selectors = append(selectors, ce.exp.selectors()...)
-	}
+func (v *UUID) selectors() []Selector {
+	return nil
+}
 
-	for _, wh := range ce.whenThen {
-		selectors = append(selectors, wh.when.selectors()...)
-		selectors = append(selectors, wh.then.selectors()...)
-	}
+func (v *UUID) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
 
-	if ce.elseExp == nil {
-		return selectors
-	}
-	return append(selectors, ce.elseExp.selectors()...)
+func (v *UUID) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
 }
 
-func (ce *CaseWhenExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	var searchValue TypedValue
-	if ce.exp != nil {
-		v, err := ce.exp.reduce(tx, row, implicitTable)
-		if err != nil {
-			return nil, err
-		}
-		searchValue = v
-	} else {
-		searchValue = &Bool{val: true}
-	}
+func (v *UUID) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
 
-	for _, wt := range ce.whenThen {
-		v, err := wt.when.reduce(tx, row, implicitTable)
-		if err != nil {
-			return nil, err
-		}
+func (v *UUID) isConstant() bool {
+	return true
+}
 
-		if v.Type() != searchValue.Type() {
-			return nil, fmt.Errorf("%w: argument of CASE/WHEN must be type %s, not type %s", ErrInvalidTypes, v.Type(), searchValue.Type())
-		}
+func (v *UUID) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
 
-		res, err := v.Compare(searchValue)
-		if err != nil {
-			return nil, err
-		}
-		if res == 0 {
-			return wt.then.reduce(tx, row, implicitTable)
-		}
+func (v *UUID) RawValue() interface{} {
+	return v.val
+}
+
+func (v *UUID) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
 	}
 
-	if ce.elseExp == nil {
-		return NewNull(AnyType), nil
+	if val.Type() != UUIDType {
+		return 0, ErrNotComparableValues
 	}
-	return ce.elseExp.reduce(tx, row, implicitTable)
-}
 
-func (ce *CaseWhenExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	whenThen := make([]whenThenClause, len(ce.whenThen))
-	for i, wt := range ce.whenThen {
-		whenValue := wt.when.reduceSelectors(row, implicitTable)
-		whenThen[i].when = whenValue
+	rval := val.RawValue().(uuid.UUID)
 
-		thenValue := wt.then.reduceSelectors(row, implicitTable)
-		whenThen[i].then = thenValue
-	}
+	return bytes.Compare(v.val[:], rval[:]), nil
+}
 
-	if ce.elseExp == nil {
-		return &CaseWhenExp{
-			whenThen: whenThen,
-		}
-	}
+type Bool struct {
+	val bool
+}
 
-	return &CaseWhenExp{
-		whenThen: whenThen,
-		elseExp:  ce.elseExp.reduceSelectors(row, implicitTable),
-	}
+func NewBool(val bool) *Bool {
+	return &Bool{val: val}
 }
 
-func (ce *CaseWhenExp) isConstant() bool {
+func (v *Bool) Type() SQLValueType {
+	return BooleanType
+}
+
+func (v *Bool) IsNull() bool {
 	return false
 }
 
-func (ce *CaseWhenExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
+func (v *Bool) String() string {
+	return strconv.FormatBool(v.val)
 }
 
-func (ce *CaseWhenExp) String() string {
-	var sb strings.Builder
-	for _, wh := range ce.whenThen {
-		sb.WriteString(fmt.Sprintf("WHEN %s THEN %s ", wh.when.String(), wh.then.String()))
-	}
+func (v *Bool) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return BooleanType, nil
+}
 
-	if ce.elseExp != nil {
-		sb.WriteString("ELSE " + ce.elseExp.String() + " ")
+func (v *Bool) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType && t != JSONType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
 	}
-	return "CASE " + sb.String() + "END"
+	return nil
 }
 
-type Comparison int
-
-const (
-	EqualTo Comparison = iota
-	LowerThan
-	LowerOrEqualTo
-	GreaterThan
-	GreaterOrEqualTo
-)
+func (v *Bool) selectors() []Selector {
+	return nil
+}
 
-type DataSource interface {
-	SQLStmt
-	Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error)
-	Alias() string
+func (v *Bool) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
 }
 
-type TargetEntry struct {
-	Exp ValueExp
-	As  string
+func (v *Bool) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
 }
 
-type SelectStmt struct {
-	distinct  bool
-	targets   []TargetEntry
-	selectors []Selector
-	ds        DataSource
-	indexOn   []string
-	joins     []*JoinSpec
-	where     ValueExp
-	groupBy   []*ColSelector
-	having    ValueExp
-	orderBy   []*OrdExp
-	limit     ValueExp
-	offset    ValueExp
-	as        string
+func (v *Bool) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
 }
 
-func NewSelectStmt(
-	targets []TargetEntry,
-	ds DataSource,
-	where ValueExp,
-	orderBy []*OrdExp,
-	limit ValueExp,
-	offset ValueExp,
-) *SelectStmt {
-	return &SelectStmt{
-		targets: targets,
-		ds:      ds,
-		where:   where,
-		orderBy: orderBy,
-		limit:   limit,
-		offset:  offset,
-	}
+func (v *Bool) isConstant() bool {
+	return true
 }
 
-func (stmt *SelectStmt) readOnly() bool {
-	return true
+func (v *Bool) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
 }
 
-func (stmt *SelectStmt) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeSelect}
+func (v *Bool) RawValue() interface{} {
+	return v.val
 }
 
-func (stmt *SelectStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	_, err := stmt.execAt(ctx, tx, nil)
-	if err != nil {
-		return err
+func (v *Bool) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
 	}
 
-	// TODO: (jeroiraz) may be optimized so to resolve the query statement just once
-	rowReader, err := stmt.Resolve(ctx, tx, nil, nil)
-	if err != nil {
-		return err
+	if val.Type() == JSONType {
+		res, err := val.Compare(v)
+		return -res, err
 	}
-	defer rowReader.Close()
-
-	return rowReader.InferParameters(ctx, params)
-}
 
-func (stmt *SelectStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	if stmt.groupBy == nil && stmt.having != nil {
-		return nil, ErrHavingClauseRequiresGroupClause
+	if val.Type() != BooleanType {
+		return 0, ErrNotComparableValues
 	}
 
-	if stmt.containsAggregations() || len(stmt.groupBy) > 0 {
-		for _, sel := range stmt.targetSelectors() {
-			_, isAgg := sel.(*AggColSelector)
-			if !isAgg && !stmt.groupByContains(sel) {
-				return nil, fmt.Errorf("%s: %w", EncodeSelector(sel.resolve(stmt.Alias())), ErrColumnMustAppearInGroupByOrAggregation)
-			}
-		}
-	}
+	rval := val.RawValue().(bool)
 
-	if len(stmt.orderBy) > 0 {
-		for _, col := range stmt.orderBy {
-			for _, sel := range col.exp.selectors() {
-				_, isAgg := sel.(*AggColSelector)
-				if (isAgg && !stmt.selectorAppearsInTargets(sel)) || (!isAgg && len(stmt.groupBy) > 0 && !stmt.groupByContains(sel)) {
-					return nil, fmt.Errorf("%s: %w", EncodeSelector(sel.resolve(stmt.Alias())), ErrColumnMustAppearInGroupByOrAggregation)
-				}
-			}
-		}
+	if v.val == rval {
+		return 0, nil
 	}
-	return tx, nil
-}
 
-func (stmt *SelectStmt) targetSelectors() []Selector {
-	if stmt.selectors == nil {
-		stmt.selectors = stmt.extractSelectors()
+	if v.val {
+		return 1, nil
 	}
-	return stmt.selectors
+
+	return -1, nil
 }
 
-func (stmt *SelectStmt) selectorAppearsInTargets(s Selector) bool {
-	encSel := EncodeSelector(s.resolve(stmt.Alias()))
+type Blob struct {
+	val []byte
+}
 
-	for _, sel := range stmt.targetSelectors() {
-		if EncodeSelector(sel.resolve(stmt.Alias())) == encSel {
-			return true
-		}
-	}
-	return false
+func NewBlob(val []byte) *Blob {
+	return &Blob{val: val}
 }
 
-func (stmt *SelectStmt) groupByContains(sel Selector) bool {
-	encSel := EncodeSelector(sel.resolve(stmt.Alias()))
+func (v *Blob) Type() SQLValueType {
+	return BLOBType
+}
 
-	for _, colSel := range stmt.groupBy {
-		if EncodeSelector(colSel.resolve(stmt.Alias())) == encSel {
-			return true
-		}
-	}
+func (v *Blob) IsNull() bool {
 	return false
 }
 
-func (stmt *SelectStmt) extractGroupByCols() []*AggColSelector {
-	cols := make([]*AggColSelector, 0, len(stmt.targets))
+func (v *Blob) String() string {
+	return hex.EncodeToString(v.val)
+}
 
-	for _, t := range stmt.targets {
-		selectors := t.Exp.selectors()
-		for _, sel := range selectors {
-			aggSel, isAgg := sel.(*AggColSelector)
-			if isAgg {
-				cols = append(cols, aggSel)
-			}
-		}
-	}
-	return cols
+func (v *Blob) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return BLOBType, nil
 }
 
-func (stmt *SelectStmt) extractSelectors() []Selector {
-	selectors := make([]Selector, 0, len(stmt.targets))
-	for _, t := range stmt.targets {
-		selectors = append(selectors, t.Exp.selectors()...)
+func (v *Blob) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BLOBType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BLOBType, t)
 	}
-	return selectors
+
+	return nil
 }
 
-func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (ret RowReader, err error) {
-	scanSpecs, err := stmt.genScanSpecs(tx, params)
-	if err != nil {
-		return nil, err
-	}
+func (v *Blob) selectors() []Selector {
+	return nil
+}
 
-	rowReader, err := stmt.ds.Resolve(ctx, tx, params, scanSpecs)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err != nil {
-			rowReader.Close()
-		}
-	}()
+func (v *Blob) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
 
-	if stmt.joins != nil {
-		var jointRowReader *jointRowReader
-		jointRowReader, err = newJointRowReader(rowReader, stmt.joins)
-		if err != nil {
-			return nil, err
-		}
-		rowReader = jointRowReader
-	}
+func (v *Blob) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
+}
 
-	if stmt.where != nil {
-		rowReader = newConditionalRowReader(rowReader, stmt.where)
-	}
+func (v *Blob) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
 
-	if stmt.containsAggregations() || len(stmt.groupBy) > 0 {
-		if len(scanSpecs.groupBySortExps) > 0 {
-			var sortRowReader *sortRowReader
-			sortRowReader, err = newSortRowReader(rowReader, scanSpecs.groupBySortExps)
-			if err != nil {
-				return nil, err
-			}
-			rowReader = sortRowReader
-		}
+func (v *Blob) isConstant() bool {
+	return true
+}
 
-		var groupedRowReader *groupedRowReader
-		groupedRowReader, err = newGroupedRowReader(rowReader, allAggregations(stmt.targets), stmt.extractGroupByCols(), stmt.groupBy)
-		if err != nil {
-			return nil, err
-		}
-		rowReader = groupedRowReader
+func (v *Blob) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
 
-		if stmt.having != nil {
-			rowReader = newConditionalRowReader(rowReader, stmt.having)
-		}
-	}
+func (v *Blob) RawValue() interface{} {
+	return v.val
+}
 
-	if len(scanSpecs.orderBySortExps) > 0 {
-		var sortRowReader *sortRowReader
-		sortRowReader, err = newSortRowReader(rowReader, stmt.orderBy)
-		if err != nil {
-			return nil, err
-		}
-		rowReader = sortRowReader
+func (v *Blob) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
 	}
 
-	projectedRowReader, err := newProjectedRowReader(ctx, rowReader, stmt.as, stmt.targets)
-	if err != nil {
-		return nil, err
+	if val.Type() != BLOBType {
+		return 0, ErrNotComparableValues
 	}
-	rowReader = projectedRowReader
 
-	if stmt.distinct {
-		var distinctRowReader *distinctRowReader
-		distinctRowReader, err = newDistinctRowReader(ctx, rowReader)
-		if err != nil {
-			return nil, err
-		}
-		rowReader = distinctRowReader
-	}
+	rval := val.RawValue().([]byte)
 
-	if stmt.offset != nil {
-		var offset int
-		offset, err = evalExpAsInt(tx, stmt.offset, params)
-		if err != nil {
-			return nil, fmt.Errorf("%w: invalid offset", err)
-		}
+	return bytes.Compare(v.val, rval), nil
+}
 
-		rowReader = newOffsetRowReader(rowReader, offset)
-	}
+type Float64 struct {
+	val float64
+}
 
-	if stmt.limit != nil {
-		var limit int
-		limit, err = evalExpAsInt(tx, stmt.limit, params)
-		if err != nil {
-			return nil, fmt.Errorf("%w: invalid limit", err)
-		}
+func NewFloat64(val float64) *Float64 {
+	return &Float64{val: val}
+}
 
-		if limit < 0 {
-			return nil, fmt.Errorf("%w: invalid limit", ErrIllegalArguments)
-		}
+func (v *Float64) Type() SQLValueType {
+	return Float64Type
+}
 
-		if limit > 0 {
-			rowReader = newLimitRowReader(rowReader, limit)
-		}
-	}
-	return rowReader, nil
+func (v *Float64) IsNull() bool {
+	return false
 }
 
-func (stmt *SelectStmt) rearrangeOrdExps(groupByCols, orderByExps []*OrdExp) ([]*OrdExp, []*OrdExp) {
-	if len(groupByCols) > 0 && len(orderByExps) > 0 && !ordExpsHaveAggregations(orderByExps) {
-		if ordExpsHasPrefix(orderByExps, groupByCols, stmt.Alias()) {
-			return orderByExps, nil
-		}
+func (v *Float64) String() string {
+	return strconv.FormatFloat(float64(v.val), 'f', -1, 64)
+}
 
-		if ordExpsHasPrefix(groupByCols, orderByExps, stmt.Alias()) {
-			for i := range orderByExps {
-				groupByCols[i].descOrder = orderByExps[i].descOrder
-			}
-			return groupByCols, nil
-		}
-	}
-	return groupByCols, orderByExps
+func (v *Float64) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return Float64Type, nil
 }
 
-func ordExpsHasPrefix(cols, prefix []*OrdExp, table string) bool {
-	if len(prefix) > len(cols) {
-		return false
+func (v *Float64) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != Float64Type && t != DecimalType && t != JSONType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, Float64Type, t)
 	}
+	return nil
+}
 
-	for i := range prefix {
-		ls := prefix[i].AsSelector()
-		rs := cols[i].AsSelector()
+func (v *Float64) selectors() []Selector {
+	return nil
+}
 
-		if ls == nil || rs == nil {
-			return false
-		}
+func (v *Float64) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
 
-		if EncodeSelector(ls.resolve(table)) != EncodeSelector(rs.resolve(table)) {
-			return false
-		}
-	}
-	return true
+func (v *Float64) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
 }
 
-func (stmt *SelectStmt) groupByOrdExps() []*OrdExp {
-	groupByCols := stmt.groupBy
+func (v *Float64) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
 
-	ordExps := make([]*OrdExp, 0, len(groupByCols))
-	for _, col := range groupByCols {
-		ordExps = append(ordExps, &OrdExp{exp: col})
-	}
-	return ordExps
+func (v *Float64) isConstant() bool {
+	return true
 }
 
-func ordExpsHaveAggregations(exps []*OrdExp) bool {
-	for _, e := range exps {
-		if _, isAgg := e.exp.(*AggColSelector); isAgg {
-			return true
-		}
-	}
-	return false
+func (v *Float64) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
 }
 
-func (stmt *SelectStmt) containsAggregations() bool {
-	for _, sel := range stmt.targetSelectors() {
-		_, isAgg := sel.(*AggColSelector)
-		if isAgg {
-			return true
-		}
-	}
-	return false
+func (v *Float64) RawValue() interface{} {
+	return v.val
 }
 
-func evalExpAsInt(tx *SQLTx, exp ValueExp, params map[string]interface{}) (int, error) {
-	offset, err := exp.substitute(params)
-	if err != nil {
-		return 0, err
+func (v *Float64) Compare(val TypedValue) (int, error) {
+	if val.Type() == JSONType {
+		res, err := val.Compare(v)
+		return -res, err
 	}
 
-	texp, err := offset.reduce(tx, nil, "")
+	convVal, err := mayApplyImplicitConversion(val.RawValue(), Float64Type)
 	if err != nil {
 		return 0, err
 	}
 
-	convVal, err := mayApplyImplicitConversion(texp.RawValue(), IntegerType)
-	if err != nil {
-		return 0, ErrInvalidValue
+	if convVal == nil {
+		return 1, nil
 	}
 
-	num, ok := convVal.(int64)
+	rval, ok := convVal.(float64)
 	if !ok {
-		return 0, ErrInvalidValue
+		return 0, ErrNotComparableValues
 	}
 
-	if num > math.MaxInt32 {
-		return 0, ErrInvalidValue
+	if v.val == rval {
+		return 0, nil
 	}
 
-	return int(num), nil
-}
-
-func (stmt *SelectStmt) Alias() string {
-	if stmt.as == "" {
-		return stmt.ds.Alias()
+	if v.val > rval {
+		return 1, nil
 	}
 
-	return stmt.as
+	return -1, nil
 }
 
-func (stmt *SelectStmt) hasTxMetadata() bool {
-	for _, sel := range stmt.targetSelectors() {
-		switch s := sel.(type) {
-		case *ColSelector:
-			if s.col == txMetadataCol {
-				return true
-			}
-		case *JSONSelector:
-			if s.ColSelector.col == txMetadataCol {
-				return true
-			}
-		}
-	}
-	return false
+type FnCall struct {
+	fn     string
+	params []ValueExp
 }
 
-func (stmt *SelectStmt) genScanSpecs(tx *SQLTx, params map[string]interface{}) (*ScanSpecs, error) {
-	groupByCols, orderByCols := stmt.groupByOrdExps(), stmt.orderBy
-
-	tableRef, isTableRef := stmt.ds.(*tableRef)
-	if !isTableRef {
-		groupByCols, orderByCols = stmt.rearrangeOrdExps(groupByCols, orderByCols)
+func (v *FnCall) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	fn, err := v.resolveFunc(tx)
+	if err != nil {
+		return AnyType, nil
+	}
 
-		return &ScanSpecs{
-			groupBySortExps: groupByCols,
-			orderBySortExps: orderByCols,
-		}, nil
+	if afn, ok := fn.(argTypedFunction); ok {
+		return afn.inferTypeFromArgs(tx, v.params, cols, params, implicitTable)
 	}
+	return fn.InferType(cols, params, implicitTable)
+}
 
-	table, err := tableRef.referencedTable(tx)
+func (v *FnCall) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	fn, err := v.resolveFunc(tx)
 	if err != nil {
-		if tx.engine.tableResolveFor(tableRef.table) != nil {
-			return &ScanSpecs{
-				groupBySortExps: groupByCols,
-				orderBySortExps: orderByCols,
-			}, nil
-		}
-		return nil, err
+		return err
 	}
 
-	rangesByColID := make(map[uint32]*typedValueRange)
-	if stmt.where != nil {
-		err = stmt.where.selectorRanges(table, tableRef.Alias(), params, rangesByColID)
+	if afn, ok := fn.(argTypedFunction); ok {
+		return afn.requiresTypeOfArgs(tx, t, v.params, cols, params, implicitTable)
+	}
+	return fn.RequiresType(t, cols, params, implicitTable)
+}
+
+func (v *FnCall) selectors() []Selector {
+	selectors := make([]Selector, 0)
+	for _, param := range v.params {
+		selectors = append(selectors, param.selectors()...)
+	}
+	return selectors
+}
+
+func (v *FnCall) substitute(params map[string]interface{}) (val ValueExp, err error) {
+	ps := make([]ValueExp, len(v.params))
+	for i, p := range v.params {
+		ps[i], err = p.substitute(params)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	preferredIndex, err := stmt.getPreferredIndex(table)
+	return &FnCall{
+		fn:     v.fn,
+		params: ps,
+	}, nil
+}
+
+func (v *FnCall) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	fn, err := v.resolveFunc(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	var sortingIndex *Index
-	if preferredIndex == nil {
-		sortingIndex = stmt.selectSortingIndex(groupByCols, orderByCols, table, rangesByColID)
-	} else {
-		sortingIndex = preferredIndex
+	fnInputs, err := v.reduceParams(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
 	}
+	return fn.Apply(tx, fnInputs)
+}
 
-	if sortingIndex == nil {
-		sortingIndex = table.primaryIndex
+func (v *FnCall) reduceParams(tx *SQLTx, row *Row, implicitTable string) ([]TypedValue, error) {
+	var values []TypedValue
+	if len(v.params) > 0 {
+		values = make([]TypedValue, len(v.params))
+		for i, p := range v.params {
+			v, err := p.reduce(tx, row, implicitTable)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
 	}
+	return values, nil
+}
 
-	if tableRef.history && !sortingIndex.IsPrimary() {
-		return nil, fmt.Errorf("%w: historical queries are supported over primary index", ErrIllegalArguments)
-	}
+// resolveFunc returns the builtin function or, otherwise, the function registered into the engine
+func (v *FnCall) resolveFunc(tx *SQLTx) (Function, error) {
+	fnName := strings.ToUpper(v.fn)
 
-	var descOrder bool
-	if len(groupByCols) > 0 && sortingIndex.coversOrdCols(groupByCols, rangesByColID) {
-		groupByCols = nil
+	fn, exists := builtinFunctions[fnName]
+	if exists {
+		return fn, nil
 	}
 
-	if len(groupByCols) == 0 && len(orderByCols) > 0 && sortingIndex.coversOrdCols(orderByCols, rangesByColID) {
-		descOrder = orderByCols[0].descOrder
-		orderByCols = nil
+	if tx != nil {
+		ufn, exists := tx.engine.userFunctionFor(fnName)
+		if exists {
+			return ufn.fn, nil
+		}
 	}
+	return nil, fmt.Errorf("%w: unknown function %s", ErrIllegalArguments, v.fn)
+}
 
-	groupByCols, orderByCols = stmt.rearrangeOrdExps(groupByCols, orderByCols)
+func (v *FnCall) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	ps := make([]ValueExp, len(v.params))
+	for i, p := range v.params {
+		ps[i] = p.reduceSelectors(row, implicitTable)
+	}
 
-	return &ScanSpecs{
-		Index:             sortingIndex,
-		rangesByColID:     rangesByColID,
-		IncludeHistory:    tableRef.history,
-		IncludeTxMetadata: stmt.hasTxMetadata(),
-		DescOrder:         descOrder,
-		groupBySortExps:   groupByCols,
-		orderBySortExps:   orderByCols,
-	}, nil
+	return &FnCall{
+		fn:     v.fn,
+		params: ps,
+	}
 }
 
-func (stmt *SelectStmt) selectSortingIndex(groupByCols, orderByCols []*OrdExp, table *Table, rangesByColId map[uint32]*typedValueRange) *Index {
-	sortCols := groupByCols
-	if len(sortCols) == 0 {
-		sortCols = orderByCols
+// isConstant holds when the function evaluates to the same value during the whole transaction,
+// so that calls such as NOW() can be used to narrow the ranges of the scanned keys.
+// User defined functions are not resolved at this point, thus they are never considered constant
+func (v *FnCall) isConstant() bool {
+	fnName := strings.ToUpper(v.fn)
+
+	switch fnName {
+	case UUIDFnCall, NextValFnCall, CurrValFnCall:
+		return false
 	}
 
-	if len(sortCols) == 0 {
-		return nil
+	if _, isBuiltin := builtinFunctions[fnName]; !isBuiltin {
+		return false
 	}
 
-	for _, idx := range table.indexes {
-		if idx.coversOrdCols(sortCols, rangesByColId) {
-			return idx
+	for _, p := range v.params {
+		if !p.isConstant() {
+			return false
 		}
 	}
+	return true
+}
+
+func (v *FnCall) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
-func (stmt *SelectStmt) getPreferredIndex(table *Table) (*Index, error) {
-	if len(stmt.indexOn) == 0 {
-		return nil, nil
+func (v *FnCall) String() string {
+	params := make([]string, len(v.params))
+	for i, p := range v.params {
+		params[i] = p.String()
 	}
+	return v.fn + "(" + strings.Join(params, ",") + ")"
+}
 
-	cols := make([]*Column, len(stmt.indexOn))
-	for i, colName := range stmt.indexOn {
-		col, err := table.GetColumnByName(colName)
-		if err != nil {
-			return nil, err
-		}
+// WindowFnExp represents a window function call, i.e. 'fn(params) OVER (PARTITION BY ... ORDER BY ...)'.
+// Its value is computed by a windowRowReader and made available in the row under the name of the expression.
+type WindowFnExp struct {
+	fn          string
+	params      []ValueExp
+	partitionBy []ValueExp
+	orderBy     []*OrdExp
+	name        string
+}
 
-		cols[i] = col
+func (w *WindowFnExp) colName() string {
+	if w.name == "" {
+		return w.String()
 	}
-	return table.GetIndexByName(indexName(table.name, cols))
+	return w.name
 }
 
-type UnionStmt struct {
-	distinct    bool
-	left, right DataSource
-}
+func (w *WindowFnExp) windowSpec() string {
+	var sb strings.Builder
 
-func (stmt *UnionStmt) readOnly() bool {
-	return true
-}
+	if len(w.partitionBy) > 0 {
+		sb.WriteString("PARTITION BY ")
 
-func (stmt *UnionStmt) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeSelect}
-}
+		for i, e := range w.partitionBy {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(e.String())
+		}
+	}
 
-func (stmt *UnionStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	err := stmt.left.inferParameters(ctx, tx, params)
-	if err != nil {
-		return err
+	if len(w.orderBy) > 0 {
+		if len(w.partitionBy) > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("ORDER BY ")
+
+		for i, oe := range w.orderBy {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(oe.exp.String())
+
+			if oe.descOrder {
+				sb.WriteString(" DESC")
+			}
+		}
 	}
-	return stmt.right.inferParameters(ctx, tx, params)
+	return sb.String()
 }
 
-func (stmt *UnionStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	_, err := stmt.left.execAt(ctx, tx, params)
-	if err != nil {
-		return tx, err
+func (w *WindowFnExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	col, ok := cols[EncodeSelector("", implicitTable, w.colName())]
+	if ok {
+		return col.Type, nil
 	}
-
-	return stmt.right.execAt(ctx, tx, params)
+	return w.valueType(tx, cols, params, implicitTable)
 }
 
-func (stmt *UnionStmt) resolveUnionAll(ctx context.Context, tx *SQLTx, params map[string]interface{}) (ret RowReader, err error) {
-	leftRowReader, err := stmt.left.Resolve(ctx, tx, params, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err != nil {
-			leftRowReader.Close()
+func (w *WindowFnExp) valueType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	for _, e := range w.partitionBy {
+		if _, err := e.inferType(tx, cols, params, implicitTable); err != nil {
+			return AnyType, err
 		}
-	}()
+	}
 
-	rightRowReader, err := stmt.right.Resolve(ctx, tx, params, nil)
-	if err != nil {
-		return nil, err
+	for _, oe := range w.orderBy {
+		if _, err := oe.exp.inferType(tx, cols, params, implicitTable); err != nil {
+			return AnyType, err
+		}
 	}
-	defer func() {
-		if err != nil {
-			rightRowReader.Close()
+
+	switch strings.ToUpper(w.fn) {
+	case RowNumberWindowFn, RankWindowFn, DenseRankWindowFn, COUNT:
+		{
+			return IntegerType, nil
 		}
-	}()
+	case LagWindowFn, LeadWindowFn, SUM, MIN, MAX, AVG:
+		{
+			if len(w.params) == 0 {
+				return AnyType, fmt.Errorf("%w: '%s' window function expects at least one argument", ErrIllegalArguments, w.fn)
+			}
+			return w.params[0].inferType(tx, cols, params, implicitTable)
+		}
+	}
+	return AnyType, fmt.Errorf("%w: unknown window function %s", ErrIllegalArguments, w.fn)
+}
 
-	rowReader, err := newUnionRowReader(ctx, []RowReader{leftRowReader, rightRowReader})
+func (w *WindowFnExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	it, err := w.inferType(tx, cols, params, implicitTable)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return rowReader, nil
+	if it != t {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, it, t)
+	}
+	return nil
 }
 
-func (stmt *UnionStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (ret RowReader, err error) {
-	rowReader, err := stmt.resolveUnionAll(ctx, tx, params)
-	if err != nil {
-		return nil, err
+func (w *WindowFnExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	ps := make([]ValueExp, len(w.params))
+	for i, p := range w.params {
+		sp, err := p.substitute(params)
+		if err != nil {
+			return nil, err
+		}
+		ps[i] = sp
 	}
-	defer func() {
+
+	partitionBy := make([]ValueExp, len(w.partitionBy))
+	for i, e := range w.partitionBy {
+		se, err := e.substitute(params)
 		if err != nil {
-			rowReader.Close()
+			return nil, err
 		}
-	}()
+		partitionBy[i] = se
+	}
 
-	if stmt.distinct {
-		distinctReader, err := newDistinctRowReader(ctx, rowReader)
+	orderBy := make([]*OrdExp, len(w.orderBy))
+	for i, oe := range w.orderBy {
+		se, err := oe.exp.substitute(params)
 		if err != nil {
 			return nil, err
 		}
-		rowReader = distinctReader
+		orderBy[i] = &OrdExp{exp: se, descOrder: oe.descOrder}
 	}
 
-	return rowReader, nil
+	return &WindowFnExp{
+		fn:          w.fn,
+		params:      ps,
+		partitionBy: partitionBy,
+		orderBy:     orderBy,
+		name:        w.colName(),
+	}, nil
 }
 
-func (stmt *UnionStmt) Alias() string {
-	return ""
+func (w *WindowFnExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	if row == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWindowFnUsage, w.colName())
+	}
+
+	v, ok := row.ValuesBySelector[EncodeSelector("", implicitTable, w.colName())]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWindowFnUsage, w.colName())
+	}
+	return v, nil
 }
 
-func NewTableRef(table string, as string) *tableRef {
-	return &tableRef{
-		table: table,
-		as:    as,
+func (w *WindowFnExp) selectors() []Selector {
+	selectors := make([]Selector, 0)
+	for _, p := range w.params {
+		selectors = append(selectors, p.selectors()...)
+	}
+
+	for _, e := range w.partitionBy {
+		selectors = append(selectors, e.selectors()...)
+	}
+
+	for _, oe := range w.orderBy {
+		selectors = append(selectors, oe.exp.selectors()...)
 	}
+	return selectors
 }
 
-type tableRef struct {
-	table   string
-	history bool
-	period  period
-	as      string
+func (w *WindowFnExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	if row == nil {
+		return w
+	}
+
+	v, ok := row.ValuesBySelector[EncodeSelector("", implicitTable, w.colName())]
+	if !ok {
+		return w
+	}
+	return v
 }
 
-func (ref *tableRef) readOnly() bool {
-	return true
+func (w *WindowFnExp) isConstant() bool {
+	return false
 }
 
-func (ref *tableRef) requiredPrivileges() []SQLPrivilege {
-	return []SQLPrivilege{SQLPrivilegeSelect}
+func (w *WindowFnExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
 }
 
-type period struct {
-	start *openPeriod
-	end   *openPeriod
+func (w *WindowFnExp) String() string {
+	params := make([]string, len(w.params))
+	for i, p := range w.params {
+		params[i] = p.String()
+	}
+
+	if len(params) == 0 && strings.ToUpper(w.fn) == COUNT {
+		params = []string{"*"}
+	}
+	return strings.ToUpper(w.fn) + "(" + strings.Join(params, ",") + ") OVER (" + w.windowSpec() + ")"
 }
 
-type openPeriod struct {
-	inclusive bool
-	instant   periodInstant
+type Cast struct {
+	val    ValueExp
+	t      SQLValueType
+	maxLen int
 }
 
-type periodInstant struct {
-	exp         ValueExp
-	instantType instantType
+func (c *Cast) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	_, err := c.val.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	// val type may be restricted by compatible conversions, but multiple types may be compatible...
+
+	return c.t, nil
 }
 
-type instantType = int
+func (c *Cast) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if c.t != t {
+		return fmt.Errorf("%w: can not use value cast to %s as %s", ErrInvalidTypes, c.t, t)
+	}
 
-const (
-	txInstant instantType = iota
-	timeInstant
-)
+	return nil
+}
 
-func (i periodInstant) resolve(tx *SQLTx, params map[string]interface{}, asc, inclusive bool) (uint64, error) {
-	exp, err := i.exp.substitute(params)
+func (c *Cast) substitute(params map[string]interface{}) (ValueExp, error) {
+	val, err := c.val.substitute(params)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	return &Cast{
+		val:    val,
+		t:      c.t,
+		maxLen: c.maxLen,
+	}, nil
+}
 
-	instantVal, err := exp.reduce(tx, nil, "")
+func (c *Cast) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	val, err := c.val.reduce(tx, row, implicitTable)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	if i.instantType == txInstant {
-		txID, ok := instantVal.RawValue().(int64)
-		if !ok {
-			return 0, fmt.Errorf("%w: invalid tx range, tx ID must be a positive integer, %s given", ErrIllegalArguments, instantVal.Type())
-		}
+	conv, err := getConverter(val.Type(), c.t)
+	if conv == nil {
+		return nil, err
+	}
 
-		if txID <= 0 {
-			return 0, fmt.Errorf("%w: invalid tx range, tx ID must be a positive integer, %d given", ErrIllegalArguments, txID)
-		}
+	cval, err := conv(val)
+	if err != nil {
+		return nil, err
+	}
 
-		if inclusive {
-			return uint64(txID), nil
-		}
+	if _, ok := cval.(*Array); ok && c.maxLen > 0 {
+		return fitArray(cval, c.t, c.maxLen)
+	}
 
-		if asc {
-			return uint64(txID + 1), nil
+	if d, ok := cval.(*Decimal); ok && c.maxLen > 0 {
+		fitted, err := d.fit(c.maxLen)
+		if err != nil {
+			return nil, err
 		}
+		return fitted, nil
+	}
+	return cval, nil
+}
 
-		if txID <= 1 {
-			return 0, fmt.Errorf("%w: invalid tx range, tx ID must be greater than 1, %d given", ErrIllegalArguments, txID)
-		}
+func (v *Cast) selectors() []Selector {
+	return v.val.selectors()
+}
 
-		return uint64(txID - 1), nil
-	} else {
+func (c *Cast) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &Cast{
+		val:    c.val.reduceSelectors(row, implicitTable),
+		t:      c.t,
+		maxLen: c.maxLen,
+	}
+}
 
-		var ts time.Time
+func (c *Cast) isConstant() bool {
+	return c.val.isConstant()
+}
 
-		if instantVal.Type() == TimestampType {
-			ts = instantVal.RawValue().(time.Time)
-		} else {
-			conv, err := getConverter(instantVal.Type(), TimestampType)
-			if err != nil {
-				return 0, err
-			}
+func (c *Cast) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
 
-			tval, err := conv(instantVal)
-			if err != nil {
-				return 0, err
-			}
+func (c *Cast) String() string {
+	t := c.t
+	if ArrayElemType(c.t) == DecimalType {
+		// precision and scale are part of the type, either of the value or of the elements of the array
+		t = decimalTypeString(c.maxLen) + strings.TrimPrefix(c.t, DecimalType)
+	}
+	return fmt.Sprintf("CAST (%s AS %s)", c.val.String(), t)
+}
 
-			ts = tval.RawValue().(time.Time)
-		}
+type Param struct {
+	id  string
+	pos int
+}
 
-		sts := ts
+func (v *Param) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	t, ok := params[v.id]
+	if !ok {
+		params[v.id] = AnyType
+		return AnyType, nil
+	}
 
-		if asc {
-			if !inclusive {
-				sts = sts.Add(1 * time.Second)
-			}
-
-			txHdr, err := tx.engine.store.FirstTxSince(sts)
-			if err != nil {
-				return 0, err
-			}
-
-			return txHdr.ID, nil
-		}
-
-		if !inclusive {
-			sts = sts.Add(-1 * time.Second)
-		}
-
-		txHdr, err := tx.engine.store.LastTxUntil(sts)
-		if err != nil {
-			return 0, err
-		}
-
-		return txHdr.ID, nil
-	}
+	return t, nil
 }
 
-func (stmt *tableRef) referencedTable(tx *SQLTx) (*Table, error) {
-	table, err := tx.catalog.GetTableByName(stmt.table)
-	if err != nil {
-		return nil, err
+func (v *Param) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	currT, ok := params[v.id]
+	if ok && currT != t && currT != AnyType {
+		return ErrInferredMultipleTypes
 	}
-	return table, nil
-}
 
-func (stmt *tableRef) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	params[v.id] = t
+
 	return nil
 }
 
-func (stmt *tableRef) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	return tx, nil
-}
+func (p *Param) substitute(params map[string]interface{}) (ValueExp, error) {
+	val, ok := params[p.id]
+	if !ok {
+		return nil, fmt.Errorf("%w(%s)", ErrMissingParameter, p.id)
+	}
 
-func (stmt *tableRef) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error) {
-	if tx == nil {
-		return nil, ErrIllegalArguments
+	if val == nil {
+		return &NullValue{t: AnyType}, nil
 	}
 
-	table, err := stmt.referencedTable(tx)
-	if err == nil {
-		return newRawRowReader(tx, params, table, stmt.period, stmt.as, scanSpecs)
+	switch v := val.(type) {
+	case bool:
+		{
+			return &Bool{val: v}, nil
+		}
+	case string:
+		{
+			return &Varchar{val: v}, nil
+		}
+	case int:
+		{
+			return &Integer{val: int64(v)}, nil
+		}
+	case uint:
+		{
+			return &Integer{val: int64(v)}, nil
+		}
+	case uint64:
+		{
+			return &Integer{val: int64(v)}, nil
+		}
+	case int64:
+		{
+			return &Integer{val: v}, nil
+		}
+	case []byte:
+		{
+			return &Blob{val: v}, nil
+		}
+	case time.Time:
+		{
+			return &Timestamp{val: v.Truncate(time.Microsecond).UTC()}, nil
+		}
+	case time.Duration:
+		{
+			return &Interval{micros: v.Microseconds()}, nil
+		}
+	case float64:
+		{
+			return &Float64{val: v}, nil
+		}
 	}
 
-	if resolver := tx.engine.tableResolveFor(stmt.table); resolver != nil {
-		return resolver.Resolve(ctx, tx, stmt.Alias())
+	if elems, ok := arrayElems(val); ok {
+		return arrayFromSlice(p.id, elems)
 	}
-	return nil, err
+	return nil, ErrUnsupportedParameter
 }
 
-func (stmt *tableRef) Alias() string {
-	if stmt.as == "" {
-		return stmt.table
-	}
-	return stmt.as
+func (p *Param) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return nil, ErrUnexpected
 }
 
-type valuesDataSource struct {
-	inferTypes bool
-	rows       []*RowSpec
+func (p *Param) selectors() []Selector {
+	return nil
 }
 
-func NewValuesDataSource(rows []*RowSpec) *valuesDataSource {
-	return &valuesDataSource{
-		rows: rows,
-	}
+func (p *Param) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return p
 }
 
-func (ds *valuesDataSource) readOnly() bool {
+func (p *Param) isConstant() bool {
 	return true
 }
 
-func (ds *valuesDataSource) requiredPrivileges() []SQLPrivilege {
+func (v *Param) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
-func (ds *valuesDataSource) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	return tx, nil
+func (v *Param) String() string {
+	return "@" + v.id
 }
 
-func (ds *valuesDataSource) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	return nil
+type whenThenClause struct {
+	when, then ValueExp
 }
 
-func (ds *valuesDataSource) Alias() string {
-	return ""
+type CaseWhenExp struct {
+	exp      ValueExp
+	whenThen []whenThenClause
+	elseExp  ValueExp
 }
 
-func (ds *valuesDataSource) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error) {
-	if tx == nil {
-		return nil, ErrIllegalArguments
-	}
+func (ce *CaseWhenExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	checkType := func(e ValueExp, expectedType SQLValueType) (string, error) {
+		t, err := e.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return "", err
+		}
 
-	cols := make([]ColDescriptor, len(ds.rows[0].Values))
-	for i := range cols {
-		cols[i] = ColDescriptor{
-			Type:   AnyType,
-			Column: fmt.Sprintf("col%d", i),
+		if expectedType == AnyType {
+			return t, nil
 		}
+
+		if t != expectedType {
+			if (t == Float64Type && expectedType == IntegerType) ||
+				(t == IntegerType && expectedType == Float64Type) {
+				return Float64Type, nil
+			}
+			return "", fmt.Errorf("%w: CASE types %s and %s cannot be matched", ErrInferredMultipleTypes, expectedType, t)
+		}
+		return t, nil
 	}
 
-	emptyColsDesc, emptyParams := map[string]ColDescriptor{}, map[string]string{}
+	searchType := BooleanType
+	inferredResType := AnyType
+	if ce.exp != nil {
+		t, err := ce.exp.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return "", err
+		}
+		searchType = t
+	}
 
-	if ds.inferTypes {
-		for i := 0; i < len(cols); i++ {
-			t := AnyType
-			for j := 0; j < len(ds.rows); j++ {
-				e, err := ds.rows[j].Values[i].substitute(params)
-				if err != nil {
-					return nil, err
-				}
+	for _, e := range ce.whenThen {
+		whenType, err := e.when.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return "", err
+		}
 
-				it, err := e.inferType(emptyColsDesc, emptyParams, "")
-				if err != nil {
-					return nil, err
-				}
+		if whenType != searchType {
+			return "", fmt.Errorf("%w: argument of CASE/WHEN must be of type %s, not type %s", ErrInvalidTypes, searchType, whenType)
+		}
 
-				if t == AnyType {
-					t = it
-				} else if t != it && it != AnyType {
-					return nil, fmt.Errorf("cannot match types %s and %s", t, it)
-				}
-			}
-			cols[i].Type = t
+		t, err := checkType(e.then, inferredResType)
+		if err != nil {
+			return "", err
 		}
+		inferredResType = t
 	}
 
-	values := make([][]ValueExp, len(ds.rows))
-	for i, rowSpec := range ds.rows {
-		values[i] = rowSpec.Values
+	if ce.elseExp != nil {
+		return checkType(ce.elseExp, inferredResType)
 	}
-	return NewValuesRowReader(tx, params, cols, ds.inferTypes, "values", values)
-}
-
-type JoinSpec struct {
-	joinType JoinType
-	ds       DataSource
-	cond     ValueExp
-	indexOn  []string
+	return inferredResType, nil
 }
 
-type OrdExp struct {
-	exp       ValueExp
-	descOrder bool
-}
+func (ce *CaseWhenExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	inferredType, err := ce.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
 
-func (oc *OrdExp) AsSelector() Selector {
-	sel, ok := oc.exp.(Selector)
-	if ok {
-		return sel
+	if inferredType != t {
+		return fmt.Errorf("%w: expected type %s but %s found instead", ErrInvalidTypes, t, inferredType)
 	}
 	return nil
 }
 
-func NewOrdCol(table string, col string, descOrder bool) *OrdExp {
-	return &OrdExp{
-		exp:       NewColSelector(table, col),
-		descOrder: descOrder,
+func (ce *CaseWhenExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	var exp ValueExp
+	if ce.exp != nil {
+		e, err := ce.exp.substitute(params)
+		if err != nil {
+			return nil, err
+		}
+		exp = e
 	}
-}
-
-type Selector interface {
-	ValueExp
-	resolve(implicitTable string) (aggFn, table, col string)
-}
 
-type ColSelector struct {
-	table string
-	col   string
-}
+	whenThen := make([]whenThenClause, len(ce.whenThen))
+	for i, wt := range ce.whenThen {
+		whenValue, err := wt.when.substitute(params)
+		if err != nil {
+			return nil, err
+		}
+		whenThen[i].when = whenValue
 
-func NewColSelector(table, col string) *ColSelector {
-	return &ColSelector{
-		table: table,
-		col:   col,
+		thenValue, err := wt.then.substitute(params)
+		if err != nil {
+			return nil, err
+		}
+		whenThen[i].then = thenValue
 	}
-}
 
-func (sel *ColSelector) resolve(implicitTable string) (aggFn, table, col string) {
-	table = implicitTable
-	if sel.table != "" {
-		table = sel.table
+	if ce.elseExp == nil {
+		return &CaseWhenExp{
+			exp:      exp,
+			whenThen: whenThen,
+		}, nil
 	}
-	return "", table, sel.col
-}
-
-func (sel *ColSelector) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	_, table, col := sel.resolve(implicitTable)
-	encSel := EncodeSelector("", table, col)
 
-	desc, ok := cols[encSel]
-	if !ok {
-		return AnyType, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
+	elseValue, err := ce.elseExp.substitute(params)
+	if err != nil {
+		return nil, err
 	}
-	return desc.Type, nil
+	return &CaseWhenExp{
+		exp:      exp,
+		whenThen: whenThen,
+		elseExp:  elseValue,
+	}, nil
 }
 
-func (sel *ColSelector) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	_, table, col := sel.resolve(implicitTable)
-	encSel := EncodeSelector("", table, col)
-
-	desc, ok := cols[encSel]
-	if !ok {
-		return fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
+func (ce *CaseWhenExp) selectors() []Selector {
+	selectors := make([]Selector, 0)
+	if ce.exp != nil {
+		selectors = append(selectors, ce.exp.selectors()...)
 	}
 
-	if desc.Type != t {
-		return fmt.Errorf("%w: %v(%s) can not be interpreted as type %v", ErrInvalidTypes, desc.Type, encSel, t)
+	for _, wh := range ce.whenThen {
+		selectors = append(selectors, wh.when.selectors()...)
+		selectors = append(selectors, wh.then.selectors()...)
 	}
 
-	return nil
-}
-
-func (sel *ColSelector) substitute(params map[string]interface{}) (ValueExp, error) {
-	return sel, nil
+	if ce.elseExp == nil {
+		return selectors
+	}
+	return append(selectors, ce.elseExp.selectors()...)
 }
 
-func (sel *ColSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	if row == nil {
-		return nil, fmt.Errorf("%w: no row to evaluate in current context", ErrInvalidValue)
+func (ce *CaseWhenExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	var searchValue TypedValue
+	if ce.exp != nil {
+		v, err := ce.exp.reduce(tx, row, implicitTable)
+		if err != nil {
+			return nil, err
+		}
+		searchValue = v
+	} else {
+		searchValue = &Bool{val: true}
 	}
 
-	aggFn, table, col := sel.resolve(implicitTable)
+	for _, wt := range ce.whenThen {
+		v, err := wt.when.reduce(tx, row, implicitTable)
+		if err != nil {
+			return nil, err
+		}
 
-	v, ok := row.ValuesBySelector[EncodeSelector(aggFn, table, col)]
-	if !ok {
-		return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
+		if v.Type() != searchValue.Type() {
+			return nil, fmt.Errorf("%w: argument of CASE/WHEN must be type %s, not type %s", ErrInvalidTypes, v.Type(), searchValue.Type())
+		}
+
+		res, err := v.Compare(searchValue)
+		if err != nil {
+			return nil, err
+		}
+		if res == 0 {
+			return wt.then.reduce(tx, row, implicitTable)
+		}
 	}
-	return v, nil
-}
 
-func (sel *ColSelector) selectors() []Selector {
-	return []Selector{sel}
+	if ce.elseExp == nil {
+		return NewNull(AnyType), nil
+	}
+	return ce.elseExp.reduce(tx, row, implicitTable)
 }
 
-func (sel *ColSelector) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	aggFn, table, col := sel.resolve(implicitTable)
+func (ce *CaseWhenExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	whenThen := make([]whenThenClause, len(ce.whenThen))
+	for i, wt := range ce.whenThen {
+		whenValue := wt.when.reduceSelectors(row, implicitTable)
+		whenThen[i].when = whenValue
 
-	v, ok := row.ValuesBySelector[EncodeSelector(aggFn, table, col)]
-	if !ok {
-		return sel
+		thenValue := wt.then.reduceSelectors(row, implicitTable)
+		whenThen[i].then = thenValue
 	}
 
-	return v
+	if ce.elseExp == nil {
+		return &CaseWhenExp{
+			whenThen: whenThen,
+		}
+	}
+
+	return &CaseWhenExp{
+		whenThen: whenThen,
+		elseExp:  ce.elseExp.reduceSelectors(row, implicitTable),
+	}
 }
 
-func (sel *ColSelector) isConstant() bool {
+func (ce *CaseWhenExp) isConstant() bool {
 	return false
 }
 
-func (sel *ColSelector) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+func (ce *CaseWhenExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
 
-func (sel *ColSelector) String() string {
-	return sel.col
+func (ce *CaseWhenExp) String() string {
+	var sb strings.Builder
+	for _, wh := range ce.whenThen {
+		sb.WriteString(fmt.Sprintf("WHEN %s THEN %s ", wh.when.String(), wh.then.String()))
+	}
+
+	if ce.elseExp != nil {
+		sb.WriteString("ELSE " + ce.elseExp.String() + " ")
+	}
+	return "CASE " + sb.String() + "END"
 }
 
-type AggColSelector struct {
-	aggFn AggregateFn
-	table string
-	col   string
+type Comparison int
+
+const (
+	EqualTo Comparison = iota
+	LowerThan
+	LowerOrEqualTo
+	GreaterThan
+	GreaterOrEqualTo
+)
+
+type DataSource interface {
+	SQLStmt
+	Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error)
+	Alias() string
 }
 
-func NewAggColSelector(aggFn AggregateFn, table, col string) *AggColSelector {
-	return &AggColSelector{
-		aggFn: aggFn,
-		table: table,
-		col:   col,
-	}
+type TargetEntry struct {
+	Exp ValueExp
+	As  string
 }
 
-func EncodeSelector(aggFn, table, col string) string {
-	return aggFn + "(" + table + "." + col + ")"
+type SelectStmt struct {
+	distinct  bool
+	targets   []TargetEntry
+	selectors []Selector
+	ds        DataSource
+	indexOn   []*indexColSpec
+	joins     []*JoinSpec
+	where     ValueExp
+	groupBy   []*ColSelector
+	having    ValueExp
+	orderBy   []*OrdExp
+	limit     ValueExp
+	offset    ValueExp
+	as        string
 }
 
-func (sel *AggColSelector) resolve(implicitTable string) (aggFn, table, col string) {
-	table = implicitTable
-	if sel.table != "" {
-		table = sel.table
+func NewSelectStmt(
+	targets []TargetEntry,
+	ds DataSource,
+	where ValueExp,
+	orderBy []*OrdExp,
+	limit ValueExp,
+	offset ValueExp,
+) *SelectStmt {
+	return &SelectStmt{
+		targets: targets,
+		ds:      ds,
+		where:   where,
+		orderBy: orderBy,
+		limit:   limit,
+		offset:  offset,
 	}
-	return sel.aggFn, table, sel.col
 }
 
-func (sel *AggColSelector) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	if sel.aggFn == COUNT {
-		return IntegerType, nil
+// readOnly returns false when the query calls NEXTVAL, as advancing a sequence requires writing its new value
+func (stmt *SelectStmt) readOnly() bool {
+	if !isReadOnlyDataSource(stmt.ds) {
+		return false
 	}
 
-	colSelector := &ColSelector{table: sel.table, col: sel.col}
-
-	if sel.aggFn == SUM || sel.aggFn == AVG {
-		t, err := colSelector.inferType(cols, params, implicitTable)
-		if err != nil {
-			return AnyType, err
-		}
-
-		if t != IntegerType && t != Float64Type {
-			return AnyType, fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, t)
-
+	for _, jspec := range stmt.joins {
+		if !isReadOnlyDataSource(jspec.ds) || advancesSequences(jspec.cond) {
+			return false
 		}
-
-		return t, nil
 	}
 
-	return colSelector.inferType(cols, params, implicitTable)
-}
-
-func (sel *AggColSelector) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if sel.aggFn == COUNT {
-		if t != IntegerType {
-			return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	for _, t := range stmt.targets {
+		if advancesSequences(t.Exp) {
+			return false
 		}
-		return nil
 	}
 
-	colSelector := &ColSelector{table: sel.table, col: sel.col}
-
-	if sel.aggFn == SUM || sel.aggFn == AVG {
-		if t != IntegerType && t != Float64Type {
-			return fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, t)
+	for _, oe := range stmt.orderBy {
+		if advancesSequences(oe.exp) {
+			return false
 		}
 	}
 
-	return colSelector.requiresType(t, cols, params, implicitTable)
+	return !advancesSequences(stmt.where) && !advancesSequences(stmt.having)
 }
 
-func (sel *AggColSelector) substitute(params map[string]interface{}) (ValueExp, error) {
-	return sel, nil
+func isReadOnlyDataSource(ds DataSource) bool {
+	return ds == nil || ds.readOnly()
 }
 
-func (sel *AggColSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	if row == nil {
-		return nil, fmt.Errorf("%w: no row to evaluate aggregation (%s) in current context", ErrInvalidValue, sel.aggFn)
-	}
-
-	v, ok := row.ValuesBySelector[EncodeSelector(sel.resolve(implicitTable))]
-	if !ok {
-		return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, sel.col)
-	}
-	return v, nil
-}
+// advancesSequences returns true if the expression calls NEXTVAL, either directly or within a sub-query
+func advancesSequences(exp ValueExp) bool {
+	found := false
 
-func (sel *AggColSelector) selectors() []Selector {
-	return []Selector{sel}
-}
+	visitExp(exp, func(e ValueExp) bool {
+		switch se := e.(type) {
+		case *FnCall:
+			found = strings.ToUpper(se.fn) == NextValFnCall
+		case *ExistsBoolExp:
+			found = !isReadOnlyDataSource(se.q)
+		case *InSubQueryExp:
+			found = !isReadOnlyDataSource(se.q)
+		}
+		return !found
+	})
 
-func (sel *AggColSelector) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return sel
+	return found
 }
 
-func (sel *AggColSelector) isConstant() bool {
-	return false
+func (stmt *SelectStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeSelect}
 }
 
-func (sel *AggColSelector) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+func (stmt *SelectStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	_, err := stmt.execAt(ctx, tx, nil)
+	if err != nil {
+		return err
+	}
 
-func (sel *AggColSelector) String() string {
-	return sel.aggFn + "(" + sel.col + ")"
-}
+	// TODO: (jeroiraz) may be optimized so to resolve the query statement just once
+	rowReader, err := stmt.Resolve(ctx, tx, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer rowReader.Close()
 
-type NumExp struct {
-	op          NumOperator
-	left, right ValueExp
+	return rowReader.InferParameters(ctx, params)
 }
 
-func (bexp *NumExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	// First step - check if we can infer the type of sub-expressions
-	tleft, err := bexp.left.inferType(cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
-	}
-	if tleft != AnyType && tleft != IntegerType && tleft != Float64Type && tleft != JSONType {
-		return AnyType, fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, tleft)
-	}
-
-	tright, err := bexp.right.inferType(cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
-	}
-	if tright != AnyType && tright != IntegerType && tright != Float64Type && tright != JSONType {
-		return AnyType, fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, tright)
+func (stmt *SelectStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	if stmt.groupBy == nil && stmt.having != nil {
+		return nil, ErrHavingClauseRequiresGroupClause
 	}
 
-	if tleft == IntegerType && tright == IntegerType {
-		// Both sides are integer types - the result is also integer
-		return IntegerType, nil
+	if stmt.containsAggregations() || len(stmt.groupBy) > 0 {
+		for _, sel := range stmt.targetSelectors() {
+			_, isAgg := sel.(*AggColSelector)
+			if !isAgg && !stmt.groupByContains(sel) {
+				return nil, fmt.Errorf("%s: %w", EncodeSelector(sel.resolve(stmt.Alias())), ErrColumnMustAppearInGroupByOrAggregation)
+			}
+		}
 	}
 
-	if tleft != AnyType && tright != AnyType {
-		// Both sides have concrete types but at least one of them is float
-		return Float64Type, nil
+	if len(stmt.orderBy) > 0 {
+		for _, col := range stmt.orderBy {
+			for _, sel := range col.exp.selectors() {
+				_, isAgg := sel.(*AggColSelector)
+				if (isAgg && !stmt.selectorAppearsInTargets(sel)) || (!isAgg && len(stmt.groupBy) > 0 && !stmt.groupByContains(sel)) {
+					return nil, fmt.Errorf("%s: %w", EncodeSelector(sel.resolve(stmt.Alias())), ErrColumnMustAppearInGroupByOrAggregation)
+				}
+			}
+		}
 	}
-
-	// Both sides are ambiguous
-	return AnyType, nil
+	return tx, nil
 }
 
-func copyParams(params map[string]SQLValueType) map[string]SQLValueType {
-	ret := make(map[string]SQLValueType, len(params))
-	for k, v := range params {
-		ret[k] = v
+func (stmt *SelectStmt) targetSelectors() []Selector {
+	if stmt.selectors == nil {
+		stmt.selectors = stmt.extractSelectors()
 	}
-	return ret
+	return stmt.selectors
 }
 
-func restoreParams(params, restore map[string]SQLValueType) {
-	for k := range params {
-		delete(params, k)
-	}
-	for k, v := range restore {
-		params[k] = v
+func (stmt *SelectStmt) selectorAppearsInTargets(s Selector) bool {
+	encSel := EncodeSelector(s.resolve(stmt.Alias()))
+
+	for _, sel := range stmt.targetSelectors() {
+		if EncodeSelector(sel.resolve(stmt.Alias())) == encSel {
+			return true
+		}
 	}
+	return false
 }
 
-func (bexp *NumExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != IntegerType && t != Float64Type {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
-	}
+func (stmt *SelectStmt) groupByContains(sel Selector) bool {
+	encSel := EncodeSelector(sel.resolve(stmt.Alias()))
 
-	floatArgs := 2
-	paramsOrig := copyParams(params)
-	err := bexp.left.requiresType(t, cols, params, implicitTable)
-	if err != nil && t == Float64Type {
-		restoreParams(params, paramsOrig)
-		floatArgs--
-		err = bexp.left.requiresType(IntegerType, cols, params, implicitTable)
-	}
-	if err != nil {
-		return err
+	for _, colSel := range stmt.groupBy {
+		if EncodeSelector(colSel.resolve(stmt.Alias())) == encSel {
+			return true
+		}
 	}
+	return false
+}
 
-	paramsOrig = copyParams(params)
-	err = bexp.right.requiresType(t, cols, params, implicitTable)
-	if err != nil && t == Float64Type {
-		restoreParams(params, paramsOrig)
-		floatArgs--
-		err = bexp.right.requiresType(IntegerType, cols, params, implicitTable)
-	}
-	if err != nil {
-		return err
-	}
+func (stmt *SelectStmt) extractGroupByCols() []*AggColSelector {
+	cols := make([]*AggColSelector, 0, len(stmt.targets))
 
-	if t == Float64Type && floatArgs == 0 {
-		// Currently this case requires explicit float cast
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	for _, t := range stmt.targets {
+		selectors := t.Exp.selectors()
+		for _, sel := range selectors {
+			aggSel, isAgg := sel.(*AggColSelector)
+			if isAgg {
+				cols = append(cols, aggSel)
+			}
+		}
 	}
-
-	return nil
+	return cols
 }
 
-func (bexp *NumExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	rlexp, err := bexp.left.substitute(params)
-	if err != nil {
-		return nil, err
-	}
-
-	rrexp, err := bexp.right.substitute(params)
-	if err != nil {
-		return nil, err
+func (stmt *SelectStmt) extractSelectors() []Selector {
+	selectors := make([]Selector, 0, len(stmt.targets))
+	for _, t := range stmt.targets {
+		selectors = append(selectors, t.Exp.selectors()...)
 	}
-
-	bexp.left = rlexp
-	bexp.right = rrexp
-
-	return bexp, nil
+	return selectors
 }
 
-func (bexp *NumExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	vl, err := bexp.left.reduce(tx, row, implicitTable)
+func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (ret RowReader, err error) {
+	scanSpecs, err := stmt.genScanSpecs(tx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	rowReader, err := stmt.ds.Resolve(ctx, tx, params, scanSpecs)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			rowReader.Close()
+		}
+	}()
 
-	vl = unwrapJSON(vl)
-	vr = unwrapJSON(vr)
-
-	return applyNumOperator(bexp.op, vl, vr)
-}
-
-func unwrapJSON(v TypedValue) TypedValue {
-	if jsonVal, ok := v.(*JSON); ok {
-		if sv, isSimple := jsonVal.castToTypedValue(); isSimple {
-			return sv
+	if stmt.joins != nil {
+		var joinsRowReader RowReader
+		joinsRowReader, err = newJoinsRowReader(rowReader, stmt.joins)
+		if err != nil {
+			return nil, err
 		}
+		rowReader = joinsRowReader
 	}
-	return v
-}
-
-func (bexp *NumExp) selectors() []Selector {
-	return append(bexp.left.selectors(), bexp.right.selectors()...)
-}
 
-func (bexp *NumExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return &NumExp{
-		op:    bexp.op,
-		left:  bexp.left.reduceSelectors(row, implicitTable),
-		right: bexp.right.reduceSelectors(row, implicitTable),
+	if stmt.where != nil {
+		rowReader = newConditionalRowReader(rowReader, stmt.where)
 	}
-}
 
-func (bexp *NumExp) isConstant() bool {
-	return bexp.left.isConstant() && bexp.right.isConstant()
-}
-
-func (bexp *NumExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
-
-func (bexp *NumExp) String() string {
-	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), NumOperatorString(bexp.op), bexp.right.String())
-}
+	if stmt.containsAggregations() || len(stmt.groupBy) > 0 {
+		if len(scanSpecs.groupBySortExps) > 0 {
+			var sortRowReader *sortRowReader
+			sortRowReader, err = newSortRowReader(rowReader, scanSpecs.groupBySortExps)
+			if err != nil {
+				return nil, err
+			}
+			rowReader = sortRowReader
+		}
 
-type NotBoolExp struct {
-	exp ValueExp
-}
+		var groupedRowReader *groupedRowReader
+		groupedRowReader, err = newGroupedRowReader(rowReader, allAggregations(stmt.targets), stmt.extractGroupByCols(), stmt.groupBy)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = groupedRowReader
 
-func (bexp *NotBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	err := bexp.exp.requiresType(BooleanType, cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
+		if stmt.having != nil {
+			rowReader = newConditionalRowReader(rowReader, stmt.having)
+		}
 	}
 
-	return BooleanType, nil
-}
-
-func (bexp *NotBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != BooleanType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	windowFns := stmt.windowFnExps()
+	if len(windowFns) > 0 {
+		var windowRowReader RowReader
+		windowRowReader, err = newWindowRowReaders(rowReader, windowFns)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = windowRowReader
 	}
 
-	return bexp.exp.requiresType(BooleanType, cols, params, implicitTable)
-}
+	// rows are sorted again when evaluating window functions, hence index ordering can not be relied on
+	if len(scanSpecs.orderBySortExps) > 0 || (len(windowFns) > 0 && len(stmt.orderBy) > 0) {
+		var sortRowReader *sortRowReader
+		sortRowReader, err = newSortRowReader(rowReader, stmt.orderBy)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = sortRowReader
+	}
 
-func (bexp *NotBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	rexp, err := bexp.exp.substitute(params)
+	projectedRowReader, err := newProjectedRowReader(ctx, rowReader, stmt.as, stmt.targets)
 	if err != nil {
 		return nil, err
 	}
+	rowReader = projectedRowReader
 
-	bexp.exp = rexp
+	if stmt.distinct {
+		var distinctRowReader *distinctRowReader
+		distinctRowReader, err = newDistinctRowReader(ctx, rowReader)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = distinctRowReader
+	}
 
-	return bexp, nil
-}
+	if stmt.offset != nil {
+		var offset int
+		offset, err = evalExpAsInt(tx, stmt.offset, params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid offset", err)
+		}
 
-func (bexp *NotBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	v, err := bexp.exp.reduce(tx, row, implicitTable)
-	if err != nil {
-		return nil, err
+		rowReader = newOffsetRowReader(rowReader, offset)
 	}
 
-	r, isBool := v.RawValue().(bool)
-	if !isBool {
-		return nil, ErrInvalidCondition
-	}
+	if stmt.limit != nil {
+		var limit int
+		limit, err = evalExpAsInt(tx, stmt.limit, params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid limit", err)
+		}
 
-	return &Bool{val: !r}, nil
-}
+		if limit < 0 {
+			return nil, fmt.Errorf("%w: invalid limit", ErrIllegalArguments)
+		}
 
-func (bexp *NotBoolExp) selectors() []Selector {
-	return bexp.exp.selectors()
+		if limit > 0 {
+			rowReader = newLimitRowReader(rowReader, limit)
+		}
+	}
+	return rowReader, nil
 }
 
-func (bexp *NotBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return &NotBoolExp{
-		exp: bexp.exp.reduceSelectors(row, implicitTable),
-	}
-}
+func (stmt *SelectStmt) rearrangeOrdExps(groupByCols, orderByExps []*OrdExp) ([]*OrdExp, []*OrdExp) {
+	if len(groupByCols) > 0 && len(orderByExps) > 0 && !ordExpsHaveAggregations(orderByExps) {
+		if ordExpsHasPrefix(orderByExps, groupByCols, stmt.Alias()) {
+			return orderByExps, nil
+		}
 
-func (bexp *NotBoolExp) isConstant() bool {
-	return bexp.exp.isConstant()
+		if ordExpsHasPrefix(groupByCols, orderByExps, stmt.Alias()) {
+			for i := range orderByExps {
+				groupByCols[i].descOrder = orderByExps[i].descOrder
+			}
+			return groupByCols, nil
+		}
+	}
+	return groupByCols, orderByExps
 }
 
-func (bexp *NotBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+func ordExpsHasPrefix(cols, prefix []*OrdExp, table string) bool {
+	if len(prefix) > len(cols) {
+		return false
+	}
 
-func (bexp *NotBoolExp) String() string {
-	return fmt.Sprintf("(NOT %s)", bexp.exp.String())
-}
+	for i := range prefix {
+		ls := prefix[i].AsSelector()
+		rs := cols[i].AsSelector()
 
-type LikeBoolExp struct {
-	val     ValueExp
-	notLike bool
-	pattern ValueExp
-}
+		if ls == nil || rs == nil {
+			return false
+		}
 
-func NewLikeBoolExp(val ValueExp, notLike bool, pattern ValueExp) *LikeBoolExp {
-	return &LikeBoolExp{
-		val:     val,
-		notLike: notLike,
-		pattern: pattern,
+		if EncodeSelector(ls.resolve(table)) != EncodeSelector(rs.resolve(table)) {
+			return false
+		}
 	}
+	return true
 }
 
-func (bexp *LikeBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	if bexp.val == nil || bexp.pattern == nil {
-		return AnyType, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
-	}
+func (stmt *SelectStmt) groupByOrdExps() []*OrdExp {
+	groupByCols := stmt.groupBy
 
-	err := bexp.pattern.requiresType(VarcharType, cols, params, implicitTable)
-	if err != nil {
-		return AnyType, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	ordExps := make([]*OrdExp, 0, len(groupByCols))
+	for _, col := range groupByCols {
+		ordExps = append(ordExps, &OrdExp{exp: col})
 	}
-
-	return BooleanType, nil
+	return ordExps
 }
 
-func (bexp *LikeBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if bexp.val == nil || bexp.pattern == nil {
-		return fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
+func ordExpsHaveAggregations(exps []*OrdExp) bool {
+	for _, e := range exps {
+		if _, isAgg := e.exp.(*AggColSelector); isAgg {
+			return true
+		}
 	}
+	return false
+}
 
-	if t != BooleanType {
-		return fmt.Errorf("error using the value of the LIKE operator as %s: %w", t, ErrInvalidTypes)
+func (stmt *SelectStmt) containsAggregations() bool {
+	for _, sel := range stmt.targetSelectors() {
+		_, isAgg := sel.(*AggColSelector)
+		if isAgg {
+			return true
+		}
 	}
+	return false
+}
 
-	err := bexp.pattern.requiresType(VarcharType, cols, params, implicitTable)
-	if err != nil {
-		return fmt.Errorf("error in 'LIKE' clause: %w", err)
-	}
+// windowFnExps returns the window functions referenced either by the select list or the ORDER BY clause.
+func (stmt *SelectStmt) windowFnExps() []*WindowFnExp {
+	var windowFns []*WindowFnExp
 
-	return nil
-}
+	names := make(map[string]struct{})
 
-func (bexp *LikeBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	if bexp.val == nil || bexp.pattern == nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
-	}
+	collect := func(e ValueExp) bool {
+		w, ok := e.(*WindowFnExp)
+		if !ok {
+			return true
+		}
 
-	val, err := bexp.val.substitute(params)
-	if err != nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+		if _, exists := names[w.colName()]; !exists {
+			names[w.colName()] = struct{}{}
+			windowFns = append(windowFns, w)
+		}
+		return true
 	}
 
-	pattern, err := bexp.pattern.substitute(params)
-	if err != nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	for _, t := range stmt.targets {
+		visitExp(t.Exp, collect)
 	}
 
-	return &LikeBoolExp{
-		val:     val,
-		notLike: bexp.notLike,
-		pattern: pattern,
-	}, nil
-}
-
-func (bexp *LikeBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	if bexp.val == nil || bexp.pattern == nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
+	for _, oe := range stmt.orderBy {
+		visitExp(oe.exp, collect)
 	}
+	return windowFns
+}
 
-	rval, err := bexp.val.reduce(tx, row, implicitTable)
+func evalExpAsInt(tx *SQLTx, exp ValueExp, params map[string]interface{}) (int, error) {
+	offset, err := exp.substitute(params)
 	if err != nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
-	}
-
-	if rval.IsNull() {
-		return &Bool{val: bexp.notLike}, nil
+		return 0, err
 	}
 
-	rvalStr, ok := rval.RawValue().(string)
-	if !ok {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w (expecting %s)", ErrInvalidTypes, VarcharType)
+	texp, err := offset.reduce(tx, nil, "")
+	if err != nil {
+		return 0, err
 	}
 
-	rpattern, err := bexp.pattern.reduce(tx, row, implicitTable)
+	convVal, err := mayApplyImplicitConversion(texp.RawValue(), IntegerType)
 	if err != nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+		return 0, ErrInvalidValue
 	}
 
-	if rpattern.Type() != VarcharType {
-		return nil, fmt.Errorf("error evaluating 'LIKE' clause: %w", ErrInvalidTypes)
+	num, ok := convVal.(int64)
+	if !ok {
+		return 0, ErrInvalidValue
 	}
 
-	matched, err := regexp.MatchString(rpattern.RawValue().(string), rvalStr)
-	if err != nil {
-		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	if num > math.MaxInt32 {
+		return 0, ErrInvalidValue
 	}
 
-	return &Bool{val: matched != bexp.notLike}, nil
+	return int(num), nil
 }
 
-func (bexp *LikeBoolExp) selectors() []Selector {
-	return bexp.val.selectors()
-}
+func (stmt *SelectStmt) Alias() string {
+	if stmt.as == "" {
+		return stmt.ds.Alias()
+	}
 
-func (bexp *LikeBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return bexp
+	return stmt.as
 }
 
-func (bexp *LikeBoolExp) isConstant() bool {
-	return false
-}
+// correlate returns a copy of the statement in which references to columns of an enclosing query,
+// available in row, are replaced by their values. Columns of the tables referenced by the statement
+// itself take precedence over the ones of the enclosing query.
+func (stmt *SelectStmt) correlate(row *Row) *SelectStmt {
+	outerRow := stmt.outerRow(row)
+	if len(outerRow.ValuesBySelector) == 0 {
+		return stmt
+	}
 
-func (bexp *LikeBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
+	correlatedStmt := *stmt
+	correlatedStmt.ds = correlateDataSource(stmt.ds, outerRow)
 
-func (bexp *LikeBoolExp) String() string {
-	fmtStr := "(%s LIKE %s)"
-	if bexp.notLike {
-		fmtStr = "(%s NOT LIKE %s)"
+	if stmt.where != nil {
+		correlatedStmt.where = stmt.where.reduceSelectors(outerRow, stmt.ds.Alias())
 	}
-	return fmt.Sprintf(fmtStr, bexp.val.String(), bexp.pattern.String())
-}
 
-type CmpBoolExp struct {
-	op          CmpOperator
-	left, right ValueExp
-}
+	if len(stmt.joins) > 0 {
+		correlatedStmt.joins = make([]*JoinSpec, len(stmt.joins))
 
-func NewCmpBoolExp(op CmpOperator, left, right ValueExp) *CmpBoolExp {
-	return &CmpBoolExp{
-		op:    op,
-		left:  left,
-		right: right,
+		for i, jspec := range stmt.joins {
+			correlatedStmt.joins[i] = &JoinSpec{
+				joinType: jspec.joinType,
+				ds:       jspec.ds,
+				cond:     jspec.cond.reduceSelectors(outerRow, stmt.ds.Alias()),
+				indexOn:  jspec.indexOn,
+			}
+		}
 	}
-}
 
-func (bexp *CmpBoolExp) Left() ValueExp {
-	return bexp.left
-}
+	if len(stmt.targets) > 0 {
+		correlatedStmt.targets = make([]TargetEntry, len(stmt.targets))
+		correlatedStmt.selectors = nil
 
-func (bexp *CmpBoolExp) Right() ValueExp {
-	return bexp.right
-}
+		for i, t := range stmt.targets {
+			exp := t.Exp.reduceSelectors(outerRow, stmt.ds.Alias())
 
-func (bexp *CmpBoolExp) OP() CmpOperator {
-	return bexp.op
-}
+			// the name of the column is kept when an outer column is projected
+			as := t.As
+			if sel, ok := t.Exp.(*ColSelector); ok && as == "" && exp != t.Exp {
+				as = sel.col
+			}
 
-func (bexp *CmpBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	tleft, err := bexp.left.inferType(cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
+			correlatedStmt.targets[i] = TargetEntry{Exp: exp, As: as}
+		}
 	}
 
-	tright, err := bexp.right.inferType(cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
+	if stmt.having != nil {
+		correlatedStmt.having = stmt.having.reduceSelectors(outerRow, stmt.ds.Alias())
 	}
 
-	// unification step
+	if len(stmt.orderBy) > 0 {
+		correlatedStmt.orderBy = make([]*OrdExp, len(stmt.orderBy))
 
-	if tleft == tright {
-		return BooleanType, nil
+		for i, oe := range stmt.orderBy {
+			correlatedStmt.orderBy[i] = &OrdExp{
+				exp:       oe.exp.reduceSelectors(outerRow, stmt.ds.Alias()),
+				descOrder: oe.descOrder,
+			}
+		}
 	}
+	return &correlatedStmt
+}
 
-	_, ok := coerceTypes(tleft, tright)
-	if !ok {
-		return AnyType, fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, tleft, tright)
+// outerRow returns the values of the row which are not shadowed by the data sources of the statement
+func (stmt *SelectStmt) outerRow(row *Row) *Row {
+	if row == nil || len(row.ValuesBySelector) == 0 {
+		return &Row{}
 	}
 
-	if tleft == AnyType {
-		err = bexp.left.requiresType(tright, cols, params, implicitTable)
-		if err != nil {
-			return AnyType, err
-		}
+	aliases := make([]string, 0, 1+len(stmt.joins))
+	aliases = append(aliases, stmt.ds.Alias())
+	for _, jspec := range stmt.joins {
+		aliases = append(aliases, jspec.ds.Alias())
 	}
 
-	if tright == AnyType {
-		err = bexp.right.requiresType(tleft, cols, params, implicitTable)
-		if err != nil {
-			return AnyType, err
-		}
+	outerRow := &Row{
+		ValuesBySelector: make(map[string]TypedValue, len(row.ValuesBySelector)),
 	}
-	return BooleanType, nil
-}
 
-func coerceTypes(t1, t2 SQLValueType) (SQLValueType, bool) {
-	switch {
-	case t1 == t2:
-		return t1, true
-	case t1 == AnyType:
-		return t2, true
-	case t2 == AnyType:
-		return t1, true
-	case (t1 == IntegerType && t2 == Float64Type) ||
-		(t1 == Float64Type && t2 == IntegerType):
-		return Float64Type, true
+	for sel, v := range row.ValuesBySelector {
+		shadowed := false
+		for _, alias := range aliases {
+			if strings.Contains(sel, "("+alias+".") {
+				shadowed = true
+				break
+			}
+		}
+
+		if !shadowed {
+			outerRow.ValuesBySelector[sel] = v
+		}
 	}
-	return "", false
+	return outerRow
 }
 
-func (bexp *CmpBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != BooleanType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+// isCorrelated returns true if any expression of the statement references a value of the row
+func (stmt *SelectStmt) isCorrelated(row *Row) bool {
+	outerRow := stmt.outerRow(row)
+	if len(outerRow.ValuesBySelector) == 0 {
+		return false
 	}
 
-	_, err := bexp.inferType(cols, params, implicitTable)
-	return err
-}
-
-func (bexp *CmpBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	rlexp, err := bexp.left.substitute(params)
-	if err != nil {
-		return nil, err
+	if isCorrelatedDataSource(stmt.ds, outerRow) {
+		return true
 	}
 
-	rrexp, err := bexp.right.substitute(params)
-	if err != nil {
-		return nil, err
+	exps := []ValueExp{stmt.where, stmt.having}
+	for _, jspec := range stmt.joins {
+		exps = append(exps, jspec.cond)
+	}
+	for _, t := range stmt.targets {
+		exps = append(exps, t.Exp)
+	}
+	for _, oe := range stmt.orderBy {
+		exps = append(exps, oe.exp)
 	}
 
-	bexp.left = rlexp
-	bexp.right = rrexp
+	found := false
 
-	return bexp, nil
-}
+	for _, exp := range exps {
+		visitExp(exp, func(e ValueExp) bool {
+			switch e := e.(type) {
+			case Selector:
+				{
+					aggFn, table, col := e.resolve(stmt.ds.Alias())
+					_, found = outerRow.ValuesBySelector[EncodeSelector(aggFn, table, col)]
+				}
+			case *ExistsBoolExp:
+				found = isCorrelatedDataSource(e.q, outerRow)
+			case *InSubQueryExp:
+				found = isCorrelatedDataSource(e.q, outerRow)
+			}
+			return !found
+		})
 
-func (bexp *CmpBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	vl, err := bexp.left.reduce(tx, row, implicitTable)
-	if err != nil {
-		return nil, err
-	}
-
-	vr, err := bexp.right.reduce(tx, row, implicitTable)
-	if err != nil {
-		return nil, err
+		if found {
+			return true
+		}
 	}
+	return false
+}
 
-	r, err := vl.Compare(vr)
-	if err != nil {
-		return nil, err
+func (stmt *SelectStmt) hasTxMetadata() bool {
+	for _, sel := range stmt.targetSelectors() {
+		switch s := sel.(type) {
+		case *ColSelector:
+			if s.col == txMetadataCol {
+				return true
+			}
+		case *JSONSelector:
+			if s.ColSelector.col == txMetadataCol {
+				return true
+			}
+		}
 	}
-
-	return &Bool{val: cmpSatisfiesOp(r, bexp.op)}, nil
+	return false
 }
 
-func (bexp *CmpBoolExp) selectors() []Selector {
-	return append(bexp.left.selectors(), bexp.right.selectors()...)
-}
+func (stmt *SelectStmt) genScanSpecs(tx *SQLTx, params map[string]interface{}) (*ScanSpecs, error) {
+	groupByCols, orderByCols := stmt.groupByOrdExps(), stmt.orderBy
 
-func (bexp *CmpBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return &CmpBoolExp{
-		op:    bexp.op,
-		left:  bexp.left.reduceSelectors(row, implicitTable),
-		right: bexp.right.reduceSelectors(row, implicitTable),
-	}
-}
+	tableRef, isTableRef := stmt.ds.(*tableRef)
+	if !isTableRef {
+		groupByCols, orderByCols = stmt.rearrangeOrdExps(groupByCols, orderByCols)
 
-func (bexp *CmpBoolExp) isConstant() bool {
-	return bexp.left.isConstant() && bexp.right.isConstant()
-}
+		return &ScanSpecs{
+			groupBySortExps: groupByCols,
+			orderBySortExps: orderByCols,
+		}, nil
+	}
 
-func (bexp *CmpBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	matchingFunc := func(_, right ValueExp) (*ColSelector, ValueExp, bool) {
-		s, isSel := bexp.left.(*ColSelector)
-		if isSel && s.col != revCol && bexp.right.isConstant() {
-			return s, right, true
+	table, err := tableRef.referencedTable(tx)
+	if err != nil {
+		if tx.engine.tableResolveFor(tableRef.table) != nil || tx.catalog.ExistView(tableRef.table) {
+			return &ScanSpecs{
+				groupBySortExps: groupByCols,
+				orderBySortExps: orderByCols,
+			}, nil
 		}
-		return nil, nil, false
+		return nil, err
 	}
 
-	sel, c, ok := matchingFunc(bexp.left, bexp.right)
-	if !ok {
-		sel, c, ok = matchingFunc(bexp.right, bexp.left)
+	rangesByColID := make(map[uint32]*typedValueRange)
+	if stmt.where != nil {
+		err = stmt.where.selectorRanges(tx, table, tableRef.Alias(), params, rangesByColID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if !ok {
-		return nil
+	preferredIndex, err := stmt.getPreferredIndex(table)
+	if err != nil {
+		return nil, err
 	}
 
-	aggFn, t, col := sel.resolve(table.name)
-	if aggFn != "" || t != asTable {
-		return nil
+	var sortingIndex *Index
+	if preferredIndex == nil {
+		sortingIndex = stmt.selectSortingIndex(groupByCols, orderByCols, table, rangesByColID)
+	} else {
+		sortingIndex = preferredIndex
 	}
 
-	column, err := table.GetColumnByName(col)
-	if err != nil {
-		return err
+	if sortingIndex == nil {
+		sortingIndex = table.primaryIndex
 	}
 
-	val, err := c.substitute(params)
-	if errors.Is(err, ErrMissingParameter) {
-		// TODO: not supported when parameters are not provided during query resolution
-		return nil
+	if tableRef.history && !sortingIndex.IsPrimary() {
+		return nil, fmt.Errorf("%w: historical queries are supported over primary index", ErrIllegalArguments)
 	}
-	if err != nil {
-		return err
+
+	var descOrder bool
+	if len(groupByCols) > 0 && sortingIndex.coversOrdCols(groupByCols, rangesByColID) {
+		groupByCols = nil
 	}
 
-	rval, err := val.reduce(nil, nil, table.name)
-	if err != nil {
-		return err
+	if len(groupByCols) == 0 && len(orderByCols) > 0 && sortingIndex.coversOrdCols(orderByCols, rangesByColID) {
+		descOrder = orderByCols[0].descOrder
+		orderByCols = nil
 	}
 
-	return updateRangeFor(column.id, rval, bexp.op, rangesByColID)
-}
+	groupByCols, orderByCols = stmt.rearrangeOrdExps(groupByCols, orderByCols)
 
-func (bexp *CmpBoolExp) String() string {
-	opStr := CmpOperatorToString(bexp.op)
-	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), opStr, bexp.right.String())
+	return &ScanSpecs{
+		Index:             sortingIndex,
+		rangesByColID:     rangesByColID,
+		IncludeHistory:    tableRef.history,
+		IncludeTxMetadata: stmt.hasTxMetadata(),
+		DescOrder:         descOrder,
+		groupBySortExps:   groupByCols,
+		orderBySortExps:   orderByCols,
+	}, nil
 }
 
-func updateRangeFor(colID uint32, val TypedValue, cmp CmpOperator, rangesByColID map[uint32]*typedValueRange) error {
-	currRange, ranged := rangesByColID[colID]
-	var newRange *typedValueRange
-
-	switch cmp {
-	case EQ:
-		{
-			newRange = &typedValueRange{
-				lRange: &typedValueSemiRange{
-					val:       val,
-					inclusive: true,
-				},
-				hRange: &typedValueSemiRange{
-					val:       val,
-					inclusive: true,
-				},
-			}
-		}
-	case LT:
-		{
-			newRange = &typedValueRange{
-				hRange: &typedValueSemiRange{
-					val: val,
-				},
-			}
-		}
-	case LE:
-		{
-			newRange = &typedValueRange{
-				hRange: &typedValueSemiRange{
-					val:       val,
-					inclusive: true,
-				},
-			}
-		}
-	case GT:
-		{
-			newRange = &typedValueRange{
-				lRange: &typedValueSemiRange{
-					val: val,
-				},
-			}
-		}
-	case GE:
-		{
-			newRange = &typedValueRange{
-				lRange: &typedValueSemiRange{
-					val:       val,
-					inclusive: true,
-				},
-			}
-		}
-	case NE:
-		{
-			return nil
-		}
+func (stmt *SelectStmt) selectSortingIndex(groupByCols, orderByCols []*OrdExp, table *Table, rangesByColId map[uint32]*typedValueRange) *Index {
+	sortCols := groupByCols
+	if len(sortCols) == 0 {
+		sortCols = orderByCols
 	}
 
-	if !ranged {
-		rangesByColID[colID] = newRange
+	if len(sortCols) == 0 {
 		return nil
 	}
 
-	return currRange.refineWith(newRange)
-}
-
-func cmpSatisfiesOp(cmp int, op CmpOperator) bool {
-	switch {
-	case cmp == 0:
-		{
-			return op == EQ || op == LE || op == GE
-		}
-	case cmp < 0:
-		{
-			return op == NE || op == LT || op == LE
-		}
-	case cmp > 0:
-		{
-			return op == NE || op == GT || op == GE
+	for _, idx := range table.indexes {
+		if idx.coversOrdCols(sortCols, rangesByColId) {
+			return idx
 		}
 	}
-	return false
-}
-
-type BinBoolExp struct {
-	op          LogicOperator
-	left, right ValueExp
+	return nil
 }
 
-func NewBinBoolExp(op LogicOperator, lrexp, rrexp ValueExp) *BinBoolExp {
-	bexp := &BinBoolExp{
-		op: op,
+func (stmt *SelectStmt) getPreferredIndex(table *Table) (*Index, error) {
+	if len(stmt.indexOn) == 0 {
+		return nil, nil
 	}
 
-	bexp.left = lrexp
-	bexp.right = rrexp
+	cols := make([]*Column, len(stmt.indexOn))
+	for i, spec := range stmt.indexOn {
+		if len(spec.jsonFields) > 0 {
+			// JSON paths are only held by virtual columns while indexed
+			vcol, indexed := table.getJSONPathColumn(spec.col, spec.jsonFields)
+			if !indexed {
+				return nil, ErrIndexNotFound
+			}
 
-	return bexp
-}
+			cols[i] = vcol
+			continue
+		}
 
-func (bexp *BinBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	err := bexp.left.requiresType(BooleanType, cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
-	}
+		col, err := table.GetColumnByName(spec.col)
+		if err != nil {
+			return nil, err
+		}
 
-	err = bexp.right.requiresType(BooleanType, cols, params, implicitTable)
-	if err != nil {
-		return AnyType, err
+		cols[i] = col
 	}
+	return table.GetIndexByName(indexName(table.name, cols))
+}
 
-	return BooleanType, nil
+type UnionStmt struct {
+	distinct    bool
+	left, right DataSource
 }
 
-func (bexp *BinBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if t != BooleanType {
-		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
-	}
+func (stmt *UnionStmt) readOnly() bool {
+	return isReadOnlyDataSource(stmt.left) && isReadOnlyDataSource(stmt.right)
+}
+
+func (stmt *UnionStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeSelect}
+}
 
-	err := bexp.left.requiresType(BooleanType, cols, params, implicitTable)
+func (stmt *UnionStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	err := stmt.left.inferParameters(ctx, tx, params)
 	if err != nil {
 		return err
 	}
+	return stmt.right.inferParameters(ctx, tx, params)
+}
 
-	err = bexp.right.requiresType(BooleanType, cols, params, implicitTable)
+func (stmt *UnionStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	_, err := stmt.left.execAt(ctx, tx, params)
 	if err != nil {
-		return err
+		return tx, err
 	}
 
-	return nil
+	return stmt.right.execAt(ctx, tx, params)
 }
 
-func (bexp *BinBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+func (stmt *UnionStmt) resolveUnionAll(ctx context.Context, tx *SQLTx, params map[string]interface{}) (ret RowReader, err error) {
+	leftRowReader, err := stmt.left.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			leftRowReader.Close()
+		}
+	}()
+
+	rightRowReader, err := stmt.right.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			rightRowReader.Close()
+		}
+	}()
+
+	rowReader, err := newUnionRowReader(ctx, []RowReader{leftRowReader, rightRowReader})
+	if err != nil {
+		return nil, err
+	}
+
+	return rowReader, nil
+}
+
+func (stmt *UnionStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (ret RowReader, err error) {
+	rowReader, err := stmt.resolveUnionAll(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			rowReader.Close()
+		}
+	}()
+
+	if stmt.distinct {
+		distinctReader, err := newDistinctRowReader(ctx, rowReader)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = distinctReader
+	}
+
+	return rowReader, nil
+}
+
+func (stmt *UnionStmt) Alias() string {
+	return ""
+}
+
+// SetOpStmt combines the rows of two queries by means of INTERSECT and EXCEPT operators
+type SetOpStmt struct {
+	op          SetOperator
+	distinct    bool
+	left, right DataSource
+}
+
+func (stmt *SetOpStmt) readOnly() bool {
+	return isReadOnlyDataSource(stmt.left) && isReadOnlyDataSource(stmt.right)
+}
+
+func (stmt *SetOpStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeSelect}
+}
+
+func (stmt *SetOpStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	err := stmt.left.inferParameters(ctx, tx, params)
+	if err != nil {
+		return err
+	}
+	return stmt.right.inferParameters(ctx, tx, params)
+}
+
+func (stmt *SetOpStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	_, err := stmt.left.execAt(ctx, tx, params)
+	if err != nil {
+		return tx, err
+	}
+
+	return stmt.right.execAt(ctx, tx, params)
+}
+
+func (stmt *SetOpStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (ret RowReader, err error) {
+	leftRowReader, err := stmt.left.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			leftRowReader.Close()
+		}
+	}()
+
+	if stmt.distinct {
+		// duplicates are removed from the left side, so that each distinct row
+		// is matched at most once against the rows of the right side
+		distinctReader, err := newDistinctRowReader(ctx, leftRowReader)
+		if err != nil {
+			return nil, err
+		}
+		leftRowReader = distinctReader
+	}
+
+	rightRowReader, err := stmt.right.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			rightRowReader.Close()
+		}
+	}()
+
+	return newSetOpRowReader(ctx, stmt.op, leftRowReader, rightRowReader)
+}
+
+func (stmt *SetOpStmt) Alias() string {
+	return ""
+}
+
+// CTE is a common table expression, a named query which can be referenced as a data source
+// by the statement it is attached to
+type CTE struct {
+	name string
+	cols []string
+	q    DataSource
+
+	// set when the query references the common table expression itself
+	recursive bool
+}
+
+// cteWorkingTable holds the resolved columns and rows of a recursive common table expression,
+// it's kept by the transaction evaluating the statement so that a parsed statement can be re-executed
+type cteWorkingTable struct {
+	colsByPos []ColDescriptor
+	rows      [][]ValueExp
+}
+
+func NewCTE(name string, cols []string, q DataSource) *CTE {
+	return &CTE{
+		name: name,
+		cols: cols,
+		q:    q,
+	}
+}
+
+type WithStmt struct {
+	recursive bool
+	ctes      []*CTE
+	q         DataSource
+}
+
+func NewWithStmt(ctes []*CTE, recursive bool, q DataSource) (*WithStmt, error) {
+	names := make(map[string]struct{}, len(ctes))
+
+	for _, cte := range ctes {
+		if _, ok := names[cte.name]; ok {
+			return nil, fmt.Errorf("%w: query name '%s' specified more than once", ErrIllegalArguments, cte.name)
+		}
+		names[cte.name] = struct{}{}
+	}
+
+	stmt := &WithStmt{
+		recursive: recursive,
+		ctes:      ctes,
+		q:         q,
+	}
+
+	stmt.bind(make(map[string]*CTE))
+
+	return stmt, nil
+}
+
+// bind replaces the references to common table expressions found in the statement,
+// a common table expression is visible in the ones declared after it, in itself when
+// the statement is recursive, and in the main query.
+func (stmt *WithStmt) bind(scope map[string]*CTE) {
+	ctes := make(map[string]*CTE, len(scope)+len(stmt.ctes))
+	for name, cte := range scope {
+		ctes[name] = cte
+	}
+
+	for _, cte := range stmt.ctes {
+		if stmt.recursive {
+			ctes[cte.name] = cte
+		}
+
+		cte.q = bindCTERefs(cte.q, ctes)
+		cte.recursive = stmt.recursive && referencesCTE(cte.q, cte)
+
+		ctes[cte.name] = cte
+	}
+
+	stmt.q = bindCTERefs(stmt.q, ctes)
+}
+
+func bindCTERefs(ds DataSource, ctes map[string]*CTE) DataSource {
+	bindExp := func(exp ValueExp) {
+		visitExp(exp, func(e ValueExp) bool {
+			switch se := e.(type) {
+			case *ExistsBoolExp:
+				se.q = bindCTERefs(se.q, ctes)
+			case *InSubQueryExp:
+				se.q = bindCTERefs(se.q, ctes)
+			}
+			return true
+		})
+	}
+
+	switch s := ds.(type) {
+	case *tableRef:
+		{
+			cte, ok := ctes[s.table]
+			if !ok || s.history {
+				return ds
+			}
+			return &cteRef{cte: cte, as: s.as}
+		}
+	case *SelectStmt:
+		{
+			s.ds = bindCTERefs(s.ds, ctes)
+
+			for _, jspec := range s.joins {
+				jspec.ds = bindCTERefs(jspec.ds, ctes)
+				bindExp(jspec.cond)
+			}
+
+			for _, t := range s.targets {
+				bindExp(t.Exp)
+			}
+
+			for _, oe := range s.orderBy {
+				bindExp(oe.exp)
+			}
+
+			bindExp(s.where)
+			bindExp(s.having)
+		}
+	case *UnionStmt:
+		{
+			s.left = bindCTERefs(s.left, ctes)
+			s.right = bindCTERefs(s.right, ctes)
+		}
+	case *SetOpStmt:
+		{
+			s.left = bindCTERefs(s.left, ctes)
+			s.right = bindCTERefs(s.right, ctes)
+		}
+	case *WithStmt:
+		{
+			s.bind(ctes)
+		}
+	}
+	return ds
+}
+
+func (stmt *WithStmt) readOnly() bool {
+	for _, cte := range stmt.ctes {
+		if !isReadOnlyDataSource(cte.q) {
+			return false
+		}
+	}
+	return isReadOnlyDataSource(stmt.q)
+}
+
+func (stmt *WithStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeSelect}
+}
+
+func (stmt *WithStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	for _, cte := range stmt.ctes {
+		if cte.recursive {
+			// the recursive term is evaluated over an empty working table
+			_, err := cte.resolveAnchor(ctx, tx, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := cte.q.inferParameters(ctx, tx, params)
+		if err != nil {
+			return err
+		}
+	}
+	return stmt.q.inferParameters(ctx, tx, params)
+}
+
+func (stmt *WithStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	for _, cte := range stmt.ctes {
+		_, err := cte.q.execAt(ctx, tx, params)
+		if err != nil {
+			return tx, err
+		}
+	}
+	return stmt.q.execAt(ctx, tx, params)
+}
+
+func (stmt *WithStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error) {
+	for _, cte := range stmt.ctes {
+		if cte.recursive {
+			err := cte.materialize(ctx, tx, params, stmt.requiredRows(tx, params, cte))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stmt.q.Resolve(ctx, tx, params, scanSpecs)
+}
+
+// requiredRows returns the number of rows of a recursive common table expression the query reads at most,
+// which is only known when the query is a plain scan of it with a LIMIT clause, e.g. SELECT n FROM r LIMIT 3.
+// Zero is returned when every row may be read.
+func (stmt *WithStmt) requiredRows(tx *SQLTx, params map[string]interface{}, cte *CTE) int {
+	sel, ok := stmt.q.(*SelectStmt)
+	if !ok || sel.limit == nil {
+		return 0
+	}
+
+	ref, ok := sel.ds.(*cteRef)
+	if !ok || ref.cte != cte {
+		return 0
+	}
+
+	if sel.distinct || len(sel.joins) > 0 || sel.where != nil || len(sel.groupBy) > 0 || sel.having != nil ||
+		len(sel.orderBy) > 0 || sel.containsAggregations() || len(sel.windowFnExps()) > 0 {
+		return 0
+	}
+
+	for _, t := range sel.targets {
+		if isSubQueryExp(t.Exp) {
+			return 0
+		}
+	}
+
+	for _, other := range stmt.ctes {
+		if other != cte && referencesCTE(other.q, cte) {
+			return 0
+		}
+	}
+
+	// invalid limits and offsets are reported when resolving the query
+	limit, err := evalExpAsInt(tx, sel.limit, params)
+	if err != nil || limit <= 0 {
+		return 0
+	}
+
+	offset := 0
+
+	if sel.offset != nil {
+		offset, err = evalExpAsInt(tx, sel.offset, params)
+		if err != nil || offset < 0 {
+			return 0
+		}
+	}
+	return limit + offset
+}
+
+func (stmt *WithStmt) Alias() string {
+	return stmt.q.Alias()
+}
+
+// recursiveTerms returns the non-recursive and recursive terms of a recursive common table expression
+func (cte *CTE) recursiveTerms() (DataSource, DataSource, bool, error) {
+	u, ok := cte.q.(*UnionStmt)
+	if !ok || referencesCTE(u.left, cte) {
+		return nil, nil, false, fmt.Errorf(
+			"%w: recursive query '%s' must be of the form 'non-recursive-term UNION [ALL] recursive-term'",
+			ErrIllegalArguments,
+			cte.name,
+		)
+	}
+	return u.left, u.right, u.distinct, nil
+}
+
+func referencesCTE(ds DataSource, cte *CTE) bool {
+	switch s := ds.(type) {
+	case *cteRef:
+		return s.cte == cte
+	case *SelectStmt:
+		{
+			if referencesCTE(s.ds, cte) {
+				return true
+			}
+
+			for _, jspec := range s.joins {
+				if referencesCTE(jspec.ds, cte) {
+					return true
+				}
+			}
+		}
+	case *UnionStmt:
+		return referencesCTE(s.left, cte) || referencesCTE(s.right, cte)
+	case *SetOpStmt:
+		return referencesCTE(s.left, cte) || referencesCTE(s.right, cte)
+	case *WithStmt:
+		return referencesCTE(s.q, cte)
+	}
+	return false
+}
+
+// resolveAnchor determines the columns of a recursive common table expression
+// from its non-recursive term and resets its working table
+func (cte *CTE) resolveAnchor(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*cteWorkingTable, error) {
+	anchor, _, _, err := cte.recursiveTerms()
+	if err != nil {
+		return nil, err
+	}
+
+	rowReader, err := anchor.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rowReader.Close()
+
+	cols, err := rowReader.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	colsByPos, err := cte.columns(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &cteWorkingTable{colsByPos: colsByPos}
+
+	if tx.cteWorkingTables == nil {
+		tx.cteWorkingTables = make(map[*CTE]*cteWorkingTable)
+	}
+	tx.cteWorkingTables[cte] = table
+
+	return table, nil
+}
+
+// materialize evaluates a recursive common table expression. The recursive term is repeatedly evaluated
+// over the rows produced by the previous iteration until no new rows are produced, or until maxRows
+// rows are produced when it's greater than zero.
+func (cte *CTE) materialize(ctx context.Context, tx *SQLTx, params map[string]interface{}, maxRows int) error {
+	anchor, recursiveTerm, distinct, err := cte.recursiveTerms()
+	if err != nil {
+		return err
+	}
+
+	table, err := cte.resolveAnchor(ctx, tx, params)
+	if err != nil {
+		return err
+	}
+
+	var result [][]ValueExp
+
+	seen := make(map[[sha256.Size]byte]struct{})
+
+	collect := func(ds DataSource) ([][]ValueExp, error) {
+		rowReader, err := ds.Resolve(ctx, tx, params, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer rowReader.Close()
+
+		cols, err := rowReader.Columns(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = requireCompatibleColumns(table.colsByPos, cols)
+		if err != nil {
+			return nil, err
+		}
+
+		var rows [][]ValueExp
+
+		for {
+			row, err := rowReader.Read(ctx)
+			if errors.Is(err, ErrNoMoreRows) {
+				return rows, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if distinct {
+				digest, err := row.digest(cols)
+				if err != nil {
+					return nil, err
+				}
+
+				if _, ok := seen[digest]; ok {
+					continue
+				}
+				seen[digest] = struct{}{}
+			}
+
+			if maxRows > 0 && len(result)+len(rows) == maxRows {
+				return rows, nil
+			}
+
+			if len(result)+len(rows) == tx.distinctLimit() {
+				return nil, ErrTooManyRows
+			}
+
+			values := make([]ValueExp, len(row.ValuesByPosition))
+			for i, v := range row.ValuesByPosition {
+				values[i] = v
+			}
+			rows = append(rows, values)
+		}
+	}
+
+	rows, err := collect(anchor)
+	if err != nil {
+		return err
+	}
+
+	for len(rows) > 0 {
+		result = append(result, rows...)
+
+		if maxRows > 0 && len(result) >= maxRows {
+			break
+		}
+
+		// the recursive term only sees the rows produced by the previous iteration
+		table.rows = rows
+
+		rows, err = collect(recursiveTerm)
+		if err != nil {
+			return err
+		}
+	}
+
+	table.rows = result
+
+	return nil
+}
+
+// columns returns the columns of the common table expression, named as specified in its declaration
+func (cte *CTE) columns(cols []ColDescriptor) ([]ColDescriptor, error) {
+	if len(cte.cols) > 0 && len(cte.cols) != len(cols) {
+		return nil, fmt.Errorf(
+			"%w: query '%s' has %d columns available but %d columns specified",
+			ErrIllegalArguments,
+			cte.name,
+			len(cols),
+			len(cte.cols),
+		)
+	}
+
+	colsByPos := make([]ColDescriptor, len(cols))
+
+	for i, col := range cols {
+		colsByPos[i] = ColDescriptor{
+			Column: col.Column,
+			Type:   col.Type,
+		}
+
+		if len(cte.cols) > 0 {
+			colsByPos[i].Column = cte.cols[i]
+		}
+	}
+	return colsByPos, nil
+}
+
+// cteRef is a reference to a common table expression used as data source
+type cteRef struct {
+	cte *CTE
+	as  string
+}
+
+func (ref *cteRef) readOnly() bool {
+	return true
+}
+
+func (ref *cteRef) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeSelect}
+}
+
+func (ref *cteRef) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (ref *cteRef) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	return tx, nil
+}
+
+func (ref *cteRef) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
+	if tx == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	if ref.cte.recursive {
+		table, ok := tx.cteWorkingTables[ref.cte]
+		if !ok {
+			return nil, fmt.Errorf("%w: recursive query '%s' was not evaluated", ErrUnexpected, ref.cte.name)
+		}
+		return NewValuesRowReader(tx, params, table.colsByPos, false, ref.Alias(), table.rows)
+	}
+
+	rowReader, err := ref.cte.q.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := rowReader.Columns(ctx)
+	if err != nil {
+		rowReader.Close()
+		return nil, err
+	}
+
+	colsByPos, err := ref.cte.columns(cols)
+	if err != nil {
+		rowReader.Close()
+		return nil, err
+	}
+
+	targets := make([]TargetEntry, len(cols))
+	for i, col := range cols {
+		targets[i] = TargetEntry{
+			Exp: &ColSelector{table: col.Table, col: col.Column},
+			As:  colsByPos[i].Column,
+		}
+	}
+
+	projectedRowReader, err := newProjectedRowReader(ctx, rowReader, ref.Alias(), targets)
+	if err != nil {
+		rowReader.Close()
+		return nil, err
+	}
+	return projectedRowReader, nil
+}
+
+func (ref *cteRef) Alias() string {
+	if ref.as == "" {
+		return ref.cte.name
+	}
+	return ref.as
+}
+
+func NewTableRef(table string, as string) *tableRef {
+	return &tableRef{
+		table: table,
+		as:    as,
+	}
+}
+
+type tableRef struct {
+	table   string
+	history bool
+	period  period
+	as      string
+}
+
+func (ref *tableRef) readOnly() bool {
+	return true
+}
+
+func (ref *tableRef) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeSelect}
+}
+
+type period struct {
+	start *openPeriod
+	end   *openPeriod
+}
+
+type openPeriod struct {
+	inclusive bool
+	instant   periodInstant
+}
+
+type periodInstant struct {
+	exp         ValueExp
+	instantType instantType
+}
+
+type instantType = int
+
+const (
+	txInstant instantType = iota
+	timeInstant
+)
+
+func (i periodInstant) resolve(tx *SQLTx, params map[string]interface{}, asc, inclusive bool) (uint64, error) {
+	exp, err := i.exp.substitute(params)
+	if err != nil {
+		return 0, err
+	}
+
+	instantVal, err := exp.reduce(tx, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	if i.instantType == txInstant {
+		txID, ok := instantVal.RawValue().(int64)
+		if !ok {
+			return 0, fmt.Errorf("%w: invalid tx range, tx ID must be a positive integer, %s given", ErrIllegalArguments, instantVal.Type())
+		}
+
+		if txID <= 0 {
+			return 0, fmt.Errorf("%w: invalid tx range, tx ID must be a positive integer, %d given", ErrIllegalArguments, txID)
+		}
+
+		if inclusive {
+			return uint64(txID), nil
+		}
+
+		if asc {
+			return uint64(txID + 1), nil
+		}
+
+		if txID <= 1 {
+			return 0, fmt.Errorf("%w: invalid tx range, tx ID must be greater than 1, %d given", ErrIllegalArguments, txID)
+		}
+
+		return uint64(txID - 1), nil
+	} else {
+
+		var ts time.Time
+
+		if instantVal.Type() == TimestampType {
+			ts = instantVal.RawValue().(time.Time)
+		} else {
+			conv, err := getConverter(instantVal.Type(), TimestampType)
+			if err != nil {
+				return 0, err
+			}
+
+			tval, err := conv(instantVal)
+			if err != nil {
+				return 0, err
+			}
+
+			ts = tval.RawValue().(time.Time)
+		}
+
+		sts := ts
+
+		if asc {
+			if !inclusive {
+				sts = sts.Add(1 * time.Second)
+			}
+
+			txHdr, err := tx.engine.store.FirstTxSince(sts)
+			if err != nil {
+				return 0, err
+			}
+
+			return txHdr.ID, nil
+		}
+
+		if !inclusive {
+			sts = sts.Add(-1 * time.Second)
+		}
+
+		txHdr, err := tx.engine.store.LastTxUntil(sts)
+		if err != nil {
+			return 0, err
+		}
+
+		return txHdr.ID, nil
+	}
+}
+
+func (stmt *tableRef) referencedTable(tx *SQLTx) (*Table, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func (stmt *tableRef) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *tableRef) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	return tx, nil
+}
+
+func (stmt *tableRef) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error) {
+	if tx == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	table, err := stmt.referencedTable(tx)
+	if err == nil {
+		return newRawRowReader(tx, params, table, stmt.period, stmt.as, scanSpecs)
+	}
+
+	if view, verr := tx.catalog.GetViewByName(stmt.table); verr == nil {
+		return stmt.resolveView(ctx, tx, params, view)
+	}
+
+	if resolver := tx.engine.tableResolveFor(stmt.table); resolver != nil {
+		return resolver.Resolve(ctx, tx, stmt.Alias())
+	}
+	return nil, err
+}
+
+// resolveView resolves the query of the view as a common table expression named after it
+func (stmt *tableRef) resolveView(ctx context.Context, tx *SQLTx, params map[string]interface{}, view *View) (RowReader, error) {
+	if stmt.history || stmt.period.start != nil || stmt.period.end != nil {
+		return nil, fmt.Errorf("%w: periods can not be specified when querying view %s", ErrIllegalArguments, view.name)
+	}
+
+	ref := &cteRef{
+		cte: NewCTE(view.name, view.cols, view.ds),
+		as:  stmt.as,
+	}
+	return ref.Resolve(ctx, tx, params, nil)
+}
+
+func (stmt *tableRef) Alias() string {
+	if stmt.as == "" {
+		return stmt.table
+	}
+	return stmt.as
+}
+
+type valuesDataSource struct {
+	inferTypes bool
+	rows       []*RowSpec
+}
+
+func NewValuesDataSource(rows []*RowSpec) *valuesDataSource {
+	return &valuesDataSource{
+		rows: rows,
+	}
+}
+
+func (ds *valuesDataSource) readOnly() bool {
+	return true
+}
+
+func (ds *valuesDataSource) requiredPrivileges() []SQLPrivilege {
+	return nil
+}
+
+func (ds *valuesDataSource) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	return tx, nil
+}
+
+func (ds *valuesDataSource) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (ds *valuesDataSource) Alias() string {
+	return ""
+}
+
+func (ds *valuesDataSource) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error) {
+	if tx == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	cols := make([]ColDescriptor, len(ds.rows[0].Values))
+	for i := range cols {
+		cols[i] = ColDescriptor{
+			Type:   AnyType,
+			Column: fmt.Sprintf("col%d", i),
+		}
+	}
+
+	emptyColsDesc, emptyParams := map[string]ColDescriptor{}, map[string]string{}
+
+	if ds.inferTypes {
+		for i := 0; i < len(cols); i++ {
+			t := AnyType
+			for j := 0; j < len(ds.rows); j++ {
+				e, err := ds.rows[j].Values[i].substitute(params)
+				if err != nil {
+					return nil, err
+				}
+
+				it, err := e.inferType(tx, emptyColsDesc, emptyParams, "")
+				if err != nil {
+					return nil, err
+				}
+
+				if t == AnyType {
+					t = it
+				} else if t != it && it != AnyType {
+					return nil, fmt.Errorf("cannot match types %s and %s", t, it)
+				}
+			}
+			cols[i].Type = t
+		}
+	}
+
+	values := make([][]ValueExp, len(ds.rows))
+	for i, rowSpec := range ds.rows {
+		values[i] = rowSpec.Values
+	}
+	return NewValuesRowReader(tx, params, cols, ds.inferTypes, "values", values)
+}
+
+type JoinSpec struct {
+	joinType JoinType
+	ds       DataSource
+	cond     ValueExp
+	indexOn  []*indexColSpec
+}
+
+type OrdExp struct {
+	exp       ValueExp
+	descOrder bool
+}
+
+func (oc *OrdExp) AsSelector() Selector {
+	sel, ok := oc.exp.(Selector)
+	if ok {
+		return sel
+	}
+	return nil
+}
+
+func NewOrdCol(table string, col string, descOrder bool) *OrdExp {
+	return &OrdExp{
+		exp:       NewColSelector(table, col),
+		descOrder: descOrder,
+	}
+}
+
+type Selector interface {
+	ValueExp
+	resolve(implicitTable string) (aggFn, table, col string)
+}
+
+type ColSelector struct {
+	table string
+	col   string
+}
+
+func NewColSelector(table, col string) *ColSelector {
+	return &ColSelector{
+		table: table,
+		col:   col,
+	}
+}
+
+func (sel *ColSelector) resolve(implicitTable string) (aggFn, table, col string) {
+	table = implicitTable
+	if sel.table != "" {
+		table = sel.table
+	}
+	return "", table, sel.col
+}
+
+func (sel *ColSelector) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	_, table, col := sel.resolve(implicitTable)
+	encSel := EncodeSelector("", table, col)
+
+	desc, ok := cols[encSel]
+	if !ok {
+		return AnyType, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
+	}
+	return desc.Type, nil
+}
+
+func (sel *ColSelector) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	_, table, col := sel.resolve(implicitTable)
+	encSel := EncodeSelector("", table, col)
+
+	desc, ok := cols[encSel]
+	if !ok {
+		return fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
+	}
+
+	if desc.Type != t {
+		return fmt.Errorf("%w: %v(%s) can not be interpreted as type %v", ErrInvalidTypes, desc.Type, encSel, t)
+	}
+
+	return nil
+}
+
+func (sel *ColSelector) substitute(params map[string]interface{}) (ValueExp, error) {
+	return sel, nil
+}
+
+func (sel *ColSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	if row == nil {
+		return nil, fmt.Errorf("%w: no row to evaluate in current context", ErrInvalidValue)
+	}
+
+	aggFn, table, col := sel.resolve(implicitTable)
+
+	v, ok := row.ValuesBySelector[EncodeSelector(aggFn, table, col)]
+	if !ok {
+		return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
+	}
+	return v, nil
+}
+
+func (sel *ColSelector) selectors() []Selector {
+	return []Selector{sel}
+}
+
+func (sel *ColSelector) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	aggFn, table, col := sel.resolve(implicitTable)
+
+	v, ok := row.ValuesBySelector[EncodeSelector(aggFn, table, col)]
+	if !ok {
+		return sel
+	}
+
+	return v
+}
+
+func (sel *ColSelector) isConstant() bool {
+	return false
+}
+
+func (sel *ColSelector) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (sel *ColSelector) String() string {
+	return sel.col
+}
+
+type AggColSelector struct {
+	aggFn    AggregateFn
+	table    string
+	col      string
+	distinct bool
+
+	// arg holds the separator used by STRING_AGG and the fraction used by PERCENTILE_CONT
+	arg       TypedValue
+	descOrder bool
+}
+
+func NewAggColSelector(aggFn AggregateFn, table, col string) *AggColSelector {
+	return &AggColSelector{
+		aggFn: aggFn,
+		table: table,
+		col:   col,
+	}
+}
+
+func EncodeSelector(aggFn, table, col string) string {
+	return aggFn + "(" + table + "." + col + ")"
+}
+
+// qualifiedAggFn returns the aggregation function including its modifiers,
+// so that different aggregations over the same column are resolved as different selectors
+func (sel *AggColSelector) qualifiedAggFn() string {
+	aggFn := sel.aggFn
+
+	if sel.distinct {
+		aggFn += " DISTINCT"
+	}
+
+	if sel.arg != nil {
+		aggFn += " " + sel.arg.String()
+	}
+
+	if sel.descOrder {
+		aggFn += " DESC"
+	}
+	return aggFn
+}
+
+func (sel *AggColSelector) resolve(implicitTable string) (aggFn, table, col string) {
+	table = implicitTable
+	if sel.table != "" {
+		table = sel.table
+	}
+	return sel.qualifiedAggFn(), table, sel.col
+}
+
+// validate checks the aggregation function is invoked with the expected arguments
+func (sel *AggColSelector) validate() error {
+	switch sel.aggFn {
+	case COUNT, SUM, MAX, MIN, AVG, BOOL_AND, BOOL_OR, STDDEV:
+		{
+			if sel.arg != nil || sel.descOrder {
+				return fmt.Errorf("%w: unexpected arguments in aggregation '%s'", ErrIllegalArguments, sel.aggFn)
+			}
+
+			if sel.col == "*" && sel.aggFn != COUNT {
+				return fmt.Errorf("%w: a column must be specified in aggregation '%s'", ErrIllegalArguments, sel.aggFn)
+			}
+			return nil
+		}
+	case STRING_AGG:
+		{
+			if sel.arg == nil || sel.arg.Type() != VarcharType || sel.descOrder || sel.col == "*" {
+				return fmt.Errorf("%w: aggregation '%s' expects a column and a separator", ErrIllegalArguments, sel.aggFn)
+			}
+			return nil
+		}
+	case PERCENTILE_CONT:
+		{
+			if sel.arg == nil || sel.distinct || sel.col == "*" {
+				return fmt.Errorf("%w: aggregation '%s' expects a fraction and an ordering column", ErrIllegalArguments, sel.aggFn)
+			}
+
+			fraction, ok := sel.arg.RawValue().(float64)
+			if !ok || fraction < 0 || fraction > 1 {
+				return fmt.Errorf("%w: fraction in aggregation '%s' must be a value between 0 and 1", ErrIllegalArguments, sel.aggFn)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown aggregation '%s'", ErrIllegalArguments, sel.aggFn)
+}
+
+func (sel *AggColSelector) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := sel.validate()
+	if err != nil {
+		return AnyType, err
+	}
+
+	if sel.aggFn == COUNT {
+		return IntegerType, nil
+	}
+
+	colSelector := &ColSelector{table: sel.table, col: sel.col}
+
+	t, err := colSelector.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+	return aggregationType(sel.aggFn, t)
+}
+
+// aggregationType returns the type of the result of the aggregation function over values of type t
+func aggregationType(aggFn AggregateFn, t SQLValueType) (SQLValueType, error) {
+	switch aggFn {
+	case COUNT:
+		{
+			return IntegerType, nil
+		}
+	case SUM, AVG, STDDEV, PERCENTILE_CONT:
+		{
+			if !IsNumericType(t) {
+				return AnyType, fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, t)
+			}
+
+			if aggFn == STDDEV || aggFn == PERCENTILE_CONT {
+				return Float64Type, nil
+			}
+			return t, nil
+		}
+	case STRING_AGG:
+		{
+			if t != VarcharType {
+				return AnyType, fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+			}
+			return VarcharType, nil
+		}
+	case BOOL_AND, BOOL_OR:
+		{
+			if t != BooleanType {
+				return AnyType, fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+			}
+			return BooleanType, nil
+		}
+	}
+	return t, nil
+}
+
+func (sel *AggColSelector) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	err := sel.validate()
+	if err != nil {
+		return err
+	}
+
+	if sel.aggFn == COUNT {
+		if t != IntegerType {
+			return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+		}
+		return nil
+	}
+
+	colSelector := &ColSelector{table: sel.table, col: sel.col}
+
+	switch sel.aggFn {
+	case SUM, AVG:
+		{
+			if !IsNumericType(t) {
+				return fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, t)
+			}
+		}
+	case STDDEV, PERCENTILE_CONT, STRING_AGG, BOOL_AND, BOOL_OR:
+		{
+			ct, err := colSelector.inferType(tx, cols, params, implicitTable)
+			if err != nil {
+				return err
+			}
+
+			at, err := aggregationType(sel.aggFn, ct)
+			if err != nil {
+				return err
+			}
+
+			if t != at {
+				return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, at, t)
+			}
+			return nil
+		}
+	}
+
+	return colSelector.requiresType(tx, t, cols, params, implicitTable)
+}
+
+func (sel *AggColSelector) substitute(params map[string]interface{}) (ValueExp, error) {
+	return sel, nil
+}
+
+func (sel *AggColSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	if row == nil {
+		return nil, fmt.Errorf("%w: no row to evaluate aggregation (%s) in current context", ErrInvalidValue, sel.aggFn)
+	}
+
+	v, ok := row.ValuesBySelector[EncodeSelector(sel.resolve(implicitTable))]
+	if !ok {
+		return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, sel.col)
+	}
+	return v, nil
+}
+
+func (sel *AggColSelector) selectors() []Selector {
+	return []Selector{sel}
+}
+
+func (sel *AggColSelector) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return sel
+}
+
+func (sel *AggColSelector) isConstant() bool {
+	return false
+}
+
+func (sel *AggColSelector) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (sel *AggColSelector) String() string {
+	if sel.aggFn == PERCENTILE_CONT && sel.arg != nil {
+		order := ""
+		if sel.descOrder {
+			order = " DESC"
+		}
+		return sel.aggFn + "(" + sel.arg.String() + ") WITHIN GROUP (ORDER BY " + sel.col + order + ")"
+	}
+
+	col := sel.col
+	if sel.distinct {
+		col = "DISTINCT " + col
+	}
+
+	if sel.arg != nil {
+		col += ", " + sel.arg.String()
+	}
+	return sel.aggFn + "(" + col + ")"
+}
+
+type NumExp struct {
+	op          NumOperator
+	left, right ValueExp
+}
+
+func (bexp *NumExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	// First step - check if we can infer the type of sub-expressions
+	tleft, err := bexp.left.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	if isTemporalType(tleft) {
+		tright, err := bexp.right.inferType(tx, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+		return temporalOperatorType(bexp.op, tleft, tright)
+	}
+
+	if tleft != AnyType && !IsNumericType(tleft) && tleft != JSONType {
+		return AnyType, fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, tleft)
+	}
+
+	tright, err := bexp.right.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	if isTemporalType(tright) {
+		return temporalOperatorType(bexp.op, tleft, tright)
+	}
+
+	if tright != AnyType && !IsNumericType(tright) && tright != JSONType {
+		return AnyType, fmt.Errorf("%w: %v or %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, Float64Type, tright)
+	}
+
+	if tleft == IntegerType && tright == IntegerType {
+		// Both sides are integer types - the result is also integer
+		return IntegerType, nil
+	}
+
+	if tleft != AnyType && tright != AnyType {
+		if bexp.decimalLiteralOperands(tleft, tright) {
+			return DecimalType, nil
+		}
+
+		if tleft != Float64Type && tright != Float64Type && (tleft == DecimalType || tright == DecimalType) {
+			// Arithmetic is kept exact unless a float is involved
+			return DecimalType, nil
+		}
+
+		// Both sides have concrete types but at least one of them is float
+		return Float64Type, nil
+	}
+
+	// Both sides are ambiguous
+	return AnyType, nil
+}
+
+func copyParams(params map[string]SQLValueType) map[string]SQLValueType {
+	ret := make(map[string]SQLValueType, len(params))
+	for k, v := range params {
+		ret[k] = v
+	}
+	return ret
+}
+
+func restoreParams(params, restore map[string]SQLValueType) {
+	for k := range params {
+		delete(params, k)
+	}
+	for k, v := range restore {
+		params[k] = v
+	}
+}
+
+func (bexp *NumExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if isTemporalType(t) {
+		return bexp.requiresTemporalType(tx, t, cols, params, implicitTable)
+	}
+
+	if !IsNumericType(t) {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	}
+
+	floatArgs := 2
+	paramsOrig := copyParams(params)
+	err := bexp.left.requiresType(tx, t, cols, params, implicitTable)
+	if err != nil && t == Float64Type {
+		restoreParams(params, paramsOrig)
+		floatArgs--
+		err = bexp.left.requiresType(tx, IntegerType, cols, params, implicitTable)
+	}
+	if err != nil {
+		return err
+	}
+
+	paramsOrig = copyParams(params)
+	err = bexp.right.requiresType(tx, t, cols, params, implicitTable)
+	if err != nil && t == Float64Type {
+		restoreParams(params, paramsOrig)
+		floatArgs--
+		err = bexp.right.requiresType(tx, IntegerType, cols, params, implicitTable)
+	}
+	if err != nil {
+		return err
+	}
+
+	if t == Float64Type && floatArgs == 0 {
+		// Currently this case requires explicit float cast
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	}
+
+	return nil
+}
+
+// requiresTemporalType checks the operands of an addition or subtraction resulting into a timestamp or an interval,
+// i.e. TIMESTAMP +/- INTERVAL, INTERVAL + TIMESTAMP, TIMESTAMP - TIMESTAMP or INTERVAL +/- INTERVAL
+func (bexp *NumExp) requiresTemporalType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if bexp.op != ADDOP && bexp.op != SUBSOP {
+		return fmt.Errorf("%w: operator %s can not result into a value of type %v", ErrInvalidTypes, NumOperatorString(bexp.op), t)
+	}
+
+	tleft, err := bexp.left.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	tright, err := bexp.right.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	ltype, rtype := t, t
+
+	switch {
+	case t == TimestampType && bexp.op == ADDOP && (tleft == IntervalType || tright == TimestampType):
+		ltype = IntervalType
+	case t == TimestampType:
+		rtype = IntervalType
+	case tleft == TimestampType || tright == TimestampType:
+		if bexp.op != SUBSOP {
+			return fmt.Errorf("%w: operator %s can not result into a value of type %v", ErrInvalidTypes, NumOperatorString(bexp.op), t)
+		}
+		ltype, rtype = TimestampType, TimestampType
+	}
+
+	err = bexp.left.requiresType(tx, ltype, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+	return bexp.right.requiresType(tx, rtype, cols, params, implicitTable)
+}
+
+func (bexp *NumExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	rlexp, err := bexp.left.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	rrexp, err := bexp.right.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NumExp{
+		op:    bexp.op,
+		left:  rlexp,
+		right: rrexp,
+	}, nil
+}
+
+func (bexp *NumExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	vl = unwrapJSON(vl)
+	vr = unwrapJSON(vr)
+
+	if vl.IsNull() || vr.IsNull() {
+		// the result of an operation over unknown values is unknown as well
+		t := vl.Type()
+		if t == AnyType {
+			t = vr.Type()
+		}
+		return &NullValue{t: t}, nil
+	}
+
+	if bexp.decimalLiteralOperands(vl.Type(), vr.Type()) {
+		return applyNumOperatorDecimal(bexp.op, vl, vr)
+	}
+
+	return applyNumOperator(bexp.op, vl, vr)
+}
+
+// decimalLiteralOperands returns true when a decimal operand is combined with a non-integer numeric literal,
+// which is then interpreted as an exact decimal value instead of a float, e.g. price + 0.005
+func (bexp *NumExp) decimalLiteralOperands(tleft, tright SQLValueType) bool {
+	_, leftLiteral := bexp.left.(*Float64)
+	_, rightLiteral := bexp.right.(*Float64)
+
+	return (leftLiteral && tright == DecimalType) || (rightLiteral && tleft == DecimalType)
+}
+
+func unwrapJSON(v TypedValue) TypedValue {
+	if jsonVal, ok := v.(*JSON); ok {
+		if sv, isSimple := jsonVal.castToTypedValue(); isSimple {
+			return sv
+		}
+	}
+	return v
+}
+
+func (bexp *NumExp) selectors() []Selector {
+	return append(bexp.left.selectors(), bexp.right.selectors()...)
+}
+
+func (bexp *NumExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &NumExp{
+		op:    bexp.op,
+		left:  bexp.left.reduceSelectors(row, implicitTable),
+		right: bexp.right.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *NumExp) isConstant() bool {
+	return bexp.left.isConstant() && bexp.right.isConstant()
+}
+
+func (bexp *NumExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *NumExp) String() string {
+	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), NumOperatorString(bexp.op), bexp.right.String())
+}
+
+type NotBoolExp struct {
+	exp ValueExp
+}
+
+func (bexp *NotBoolExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := bexp.exp.requiresType(tx, BooleanType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	return BooleanType, nil
+}
+
+func (bexp *NotBoolExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	}
+
+	return bexp.exp.requiresType(tx, BooleanType, cols, params, implicitTable)
+}
+
+func (bexp *NotBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	rexp, err := bexp.exp.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotBoolExp{exp: rexp}, nil
+}
+
+func (bexp *NotBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	v, err := bexp.exp.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	r, isBool := v.RawValue().(bool)
+	if !isBool {
+		return nil, ErrInvalidCondition
+	}
+
+	return &Bool{val: !r}, nil
+}
+
+func (bexp *NotBoolExp) selectors() []Selector {
+	return bexp.exp.selectors()
+}
+
+func (bexp *NotBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &NotBoolExp{
+		exp: bexp.exp.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *NotBoolExp) isConstant() bool {
+	return bexp.exp.isConstant()
+}
+
+func (bexp *NotBoolExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *NotBoolExp) String() string {
+	return fmt.Sprintf("(NOT %s)", bexp.exp.String())
+}
+
+type LikeBoolExp struct {
+	val     ValueExp
+	notLike bool
+	pattern ValueExp
+}
+
+func NewLikeBoolExp(val ValueExp, notLike bool, pattern ValueExp) *LikeBoolExp {
+	return &LikeBoolExp{
+		val:     val,
+		notLike: notLike,
+		pattern: pattern,
+	}
+}
+
+func (bexp *LikeBoolExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	if bexp.val == nil || bexp.pattern == nil {
+		return AnyType, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
+	}
+
+	err := bexp.pattern.requiresType(tx, VarcharType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	return BooleanType, nil
+}
+
+func (bexp *LikeBoolExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if bexp.val == nil || bexp.pattern == nil {
+		return fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
+	}
+
+	if t != BooleanType {
+		return fmt.Errorf("error using the value of the LIKE operator as %s: %w", t, ErrInvalidTypes)
+	}
+
+	err := bexp.pattern.requiresType(tx, VarcharType, cols, params, implicitTable)
+	if err != nil {
+		return fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	return nil
+}
+
+func (bexp *LikeBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	if bexp.val == nil || bexp.pattern == nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
+	}
+
+	val, err := bexp.val.substitute(params)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	pattern, err := bexp.pattern.substitute(params)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	return &LikeBoolExp{
+		val:     val,
+		notLike: bexp.notLike,
+		pattern: pattern,
+	}, nil
+}
+
+func (bexp *LikeBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	if bexp.val == nil || bexp.pattern == nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
+	}
+
+	rval, err := bexp.val.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	if rval.IsNull() {
+		return &Bool{val: bexp.notLike}, nil
+	}
+
+	rvalStr, ok := rval.RawValue().(string)
+	if !ok {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w (expecting %s)", ErrInvalidTypes, VarcharType)
+	}
+
+	rpattern, err := bexp.pattern.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	if rpattern.Type() != VarcharType {
+		return nil, fmt.Errorf("error evaluating 'LIKE' clause: %w", ErrInvalidTypes)
+	}
+
+	matched, err := regexp.MatchString(rpattern.RawValue().(string), rvalStr)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
+	}
+
+	return &Bool{val: matched != bexp.notLike}, nil
+}
+
+func (bexp *LikeBoolExp) selectors() []Selector {
+	return bexp.val.selectors()
+}
+
+func (bexp *LikeBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return bexp
+}
+
+func (bexp *LikeBoolExp) isConstant() bool {
+	return false
+}
+
+func (bexp *LikeBoolExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *LikeBoolExp) String() string {
+	fmtStr := "(%s LIKE %s)"
+	if bexp.notLike {
+		fmtStr = "(%s NOT LIKE %s)"
+	}
+	return fmt.Sprintf(fmtStr, bexp.val.String(), bexp.pattern.String())
+}
+
+type CmpBoolExp struct {
+	op          CmpOperator
+	left, right ValueExp
+}
+
+func NewCmpBoolExp(op CmpOperator, left, right ValueExp) *CmpBoolExp {
+	return &CmpBoolExp{
+		op:    op,
+		left:  left,
+		right: right,
+	}
+}
+
+func (bexp *CmpBoolExp) Left() ValueExp {
+	return bexp.left
+}
+
+func (bexp *CmpBoolExp) Right() ValueExp {
+	return bexp.right
+}
+
+func (bexp *CmpBoolExp) OP() CmpOperator {
+	return bexp.op
+}
+
+func (bexp *CmpBoolExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	tleft, err := bexp.left.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	tright, err := bexp.right.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	// unification step
+
+	if tleft == tright {
+		return BooleanType, nil
+	}
+
+	_, ok := coerceTypes(tleft, tright)
+	if !ok {
+		return AnyType, fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, tleft, tright)
+	}
+
+	if tleft == AnyType {
+		err = bexp.left.requiresType(tx, tright, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+	}
+
+	if tright == AnyType {
+		err = bexp.right.requiresType(tx, tleft, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+	}
+	return BooleanType, nil
+}
+
+func coerceTypes(t1, t2 SQLValueType) (SQLValueType, bool) {
+	switch {
+	case t1 == t2:
+		return t1, true
+	case t1 == AnyType:
+		return t2, true
+	case t2 == AnyType:
+		return t1, true
+	case (t1 == IntegerType && t2 == Float64Type) ||
+		(t1 == Float64Type && t2 == IntegerType):
+		return Float64Type, true
+	case (t1 == DecimalType && t2 == IntegerType) ||
+		(t1 == IntegerType && t2 == DecimalType):
+		return DecimalType, true
+	case (t1 == DecimalType && t2 == Float64Type) ||
+		(t1 == Float64Type && t2 == DecimalType):
+		return Float64Type, true
+	}
+	return "", false
+}
+
+func (bexp *CmpBoolExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	}
+
+	_, err := bexp.inferType(tx, cols, params, implicitTable)
+	return err
+}
+
+func (bexp *CmpBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	rlexp, err := bexp.left.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	rrexp, err := bexp.right.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CmpBoolExp{
+		op:    bexp.op,
+		left:  rlexp,
+		right: rrexp,
+	}, nil
+}
+
+func (bexp *CmpBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if !jsonValuesOfSameKind(vl, vr) {
+		// as when filtered through an index on the JSON path
+		return &Bool{val: bexp.op == NE}, nil
+	}
+
+	r, err := vl.Compare(vr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bool{val: cmpSatisfiesOp(r, bexp.op)}, nil
+}
+
+func (bexp *CmpBoolExp) selectors() []Selector {
+	return append(bexp.left.selectors(), bexp.right.selectors()...)
+}
+
+func (bexp *CmpBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &CmpBoolExp{
+		op:    bexp.op,
+		left:  bexp.left.reduceSelectors(row, implicitTable),
+		right: bexp.right.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *CmpBoolExp) isConstant() bool {
+	return bexp.left.isConstant() && bexp.right.isConstant()
+}
+
+func (bexp *CmpBoolExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	if jsonSel, isJSONSel := bexp.left.(*JSONSelector); isJSONSel && bexp.right.isConstant() {
+		return bexp.jsonPathRanges(tx, jsonSel, table, asTable, params, rangesByColID)
+	}
+
+	matchingFunc := func(_, right ValueExp) (*ColSelector, ValueExp, bool) {
+		s, isSel := bexp.left.(*ColSelector)
+		if isSel && s.col != revCol && bexp.right.isConstant() {
+			return s, right, true
+		}
+		return nil, nil, false
+	}
+
+	sel, c, ok := matchingFunc(bexp.left, bexp.right)
+	if !ok {
+		sel, c, ok = matchingFunc(bexp.right, bexp.left)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	aggFn, t, col := sel.resolve(table.name)
+	if aggFn != "" || t != asTable {
+		return nil
+	}
+
+	column, err := table.GetColumnByName(col)
+	if err != nil {
+		return err
+	}
+
+	val, err := c.substitute(params)
+	if errors.Is(err, ErrMissingParameter) {
+		// TODO: not supported when parameters are not provided during query resolution
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rval, err := val.reduce(tx, nil, table.name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := getConverter(rval.Type(), column.colType); err != nil {
+		// values not convertible to the type of the column, e.g. NOW() compared to an UUID,
+		// can not narrow the range and the type mismatch is reported when the condition is evaluated
+		return nil
+	}
+
+	return updateRangeFor(column.id, rval, bexp.op, rangesByColID)
+}
+
+// jsonPathRanges narrows the range of the virtual column holding the values of the JSON path,
+// which only exists when the path is indexed
+func (bexp *CmpBoolExp) jsonPathRanges(tx *SQLTx, sel *JSONSelector, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	aggFn, t, _ := sel.ColSelector.resolve(table.name)
+	if aggFn != "" || t != asTable {
+		return nil
+	}
+
+	column, indexed := table.getJSONPathColumn(sel.col, sel.fields)
+	if !indexed {
+		return nil
+	}
+
+	val, err := bexp.right.substitute(params)
+	if errors.Is(err, ErrMissingParameter) {
+		// TODO: not supported when parameters are not provided during query resolution
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rval, err := val.reduce(tx, nil, table.name)
+	if err != nil {
+		return err
+	}
+
+	// ranges are only defined for the types values at JSON paths can be compared with
+	switch rval.Type() {
+	case IntegerType, Float64Type, VarcharType, BooleanType:
+		return updateRangeFor(column.id, rval, bexp.op, rangesByColID)
+	}
+	return nil
+}
+
+func (bexp *CmpBoolExp) String() string {
+	opStr := CmpOperatorToString(bexp.op)
+	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), opStr, bexp.right.String())
+}
+
+func updateRangeFor(colID uint32, val TypedValue, cmp CmpOperator, rangesByColID map[uint32]*typedValueRange) error {
+	currRange, ranged := rangesByColID[colID]
+	var newRange *typedValueRange
+
+	switch cmp {
+	case EQ:
+		{
+			newRange = &typedValueRange{
+				lRange: &typedValueSemiRange{
+					val:       val,
+					inclusive: true,
+				},
+				hRange: &typedValueSemiRange{
+					val:       val,
+					inclusive: true,
+				},
+			}
+		}
+	case LT:
+		{
+			newRange = &typedValueRange{
+				hRange: &typedValueSemiRange{
+					val: val,
+				},
+			}
+		}
+	case LE:
+		{
+			newRange = &typedValueRange{
+				hRange: &typedValueSemiRange{
+					val:       val,
+					inclusive: true,
+				},
+			}
+		}
+	case GT:
+		{
+			newRange = &typedValueRange{
+				lRange: &typedValueSemiRange{
+					val: val,
+				},
+			}
+		}
+	case GE:
+		{
+			newRange = &typedValueRange{
+				lRange: &typedValueSemiRange{
+					val:       val,
+					inclusive: true,
+				},
+			}
+		}
+	case NE:
+		{
+			return nil
+		}
+	}
+
+	if !ranged {
+		rangesByColID[colID] = newRange
+		return nil
+	}
+
+	return currRange.refineWith(newRange)
+}
+
+func cmpSatisfiesOp(cmp int, op CmpOperator) bool {
+	switch {
+	case cmp == 0:
+		{
+			return op == EQ || op == LE || op == GE
+		}
+	case cmp < 0:
+		{
+			return op == NE || op == LT || op == LE
+		}
+	case cmp > 0:
+		{
+			return op == NE || op == GT || op == GE
+		}
+	}
+	return false
+}
+
+type BinBoolExp struct {
+	op          LogicOperator
+	left, right ValueExp
+}
+
+func NewBinBoolExp(op LogicOperator, lrexp, rrexp ValueExp) *BinBoolExp {
+	bexp := &BinBoolExp{
+		op: op,
+	}
+
+	bexp.left = lrexp
+	bexp.right = rrexp
+
+	return bexp
+}
+
+func (bexp *BinBoolExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := bexp.left.requiresType(tx, BooleanType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	err = bexp.right.requiresType(tx, BooleanType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, err
+	}
+
+	return BooleanType, nil
+}
+
+func (bexp *BinBoolExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	}
+
+	err := bexp.left.requiresType(tx, BooleanType, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	err = bexp.right.requiresType(tx, BooleanType, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (bexp *BinBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
 	rlexp, err := bexp.left.substitute(params)
 	if err != nil {
 		return nil, err
 	}
 
-	rrexp, err := bexp.right.substitute(params)
+	rrexp, err := bexp.right.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BinBoolExp{
+		op:    bexp.op,
+		left:  rlexp,
+		right: rrexp,
+	}, nil
+}
+
+func (bexp *BinBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	bl, isBool := vl.(*Bool)
+	if !isBool {
+		return nil, fmt.Errorf("%w (expecting boolean value)", ErrInvalidValue)
+	}
+
+	// short-circuit evaluation
+	if (bl.val && bexp.op == Or) || (!bl.val && bexp.op == And) {
+		return &Bool{val: bl.val}, nil
+	}
+
+	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	br, isBool := vr.(*Bool)
+	if !isBool {
+		return nil, fmt.Errorf("%w (expecting boolean value)", ErrInvalidValue)
+	}
+
+	switch bexp.op {
+	case And:
+		{
+			return &Bool{val: bl.val && br.val}, nil
+		}
+	case Or:
+		{
+			return &Bool{val: bl.val || br.val}, nil
+		}
+	}
+
+	return nil, ErrUnexpected
+}
+
+func (bexp *BinBoolExp) selectors() []Selector {
+	return append(bexp.left.selectors(), bexp.right.selectors()...)
+}
+
+func (bexp *BinBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &BinBoolExp{
+		op:    bexp.op,
+		left:  bexp.left.reduceSelectors(row, implicitTable),
+		right: bexp.right.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *BinBoolExp) isConstant() bool {
+	return bexp.left.isConstant() && bexp.right.isConstant()
+}
+
+func (bexp *BinBoolExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	if bexp.op == And {
+		err := bexp.left.selectorRanges(tx, table, asTable, params, rangesByColID)
+		if err != nil {
+			return err
+		}
+
+		return bexp.right.selectorRanges(tx, table, asTable, params, rangesByColID)
+	}
+
+	lRanges := make(map[uint32]*typedValueRange)
+	rRanges := make(map[uint32]*typedValueRange)
+
+	err := bexp.left.selectorRanges(tx, table, asTable, params, lRanges)
+	if err != nil {
+		return err
+	}
+
+	err = bexp.right.selectorRanges(tx, table, asTable, params, rRanges)
+	if err != nil {
+		return err
+	}
+
+	for colID, lr := range lRanges {
+		rr, ok := rRanges[colID]
+		if !ok {
+			continue
+		}
+
+		err = lr.extendWith(rr)
+		if err != nil {
+			return err
+		}
+
+		rangesByColID[colID] = lr
+	}
+
+	return nil
+}
+
+func (bexp *BinBoolExp) String() string {
+	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), LogicOperatorToString(bexp.op), bexp.right.String())
+}
+
+type ExistsBoolExp struct {
+	q      DataSource
+	params map[string]interface{}
+}
+
+func (bexp *ExistsBoolExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return BooleanType, nil
+}
+
+func (bexp *ExistsBoolExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("error inferring type in 'EXISTS' clause: %w", ErrInvalidTypes)
+	}
+	return nil
+}
+
+func (bexp *ExistsBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	return &ExistsBoolExp{
+		q:      bexp.q,
+		params: params,
+	}, nil
+}
+
+func (bexp *ExistsBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	if tx == nil || bexp.q == nil {
+		return nil, fmt.Errorf("error evaluating 'EXISTS' clause: %w", ErrIllegalArguments)
+	}
+
+	ctx := tx.tx.Context()
+
+	rowReader, err := correlateDataSource(bexp.q, row).Resolve(ctx, tx, bexp.params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'EXISTS' clause: %w", err)
+	}
+	defer rowReader.Close()
+
+	_, err = rowReader.Read(ctx)
+	if errors.Is(err, ErrNoMoreRows) {
+		return &Bool{val: false}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'EXISTS' clause: %w", err)
+	}
+	return &Bool{val: true}, nil
+}
+
+func (bexp *ExistsBoolExp) selectors() []Selector {
+	return nil
+}
+
+func (bexp *ExistsBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	if bexp.q == nil {
+		return bexp
+	}
+
+	return &ExistsBoolExp{
+		q:      correlateDataSource(bexp.q, row),
+		params: bexp.params,
+	}
+}
+
+func (bexp *ExistsBoolExp) isConstant() bool {
+	return false
+}
+
+func (bexp *ExistsBoolExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *ExistsBoolExp) String() string {
+	return "EXISTS (SELECT ...)"
+}
+
+type InSubQueryExp struct {
+	val    ValueExp
+	notIn  bool
+	q      DataSource
+	params map[string]interface{}
+	cache  *subQueryCache
+}
+
+func (bexp *InSubQueryExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	t, err := bexp.val.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
+	}
+
+	if tx == nil || bexp.q == nil {
+		return BooleanType, nil
+	}
+
+	qt, err := bexp.subQueryType(tx, cols)
+	if err != nil {
+		return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
+	}
+
+	if t == qt {
+		return BooleanType, nil
+	}
+
+	_, ok := coerceTypes(t, qt)
+	if !ok {
+		return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w: %v can not be interpreted as type %v", ErrInvalidTypes, t, qt)
+	}
+
+	if t == AnyType {
+		err = bexp.val.requiresType(tx, qt, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
+		}
+	}
+	return BooleanType, nil
+}
+
+// subQueryType returns the type of the column returned by the sub-query,
+// references to columns of the enclosing query are bound to NULL values of their type
+func (bexp *InSubQueryExp) subQueryType(tx *SQLTx, cols map[string]ColDescriptor) (SQLValueType, error) {
+	ctx := tx.tx.Context()
+
+	row := &Row{ValuesBySelector: make(map[string]TypedValue, len(cols))}
+	for sel, col := range cols {
+		row.ValuesBySelector[sel] = &NullValue{t: col.Type}
+	}
+
+	rowReader, err := correlateDataSource(bexp.q, row).Resolve(ctx, tx, bexp.params, nil)
+	if err != nil {
+		return AnyType, err
+	}
+	defer rowReader.Close()
+
+	qcols, err := rowReader.Columns(ctx)
+	if err != nil {
+		return AnyType, err
+	}
+
+	if len(qcols) != 1 {
+		return AnyType, fmt.Errorf("%w: sub-query must return a single column", ErrInvalidNumberOfValues)
+	}
+	return qcols[0].Type, nil
+}
+
+func (bexp *InSubQueryExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	_, err := bexp.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	if t != BooleanType {
+		return fmt.Errorf("error inferring type in 'IN' clause: %w", ErrInvalidTypes)
+	}
+	return nil
+}
+
+func (bexp *InSubQueryExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	val, err := bexp.val.substitute(params)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+	}
+
+	return &InSubQueryExp{
+		val:    val,
+		notIn:  bexp.notIn,
+		q:      bexp.q,
+		params: params,
+		cache:  &subQueryCache{},
+	}, nil
+}
+
+// reduce checks whether the value is returned by the sub-query. References to columns of the enclosing query
+// are bound as constants before the sub-query is resolved, so conditions such as 'inner.col = outer.col' can be
+// used to narrow down the scan of the inner table with an index. The sub-query is evaluated once per outer row
+// unless it does not reference the enclosing query, in which case its values are read only once per execution
+func (bexp *InSubQueryExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	if tx == nil || bexp.q == nil {
+		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", ErrIllegalArguments)
+	}
+
+	rval, err := bexp.val.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+	}
+
+	var found, nullFound bool
+
+	match := func(v TypedValue) (bool, error) {
+		if rval.IsNull() || v.IsNull() {
+			nullFound = true
+			return true, nil
+		}
+
+		res, err := rval.Compare(v)
+		if err != nil {
+			return false, err
+		}
+
+		found = res == 0
+
+		return !found, nil
+	}
+
+	if bexp.cache != nil && !bexp.cache.correlatedWith(bexp.q, row) {
+		if bexp.cache.values == nil {
+			bexp.cache.values = make([]TypedValue, 0)
+
+			err = scanSubQuery(tx, bexp.q, bexp.params, func(v TypedValue) (bool, error) {
+				bexp.cache.values = append(bexp.cache.values, v)
+				return true, nil
+			})
+			if err != nil {
+				bexp.cache.values = nil
+				return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+			}
+		}
+
+		for _, v := range bexp.cache.values {
+			next, err := match(v)
+			if err != nil {
+				return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+			}
+			if !next {
+				break
+			}
+		}
+	} else {
+		err = scanSubQuery(tx, correlateDataSource(bexp.q, row), bexp.params, match)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+		}
+	}
+
+	if !found && nullFound {
+		return &NullValue{t: BooleanType}, nil
+	}
+	return &Bool{val: found != bexp.notIn}, nil
+}
+
+// subQueryCache holds the values returned by a sub-query which does not reference the enclosing query
+type subQueryCache struct {
+	checked    bool
+	correlated bool
+	values     []TypedValue
+}
+
+// correlatedWith returns true if the sub-query references values of the row. As rows read during
+// the execution of a query share the same selectors, it's only checked for the first row
+func (c *subQueryCache) correlatedWith(ds DataSource, row *Row) bool {
+	if !c.checked {
+		c.correlated = isCorrelatedDataSource(ds, row)
+		c.checked = true
+	}
+	return c.correlated
+}
+
+// scanSubQuery resolves the single-column sub-query and invokes fn with each value it returns,
+// until there are no more rows or fn returns false
+func scanSubQuery(tx *SQLTx, ds DataSource, params map[string]interface{}, fn func(v TypedValue) (bool, error)) error {
+	ctx := tx.tx.Context()
+
+	rowReader, err := ds.Resolve(ctx, tx, params, nil)
+	if err != nil {
+		return err
+	}
+	defer rowReader.Close()
+
+	cols, err := rowReader.Columns(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(cols) != 1 {
+		return fmt.Errorf("%w: sub-query must return a single column", ErrInvalidNumberOfValues)
+	}
+
+	for {
+		r, err := rowReader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		next, err := fn(r.ValuesByPosition[0])
+		if err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+	}
+}
+
+func (bexp *InSubQueryExp) selectors() []Selector {
+	return bexp.val.selectors()
+}
+
+func (bexp *InSubQueryExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	if bexp.q == nil {
+		return bexp
+	}
+
+	return &InSubQueryExp{
+		val:    bexp.val.reduceSelectors(row, implicitTable),
+		notIn:  bexp.notIn,
+		q:      correlateDataSource(bexp.q, row),
+		params: bexp.params,
+	}
+}
+
+func (bexp *InSubQueryExp) isConstant() bool {
+	return false
+}
+
+func (bexp *InSubQueryExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *InSubQueryExp) String() string {
+	if bexp.notIn {
+		return fmt.Sprintf("(%s NOT IN (SELECT ...))", bexp.val.String())
+	}
+	return fmt.Sprintf("(%s IN (SELECT ...))", bexp.val.String())
+}
+
+func correlateDataSource(ds DataSource, row *Row) DataSource {
+	switch q := ds.(type) {
+	case *SelectStmt:
+		return q.correlate(row)
+	case *UnionStmt:
+		return &UnionStmt{
+			distinct: q.distinct,
+			left:     correlateDataSource(q.left, row),
+			right:    correlateDataSource(q.right, row),
+		}
+	case *SetOpStmt:
+		return &SetOpStmt{
+			op:       q.op,
+			distinct: q.distinct,
+			left:     correlateDataSource(q.left, row),
+			right:    correlateDataSource(q.right, row),
+		}
+	}
+	return ds
+}
+
+func isCorrelatedDataSource(ds DataSource, row *Row) bool {
+	switch q := ds.(type) {
+	case *SelectStmt:
+		return q.isCorrelated(row)
+	case *UnionStmt:
+		return isCorrelatedDataSource(q.left, row) || isCorrelatedDataSource(q.right, row)
+	case *SetOpStmt:
+		return isCorrelatedDataSource(q.left, row) || isCorrelatedDataSource(q.right, row)
+	}
+	return false
+}
+
+func isSubQueryExp(exp ValueExp) bool {
+	found := false
+
+	visitExp(exp, func(e ValueExp) bool {
+		switch e.(type) {
+		case *ExistsBoolExp, *InSubQueryExp:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// usesSequence returns true if the expression calls a sequence function over the named sequence,
+// or over any sequence when no name is given
+func usesSequence(exp ValueExp, name string) bool {
+	found := false
+
+	visitExp(exp, func(e ValueExp) bool {
+		fn, ok := e.(*FnCall)
+		if !ok {
+			return true
+		}
+
+		switch strings.ToUpper(fn.fn) {
+		case NextValFnCall, CurrValFnCall:
+			{
+				if name == "" || len(fn.params) != 1 {
+					found = true
+					break
+				}
+
+				seqName, isLiteral := fn.params[0].(*Varchar)
+				found = !isLiteral || seqName.val == name
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// usesNonDeterministicFunction returns true if the expression calls a builtin function whose result
+// does not solely depend on its arguments, e.g. NOW() or AGE() of a single timestamp, or a user
+// defined function which was not registered as deterministic
+func usesNonDeterministicFunction(tx *SQLTx, exp ValueExp) bool {
+	found := false
+
+	visitExp(exp, func(e ValueExp) bool {
+		fn, ok := e.(*FnCall)
+		if !ok {
+			return true
+		}
+
+		fnName := strings.ToUpper(fn.fn)
+
+		if _, nonDeterministic := nonDeterministicFunctions[fnName]; nonDeterministic {
+			found = true
+		} else if fnName == AgeFnCall {
+			// the age of a single timestamp is computed with respect to the current time
+			found = len(fn.params) == 1
+		} else {
+			ufn, exists := tx.engine.userFunctionFor(fnName)
+			found = exists && !ufn.deterministic
+		}
+
+		return !found
+	})
+	return found
+}
+
+// visitExp invokes visit on exp and on every expression nested into it, in depth-first order.
+// The traversal is stopped as soon as visit returns false.
+func visitExp(exp ValueExp, visit func(ValueExp) bool) bool {
+	if exp == nil {
+		return true
+	}
+
+	if !visit(exp) {
+		return false
+	}
+
+	var children []ValueExp
+
+	switch e := exp.(type) {
+	case *NotBoolExp:
+		children = []ValueExp{e.exp}
+	case *BinBoolExp:
+		children = []ValueExp{e.left, e.right}
+	case *CmpBoolExp:
+		children = []ValueExp{e.left, e.right}
+	case *NumExp:
+		children = []ValueExp{e.left, e.right}
+	case *LikeBoolExp:
+		children = []ValueExp{e.val, e.pattern}
+	case *Cast:
+		children = []ValueExp{e.val}
+	case *InListExp:
+		children = append([]ValueExp{e.val}, e.values...)
+	case *InSubQueryExp:
+		children = []ValueExp{e.val}
+	case *FnCall:
+		children = e.params
+	case *CaseWhenExp:
+		children = []ValueExp{e.exp}
+		for _, wt := range e.whenThen {
+			children = append(children, wt.when, wt.then)
+		}
+		children = append(children, e.elseExp)
+	case *WindowFnExp:
+		children = append(children, e.params...)
+		children = append(children, e.partitionBy...)
+		for _, oe := range e.orderBy {
+			children = append(children, oe.exp)
+		}
+	}
+
+	for _, c := range children {
+		if !visitExp(c, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+type InListExp struct {
+	val    ValueExp
+	notIn  bool
+	values []ValueExp
+}
+
+func (bexp *InListExp) inferType(tx *SQLTx, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	t, err := bexp.val.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
+	}
+
+	for _, v := range bexp.values {
+		err = v.requiresType(tx, t, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
+		}
+	}
+
+	return BooleanType, nil
+}
+
+func (bexp *InListExp) requiresType(tx *SQLTx, t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	_, err := bexp.inferType(tx, cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	if t != BooleanType {
+		return fmt.Errorf("error inferring type in 'IN' clause: %w", ErrInvalidTypes)
+	}
+
+	return nil
+}
+
+func (bexp *InListExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	val, err := bexp.val.substitute(params)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+	}
+
+	values := make([]ValueExp, len(bexp.values))
+
+	for i, val := range bexp.values {
+		values[i], err = val.substitute(params)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+		}
+	}
+
+	return &InListExp{
+		val:    val,
+		notIn:  bexp.notIn,
+		values: values,
+	}, nil
+}
+
+func (bexp *InListExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	rval, err := bexp.val.reduce(tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+	}
+
+	var found bool
+
+	for _, v := range bexp.values {
+		rv, err := v.reduce(tx, row, implicitTable)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+		}
+
+		r, err := rval.Compare(rv)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+		}
+
+		if r == 0 {
+			// TODO: short-circuit evaluation may be preferred when upfront static type inference is in place
+			found = found || true
+		}
+	}
+
+	return &Bool{val: found != bexp.notIn}, nil
+}
+
+func (bexp *InListExp) selectors() []Selector {
+	selectors := make([]Selector, 0, len(bexp.values))
+	for _, v := range bexp.values {
+		selectors = append(selectors, v.selectors()...)
+	}
+	return append(bexp.val.selectors(), selectors...)
+}
+
+func (bexp *InListExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	values := make([]ValueExp, len(bexp.values))
+
+	for i, val := range bexp.values {
+		values[i] = val.reduceSelectors(row, implicitTable)
+	}
+
+	return &InListExp{
+		val:    bexp.val.reduceSelectors(row, implicitTable),
+		values: values,
+	}
+}
+
+func (bexp *InListExp) isConstant() bool {
+	return false
+}
+
+func (bexp *InListExp) selectorRanges(tx *SQLTx, table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	// TODO: may be determiined by smallest and bigggest value in the list
+	return nil
+}
+
+func (bexp *InListExp) String() string {
+	values := make([]string, len(bexp.values))
+	for i, exp := range bexp.values {
+		values[i] = exp.String()
+	}
+	return fmt.Sprintf("%s IN (%s)", bexp.val.String(), strings.Join(values, ","))
+}
+
+type FnDataSourceStmt struct {
+	fnCall *FnCall
+	as     string
+}
+
+func (stmt *FnDataSourceStmt) readOnly() bool {
+	return true
+}
+
+func (stmt *FnDataSourceStmt) requiredPrivileges() []SQLPrivilege {
+	return nil
+}
+
+func (stmt *FnDataSourceStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	return tx, nil
+}
+
+func (stmt *FnDataSourceStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *FnDataSourceStmt) Alias() string {
+	if stmt.as != "" {
+		return stmt.as
+	}
+
+	switch strings.ToUpper(stmt.fnCall.fn) {
+	case DatabasesFnCall:
+		{
+			return "databases"
+		}
+	case TablesFnCall:
+		{
+			return "tables"
+		}
+	case ViewsFnCall:
+		{
+			return "views"
+		}
+	case TableFnCall:
+		{
+			return "table"
+		}
+	case UsersFnCall:
+		{
+			return "users"
+		}
+	case ColumnsFnCall:
+		{
+			return "columns"
+		}
+	case IndexesFnCall:
+		{
+			return "indexes"
+		}
+	case GrantsFnCall:
+		return "grants"
+	case UnnestFnCall:
+		return "unnest"
+	}
+
+	// not reachable
+	return ""
+}
+
+func (stmt *FnDataSourceStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (rowReader RowReader, err error) {
+	if stmt.fnCall == nil {
+		return nil, fmt.Errorf("%w: function is unspecified", ErrIllegalArguments)
+	}
+
+	switch strings.ToUpper(stmt.fnCall.fn) {
+	case DatabasesFnCall:
+		{
+			return stmt.resolveListDatabases(ctx, tx, params, scanSpecs)
+		}
+	case TablesFnCall:
+		{
+			return stmt.resolveListTables(ctx, tx, params, scanSpecs)
+		}
+	case ViewsFnCall:
+		{
+			return stmt.resolveListViews(ctx, tx, params, scanSpecs)
+		}
+	case TableFnCall:
+		{
+			return stmt.resolveShowTable(ctx, tx, params, scanSpecs)
+		}
+	case UsersFnCall:
+		{
+			return stmt.resolveListUsers(ctx, tx, params, scanSpecs)
+		}
+	case ColumnsFnCall:
+		{
+			return stmt.resolveListColumns(ctx, tx, params, scanSpecs)
+		}
+	case IndexesFnCall:
+		{
+			return stmt.resolveListIndexes(ctx, tx, params, scanSpecs)
+		}
+	case GrantsFnCall:
+		{
+			return stmt.resolveListGrants(ctx, tx, params, scanSpecs)
+		}
+	case UnnestFnCall:
+		{
+			return stmt.resolveUnnest(ctx, tx, params, scanSpecs)
+		}
+	}
+
+	return nil, fmt.Errorf("%w (%s)", ErrFunctionDoesNotExist, stmt.fnCall.fn)
+}
+
+// resolveUnnest expands an array into a set of rows, one per element
+func (stmt *FnDataSourceStmt) resolveUnnest(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
+	if len(stmt.fnCall.params) != 1 {
+		return nil, fmt.Errorf("%w: function '%s' expect an array as parameter", ErrIllegalArguments, UnnestFnCall)
+	}
+
+	val, err := stmt.fnCall.params[0].substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, err := reduceArray(tx, nil, "", val)
+	if err != nil {
+		return nil, err
+	}
+
+	elemType := AnyType
+	if arr != nil {
+		elemType = arr.elemType
+	} else if t, err := val.inferType(tx, nil, nil, ""); err == nil && IsArrayType(t) {
+		elemType = ArrayElemType(t)
+	}
+
+	if elemType == AnyType {
+		return nil, fmt.Errorf("%w: function '%s' can not determine the type of the elements of the array", ErrIllegalArguments, UnnestFnCall)
+	}
+
+	cols := []ColDescriptor{
+		{
+			Column: "unnest",
+			Type:   elemType,
+		},
+	}
+
+	var values [][]ValueExp
+
+	if arr != nil {
+		values = make([][]ValueExp, len(arr.vals))
+
+		for i, v := range arr.vals {
+			values[i] = []ValueExp{v}
+		}
+	}
+
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *FnDataSourceStmt) resolveListDatabases(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
+	if len(stmt.fnCall.params) > 0 {
+		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, DatabasesFnCall, len(stmt.fnCall.params))
+	}
+
+	cols := make([]ColDescriptor, 1)
+	cols[0] = ColDescriptor{
+		Column: "name",
+		Type:   VarcharType,
+	}
+
+	var dbs []string
+
+	if tx.engine.multidbHandler == nil {
+		return nil, ErrUnspecifiedMultiDBHandler
+	} else {
+		dbs, err = tx.engine.multidbHandler.ListDatabases(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([][]ValueExp, len(dbs))
+
+	for i, db := range dbs {
+		values[i] = []ValueExp{&Varchar{val: db}}
+	}
+
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *FnDataSourceStmt) resolveListTables(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
+	if len(stmt.fnCall.params) > 0 {
+		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, TablesFnCall, len(stmt.fnCall.params))
+	}
+
+	cols := make([]ColDescriptor, 1)
+	cols[0] = ColDescriptor{
+		Column: "name",
+		Type:   VarcharType,
+	}
+
+	tables := tx.catalog.GetTables()
+
+	values := make([][]ValueExp, len(tables))
+
+	for i, t := range tables {
+		values[i] = []ValueExp{&Varchar{val: t.name}}
+	}
+
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *FnDataSourceStmt) resolveListViews(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
+	if len(stmt.fnCall.params) > 0 {
+		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, ViewsFnCall, len(stmt.fnCall.params))
+	}
+
+	cols := []ColDescriptor{
+		{
+			Column: "name",
+			Type:   VarcharType,
+		},
+		{
+			Column: "query",
+			Type:   VarcharType,
+		},
+	}
+
+	views := tx.catalog.GetViews()
+
+	values := make([][]ValueExp, len(views))
+
+	for i, v := range views {
+		values[i] = []ValueExp{
+			&Varchar{val: v.name},
+			&Varchar{val: v.query},
+		}
+	}
+
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *FnDataSourceStmt) resolveShowTable(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
+	cols := []ColDescriptor{
+		{
+			Column: "column_name",
+			Type:   VarcharType,
+		},
+		{
+			Column: "type_name",
+			Type:   VarcharType,
+		},
+		{
+			Column: "is_nullable",
+			Type:   BooleanType,
+		},
+		{
+			Column: "is_indexed",
+			Type:   VarcharType,
+		},
+		{
+			Column: "is_auto_increment",
+			Type:   BooleanType,
+		},
+		{
+			Column: "is_unique",
+			Type:   BooleanType,
+		},
+	}
+
+	tableName, _ := stmt.fnCall.params[0].reduce(tx, nil, "")
+	table, err := tx.catalog.GetTableByName(tableName.RawValue().(string))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]ValueExp, len(table.cols))
+
+	for i, c := range table.cols {
+		index := "NO"
+
+		indexed, err := table.IsIndexed(c.Name())
+		if err != nil {
+			return nil, err
+		}
+		if indexed {
+			index = "YES"
+		}
+
+		if table.PrimaryIndex().IncludesCol(c.ID()) {
+			index = "PRIMARY KEY"
+		}
+
+		var unique bool
+		for _, index := range table.GetIndexesByColID(c.ID()) {
+			if index.IsUnique() && len(index.Cols()) == 1 {
+				unique = true
+				break
+			}
+		}
+
+		values[i] = []ValueExp{
+			&Varchar{val: c.colName},
+			&Varchar{val: c.TypeName()},
+			&Bool{val: c.IsNullable()},
+			&Varchar{val: index},
+			&Bool{val: c.IsAutoIncremental()},
+			&Bool{val: unique},
+		}
+	}
+
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *FnDataSourceStmt) resolveListUsers(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
+	if len(stmt.fnCall.params) > 0 {
+		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, UsersFnCall, len(stmt.fnCall.params))
+	}
+
+	cols := []ColDescriptor{
+		{
+			Column: "name",
+			Type:   VarcharType,
+		},
+		{
+			Column: "permission",
+			Type:   VarcharType,
+		},
+	}
+
+	users, err := tx.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]ValueExp, len(users))
+	for i, user := range users {
+		perm := user.Permission()
+
+		values[i] = []ValueExp{
+			&Varchar{val: user.Username()},
+			&Varchar{val: perm},
+		}
+	}
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+}
+
+func (stmt *FnDataSourceStmt) resolveListColumns(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
+	if len(stmt.fnCall.params) != 1 {
+		return nil, fmt.Errorf("%w: function '%s' expect table name as parameter", ErrIllegalArguments, ColumnsFnCall)
+	}
+
+	cols := []ColDescriptor{
+		{
+			Column: "table",
+			Type:   VarcharType,
+		},
+		{
+			Column: "name",
+			Type:   VarcharType,
+		},
+		{
+			Column: "type",
+			Type:   VarcharType,
+		},
+		{
+			Column: "max_length",
+			Type:   IntegerType,
+		},
+		{
+			Column: "scale",
+			Type:   IntegerType,
+		},
+		{
+			Column: "nullable",
+			Type:   BooleanType,
+		},
+		{
+			Column: "auto_increment",
+			Type:   BooleanType,
+		},
+		{
+			Column: "indexed",
+			Type:   BooleanType,
+		},
+		{
+			Column: "primary",
+			Type:   BooleanType,
+		},
+		{
+			Column: "unique",
+			Type:   BooleanType,
+		},
+		{
+			Column: "foreign_key",
+			Type:   VarcharType,
+		},
+		{
+			Column: "referenced_table",
+			Type:   VarcharType,
+		},
+		{
+			Column: "referenced_column",
+			Type:   VarcharType,
+		},
+		{
+			Column: "default_value",
+			Type:   VarcharType,
+		},
+		{
+			Column: "generated_as",
+			Type:   VarcharType,
+		},
+	}
+
+	val, err := stmt.fnCall.params[0].substitute(params)
 	if err != nil {
 		return nil, err
 	}
 
-	bexp.left = rlexp
-	bexp.right = rrexp
-
-	return bexp, nil
-}
-
-func (bexp *BinBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	tableName, err := val.reduce(tx, nil, "")
 	if err != nil {
 		return nil, err
 	}
 
-	bl, isBool := vl.(*Bool)
-	if !isBool {
-		return nil, fmt.Errorf("%w (expecting boolean value)", ErrInvalidValue)
-	}
-
-	// short-circuit evaluation
-	if (bl.val && bexp.op == Or) || (!bl.val && bexp.op == And) {
-		return &Bool{val: bl.val}, nil
+	if tableName.Type() != VarcharType {
+		return nil, fmt.Errorf("%w: expected '%s' for table name but type '%s' given instead", ErrIllegalArguments, VarcharType, tableName.Type())
 	}
 
-	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	table, err := tx.catalog.GetTableByName(tableName.RawValue().(string))
 	if err != nil {
 		return nil, err
 	}
 
-	br, isBool := vr.(*Bool)
-	if !isBool {
-		return nil, fmt.Errorf("%w (expecting boolean value)", ErrInvalidValue)
-	}
+	values := make([][]ValueExp, len(table.cols))
 
-	switch bexp.op {
-	case And:
-		{
-			return &Bool{val: bl.val && br.val}, nil
-		}
-	case Or:
-		{
-			return &Bool{val: bl.val || br.val}, nil
+	for i, c := range table.cols {
+		indexed, err := table.IsIndexed(c.Name())
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	return nil, ErrUnexpected
-}
-
-func (bexp *BinBoolExp) selectors() []Selector {
-	return append(bexp.left.selectors(), bexp.right.selectors()...)
-}
-
-func (bexp *BinBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return &BinBoolExp{
-		op:    bexp.op,
-		left:  bexp.left.reduceSelectors(row, implicitTable),
-		right: bexp.right.reduceSelectors(row, implicitTable),
-	}
-}
-
-func (bexp *BinBoolExp) isConstant() bool {
-	return bexp.left.isConstant() && bexp.right.isConstant()
-}
 
-func (bexp *BinBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	if bexp.op == And {
-		err := bexp.left.selectorRanges(table, asTable, params, rangesByColID)
-		if err != nil {
-			return err
+		var unique bool
+		for _, index := range table.indexesByColID[c.id] {
+			if index.IsUnique() && len(index.Cols()) == 1 {
+				unique = true
+				break
+			}
 		}
 
-		return bexp.right.selectorRanges(table, asTable, params, rangesByColID)
-	}
+		maxLen := c.MaxLen()
 
-	lRanges := make(map[uint32]*typedValueRange)
-	rRanges := make(map[uint32]*typedValueRange)
+		// the max length of DECIMAL columns is their precision, which along with the scale is unset when unconstrained
+		var scale ValueExp = NewNull(IntegerType)
+		if ArrayElemType(c.colType) == DecimalType {
+			maxLen = decimalPrecision(c.maxLen)
 
-	err := bexp.left.selectorRanges(table, asTable, params, lRanges)
-	if err != nil {
-		return err
-	}
+			if c.maxLen > 0 {
+				scale = &Integer{val: int64(decimalScale(c.maxLen))}
+			}
+		}
 
-	err = bexp.right.selectorRanges(table, asTable, params, rRanges)
-	if err != nil {
-		return err
-	}
+		var fkName, refTable, refCol ValueExp = NewNull(VarcharType), NewNull(VarcharType), NewNull(VarcharType)
 
-	for colID, lr := range lRanges {
-		rr, ok := rRanges[colID]
-		if !ok {
-			continue
+		fk, referencedCol := table.foreignKeyIncluding(c.id)
+		if fk != nil {
+			fkName = &Varchar{val: fk.name}
+			refTable = &Varchar{val: fk.refTable.name}
+			refCol = &Varchar{val: referencedCol.colName}
 		}
 
-		err = lr.extendWith(rr)
-		if err != nil {
-			return err
+		values[i] = []ValueExp{
+			&Varchar{val: table.name},
+			&Varchar{val: c.colName},
+			&Varchar{val: c.colType},
+			&Integer{val: int64(maxLen)},
+			scale,
+			&Bool{val: c.IsNullable()},
+			&Bool{val: c.autoIncrement},
+			&Bool{val: indexed},
+			&Bool{val: table.PrimaryIndex().IncludesCol(c.ID())},
+			&Bool{val: unique},
+			fkName,
+			refTable,
+			refCol,
+			expText(c.defaultExp),
+			expText(c.generatedExp),
 		}
-
-		rangesByColID[colID] = lr
 	}
 
-	return nil
-}
-
-func (bexp *BinBoolExp) String() string {
-	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), LogicOperatorToString(bexp.op), bexp.right.String())
-}
-
-type ExistsBoolExp struct {
-	q DataSource
-}
-
-func (bexp *ExistsBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return AnyType, fmt.Errorf("error inferring type in 'EXISTS' clause: %w", ErrNoSupported)
-}
-
-func (bexp *ExistsBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	return fmt.Errorf("error inferring type in 'EXISTS' clause: %w", ErrNoSupported)
-}
-
-func (bexp *ExistsBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	return bexp, nil
-}
-
-func (bexp *ExistsBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return nil, fmt.Errorf("'EXISTS' clause: %w", ErrNoSupported)
-}
-
-func (bexp *ExistsBoolExp) selectors() []Selector {
-	return nil
-}
-
-func (bexp *ExistsBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return bexp
-}
-
-func (bexp *ExistsBoolExp) isConstant() bool {
-	return false
-}
-
-func (bexp *ExistsBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
-}
-
-func (bexp *ExistsBoolExp) String() string {
-	return ""
-}
-
-type InSubQueryExp struct {
-	val   ValueExp
-	notIn bool
-	q     *SelectStmt
-}
-
-func (bexp *InSubQueryExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", ErrNoSupported)
-}
-
-func (bexp *InSubQueryExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	return fmt.Errorf("error inferring type in 'IN' clause: %w", ErrNoSupported)
-}
-
-func (bexp *InSubQueryExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	return bexp, nil
-}
-
-func (bexp *InSubQueryExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	return nil, fmt.Errorf("error inferring type in 'IN' clause: %w", ErrNoSupported)
-}
-
-func (bexp *InSubQueryExp) selectors() []Selector {
-	return bexp.val.selectors()
-}
-
-func (bexp *InSubQueryExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	return bexp
-}
-
-func (bexp *InSubQueryExp) isConstant() bool {
-	return false
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
 }
 
-func (bexp *InSubQueryExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	return nil
+func expText(exp ValueExp) ValueExp {
+	if exp == nil {
+		return NewNull(VarcharType)
+	}
+	return &Varchar{val: exp.String()}
 }
 
-func (bexp *InSubQueryExp) String() string {
-	return ""
-}
+func (stmt *FnDataSourceStmt) resolveListIndexes(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
+	if len(stmt.fnCall.params) != 1 {
+		return nil, fmt.Errorf("%w: function '%s' expect table name as parameter", ErrIllegalArguments, IndexesFnCall)
+	}
 
-// TODO: once InSubQueryExp is supported, this struct may become obsolete by creating a ListDataSource struct
-type InListExp struct {
-	val    ValueExp
-	notIn  bool
-	values []ValueExp
-}
+	cols := []ColDescriptor{
+		{
+			Column: "table",
+			Type:   VarcharType,
+		},
+		{
+			Column: "name",
+			Type:   VarcharType,
+		},
+		{
+			Column: "unique",
+			Type:   BooleanType,
+		},
+		{
+			Column: "primary",
+			Type:   BooleanType,
+		},
+		{
+			Column: "referenced_by",
+			Type:   VarcharType,
+		},
+	}
 
-func (bexp *InListExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	t, err := bexp.val.inferType(cols, params, implicitTable)
+	val, err := stmt.fnCall.params[0].substitute(params)
 	if err != nil {
-		return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
+		return nil, err
 	}
 
-	for _, v := range bexp.values {
-		err = v.requiresType(t, cols, params, implicitTable)
-		if err != nil {
-			return AnyType, fmt.Errorf("error inferring type in 'IN' clause: %w", err)
-		}
+	tableName, err := val.reduce(tx, nil, "")
+	if err != nil {
+		return nil, err
 	}
 
-	return BooleanType, nil
-}
+	if tableName.Type() != VarcharType {
+		return nil, fmt.Errorf("%w: expected '%s' for table name but type '%s' given instead", ErrIllegalArguments, VarcharType, tableName.Type())
+	}
 
-func (bexp *InListExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	_, err := bexp.inferType(cols, params, implicitTable)
+	table, err := tx.catalog.GetTableByName(tableName.RawValue().(string))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if t != BooleanType {
-		return fmt.Errorf("error inferring type in 'IN' clause: %w", ErrInvalidTypes)
-	}
+	referencing := tx.catalog.referencingForeignKeys(table)
 
-	return nil
-}
+	values := make([][]ValueExp, len(table.indexes))
 
-func (bexp *InListExp) substitute(params map[string]interface{}) (ValueExp, error) {
-	val, err := bexp.val.substitute(params)
-	if err != nil {
-		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
-	}
+	for i, index := range table.indexes {
+		var referencedBy []string
+		for _, fk := range referencing {
+			if fk.refIndex == index {
+				referencedBy = append(referencedBy, fk.table.name+"."+fk.name)
+			}
+		}
 
-	values := make([]ValueExp, len(bexp.values))
+		var refs ValueExp = NewNull(VarcharType)
+		if len(referencedBy) > 0 {
+			refs = &Varchar{val: strings.Join(referencedBy, ", ")}
+		}
 
-	for i, val := range bexp.values {
-		values[i], err = val.substitute(params)
-		if err != nil {
-			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+		values[i] = []ValueExp{
+			&Varchar{val: table.name},
+			&Varchar{val: index.Name()},
+			&Bool{val: index.unique},
+			&Bool{val: index.IsPrimary()},
+			refs,
 		}
 	}
 
-	return &InListExp{
-		val:    val,
-		notIn:  bexp.notIn,
-		values: values,
-	}, nil
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
 }
 
-func (bexp *InListExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	rval, err := bexp.val.reduce(tx, row, implicitTable)
-	if err != nil {
-		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+func (stmt *FnDataSourceStmt) resolveListGrants(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
+	if len(stmt.fnCall.params) > 1 {
+		return nil, fmt.Errorf("%w: function '%s' expect at most one parameter of type %s", ErrIllegalArguments, GrantsFnCall, VarcharType)
 	}
 
-	var found bool
-
-	for _, v := range bexp.values {
-		rv, err := v.reduce(tx, row, implicitTable)
+	var username string
+	if len(stmt.fnCall.params) == 1 {
+		val, err := stmt.fnCall.params[0].substitute(params)
 		if err != nil {
-			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+			return nil, err
 		}
 
-		r, err := rval.Compare(rv)
+		userVal, err := val.reduce(tx, nil, "")
 		if err != nil {
-			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
+			return nil, err
 		}
 
-		if r == 0 {
-			// TODO: short-circuit evaluation may be preferred when upfront static type inference is in place
-			found = found || true
+		if userVal.Type() != VarcharType {
+			return nil, fmt.Errorf("%w: expected '%s' for username but type '%s' given instead", ErrIllegalArguments, VarcharType, userVal.Type())
 		}
+		username, _ = userVal.RawValue().(string)
 	}
 
-	return &Bool{val: found != bexp.notIn}, nil
-}
-
-func (bexp *InListExp) selectors() []Selector {
-	selectors := make([]Selector, 0, len(bexp.values))
-	for _, v := range bexp.values {
-		selectors = append(selectors, v.selectors()...)
+	cols := []ColDescriptor{
+		{
+			Column: "user",
+			Type:   VarcharType,
+		},
+		{
+			Column: "privilege",
+			Type:   VarcharType,
+		},
 	}
-	return append(bexp.val.selectors(), selectors...)
-}
 
-func (bexp *InListExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
-	values := make([]ValueExp, len(bexp.values))
+	var err error
+	var users []User
 
-	for i, val := range bexp.values {
-		values[i] = val.reduceSelectors(row, implicitTable)
+	if tx.engine.multidbHandler == nil {
+		return nil, ErrUnspecifiedMultiDBHandler
+	} else {
+		users, err = tx.engine.multidbHandler.ListUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &InListExp{
-		val:    bexp.val.reduceSelectors(row, implicitTable),
-		values: values,
+	values := make([][]ValueExp, 0, len(users))
+
+	for _, user := range users {
+		if username == "" || user.Username() == username {
+			for _, p := range user.SQLPrivileges() {
+				values = append(values, []ValueExp{
+					&Varchar{val: user.Username()},
+					&Varchar{val: string(p)},
+				})
+			}
+		}
 	}
-}
 
-func (bexp *InListExp) isConstant() bool {
-	return false
+	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
 }
 
-func (bexp *InListExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	// TODO: may be determiined by smallest and bigggest value in the list
-	return nil
+// DropTableStmt represents a statement to delete a table.
+type DropTableStmt struct {
+	table string
 }
 
-func (bexp *InListExp) String() string {
-	values := make([]string, len(bexp.values))
-	for i, exp := range bexp.values {
-		values[i] = exp.String()
-	}
-	return fmt.Sprintf("%s IN (%s)", bexp.val.String(), strings.Join(values, ","))
+func NewDropTableStmt(table string) *DropTableStmt {
+	return &DropTableStmt{table: table}
 }
 
-type FnDataSourceStmt struct {
-	fnCall *FnCall
-	as     string
+func (stmt *DropTableStmt) readOnly() bool {
+	return false
 }
 
-func (stmt *FnDataSourceStmt) readOnly() bool {
-	return true
+func (stmt *DropTableStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeDrop}
 }
 
-func (stmt *FnDataSourceStmt) requiredPrivileges() []SQLPrivilege {
+func (stmt *DropTableStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
 	return nil
 }
 
-func (stmt *FnDataSourceStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	return tx, nil
-}
-
-func (stmt *FnDataSourceStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
-	return nil
-}
+/*
+Exec executes the delete table statement.
+It the table exists, if not it does nothing.
+If the table exists, it deletes all the indexes and the table itself.
+Note that this is a soft delete of the index and table key,
+the data is not deleted, but the metadata is updated.
+*/
+func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	if !tx.catalog.ExistTable(stmt.table) {
+		return nil, ErrTableDoesNotExist
+	}
 
-func (stmt *FnDataSourceStmt) Alias() string {
-	if stmt.as != "" {
-		return stmt.as
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
 	}
 
-	switch strings.ToUpper(stmt.fnCall.fn) {
-	case DatabasesFnCall:
-		{
-			return "databases"
-		}
-	case TablesFnCall:
-		{
-			return "tables"
-		}
-	case TableFnCall:
-		{
-			return "table"
-		}
-	case UsersFnCall:
-		{
-			return "users"
-		}
-	case ColumnsFnCall:
-		{
-			return "columns"
-		}
-	case IndexesFnCall:
-		{
-			return "indexes"
+	for _, fk := range tx.catalog.referencingForeignKeys(table) {
+		if fk.table != table {
+			return nil, fmt.Errorf("%w %s because %s constraint of table %s requires it", ErrCannotDropTable, table.name, fk.name, fk.table.name)
 		}
-	case GrantsFnCall:
-		return "grants"
 	}
 
-	// not reachable
-	return ""
-}
+	if view := tx.catalog.dependentView(table.name); view != nil {
+		return nil, fmt.Errorf("%w %s because view %s requires it", ErrCannotDropTable, table.name, view.name)
+	}
 
-func (stmt *FnDataSourceStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (rowReader RowReader, err error) {
-	if stmt.fnCall == nil {
-		return nil, fmt.Errorf("%w: function is unspecified", ErrIllegalArguments)
+	// delete table
+	mappedKey := MapKey(
+		tx.sqlPrefix(),
+		catalogTablePrefix,
+		EncodeID(DatabaseID),
+		EncodeID(table.id),
+	)
+	err = tx.delete(ctx, mappedKey)
+	if err != nil {
+		return nil, err
 	}
 
-	switch strings.ToUpper(stmt.fnCall.fn) {
-	case DatabasesFnCall:
-		{
-			return stmt.resolveListDatabases(ctx, tx, params, scanSpecs)
-		}
-	case TablesFnCall:
-		{
-			return stmt.resolveListTables(ctx, tx, params, scanSpecs)
-		}
-	case TableFnCall:
-		{
-			return stmt.resolveShowTable(ctx, tx, params, scanSpecs)
+	// delete columns
+	cols := table.ColumnsByID()
+	for _, col := range cols {
+		mappedKey := MapKey(
+			tx.sqlPrefix(),
+			catalogColumnPrefix,
+			EncodeID(DatabaseID),
+			EncodeID(col.table.id),
+			EncodeID(col.id),
+			[]byte(col.colType),
+		)
+		err = tx.delete(ctx, mappedKey)
+		if err != nil {
+			return nil, err
 		}
-	case UsersFnCall:
-		{
-			return stmt.resolveListUsers(ctx, tx, params, scanSpecs)
+	}
+
+	// delete checks
+	for name := range table.checkConstraints {
+		key := MapKey(
+			tx.sqlPrefix(),
+			catalogCheckPrefix,
+			EncodeID(DatabaseID),
+			EncodeID(table.id),
+			[]byte(name),
+		)
+
+		if err := tx.delete(ctx, key); err != nil {
+			return nil, err
 		}
-	case ColumnsFnCall:
-		{
-			return stmt.resolveListColumns(ctx, tx, params, scanSpecs)
+	}
+
+	// delete foreign keys
+	for _, fk := range table.foreignKeys {
+		if err := persistForeignKeyDeletion(ctx, tx, table.id, fk.id); err != nil {
+			return nil, err
 		}
-	case IndexesFnCall:
-		{
-			return stmt.resolveListIndexes(ctx, tx, params, scanSpecs)
+	}
+
+	// delete indexes
+	for _, index := range table.indexes {
+		mappedKey := MapKey(
+			tx.sqlPrefix(),
+			catalogIndexPrefix,
+			EncodeID(DatabaseID),
+			EncodeID(table.id),
+			EncodeID(index.id),
+		)
+		err = tx.delete(ctx, mappedKey)
+		if err != nil {
+			return nil, err
 		}
-	case GrantsFnCall:
-		{
-			return stmt.resolveListGrants(ctx, tx, params, scanSpecs)
+
+		indexKey := MapKey(
+			tx.sqlPrefix(),
+			MappedPrefix,
+			EncodeID(table.id),
+			EncodeID(index.id),
+		)
+		err = tx.addOnCommittedCallback(func(sqlTx *SQLTx) error {
+			return sqlTx.engine.store.DeleteIndex(indexKey)
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil, fmt.Errorf("%w (%s)", ErrFunctionDoesNotExist, stmt.fnCall.fn)
+	err = tx.catalog.deleteTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
 }
 
-func (stmt *FnDataSourceStmt) resolveListDatabases(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
-	if len(stmt.fnCall.params) > 0 {
-		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, DatabasesFnCall, len(stmt.fnCall.params))
+// CreateViewStmt represents a statement to store a named query in the catalog
+type CreateViewStmt struct {
+	view        string
+	ifNotExists bool
+	cols        []string
+	query       string
+}
+
+func NewCreateViewStmt(view string, ifNotExists bool, cols []string, query string) *CreateViewStmt {
+	return &CreateViewStmt{view: view, ifNotExists: ifNotExists, cols: cols, query: query}
+}
+
+func (stmt *CreateViewStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *CreateViewStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeCreate}
+}
+
+func (stmt *CreateViewStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *CreateViewStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	if stmt.ifNotExists && tx.catalog.ExistView(stmt.view) {
+		return tx, nil
 	}
 
-	cols := make([]ColDescriptor, 1)
-	cols[0] = ColDescriptor{
-		Column: "name",
-		Type:   VarcharType,
+	ds, err := parseViewQuery(stmt.view, stmt.query)
+	if err != nil {
+		return nil, err
 	}
 
-	var dbs []string
+	inferredParams := make(map[string]SQLValueType)
 
-	if tx.engine.multidbHandler == nil {
-		return nil, ErrUnspecifiedMultiDBHandler
-	} else {
-		dbs, err = tx.engine.multidbHandler.ListDatabases(ctx)
-		if err != nil {
-			return nil, err
-		}
+	err = ds.inferParameters(ctx, tx, inferredParams)
+	if err != nil {
+		return nil, err
 	}
 
-	values := make([][]ValueExp, len(dbs))
+	if len(inferredParams) > 0 {
+		return nil, fmt.Errorf("%w: view %s can not be parametrized", ErrIllegalArguments, stmt.view)
+	}
 
-	for i, db := range dbs {
-		values[i] = []ValueExp{&Varchar{val: db}}
+	// the query is resolved before the view is added to the catalog,
+	// so referenced tables and views must exist and a view can not reference itself
+	r, err := (&cteRef{cte: NewCTE(stmt.view, stmt.cols, ds)}).Resolve(ctx, tx, nil, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
-}
+	_, err = r.Columns(ctx)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
 
-func (stmt *FnDataSourceStmt) resolveListTables(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
-	if len(stmt.fnCall.params) > 0 {
-		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, TablesFnCall, len(stmt.fnCall.params))
+	view, err := tx.catalog.newView(stmt.view, stmt.cols, stmt.query)
+	if err != nil {
+		return nil, err
 	}
 
-	cols := make([]ColDescriptor, 1)
-	cols[0] = ColDescriptor{
-		Column: "name",
-		Type:   VarcharType,
+	err = persistView(tx, view)
+	if err != nil {
+		return nil, err
 	}
 
-	tables := tx.catalog.GetTables()
+	tx.mutatedCatalog = true
 
-	values := make([][]ValueExp, len(tables))
+	return tx, nil
+}
 
-	for i, t := range tables {
-		values[i] = []ValueExp{&Varchar{val: t.name}}
+func parseViewQuery(view, query string) (DataSource, error) {
+	stmts, err := ParseSQLString(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingError, err)
 	}
 
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+	if len(stmts) == 1 {
+		ds, ok := stmts[0].(DataSource)
+		if ok {
+			return ds, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: view %s", ErrExpectingDQLStmt, view)
 }
 
-func (stmt *FnDataSourceStmt) resolveShowTable(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
-	cols := []ColDescriptor{
-		{
-			Column: "column_name",
-			Type:   VarcharType,
-		},
-		{
-			Column: "type_name",
-			Type:   VarcharType,
-		},
+// viewDependencies holds the tables and views referenced by the query of a view,
+// along with the columns it may reference
+type viewDependencies struct {
+	aliases    map[string][]string // names under which each table or view is referenced
+	cols       []*ColSelector
+	selectsAll bool // true when all the columns of a data source are selected, i.e. SELECT *
+}
+
+func newViewDependencies(ds DataSource) *viewDependencies {
+	deps := &viewDependencies{aliases: make(map[string][]string)}
+	deps.addDataSource(ds, make(map[*CTE]bool))
+	return deps
+}
+
+func (deps *viewDependencies) addDataSource(ds DataSource, visited map[*CTE]bool) {
+	switch s := ds.(type) {
+	case *tableRef:
+		deps.aliases[s.table] = append(deps.aliases[s.table], s.Alias())
+	case *cteRef:
+		if !visited[s.cte] {
+			visited[s.cte] = true
+			deps.addDataSource(s.cte.q, visited)
+		}
+	case *SelectStmt:
 		{
-			Column: "is_nullable",
-			Type:   BooleanType,
-		},
+			deps.selectsAll = deps.selectsAll || len(s.targets) == 0
+
+			deps.addDataSource(s.ds, visited)
+
+			for _, jspec := range s.joins {
+				deps.addDataSource(jspec.ds, visited)
+				deps.addExp(jspec.cond, visited)
+			}
+
+			for _, t := range s.targets {
+				deps.addExp(t.Exp, visited)
+			}
+
+			for _, sel := range s.groupBy {
+				deps.cols = append(deps.cols, sel)
+			}
+
+			for _, oe := range s.orderBy {
+				deps.addExp(oe.exp, visited)
+			}
+
+			deps.addExp(s.where, visited)
+			deps.addExp(s.having, visited)
+		}
+	case *UnionStmt:
 		{
-			Column: "is_indexed",
-			Type:   VarcharType,
-		},
+			deps.addDataSource(s.left, visited)
+			deps.addDataSource(s.right, visited)
+		}
+	case *SetOpStmt:
 		{
-			Column: "is_auto_increment",
-			Type:   BooleanType,
-		},
+			deps.addDataSource(s.left, visited)
+			deps.addDataSource(s.right, visited)
+		}
+	case *WithStmt:
 		{
-			Column: "is_unique",
-			Type:   BooleanType,
-		},
+			for _, cte := range s.ctes {
+				deps.addDataSource(&cteRef{cte: cte}, visited)
+			}
+			deps.addDataSource(s.q, visited)
+		}
 	}
+}
 
-	tableName, _ := stmt.fnCall.params[0].reduce(tx, nil, "")
-	table, err := tx.catalog.GetTableByName(tableName.RawValue().(string))
-	if err != nil {
-		return nil, err
-	}
+func (deps *viewDependencies) addExp(exp ValueExp, visited map[*CTE]bool) {
+	visitExp(exp, func(e ValueExp) bool {
+		switch se := e.(type) {
+		case *ColSelector:
+			deps.cols = append(deps.cols, se)
+		case *AggColSelector:
+			deps.cols = append(deps.cols, &ColSelector{table: se.table, col: se.col})
+		case *ExistsBoolExp:
+			deps.addDataSource(se.q, visited)
+		case *InSubQueryExp:
+			deps.addDataSource(se.q, visited)
+		}
+		return true
+	})
+}
 
-	values := make([][]ValueExp, len(table.cols))
+// references returns true if the given table or view is referenced by the query
+func (deps *viewDependencies) references(name string) bool {
+	_, ok := deps.aliases[name]
+	return ok
+}
 
-	for i, c := range table.cols {
-		index := "NO"
+// referencesColumn returns true if the query may reference the given column of the table.
+// Selectors are not resolved, so a column is conservatively considered as referenced when
+// the query selects all the columns of any data source or when it includes a selector with
+// the same name, either unqualified or qualified by one of the names under which the table is referenced
+func (deps *viewDependencies) referencesColumn(table, col string) bool {
+	aliases, ok := deps.aliases[table]
+	if !ok {
+		return false
+	}
 
-		indexed, err := table.IsIndexed(c.Name())
-		if err != nil {
-			return nil, err
-		}
-		if indexed {
-			index = "YES"
+	if deps.selectsAll {
+		return true
+	}
+
+	for _, sel := range deps.cols {
+		if sel.col != col {
+			continue
 		}
 
-		if table.PrimaryIndex().IncludesCol(c.ID()) {
-			index = "PRIMARY KEY"
+		if sel.table == "" {
+			return true
 		}
 
-		var unique bool
-		for _, index := range table.GetIndexesByColID(c.ID()) {
-			if index.IsUnique() && len(index.Cols()) == 1 {
-				unique = true
-				break
+		for _, alias := range aliases {
+			if sel.table == alias {
+				return true
 			}
 		}
+	}
+	return false
+}
 
-		var maxLen string
+func persistView(tx *SQLTx, view *View) error {
+	if len(view.name) > 256 {
+		return fmt.Errorf("view name len: %w", ErrMaxLengthExceeded)
+	}
 
-		if c.MaxLen() > 0 && (c.Type() == VarcharType || c.Type() == BLOBType) {
-			maxLen = fmt.Sprintf("(%d)", c.MaxLen())
-		}
+	if len(view.cols) > math.MaxUint8 {
+		return fmt.Errorf("view columns: %w", ErrMaxLengthExceeded)
+	}
 
-		values[i] = []ValueExp{
-			&Varchar{val: c.colName},
-			&Varchar{val: c.Type() + maxLen},
-			&Bool{val: c.IsNullable()},
-			&Varchar{val: index},
-			&Bool{val: c.IsAutoIncremental()},
-			&Bool{val: unique},
+	//{nameLen-1}{name}{colCount}({colNameLen-1}{colName})*{query}
+	val := make([]byte, 0, 2+len(view.name)+len(view.query))
+
+	val = append(val, byte(len(view.name)-1))
+	val = append(val, []byte(view.name)...)
+	val = append(val, byte(len(view.cols)))
+
+	for _, col := range view.cols {
+		if len(col) > 256 {
+			return fmt.Errorf("view column name len: %w", ErrMaxLengthExceeded)
 		}
+
+		val = append(val, byte(len(col)-1))
+		val = append(val, []byte(col)...)
 	}
 
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+	val = append(val, []byte(view.query)...)
+
+	mappedKey := MapKey(tx.sqlPrefix(), catalogViewPrefix, EncodeID(DatabaseID), EncodeID(view.id))
+
+	return tx.set(mappedKey, nil, val)
 }
 
-func (stmt *FnDataSourceStmt) resolveListUsers(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (rowReader RowReader, err error) {
-	if len(stmt.fnCall.params) > 0 {
-		return nil, fmt.Errorf("%w: function '%s' expect no parameters but %d were provided", ErrIllegalArguments, UsersFnCall, len(stmt.fnCall.params))
-	}
+// DropViewStmt represents a statement to delete a view.
+type DropViewStmt struct {
+	view string
+}
 
-	cols := []ColDescriptor{
-		{
-			Column: "name",
-			Type:   VarcharType,
-		},
-		{
-			Column: "permission",
-			Type:   VarcharType,
-		},
-	}
+func NewDropViewStmt(view string) *DropViewStmt {
+	return &DropViewStmt{view: view}
+}
 
-	users, err := tx.ListUsers(ctx)
-	if err != nil {
-		return nil, err
-	}
+func (stmt *DropViewStmt) readOnly() bool {
+	return false
+}
 
-	values := make([][]ValueExp, len(users))
-	for i, user := range users {
-		perm := user.Permission()
+func (stmt *DropViewStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeDrop}
+}
 
-		values[i] = []ValueExp{
-			&Varchar{val: user.Username()},
-			&Varchar{val: perm},
-		}
-	}
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+func (stmt *DropViewStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
 }
 
-func (stmt *FnDataSourceStmt) resolveListColumns(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
-	if len(stmt.fnCall.params) != 1 {
-		return nil, fmt.Errorf("%w: function '%s' expect table name as parameter", ErrIllegalArguments, ColumnsFnCall)
+func (stmt *DropViewStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	view, err := tx.catalog.GetViewByName(stmt.view)
+	if err != nil {
+		return nil, err
 	}
 
-	cols := []ColDescriptor{
-		{
-			Column: "table",
-			Type:   VarcharType,
-		},
-		{
-			Column: "name",
-			Type:   VarcharType,
-		},
-		{
-			Column: "type",
-			Type:   VarcharType,
-		},
-		{
-			Column: "max_length",
-			Type:   IntegerType,
-		},
-		{
-			Column: "nullable",
-			Type:   BooleanType,
-		},
-		{
-			Column: "auto_increment",
-			Type:   BooleanType,
-		},
-		{
-			Column: "indexed",
-			Type:   BooleanType,
-		},
-		{
-			Column: "primary",
-			Type:   BooleanType,
-		},
-		{
-			Column: "unique",
-			Type:   BooleanType,
-		},
+	if dependent := tx.catalog.dependentView(view.name); dependent != nil {
+		return nil, fmt.Errorf("%w %s because view %s requires it", ErrCannotDropView, view.name, dependent.name)
 	}
 
-	val, err := stmt.fnCall.params[0].substitute(params)
+	mappedKey := MapKey(tx.sqlPrefix(), catalogViewPrefix, EncodeID(DatabaseID), EncodeID(view.id))
+
+	err = tx.delete(ctx, mappedKey)
 	if err != nil {
 		return nil, err
 	}
 
-	tableName, err := val.reduce(tx, nil, "")
+	err = tx.catalog.deleteView(view)
 	if err != nil {
 		return nil, err
 	}
 
-	if tableName.Type() != VarcharType {
-		return nil, fmt.Errorf("%w: expected '%s' for table name but type '%s' given instead", ErrIllegalArguments, VarcharType, tableName.Type())
-	}
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+// sequenceOption is an option specified when creating a sequence, e.g. INCREMENT BY 2
+type sequenceOption struct {
+	name     string
+	value    int64
+	hasValue bool
+}
+
+// CreateSequenceStmt represents a statement to create a sequence.
+type CreateSequenceStmt struct {
+	sequence    string
+	ifNotExists bool
+	start       int64
+	increment   int64
+	minValue    int64
+	maxValue    int64
+	cycle       bool
+}
+
+func NewCreateSequenceStmt(sequence string, ifNotExists bool, start, increment, minValue, maxValue int64, cycle bool) *CreateSequenceStmt {
+	return &CreateSequenceStmt{
+		sequence:    sequence,
+		ifNotExists: ifNotExists,
+		start:       start,
+		increment:   increment,
+		minValue:    minValue,
+		maxValue:    maxValue,
+		cycle:       cycle,
+	}
+}
+
+// newCreateSequenceStmt builds the statement out of the specified options.
+// As in PostgreSQL, ascending sequences start at their minimum value, which defaults to 1,
+// while descending ones start at their maximum value, which defaults to -1.
+func newCreateSequenceStmt(sequence string, ifNotExists bool, opts []sequenceOption) (*CreateSequenceStmt, error) {
+	specified := make(map[string]int64, len(opts))
+	cycle := false
+
+	for _, opt := range opts {
+		switch opt.name {
+		case "start", "increment", "minvalue", "maxvalue":
+			if !opt.hasValue {
+				return nil, fmt.Errorf("syntax error: missing value for sequence option %s", strings.ToUpper(opt.name))
+			}
+		case "cycle":
+			if opt.hasValue {
+				return nil, fmt.Errorf("syntax error: unexpected value for sequence option CYCLE")
+			}
+			cycle = true
+		default:
+			return nil, fmt.Errorf("syntax error: unexpected %s, expecting START or INCREMENT or MINVALUE or MAXVALUE or CYCLE", opt.name)
+		}
 
-	table, err := tx.catalog.GetTableByName(tableName.RawValue().(string))
-	if err != nil {
-		return nil, err
+		if _, ok := specified[opt.name]; ok {
+			return nil, fmt.Errorf("syntax error: conflicting or redundant sequence option %s", strings.ToUpper(opt.name))
+		}
+		specified[opt.name] = opt.value
 	}
 
-	values := make([][]ValueExp, len(table.cols))
+	increment, ok := specified["increment"]
+	if !ok {
+		increment = 1
+	}
 
-	for i, c := range table.cols {
-		indexed, err := table.IsIndexed(c.Name())
-		if err != nil {
-			return nil, err
+	minValue, ok := specified["minvalue"]
+	if !ok {
+		minValue = 1
+		if increment < 0 {
+			minValue = math.MinInt64
 		}
+	}
 
-		var unique bool
-		for _, index := range table.indexesByColID[c.id] {
-			if index.IsUnique() && len(index.Cols()) == 1 {
-				unique = true
-				break
-			}
+	maxValue, ok := specified["maxvalue"]
+	if !ok {
+		maxValue = math.MaxInt64
+		if increment < 0 {
+			maxValue = -1
 		}
+	}
 
-		values[i] = []ValueExp{
-			&Varchar{val: table.name},
-			&Varchar{val: c.colName},
-			&Varchar{val: c.colType},
-			&Integer{val: int64(c.MaxLen())},
-			&Bool{val: c.IsNullable()},
-			&Bool{val: c.autoIncrement},
-			&Bool{val: indexed},
-			&Bool{val: table.PrimaryIndex().IncludesCol(c.ID())},
-			&Bool{val: unique},
+	start, ok := specified["start"]
+	if !ok {
+		start = minValue
+		if increment < 0 {
+			start = maxValue
 		}
 	}
 
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+	return NewCreateSequenceStmt(sequence, ifNotExists, start, increment, minValue, maxValue, cycle), nil
 }
 
-func (stmt *FnDataSourceStmt) resolveListIndexes(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
-	if len(stmt.fnCall.params) != 1 {
-		return nil, fmt.Errorf("%w: function '%s' expect table name as parameter", ErrIllegalArguments, IndexesFnCall)
-	}
+func (stmt *CreateSequenceStmt) readOnly() bool {
+	return false
+}
 
-	cols := []ColDescriptor{
-		{
-			Column: "table",
-			Type:   VarcharType,
-		},
-		{
-			Column: "name",
-			Type:   VarcharType,
-		},
-		{
-			Column: "unique",
-			Type:   BooleanType,
-		},
-		{
-			Column: "primary",
-			Type:   BooleanType,
-		},
+func (stmt *CreateSequenceStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeCreate}
+}
+
+func (stmt *CreateSequenceStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *CreateSequenceStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	if stmt.ifNotExists && tx.catalog.ExistSequence(stmt.sequence) {
+		return tx, nil
 	}
 
-	val, err := stmt.fnCall.params[0].substitute(params)
+	seq, err := tx.catalog.newSequence(stmt.sequence, stmt.start, stmt.increment, stmt.minValue, stmt.maxValue, stmt.cycle)
 	if err != nil {
 		return nil, err
 	}
 
-	tableName, err := val.reduce(tx, nil, "")
+	err = persistSequence(tx, seq)
 	if err != nil {
 		return nil, err
 	}
 
-	if tableName.Type() != VarcharType {
-		return nil, fmt.Errorf("%w: expected '%s' for table name but type '%s' given instead", ErrIllegalArguments, VarcharType, tableName.Type())
-	}
+	tx.mutatedCatalog = true
 
-	table, err := tx.catalog.GetTableByName(tableName.RawValue().(string))
-	if err != nil {
-		return nil, err
+	return tx, nil
+}
+
+func persistSequence(tx *SQLTx, seq *Sequence) error {
+	if len(seq.name) > 256 {
+		return fmt.Errorf("sequence name len: %w", ErrMaxLengthExceeded)
 	}
 
-	values := make([][]ValueExp, len(table.indexes))
+	//{nameLen-1}{name}{start}{increment}{minValue}{maxValue}{cycle}
+	val := make([]byte, 1+len(seq.name)+4*8+1)
 
-	for i, index := range table.indexes {
-		values[i] = []ValueExp{
-			&Varchar{val: table.name},
-			&Varchar{val: index.Name()},
-			&Bool{val: index.unique},
-			&Bool{val: index.IsPrimary()},
-		}
+	i := 0
+
+	val[i] = byte(len(seq.name) - 1)
+	i++
+
+	copy(val[i:], seq.name)
+	i += len(seq.name)
+
+	for _, v := range []int64{seq.start, seq.increment, seq.minValue, seq.maxValue} {
+		binary.BigEndian.PutUint64(val[i:], uint64(v))
+		i += 8
 	}
 
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+	if seq.cycle {
+		val[i] = 1
+	}
+
+	mappedKey := MapKey(tx.sqlPrefix(), catalogSequencePrefix, EncodeID(DatabaseID), EncodeID(seq.id))
+
+	return tx.set(mappedKey, nil, val)
 }
 
-func (stmt *FnDataSourceStmt) resolveListGrants(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (RowReader, error) {
-	if len(stmt.fnCall.params) > 1 {
-		return nil, fmt.Errorf("%w: function '%s' expect at most one parameter of type %s", ErrIllegalArguments, GrantsFnCall, VarcharType)
-	}
+func sequenceValueKey(tx *SQLTx, seq *Sequence) []byte {
+	return MapKey(tx.sqlPrefix(), catalogSeqValuePrefix, EncodeID(DatabaseID), EncodeID(seq.id))
+}
 
-	var username string
-	if len(stmt.fnCall.params) == 1 {
-		val, err := stmt.fnCall.params[0].substitute(params)
-		if err != nil {
-			return nil, err
-		}
+// lastSequenceValue returns the last value generated by the sequence as seen by the transaction,
+// ErrSequenceNotUsed is returned when no value was generated yet.
+func (tx *SQLTx) lastSequenceValue(ctx context.Context, seq *Sequence) (int64, error) {
+	vref, err := tx.get(ctx, sequenceValueKey(tx, seq))
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return 0, fmt.Errorf("%w (%s)", ErrSequenceNotUsed, seq.name)
+	}
+	if err != nil {
+		return 0, err
+	}
 
-		userVal, err := val.reduce(tx, nil, "")
-		if err != nil {
-			return nil, err
-		}
+	v, err := vref.Resolve()
+	if err != nil {
+		return 0, err
+	}
 
-		if userVal.Type() != VarcharType {
-			return nil, fmt.Errorf("%w: expected '%s' for username but type '%s' given instead", ErrIllegalArguments, VarcharType, userVal.Type())
-		}
-		username, _ = userVal.RawValue().(string)
+	if len(v) != 8 {
+		return 0, ErrCorruptedData
 	}
+	return int64(binary.BigEndian.Uint64(v)), nil
+}
 
-	cols := []ColDescriptor{
-		{
-			Column: "user",
-			Type:   VarcharType,
-		},
-		{
-			Column: "privilege",
-			Type:   VarcharType,
-		},
+// nextSequenceValue advances the sequence within the transaction. As the last generated value is
+// read and written by the transaction, concurrent transactions using the same sequence conflict
+// with each other, so values are only consumed by committed transactions.
+func (tx *SQLTx) nextSequenceValue(ctx context.Context, seq *Sequence) (int64, error) {
+	last, err := tx.lastSequenceValue(ctx, seq)
+	if err != nil && !errors.Is(err, ErrSequenceNotUsed) {
+		return 0, err
 	}
 
-	var err error
-	var users []User
+	next := seq.start
 
-	if tx.engine.multidbHandler == nil {
-		return nil, ErrUnspecifiedMultiDBHandler
-	} else {
-		users, err = tx.engine.multidbHandler.ListUsers(ctx)
+	if err == nil {
+		next, err = seq.next(last)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 	}
 
-	values := make([][]ValueExp, 0, len(users))
+	var val [8]byte
+	binary.BigEndian.PutUint64(val[:], uint64(next))
 
-	for _, user := range users {
-		if username == "" || user.Username() == username {
-			for _, p := range user.SQLPrivileges() {
-				values = append(values, []ValueExp{
-					&Varchar{val: user.Username()},
-					&Varchar{val: string(p)},
-				})
-			}
-		}
+	err = tx.set(sequenceValueKey(tx, seq), nil, val[:])
+	if err != nil {
+		return 0, err
 	}
-
-	return NewValuesRowReader(tx, params, cols, true, stmt.Alias(), values)
+	return next, nil
 }
 
-// DropTableStmt represents a statement to delete a table.
-type DropTableStmt struct {
-	table string
+// DropSequenceStmt represents a statement to delete a sequence.
+type DropSequenceStmt struct {
+	sequence string
 }
 
-func NewDropTableStmt(table string) *DropTableStmt {
-	return &DropTableStmt{table: table}
+func NewDropSequenceStmt(sequence string) *DropSequenceStmt {
+	return &DropSequenceStmt{sequence: sequence}
 }
 
-func (stmt *DropTableStmt) readOnly() bool {
+func (stmt *DropSequenceStmt) readOnly() bool {
 	return false
 }
 
-func (stmt *DropTableStmt) requiredPrivileges() []SQLPrivilege {
+func (stmt *DropSequenceStmt) requiredPrivileges() []SQLPrivilege {
 	return []SQLPrivilege{SQLPrivilegeDrop}
 }
 
-func (stmt *DropTableStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+func (stmt *DropSequenceStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
 	return nil
 }
 
-/*
-Exec executes the delete table statement.
-It the table exists, if not it does nothing.
-If the table exists, it deletes all the indexes and the table itself.
-Note that this is a soft delete of the index and table key,
-the data is not deleted, but the metadata is updated.
-*/
-func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
-	if !tx.catalog.ExistTable(stmt.table) {
-		return nil, ErrTableDoesNotExist
-	}
-
-	table, err := tx.catalog.GetTableByName(stmt.table)
-	if err != nil {
-		return nil, err
-	}
-
-	// delete table
-	mappedKey := MapKey(
-		tx.sqlPrefix(),
-		catalogTablePrefix,
-		EncodeID(DatabaseID),
-		EncodeID(table.id),
-	)
-	err = tx.delete(ctx, mappedKey)
+func (stmt *DropSequenceStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	seq, err := tx.catalog.GetSequenceByName(stmt.sequence)
 	if err != nil {
 		return nil, err
 	}
 
-	// delete columns
-	cols := table.ColumnsByID()
-	for _, col := range cols {
-		mappedKey := MapKey(
-			tx.sqlPrefix(),
-			catalogColumnPrefix,
-			EncodeID(DatabaseID),
-			EncodeID(col.table.id),
-			EncodeID(col.id),
-			[]byte(col.colType),
-		)
-		err = tx.delete(ctx, mappedKey)
-		if err != nil {
-			return nil, err
+	for _, table := range tx.catalog.tables {
+		for _, col := range table.cols {
+			if usesSequence(col.defaultExp, seq.name) {
+				return nil, fmt.Errorf("%w: sequence %s is used by column %s.%s", ErrIllegalArguments, seq.name, table.name, col.colName)
+			}
 		}
 	}
 
-	// delete checks
-	for name := range table.checkConstraints {
-		key := MapKey(
-			tx.sqlPrefix(),
-			catalogCheckPrefix,
-			EncodeID(DatabaseID),
-			EncodeID(table.id),
-			[]byte(name),
-		)
+	mappedKey := MapKey(tx.sqlPrefix(), catalogSequencePrefix, EncodeID(DatabaseID), EncodeID(seq.id))
 
-		if err := tx.delete(ctx, key); err != nil {
-			return nil, err
-		}
+	err = tx.delete(ctx, mappedKey)
+	if err != nil {
+		return nil, err
 	}
 
-	// delete indexes
-	for _, index := range table.indexes {
-		mappedKey := MapKey(
-			tx.sqlPrefix(),
-			catalogIndexPrefix,
-			EncodeID(DatabaseID),
-			EncodeID(table.id),
-			EncodeID(index.id),
-		)
-		err = tx.delete(ctx, mappedKey)
-		if err != nil {
-			return nil, err
-		}
-
-		indexKey := MapKey(
-			tx.sqlPrefix(),
-			MappedPrefix,
-			EncodeID(table.id),
-			EncodeID(index.id),
-		)
-		err = tx.addOnCommittedCallback(func(sqlTx *SQLTx) error {
-			return sqlTx.engine.store.DeleteIndex(indexKey)
-		})
-		if err != nil {
-			return nil, err
-		}
+	err = tx.delete(ctx, sequenceValueKey(tx, seq))
+	if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+		return nil, err
 	}
 
-	err = tx.catalog.deleteTable(table)
+	err = tx.catalog.deleteSequence(seq)
 	if err != nil {
 		return nil, err
 	}
@@ -5833,8 +9823,9 @@ func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 
 // DropIndexStmt represents a statement to delete a table.
 type DropIndexStmt struct {
-	table string
-	cols  []string
+	table      string
+	cols       []string
+	jsonFields [][]string
 }
 
 func NewDropIndexStmt(table string, cols []string) *DropIndexStmt {
@@ -5871,7 +9862,7 @@ func (stmt *DropIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 	cols := make([]*Column, len(stmt.cols))
 
 	for i, colName := range stmt.cols {
-		col, err := table.GetColumnByName(colName)
+		col, err := table.indexColumn(colName, jsonFieldsAt(stmt.jsonFields, i))
 		if err != nil {
 			return nil, err
 		}
@@ -5884,6 +9875,12 @@ func (stmt *DropIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		return nil, err
 	}
 
+	for _, fk := range tx.catalog.referencingForeignKeys(table) {
+		if fk.refIndex == index {
+			return nil, fmt.Errorf("%w %s because %s constraint of table %s requires it", ErrCannotDropIndex, index.Name(), fk.name, fk.table.name)
+		}
+	}
+
 	// delete index
 	mappedKey := MapKey(
 		tx.sqlPrefix(),
@@ -155,11 +155,11 @@ func TestRequiresTypeColSelectorsValueExp(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		err := tc.exp.requiresType(tc.requiredType, tc.cols, tc.params, tc.implicitTable)
+		err := tc.exp.requiresType(nil, tc.requiredType, tc.cols, tc.params, tc.implicitTable)
 		require.ErrorIs(t, err, tc.expectedError, fmt.Sprintf("failed on iteration %d", i))
 
 		if tc.expectedError == nil {
-			it, err := tc.exp.inferType(tc.cols, params, tc.implicitTable)
+			it, err := tc.exp.inferType(nil, tc.cols, params, tc.implicitTable)
 			require.NoError(t, err)
 			require.Equal(t, tc.requiredType, it)
 		}
@@ -244,11 +244,11 @@ func TestRequiresTypeNumExpValueExp(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		err := tc.exp.requiresType(tc.requiredType, tc.cols, tc.params, tc.implicitTable)
+		err := tc.exp.requiresType(nil, tc.requiredType, tc.cols, tc.params, tc.implicitTable)
 		require.ErrorIs(t, err, tc.expectedError, fmt.Sprintf("failed on iteration %d", i))
 
 		if tc.expectedError == nil {
-			it, err := tc.exp.inferType(tc.cols, params, tc.implicitTable)
+			it, err := tc.exp.inferType(nil, tc.cols, params, tc.implicitTable)
 			require.NoError(t, err)
 			require.Equal(t, tc.requiredType, it)
 		}
@@ -512,7 +512,7 @@ func TestRequiresTypeSimpleValueExp(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		err := tc.exp.requiresType(tc.requiredType, tc.cols, tc.params, tc.implicitTable)
+		err := tc.exp.requiresType(nil, tc.requiredType, tc.cols, tc.params, tc.implicitTable)
 		require.ErrorIs(t, err, tc.expectedError, fmt.Sprintf("failed on iteration %d", i))
 
 		if tc.expectedError == nil {
@@ -521,7 +521,7 @@ func TestRequiresTypeSimpleValueExp(t *testing.T) {
 				expectedInferredType = tc.requiredType
 			}
 
-			it, err := tc.exp.inferType(tc.cols, params, tc.implicitTable)
+			it, err := tc.exp.inferType(nil, tc.cols, params, tc.implicitTable)
 			require.NoError(t, err)
 			require.Equal(t, expectedInferredType, it)
 		}
@@ -686,11 +686,11 @@ func TestRequiresTypeSysFnValueExp(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		err := tc.exp.requiresType(tc.requiredType, tc.cols, tc.params, tc.implicitTable)
+		err := tc.exp.requiresType(nil, tc.requiredType, tc.cols, tc.params, tc.implicitTable)
 		require.ErrorIs(t, err, tc.expectedError, fmt.Sprintf("failed on iteration %d", i))
 
 		if tc.expectedError == nil {
-			it, err := tc.exp.inferType(tc.cols, params, tc.implicitTable)
+			it, err := tc.exp.inferType(nil, tc.cols, params, tc.implicitTable)
 			require.NoError(t, err)
 			require.Equal(t, tc.requiredType, it)
 		}
@@ -783,61 +783,74 @@ func TestRequiresTypeBinValueExp(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		err := tc.exp.requiresType(tc.requiredType, tc.cols, tc.params, tc.implicitTable)
+		err := tc.exp.requiresType(nil, tc.requiredType, tc.cols, tc.params, tc.implicitTable)
 		require.ErrorIs(t, err, tc.expectedError, fmt.Sprintf("failed on iteration %d", i))
 
 		if tc.expectedError == nil {
-			it, err := tc.exp.inferType(tc.cols, params, tc.implicitTable)
+			it, err := tc.exp.inferType(nil, tc.cols, params, tc.implicitTable)
 			require.NoError(t, err)
 			require.Equal(t, tc.requiredType, it)
 		}
 	}
 }
 
-func TestYetUnsupportedExistsBoolExp(t *testing.T) {
+func TestExistsBoolExpEdgeCases(t *testing.T) {
 	exp := &ExistsBoolExp{}
 
-	_, err := exp.inferType(nil, nil, "")
-	require.Error(t, err)
+	it, err := exp.inferType(nil, nil, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, BooleanType, it)
 
-	err = exp.requiresType(BooleanType, nil, nil, "")
-	require.Error(t, err)
+	err = exp.requiresType(nil, BooleanType, nil, nil, "")
+	require.NoError(t, err)
 
-	rexp, err := exp.substitute(nil)
+	err = exp.requiresType(nil, IntegerType, nil, nil, "")
+	require.ErrorIs(t, err, ErrInvalidTypes)
+
+	params := map[string]interface{}{"param1": 1}
+
+	rexp, err := exp.substitute(params)
 	require.NoError(t, err)
-	require.Equal(t, exp, rexp)
+	require.Equal(t, &ExistsBoolExp{params: params}, rexp)
 
 	_, err = exp.reduce(nil, nil, "")
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrIllegalArguments)
 
 	require.Equal(t, exp, exp.reduceSelectors(nil, ""))
 
 	require.False(t, exp.isConstant())
 
-	require.Nil(t, exp.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, exp.selectorRanges(nil, nil, "", nil, nil))
 }
 
-func TestYetUnsupportedInSubQueryExp(t *testing.T) {
-	exp := &InSubQueryExp{}
+func TestInSubQueryExpEdgeCases(t *testing.T) {
+	exp := &InSubQueryExp{val: &ColSelector{col: "col"}}
 
-	_, err := exp.inferType(nil, nil, "")
-	require.ErrorIs(t, err, ErrNoSupported)
+	it, err := exp.inferType(nil, map[string]ColDescriptor{"(table1.col)": {Type: IntegerType}}, nil, "table1")
+	require.NoError(t, err)
+	require.Equal(t, BooleanType, it)
+
+	_, err = exp.inferType(nil, nil, nil, "table1")
+	require.ErrorIs(t, err, ErrColumnDoesNotExist)
 
-	err = exp.requiresType(BooleanType, nil, nil, "")
-	require.ErrorIs(t, err, ErrNoSupported)
+	err = exp.requiresType(nil, IntegerType, map[string]ColDescriptor{"(table1.col)": {Type: IntegerType}}, nil, "table1")
+	require.ErrorIs(t, err, ErrInvalidTypes)
 
 	rexp, err := exp.substitute(nil)
 	require.NoError(t, err)
-	require.Equal(t, exp, rexp)
+	require.Equal(t, exp.val, rexp.(*InSubQueryExp).val)
+	require.NotNil(t, rexp.(*InSubQueryExp).cache)
 
 	_, err = exp.reduce(nil, nil, "")
-	require.ErrorIs(t, err, ErrNoSupported)
+	require.ErrorIs(t, err, ErrIllegalArguments)
 
 	require.Equal(t, exp, exp.reduceSelectors(nil, ""))
 
 	require.False(t, exp.isConstant())
 
-	require.Nil(t, exp.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, exp.selectorRanges(nil, nil, "", nil, nil))
+
+	require.Equal(t, "(col IN (SELECT ...))", exp.String())
 }
 
 func TestCaseWhenExp(t *testing.T) {
@@ -847,7 +860,7 @@ func TestCaseWhenExp(t *testing.T) {
 		)
 		require.NoError(t, err)
 
-		err = e.requiresType(BooleanType, map[string]ColDescriptor{
+		err = e.requiresType(nil, BooleanType, map[string]ColDescriptor{
 			EncodeSelector("", "", "job_title"): {Type: VarcharType},
 		}, nil, "")
 		require.ErrorIs(t, err, ErrInvalidTypes)
@@ -879,13 +892,13 @@ func TestCaseWhenExp(t *testing.T) {
 		e, err = e.substitute(map[string]interface{}{"p0": int64(0), "p1": int64(1)})
 		require.NoError(t, err)
 
-		err = e.requiresType(IntegerType, map[string]ColDescriptor{
+		err = e.requiresType(nil, IntegerType, map[string]ColDescriptor{
 			EncodeSelector("", "", "salary"): {Type: IntegerType},
 		}, nil, "")
 		require.NoError(t, err)
 
 		require.False(t, e.isConstant())
-		require.Nil(t, e.selectorRanges(nil, "", nil, nil))
+		require.Nil(t, e.selectorRanges(nil, nil, "", nil, nil))
 
 		row := &Row{ValuesBySelector: map[string]TypedValue{EncodeSelector("", "", "salary"): &Integer{50000}}}
 		require.Equal(t,
@@ -912,6 +925,7 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 		require.NoError(t, err)
 
 		_, err = e.inferType(
+			nil,
 			map[string]ColDescriptor{
 				EncodeSelector("", "", "department"): {Type: IntegerType},
 			},
@@ -922,6 +936,7 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 		require.ErrorContains(t, err, "argument of CASE/WHEN must be of type INTEGER, not type VARCHAR")
 
 		it, err := e.inferType(
+			nil,
 			map[string]ColDescriptor{
 				EncodeSelector("", "", "department"): {Type: VarcharType},
 			},
@@ -939,6 +954,7 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 		require.NoError(t, err)
 
 		_, err = e.inferType(
+			nil,
 			map[string]ColDescriptor{
 				EncodeSelector("", "", "salary"): {Type: IntegerType},
 			},
@@ -953,6 +969,7 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 		require.NoError(t, err)
 
 		_, err = e.inferType(
+			nil,
 			map[string]ColDescriptor{
 				EncodeSelector("", "", "salary"): {Type: IntegerType},
 			},
@@ -967,6 +984,7 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 		require.NoError(t, err)
 
 		it, err := e.inferType(
+			nil,
 			map[string]ColDescriptor{
 				EncodeSelector("", "", "salary"): {Type: IntegerType},
 			},
@@ -977,6 +995,7 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 		require.Equal(t, IntegerType, it)
 
 		it, err = e.inferType(
+			nil,
 			map[string]ColDescriptor{
 				EncodeSelector("", "", "salary"): {Type: Float64Type},
 			},
@@ -991,10 +1010,10 @@ func TestInferTypeCaseWhenExp(t *testing.T) {
 func TestLikeBoolExpEdgeCases(t *testing.T) {
 	exp := &LikeBoolExp{}
 
-	_, err := exp.inferType(nil, nil, "")
+	_, err := exp.inferType(nil, nil, nil, "")
 	require.ErrorIs(t, err, ErrInvalidCondition)
 
-	err = exp.requiresType(BooleanType, nil, nil, "")
+	err = exp.requiresType(nil, BooleanType, nil, nil, "")
 	require.ErrorIs(t, err, ErrInvalidCondition)
 
 	_, err = exp.substitute(nil)
@@ -1005,15 +1024,15 @@ func TestLikeBoolExpEdgeCases(t *testing.T) {
 
 	require.Equal(t, exp, exp.reduceSelectors(nil, ""))
 	require.False(t, exp.isConstant())
-	require.Nil(t, exp.selectorRanges(nil, "", nil, nil))
+	require.Nil(t, exp.selectorRanges(nil, nil, "", nil, nil))
 
 	t.Run("like expression with invalid types", func(t *testing.T) {
 		exp := &LikeBoolExp{val: &ColSelector{col: "col1"}, pattern: &Integer{}}
 
-		_, err = exp.inferType(nil, nil, "")
+		_, err = exp.inferType(nil, nil, nil, "")
 		require.ErrorIs(t, err, ErrInvalidTypes)
 
-		err = exp.requiresType(BooleanType, nil, nil, "")
+		err = exp.requiresType(nil, BooleanType, nil, nil, "")
 		require.ErrorIs(t, err, ErrInvalidTypes)
 
 		v := &Integer{}
@@ -1115,7 +1134,9 @@ func TestIsConstant(t *testing.T) {
 		right: &ColSelector{},
 	}).isConstant())
 
-	require.False(t, (&FnCall{}).isConstant())
+	require.True(t, (&FnCall{fn: NowFnCall}).isConstant())
+	require.False(t, (&FnCall{fn: UUIDFnCall}).isConstant())
+	require.False(t, (&FnCall{fn: LengthFnCall, params: []ValueExp{&ColSelector{}}}).isConstant())
 
 	require.False(t, (&ExistsBoolExp{}).isConstant())
 }
@@ -1155,14 +1176,14 @@ func TestTimestamapType(t *testing.T) {
 		require.Equal(t, -1, cmp)
 	})
 
-	it, err := ts.inferType(map[string]ColDescriptor{}, map[string]string{}, "")
+	it, err := ts.inferType(nil, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.NoError(t, err)
 	require.Equal(t, TimestampType, it)
 
-	err = ts.requiresType(TimestampType, map[string]ColDescriptor{}, map[string]string{}, "")
+	err = ts.requiresType(nil, TimestampType, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.NoError(t, err)
 
-	err = ts.requiresType(IntegerType, map[string]ColDescriptor{}, map[string]string{}, "")
+	err = ts.requiresType(nil, IntegerType, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.ErrorIs(t, err, ErrInvalidTypes)
 
 	v, err := ts.substitute(map[string]interface{}{})
@@ -1172,7 +1193,7 @@ func TestTimestamapType(t *testing.T) {
 	v = ts.reduceSelectors(&Row{}, "")
 	require.Equal(t, ts, v)
 
-	err = ts.selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = ts.selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 }
 
@@ -1182,7 +1203,7 @@ func TestJSONType(t *testing.T) {
 	require.True(t, js.isConstant())
 	require.False(t, js.IsNull())
 
-	it, err := js.inferType(map[string]ColDescriptor{}, map[string]string{}, "")
+	it, err := js.inferType(nil, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.NoError(t, err)
 	require.Equal(t, JSONType, it)
 
@@ -1197,7 +1218,7 @@ func TestJSONType(t *testing.T) {
 	v = js.reduceSelectors(&Row{}, "")
 	require.Equal(t, js, v)
 
-	err = js.selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = js.selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 
 	t.Run("test comparison functions", func(t *testing.T) {
@@ -1499,14 +1520,14 @@ func TestFloat64Type(t *testing.T) {
 		require.Equal(t, -1, cmp)
 	})
 
-	it, err := ts.inferType(map[string]ColDescriptor{}, map[string]string{}, "")
+	it, err := ts.inferType(nil, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.NoError(t, err)
 	require.Equal(t, Float64Type, it)
 
-	err = ts.requiresType(Float64Type, map[string]ColDescriptor{}, map[string]string{}, "")
+	err = ts.requiresType(nil, Float64Type, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.NoError(t, err)
 
-	err = ts.requiresType(IntegerType, map[string]ColDescriptor{}, map[string]string{}, "")
+	err = ts.requiresType(nil, IntegerType, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.ErrorIs(t, err, ErrInvalidTypes)
 
 	v, err := ts.substitute(map[string]interface{}{})
@@ -1516,7 +1537,7 @@ func TestFloat64Type(t *testing.T) {
 	v = ts.reduceSelectors(&Row{}, "")
 	require.Equal(t, ts, v)
 
-	err = ts.selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = ts.selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 }
 
@@ -1545,10 +1566,10 @@ func TestUUIDType(t *testing.T) {
 		require.ErrorIs(t, err, ErrNotComparableValues)
 	})
 
-	err := id.requiresType(UUIDType, map[string]ColDescriptor{}, map[string]string{}, "")
+	err := id.requiresType(nil, UUIDType, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.NoError(t, err)
 
-	err = id.requiresType(IntegerType, map[string]ColDescriptor{}, map[string]string{}, "")
+	err = id.requiresType(nil, IntegerType, map[string]ColDescriptor{}, map[string]string{}, "")
 	require.ErrorIs(t, err, ErrInvalidTypes)
 
 	v, err := id.substitute(map[string]interface{}{})
@@ -1558,16 +1579,16 @@ func TestUUIDType(t *testing.T) {
 	v = id.reduceSelectors(&Row{}, "")
 	require.Equal(t, id, v)
 
-	err = id.selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = id.selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 
-	err = (&NullValue{}).selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = (&NullValue{}).selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 
-	err = (&Integer{}).selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = (&Integer{}).selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 
-	err = (&Varchar{}).selectorRanges(&Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
+	err = (&Varchar{}).selectorRanges(nil, &Table{}, "", map[string]interface{}{}, map[uint32]*typedValueRange{})
 	require.NoError(t, err)
 }
 
@@ -1630,6 +1651,11 @@ func TestRequiredPrivileges(t *testing.T) {
 			readOnly:   true,
 			privileges: []SQLPrivilege{SQLPrivilegeSelect},
 		},
+		{
+			stmt:       &SetOpStmt{},
+			readOnly:   true,
+			privileges: []SQLPrivilege{SQLPrivilegeSelect},
+		},
 		{
 			stmt:       &tableRef{},
 			readOnly:   true,
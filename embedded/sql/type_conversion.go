@@ -19,6 +19,7 @@ package sql
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -28,6 +29,44 @@ import (
 
 type converterFunc func(TypedValue) (TypedValue, error)
 
+// getExactConverter returns a converter which fails when a value can not be converted without losing
+// information, i.e. when converting it back does not yield the original value, e.g. FLOAT 1.5 as INTEGER.
+// Conversions which can not be reverted are not checked.
+func getExactConverter(src, dst SQLValueType) (converterFunc, error) {
+	conv, err := getConverter(src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	back, err := getConverter(dst, src)
+	if err != nil {
+		return conv, nil
+	}
+
+	return func(val TypedValue) (TypedValue, error) {
+		cval, err := conv(val)
+		if err != nil || val.IsNull() {
+			return cval, err
+		}
+
+		if !sameValue(back, cval, val) {
+			return nil, fmt.Errorf("%w: %s can not be converted exactly into %s", ErrInvalidValue, val.String(), dst)
+		}
+		return cval, nil
+	}, nil
+}
+
+// sameValue returns true if converting cval yields val
+func sameValue(conv converterFunc, cval, val TypedValue) bool {
+	v, err := conv(cval)
+	if err != nil {
+		return false
+	}
+
+	r, err := v.Compare(val)
+	return err == nil && r == 0
+}
+
 func getConverter(src, dst SQLValueType) (converterFunc, error) {
 	if src == dst {
 		if src == JSONType {
@@ -52,6 +91,75 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 		}, nil
 	}
 
+	if IsArrayType(dst) {
+		elemType := ArrayElemType(dst)
+
+		if src == VarcharType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: dst}, nil
+				}
+
+				arr, err := newArrayFromText(val.RawValue().(string), elemType)
+				if err != nil {
+					return nil, err
+				}
+				return arr, nil
+			}, nil
+		}
+
+		if IsArrayType(src) {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: dst}, nil
+				}
+
+				arr, err := convertArray(val.(*Array), elemType)
+				if err != nil {
+					return nil, err
+				}
+				return arr, nil
+			}, nil
+		}
+
+		return nil, fmt.Errorf(
+			"%w: only VARCHAR and array types can be cast as %s",
+			ErrUnsupportedCast,
+			dst,
+		)
+	}
+
+	if dst == IntervalType {
+		if src == VarcharType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: IntervalType}, nil
+				}
+
+				str := val.RawValue().(string)
+
+				i, err := ParseInterval(str)
+				if err != nil {
+					if len(str) > 30 {
+						str = str[:30] + "..."
+					}
+
+					return nil, fmt.Errorf(
+						"%w: can not cast string '%s' as an INTERVAL",
+						ErrUnsupportedCast,
+						str,
+					)
+				}
+				return i, nil
+			}, nil
+		}
+
+		return nil, fmt.Errorf(
+			"%w: only VARCHAR type can be cast as INTERVAL",
+			ErrUnsupportedCast,
+		)
+	}
+
 	if dst == TimestampType {
 		if src == IntegerType {
 			return func(val TypedValue) (TypedValue, error) {
@@ -153,6 +261,15 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 			}, nil
 		}
 
+		if src == DecimalType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: Float64Type}, nil
+				}
+				return &Float64{val: val.(*Decimal).Float64()}, nil
+			}, nil
+		}
+
 		if src == JSONType {
 			return jsonConverted(dst), nil
 		}
@@ -163,6 +280,75 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 		)
 	}
 
+	if dst == DecimalType {
+		if src == IntegerType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: DecimalType}, nil
+				}
+				return NewDecimal(val.RawValue().(int64), 0), nil
+			}, nil
+		}
+
+		if src == Float64Type {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: DecimalType}, nil
+				}
+
+				f := val.RawValue().(float64)
+				if math.IsNaN(f) || math.IsInf(f, 0) {
+					return nil, fmt.Errorf(
+						"%w: can not cast FLOAT '%s' as a DECIMAL",
+						ErrUnsupportedCast,
+						val.String(),
+					)
+				}
+
+				d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+				if err != nil {
+					return nil, err
+				}
+				fitted, err := d.fit(0)
+				if err != nil {
+					return nil, err
+				}
+				return fitted, nil
+			}, nil
+		}
+
+		if src == VarcharType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: DecimalType}, nil
+				}
+
+				d, err := ParseDecimal(val.RawValue().(string))
+				if err != nil {
+					return nil, fmt.Errorf(
+						"%w: can not cast string '%s' as a DECIMAL",
+						ErrUnsupportedCast,
+						val.RawValue().(string),
+					)
+				}
+				fitted, err := d.fit(0)
+				if err != nil {
+					return nil, err
+				}
+				return fitted, nil
+			}, nil
+		}
+
+		if src == JSONType {
+			return jsonConverted(dst), nil
+		}
+
+		return nil, fmt.Errorf(
+			"%w: only INTEGER, FLOAT and VARCHAR types can be cast as DECIMAL",
+			ErrUnsupportedCast,
+		)
+	}
+
 	if dst == BooleanType {
 		if src == JSONType {
 			return jsonConverted(dst), nil
@@ -204,6 +390,24 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 			}, nil
 		}
 
+		if src == DecimalType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: IntegerType}, nil
+				}
+
+				i, ok := val.(*Decimal).Int64()
+				if !ok {
+					return nil, fmt.Errorf(
+						"%w: can not cast DECIMAL '%s' as an INTEGER",
+						ErrNumericFieldOverflow,
+						val.String(),
+					)
+				}
+				return &Integer{val: i}, nil
+			}, nil
+		}
+
 		if src == JSONType {
 			return jsonConverted(dst), nil
 		}
@@ -315,6 +519,24 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 			}, nil
 		}
 
+		if src == DecimalType || src == IntervalType {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: VarcharType}, nil
+				}
+				return &Varchar{val: val.String()}, nil
+			}, nil
+		}
+
+		if IsArrayType(src) {
+			return func(val TypedValue) (TypedValue, error) {
+				if val.RawValue() == nil {
+					return &NullValue{t: VarcharType}, nil
+				}
+				return &Varchar{val: val.(*Array).Text()}, nil
+			}, nil
+		}
+
 		if src == JSONType {
 			return jsonConverted(dst), nil
 		}
@@ -334,6 +556,8 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 			switch tv.Type() {
 			case Float64Type, IntegerType, BooleanType, AnyType:
 				return &JSON{val: tv.RawValue()}, nil
+			case DecimalType:
+				return &JSON{val: tv.(*Decimal).Float64()}, nil
 			case VarcharType:
 				var x interface{}
 				s := strings.TrimSuffix(strings.TrimPrefix(tv.String(), "'"), "'")
@@ -48,14 +48,9 @@ func newUnionRowReader(ctx context.Context, rowReaders []RowReader) (*unionRowRe
 			return nil, err
 		}
 
-		if len(cols) != len(cs) {
-			return nil, fmt.Errorf("%w: each subquery must have same number of columns", ErrColumnMismatchInUnionStmt)
-		}
-
-		for c := 0; c < len(cols); c++ {
-			if cols[c].Type != cs[c].Type {
-				return nil, fmt.Errorf("%w: expecting type '%v' for column '%s'", ErrColumnMismatchInUnionStmt, cols[c].Type, cs[c].Column)
-			}
+		err = requireCompatibleColumns(cols, cs)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -65,6 +60,21 @@ func newUnionRowReader(ctx context.Context, rowReaders []RowReader) (*unionRowRe
 	}, nil
 }
 
+// requireCompatibleColumns checks the columns produced by a subquery can be combined
+// with the ones of the first subquery of a set operation
+func requireCompatibleColumns(cols, cs []ColDescriptor) error {
+	if len(cols) != len(cs) {
+		return fmt.Errorf("%w: each subquery must have same number of columns", ErrColumnMismatchInUnionStmt)
+	}
+
+	for c := 0; c < len(cols); c++ {
+		if cols[c].Type != cs[c].Type {
+			return fmt.Errorf("%w: expecting type '%v' for column '%s'", ErrColumnMismatchInUnionStmt, cols[c].Type, cs[c].Column)
+		}
+	}
+	return nil
+}
+
 func (ur *unionRowReader) onClose(callback func()) {
 	ur.rowReaders[0].onClose(callback)
 }
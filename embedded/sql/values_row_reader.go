@@ -112,7 +112,7 @@ func (vr *valuesRowReader) colsBySelector(ctx context.Context) (map[string]ColDe
 func (vr *valuesRowReader) InferParameters(ctx context.Context, params map[string]SQLValueType) error {
 	for _, vs := range vr.values {
 		for _, v := range vs {
-			_, err := v.inferType(vr.colsBySel, params, vr.tableAlias)
+			_, err := v.inferType(vr.tx, vr.colsBySel, params, vr.tableAlias)
 			if err != nil {
 				return err
 			}
@@ -143,7 +143,7 @@ func (vr *valuesRowReader) Read(ctx context.Context) (*Row, error) {
 		}
 
 		if vr.checkTypes {
-			err = rv.requiresType(vr.colsByPos[i].Type, vr.colsBySel, nil, vr.tableAlias)
+			err = rv.requiresType(vr.tx, vr.colsByPos[i].Type, vr.colsBySel, nil, vr.tableAlias)
 			if err != nil {
 				return nil, err
 			}
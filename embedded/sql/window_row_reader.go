@@ -0,0 +1,604 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	RowNumberWindowFn string = "ROW_NUMBER"
+	RankWindowFn      string = "RANK"
+	DenseRankWindowFn string = "DENSE_RANK"
+	LagWindowFn       string = "LAG"
+	LeadWindowFn      string = "LEAD"
+)
+
+// newWindowRowReaders evaluates the specified window functions over the rows produced by rowReader.
+// Functions sharing the same window specification are evaluated together, after sorting the rows
+// by the partitioning and ordering expressions of the window.
+func newWindowRowReaders(rowReader RowReader, windowFns []*WindowFnExp) (RowReader, error) {
+	var specs []string
+
+	fnsBySpec := make(map[string][]*WindowFnExp)
+
+	for _, w := range windowFns {
+		spec := w.windowSpec()
+
+		if _, exists := fnsBySpec[spec]; !exists {
+			specs = append(specs, spec)
+		}
+		fnsBySpec[spec] = append(fnsBySpec[spec], w)
+	}
+
+	for _, spec := range specs {
+		fns := fnsBySpec[spec]
+
+		ordExps := make([]*OrdExp, 0, len(fns[0].partitionBy)+len(fns[0].orderBy))
+		for _, e := range fns[0].partitionBy {
+			ordExps = append(ordExps, &OrdExp{exp: e})
+		}
+		ordExps = append(ordExps, fns[0].orderBy...)
+
+		if len(ordExps) > 0 {
+			sortRowReader, err := newSortRowReader(rowReader, ordExps)
+			if err != nil {
+				return nil, err
+			}
+			rowReader = sortRowReader
+		}
+
+		windowRowReader, err := newWindowRowReader(rowReader, fns)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = windowRowReader
+	}
+	return rowReader, nil
+}
+
+type windowRow struct {
+	row          *Row
+	partitionKey Tuple
+	orderKey     Tuple
+}
+
+// windowRowReader evaluates window functions sharing the same window specification
+// over rows already sorted by partition and ordering keys.
+//
+// Rows are streamed, only the rows required to evaluate the functions over the first pending row
+// are kept in memory i.e. the following rows accessed by LEAD and the rest of the peer group when
+// computing aggregations (the whole partition when no ordering is specified).
+type windowRowReader struct {
+	rowReader RowReader
+
+	fns     []*WindowFnExp
+	offsets []int
+	cols    []ColDescriptor
+
+	buf          []*windowRow
+	pending      *windowRow
+	inPartition  bool
+	partitionKey Tuple
+
+	partitionEnded bool
+	eof            bool
+
+	rowNumber    int64
+	rank         int64
+	denseRank    int64
+	prevOrderKey Tuple
+	lagValues    [][]TypedValue
+	aggValues    []AggregatedValue
+}
+
+func newWindowRowReader(rowReader RowReader, windowFns []*WindowFnExp) (*windowRowReader, error) {
+	if rowReader == nil || len(windowFns) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	colsBySel, err := rowReader.colsBySelector(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	params := rowReader.Parameters()
+
+	fns := make([]*WindowFnExp, len(windowFns))
+	offsets := make([]int, len(windowFns))
+	cols := make([]ColDescriptor, len(windowFns))
+
+	for i, w := range windowFns {
+		sw, err := w.substitute(params)
+		if err != nil {
+			return nil, err
+		}
+		fns[i] = sw.(*WindowFnExp)
+
+		offsets[i], err = validateWindowFn(rowReader.Tx(), fns[i], params)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := fns[i].valueType(rowReader.Tx(), colsBySel, make(map[string]SQLValueType), rowReader.TableAlias())
+		if err != nil {
+			return nil, err
+		}
+
+		cols[i] = ColDescriptor{
+			Table:  rowReader.TableAlias(),
+			Column: fns[i].colName(),
+			Type:   t,
+		}
+	}
+
+	return &windowRowReader{
+		rowReader: rowReader,
+		fns:       fns,
+		offsets:   offsets,
+		cols:      cols,
+		lagValues: make([][]TypedValue, len(fns)),
+		aggValues: make([]AggregatedValue, len(fns)),
+	}, nil
+}
+
+// validateWindowFn checks the arguments of the window function and returns the offset to be used by LAG and LEAD functions.
+func validateWindowFn(tx *SQLTx, w *WindowFnExp, params map[string]interface{}) (int, error) {
+	fn := strings.ToUpper(w.fn)
+
+	switch fn {
+	case RowNumberWindowFn, RankWindowFn, DenseRankWindowFn:
+		{
+			if len(w.params) > 0 {
+				return 0, fmt.Errorf("%w: '%s' window function does not expect any argument but %d were provided", ErrIllegalArguments, fn, len(w.params))
+			}
+			return 0, nil
+		}
+	case LagWindowFn, LeadWindowFn:
+		{
+			if len(w.params) == 0 || len(w.params) > 3 {
+				return 0, fmt.Errorf("%w: '%s' window function expects between one and three arguments but %d were provided", ErrIllegalArguments, fn, len(w.params))
+			}
+
+			if len(w.params) == 1 {
+				return 1, nil
+			}
+
+			offset, err := evalExpAsInt(tx, w.params[1], params)
+			if err != nil {
+				return 0, fmt.Errorf("%w: invalid offset in '%s' window function", err, fn)
+			}
+
+			if offset < 0 {
+				return 0, fmt.Errorf("%w: offset in '%s' window function can not be negative", ErrIllegalArguments, fn)
+			}
+			return offset, nil
+		}
+	case COUNT, SUM, MIN, MAX, AVG:
+		{
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown window function %s", ErrIllegalArguments, w.fn)
+}
+
+func (wr *windowRowReader) onClose(callback func()) {
+	wr.rowReader.onClose(callback)
+}
+
+func (wr *windowRowReader) Tx() *SQLTx {
+	return wr.rowReader.Tx()
+}
+
+func (wr *windowRowReader) TableAlias() string {
+	return wr.rowReader.TableAlias()
+}
+
+func (wr *windowRowReader) Parameters() map[string]interface{} {
+	return wr.rowReader.Parameters()
+}
+
+func (wr *windowRowReader) OrderBy() []ColDescriptor {
+	return wr.rowReader.OrderBy()
+}
+
+func (wr *windowRowReader) ScanSpecs() *ScanSpecs {
+	return wr.rowReader.ScanSpecs()
+}
+
+func (wr *windowRowReader) Columns(ctx context.Context) ([]ColDescriptor, error) {
+	cols, err := wr.rowReader.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	colsByPos := make([]ColDescriptor, 0, len(cols)+len(wr.cols))
+	colsByPos = append(colsByPos, cols...)
+	return append(colsByPos, wr.cols...), nil
+}
+
+func (wr *windowRowReader) colsBySelector(ctx context.Context) (map[string]ColDescriptor, error) {
+	cols, err := wr.rowReader.colsBySelector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	colsBySel := make(map[string]ColDescriptor, len(cols)+len(wr.cols))
+	for sel, col := range cols {
+		colsBySel[sel] = col
+	}
+
+	for _, col := range wr.cols {
+		colsBySel[col.Selector()] = col
+	}
+	return colsBySel, nil
+}
+
+func (wr *windowRowReader) InferParameters(ctx context.Context, params map[string]SQLValueType) error {
+	err := wr.rowReader.InferParameters(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	cols, err := wr.rowReader.colsBySelector(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range wr.fns {
+		_, err = w.valueType(wr.Tx(), cols, params, wr.TableAlias())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wr *windowRowReader) Read(ctx context.Context) (*Row, error) {
+	for {
+		if len(wr.buf) == 0 {
+			if wr.eof {
+				return nil, ErrNoMoreRows
+			}
+
+			if wr.partitionEnded {
+				wr.startPartition()
+			}
+		}
+
+		if wr.canEmit() {
+			return wr.emit()
+		}
+
+		err := wr.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (wr *windowRowReader) fetch(ctx context.Context) error {
+	row, err := wr.rowReader.Read(ctx)
+	if err == ErrNoMoreRows {
+		wr.eof = true
+		wr.partitionEnded = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	wrow := &windowRow{row: row}
+
+	wrow.partitionKey, err = wr.evalExps(row, wr.fns[0].partitionBy)
+	if err != nil {
+		return err
+	}
+
+	orderExps := make([]ValueExp, len(wr.fns[0].orderBy))
+	for i, oe := range wr.fns[0].orderBy {
+		orderExps[i] = oe.exp
+	}
+
+	wrow.orderKey, err = wr.evalExps(row, orderExps)
+	if err != nil {
+		return err
+	}
+
+	if !wr.inPartition {
+		wr.inPartition = true
+		wr.partitionKey = wrow.partitionKey
+		wr.buf = append(wr.buf, wrow)
+		return nil
+	}
+
+	res, _, err := wr.partitionKey.Compare(wrow.partitionKey)
+	if err != nil {
+		return err
+	}
+
+	if res == 0 {
+		wr.buf = append(wr.buf, wrow)
+		return nil
+	}
+
+	wr.pending = wrow
+	wr.partitionEnded = true
+
+	return nil
+}
+
+func (wr *windowRowReader) evalExps(row *Row, exps []ValueExp) (Tuple, error) {
+	t := make(Tuple, len(exps))
+
+	for i, e := range exps {
+		v, err := e.reduce(wr.Tx(), row, wr.TableAlias())
+		if err != nil {
+			return nil, err
+		}
+		t[i] = v
+	}
+	return t, nil
+}
+
+func (wr *windowRowReader) startPartition() {
+	wr.buf = wr.buf[:0]
+	wr.inPartition = false
+	wr.partitionEnded = false
+
+	wr.rowNumber = 0
+	wr.rank = 0
+	wr.denseRank = 0
+	wr.prevOrderKey = nil
+
+	for i := range wr.fns {
+		wr.lagValues[i] = nil
+		wr.aggValues[i] = nil
+	}
+
+	if wr.pending != nil {
+		wr.inPartition = true
+		wr.partitionKey = wr.pending.partitionKey
+		wr.buf = append(wr.buf, wr.pending)
+		wr.pending = nil
+	}
+}
+
+// canEmit returns true when all the rows needed to evaluate the window functions over the first buffered row are available
+func (wr *windowRowReader) canEmit() bool {
+	if len(wr.buf) == 0 {
+		return false
+	}
+
+	if wr.partitionEnded {
+		return true
+	}
+
+	head := wr.buf[0]
+
+	for i, w := range wr.fns {
+		switch strings.ToUpper(w.fn) {
+		case LeadWindowFn:
+			{
+				if len(wr.buf) <= wr.offsets[i] {
+					return false
+				}
+			}
+		case COUNT, SUM, MIN, MAX, AVG:
+			{
+				// the whole peer group must be available
+				last := wr.buf[len(wr.buf)-1]
+
+				if len(wr.buf) == 1 || wr.isPeer(head, last) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (wr *windowRowReader) isPeer(r1, r2 *windowRow) bool {
+	res, _, err := r1.orderKey.Compare(r2.orderKey)
+	return err == nil && res == 0
+}
+
+func (wr *windowRowReader) emit() (*Row, error) {
+	head := wr.buf[0]
+
+	newPeerGroup := wr.prevOrderKey == nil
+	if !newPeerGroup {
+		res, _, err := wr.prevOrderKey.Compare(head.orderKey)
+		if err != nil {
+			return nil, err
+		}
+		newPeerGroup = res != 0
+	}
+
+	wr.rowNumber++
+
+	if newPeerGroup {
+		wr.rank = wr.rowNumber
+		wr.denseRank++
+
+		err := wr.aggregatePeerGroup()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	row := &Row{
+		ValuesByPosition: make([]TypedValue, 0, len(head.row.ValuesByPosition)+len(wr.fns)),
+		ValuesBySelector: make(map[string]TypedValue, len(head.row.ValuesBySelector)+len(wr.fns)),
+	}
+
+	row.ValuesByPosition = append(row.ValuesByPosition, head.row.ValuesByPosition...)
+
+	for sel, v := range head.row.ValuesBySelector {
+		row.ValuesBySelector[sel] = v
+	}
+
+	for i, w := range wr.fns {
+		v, err := wr.evalWindowFn(i, w)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.IsNull() {
+			v = NewNull(wr.cols[i].Type)
+		}
+
+		row.ValuesByPosition = append(row.ValuesByPosition, v)
+		row.ValuesBySelector[wr.cols[i].Selector()] = v
+	}
+
+	for i, w := range wr.fns {
+		if strings.ToUpper(w.fn) != LagWindowFn || wr.offsets[i] == 0 {
+			continue
+		}
+
+		v, err := w.params[0].reduce(wr.Tx(), head.row, wr.TableAlias())
+		if err != nil {
+			return nil, err
+		}
+
+		wr.lagValues[i] = append(wr.lagValues[i], v)
+		if len(wr.lagValues[i]) > wr.offsets[i] {
+			wr.lagValues[i] = wr.lagValues[i][1:]
+		}
+	}
+
+	wr.prevOrderKey = head.orderKey
+	wr.buf = wr.buf[1:]
+
+	return row, nil
+}
+
+func (wr *windowRowReader) aggregatePeerGroup() error {
+	head := wr.buf[0]
+
+	for i, w := range wr.fns {
+		aggFn := strings.ToUpper(w.fn)
+
+		switch aggFn {
+		case COUNT, SUM, MIN, MAX, AVG:
+		default:
+			continue
+		}
+
+		if wr.aggValues[i] == nil {
+			sel := &AggColSelector{aggFn: aggFn, col: "*"}
+			if len(w.params) > 0 {
+				sel.col = w.params[0].String()
+			}
+
+			v, err := initAggValue(sel, wr.TableAlias(), wr.Tx().distinctLimit())
+			if err != nil {
+				return err
+			}
+			wr.aggValues[i] = v
+		}
+
+		for _, r := range wr.buf {
+			if !wr.isPeer(head, r) {
+				break
+			}
+
+			var v TypedValue = &Bool{val: true}
+
+			if len(w.params) > 0 {
+				rv, err := w.params[0].reduce(wr.Tx(), r.row, wr.TableAlias())
+				if err != nil {
+					return err
+				}
+				v = rv
+			}
+
+			err := wr.aggValues[i].updateWith(v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (wr *windowRowReader) evalWindowFn(i int, w *WindowFnExp) (TypedValue, error) {
+	switch strings.ToUpper(w.fn) {
+	case RowNumberWindowFn:
+		{
+			return &Integer{val: wr.rowNumber}, nil
+		}
+	case RankWindowFn:
+		{
+			return &Integer{val: wr.rank}, nil
+		}
+	case DenseRankWindowFn:
+		{
+			return &Integer{val: wr.denseRank}, nil
+		}
+	case LagWindowFn:
+		{
+			if wr.offsets[i] == 0 {
+				return w.params[0].reduce(wr.Tx(), wr.buf[0].row, wr.TableAlias())
+			}
+
+			if len(wr.lagValues[i]) == wr.offsets[i] {
+				return wr.lagValues[i][0], nil
+			}
+			return wr.defaultValue(w)
+		}
+	case LeadWindowFn:
+		{
+			if wr.offsets[i] < len(wr.buf) {
+				return w.params[0].reduce(wr.Tx(), wr.buf[wr.offsets[i]].row, wr.TableAlias())
+			}
+			return wr.defaultValue(w)
+		}
+	}
+	return aggregatedValueOf(wr.aggValues[i]), nil
+}
+
+func (wr *windowRowReader) defaultValue(w *WindowFnExp) (TypedValue, error) {
+	if len(w.params) < 3 {
+		return &NullValue{t: AnyType}, nil
+	}
+	return w.params[2].reduce(wr.Tx(), nil, wr.TableAlias())
+}
+
+// aggregatedValueOf returns the current value of an aggregation, which will not be affected by further updates
+func aggregatedValueOf(v AggregatedValue) TypedValue {
+	switch av := v.(type) {
+	case *CountValue:
+		return &Integer{val: av.c}
+	case *SumValue:
+		return av.val
+	case *MinValue:
+		return av.val
+	case *MaxValue:
+		return av.val
+	case *AVGValue:
+		return av.calculate()
+	}
+	return v
+}
+
+func (wr *windowRowReader) Close() error {
+	return wr.rowReader.Close()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowRowReader(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, err = newWindowRowReader(nil, nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE table1(id INTEGER, number INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions())
+	require.NoError(t, err)
+
+	defer tx.Cancel()
+
+	table := tx.catalog.tables[0]
+
+	r, err := newRawRowReader(tx, nil, table, period{}, "", &ScanSpecs{Index: table.primaryIndex})
+	require.NoError(t, err)
+
+	_, err = newWindowRowReader(r, nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = newWindowRowReader(r, []*WindowFnExp{{fn: "ntile"}})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = newWindowRowReader(r, []*WindowFnExp{{fn: "lead"}})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = newWindowRowReader(r, []*WindowFnExp{{fn: "lag", params: []ValueExp{&ColSelector{col: "number"}, &Varchar{val: "one"}}}})
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	wr, err := newWindowRowReader(r, []*WindowFnExp{
+		{fn: "row_number", name: "rn"},
+		{fn: SUM, params: []ValueExp{&ColSelector{col: "number"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, r.OrderBy(), wr.OrderBy())
+	require.Equal(t, r.ScanSpecs(), wr.ScanSpecs())
+	require.Equal(t, "table1", wr.TableAlias())
+
+	cols, err := wr.Columns(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cols, 4)
+	require.Equal(t, "rn", cols[2].Column)
+	require.Equal(t, IntegerType, cols[2].Type)
+	require.Equal(t, "SUM(number) OVER ()", cols[3].Column)
+	require.Equal(t, IntegerType, cols[3].Type)
+
+	colsBySel, err := wr.colsBySelector(context.Background())
+	require.NoError(t, err)
+	require.Len(t, colsBySel, 4)
+	require.Contains(t, colsBySel, EncodeSelector("", "table1", "rn"))
+
+	params := make(map[string]SQLValueType)
+	err = wr.InferParameters(context.Background(), params)
+	require.NoError(t, err)
+
+	_, err = wr.Read(context.Background())
+	require.ErrorIs(t, err, ErrNoMoreRows)
+
+	err = wr.Close()
+	require.NoError(t, err)
+}
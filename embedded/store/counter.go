@@ -0,0 +1,50 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+const counterSize = 8
+
+// IncrementFn returns a ValueFn which adds delta to the counter held by the key.
+// Counters are encoded as 8-byte big-endian signed integers, a non-existent key is a zero counter.
+func IncrementFn(delta int64) ValueFn {
+	return func(value []byte, found bool) ([]byte, error) {
+		var counter int64
+
+		if found {
+			if len(value) != counterSize {
+				return nil, ErrInvalidCounterValue
+			}
+
+			counter = int64(binary.BigEndian.Uint64(value))
+		}
+
+		if (delta > 0 && counter > math.MaxInt64-delta) ||
+			(delta < 0 && counter < math.MinInt64-delta) {
+			return nil, ErrCounterOverflow
+		}
+
+		newValue := make([]byte, counterSize)
+		binary.BigEndian.PutUint64(newValue, uint64(counter+delta))
+
+		return newValue, nil
+	}
+}
@@ -90,6 +90,9 @@ var ErrUnexpectedError = errors.New("unexpected error")
 var ErrUnsupportedTxVersion = errors.New("unsupported tx version")
 var ErrNewerVersionOrCorruptedData = errors.New("tx created with a newer version or data is corrupted")
 var ErrTxPoolExhausted = errors.New("transaction pool exhausted")
+var ErrInvalidCounterValue = errors.New("invalid counter value")
+var ErrCounterOverflow = errors.New("counter overflow")
+var ErrValueNotYetResolved = errors.New("value is not resolved until the transaction is committed")
 
 var ErrInvalidPrecondition = errors.New("invalid precondition")
 var ErrInvalidPreconditionTooMany = fmt.Errorf("%w: too many preconditions", ErrInvalidPrecondition)
@@ -97,6 +100,8 @@ var ErrInvalidPreconditionNull = fmt.Errorf("%w: null", ErrInvalidPrecondition)
 var ErrInvalidPreconditionNullKey = fmt.Errorf("%w: %v", ErrInvalidPrecondition, ErrNullKey)
 var ErrInvalidPreconditionMaxKeyLenExceeded = fmt.Errorf("%w: %v", ErrInvalidPrecondition, ErrMaxKeyLenExceeded)
 var ErrInvalidPreconditionInvalidTxID = fmt.Errorf("%w: invalid transaction ID", ErrInvalidPrecondition)
+var ErrInvalidPreconditionMaxValueLenExceeded = fmt.Errorf("%w: %v", ErrInvalidPrecondition, ErrMaxValueLenExceeded)
+var ErrInvalidPreconditionInvalidHash = fmt.Errorf("%w: invalid hash", ErrInvalidPrecondition)
 
 var ErrSourceTxNewerThanTargetTx = fmt.Errorf("%w: source tx is newer than target tx", ErrIllegalArguments)
 
@@ -272,6 +277,7 @@ func Open(path string, opts *Options) (*ImmuStore, error) {
 		WithAutoSync(true).
 		WithFileSize(opts.FileSize).
 		WithFileMode(opts.FileMode).
+		WithKeyProvider(opts.KeyProvider).
 		WithMetadata(metadata.Bytes())
 
 	appFactory := opts.appFactory
@@ -596,7 +602,8 @@ func OpenWith(path string, vLogs []appendable.Appendable, txLog, cLog appendable
 		WithRetryableSync(opts.Synced).
 		WithAutoSync(true).
 		WithWriteBufferSize(opts.AHTOpts.WriteBufferSize).
-		WithSyncThld(opts.AHTOpts.SyncThld)
+		WithSyncThld(opts.AHTOpts.SyncThld).
+		WithKeyProvider(opts.KeyProvider)
 
 	if opts.appFactory != nil {
 		ahtOpts.WithAppFactory(func(rootPath, subPath string, appOpts *multiapp.Options) (appendable.Appendable, error) {
@@ -1582,6 +1589,33 @@ func (s *ImmuStore) precommit(ctx context.Context, otx *OngoingTx, hdr *TxHeader
 		return nil, err
 	}
 
+	withValueFns := hasValueFns(otx.entries)
+
+	if withValueFns {
+		if hdr != nil {
+			return nil, fmt.Errorf("%w: values of replicated transactions must be resolved", ErrIllegalArguments)
+		}
+
+		// values depending on the latest state of the keys must be written
+		// while no other transaction can be precommitted
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if s.closed {
+			return nil, ErrAlreadyClosed
+		}
+
+		err = s.WaitForIndexingUpto(ctx, s.LastPrecommittedTxID())
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.resolveValues(ctx, otx.entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := s.fetchAllocTx()
 	if err != nil {
 		return nil, err
@@ -1686,8 +1720,10 @@ func (s *ImmuStore) precommit(ctx context.Context, otx *OngoingTx, hdr *TxHeader
 		}
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	if !withValueFns {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+	}
 
 	if s.closed {
 		return nil, ErrAlreadyClosed
@@ -2249,12 +2285,15 @@ func (s *ImmuStore) preCommitWith(ctx context.Context, callback func(txID uint64
 		return nil, err
 	}
 
-	if otx.hasPreconditions() {
+	withValueFns := hasValueFns(otx.entries)
+
+	if otx.hasPreconditions() || withValueFns {
 		for _, indexer := range s.indexers {
 			indexer.Resume()
 		}
 
-		// Preconditions must be executed with up-to-date tree
+		// Preconditions and values depending on the latest state
+		// of the keys must be executed with up-to-date tree
 		err = s.WaitForIndexingUpto(ctx, lastPreCommittedTxID)
 		if err != nil {
 			return nil, err
@@ -2265,6 +2304,13 @@ func (s *ImmuStore) preCommitWith(ctx context.Context, callback func(txID uint64
 			return nil, err
 		}
 
+		if withValueFns {
+			err = s.resolveValues(ctx, otx.entries)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		for _, indexer := range s.indexers {
 			indexer.Pause()
 		}
@@ -3268,6 +3314,52 @@ func (s *ImmuStore) validateEntries(entries []*EntrySpec) error {
 	return nil
 }
 
+func hasValueFns(entries []*EntrySpec) bool {
+	for _, e := range entries {
+		if e.ValueFn != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveValues computes the values of the entries which depend on the latest value of the key.
+// It must be called with an up-to-date index and while no other transaction can be precommitted
+func (s *ImmuStore) resolveValues(ctx context.Context, entries []*EntrySpec) error {
+	for _, e := range entries {
+		if e.ValueFn == nil {
+			continue
+		}
+
+		var value []byte
+
+		valRef, err := s.GetWithFilters(ctx, e.Key, IgnoreExpired, IgnoreDeleted)
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return err
+		}
+
+		found := err == nil
+
+		if found {
+			value, err = valRef.Resolve()
+			if err != nil {
+				return err
+			}
+		}
+
+		e.Value, err = e.ValueFn(value, found)
+		if err != nil {
+			return err
+		}
+
+		if len(e.Value) > s.maxValueLen {
+			return ErrMaxValueLenExceeded
+		}
+	}
+
+	return nil
+}
+
 func (s *ImmuStore) validatePreconditions(preconditions []Precondition) error {
 	if len(preconditions) > s.maxTxEntries {
 		return ErrInvalidPreconditionTooMany
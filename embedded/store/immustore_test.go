@@ -35,6 +35,7 @@ import (
 	"github.com/codenotary/immudb/embedded"
 	"github.com/codenotary/immudb/embedded/ahtree"
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/mocked"
 	"github.com/codenotary/immudb/embedded/appendable/multiapp"
 	"github.com/codenotary/immudb/embedded/htree"
@@ -4455,6 +4456,107 @@ func TestImmudbStoreWithVLogCache(t *testing.T) {
 	require.Equal(t, []byte("value1"), val)
 }
 
+func TestImmudbStoreWithEncryption(t *testing.T) {
+	dir := t.TempDir()
+
+	kp := cryptoapp.NewFileKeyProvider(filepath.Join(t.TempDir(), "keys"))
+
+	_, err := kp.Rotate(32)
+	require.NoError(t, err)
+
+	_, err = Open(dir, DefaultOptions().WithKeyProvider(kp).WithCompressionFormat(appendable.ZLibCompression))
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = Open(dir, DefaultOptions().WithKeyProvider(kp).WithPreallocFiles(true))
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	opts := DefaultOptions().
+		WithEmbeddedValues(false).
+		WithFileSize(1024).
+		WithKeyProvider(kp)
+
+	immuStore, err := Open(dir, opts)
+	require.NoError(t, err)
+
+	value := func(i int) []byte {
+		return []byte(fmt.Sprintf("plaintext-value-%d", i))
+	}
+
+	commit := func(from, to int) {
+		for i := from; i < to; i++ {
+			tx, err := immuStore.NewWriteOnlyTx(context.Background())
+			require.NoError(t, err)
+
+			err = tx.Set([]byte(fmt.Sprintf("plaintext-key-%d", i)), nil, value(i))
+			require.NoError(t, err)
+
+			_, err = tx.Commit(context.Background())
+			require.NoError(t, err)
+		}
+	}
+
+	commit(0, 50)
+
+	err = immuStore.Close()
+	require.NoError(t, err)
+
+	// files created from now on are encrypted with the new key
+	_, err = kp.Rotate(32)
+	require.NoError(t, err)
+
+	immuStore, err = Open(dir, opts)
+	require.NoError(t, err)
+
+	commit(50, 100)
+
+	err = immuStore.WaitForIndexingUpto(context.Background(), 100)
+	require.NoError(t, err)
+
+	err = immuStore.Close()
+	require.NoError(t, err)
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		require.NotContains(t, string(bs), "plaintext-", path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = Open(dir, DefaultOptions())
+	require.ErrorIs(t, err, cryptoapp.ErrKeyProviderRequired)
+
+	immuStore, err = Open(dir, opts)
+	require.NoError(t, err)
+
+	defer immuStore.Close()
+
+	require.Equal(t, uint64(100), immuStore.LastCommittedTxID())
+
+	for i := 0; i < 100; i++ {
+		valRef, err := immuStore.Get(context.Background(), []byte(fmt.Sprintf("plaintext-key-%d", i)))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, value(i), val)
+	}
+
+	tx := tempTxHolder(t, immuStore)
+
+	for i := uint64(1); i <= 100; i++ {
+		err = immuStore.ReadTx(i, false, tx)
+		require.NoError(t, err)
+	}
+}
+
 func TestImmudbStoreTruncateUptoTx_WithMultipleIOConcurrency(t *testing.T) {
 	fileSize := 1024
 
@@ -119,6 +119,7 @@ func newIndexer(path string, store *ImmuStore, opts *Options) (*indexer, error)
 		WithFileMode(opts.FileMode).
 		WithLogger(opts.logger).
 		WithFileSize(opts.FileSize).
+		WithKeyProvider(opts.KeyProvider).
 		WithCacheSize(opts.IndexOpts.CacheSize).
 		WithCache(store.indexCache).
 		WithFlushThld(opts.IndexOpts.FlushThld).
@@ -95,8 +95,15 @@ type EntrySpec struct {
 	// isValueTruncated is true if the value is
 	// truncated. This is used during replication.
 	IsValueTruncated bool
+	// ValueFn, when set, is used to compute the value at commit time
+	// based on the latest value of the key.
+	ValueFn ValueFn
 }
 
+// ValueFn computes the value of an entry based on the current value of the key.
+// found is false when the key does not exist or when it was deleted or expired.
+type ValueFn func(value []byte, found bool) ([]byte, error)
+
 func newOngoingTx(ctx context.Context, s *ImmuStore, opts *TxOptions) (*OngoingTx, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -130,13 +137,17 @@ func newOngoingTx(ctx context.Context, s *ImmuStore, opts *TxOptions) (*OngoingT
 }
 
 type ongoingValRef struct {
-	value []byte
-	hc    uint64
-	txmd  *TxMetadata
-	kvmd  *KVMetadata
+	value      []byte
+	unresolved bool // value is computed at commit time
+	hc         uint64
+	txmd       *TxMetadata
+	kvmd       *KVMetadata
 }
 
 func (oref *ongoingValRef) Resolve() (val []byte, err error) {
+	if oref.unresolved {
+		return nil, ErrValueNotYetResolved
+	}
 	return oref.value, nil
 }
 
@@ -227,10 +238,11 @@ func (tx *OngoingTx) snap(key []byte) (*Snapshot, error) {
 		}
 
 		return &ongoingValRef{
-			hc:    valRef.HC(),
-			value: entrySpec.Value,
-			txmd:  tx.metadata,
-			kvmd:  entrySpec.Metadata,
+			hc:         valRef.HC(),
+			value:      entrySpec.Value,
+			unresolved: entrySpec.ValueFn != nil,
+			txmd:       tx.metadata,
+			kvmd:       entrySpec.Metadata,
 		}
 	}
 
@@ -336,8 +348,9 @@ func (tx *OngoingTx) set(key []byte, md *KVMetadata, value []byte, hashValue [sh
 			if isKeyUpdate {
 				tx.transientEntries[keyRef] = e
 			} else {
-				tx.transientEntries[len(tx.entriesByKey)] = e
-				tx.entriesByKey[kid] = len(tx.entriesByKey)
+				keyRef := tx.newTransientKeyRef()
+				tx.transientEntries[keyRef] = e
+				tx.entriesByKey[kid] = keyRef
 			}
 		}
 	}
@@ -350,8 +363,9 @@ func (tx *OngoingTx) set(key []byte, md *KVMetadata, value []byte, hashValue [sh
 		}
 	} else {
 		if isTransient {
-			tx.transientEntries[len(tx.entriesByKey)] = e
-			tx.entriesByKey[kid] = len(tx.entriesByKey)
+			keyRef := tx.newTransientKeyRef()
+			tx.transientEntries[keyRef] = e
+			tx.entriesByKey[kid] = keyRef
 		} else {
 			tx.entries = append(tx.entries, e)
 			tx.entriesByKey[kid] = len(tx.entries) - 1
@@ -362,6 +376,12 @@ func (tx *OngoingTx) set(key []byte, md *KVMetadata, value []byte, hashValue [sh
 	return nil
 }
 
+// newTransientKeyRef returns a reference for a new transient entry. Transient entries are referenced
+// by negative values so to not clash with the position of regular entries
+func (tx *OngoingTx) newTransientKeyRef() int {
+	return -(len(tx.transientEntries) + 1)
+}
+
 func mapKey(key []byte, value []byte, mapper EntryMapper) (mappedKey []byte, err error) {
 	if mapper == nil {
 		return key, nil
@@ -379,6 +399,58 @@ func (tx *OngoingTx) SetTransient(key []byte, md *KVMetadata, value []byte) erro
 	return tx.set(key, md, value, hashValue, false, true)
 }
 
+// Increment adds delta to the counter held by the key. The increment is applied at commit time
+// over the latest value of the key, thus it does not introduce read conflicts.
+// A counter is encoded as an 8-byte big-endian signed integer, a non-existent key is a zero counter.
+func (tx *OngoingTx) Increment(key []byte, md *KVMetadata, delta int64) error {
+	if tx.closed {
+		return ErrAlreadyClosed
+	}
+
+	keyRef, isKeyUpdate := tx.entriesByKey[sha256.Sum256(key)]
+	if isKeyUpdate && keyRef < 0 {
+		return ErrCannotUpdateKeyTransiency
+	}
+
+	incr := IncrementFn(delta)
+
+	if isKeyUpdate {
+		e := tx.entries[keyRef]
+
+		if e.ValueFn == nil {
+			// the latest value is already known within the transaction
+			found := e.Metadata == nil || !e.Metadata.Deleted()
+
+			value, err := incr(e.Value, found)
+			if err != nil {
+				return err
+			}
+
+			return tx.Set(key, md, value)
+		}
+
+		prevFn := e.ValueFn
+
+		incr = func(value []byte, found bool) ([]byte, error) {
+			value, err := prevFn(value, found)
+			if err != nil {
+				return nil, err
+			}
+			return IncrementFn(delta)(value, true)
+		}
+	}
+
+	// the placeholder value is replaced at commit time
+	err := tx.Set(key, md, make([]byte, counterSize))
+	if err != nil {
+		return err
+	}
+
+	tx.entries[tx.entriesByKey[sha256.Sum256(key)]].ValueFn = incr
+
+	return nil
+}
+
 func (tx *OngoingTx) AddPrecondition(c Precondition) error {
 	if tx.closed {
 		return ErrAlreadyClosed
@@ -18,6 +18,9 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
 	"testing"
 	"time"
 
@@ -94,3 +97,277 @@ func TestOngoingTxOptions(t *testing.T) {
 	require.EqualValues(t, 1, opts.WithSnapshotMustIncludeTxID(func(lastPrecommittedTxID uint64) uint64 { return 1 }).SnapshotMustIncludeTxID(100))
 	require.True(t, opts.WithUnsafeMVCC(true).UnsafeMVCC)
 }
+
+func TestOngoingTxUpdateAfterTransientEntry(t *testing.T) {
+	st, err := Open(t.TempDir(), DefaultOptions())
+	require.NoError(t, err)
+
+	defer immustoreClose(t, st)
+
+	tx, err := st.NewTx(context.Background(), DefaultTxOptions())
+	require.NoError(t, err)
+
+	err = tx.Set([]byte("key1"), nil, []byte("value1"))
+	require.NoError(t, err)
+
+	err = tx.SetTransient([]byte("key2"), nil, []byte("value2"))
+	require.NoError(t, err)
+
+	err = tx.Set([]byte("key3"), nil, []byte("value3"))
+	require.NoError(t, err)
+
+	err = tx.Set([]byte("key3"), nil, []byte("value3_1"))
+	require.NoError(t, err)
+
+	err = tx.SetTransient([]byte("key3"), nil, []byte("value3_2"))
+	require.ErrorIs(t, err, ErrCannotUpdateKeyTransiency)
+
+	for key, value := range map[string]string{"key2": "value2", "key3": "value3_1"} {
+		valRef, err := tx.Get(context.Background(), []byte(key))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, []byte(value), val)
+	}
+
+	_, err = tx.Commit(context.Background())
+	require.NoError(t, err)
+}
+
+func TestOngoingTxValuePreconditions(t *testing.T) {
+	st, err := Open(t.TempDir(), DefaultOptions().WithMaxValueLen(32))
+	require.NoError(t, err)
+
+	defer immustoreClose(t, st)
+
+	tx, err := st.NewWriteOnlyTx(context.Background())
+	require.NoError(t, err)
+
+	err = tx.Set([]byte("key1"), nil, []byte("value1"))
+	require.NoError(t, err)
+
+	_, err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	t.Run("invalid preconditions should be rejected", func(t *testing.T) {
+		tx, err := st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		err = tx.AddPrecondition(&PreconditionKeyValueMustEqual{})
+		require.ErrorIs(t, err, ErrInvalidPreconditionNullKey)
+
+		err = tx.AddPrecondition(&PreconditionKeyValueMustEqual{Key: []byte("key1"), Value: make([]byte, 33)})
+		require.ErrorIs(t, err, ErrInvalidPreconditionMaxValueLenExceeded)
+
+		err = tx.AddPrecondition(&PreconditionKeyValueHashMustEqual{})
+		require.ErrorIs(t, err, ErrInvalidPreconditionNullKey)
+	})
+
+	for _, c := range []struct {
+		precondition Precondition
+		satisfied    bool
+	}{
+		{&PreconditionKeyValueMustEqual{Key: []byte("key1"), Value: []byte("value1")}, true},
+		{&PreconditionKeyValueMustEqual{Key: []byte("key1"), Value: []byte("value2")}, false},
+		{&PreconditionKeyValueMustEqual{Key: []byte("key1"), Value: []byte("value1_")}, false},
+		{&PreconditionKeyValueMustEqual{Key: []byte("key2"), Value: nil}, false},
+		{&PreconditionKeyValueHashMustEqual{Key: []byte("key1"), Hash: sha256.Sum256([]byte("value1"))}, true},
+		{&PreconditionKeyValueHashMustEqual{Key: []byte("key1"), Hash: sha256.Sum256([]byte("value2"))}, false},
+		{&PreconditionKeyValueHashMustEqual{Key: []byte("key2"), Hash: sha256.Sum256(nil)}, false},
+		{&PreconditionKeyValueHashMustEqual{Key: []byte("key1"), Hash: sha256.Sum256([]byte("1")), ValuePrefix: []byte("value")}, true},
+		{&PreconditionKeyValueHashMustEqual{Key: []byte("key1"), Hash: sha256.Sum256([]byte("value1")), ValuePrefix: []byte("value")}, false},
+		{&PreconditionKeyValueHashMustEqual{Key: []byte("key1"), Hash: sha256.Sum256([]byte("1")), ValuePrefix: []byte("key")}, false},
+	} {
+		tx, err := st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Set([]byte("key1"), nil, []byte("value3"))
+		require.NoError(t, err)
+
+		err = tx.AddPrecondition(c.precondition)
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		if c.satisfied {
+			require.NoError(t, err)
+
+			// restore the value so to keep checking against it
+			tx, err = st.NewWriteOnlyTx(context.Background())
+			require.NoError(t, err)
+
+			err = tx.Set([]byte("key1"), nil, []byte("value1"))
+			require.NoError(t, err)
+
+			_, err = tx.Commit(context.Background())
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, ErrPreconditionFailed)
+		}
+	}
+}
+
+func TestOngoingTxIncrement(t *testing.T) {
+	st, err := Open(t.TempDir(), DefaultOptions())
+	require.NoError(t, err)
+
+	defer immustoreClose(t, st)
+
+	counterValue := func(t *testing.T, key string) int64 {
+		valRef, err := st.Get(context.Background(), []byte(key))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Len(t, val, 8)
+
+		return int64(binary.BigEndian.Uint64(val))
+	}
+
+	t.Run("increment a non-existent key", func(t *testing.T) {
+		tx, err := st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Increment([]byte("counter1"), nil, 5)
+		require.NoError(t, err)
+
+		err = tx.Increment([]byte("counter1"), nil, -2)
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, int64(3), counterValue(t, "counter1"))
+	})
+
+	t.Run("increment a key set within the same transaction", func(t *testing.T) {
+		tx, err := st.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, 10)
+
+		err = tx.Set([]byte("counter2"), nil, value)
+		require.NoError(t, err)
+
+		err = tx.Increment([]byte("counter2"), nil, 1)
+		require.NoError(t, err)
+
+		// the value is already known within the transaction
+		valRef, err := tx.Get(context.Background(), []byte("counter2"))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), binary.BigEndian.Uint64(val))
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, int64(11), counterValue(t, "counter2"))
+	})
+
+	t.Run("pending increments can not be read within the transaction", func(t *testing.T) {
+		tx, err := st.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		err = tx.Increment([]byte("counter1"), nil, 1)
+		require.NoError(t, err)
+
+		valRef, err := tx.Get(context.Background(), []byte("counter1"))
+		require.NoError(t, err)
+
+		_, err = valRef.Resolve()
+		require.ErrorIs(t, err, ErrValueNotYetResolved)
+	})
+
+	t.Run("concurrent increments should not conflict", func(t *testing.T) {
+		tx1, err := st.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		tx2, err := st.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		err = tx1.Increment([]byte("counter1"), nil, 10)
+		require.NoError(t, err)
+
+		err = tx2.Increment([]byte("counter1"), nil, 100)
+		require.NoError(t, err)
+
+		_, err = tx1.Commit(context.Background())
+		require.NoError(t, err)
+
+		_, err = tx2.Commit(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, int64(113), counterValue(t, "counter1"))
+	})
+
+	t.Run("increment a deleted key", func(t *testing.T) {
+		tx, err := st.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		err = tx.Delete(context.Background(), []byte("counter2"))
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		tx, err = st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Increment([]byte("counter2"), nil, 7)
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, int64(7), counterValue(t, "counter2"))
+	})
+
+	t.Run("increment a non-counter value", func(t *testing.T) {
+		tx, err := st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Set([]byte("key1"), nil, []byte("value1"))
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		tx, err = st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Increment([]byte("key1"), nil, 1)
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.ErrorIs(t, err, ErrInvalidCounterValue)
+	})
+
+	t.Run("counter overflow", func(t *testing.T) {
+		tx, err := st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Increment([]byte("counter1"), nil, math.MaxInt64)
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.ErrorIs(t, err, ErrCounterOverflow)
+
+		require.Equal(t, int64(113), counterValue(t, "counter1"))
+	})
+
+	t.Run("increments along with preconditions", func(t *testing.T) {
+		_, err := st.CommitWith(context.Background(), func(txID uint64, index KeyIndex) ([]*EntrySpec, []Precondition, error) {
+			return []*EntrySpec{{Key: []byte("counter1"), ValueFn: IncrementFn(-13)}},
+				[]Precondition{&PreconditionKeyMustExist{Key: []byte("counter1")}},
+				nil
+		}, true)
+		require.NoError(t, err)
+
+		require.Equal(t, int64(100), counterValue(t, "counter1"))
+	})
+}
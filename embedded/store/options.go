@@ -23,6 +23,7 @@ import (
 
 	"github.com/codenotary/immudb/embedded/ahtree"
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/multiapp"
 	"github.com/codenotary/immudb/embedded/cache"
 	"github.com/codenotary/immudb/embedded/logger"
@@ -138,6 +139,9 @@ type Options struct {
 
 	MultiIndexing bool
 
+	// Provider of the keys used to encrypt newly created files, no encryption is applied when nil
+	KeyProvider cryptoapp.KeyProvider
+
 	// options below are only set during initialization and stored as metadata
 	MaxTxEntries      int
 	MaxKeyLen         int
@@ -370,6 +374,12 @@ func (opts *Options) Validate() error {
 	if opts.logger == nil {
 		return fmt.Errorf("%w: invalid log", ErrInvalidOptions)
 	}
+	if opts.KeyProvider != nil && opts.CompressionFormat != appendable.NoCompression {
+		return fmt.Errorf("%w: compression can not be used along with encryption", ErrInvalidOptions)
+	}
+	if opts.KeyProvider != nil && opts.PreallocFiles {
+		return fmt.Errorf("%w: preallocation can not be used along with encryption", ErrInvalidOptions)
+	}
 
 	err := opts.IndexOpts.Validate()
 	if err != nil {
@@ -610,6 +620,11 @@ func (opts *Options) WithPreallocFiles(preallocFiles bool) *Options {
 	return opts
 }
 
+func (opts *Options) WithKeyProvider(keyProvider cryptoapp.KeyProvider) *Options {
+	opts.KeyProvider = keyProvider
+	return opts
+}
+
 func (opts *Options) WithIndexOptions(indexOptions *IndexOptions) *Options {
 	opts.IndexOpts = indexOptions
 	return opts
@@ -17,7 +17,9 @@ limitations under the License.
 package store
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 
 	"github.com/codenotary/immudb/embedded/tbtree"
@@ -123,3 +125,96 @@ func (cs *PreconditionKeyNotModifiedAfterTx) Check(ctx context.Context, idx KeyI
 
 	return valRef.Tx() <= cs.TxID, nil
 }
+
+type PreconditionKeyValueMustEqual struct {
+	Key   []byte
+	Value []byte
+}
+
+func (cs *PreconditionKeyValueMustEqual) String() string { return "KeyValueMustEqual" }
+
+func (cs *PreconditionKeyValueMustEqual) Validate(st *ImmuStore) error {
+	if len(cs.Key) == 0 {
+		return ErrInvalidPreconditionNullKey
+	}
+
+	if len(cs.Key) > st.maxKeyLen {
+		return ErrInvalidPreconditionMaxKeyLenExceeded
+	}
+
+	if len(cs.Value) > st.maxValueLen {
+		return ErrInvalidPreconditionMaxValueLenExceeded
+	}
+
+	return nil
+}
+
+func (cs *PreconditionKeyValueMustEqual) Check(ctx context.Context, idx KeyIndex) (bool, error) {
+	valRef, err := idx.Get(ctx, cs.Key)
+	if errors.Is(err, tbtree.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if valRef.Len() != uint32(len(cs.Value)) {
+		return false, nil
+	}
+
+	val, err := valRef.Resolve()
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(val, cs.Value), nil
+}
+
+type PreconditionKeyValueHashMustEqual struct {
+	Key  []byte
+	Hash [sha256.Size]byte
+
+	// ValuePrefix, when specified, must be found at the beginning of the current value
+	// and it's excluded from the hash, thus the value needs to be resolved
+	ValuePrefix []byte
+}
+
+func (cs *PreconditionKeyValueHashMustEqual) String() string { return "KeyValueHashMustEqual" }
+
+func (cs *PreconditionKeyValueHashMustEqual) Validate(st *ImmuStore) error {
+	if len(cs.Key) == 0 {
+		return ErrInvalidPreconditionNullKey
+	}
+
+	if len(cs.Key) > st.maxKeyLen {
+		return ErrInvalidPreconditionMaxKeyLenExceeded
+	}
+
+	return nil
+}
+
+func (cs *PreconditionKeyValueHashMustEqual) Check(ctx context.Context, idx KeyIndex) (bool, error) {
+	valRef, err := idx.Get(ctx, cs.Key)
+	if errors.Is(err, tbtree.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(cs.ValuePrefix) == 0 {
+		// value hash is kept in the index, thus the value does not need to be resolved
+		return valRef.HVal() == cs.Hash, nil
+	}
+
+	val, err := valRef.Resolve()
+	if err != nil {
+		return false, err
+	}
+
+	if !bytes.HasPrefix(val, cs.ValuePrefix) {
+		return false, nil
+	}
+
+	return sha256.Sum256(val[len(cs.ValuePrefix):]) == cs.Hash, nil
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/multiapp"
 	"github.com/codenotary/immudb/embedded/cache"
 	"github.com/codenotary/immudb/embedded/logger"
@@ -91,6 +92,8 @@ type Options struct {
 	maxValueSize int
 	fileSize     int
 
+	keyProvider cryptoapp.KeyProvider
+
 	appFactory AppFactoryFunc
 	appRemove  AppRemoveFunc
 	onFlush    OnFlushFunc
@@ -301,6 +304,11 @@ func (opts *Options) WithFileSize(fileSize int) *Options {
 	return opts
 }
 
+func (opts *Options) WithKeyProvider(keyProvider cryptoapp.KeyProvider) *Options {
+	opts.keyProvider = keyProvider
+	return opts
+}
+
 func (opts *Options) WithCompactionThld(compactionThld int) *Options {
 	opts.compactionThld = compactionThld
 	return opts
@@ -33,6 +33,7 @@ import (
 
 	"github.com/codenotary/immudb/embedded"
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/appendable/multiapp"
 	"github.com/codenotary/immudb/embedded/cache"
 	"github.com/codenotary/immudb/embedded/logger"
@@ -196,6 +197,7 @@ type TBtree struct {
 	cacheSize                  int
 	fileSize                   int
 	fileMode                   os.FileMode
+	keyProvider                cryptoapp.KeyProvider
 	maxKeySize                 int
 	maxValueSize               int
 	compactionThld             int
@@ -327,6 +329,7 @@ func Open(path string, opts *Options) (*TBtree, error) {
 		WithFileSize(opts.fileSize).
 		WithFileMode(opts.fileMode).
 		WithWriteBufferSize(opts.flushBufferSize).
+		WithKeyProvider(opts.keyProvider).
 		WithMetadata(metadata.Bytes())
 
 	appFactory := opts.appFactory
@@ -590,6 +593,7 @@ func OpenWith(path string, nLog, hLog, cLog appendable.Appendable, opts *Options
 		fileSize:                 opts.fileSize,
 		cacheSize:                opts.cacheSize,
 		fileMode:                 opts.fileMode,
+		keyProvider:              opts.keyProvider,
 		compactionThld:           opts.compactionThld,
 		delayDuringCompaction:    opts.delayDuringCompaction,
 		nodesLogMaxOpenedFiles:   opts.nodesLogMaxOpenedFiles,
@@ -724,6 +728,7 @@ func (t *TBtree) GetOptions() *Options {
 		WithReadOnly(t.readOnly).
 		WithFileMode(t.fileMode).
 		WithFileSize(t.fileSize).
+		WithKeyProvider(t.keyProvider).
 		WithMaxKeySize(t.maxKeySize).
 		WithMaxValueSize(t.maxValueSize).
 		WithLogger(t.logger).
@@ -1436,6 +1441,7 @@ func (t *TBtree) fullDump(snap *Snapshot, progressOutput writeProgressOutputFunc
 		WithFileSize(t.fileSize).
 		WithFileMode(t.fileMode).
 		WithWriteBufferSize(t.flushBufferSize).
+		WithKeyProvider(t.keyProvider).
 		WithMetadata(t.cLog.Metadata())
 
 	appendableOpts.WithFileExt("n")
@@ -38,7 +38,7 @@ func main() {
 
 	parallelIO := flag.Int("parallelIO", 1, "number of parallel IO")
 	fileSize := flag.Int("fileSize", 1<<26, "file size up to which a new ones are created")
-	cFormat := flag.String("compressionFormat", "no-compression", "one of: no-compression, flate, gzip, lzw, zlib")
+	cFormat := flag.String("compressionFormat", "no-compression", "one of: no-compression, flate, gzip, lzw, zlib, zstd, lz4, snappy")
 	cLevel := flag.String("compressionLevel", "best-speed", "one of: best-speed, best-compression, default-compression, huffman-only")
 
 	synced := flag.Bool("synced", false, "strict sync mode - no data lost")
@@ -82,6 +82,12 @@ func main() {
 		compressionFormat = appendable.LZWCompression
 	case "zlib":
 		compressionFormat = appendable.ZLibCompression
+	case "zstd":
+		compressionFormat = appendable.ZstdCompression
+	case "lz4":
+		compressionFormat = appendable.LZ4Compression
+	case "snappy":
+		compressionFormat = appendable.SnappyCompression
 	default:
 		panic("invalid compression format")
 	}
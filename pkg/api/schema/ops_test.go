@@ -59,6 +59,31 @@ func TestOps_ValidateErrDuplicatedKeysNotSupported(t *testing.T) {
 
 }
 
+func TestOps_ValidateErrDuplicatedIncrementKeysNotSupported(t *testing.T) {
+	aOps := &ExecAllRequest{
+		Operations: []*Op{
+			{
+				Operation: &Op_Kv{
+					Kv: &KeyValue{
+						Key:   []byte(`key`),
+						Value: []byte(`val`),
+					},
+				},
+			},
+			{
+				Operation: &Op_Increment{
+					Increment: &IncrementRequest{
+						Key:   []byte(`key`),
+						Delta: 1,
+					},
+				},
+			},
+		},
+	}
+	err := aOps.Validate()
+	require.ErrorIs(t, err, ErrDuplicatedKeysNotSupported)
+}
+
 func TestOps_ValidateErrDuplicateZAddNotSupported(t *testing.T) {
 	aOps := &ExecAllRequest{
 		Operations: []*Op{
@@ -46,3 +46,25 @@ func PreconditionKeyNotModifiedAfterTX(key []byte, txID uint64) *Precondition {
 		},
 	}
 }
+
+func PreconditionKeyValueMustEqual(key, value []byte) *Precondition {
+	return &Precondition{
+		Precondition: &Precondition_KeyValueMustEqual{
+			KeyValueMustEqual: &Precondition_KeyValueMustEqualPrecondition{
+				Key:   key,
+				Value: value,
+			},
+		},
+	}
+}
+
+func PreconditionKeyValueHashMustEqual(key, hash []byte) *Precondition {
+	return &Precondition{
+		Precondition: &Precondition_KeyValueHashMustEqual{
+			KeyValueHashMustEqual: &Precondition_KeyValueHashMustEqualPrecondition{
+				Key:  key,
+				Hash: hash,
+			},
+		},
+	}
+}
@@ -46,6 +46,7 @@ type ImmuServiceClient interface {
 	Get(ctx context.Context, in *KeyRequest, opts ...grpc.CallOption) (*Entry, error)
 	VerifiableGet(ctx context.Context, in *VerifiableGetRequest, opts ...grpc.CallOption) (*VerifiableEntry, error)
 	Delete(ctx context.Context, in *DeleteKeysRequest, opts ...grpc.CallOption) (*TxHeader, error)
+	DeleteRange(ctx context.Context, in *DeleteRangeRequest, opts ...grpc.CallOption) (*DeleteRangeResponse, error)
 	GetAll(ctx context.Context, in *KeyListRequest, opts ...grpc.CallOption) (*Entries, error)
 	ExecAll(ctx context.Context, in *ExecAllRequest, opts ...grpc.CallOption) (*TxHeader, error)
 	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*Entries, error)
@@ -115,6 +116,7 @@ type ImmuServiceClient interface {
 	DescribeTable(ctx context.Context, in *Table, opts ...grpc.CallOption) (*SQLQueryResult, error)
 	VerifiableSQLGet(ctx context.Context, in *VerifiableSQLGetRequest, opts ...grpc.CallOption) (*VerifiableSQLEntry, error)
 	TruncateDatabase(ctx context.Context, in *TruncateDatabaseRequest, opts ...grpc.CallOption) (*TruncateDatabaseResponse, error)
+	SubscribeTxs(ctx context.Context, in *SubscribeTxsRequest, opts ...grpc.CallOption) (ImmuService_SubscribeTxsClient, error)
 }
 
 type immuServiceClient struct {
@@ -359,6 +361,15 @@ func (c *immuServiceClient) Delete(ctx context.Context, in *DeleteKeysRequest, o
 	return out, nil
 }
 
+func (c *immuServiceClient) DeleteRange(ctx context.Context, in *DeleteRangeRequest, opts ...grpc.CallOption) (*DeleteRangeResponse, error) {
+	out := new(DeleteRangeResponse)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/DeleteRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) GetAll(ctx context.Context, in *KeyListRequest, opts ...grpc.CallOption) (*Entries, error) {
 	out := new(Entries)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetAll", in, out, opts...)
@@ -1106,6 +1117,38 @@ func (c *immuServiceClient) TruncateDatabase(ctx context.Context, in *TruncateDa
 	return out, nil
 }
 
+func (c *immuServiceClient) SubscribeTxs(ctx context.Context, in *SubscribeTxsRequest, opts ...grpc.CallOption) (ImmuService_SubscribeTxsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ImmuService_ServiceDesc.Streams[13], "/immudb.schema.ImmuService/SubscribeTxs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &immuServiceSubscribeTxsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ImmuService_SubscribeTxsClient interface {
+	Recv() (*VerifiableTx, error)
+	grpc.ClientStream
+}
+
+type immuServiceSubscribeTxsClient struct {
+	grpc.ClientStream
+}
+
+func (x *immuServiceSubscribeTxsClient) Recv() (*VerifiableTx, error) {
+	m := new(VerifiableTx)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ImmuServiceServer is the server API for ImmuService service.
 // All implementations should embed UnimplementedImmuServiceServer
 // for forward compatibility
@@ -1137,6 +1180,7 @@ type ImmuServiceServer interface {
 	Get(context.Context, *KeyRequest) (*Entry, error)
 	VerifiableGet(context.Context, *VerifiableGetRequest) (*VerifiableEntry, error)
 	Delete(context.Context, *DeleteKeysRequest) (*TxHeader, error)
+	DeleteRange(context.Context, *DeleteRangeRequest) (*DeleteRangeResponse, error)
 	GetAll(context.Context, *KeyListRequest) (*Entries, error)
 	ExecAll(context.Context, *ExecAllRequest) (*TxHeader, error)
 	Scan(context.Context, *ScanRequest) (*Entries, error)
@@ -1206,6 +1250,7 @@ type ImmuServiceServer interface {
 	DescribeTable(context.Context, *Table) (*SQLQueryResult, error)
 	VerifiableSQLGet(context.Context, *VerifiableSQLGetRequest) (*VerifiableSQLEntry, error)
 	TruncateDatabase(context.Context, *TruncateDatabaseRequest) (*TruncateDatabaseResponse, error)
+	SubscribeTxs(*SubscribeTxsRequest, ImmuService_SubscribeTxsServer) error
 }
 
 // UnimplementedImmuServiceServer should be embedded to have forward compatible implementations.
@@ -1281,6 +1326,9 @@ func (UnimplementedImmuServiceServer) VerifiableGet(context.Context, *Verifiable
 func (UnimplementedImmuServiceServer) Delete(context.Context, *DeleteKeysRequest) (*TxHeader, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedImmuServiceServer) DeleteRange(context.Context, *DeleteRangeRequest) (*DeleteRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRange not implemented")
+}
 func (UnimplementedImmuServiceServer) GetAll(context.Context, *KeyListRequest) (*Entries, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAll not implemented")
 }
@@ -1434,6 +1482,9 @@ func (UnimplementedImmuServiceServer) VerifiableSQLGet(context.Context, *Verifia
 func (UnimplementedImmuServiceServer) TruncateDatabase(context.Context, *TruncateDatabaseRequest) (*TruncateDatabaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TruncateDatabase not implemented")
 }
+func (UnimplementedImmuServiceServer) SubscribeTxs(*SubscribeTxsRequest, ImmuService_SubscribeTxsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTxs not implemented")
+}
 
 // UnsafeImmuServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ImmuServiceServer will
@@ -1863,6 +1914,24 @@ func _ImmuService_Delete_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ImmuService_DeleteRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).DeleteRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/DeleteRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).DeleteRange(ctx, req.(*DeleteRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ImmuService_GetAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(KeyListRequest)
 	if err := dec(in); err != nil {
@@ -2842,6 +2911,27 @@ func _ImmuService_TruncateDatabase_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ImmuService_SubscribeTxs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTxsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImmuServiceServer).SubscribeTxs(m, &immuServiceSubscribeTxsServer{stream})
+}
+
+type ImmuService_SubscribeTxsServer interface {
+	Send(*VerifiableTx) error
+	grpc.ServerStream
+}
+
+type immuServiceSubscribeTxsServer struct {
+	grpc.ServerStream
+}
+
+func (x *immuServiceSubscribeTxsServer) Send(m *VerifiableTx) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ImmuService_ServiceDesc is the grpc.ServiceDesc for ImmuService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -2937,6 +3027,10 @@ var ImmuService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ImmuService_Delete_Handler,
 		},
+		{
+			MethodName: "DeleteRange",
+			Handler:    _ImmuService_DeleteRange_Handler,
+		},
 		{
 			MethodName: "GetAll",
 			Handler:    _ImmuService_GetAll_Handler,
@@ -3161,6 +3255,11 @@ var ImmuService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ImmuService_SQLQuery_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeTxs",
+			Handler:       _ImmuService_SubscribeTxs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "schema.proto",
 }
@@ -152,8 +152,14 @@ func TypedValueToRowValue(tv sql.TypedValue) *SQLValue {
 		{
 			return &SQLValue{Value: &SQLValue_F{F: tv.RawValue().(float64)}}
 		}
+	case sql.DecimalType, sql.IntervalType:
+		return &SQLValue{Value: &SQLValue_S{S: tv.String()}}
 	case sql.JSONType:
 		return &SQLValue{Value: &SQLValue_S{S: tv.String()}}
 	}
+
+	if arr, ok := tv.(*sql.Array); ok {
+		return &SQLValue{Value: &SQLValue_S{S: arr.Text()}}
+	}
 	return nil
 }
@@ -84,6 +84,7 @@ var methodsPermissions = map[string][]uint32{
 	// readwrite methods
 	"Set":                    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
 	"Delete":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"DeleteRange":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
 	"VerifiableSet":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
 	"StreamSet":              {PermissionSysAdmin, PermissionAdmin, PermissionRW},
 	"StreamVerifiableSet":    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
@@ -108,6 +109,7 @@ var methodsPermissions = map[string][]uint32{
 	"StreamHistory":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
 	"TxByID":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
 	"TxScan":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SubscribeTxs":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
 	"Count":                  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
 	"CountAll":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
 	"DatabaseList":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
@@ -402,6 +402,14 @@ type ImmuClient interface {
 	// TxScan returns raw entries for a range of transactions.
 	TxScan(ctx context.Context, req *schema.TxScanRequest) (*schema.TxList, error)
 
+	// SubscribeTxs opens a stream receiving committed transactions as they land.
+	//
+	// Only transactions with entries matching any of the given key prefixes, SQL tables or document
+	// collections are sent, all of them if no filter is given. When proofs are requested, each
+	// transaction comes with a dual proof against the previously sent one (or req.ProveSinceTx for the first).
+	// The stream is closed by cancelling the context.
+	SubscribeTxs(ctx context.Context, req *schema.SubscribeTxsRequest) (schema.ImmuService_SubscribeTxsClient, error)
+
 	// Count returns count of key-value entries with given prefix.
 	//
 	// Note: This feature is not implemented yet.
@@ -421,6 +429,10 @@ type ImmuClient interface {
 	// Delete performs a logical deletion for a list of keys marking them as deleted.
 	Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*schema.TxHeader, error)
 
+	// DeleteRange performs a logical deletion of all the keys matching a prefix and/or a range,
+	// splitting the deletion into several transactions if needed.
+	DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error)
+
 	// ExecAll performs multiple write operations (values, references, sorted set entries)
 	// in a single transaction.
 	ExecAll(ctx context.Context, in *schema.ExecAllRequest) (*schema.TxHeader, error)
@@ -1489,6 +1501,16 @@ func (c *immuClient) Delete(ctx context.Context, req *schema.DeleteKeysRequest)
 	return c.ServiceClient.Delete(ctx, req)
 }
 
+// DeleteRange performs a logical deletion of all the keys matching a prefix and/or a range,
+// splitting the deletion into several transactions if needed.
+func (c *immuClient) DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error) {
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	return c.ServiceClient.DeleteRange(ctx, req)
+}
+
 // TxByID retrieves all entries (in a raw, unprocessed form) for given transaction.
 //
 // Note: In order to read keys and values, it is necessary to parse returned entries
@@ -1615,6 +1637,19 @@ func (c *immuClient) TxScan(ctx context.Context, req *schema.TxScanRequest) (*sc
 	return c.ServiceClient.TxScan(ctx, req)
 }
 
+// SubscribeTxs opens a stream receiving committed transactions as they land.
+func (c *immuClient) SubscribeTxs(ctx context.Context, req *schema.SubscribeTxsRequest) (schema.ImmuService_SubscribeTxsClient, error) {
+	if req == nil {
+		return nil, errors.FromError(ErrIllegalArguments)
+	}
+
+	if !c.IsConnected() {
+		return nil, errors.FromError(ErrNotConnected)
+	}
+
+	return c.ServiceClient.SubscribeTxs(ctx, req)
+}
+
 // History returns history for a single key.
 func (c *immuClient) History(ctx context.Context, req *schema.HistoryRequest) (sl *schema.Entries, err error) {
 	if !c.IsConnected() {
@@ -80,6 +80,21 @@ func (d *db) ExecAll(ctx context.Context, req *schema.ExecAllRequest) (*schema.T
 					x.Kv.Value,
 				)
 
+			case *schema.Op_Increment:
+
+				kmap[sha256.Sum256(x.Increment.Key)] = true
+
+				if len(x.Increment.Key) == 0 {
+					return nil, nil, store.ErrIllegalArguments
+				}
+
+				// the value is computed at commit time over the latest value of the key
+				e = EncodeIncrement(
+					x.Increment.Key,
+					schema.KVMetadataFromProto(x.Increment.Metadata),
+					x.Increment.Delta,
+				)
+
 			case *schema.Op_Ref:
 				if len(x.Ref.Key) == 0 || len(x.Ref.ReferencedKey) == 0 {
 					return nil, nil, store.ErrIllegalArguments
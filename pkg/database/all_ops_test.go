@@ -18,6 +18,8 @@ package database
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -1330,6 +1332,175 @@ func TestOps_Preconditions(t *testing.T) {
 
 }
 
+func TestOps_ValuePreconditions(t *testing.T) {
+	db := makeDb(t)
+
+	setOp := func(value string) []*schema.Op {
+		return []*schema.Op{{
+			Operation: &schema.Op_Kv{
+				Kv: &schema.KeyValue{
+					Key:   []byte("config"),
+					Value: []byte(value),
+				},
+			},
+		}}
+	}
+
+	hdr, err := db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v1"),
+	})
+	require.NoError(t, err)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v2"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueMustEqual([]byte("config"), []byte("v0")),
+		},
+	})
+	require.ErrorIs(t, err, store.ErrPreconditionFailed)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v2"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueMustEqual([]byte("config"), []byte("v1")),
+		},
+	})
+	require.NoError(t, err)
+
+	// the hash is computed over the value as it was set,
+	// not over its internal encoding as the one exposed in transaction entries
+	tx, err := db.TxByID(context.Background(), &schema.TxRequest{Tx: hdr.Id + 1})
+	require.NoError(t, err)
+	require.Len(t, tx.Entries, 1)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v3"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueHashMustEqual([]byte("config"), tx.Entries[0].HValue),
+		},
+	})
+	require.ErrorIs(t, err, store.ErrPreconditionFailed)
+
+	v1Hash := sha256.Sum256([]byte("v1"))
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v3"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueHashMustEqual([]byte("config"), v1Hash[:]),
+		},
+	})
+	require.ErrorIs(t, err, store.ErrPreconditionFailed)
+
+	v2Hash := sha256.Sum256([]byte("v2"))
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v3"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueHashMustEqual([]byte("config"), v2Hash[:]),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v3"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueHashMustEqual([]byte("config"), []byte{1, 2, 3}),
+		},
+	})
+	require.ErrorIs(t, err, store.ErrInvalidPreconditionInvalidHash)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: setOp("v3"),
+		Preconditions: []*schema.Precondition{
+			schema.PreconditionKeyValueMustEqual(nil, []byte("v2")),
+		},
+	})
+	require.ErrorIs(t, err, store.ErrInvalidPreconditionNullKey)
+}
+
+func TestOps_Increment(t *testing.T) {
+	db := makeDb(t)
+
+	incrementOp := func(key string, delta int64) *schema.Op {
+		return &schema.Op{
+			Operation: &schema.Op_Increment{
+				Increment: &schema.IncrementRequest{
+					Key:   []byte(key),
+					Delta: delta,
+				},
+			},
+		}
+	}
+
+	counterValue := func(key string) int64 {
+		entry, err := db.Get(context.Background(), &schema.KeyRequest{Key: []byte(key)})
+		require.NoError(t, err)
+		require.Len(t, entry.Value, 8)
+
+		return int64(binary.BigEndian.Uint64(entry.Value))
+	}
+
+	_, err := db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: []*schema.Op{incrementOp("hits", 3), incrementOp("misses", -1)},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(3), counterValue("hits"))
+	require.Equal(t, int64(-1), counterValue("misses"))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := db.ExecAll(context.Background(), &schema.ExecAllRequest{
+				Operations: []*schema.Op{incrementOp("hits", 2)},
+				NoWait:     true,
+			})
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int64(23), counterValue("hits"))
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: []*schema.Op{incrementOp("", 1)},
+	})
+	require.ErrorIs(t, err, store.ErrIllegalArguments)
+
+	_, err = db.Set(context.Background(), &schema.SetRequest{
+		KVs: []*schema.KeyValue{{Key: []byte("name"), Value: []byte("immudb")}},
+	})
+	require.NoError(t, err)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: []*schema.Op{incrementOp("name", 1)},
+	})
+	require.ErrorIs(t, err, store.ErrInvalidCounterValue)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: []*schema.Op{{
+			Operation: &schema.Op_Ref{
+				Ref: &schema.ReferenceRequest{
+					Key:           []byte("hitsRef"),
+					ReferencedKey: []byte("hits"),
+				},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = db.ExecAll(context.Background(), &schema.ExecAllRequest{
+		Operations: []*schema.Op{incrementOp("hitsRef", 1)},
+	})
+	require.ErrorIs(t, err, store.ErrInvalidCounterValue)
+}
+
 /*
 func TestOps_ReferenceKeyNotYetPersisted(t *testing.T) {
 	st, closer := makeStore()
@@ -87,6 +87,7 @@ type DB interface {
 	GetAll(ctx context.Context, req *schema.KeyListRequest) (*schema.Entries, error)
 
 	Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*schema.TxHeader, error)
+	DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error)
 
 	SetReference(ctx context.Context, req *schema.ReferenceRequest) (*schema.TxHeader, error)
 	VerifiableSetReference(ctx context.Context, req *schema.VerifiableReferenceRequest) (*schema.VerifiableTx, error)
@@ -136,6 +137,7 @@ type DB interface {
 
 	VerifiableTxByID(ctx context.Context, req *schema.VerifiableTxRequest) (*schema.VerifiableTx, error)
 	TxScan(ctx context.Context, req *schema.TxScanRequest) (*schema.TxList, error)
+	SubscribeTxs(ctx context.Context, req *schema.SubscribeTxsRequest, send func(*schema.VerifiableTx) error) error
 
 	// Truncation
 	FindTruncationPoint(ctx context.Context, until time.Time) (*schema.TxHeader, error)
@@ -41,6 +41,7 @@ type DBManager struct {
 
 	mtx      sync.Mutex
 	waitCond *sync.Cond
+	waiting  int
 
 	closed bool
 }
@@ -227,10 +228,21 @@ func (m *DBManager) allocDB(idx int, db *dbInfo) (*dbRef, error) {
 		}
 
 		db.mtx.Unlock()
+
+		m.waiting++
 		m.waitCond.Wait()
+		m.waiting--
 	}
 }
 
+// hasWaiters returns true if any caller is waiting for a database to be released in order to acquire another one
+func (m *DBManager) hasWaiters() bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.waiting > 0
+}
+
 func (m *DBManager) Release(idx int) {
 	v, err := m.dbCache.Get(idx)
 
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// DeleteRange logically deletes all the live keys matching the given prefix and range.
+// Keys are deleted in as many transactions as required to honor the maximum number of
+// entries per transaction, previous values remain accessible through the history of each key.
+func (d *db) DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error) {
+	if req == nil || (len(req.Prefix) == 0 && len(req.StartKey) == 0 && len(req.EndKey) == 0) {
+		return nil, ErrIllegalArguments
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.isReplica() {
+		return nil, ErrIsReplica
+	}
+
+	if req.SinceTx > d.st.LastPrecommittedTxID() {
+		return nil, store.ErrTxNotFound
+	}
+
+	// each deletion adds an entry to both the transaction and its MVCC read-set
+	chunkSize := d.st.MaxTxEntries()
+	if d.st.MVCCReadSetLimit() < chunkSize {
+		chunkSize = d.st.MVCCReadSetLimit()
+	}
+
+	var seekKey, endKey []byte
+
+	if len(req.StartKey) > 0 {
+		seekKey = EncodeKey(req.StartKey)
+	}
+
+	if len(req.EndKey) > 0 {
+		endKey = EncodeKey(req.EndKey)
+	}
+
+	res := &schema.DeleteRangeResponse{}
+
+	sinceTx := req.SinceTx
+	inclusiveSeek := true
+
+	for {
+		keys, err := d.readKeys(ctx, store.KeyReaderSpec{
+			SeekKey:       seekKey,
+			EndKey:        endKey,
+			Prefix:        EncodeKey(req.Prefix),
+			Filters:       []store.FilterFn{store.IgnoreExpired, store.IgnoreDeleted},
+			InclusiveSeek: inclusiveSeek,
+		}, sinceTx, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(keys) == 0 {
+			break
+		}
+
+		lastChunk := len(keys) < chunkSize
+
+		hdr, deleted, err := d.deleteKeys(ctx, keys, sinceTx, lastChunk && req.NoWait)
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr != nil {
+			res.Txs = append(res.Txs, schema.TxHeaderToProto(hdr))
+			res.DeletedKeys += uint64(deleted)
+
+			sinceTx = hdr.ID
+		}
+
+		if lastChunk {
+			break
+		}
+
+		seekKey = keys[len(keys)-1]
+		inclusiveSeek = false
+	}
+
+	return res, nil
+}
+
+func (d *db) readKeys(ctx context.Context, spec store.KeyReaderSpec, sinceTx uint64, limit int) ([][]byte, error) {
+	snap, err := d.snapshotSince(ctx, []byte{SetKeyPrefix}, sinceTx)
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Close()
+
+	r, err := snap.NewKeyReader(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var keys [][]byte
+
+	for len(keys) < limit {
+		key, _, err := r.Read(ctx)
+		if errors.Is(err, store.ErrNoMoreEntries) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (d *db) deleteKeys(ctx context.Context, keys [][]byte, sinceTx uint64, noWait bool) (*store.TxHeader, int, error) {
+	opts := store.DefaultTxOptions()
+
+	if sinceTx > 0 {
+		opts.WithSnapshotMustIncludeTxID(func(_ uint64) uint64 {
+			return sinceTx
+		})
+	}
+
+	tx, err := d.newTx(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Cancel()
+
+	deleted := 0
+
+	for _, key := range keys {
+		err = tx.Delete(ctx, key)
+		if errors.Is(err, store.ErrKeyNotFound) {
+			continue // key deleted or expired in the meantime
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		deleted++
+	}
+
+	if deleted == 0 {
+		return nil, 0, nil
+	}
+
+	var hdr *store.TxHeader
+	if noWait {
+		hdr, err = tx.AsyncCommit(ctx)
+	} else {
+		hdr, err = tx.Commit(ctx)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hdr, deleted, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRange(t *testing.T) {
+	options := DefaultOptions().WithDBRootPath(t.TempDir())
+	options.storeOpts.WithMaxTxEntries(5)
+
+	db := makeDbWith(t, "db", options)
+
+	ctx := context.Background()
+
+	_, err := db.DeleteRange(ctx, nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = db.DeleteRange(ctx, &schema.DeleteRangeRequest{})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	for _, prefix := range []string{"tenant1", "tenant2"} {
+		for i := 0; i < 12; i++ {
+			_, err := db.Set(ctx, &schema.SetRequest{
+				KVs: []*schema.KeyValue{
+					{
+						Key:   []byte(fmt.Sprintf("%s/key%02d", prefix, i)),
+						Value: []byte(fmt.Sprintf("value%02d", i)),
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("deletion with invalid indexing spec should return an error", func(t *testing.T) {
+		_, err = db.DeleteRange(ctx, &schema.DeleteRangeRequest{
+			Prefix:  []byte("tenant1/"),
+			SinceTx: db.st.LastPrecommittedTxID() + 1,
+		})
+		require.ErrorIs(t, err, store.ErrTxNotFound)
+	})
+
+	t.Run("prefix deletion should be split into several transactions", func(t *testing.T) {
+		res, err := db.DeleteRange(ctx, &schema.DeleteRangeRequest{
+			Prefix: []byte("tenant1/"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(12), res.DeletedKeys)
+		require.Len(t, res.Txs, 3)
+
+		for _, hdr := range res.Txs {
+			require.LessOrEqual(t, hdr.Nentries, int32(5))
+		}
+
+		entries, err := db.Scan(ctx, &schema.ScanRequest{Prefix: []byte("tenant1/")})
+		require.NoError(t, err)
+		require.Empty(t, entries.Entries)
+
+		entries, err = db.Scan(ctx, &schema.ScanRequest{Prefix: []byte("tenant2/")})
+		require.NoError(t, err)
+		require.Len(t, entries.Entries, 12)
+	})
+
+	t.Run("history should include values prior to deletion", func(t *testing.T) {
+		entries, err := db.History(ctx, &schema.HistoryRequest{Key: []byte("tenant1/key07")})
+		require.NoError(t, err)
+		require.Len(t, entries.Entries, 2)
+		require.Equal(t, []byte("value07"), entries.Entries[0].Value)
+		require.True(t, entries.Entries[1].Metadata.Deleted)
+
+		_, err = db.Get(ctx, &schema.KeyRequest{Key: []byte("tenant1/key07")})
+		require.ErrorIs(t, err, store.ErrKeyNotFound)
+	})
+
+	t.Run("deleting an already deleted prefix should be a no-op", func(t *testing.T) {
+		res, err := db.DeleteRange(ctx, &schema.DeleteRangeRequest{
+			Prefix: []byte("tenant1/"),
+		})
+		require.NoError(t, err)
+		require.Zero(t, res.DeletedKeys)
+		require.Empty(t, res.Txs)
+	})
+
+	t.Run("range deletion should only delete keys within the range", func(t *testing.T) {
+		res, err := db.DeleteRange(ctx, &schema.DeleteRangeRequest{
+			Prefix:   []byte("tenant2/"),
+			StartKey: []byte("tenant2/key03"),
+			EndKey:   []byte("tenant2/key08"),
+			NoWait:   true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), res.DeletedKeys)
+		require.Len(t, res.Txs, 1)
+
+		err = db.WaitForIndexingUpto(ctx, res.Txs[len(res.Txs)-1].Id)
+		require.NoError(t, err)
+
+		entries, err := db.Scan(ctx, &schema.ScanRequest{Prefix: []byte("tenant2/")})
+		require.NoError(t, err)
+		require.Len(t, entries.Entries, 7)
+
+		for _, e := range entries.Entries {
+			require.False(t, string(e.Key) >= "tenant2/key03" && string(e.Key) < "tenant2/key08")
+		}
+	})
+
+	t.Run("deletion on a replica should fail", func(t *testing.T) {
+		replicaOpts := DefaultOptions().WithDBRootPath(t.TempDir()).AsReplica(true)
+		replica := makeDbWith(t, "replica", replicaOpts)
+
+		_, err := replica.DeleteRange(ctx, &schema.DeleteRangeRequest{Prefix: []byte("tenant2/")})
+		require.ErrorIs(t, err, ErrIsReplica)
+	})
+}
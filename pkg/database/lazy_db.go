@@ -194,6 +194,16 @@ func (db *lazyDB) Delete(ctx context.Context, req *schema.DeleteKeysRequest) (*s
 	return d.Delete(ctx, req)
 }
 
+func (db *lazyDB) DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error) {
+	d, err := db.m.Get(db.idx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.m.Release(db.idx)
+
+	return d.DeleteRange(ctx, req)
+}
+
 func (db *lazyDB) SetReference(ctx context.Context, req *schema.ReferenceRequest) (*schema.TxHeader, error) {
 	d, err := db.m.Get(db.idx)
 	if err != nil {
@@ -505,6 +515,63 @@ func (db *lazyDB) TxScan(ctx context.Context, req *schema.TxScanRequest) (*schem
 	return d.TxScan(ctx, req)
 }
 
+// SubscribeTxs acquires the database for each batch of transactions rather than for the whole subscription,
+// so that a long-lived subscription does not prevent the database from being released while idle
+func (db *lazyDB) SubscribeTxs(ctx context.Context, req *schema.SubscribeTxsRequest, send func(*schema.VerifiableTx) error) error {
+	d, err := db.m.Get(db.idx)
+	if err != nil {
+		return err
+	}
+
+	subscriber, ok := d.(txSubscriber)
+	if !ok {
+		defer db.m.Release(db.idx)
+
+		return d.SubscribeTxs(ctx, req, send)
+	}
+
+	sub, err := subscriber.newTxSubscription(ctx, req)
+	db.m.Release(db.idx)
+	if err != nil {
+		return err
+	}
+
+	if send == nil {
+		return ErrIllegalArguments
+	}
+
+	for {
+		err := db.sendSubscribedTxs(ctx, sub, send)
+		if err != nil {
+			return err
+		}
+
+		// give way to the callers waiting for the database to be released
+		if db.m.hasWaiters() {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(subscriptionMaxWait):
+			}
+		}
+	}
+}
+
+func (db *lazyDB) sendSubscribedTxs(ctx context.Context, sub *txSubscription, send func(*schema.VerifiableTx) error) error {
+	d, err := db.m.Get(db.idx)
+	if err != nil {
+		return err
+	}
+	defer db.m.Release(db.idx)
+
+	subscriber, ok := d.(txSubscriber)
+	if !ok {
+		return ErrIllegalState
+	}
+
+	return subscriber.sendSubscribedTxs(ctx, sub, send, subscriptionMaxWait)
+}
+
 func (db *lazyDB) FlushIndex(req *schema.FlushIndexRequest) error {
 	d, err := db.m.Get(db.idx)
 	if err != nil {
@@ -63,6 +63,37 @@ func EncodeEntrySpec(
 	}
 }
 
+// EncodeIncrement returns an entry whose value is computed at commit time
+// by adding delta to the counter held by the key
+func EncodeIncrement(
+	key []byte,
+	md *store.KVMetadata,
+	delta int64,
+) *store.EntrySpec {
+	incr := store.IncrementFn(delta)
+
+	return &store.EntrySpec{
+		Key:      WrapWithPrefix(key, SetKeyPrefix),
+		Metadata: md,
+		ValueFn: func(value []byte, found bool) ([]byte, error) {
+			if found {
+				if len(value) == 0 || value[0] != PlainValuePrefix {
+					return nil, store.ErrInvalidCounterValue
+				}
+
+				value = TrimPrefix(value)
+			}
+
+			counter, err := incr(value, found)
+			if err != nil {
+				return nil, err
+			}
+
+			return WrapWithPrefix(counter, PlainValuePrefix), nil
+		},
+	}
+}
+
 func EncodeReference(
 	key []byte,
 	md *store.KVMetadata,
@@ -17,6 +17,8 @@ limitations under the License.
 package database
 
 import (
+	"crypto/sha256"
+
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/pkg/api/schema"
 )
@@ -56,6 +58,37 @@ func PreconditionFromProto(c *schema.Precondition) (store.Precondition, error) {
 			Key:  EncodeKey(key),
 			TxID: c.KeyNotModifiedAfterTX.GetTxID(),
 		}, nil
+
+	case *schema.Precondition_KeyValueMustEqual:
+		key := c.KeyValueMustEqual.GetKey()
+		if len(key) == 0 {
+			return nil, store.ErrInvalidPreconditionNullKey
+		}
+
+		return &store.PreconditionKeyValueMustEqual{
+			Key:   EncodeKey(key),
+			Value: WrapWithPrefix(c.KeyValueMustEqual.GetValue(), PlainValuePrefix),
+		}, nil
+
+	case *schema.Precondition_KeyValueHashMustEqual:
+		key := c.KeyValueHashMustEqual.GetKey()
+		if len(key) == 0 {
+			return nil, store.ErrInvalidPreconditionNullKey
+		}
+
+		hash := c.KeyValueHashMustEqual.GetHash()
+		if len(hash) != sha256.Size {
+			return nil, store.ErrInvalidPreconditionInvalidHash
+		}
+
+		// the hash is computed over the value as it was set, i.e. without the prefix added by the database
+		cs := &store.PreconditionKeyValueHashMustEqual{
+			Key:         EncodeKey(key),
+			ValuePrefix: []byte{PlainValuePrefix},
+		}
+		copy(cs.Hash[:], hash)
+
+		return cs, nil
 	}
 
 	return nil, store.ErrInvalidPreconditionNull
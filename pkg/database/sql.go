@@ -266,16 +266,10 @@ func (d *db) DescribeTable(ctx context.Context, tx *sql.SQLTx, tableName string)
 			}
 		}
 
-		var maxLen string
-
-		if c.MaxLen() > 0 && (c.Type() == sql.VarcharType || c.Type() == sql.BLOBType) {
-			maxLen = fmt.Sprintf("(%d)", c.MaxLen())
-		}
-
 		res.Rows = append(res.Rows, &schema.Row{
 			Values: []*schema.SQLValue{
 				{Value: &schema.SQLValue_S{S: c.Name()}},
-				{Value: &schema.SQLValue_S{S: c.Type() + maxLen}},
+				{Value: &schema.SQLValue_S{S: c.TypeName()}},
 				{Value: &schema.SQLValue_B{B: c.IsNullable()}},
 				{Value: &schema.SQLValue_S{S: index}},
 				{Value: &schema.SQLValue_B{B: c.IsAutoIncremental()}},
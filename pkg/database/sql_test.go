@@ -100,6 +100,11 @@ func TestSQLExecAndQuery(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, rows, 3)
 
+	rows, err = db.SQLQueryAll(context.Background(), nil, &schema.SQLQueryRequest{Sql: "EXPLAIN " + q, Params: params, AcceptStream: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, "  -> scan table1 as t using index table1(id)", rows[2].ValuesByPosition[0].RawValue())
+
 	inferredParams, err := db.InferParameters(context.Background(), nil, q)
 	require.NoError(t, err)
 	require.Len(t, inferredParams, 1)
@@ -0,0 +1,291 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// subscriptionMaxWait bounds the time a database is held by a subscription while waiting for
+// or sending transactions, so that idle databases can be released in the meantime
+var subscriptionMaxWait = time.Second
+
+// txSubscription keeps track of the progress of a subscription,
+// so that it can be resumed after the database has been released
+type txSubscription struct {
+	req *schema.SubscribeTxsRequest
+
+	nextTxID     uint64
+	proveSinceTx uint64
+
+	prefixes [][]byte
+}
+
+// txSubscriber is implemented by databases which can send the transactions of a subscription in batches
+type txSubscriber interface {
+	newTxSubscription(ctx context.Context, req *schema.SubscribeTxsRequest) (*txSubscription, error)
+	sendSubscribedTxs(ctx context.Context, sub *txSubscription, send func(*schema.VerifiableTx) error, maxWait time.Duration) error
+}
+
+// SubscribeTxs sends committed transactions to the provided callback as they land,
+// starting from req.SinceTx (or from the next transaction to be committed when not set).
+// When filters are specified, only transactions with at least one entry
+// matching any of them are sent. Tables and collections are resolved again
+// whenever tables are created, dropped or renamed, the subscription ends
+// with an error if any of them no longer exists.
+// It blocks until the context is cancelled or send returns an error.
+func (d *db) SubscribeTxs(ctx context.Context, req *schema.SubscribeTxsRequest, send func(*schema.VerifiableTx) error) error {
+	sub, err := d.newTxSubscription(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if send == nil {
+		return ErrIllegalArguments
+	}
+
+	for {
+		err := d.sendSubscribedTxs(ctx, sub, send, 0)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (d *db) newTxSubscription(ctx context.Context, req *schema.SubscribeTxsRequest) (*txSubscription, error) {
+	if req == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	lastTxID, _ := d.st.CommittedAlh()
+	if lastTxID < req.ProveSinceTx {
+		return nil, fmt.Errorf("%w: latest txID=%d is lower than specified as initial tx=%d", ErrIllegalState, lastTxID, req.ProveSinceTx)
+	}
+
+	prefixes, err := d.subscriptionPrefixes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &txSubscription{
+		req:          req,
+		nextTxID:     req.SinceTx,
+		proveSinceTx: req.ProveSinceTx,
+		prefixes:     prefixes,
+	}
+
+	if sub.nextTxID == 0 {
+		sub.nextTxID = lastTxID + 1
+	}
+
+	return sub, nil
+}
+
+// sendSubscribedTxs waits for the next transaction of the subscription and sends it along with
+// the ones committed afterwards. When maxWait is set, it returns without error as soon as maxWait
+// has elapsed, either while waiting or while sending transactions.
+func (d *db) sendSubscribedTxs(ctx context.Context, sub *txSubscription, send func(*schema.VerifiableTx) error, maxWait time.Duration) error {
+	batchCtx := ctx
+
+	if maxWait > 0 {
+		var cancel context.CancelFunc
+
+		batchCtx, cancel = context.WithTimeout(ctx, maxWait)
+		defer cancel()
+	}
+
+	err := d.WaitForTx(batchCtx, sub.nextTxID, false)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.allocTx()
+	if err != nil {
+		return err
+	}
+	defer d.releaseTx(tx)
+
+	lastTxID, _ := d.st.CommittedAlh()
+
+	for ; sub.nextTxID <= lastTxID && batchCtx.Err() == nil; sub.nextTxID++ {
+		err = d.st.ReadTx(sub.nextTxID, false, tx)
+		if err != nil {
+			return err
+		}
+
+		if len(sub.req.Tables) > 0 || len(sub.req.Collections) > 0 {
+			if txMatchesAnyPrefix(tx, [][]byte{d.sqlEngine.TablesCatalogPrefix()}) {
+				// tables may have been dropped and created again
+				sub.prefixes, err = d.subscriptionPrefixes(ctx, sub.req)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if !txMatchesAnyPrefix(tx, sub.prefixes) {
+			continue
+		}
+
+		vtx, err := d.subscriptionTx(ctx, tx, sub)
+		if err != nil {
+			return err
+		}
+
+		err = send(vtx)
+		if err != nil {
+			return err
+		}
+
+		if sub.req.WithProofs && sub.nextTxID > sub.proveSinceTx {
+			// following proofs are built against the last sent transaction
+			sub.proveSinceTx = sub.nextTxID
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (d *db) subscriptionPrefixes(ctx context.Context, req *schema.SubscribeTxsRequest) ([][]byte, error) {
+	var prefixes [][]byte
+
+	for _, p := range req.Prefixes {
+		prefixes = append(prefixes, WrapWithPrefix(p, SetKeyPrefix))
+	}
+
+	for _, table := range req.Tables {
+		prefix, err := d.sqlEngine.TableRowsPrefix(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	for _, collection := range req.Collections {
+		prefix, err := d.documentEngine.CollectionPrefix(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+func txMatchesAnyPrefix(tx *store.Tx, prefixes [][]byte) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+
+	for _, e := range tx.Entries() {
+		if hasAnyPrefix(e.Key(), prefixes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasAnyPrefix(key []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *db) subscriptionTx(ctx context.Context, tx *store.Tx, sub *txSubscription) (*schema.VerifiableTx, error) {
+	var snap *store.Snapshot
+	var err error
+
+	if sub.req.EntriesSpec != nil {
+		snap, err = d.snapshotSince(ctx, []byte{SetKeyPrefix}, tx.Header().ID)
+		if err != nil {
+			return nil, err
+		}
+		defer snap.Close()
+	}
+
+	sTx, err := d.serializeTx(ctx, tx, sub.req.EntriesSpec, snap, true)
+	if err != nil {
+		return nil, err
+	}
+
+	vtx := &schema.VerifiableTx{Tx: sTx}
+
+	if sub.req.WithInclusionProofs {
+		for _, e := range tx.Entries() {
+			if len(sub.prefixes) > 0 && !hasAnyPrefix(e.Key(), sub.prefixes) {
+				continue
+			}
+
+			inclusionProof, err := tx.Proof(e.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			vtx.InclusionProofs = append(vtx.InclusionProofs, schema.InclusionProofToProto(inclusionProof))
+		}
+	}
+
+	if !sub.req.WithProofs {
+		return vtx, nil
+	}
+
+	var sourceTxHdr, targetTxHdr *store.TxHeader
+	var rootTxHdr *store.TxHeader
+
+	if sub.proveSinceTx == 0 {
+		rootTxHdr = tx.Header()
+	} else {
+		rootTxHdr, err = d.st.ReadTxHeader(sub.proveSinceTx, false, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if sub.proveSinceTx <= tx.Header().ID {
+		sourceTxHdr = rootTxHdr
+		targetTxHdr = tx.Header()
+	} else {
+		sourceTxHdr = tx.Header()
+		targetTxHdr = rootTxHdr
+	}
+
+	dualProof, err := d.st.DualProof(sourceTxHdr, targetTxHdr)
+	if err != nil {
+		return nil, err
+	}
+
+	vtx.DualProof = schema.DualProofToProto(dualProof)
+
+	return vtx, nil
+}
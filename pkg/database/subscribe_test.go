@@ -0,0 +1,311 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/document"
+	"github.com/codenotary/immudb/embedded/logger"
+	"github.com/codenotary/immudb/embedded/sql"
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/protomodel"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func subscribe(t *testing.T, db *db, req *schema.SubscribeTxsRequest) (<-chan *schema.VerifiableTx, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan *schema.VerifiableTx, 10)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- db.SubscribeTxs(ctx, req, func(vtx *schema.VerifiableTx) error {
+			ch <- vtx
+			return nil
+		})
+	}()
+
+	return ch, func() {
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	}
+}
+
+func receiveTx(t *testing.T, ch <-chan *schema.VerifiableTx) *schema.VerifiableTx {
+	select {
+	case vtx := <-ch:
+		return vtx
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no transaction received")
+		return nil
+	}
+}
+
+func TestSubscribeTxs(t *testing.T) {
+	db := makeDb(t)
+
+	err := db.SubscribeTxs(context.Background(), nil, func(vtx *schema.VerifiableTx) error { return nil })
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = db.SubscribeTxs(context.Background(), &schema.SubscribeTxsRequest{}, nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = db.SubscribeTxs(context.Background(), &schema.SubscribeTxsRequest{ProveSinceTx: 100}, func(vtx *schema.VerifiableTx) error { return nil })
+	require.ErrorIs(t, err, ErrIllegalState)
+
+	err = db.SubscribeTxs(context.Background(), &schema.SubscribeTxsRequest{Tables: []string{"unknown"}}, func(vtx *schema.VerifiableTx) error { return nil })
+	require.ErrorIs(t, err, sql.ErrTableDoesNotExist)
+
+	err = db.SubscribeTxs(context.Background(), &schema.SubscribeTxsRequest{Collections: []string{"unknown"}}, func(vtx *schema.VerifiableTx) error { return nil })
+	require.ErrorIs(t, err, document.ErrCollectionDoesNotExist)
+
+	_, _, err = db.SQLExec(context.Background(), nil, &schema.SQLExecRequest{Sql: "CREATE TABLE mytable(id INTEGER AUTO_INCREMENT, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	_, err = db.CreateCollection(context.Background(), "admin", &protomodel.CreateCollectionRequest{
+		Name:   "mycollection",
+		Fields: []*protomodel.Field{{Name: "name", Type: protomodel.FieldType_STRING}},
+	})
+	require.NoError(t, err)
+
+	hdr0, err := db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("sub/0"), Value: []byte("v0")}}})
+	require.NoError(t, err)
+
+	t.Run("send errors should stop the subscription", func(t *testing.T) {
+		errSend := errors.New("send error")
+
+		err := db.SubscribeTxs(context.Background(), &schema.SubscribeTxsRequest{SinceTx: 1}, func(vtx *schema.VerifiableTx) error {
+			return errSend
+		})
+		require.ErrorIs(t, err, errSend)
+	})
+
+	t.Run("only transactions committed after the subscription should be sent", func(t *testing.T) {
+		ch, stop := subscribe(t, db, &schema.SubscribeTxsRequest{})
+		defer stop()
+
+		hdr, err := db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k"), Value: []byte("v")}}})
+		require.NoError(t, err)
+
+		vtx := receiveTx(t, ch)
+		require.Equal(t, hdr.Id, vtx.Tx.Header.Id)
+		require.Nil(t, vtx.DualProof)
+	})
+
+	t.Run("filtered transactions should be sent with chained proofs", func(t *testing.T) {
+		ch, stop := subscribe(t, db, &schema.SubscribeTxsRequest{
+			SinceTx:     hdr0.Id,
+			Prefixes:    [][]byte{[]byte("sub/")},
+			Tables:      []string{"mytable"},
+			Collections: []string{"mycollection"},
+			WithProofs:  true,
+		})
+		defer stop()
+
+		_, err := db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("other/1"), Value: []byte("v1")}}})
+		require.NoError(t, err)
+
+		hdr1, err := db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("sub/1"), Value: []byte("v1")}}})
+		require.NoError(t, err)
+
+		_, ctxs, err := db.SQLExec(context.Background(), nil, &schema.SQLExecRequest{Sql: "INSERT INTO mytable() VALUES ()"})
+		require.NoError(t, err)
+		require.Len(t, ctxs, 1)
+
+		res, err := db.InsertDocuments(context.Background(), "admin", &protomodel.InsertDocumentsRequest{
+			CollectionName: "mycollection",
+			Documents: []*structpb.Struct{
+				{Fields: map[string]*structpb.Value{"name": structpb.NewStringValue("doc")}},
+			},
+		})
+		require.NoError(t, err)
+
+		expectedTxs := []uint64{hdr0.Id, hdr1.Id, ctxs[0].TxHeader().ID, res.TransactionId}
+
+		var prevHdr *store.TxHeader
+
+		for _, txID := range expectedTxs {
+			vtx := receiveTx(t, ch)
+			require.Equal(t, txID, vtx.Tx.Header.Id)
+			require.NotNil(t, vtx.DualProof)
+
+			tx := schema.TxFromProto(vtx.Tx)
+
+			sourceHdr := prevHdr
+			if sourceHdr == nil {
+				sourceHdr = tx.Header()
+			}
+
+			verifies := store.VerifyDualProof(
+				schema.DualProofFromProto(vtx.DualProof),
+				sourceHdr.ID,
+				tx.Header().ID,
+				sourceHdr.Alh(),
+				tx.Header().Alh(),
+			)
+			require.True(t, verifies)
+
+			prevHdr = tx.Header()
+		}
+
+		select {
+		case vtx := <-ch:
+			require.FailNow(t, "unexpected transaction", "tx %d", vtx.Tx.Header.Id)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("entries should be resolved according to the spec", func(t *testing.T) {
+		ch, stop := subscribe(t, db, &schema.SubscribeTxsRequest{
+			SinceTx:  hdr0.Id,
+			Prefixes: [][]byte{[]byte("sub/0")},
+			EntriesSpec: &schema.EntriesSpec{
+				KvEntriesSpec: &schema.EntryTypeSpec{Action: schema.EntryTypeAction_RESOLVE},
+			},
+		})
+		defer stop()
+
+		vtx := receiveTx(t, ch)
+		require.Equal(t, hdr0.Id, vtx.Tx.Header.Id)
+		require.Len(t, vtx.Tx.KvEntries, 1)
+		require.Equal(t, []byte("sub/0"), vtx.Tx.KvEntries[0].Key)
+		require.Equal(t, []byte("v0"), vtx.Tx.KvEntries[0].Value)
+	})
+
+	t.Run("inclusion proofs of the matching entries should be sent", func(t *testing.T) {
+		ch, stop := subscribe(t, db, &schema.SubscribeTxsRequest{
+			Prefixes:            [][]byte{[]byte("incl/")},
+			WithInclusionProofs: true,
+		})
+		defer stop()
+
+		_, err := db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{
+			{Key: []byte("excl/1"), Value: []byte("v1")},
+			{Key: []byte("incl/1"), Value: []byte("v1")},
+			{Key: []byte("incl/2"), Value: []byte("v2")},
+		}})
+		require.NoError(t, err)
+
+		vtx := receiveTx(t, ch)
+		require.Nil(t, vtx.DualProof)
+		require.Len(t, vtx.InclusionProofs, 2)
+
+		tx := schema.TxFromProto(vtx.Tx)
+
+		entryDigest, err := tx.Header().TxEntryDigest()
+		require.NoError(t, err)
+
+		for i, proof := range vtx.InclusionProofs {
+			inclusionProof := schema.InclusionProofFromProto(proof)
+
+			e := tx.Entries()[inclusionProof.Leaf]
+			require.Equal(t, WrapWithPrefix([]byte(fmt.Sprintf("incl/%d", i+1)), SetKeyPrefix), e.Key())
+
+			digest, err := entryDigest(e)
+			require.NoError(t, err)
+			require.True(t, store.VerifyInclusion(inclusionProof, digest, tx.Header().Eh))
+		}
+	})
+
+	t.Run("tables should be resolved again when created after being dropped", func(t *testing.T) {
+		_, _, err := db.SQLExec(context.Background(), nil, &schema.SQLExecRequest{Sql: "CREATE TABLE recreated(id INTEGER AUTO_INCREMENT, PRIMARY KEY id)"})
+		require.NoError(t, err)
+
+		ch, stop := subscribe(t, db, &schema.SubscribeTxsRequest{Tables: []string{"recreated"}})
+		defer stop()
+
+		_, _, err = db.SQLExec(context.Background(), nil, &schema.SQLExecRequest{Sql: `
+			DROP TABLE recreated;
+			CREATE TABLE recreated(id INTEGER AUTO_INCREMENT, PRIMARY KEY id);
+		`})
+		require.NoError(t, err)
+
+		_, ctxs, err := db.SQLExec(context.Background(), nil, &schema.SQLExecRequest{Sql: "INSERT INTO recreated() VALUES ()"})
+		require.NoError(t, err)
+		require.Len(t, ctxs, 1)
+
+		vtx := receiveTx(t, ch)
+		require.Equal(t, ctxs[0].TxHeader().ID, vtx.Tx.Header.Id)
+	})
+}
+
+func TestLazyDBSubscribeTxs(t *testing.T) {
+	defer func(maxWait time.Duration) {
+		subscriptionMaxWait = maxWait
+	}(subscriptionMaxWait)
+
+	subscriptionMaxWait = 10 * time.Millisecond
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"testdb", "testdb1"} {
+		err := os.MkdirAll(filepath.Join(dir, name), os.ModePerm)
+		require.NoError(t, err)
+	}
+
+	logger := logger.NewMemoryLogger()
+
+	m := NewDBManager(func(name string, opts *Options) (DB, error) {
+		return OpenDB(name, nil, opts, logger)
+	}, 1, logger)
+
+	dbList := NewDatabaseList(m)
+
+	db := dbList.Put("testdb", DefaultOptions().WithDBRootPath(dir))
+	db1 := dbList.Put("testdb1", DefaultOptions().WithDBRootPath(dir))
+
+	defer m.CloseAll(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan *schema.VerifiableTx, 10)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- db.SubscribeTxs(ctx, &schema.SubscribeTxsRequest{WithProofs: true}, func(vtx *schema.VerifiableTx) error {
+			ch <- vtx
+			return nil
+		})
+	}()
+
+	hdr, err := db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k"), Value: []byte("v")}}})
+	require.NoError(t, err)
+	require.Equal(t, hdr.Id, receiveTx(t, ch).Tx.Header.Id)
+
+	// the subscribed database is released while idle, so that another one can be opened
+	_, err = db1.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k"), Value: []byte("v")}}})
+	require.NoError(t, err)
+
+	hdr, err = db.Set(context.Background(), &schema.SetRequest{KVs: []*schema.KeyValue{{Key: []byte("k"), Value: []byte("v")}}})
+	require.NoError(t, err)
+
+	vtx := receiveTx(t, ch)
+	require.Equal(t, hdr.Id, vtx.Tx.Header.Id)
+	require.Equal(t, hdr.Id-1, vtx.DualProof.SourceTxHeader.Id)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
@@ -522,6 +522,50 @@ func TestImmuClient_SQL_Errors(t *testing.T) {
 	require.ErrorIs(t, err, ic.ErrNotConnected)
 }
 
+func TestImmuClient_SQLOuterJoins(t *testing.T) {
+	options := server.DefaultOptions().WithDir(t.TempDir())
+	bs := servertest.NewBufconnServer(options)
+
+	bs.Start()
+	defer bs.Stop()
+
+	ctx := context.Background()
+
+	client, err := bs.NewAuthenticatedClient(ic.DefaultOptions().WithDir(t.TempDir()))
+	require.NoError(t, err)
+	defer client.CloseSession(ctx)
+
+	_, err = client.SQLExec(ctx, `
+		CREATE TABLE employees(id INTEGER, dept_id INTEGER, PRIMARY KEY id);
+		CREATE TABLE departments(id INTEGER, title VARCHAR, PRIMARY KEY id);
+
+		INSERT INTO employees(id, dept_id) VALUES (1, 10), (2, 30);
+		INSERT INTO departments(id, title) VALUES (10, 'engineering'), (20, 'sales');
+	`, nil)
+	require.NoError(t, err)
+
+	queryRows := func(t *testing.T, joinType string) [][]interface{} {
+		res, err := client.SQLQuery(ctx, fmt.Sprintf("SELECT e.id, d.title FROM employees AS e %s JOIN departments AS d ON e.dept_id = d.id", joinType), nil, true)
+		require.NoError(t, err)
+
+		rows := make([][]interface{}, len(res.Rows))
+
+		for i, row := range res.Rows {
+			for _, v := range row.Values {
+				rows[i] = append(rows[i], schema.RawValue(v))
+			}
+		}
+		return rows
+	}
+
+	require.Equal(t, [][]interface{}{{int64(1), "engineering"}, {int64(2), nil}}, queryRows(t, "LEFT OUTER"))
+	require.Equal(t, [][]interface{}{{int64(1), "engineering"}, {nil, "sales"}}, queryRows(t, "RIGHT OUTER"))
+	require.Equal(t, [][]interface{}{{int64(1), "engineering"}, {int64(2), nil}, {nil, "sales"}}, queryRows(t, "FULL"))
+
+	_, err = client.SQLQuery(ctx, "SELECT e.id FROM employees AS e INNER OUTER JOIN departments AS d ON e.dept_id = d.id", nil, true)
+	require.ErrorContains(t, err, "unexpected OUTER")
+}
+
 func TestQueryTxMetadata(t *testing.T) {
 	options := server.DefaultOptions().
 		WithDir(t.TempDir()).
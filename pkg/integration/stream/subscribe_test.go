@@ -0,0 +1,100 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	ic "github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestImmuClient_SubscribeTxs(t *testing.T) {
+	options := server.DefaultOptions().WithDir(t.TempDir())
+	bs := servertest.NewBufconnServer(options)
+
+	bs.Start()
+	defer bs.Stop()
+
+	cliOpts := ic.
+		DefaultOptions().
+		WithDir(t.TempDir()).
+		WithDialOptions([]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithTransportCredentials(insecure.NewCredentials())})
+
+	client := ic.NewClient().WithOptions(cliOpts)
+
+	err := client.OpenSession(context.Background(), []byte(`immudb`), []byte(`immudb`), "defaultdb")
+	require.NoError(t, err)
+
+	defer client.CloseSession(context.Background())
+
+	_, err = client.SubscribeTxs(context.Background(), nil)
+	require.ErrorIs(t, err, ic.ErrIllegalArguments)
+
+	hdr0, err := client.Set(context.Background(), []byte("sub/0"), []byte("value0"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.SubscribeTxs(ctx, &schema.SubscribeTxsRequest{
+		SinceTx:    hdr0.Id,
+		Prefixes:   [][]byte{[]byte("sub/")},
+		WithProofs: true,
+	})
+	require.NoError(t, err)
+
+	_, err = client.Set(context.Background(), []byte("other/1"), []byte("value1"))
+	require.NoError(t, err)
+
+	hdr1, err := client.Set(context.Background(), []byte("sub/1"), []byte("value1"))
+	require.NoError(t, err)
+
+	vtx0, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, hdr0.Id, vtx0.Tx.Header.Id)
+
+	vtx1, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, hdr1.Id, vtx1.Tx.Header.Id)
+
+	sourceHdr := schema.TxFromProto(vtx0.Tx).Header()
+	targetHdr := schema.TxFromProto(vtx1.Tx).Header()
+
+	verifies := store.VerifyDualProof(
+		schema.DualProofFromProto(vtx1.DualProof),
+		sourceHdr.ID,
+		targetHdr.ID,
+		sourceHdr.Alh(),
+		targetHdr.Alh(),
+	)
+	require.True(t, verifies)
+
+	cancel()
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+}
@@ -89,6 +89,58 @@ func TestQueryPgCatalogTables(t *testing.T) {
 	require.Nil(t, schema)
 }
 
+func TestQueryPgCatalogViews(t *testing.T) {
+	engine := setupEngine(t, nil)
+
+	_, _, err := engine.Exec(context.Background(),
+		nil,
+		`CREATE TABLE table1 (id INTEGER, PRIMARY KEY id)`,
+		nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(),
+		nil,
+		`CREATE VIEW view1 AS SELECT id FROM table1 WHERE id > 10`,
+		nil)
+	require.NoError(t, err)
+
+	rows, err := engine.Query(
+		context.Background(),
+		nil,
+		`SELECT c.relname, c.relkind FROM pg_class c ORDER BY c.relname`,
+		nil,
+	)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	row, err := rows.Read(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "table1", row.ValuesByPosition[0].RawValue())
+	require.Equal(t, "r", row.ValuesByPosition[1].RawValue())
+
+	row, err = rows.Read(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "view1", row.ValuesByPosition[0].RawValue())
+	require.Equal(t, "v", row.ValuesByPosition[1].RawValue())
+
+	_, err = rows.Read(context.Background())
+	require.ErrorIs(t, err, sql.ErrNoMoreRows)
+
+	views, err := engine.Query(
+		context.Background(),
+		nil,
+		`SELECT viewname, definition FROM pg_views`,
+		nil,
+	)
+	require.NoError(t, err)
+	defer views.Close()
+
+	row, err = views.Read(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "view1", row.ValuesByPosition[0].RawValue())
+	require.Equal(t, "SELECT id FROM table1 WHERE id > 10", row.ValuesByPosition[1].RawValue())
+}
+
 func TestQueryPgRolesTable(t *testing.T) {
 	engine := setupEngine(t, &mockMultiDBHandler{
 		users: []sql.User{
@@ -178,49 +178,23 @@ var pgClassCols = []sql.ColDescriptor{
 	},
 }
 
+// viewOIDOffset keeps the oids of views apart from the ones of tables
+const viewOIDOffset = 1 << 32
+
 type pgClassResolver struct{}
 
 func (r *pgClassResolver) Resolve(ctx context.Context, tx *sql.SQLTx, alias string) (sql.RowReader, error) {
 	catalog := tx.Catalog()
 	tables := catalog.GetTables()
+	views := catalog.GetViews()
 
-	rows := make([][]sql.ValueExp, len(tables))
-	for i, t := range tables {
-		rows[i] = []sql.ValueExp{
-			sql.NewInteger(int64(t.ID())),        // oid
-			sql.NewVarchar(t.Name()),             // relname
-			sql.NewInteger(-1),                   // relnamespace
-			sql.NewVarchar(""),                   // reltype
-			sql.NewNull(sql.IntegerType),         // reloftype
-			sql.NewInteger(0),                    // relowner
-			sql.NewNull(sql.IntegerType),         // relam
-			sql.NewNull(sql.IntegerType),         // relfilenode
-			sql.NewNull(sql.IntegerType),         // reltablespace
-			sql.NewNull(sql.IntegerType),         // relpages
-			sql.NewNull(sql.Float64Type),         // reltuples
-			sql.NewNull(sql.IntegerType),         // relallvisible
-			sql.NewNull(sql.IntegerType),         // reltoastrelid
-			sql.NewBool(len(t.GetIndexes()) > 1), // relhasindex
-			sql.NewBool(false),                   // relisshared
-			sql.NewNull(sql.VarcharType),         // relpersistence
-			sql.NewVarchar("r"),                  // relkind
-			sql.NewNull(sql.IntegerType),         // relnats
-			sql.NewNull(sql.IntegerType),         // relchecks
-			sql.NewBool(false),                   // relhasrules
-			sql.NewBool(false),                   // relhastriggers
-			sql.NewBool(false),                   // relhassubclass
-			sql.NewBool(false),                   // relrowsecurity
-			sql.NewBool(false),                   // relforcerowsecurity
-			sql.NewBool(false),                   // relispopulated
-			sql.NewVarchar(""),                   // relreplident
-			sql.NewBool(false),                   // relispartition
-			sql.NewInteger(0),                    // relrewrite
-			sql.NewNull(sql.IntegerType),         // relfrozenxid
-			sql.NewNull(sql.IntegerType),         // relminmxid
-			sql.NewNull(sql.AnyType),             // relacl
-			sql.NewNull(sql.AnyType),             // reloptions
-			sql.NewNull(sql.AnyType),             // relpartbound
-		}
+	rows := make([][]sql.ValueExp, 0, len(tables)+len(views))
+	for _, t := range tables {
+		rows = append(rows, pgClassRow(int64(t.ID()), t.Name(), len(t.GetIndexes()) > 1, "r"))
+	}
+
+	for _, v := range views {
+		rows = append(rows, pgClassRow(viewOIDOffset+int64(v.ID()), v.Name(), false, "v"))
 	}
 
 	return sql.NewValuesRowReader(
@@ -233,10 +207,96 @@ func (r *pgClassResolver) Resolve(ctx context.Context, tx *sql.SQLTx, alias stri
 	)
 }
 
+func pgClassRow(oid int64, name string, hasIndex bool, kind string) []sql.ValueExp {
+	return []sql.ValueExp{
+		sql.NewInteger(oid),          // oid
+		sql.NewVarchar(name),         // relname
+		sql.NewInteger(-1),           // relnamespace
+		sql.NewVarchar(""),           // reltype
+		sql.NewNull(sql.IntegerType), // reloftype
+		sql.NewInteger(0),            // relowner
+		sql.NewNull(sql.IntegerType), // relam
+		sql.NewNull(sql.IntegerType), // relfilenode
+		sql.NewNull(sql.IntegerType), // reltablespace
+		sql.NewNull(sql.IntegerType), // relpages
+		sql.NewNull(sql.Float64Type), // reltuples
+		sql.NewNull(sql.IntegerType), // relallvisible
+		sql.NewNull(sql.IntegerType), // reltoastrelid
+		sql.NewBool(hasIndex),        // relhasindex
+		sql.NewBool(false),           // relisshared
+		sql.NewNull(sql.VarcharType), // relpersistence
+		sql.NewVarchar(kind),         // relkind
+		sql.NewNull(sql.IntegerType), // relnats
+		sql.NewNull(sql.IntegerType), // relchecks
+		sql.NewBool(false),           // relhasrules
+		sql.NewBool(false),           // relhastriggers
+		sql.NewBool(false),           // relhassubclass
+		sql.NewBool(false),           // relrowsecurity
+		sql.NewBool(false),           // relforcerowsecurity
+		sql.NewBool(false),           // relispopulated
+		sql.NewVarchar(""),           // relreplident
+		sql.NewBool(false),           // relispartition
+		sql.NewInteger(0),            // relrewrite
+		sql.NewNull(sql.IntegerType), // relfrozenxid
+		sql.NewNull(sql.IntegerType), // relminmxid
+		sql.NewNull(sql.AnyType),     // relacl
+		sql.NewNull(sql.AnyType),     // reloptions
+		sql.NewNull(sql.AnyType),     // relpartbound
+	}
+}
+
 func (r *pgClassResolver) Table() string {
 	return "pg_class"
 }
 
+var pgViewsCols = []sql.ColDescriptor{
+	{
+		Column: "schemaname",
+		Type:   sql.VarcharType,
+	},
+	{
+		Column: "viewname",
+		Type:   sql.VarcharType,
+	},
+	{
+		Column: "viewowner",
+		Type:   sql.VarcharType,
+	},
+	{
+		Column: "definition",
+		Type:   sql.VarcharType,
+	},
+}
+
+type pgViewsResolver struct{}
+
+func (r *pgViewsResolver) Resolve(ctx context.Context, tx *sql.SQLTx, alias string) (sql.RowReader, error) {
+	views := tx.Catalog().GetViews()
+
+	rows := make([][]sql.ValueExp, len(views))
+	for i, v := range views {
+		rows[i] = []sql.ValueExp{
+			sql.NewNull(sql.VarcharType), // schemaname
+			sql.NewVarchar(v.Name()),     // viewname
+			sql.NewNull(sql.VarcharType), // viewowner
+			sql.NewVarchar(v.Query()),    // definition
+		}
+	}
+
+	return sql.NewValuesRowReader(
+		tx,
+		nil,
+		pgViewsCols,
+		true,
+		alias,
+		rows,
+	)
+}
+
+func (r *pgViewsResolver) Table() string {
+	return "pg_views"
+}
+
 var pgNamespaceCols = []sql.ColDescriptor{
 	{
 		Column: "oid",
@@ -373,6 +433,7 @@ func (r *pgRolesResolver) Table() string {
 
 var tableResolvers = []sql.TableResolver{
 	&pgClassResolver{},
+	&pgViewsResolver{},
 	&pgNamespaceResolver{},
 	&pgRolesResolver{},
 }
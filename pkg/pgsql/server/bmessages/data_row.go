@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/codenotary/immudb/embedded/sql"
+	"github.com/codenotary/immudb/pkg/pgsql/server/pgmeta"
 )
 
 // DataRow if ResultColumnFormatCodes is nil default text format is used
@@ -58,41 +59,7 @@ func DataRow(rows []*sql.Row, colNumb int, ResultColumnFormatCodes []int16) []by
 					n := -1
 					binary.BigEndian.PutUint32(valueLength, uint32(n))
 				} else {
-					rv := val.RawValue()
-					switch val.Type() {
-					case sql.IntegerType:
-						{
-							binary.BigEndian.PutUint32(valueLength, uint32(8))
-							value = make([]byte, 8)
-							binary.BigEndian.PutUint64(value, uint64(rv.(int64)))
-						}
-					case sql.JSONType:
-						{
-							jsonStr := trimQuotes(val.String())
-							binary.BigEndian.PutUint32(valueLength, uint32(len(jsonStr)))
-							value = []byte(jsonStr)
-						}
-					case sql.VarcharType:
-						{
-							s := rv.(string)
-							binary.BigEndian.PutUint32(valueLength, uint32(len(s)))
-							value = []byte(s)
-						}
-					case sql.BooleanType:
-						{
-							binary.BigEndian.PutUint32(valueLength, uint32(1))
-							value = []byte{0}
-							if rv.(bool) {
-								value = []byte{1}
-							}
-						}
-					case sql.BLOBType:
-						{
-							blob := rv.([]byte)
-							binary.BigEndian.PutUint32(valueLength, uint32(len(blob)))
-							value = blob
-						}
-					}
+					value = renderValueAsBinary(val)
 				}
 			} else {
 				// only text format is allowed in simple query
@@ -117,11 +84,57 @@ func DataRow(rows []*sql.Row, colNumb int, ResultColumnFormatCodes []int16) []by
 	return rowsB
 }
 
+func renderValueAsBinary(v sql.TypedValue) []byte {
+	rv := v.RawValue()
+
+	switch v.Type() {
+	case sql.IntegerType:
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, uint64(rv.(int64)))
+		return value
+	case sql.JSONType:
+		return []byte(trimQuotes(v.String()))
+	case sql.VarcharType:
+		return []byte(rv.(string))
+	case sql.BooleanType:
+		if rv.(bool) {
+			return []byte{1}
+		}
+		return []byte{0}
+	case sql.BLOBType:
+		return rv.([]byte)
+	case sql.DecimalType:
+		value, _ := pgmeta.EncodeNumeric(v.String())
+		return value
+	case sql.IntervalType:
+		months, days, micros := v.(*sql.Interval).Fields()
+
+		value := make([]byte, 16)
+		binary.BigEndian.PutUint64(value, uint64(micros))
+		binary.BigEndian.PutUint32(value[8:], uint32(days))
+		binary.BigEndian.PutUint32(value[12:], uint32(months))
+		return value
+	}
+
+	if arr, ok := v.(*sql.Array); ok {
+		elems := make([][]byte, len(arr.Values()))
+		for i, e := range arr.Values() {
+			elems[i] = renderValueAsBinary(e)
+		}
+		return pgmeta.EncodeArray(pgmeta.PgTypeMap[arr.ElemType()][pgmeta.PgTypeMapOid], elems)
+	}
+	return make([]byte, 0)
+}
+
 func renderValueAsByte(v sql.TypedValue) []byte {
 	if v.IsNull() {
 		return nil
 	}
 
+	if arr, ok := v.(*sql.Array); ok {
+		return []byte(arr.Text())
+	}
+
 	var s string
 	switch v.Type() {
 	case sql.VarcharType:
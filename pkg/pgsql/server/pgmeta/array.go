@@ -0,0 +1,50 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgmeta
+
+import "encoding/binary"
+
+// EncodeArray encodes already encoded elements in the binary format of one-dimensional arrays,
+// i.e. {ndim}{hasnull}{elem oid}{dim length}{lower bound}, followed by the length and value of each element
+func EncodeArray(elemOid int, elems [][]byte) []byte {
+	if len(elems) == 0 {
+		b := make([]byte, 12)
+		binary.BigEndian.PutUint32(b[8:], uint32(elemOid))
+		return b
+	}
+
+	size := 20
+	for _, e := range elems {
+		size += 4 + len(e)
+	}
+
+	b := make([]byte, 20, size)
+	binary.BigEndian.PutUint32(b[0:], 1)
+	binary.BigEndian.PutUint32(b[4:], 0)
+	binary.BigEndian.PutUint32(b[8:], uint32(elemOid))
+	binary.BigEndian.PutUint32(b[12:], uint32(len(elems)))
+	binary.BigEndian.PutUint32(b[16:], 1)
+
+	for _, e := range elems {
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(e)))
+
+		b = append(b, l[:]...)
+		b = append(b, e...)
+	}
+	return b
+}
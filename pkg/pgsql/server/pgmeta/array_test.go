@@ -0,0 +1,39 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgmeta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayEncoding(t *testing.T) {
+	enc := EncodeArray(20, nil)
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 20}, enc)
+
+	enc = EncodeArray(25, [][]byte{[]byte("a"), []byte("bc")})
+	require.Equal(t, []byte{
+		0, 0, 0, 1, // ndim
+		0, 0, 0, 0, // hasnull
+		0, 0, 0, 25, // elem oid
+		0, 0, 0, 2, // dim length
+		0, 0, 0, 1, // lower bound
+		0, 0, 0, 1, 'a',
+		0, 0, 0, 2, 'b', 'c',
+	}, enc)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgmeta
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	numericPositive = 0x0000
+	numericNegative = 0x4000
+	numericNaN      = 0xC000
+
+	// numeric values are sent as digits in base 10000
+	numericDigitLen = 4
+)
+
+var ErrInvalidNumeric = errors.New("invalid numeric value")
+
+// EncodeNumeric encodes a decimal value in the binary format of the numeric type,
+// i.e. {ndigits}{weight}{sign}{dscale}{digits in base 10000}
+func EncodeNumeric(s string) ([]byte, error) {
+	neg := strings.HasPrefix(s, "-")
+
+	intPart, fracPart := strings.TrimPrefix(s, "-"), ""
+	if i := strings.IndexByte(intPart, '.'); i >= 0 {
+		intPart, fracPart = intPart[:i], intPart[i+1:]
+	}
+
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNumeric, s)
+		}
+	}
+
+	dscale := len(fracPart)
+
+	intPart = strings.TrimLeft(intPart, "0")
+	if r := len(intPart) % numericDigitLen; r > 0 {
+		intPart = strings.Repeat("0", numericDigitLen-r) + intPart
+	}
+	if r := len(fracPart) % numericDigitLen; r > 0 {
+		fracPart += strings.Repeat("0", numericDigitLen-r)
+	}
+
+	digits := intPart + fracPart
+	weight := len(intPart)/numericDigitLen - 1
+
+	groups := make([]uint16, 0, len(digits)/numericDigitLen)
+	for i := 0; i < len(digits); i += numericDigitLen {
+		g, _ := strconv.Atoi(digits[i : i+numericDigitLen])
+		groups = append(groups, uint16(g))
+	}
+
+	for len(groups) > 0 && groups[0] == 0 {
+		groups = groups[1:]
+		weight--
+	}
+	for len(groups) > 0 && groups[len(groups)-1] == 0 {
+		groups = groups[:len(groups)-1]
+	}
+
+	sign := uint16(numericPositive)
+	if len(groups) == 0 {
+		weight = 0
+	} else if neg {
+		sign = numericNegative
+	}
+
+	b := make([]byte, 8+2*len(groups))
+	binary.BigEndian.PutUint16(b[0:], uint16(len(groups)))
+	binary.BigEndian.PutUint16(b[2:], uint16(int16(weight)))
+	binary.BigEndian.PutUint16(b[4:], sign)
+	binary.BigEndian.PutUint16(b[6:], uint16(dscale))
+
+	for i, g := range groups {
+		binary.BigEndian.PutUint16(b[8+2*i:], g)
+	}
+	return b, nil
+}
+
+// DecodeNumeric decodes a value in the binary format of the numeric type into its textual representation
+func DecodeNumeric(b []byte) (string, error) {
+	if len(b) < 8 {
+		return "", ErrInvalidNumeric
+	}
+
+	ndigits := int(binary.BigEndian.Uint16(b[0:]))
+	weight := int(int16(binary.BigEndian.Uint16(b[2:])))
+	sign := binary.BigEndian.Uint16(b[4:])
+	dscale := int(binary.BigEndian.Uint16(b[6:]))
+
+	if len(b) != 8+2*ndigits {
+		return "", ErrInvalidNumeric
+	}
+
+	if sign == numericNaN {
+		return "", fmt.Errorf("%w: NaN is not supported", ErrInvalidNumeric)
+	}
+
+	group := func(i int) uint16 {
+		if i < 0 || i >= ndigits {
+			return 0
+		}
+		return binary.BigEndian.Uint16(b[8+2*i:])
+	}
+
+	var intPart strings.Builder
+	for i := 0; i <= weight; i++ {
+		fmt.Fprintf(&intPart, "%04d", group(i))
+	}
+
+	var fracPart strings.Builder
+	for i := weight + 1; fracPart.Len() < dscale; i++ {
+		fmt.Fprintf(&fracPart, "%04d", group(i))
+	}
+
+	s := strings.TrimLeft(intPart.String(), "0")
+	if s == "" {
+		s = "0"
+	}
+
+	if dscale > 0 {
+		s += "." + fracPart.String()[:dscale]
+	}
+
+	if sign == numericNegative {
+		s = "-" + s
+	}
+	return s, nil
+}
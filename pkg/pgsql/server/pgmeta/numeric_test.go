@@ -0,0 +1,57 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgmeta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericEncoding(t *testing.T) {
+	for _, d := range []struct {
+		s   string
+		enc []byte
+	}{
+		{"0", []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{"0.00", []byte{0, 0, 0, 0, 0, 0, 0, 2}},
+		{"12.50", []byte{0, 2, 0, 0, 0, 0, 0, 2, 0, 12, 0x13, 0x88}},
+		{"-12345.6789", []byte{0, 3, 0, 1, 0x40, 0, 0, 4, 0, 1, 0x09, 0x29, 0x1a, 0x85}},
+		{"0.0001", []byte{0, 1, 0xff, 0xff, 0, 0, 0, 4, 0, 1}},
+		{"0.00000005", []byte{0, 1, 0xff, 0xfe, 0, 0, 0, 8, 0, 5}},
+		{"100000000", []byte{0, 1, 0, 2, 0, 0, 0, 0, 0, 1}},
+	} {
+		t.Run(d.s, func(t *testing.T) {
+			enc, err := EncodeNumeric(d.s)
+			require.NoError(t, err)
+			require.Equal(t, d.enc, enc)
+
+			s, err := DecodeNumeric(enc)
+			require.NoError(t, err)
+			require.Equal(t, d.s, s)
+		})
+	}
+
+	_, err := EncodeNumeric("1e5")
+	require.ErrorIs(t, err, ErrInvalidNumeric)
+
+	_, err = DecodeNumeric([]byte{0, 1, 0, 0})
+	require.ErrorIs(t, err, ErrInvalidNumeric)
+
+	_, err = DecodeNumeric([]byte{0, 0, 0, 0, 0xc0, 0, 0, 0})
+	require.ErrorIs(t, err, ErrInvalidNumeric)
+}
@@ -46,8 +46,18 @@ var PgTypeMap = map[string][]int{
 	sql.VarcharType:   {25, -1},   //text
 	sql.UUIDType:      {2950, 16}, //uuid
 	sql.Float64Type:   {701, 8},   //double-precision floating point number
+	sql.DecimalType:   {1700, -1}, //numeric
 	sql.JSONType:      {114, -1},  //json
+	sql.IntervalType:  {1186, 16}, //interval
 	sql.AnyType:       {17, -1},   // bytea
+
+	sql.ArrayType(sql.BooleanType):   {1000, -1}, //bool[]
+	sql.ArrayType(sql.TimestampType): {1016, -1}, //int8[]
+	sql.ArrayType(sql.IntegerType):   {1016, -1}, //int8[]
+	sql.ArrayType(sql.VarcharType):   {1009, -1}, //text[]
+	sql.ArrayType(sql.UUIDType):      {2951, -1}, //uuid[]
+	sql.ArrayType(sql.Float64Type):   {1022, -1}, //float8[]
+	sql.ArrayType(sql.DecimalType):   {1231, -1}, //numeric[]
 }
 
 const PgSeverityError = "ERROR"
@@ -184,6 +184,322 @@ func TestPgsqlServer_SimpleQueryBool(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPgsqlServer_SimpleQueryDecimal(t *testing.T) {
+	td := t.TempDir()
+
+	options := server.DefaultOptions().
+		WithDir(td).
+		WithPort(0).
+		WithPgsqlServer(true).
+		WithPgsqlServerPort(0).
+		WithMetricsServer(false).
+		WithWebServer(false)
+
+	srv := server.DefaultServer().WithOptions(options).(*server.ImmuServer)
+
+	err := srv.Initialize()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		srv.Start()
+	}()
+
+	defer func() {
+		srv.Stop()
+	}()
+
+	defer os.Remove(".state-")
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+	require.NoError(t, err)
+
+	table := getRandomTableName()
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, balance DECIMAL(12,2), PRIMARY KEY id)", table))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("UPSERT INTO %s (id, balance) VALUES (1, '1024.5')", table))
+	require.NoError(t, err)
+
+	var id int64
+	var balance string
+	err = db.QueryRow(fmt.Sprintf("SELECT id, balance FROM %s", table)).Scan(&id, &balance)
+	require.NoError(t, err)
+	require.Equal(t, "1024.50", balance)
+}
+
+func TestPgsqlServer_QueryOuterJoins(t *testing.T) {
+	td := t.TempDir()
+
+	options := server.DefaultOptions().
+		WithDir(td).
+		WithPort(0).
+		WithPgsqlServer(true).
+		WithPgsqlServerPort(0).
+		WithMetricsServer(false).
+		WithWebServer(false)
+
+	srv := server.DefaultServer().WithOptions(options).(*server.ImmuServer)
+
+	err := srv.Initialize()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		srv.Start()
+	}()
+
+	defer func() {
+		srv.Stop()
+	}()
+
+	defer os.Remove(".state-")
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+	require.NoError(t, err)
+
+	employees := getRandomTableName()
+	departments := getRandomTableName()
+
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, dept_id INTEGER, PRIMARY KEY id)", employees))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, title VARCHAR, PRIMARY KEY id)", departments))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, dept_id) VALUES (1, 10), (2, 30)", employees))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, title) VALUES (10, 'engineering'), (20, 'sales')", departments))
+	require.NoError(t, err)
+
+	queryRows := func(t *testing.T, joinType string) [][2]interface{} {
+		rows, err := db.Query(fmt.Sprintf("SELECT e.id, d.title FROM %s AS e %s JOIN %s AS d ON e.dept_id = d.id", employees, joinType, departments))
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var res [][2]interface{}
+
+		for rows.Next() {
+			var id sql.NullInt64
+			var title sql.NullString
+
+			err := rows.Scan(&id, &title)
+			require.NoError(t, err)
+
+			row := [2]interface{}{nil, nil}
+			if id.Valid {
+				row[0] = id.Int64
+			}
+			if title.Valid {
+				row[1] = title.String
+			}
+
+			res = append(res, row)
+		}
+		require.NoError(t, rows.Err())
+
+		return res
+	}
+
+	require.Equal(t, [][2]interface{}{{int64(1), "engineering"}, {int64(2), nil}}, queryRows(t, "LEFT OUTER"))
+	require.Equal(t, [][2]interface{}{{int64(1), "engineering"}, {nil, "sales"}}, queryRows(t, "RIGHT"))
+	require.Equal(t, [][2]interface{}{{int64(1), "engineering"}, {int64(2), nil}, {nil, "sales"}}, queryRows(t, "FULL OUTER"))
+
+	_, err = db.Query(fmt.Sprintf("SELECT e.id FROM %s AS e INNER OUTER JOIN %s AS d ON e.dept_id = d.id", employees, departments))
+	require.ErrorContains(t, err, "unexpected OUTER")
+}
+
+func TestPgsqlServer_QueryFunctionsWithParams(t *testing.T) {
+	td := t.TempDir()
+
+	options := server.DefaultOptions().
+		WithDir(td).
+		WithPort(0).
+		WithPgsqlServer(true).
+		WithPgsqlServerPort(0).
+		WithMetricsServer(false).
+		WithWebServer(false)
+
+	srv := server.DefaultServer().WithOptions(options).(*server.ImmuServer)
+
+	err := srv.Initialize()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		srv.Start()
+	}()
+
+	defer func() {
+		srv.Stop()
+	}()
+
+	defer os.Remove(".state-")
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+	require.NoError(t, err)
+
+	table := getRandomTableName()
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, name VARCHAR, nickname VARCHAR, ratio FLOAT, PRIMARY KEY id)", table))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, name, nickname, ratio) VALUES (1, 'john', NULL, 0.25), (2, 'mary', 'molly', 0.75)", table))
+	require.NoError(t, err)
+
+	t.Run("text format", func(t *testing.T) {
+		var display, padded string
+		err := db.QueryRow(
+			fmt.Sprintf("SELECT COALESCE(nickname, name), LPAD(name, $1, $2) FROM %s WHERE COALESCE(nickname, $3) = $4", table),
+			8, "*", "guest", "guest",
+		).Scan(&display, &padded)
+		require.NoError(t, err)
+		require.Equal(t, "john", display)
+		require.Equal(t, "****john", padded)
+
+		var id int64
+		err = db.QueryRow(fmt.Sprintf("SELECT id FROM %s WHERE ratio > $1", table), 0.5).Scan(&id)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), id)
+	})
+
+	t.Run("binary format", func(t *testing.T) {
+		conn, err := pgx.Connect(context.Background(), fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+		require.NoError(t, err)
+		defer conn.Close(context.Background())
+
+		var part string
+		var pos int64
+		err = conn.QueryRow(
+			context.Background(),
+			fmt.Sprintf("SELECT SPLIT_PART(name, $1, $2), POSITION($3 IN name) FROM %s WHERE ABS(ratio - $4) < 0.1", table),
+			"a", 2, "r", 0.7,
+		).Scan(&part, &pos)
+		require.NoError(t, err)
+		require.Equal(t, "ry", part)
+		require.Equal(t, int64(3), pos)
+	})
+}
+
+func TestPgsqlServer_QueryArray(t *testing.T) {
+	td := t.TempDir()
+
+	options := server.DefaultOptions().
+		WithDir(td).
+		WithPort(0).
+		WithPgsqlServer(true).
+		WithPgsqlServerPort(0).
+		WithMetricsServer(false).
+		WithWebServer(false)
+
+	srv := server.DefaultServer().WithOptions(options).(*server.ImmuServer)
+
+	err := srv.Initialize()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		srv.Start()
+	}()
+
+	defer func() {
+		srv.Stop()
+	}()
+
+	defer os.Remove(".state-")
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+	require.NoError(t, err)
+
+	table := getRandomTableName()
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, tags VARCHAR[], scores INTEGER[], PRIMARY KEY id)", table))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, tags, scores) VALUES (1, ARRAY['go', 'immu db'], ARRAY[3, 1])", table))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, tags, scores) VALUES (2, $1, $2)", table), pq.Array([]string{"sql"}), pq.Array([]int64{2}))
+	require.NoError(t, err)
+
+	t.Run("text format", func(t *testing.T) {
+		var tags []string
+		var scores []int64
+		err := db.QueryRow(fmt.Sprintf("SELECT tags, scores FROM %s WHERE $1 = ANY(tags)", table), "immu db").
+			Scan(pq.Array(&tags), pq.Array(&scores))
+		require.NoError(t, err)
+		require.Equal(t, []string{"go", "immu db"}, tags)
+		require.Equal(t, []int64{3, 1}, scores)
+
+		var id int64
+		err = db.QueryRow(fmt.Sprintf("SELECT id FROM %s WHERE tags @> $1", table), pq.Array([]string{"sql"})).Scan(&id)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), id)
+	})
+
+	t.Run("binary format", func(t *testing.T) {
+		conn, err := pgx.Connect(context.Background(), fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+		require.NoError(t, err)
+		defer conn.Close(context.Background())
+
+		var scores []int64
+		err = conn.QueryRow(context.Background(), fmt.Sprintf("SELECT scores FROM %s WHERE id = 1", table)).Scan(&scores)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3, 1}, scores)
+	})
+}
+
+func TestPgsqlServer_QueryInterval(t *testing.T) {
+	td := t.TempDir()
+
+	options := server.DefaultOptions().
+		WithDir(td).
+		WithPort(0).
+		WithPgsqlServer(true).
+		WithPgsqlServerPort(0).
+		WithMetricsServer(false).
+		WithWebServer(false)
+
+	srv := server.DefaultServer().WithOptions(options).(*server.ImmuServer)
+
+	err := srv.Initialize()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		srv.Start()
+	}()
+
+	defer func() {
+		srv.Stop()
+	}()
+
+	defer os.Remove(".state-")
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+	require.NoError(t, err)
+
+	table := getRandomTableName()
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, ts TIMESTAMP, PRIMARY KEY id)", table))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, ts) VALUES (1, CAST('2024-02-29 23:59:59' AS TIMESTAMP))", table))
+	require.NoError(t, err)
+
+	var age, month string
+	err = db.QueryRow(fmt.Sprintf(
+		"SELECT AGE(CAST('2024-04-01' AS TIMESTAMP), ts), TO_CHAR(ts + INTERVAL '1 day', 'YYYY-MM') FROM %s WHERE ts > CAST('2024-03-01' AS TIMESTAMP) - INTERVAL '1 week'",
+		table,
+	)).Scan(&age, &month)
+	require.NoError(t, err)
+	require.Equal(t, "1 mon 00:00:01", age)
+	require.Equal(t, "2024-03", month)
+}
+
 func TestPgsqlServer_SimpleQueryExecError(t *testing.T) {
 	td := t.TempDir()
 
@@ -817,6 +1133,72 @@ func TestPgsqlServer_SimpleQueryNilValues(t *testing.T) {
 	require.False(t, amount.Valid)
 }
 
+func TestPgsqlServer_Explain(t *testing.T) {
+	td := t.TempDir()
+
+	options := server.DefaultOptions().
+		WithDir(td).
+		WithPort(0).
+		WithPgsqlServer(true).
+		WithPgsqlServerPort(0).
+		WithMetricsServer(false).
+		WithWebServer(false)
+
+	srv := server.DefaultServer().WithOptions(options).(*server.ImmuServer)
+
+	err := srv.Initialize()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		srv.Start()
+	}()
+
+	defer func() {
+		srv.Stop()
+	}()
+
+	defer os.Remove(".state-")
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=localhost port=%d sslmode=disable user=immudb dbname=defaultdb password=immudb", srv.PgsqlSrv.GetPort()))
+	require.NoError(t, err)
+
+	table := getRandomTableName()
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (id INTEGER, amount INTEGER, title VARCHAR, PRIMARY KEY id)", table))
+	require.NoError(t, err)
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (id, amount, title) VALUES (1, 100, 'title 1'), (2, 200, 'title 2')", table))
+	require.NoError(t, err)
+
+	readPlan := func(query string, args ...interface{}) []string {
+		rows, err := db.Query(query, args...)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var plan []string
+		for rows.Next() {
+			var line string
+			require.NoError(t, rows.Scan(&line))
+			plan = append(plan, line)
+		}
+		require.NoError(t, rows.Err())
+		return plan
+	}
+
+	plan := readPlan(fmt.Sprintf("EXPLAIN SELECT id, title FROM %s WHERE id > 1", table))
+	require.Equal(t, []string{
+		"project id, title",
+		"-> filter (id > 1)",
+		fmt.Sprintf("  -> scan %s using index %s(id) range id > 1", table, table),
+	}, plan)
+
+	plan = readPlan(fmt.Sprintf("EXPLAIN ANALYZE SELECT id, title FROM %s WHERE amount = ?", table), 200)
+	require.Len(t, plan, 3)
+	require.Contains(t, plan[0], "(rows=1 ")
+	require.Contains(t, plan[2], "(rows=2 ")
+}
+
 func getRandomTableName() string {
 	rand.Seed(time.Now().UnixNano())
 	r := rand.Intn(100000)
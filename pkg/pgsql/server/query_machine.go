@@ -287,7 +287,7 @@ func (s *session) fetchAndWriteResults(statements string, parameters []*schema.N
 			{
 				return pserr.ErrUseDBStatementNotSupported
 			}
-		case *sql.SelectStmt:
+		case sql.DataSource:
 			if err = s.query(st, parameters, resultColumnFormatCodes, extQueryMode); err != nil {
 				return err
 			}
@@ -310,7 +310,7 @@ func removePGCatalogReferences(sql string) string {
 	return strings.ReplaceAll(sql, "pg_catalog.", "")
 }
 
-func (s *session) query(st *sql.SelectStmt, parameters []*schema.NamedParam, resultColumnFormatCodes []int16, skipRowDesc bool) error {
+func (s *session) query(st sql.DataSource, parameters []*schema.NamedParam, resultColumnFormatCodes []int16, skipRowDesc bool) error {
 	tx, err := s.sqlTx()
 	if err != nil {
 		return err
@@ -375,9 +375,14 @@ type statement struct {
 func (s *session) inferParamAndResultCols(stmt sql.SQLStmt) ([]sql.ColDescriptor, []sql.ColDescriptor, error) {
 	var resCols []sql.ColDescriptor
 
-	sel, ok := stmt.(*sql.SelectStmt)
+	ds, ok := stmt.(sql.DataSource)
 	if ok {
-		rr, err := s.db.SQLQueryPrepared(s.ctx, s.tx, sel, nil)
+		if explain, isExplain := ds.(*sql.ExplainStmt); isExplain && explain.Analyze() {
+			// result columns are described without executing the query
+			ds = sql.NewExplainStmt(explain.Query(), false)
+		}
+
+		rr, err := s.db.SQLQueryPrepared(s.ctx, s.tx, ds, nil)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -20,10 +20,12 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/codenotary/immudb/embedded/sql"
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/pgsql/server/pgmeta"
 )
 
 func buildNamedParams(paramsType []sql.ColDescriptor, paramsVal []interface{}) ([]*schema.NamedParam, error) {
@@ -42,7 +44,13 @@ func buildNamedParams(paramsType []sql.ColDescriptor, paramsVal []interface{}) (
 					return nil, err
 				}
 				pMap[name] = int64(int)
-			case sql.VarcharType:
+			case sql.Float64Type:
+				f, err := strconv.ParseFloat(p, 64)
+				if err != nil {
+					return nil, err
+				}
+				pMap[name] = f
+			case sql.VarcharType, sql.DecimalType:
 				pMap[name] = p
 			case sql.BooleanType:
 				pMap[name] = p == "true"
@@ -52,6 +60,11 @@ func buildNamedParams(paramsType []sql.ColDescriptor, paramsVal []interface{}) (
 					return nil, err
 				}
 				pMap[name] = d
+			default:
+				// arrays are sent in their text representation e.g. {1,2,3}
+				if sql.IsArrayType(param.Type) {
+					pMap[name] = p
+				}
 			}
 		}
 		// binary param
@@ -63,6 +76,12 @@ func buildNamedParams(paramsType []sql.ColDescriptor, paramsVal []interface{}) (
 					return nil, err
 				}
 				pMap[name] = i
+			case sql.Float64Type:
+				f, err := getFloat64(p)
+				if err != nil {
+					return nil, err
+				}
+				pMap[name] = f
 			case sql.VarcharType:
 				pMap[name] = string(p)
 			case sql.BooleanType:
@@ -73,6 +92,12 @@ func buildNamedParams(paramsType []sql.ColDescriptor, paramsVal []interface{}) (
 				pMap[name] = v
 			case sql.BLOBType:
 				pMap[name] = p
+			case sql.DecimalType:
+				d, err := pgmeta.DecodeNumeric(p)
+				if err != nil {
+					return nil, err
+				}
+				pMap[name] = d
 			}
 		}
 	}
@@ -91,3 +116,14 @@ func getInt64(p []byte) (int64, error) {
 		return 0, fmt.Errorf("cannot convert a slice of %d byte in an INTEGER parameter", len(p))
 	}
 }
+
+func getFloat64(p []byte) (float64, error) {
+	switch len(p) {
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(p)), nil
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(p))), nil
+	default:
+		return 0, fmt.Errorf("cannot convert a slice of %d byte in a FLOAT parameter", len(p))
+	}
+}
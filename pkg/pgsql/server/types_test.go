@@ -20,6 +20,8 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
+	"strconv"
 	"testing"
 
 	"github.com/codenotary/immudb/embedded/sql"
@@ -48,6 +50,23 @@ func Test_getInt64(t *testing.T) {
 	require.ErrorContains(t, err, fmt.Sprintf("cannot convert a slice of %d byte in an INTEGER parameter", len(bxxx)))
 }
 
+func Test_getFloat64(t *testing.T) {
+	b64f := make([]byte, 8)
+	binary.BigEndian.PutUint64(b64f, math.Float64bits(1.5))
+	f, err := getFloat64(b64f)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, f)
+	b32f := make([]byte, 4)
+	binary.BigEndian.PutUint32(b32f, math.Float32bits(1.5))
+	f, err = getFloat64(b32f)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, f)
+
+	bxxx := make([]byte, 2)
+	_, err = getFloat64(bxxx)
+	require.ErrorContains(t, err, fmt.Sprintf("cannot convert a slice of %d byte in a FLOAT parameter", len(bxxx)))
+}
+
 func Test_buildNamedParams(t *testing.T) {
 	// integer error
 	cols := []sql.ColDescriptor{
@@ -92,4 +111,20 @@ func Test_buildNamedParams(t *testing.T) {
 	pt = []interface{}{"blob"}
 	_, err = buildNamedParams(cols, pt)
 	require.ErrorIs(t, err, hex.InvalidByteError(108))
+
+	// float
+	cols = []sql.ColDescriptor{
+		{
+			Column: "p1",
+			Type:   "FLOAT",
+		},
+	}
+	pt = []interface{}{"2.5"}
+	_, err = buildNamedParams(cols, pt)
+	require.NoError(t, err)
+
+	// float text error
+	pt = []interface{}{"two"}
+	_, err = buildNamedParams(cols, pt)
+	require.ErrorIs(t, err, strconv.ErrSyntax)
 }
@@ -110,6 +110,10 @@ func (db *closedDB) Delete(ctx context.Context, req *schema.DeleteKeysRequest) (
 	return nil, store.ErrAlreadyClosed
 }
 
+func (db *closedDB) DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error) {
+	return nil, store.ErrAlreadyClosed
+}
+
 func (db *closedDB) SetReference(ctx context.Context, req *schema.ReferenceRequest) (*schema.TxHeader, error) {
 	return nil, store.ErrAlreadyClosed
 }
@@ -230,6 +234,10 @@ func (db *closedDB) TxScan(ctx context.Context, req *schema.TxScanRequest) (*sch
 	return nil, store.ErrAlreadyClosed
 }
 
+func (db *closedDB) SubscribeTxs(ctx context.Context, req *schema.SubscribeTxsRequest, send func(*schema.VerifiableTx) error) error {
+	return store.ErrAlreadyClosed
+}
+
 func (db *closedDB) FlushIndex(req *schema.FlushIndexRequest) error {
 	return store.ErrAlreadyClosed
 }
@@ -244,6 +244,40 @@ func (s *ImmuServer) TxScan(ctx context.Context, req *schema.TxScanRequest) (*sc
 	return db.TxScan(ctx, req)
 }
 
+// SubscribeTxs streams committed transactions as they land
+func (s *ImmuServer) SubscribeTxs(req *schema.SubscribeTxsRequest, stream schema.ImmuService_SubscribeTxsServer) error {
+	if req == nil || stream == nil {
+		return ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(stream.Context(), "SubscribeTxs")
+	if err != nil {
+		return err
+	}
+
+	return db.SubscribeTxs(stream.Context(), req, func(vtx *schema.VerifiableTx) error {
+		if s.StateSigner != nil && vtx.DualProof != nil {
+			hdr := schema.TxHeaderFromProto(vtx.DualProof.TargetTxHeader)
+			alh := hdr.Alh()
+
+			newState := &schema.ImmutableState{
+				Db:     db.GetName(),
+				TxId:   hdr.ID,
+				TxHash: alh[:],
+			}
+
+			err := s.StateSigner.Sign(newState)
+			if err != nil {
+				return err
+			}
+
+			vtx.Signature = newState.Signature
+		}
+
+		return stream.Send(vtx)
+	})
+}
+
 // History ...
 func (s *ImmuServer) History(ctx context.Context, req *schema.HistoryRequest) (*schema.Entries, error) {
 	db, err := s.getDBFromCtx(ctx, "History")
@@ -416,6 +450,19 @@ func (s *ImmuServer) Delete(ctx context.Context, req *schema.DeleteKeysRequest)
 	return db.Delete(ctx, req)
 }
 
+func (s *ImmuServer) DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error) {
+	if req == nil {
+		return nil, store.ErrIllegalArguments
+	}
+
+	db, err := s.getDBFromCtx(ctx, "DeleteRange")
+	if err != nil {
+		return nil, err
+	}
+
+	return db.DeleteRange(ctx, req)
+}
+
 func (s *ImmuServer) ExecAll(ctx context.Context, req *schema.ExecAllRequest) (*schema.TxHeader, error) {
 	if s.Options.GetMaintenance() {
 		return nil, ErrNotAllowedInMaintenanceMode
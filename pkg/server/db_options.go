@@ -21,9 +21,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/codenotary/immudb/embedded/ahtree"
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/embedded/tbtree"
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -36,8 +39,9 @@ type Milliseconds int64
 type dbOptions struct {
 	Database string `json:"database"`
 
-	synced        bool         // currently a global immudb instance option
-	SyncFrequency Milliseconds `json:"syncFrequency"` // ms
+	synced            bool         // currently a global immudb instance option
+	encryptionKeysDir string       // currently a global immudb instance option
+	SyncFrequency     Milliseconds `json:"syncFrequency"` // ms
 
 	// replication options (field names must be kept for backwards compatibility)
 	Replica                      bool   `json:"replica"`
@@ -62,6 +66,14 @@ type dbOptions struct {
 	MaxValueLen    int  `json:"maxValueLen"`    // permanent
 	MaxTxEntries   int  `json:"maxTxEntries"`   // permanent
 
+	// name of the file, within the encryption keys dir, holding the keys used to encrypt newly created files,
+	// files written with previous keys remain readable
+	EncryptionKeyFile string `json:"encryptionKeyFile"` // permanent
+
+	// compression of newly created value log files, existing files keep the one they were written with
+	CompressionFormat string `json:"compressionFormat"`
+	CompressionLevel  string `json:"compressionLevel"`
+
 	ExcludeCommitTime bool `json:"excludeCommitTime"`
 
 	MaxActiveTransactions int `json:"maxActiveTransactions"`
@@ -136,12 +148,36 @@ const (
 	DefaultStoreFileSize = 1 << 29 //512Mb
 )
 
+const (
+	defaultCompressionFormat = "no-compression"
+	defaultCompressionLevel  = "best-speed"
+)
+
+var compressionFormats = map[string]int{
+	"no-compression": appendable.NoCompression,
+	"flate":          appendable.FlateCompression,
+	"gzip":           appendable.GZipCompression,
+	"lzw":            appendable.LZWCompression,
+	"zlib":           appendable.ZLibCompression,
+	"zstd":           appendable.ZstdCompression,
+	"lz4":            appendable.LZ4Compression,
+	"snappy":         appendable.SnappyCompression,
+}
+
+var compressionLevels = map[string]int{
+	"best-speed":          appendable.BestSpeed,
+	"best-compression":    appendable.BestCompression,
+	"default-compression": appendable.DefaultCompression,
+	"huffman-only":        appendable.HuffmanOnly,
+}
+
 func (s *ImmuServer) defaultDBOptions(dbName, userName string) *dbOptions {
 	dbOpts := &dbOptions{
 		Database: dbName,
 
-		synced:        s.Options.synced,
-		SyncFrequency: Milliseconds(store.DefaultSyncFrequency.Milliseconds()),
+		synced:            s.Options.synced,
+		encryptionKeysDir: s.Options.EncryptionKeysDir,
+		SyncFrequency:     Milliseconds(store.DefaultSyncFrequency.Milliseconds()),
 
 		EmbeddedValues: store.DefaultEmbeddedValues,
 		PreallocFiles:  store.DefaultPreallocFiles,
@@ -150,6 +186,9 @@ func (s *ImmuServer) defaultDBOptions(dbName, userName string) *dbOptions {
 		MaxValueLen:    DefaultMaxValueLen,
 		MaxTxEntries:   store.DefaultMaxTxEntries,
 
+		CompressionFormat: defaultCompressionFormat,
+		CompressionLevel:  defaultCompressionLevel,
+
 		ExcludeCommitTime: false,
 
 		MaxActiveTransactions:   store.DefaultMaxActiveTransactions,
@@ -241,6 +280,11 @@ func (s *ImmuServer) databaseOptionsFrom(opts *dbOptions) *database.Options {
 		WithMaxResultSize(s.Options.MaxResultSize)
 }
 
+// encryptionKeyPath returns the path of the encryption key file, which is always located within the encryption keys dir
+func (opts *dbOptions) encryptionKeyPath() string {
+	return filepath.Join(opts.encryptionKeysDir, opts.EncryptionKeyFile)
+}
+
 func (opts *dbOptions) storeOptions() *store.Options {
 	indexOpts := store.DefaultIndexOptions()
 
@@ -279,6 +323,8 @@ func (opts *dbOptions) storeOptions() *store.Options {
 		WithMaxKeyLen(opts.MaxKeyLen).
 		WithMaxValueLen(opts.MaxValueLen).
 		WithMaxTxEntries(opts.MaxTxEntries).
+		WithCompressionFormat(compressionFormats[opts.CompressionFormat]).
+		WithCompresionLevel(compressionLevels[opts.CompressionLevel]).
 		WithWriteTxHeaderVersion(opts.WriteTxHeaderVersion).
 		WithMaxActiveTransactions(opts.MaxActiveTransactions).
 		WithMVCCReadSetLimit(opts.MVCCReadSetLimit).
@@ -293,6 +339,10 @@ func (opts *dbOptions) storeOptions() *store.Options {
 		WithIndexOptions(indexOpts).
 		WithAHTOptions(ahtOpts)
 
+	if opts.EncryptionKeyFile != "" {
+		stOpts.WithKeyProvider(cryptoapp.NewFileKeyProvider(opts.encryptionKeyPath()))
+	}
+
 	if opts.ExcludeCommitTime {
 		stOpts.WithTimeFunc(func() time.Time { return time.Unix(0, 0) })
 	} else {
@@ -329,6 +379,11 @@ func (opts *dbOptions) databaseNullableSettings() *schema.DatabaseNullableSettin
 		EmbeddedValues: &schema.NullableBool{Value: opts.EmbeddedValues},
 		PreallocFiles:  &schema.NullableBool{Value: opts.PreallocFiles},
 
+		EncryptionKeyFile: &schema.NullableString{Value: opts.EncryptionKeyFile},
+
+		CompressionFormat: &schema.NullableString{Value: opts.CompressionFormat},
+		CompressionLevel:  &schema.NullableString{Value: opts.CompressionLevel},
+
 		ExcludeCommitTime: &schema.NullableBool{Value: opts.ExcludeCommitTime},
 
 		MaxActiveTransactions: &schema.NullableUint32{Value: uint32(opts.MaxActiveTransactions)},
@@ -457,6 +512,11 @@ func (s *ImmuServer) overwriteWith(opts *dbOptions, settings *schema.DatabaseNul
 			return fmt.Errorf("%w: %s can not be changed after database creation ('%s')", ErrIllegalArguments, "max node size", opts.Database)
 		}
 
+		if settings.EncryptionKeyFile != nil {
+			return fmt.Errorf("%w: %s can not be changed after database creation ('%s')", ErrIllegalArguments,
+				"encryption key file", opts.Database)
+		}
+
 		opts.UpdatedAt = time.Now()
 	}
 
@@ -564,6 +624,18 @@ func (s *ImmuServer) overwriteWith(opts *dbOptions, settings *schema.DatabaseNul
 		opts.PreallocFiles = settings.PreallocFiles.Value
 	}
 
+	if settings.EncryptionKeyFile != nil {
+		opts.EncryptionKeyFile = settings.EncryptionKeyFile.Value
+	}
+
+	if settings.CompressionFormat != nil {
+		opts.CompressionFormat = settings.CompressionFormat.Value
+	}
+
+	if settings.CompressionLevel != nil {
+		opts.CompressionLevel = settings.CompressionLevel.Value
+	}
+
 	if settings.ExcludeCommitTime != nil {
 		opts.ExcludeCommitTime = settings.ExcludeCommitTime.Value
 	}
@@ -819,6 +891,39 @@ func (opts *dbOptions) Validate() error {
 			ErrIllegalArguments, opts.Database, store.MinimumTruncationFrequency.Hours())
 	}
 
+	if opts.EncryptionKeyFile != "" {
+		if opts.encryptionKeysDir == "" {
+			return fmt.Errorf(
+				"%w: encryption keys dir must be specified to encrypt database '%s'",
+				ErrIllegalArguments, opts.Database)
+		}
+
+		if filepath.Base(opts.EncryptionKeyFile) != opts.EncryptionKeyFile || opts.EncryptionKeyFile == "." || opts.EncryptionKeyFile == ".." {
+			return fmt.Errorf(
+				"%w: encryption key file of database '%s' must be the name of a file within the encryption keys dir",
+				ErrIllegalArguments, opts.Database)
+		}
+
+		_, _, err := cryptoapp.NewFileKeyProvider(opts.encryptionKeyPath()).CurrentKey()
+		if err != nil {
+			return fmt.Errorf(
+				"%w: invalid encryption key file for database '%s': %v",
+				ErrIllegalArguments, opts.Database, err)
+		}
+	}
+
+	if _, ok := compressionFormats[opts.CompressionFormat]; !ok {
+		return fmt.Errorf(
+			"%w: invalid compression format '%s' for database '%s'",
+			ErrIllegalArguments, opts.CompressionFormat, opts.Database)
+	}
+
+	if _, ok := compressionLevels[opts.CompressionLevel]; !ok {
+		return fmt.Errorf(
+			"%w: invalid compression level '%s' for database '%s'",
+			ErrIllegalArguments, opts.CompressionLevel, opts.Database)
+	}
+
 	return opts.storeOptions().Validate()
 }
 
@@ -913,6 +1018,9 @@ func (s *ImmuServer) logDBOptions(database string, opts *dbOptions) {
 	s.Logger.Infof("%s.MaxTxEntries: %v", database, opts.MaxTxEntries)
 	s.Logger.Infof("%s.EmbeddedValues: %v", database, opts.EmbeddedValues)
 	s.Logger.Infof("%s.PreallocFiles: %v", database, opts.PreallocFiles)
+	s.Logger.Infof("%s.EncryptionKeyFile: %v", database, opts.EncryptionKeyFile)
+	s.Logger.Infof("%s.CompressionFormat: %v", database, opts.CompressionFormat)
+	s.Logger.Infof("%s.CompressionLevel: %v", database, opts.CompressionLevel)
 	s.Logger.Infof("%s.ExcludeCommitTime: %v", database, opts.ExcludeCommitTime)
 	s.Logger.Infof("%s.MaxActiveTransactions: %v", database, opts.MaxActiveTransactions)
 	s.Logger.Infof("%s.MVCCReadSetLimit: %v", database, opts.MVCCReadSetLimit)
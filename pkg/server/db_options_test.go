@@ -17,8 +17,12 @@ limitations under the License.
 package server
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
+	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/replication"
 	"github.com/stretchr/testify/require"
 )
@@ -116,3 +120,82 @@ func TestPrimaryOptions(t *testing.T) {
 	opts.RetentionPeriod = -1
 	require.ErrorIs(t, opts.Validate(), ErrIllegalArguments)
 }
+
+func TestEncryptionOptions(t *testing.T) {
+	dir := t.TempDir()
+	keysDir := t.TempDir()
+
+	s, closer := testServer(DefaultOptions().WithDir(dir))
+	defer closer()
+
+	opts := s.defaultDBOptions("db1", "user")
+
+	keyFile := "keys"
+
+	_, err := cryptoapp.NewFileKeyProvider(filepath.Join(keysDir, keyFile)).Rotate(32)
+	require.NoError(t, err)
+
+	// encryption is disabled when the encryption keys dir is not specified
+	err = s.overwriteWith(opts, &schema.DatabaseNullableSettings{
+		EncryptionKeyFile: &schema.NullableString{Value: keyFile},
+	}, false)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	s.Options.WithEncryptionKeysDir(keysDir)
+
+	opts = s.defaultDBOptions("db1", "user")
+
+	err = s.overwriteWith(opts, &schema.DatabaseNullableSettings{
+		EncryptionKeyFile: &schema.NullableString{Value: keyFile},
+	}, true)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = s.overwriteWith(opts, &schema.DatabaseNullableSettings{
+		EncryptionKeyFile: &schema.NullableString{Value: keyFile},
+	}, false)
+	require.NoError(t, err)
+
+	require.NoError(t, opts.Validate())
+	require.NotNil(t, opts.storeOptions().KeyProvider)
+	require.Equal(t, keyFile, opts.databaseNullableSettings().EncryptionKeyFile.GetValue())
+
+	opts.PreallocFiles = true
+	require.Error(t, opts.Validate())
+
+	opts.PreallocFiles = false
+	opts.EncryptionKeyFile = ""
+	require.Nil(t, opts.storeOptions().KeyProvider)
+}
+
+func TestCompressionOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	s, closer := testServer(DefaultOptions().WithDir(dir))
+	defer closer()
+
+	opts := s.defaultDBOptions("db1", "user")
+	require.NoError(t, opts.Validate())
+	require.Equal(t, appendable.NoCompression, opts.storeOptions().CompressionFormat)
+	require.Equal(t, appendable.BestSpeed, opts.storeOptions().CompressionLevel)
+
+	err := s.overwriteWith(opts, &schema.DatabaseNullableSettings{
+		CompressionFormat: &schema.NullableString{Value: "zstd"},
+		CompressionLevel:  &schema.NullableString{Value: "best-compression"},
+	}, true)
+	require.NoError(t, err)
+	require.Equal(t, appendable.ZstdCompression, opts.storeOptions().CompressionFormat)
+	require.Equal(t, appendable.BestCompression, opts.storeOptions().CompressionLevel)
+	require.Equal(t, "zstd", opts.databaseNullableSettings().CompressionFormat.GetValue())
+	require.Equal(t, "best-compression", opts.databaseNullableSettings().CompressionLevel.GetValue())
+
+	err = s.overwriteWith(opts, &schema.DatabaseNullableSettings{
+		CompressionFormat: &schema.NullableString{Value: "brotli"},
+	}, true)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = s.overwriteWith(opts, &schema.DatabaseNullableSettings{
+		CompressionFormat: &schema.NullableString{Value: "lz4"},
+		CompressionLevel:  &schema.NullableString{Value: "fastest"},
+	}, true)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+}
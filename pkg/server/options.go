@@ -84,6 +84,7 @@ type Options struct {
 	SwaggerUIEnabled            bool
 	LogRequestMetadata          bool
 	MaxActiveDatabases          int
+	EncryptionKeysDir           string
 }
 
 type RemoteStorageOptions struct {
@@ -366,6 +367,9 @@ func (o *Options) String() string {
 	if o.SigningKey != "" {
 		opts = append(opts, rightPad("Signing key", o.SigningKey))
 	}
+	if o.EncryptionKeysDir != "" {
+		opts = append(opts, rightPad("Encryption keys dir", o.EncryptionKeysDir))
+	}
 	if o.RemoteStorageOptions.S3Storage {
 		opts = append(opts, "S3 storage")
 		if o.RemoteStorageOptions.S3RoleEnabled {
@@ -542,6 +546,13 @@ func (o *Options) WithMaxActiveDatabases(n int) *Options {
 	return o
 }
 
+// WithEncryptionKeysDir sets the folder holding the key files databases can be encrypted with,
+// databases can not be encrypted when it's not specified
+func (o *Options) WithEncryptionKeysDir(dir string) *Options {
+	o.EncryptionKeysDir = dir
+	return o
+}
+
 // RemoteStorageOptions
 
 func (opts *RemoteStorageOptions) WithS3Storage(S3Storage bool) *RemoteStorageOptions {
@@ -36,6 +36,7 @@ import (
 	"github.com/codenotary/immudb/pkg/stream"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/codenotary/immudb/embedded/appendable/cryptoapp"
 	"github.com/codenotary/immudb/embedded/logger"
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/codenotary/immudb/embedded/tbtree"
@@ -495,6 +496,94 @@ func TestServerCreateMultipleDatabases(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestServerCreateEncryptedDatabase(t *testing.T) {
+	keysDir := t.TempDir()
+
+	serverOptions := DefaultOptions().
+		WithDir(t.TempDir()).
+		WithMetricsServer(false).
+		WithAdminPassword(auth.SysAdminPassword).
+		WithEncryptionKeysDir(keysDir)
+
+	s, closer := testServer(serverOptions)
+	defer closer()
+
+	err := s.Initialize()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	lr, err := s.Login(ctx, &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	})
+	require.NoError(t, err)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+
+	keyFile := "keys"
+
+	_, err = s.CreateDatabaseV2(ctx, &schema.CreateDatabaseRequest{
+		Name:     "encdb",
+		Settings: &schema.DatabaseNullableSettings{EncryptionKeyFile: &schema.NullableString{Value: keyFile}},
+	})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = cryptoapp.NewFileKeyProvider(filepath.Join(keysDir, keyFile)).Rotate(32)
+	require.NoError(t, err)
+
+	// key files can only be located within the encryption keys dir
+	for _, path := range []string{filepath.Join(keysDir, keyFile), "../keys", "."} {
+		_, err = s.CreateDatabaseV2(ctx, &schema.CreateDatabaseRequest{
+			Name:     "encdb",
+			Settings: &schema.DatabaseNullableSettings{EncryptionKeyFile: &schema.NullableString{Value: path}},
+		})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	}
+
+	res, err := s.CreateDatabaseV2(ctx, &schema.CreateDatabaseRequest{
+		Name:     "encdb",
+		Settings: &schema.DatabaseNullableSettings{EncryptionKeyFile: &schema.NullableString{Value: keyFile}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, keyFile, res.Settings.EncryptionKeyFile.GetValue())
+
+	for _, path := range []string{"", keyFile} {
+		_, err = s.UpdateDatabaseV2(ctx, &schema.UpdateDatabaseRequest{
+			Database: "encdb",
+			Settings: &schema.DatabaseNullableSettings{EncryptionKeyFile: &schema.NullableString{Value: path}},
+		})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	}
+
+	uR, err := s.UseDatabase(ctx, &schema.Database{DatabaseName: "encdb"})
+	require.NoError(t, err)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", uR.Token))
+
+	_, err = s.Set(ctx, &schema.SetRequest{
+		KVs: []*schema.KeyValue{{Key: []byte("encrypted-key"), Value: []byte("encrypted-value")}},
+	})
+	require.NoError(t, err)
+
+	err = s.CloseDatabases()
+	require.NoError(t, err)
+
+	err = filepath.Walk(filepath.Join(serverOptions.Dir, "encdb"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		require.NotContains(t, string(bs), "encrypted-value", path)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestServerUpdateDatabaseAuthDisabled(t *testing.T) {
 	serverOptions := DefaultOptions().
 		WithDir(t.TempDir()).
@@ -190,6 +190,10 @@ func (s *ServerMock) Delete(ctx context.Context, req *schema.DeleteKeysRequest)
 	return s.Srv.Delete(ctx, req)
 }
 
+func (s *ServerMock) DeleteRange(ctx context.Context, req *schema.DeleteRangeRequest) (*schema.DeleteRangeResponse, error) {
+	return s.Srv.DeleteRange(ctx, req)
+}
+
 func (s *ServerMock) ExecAll(ctx context.Context, req *schema.ExecAllRequest) (*schema.TxHeader, error) {
 	if s.PostExecAllFn == nil {
 		return s.Srv.ExecAll(ctx, req)
@@ -223,6 +227,10 @@ func (s *ServerMock) TxScan(ctx context.Context, req *schema.TxScanRequest) (*sc
 	return s.Srv.TxScan(ctx, req)
 }
 
+func (s *ServerMock) SubscribeTxs(req *schema.SubscribeTxsRequest, stream schema.ImmuService_SubscribeTxsServer) error {
+	return s.Srv.SubscribeTxs(req, stream)
+}
+
 func (s *ServerMock) History(ctx context.Context, req *schema.HistoryRequest) (*schema.Entries, error) {
 	return s.Srv.History(ctx, req)
 }
@@ -59,6 +59,8 @@ func (r *Rows) Columns() []string {
 //	VarcharType   SQLValueType = "VARCHAR"
 //	BLOBType      SQLValueType = "BLOB"
 //	TimestampType SQLValueType = "TIMESTAMP"
+//	DecimalType   SQLValueType = "DECIMAL"
+//	IntervalType  SQLValueType = "INTERVAL"
 //	AnyType       SQLValueType = "ANY"
 func (r *Rows) ColumnTypeDatabaseTypeName(index int) string {
 	if index >= len(r.columns) {
@@ -86,6 +88,8 @@ func (r *Rows) ColumnTypeLength(index int) (int64, bool) {
 		return math.MaxInt64, true
 	case sql.TimestampType:
 		return math.MaxInt64, true
+	case sql.DecimalType:
+		return math.MaxInt64, true
 	default:
 		return math.MaxInt64, true
 	}
@@ -93,6 +97,8 @@ func (r *Rows) ColumnTypeLength(index int) (int64, bool) {
 
 // ColumnTypePrecisionScale should return the precision and scale for decimal
 // types. If not applicable, variableLength should be false.
+// The precision and scale of DECIMAL columns are not part of the result metadata,
+// values are returned as strings with their own scale.
 func (r *Rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
 	return 0, 0, false
 }
@@ -116,6 +122,9 @@ func (r *Rows) ColumnTypeScanType(index int) reflect.Type {
 		return reflect.TypeOf([]byte{})
 	case sql.TimestampType:
 		return reflect.TypeOf(time.Time{})
+	case sql.DecimalType:
+		// decimal values are scanned as strings to preserve their exactness
+		return reflect.TypeOf("")
 	default:
 		return reflect.TypeOf("")
 	}
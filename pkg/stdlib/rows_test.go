@@ -144,6 +144,12 @@ func TestRows_ColumnTypeLength(t *testing.T) {
 			lenght:         math.MaxInt64,
 			variableLenght: true,
 		},
+		{
+			name:           "DECIMAL",
+			reader:         newMockRowReader([]client.Column{{Name: "(defaultdb.emptytable.c1)", Type: sql.DecimalType}}, []client.Row{{"12.50"}}),
+			lenght:         math.MaxInt64,
+			variableLenght: true,
+		},
 		{
 			name:           "default",
 			reader:         newMockRowReader([]client.Column{{Name: "(defaultdb.emptytable.c1)", Type: sql.AnyType}}, []client.Row{{nil}}),
@@ -202,6 +208,11 @@ func TestRows_ColumnTypeScanType(t *testing.T) {
 			reader:       newMockRowReader([]client.Column{{Name: "(defaultdb.emptytable.c1)", Type: sql.TimestampType}}, []client.Row{{sql.TimeToInt64(time.Now())}}),
 			expectedType: reflect.TypeOf(time.Now()),
 		},
+		{
+			name:         "DECIMAL",
+			reader:       newMockRowReader([]client.Column{{Name: "(defaultdb.emptytable.c1)", Type: sql.DecimalType}}, []client.Row{{"12.50"}}),
+			expectedType: reflect.TypeOf(""),
+		},
 		{
 			name:         "default",
 			reader:       newMockRowReader([]client.Column{{Name: "(defaultdb.emptytable.c1)", Type: sql.AnyType}}, []client.Row{nil}),